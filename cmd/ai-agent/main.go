@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/birddigital/signalwire-telephony/pkg/config"
+	"github.com/birddigital/signalwire-telephony/pkg/conversation"
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+	"github.com/birddigital/signalwire-telephony/pkg/tts"
+)
+
+func main() {
+	// Load configuration from the environment
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required to track calls")
+	}
+
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Create or upgrade the call_sessions schema
+	if err := telephony.Migrate(ctx, db); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	sw := cfg.SignalWire
+
+	// Create call initiator for call lifecycle tracking
+	initiator := telephony.NewCallInitiator(sw.ProjectID, sw.Token, sw.Space, db)
+	initiator.SetEndpoints(sw.Endpoints...)
+	if err := initiator.SetTransport(sw.Transport()); err != nil {
+		log.Fatal(err)
+	}
+
+	initiator.SetPublicBaseURL(cfg.Server.PublicBaseURL)
+
+	// With several replicas, forward each call's webhooks to the replica
+	// that owns it
+	if cfg.RedisURL != "" && cfg.Server.InstanceURL != "" {
+		opts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			log.Fatalf("Invalid REDIS_URL: %v", err)
+		}
+		initiator.SetCallOwnership(telephony.NewCallOwnership(redis.NewClient(opts), cfg.Server.InstanceURL, "", 0))
+	}
+
+	// Take call state from the Relay WebSocket when webhooks can't reach us
+	if len(sw.RelayContexts) > 0 {
+		relay := telephony.NewRelayConsumer(initiator, sw.ProjectID, sw.Token, sw.Space, sw.RelayContexts...)
+		relay.SetEndpoints(sw.Endpoints...)
+		if err := relay.SetTransport(sw.Transport()); err != nil {
+			log.Fatal(err)
+		}
+		go relay.Run(ctx)
+	}
+
+	// Pick up calls that were in flight when the server last stopped
+	if _, err := initiator.RestoreActiveCalls(ctx); err != nil {
+		log.Printf("Failed to restore active calls: %v", err)
+	}
+
+	// Create audio bridge for real-time streaming
+	bridge := telephony.NewAudioStreamBridge()
+
+	// Create WebSocket server
+	audioServer := telephony.NewSignalWireAudioBridge(sw.ProjectID, sw.Token, bridge)
+
+	// Pick the speech recognition provider (STT_PROVIDER)
+	sttProvider, err := cfg.STT.NewProvider()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if sttProvider == nil {
+		log.Printf("No speech recognition configured (see STT_PROVIDER); the AI agent can't hear callers")
+	}
+
+	// And the speech synthesis provider (TTS_PROVIDER)
+	ttsProvider, err := cfg.TTS.NewProvider()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if ttsProvider == nil {
+		log.Printf("No speech synthesis configured (see TTS_PROVIDER); the AI agent can't speak")
+	}
+
+	// Create AI handler (you'd implement your AI logic here)
+	aiHandler := &AIAgentHandler{
+		bridge:    bridge,
+		initiator: initiator,
+	}
+	aiHandler.conversations = conversation.NewManager(bridge, sttProvider, ttsProvider, conversation.LLMFunc(aiHandler.getAIResponse))
+	aiHandler.conversations.SetVoice(aiHandler.callVoice)
+
+	// Extract each call's ConversationGoal outcome (quote, claim,
+	// appointment) as it ends; getAIResponse must become a
+	// conversation.OutcomeLLM for this
+	aiHandler.conversations.SetGoal(aiHandler.callGoal)
+
+	// Score how callers feel each turn (SENTIMENT_PROVIDER), and escalate
+	// calls going badly to a human (SENTIMENT_ESCALATE_TO)
+	sentimentProvider, err := cfg.Sentiment.NewProvider()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if sentimentProvider != nil {
+		aiHandler.conversations.SetSentiment(sentimentProvider)
+		initiator.OnSentimentDrop(cfg.Sentiment.EscalateBelow, func(event telephony.SentimentEvent) {
+			aiHandler.escalate(event, cfg.Sentiment.EscalateTo)
+		})
+	}
+
+	// Functions the LLM may call mid-conversation; getAIResponse must become
+	// a conversation.ToolLLM to use them
+	tools := conversation.NewTools()
+	aiHandler.conversations.SetTools(tools)
+	aiHandler.conversations.OnTurn(func(event conversation.TurnEvent) {
+		switch event.Type {
+		case conversation.TurnCaller:
+			log.Printf("[AI] Heard: %s", event.Text)
+		case conversation.TurnAgent:
+			log.Printf("[AI] Said: %s (reply ready after %s)", event.Text, event.Latency)
+		case conversation.TurnTool:
+			log.Printf("[AI] Called %s (%s)", event.Text, event.Latency)
+		case conversation.TurnOutcome:
+			log.Printf("[AI] Goal %s achieved: %v", event.Outcome.Goal, event.Outcome.Achieved)
+		case conversation.TurnSentiment:
+			log.Printf("[AI] Caller sentiment %.2f %s", event.Sentiment.Score, event.Sentiment.Emotion)
+		}
+		aiHandler.recordTurn(event)
+	})
+
+	// Let the initiator inject audio (DTMF) into bridged calls
+	initiator.SetAudioBridge(bridge)
+
+	// Brief human agents on the conversation when a call is handed off
+	initiator.SetConversationSummarizer(aiHandler)
+
+	// Create HTTP handlers
+	handlers := telephony.NewCallHandlers(initiator, audioServer, bridge)
+
+	// Share one event bus, so a dashboard mounting HandleTranscriptStream
+	// behind its auth can follow transcripts live
+	bus := telephony.NewLocalEventBus(0)
+	handlers.SetEventBus(bus)
+	aiHandler.conversations.SetEventBus(bus)
+
+	// Setup HTTP router
+	mux := http.NewServeMux()
+	handlers.RegisterRoutes(mux)
+
+	// AI webhook endpoint
+	mux.HandleFunc("/api/ai/audio", aiHandler.HandleAudio)
+
+	fmt.Printf("AI Agent server starting on %s...\n", cfg.Server.Addr)
+	server := &http.Server{Addr: cfg.Server.Addr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	// On SIGINT/SIGTERM, let live calls finish while still serving their
+	// webhooks, then stop the server
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	if err := handlers.Drain(drainCtx); err != nil {
+		log.Printf("Drain: %v", err)
+	}
+	server.Shutdown(drainCtx)
+}
+
+// AIAgentHandler handles AI-powered phone conversations
+type AIAgentHandler struct {
+	bridge        *telephony.AudioStreamBridge
+	initiator     *telephony.CallInitiator
+	conversations *conversation.Manager
+}
+
+// HandleAudio starts the AI conversation for a bridge session
+func (h *AIAgentHandler) HandleAudio(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.URL.Query().Get("session_id")
+	if sessionID == "" {
+		http.Error(w, "Missing session_id", http.StatusBadRequest)
+		return
+	}
+
+	if h.bridge.GetSession(sessionID) == nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	// The conversation ends with the session
+	err := h.conversations.Start(context.Background(), sessionID)
+	switch {
+	case errors.Is(err, conversation.ErrConversationExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Write([]byte("OK"))
+}
+
+// Summarize briefs a human agent on a call the AI is handing off
+func (h *AIAgentHandler) Summarize(ctx context.Context, callSID string) (string, error) {
+	session := h.bridge.GetSessionByCallSID(callSID)
+	if session == nil {
+		return "", fmt.Errorf("no audio session for call %s", callSID)
+	}
+
+	history, ok := h.conversations.History(session.GetSessionID())
+	if !ok {
+		return "", fmt.Errorf("no conversation for call %s", callSID)
+	}
+
+	// TODO: Summarize with Claude/GPT
+	var transcript []string
+	for _, message := range history {
+		if message.Role == conversation.RoleCaller {
+			transcript = append(transcript, message.Text)
+		}
+	}
+	if len(transcript) == 0 {
+		return "", nil
+	}
+	if len(transcript) > 3 {
+		transcript = transcript[len(transcript)-3:]
+	}
+	return "The caller said: " + strings.Join(transcript, " "), nil
+}
+
+// recordTurn adds what the caller or agent said to the call's transcript,
+// with the caller's sentiment, and the tools the agent called and the goal's
+// outcome to its session
+func (h *AIAgentHandler) recordTurn(event conversation.TurnEvent) {
+	if event.CallSID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	speaker := telephony.SpeakerAgent
+	switch event.Type {
+	case conversation.TurnCaller:
+		speaker = telephony.SpeakerCaller
+	case conversation.TurnAgent, conversation.TurnInterrupted:
+	case conversation.TurnTool:
+		err := h.initiator.RecordToolCall(ctx, event.CallSID, telephony.ToolCallRecord{
+			Name:       event.ToolCall.Name,
+			Arguments:  event.ToolCall.Arguments,
+			Output:     event.ToolResult.Output,
+			IsError:    event.ToolResult.IsError,
+			DurationMS: event.Latency.Milliseconds(),
+			CalledAt:   event.Start,
+		})
+		if err != nil {
+			log.Printf("[AI] Failed to record tool call for %s: %v", event.CallSID, err)
+		}
+		return
+	case conversation.TurnOutcome:
+		if err := h.initiator.SetGoalOutcome(ctx, event.CallSID, *event.Outcome); err != nil {
+			log.Printf("[AI] Failed to record goal outcome for %s: %v", event.CallSID, err)
+		}
+		return
+	case conversation.TurnSentiment:
+		if err := h.initiator.RecordSentiment(ctx, event.CallSID, telephony.SpeakerCaller, event.Start, *event.Sentiment); err != nil {
+			log.Printf("[AI] Failed to record sentiment for %s: %v", event.CallSID, err)
+		}
+		return
+	default:
+		return
+	}
+
+	if err := h.initiator.RecordTranscript(ctx, event.CallSID, speaker, event.Text, event.Start, event.Time, event.Confidence); err != nil {
+		log.Printf("[AI] Failed to record transcript for %s: %v", event.CallSID, err)
+	}
+}
+
+// getAIResponse generates AI response
+func (h *AIAgentHandler) getAIResponse(ctx context.Context, history []conversation.Message) (string, error) {
+	// TODO: Integrate with Claude/GPT
+	return "AI response placeholder", nil
+}
+
+// callVoice returns the voice configured for the call a bridge session
+// carries, if this server placed it
+func (h *AIAgentHandler) callVoice(ctx context.Context, sessionID string) tts.Voice {
+	session := h.bridge.GetSession(sessionID)
+	if session == nil || session.CallSID == "" {
+		return tts.Voice{}
+	}
+	return tts.CallVoice(h.initiator.GetCallConfig(ctx, session.CallSID))
+}
+
+// callGoal returns the conversation goal of the call a bridge session
+// carries, if this server placed it
+func (h *AIAgentHandler) callGoal(ctx context.Context, sessionID string) string {
+	session := h.bridge.GetSession(sessionID)
+	if session == nil || session.CallSID == "" {
+		return ""
+	}
+	config := h.initiator.GetCallConfig(ctx, session.CallSID)
+	if config == nil {
+		return ""
+	}
+	return config.ConversationGoal
+}
+
+// escalate hands a call whose caller is unhappy to a human agent, or just
+// logs it with no agent configured. The handoff runs in the background, as
+// it ends the conversation reporting the drop.
+func (h *AIAgentHandler) escalate(event telephony.SentimentEvent, agent string) {
+	callSID := event.Call.SignalWireCallSID
+	log.Printf("[AI] Caller sentiment on %s dropped to %.2f (%s), average %.2f",
+		callSID, event.Sentiment.Score, event.Sentiment.Emotion, event.Summary.Average)
+	if agent == "" {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := h.initiator.HandoffToAgent(ctx, callSID, agent, telephony.HandoffOptions{}); err != nil {
+			log.Printf("[AI] Failed to escalate %s: %v", callSID, err)
+		}
+	}()
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/birddigital/signalwire-telephony/pkg/config"
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+func main() {
+	// Load configuration from the environment
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required to track calls")
+	}
+
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Create or upgrade the call_sessions schema
+	if err := telephony.Migrate(ctx, db); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	sw := cfg.SignalWire
+
+	// Create call initiator for call lifecycle tracking
+	initiator := telephony.NewCallInitiator(sw.ProjectID, sw.Token, sw.Space, db)
+	initiator.SetEndpoints(sw.Endpoints...)
+	if err := initiator.SetTransport(sw.Transport()); err != nil {
+		log.Fatal(err)
+	}
+
+	initiator.SetPublicBaseURL(cfg.Server.PublicBaseURL)
+
+	// With several replicas, forward each call's webhooks to the replica
+	// that owns it
+	if cfg.RedisURL != "" && cfg.Server.InstanceURL != "" {
+		opts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			log.Fatalf("Invalid REDIS_URL: %v", err)
+		}
+		initiator.SetCallOwnership(telephony.NewCallOwnership(redis.NewClient(opts), cfg.Server.InstanceURL, "", 0))
+	}
+
+	// Take call state from the Relay WebSocket when webhooks can't reach us
+	if len(sw.RelayContexts) > 0 {
+		relay := telephony.NewRelayConsumer(initiator, sw.ProjectID, sw.Token, sw.Space, sw.RelayContexts...)
+		relay.SetEndpoints(sw.Endpoints...)
+		if err := relay.SetTransport(sw.Transport()); err != nil {
+			log.Fatal(err)
+		}
+		go relay.Run(ctx)
+	}
+
+	// Pick up calls that were in flight when the server last stopped
+	if _, err := initiator.RestoreActiveCalls(ctx); err != nil {
+		log.Printf("Failed to restore active calls: %v", err)
+	}
+
+	// Create audio bridge
+	bridge := telephony.NewAudioStreamBridge()
+
+	// Create WebSocket server
+	audioServer := telephony.NewSignalWireAudioBridge(sw.ProjectID, sw.Token, bridge)
+
+	// Let the initiator inject audio (DTMF) into bridged calls
+	initiator.SetAudioBridge(bridge)
+
+	// Create HTTP handlers
+	handlers := telephony.NewCallHandlers(initiator, audioServer, bridge)
+
+	// Setup HTTP router
+	mux := http.NewServeMux()
+	handlers.RegisterRoutes(mux)
+
+	// Health check endpoint
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+
+	fmt.Printf("Server starting on %s...\n", cfg.Server.Addr)
+	server := &http.Server{Addr: cfg.Server.Addr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	// On SIGINT/SIGTERM, let live calls finish while still serving their
+	// webhooks, then stop the server
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	if err := handlers.Drain(drainCtx); err != nil {
+		log.Printf("Drain: %v", err)
+	}
+	server.Shutdown(drainCtx)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/birddigital/signalwire-telephony/pkg/config"
+	"github.com/birddigital/signalwire-telephony/pkg/messaging"
+)
+
+func main() {
+	message := flag.String("message", "Hello! This is a broadcast message.", "Message body")
+	from := flag.String("from", "", "Sending number (defaults to SMS_FROM)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: sms-broadcast [-from +15551234567] [-message text] RECIPIENT...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// Load configuration from the environment
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *from == "" {
+		*from = cfg.SMSFrom
+	}
+	if *from == "" {
+		log.Fatal("Set -from or SMS_FROM")
+	}
+
+	recipients := flag.Args()
+	if len(recipients) == 0 {
+		flag.Usage()
+		log.Fatal("No recipients given")
+	}
+
+	// Initialize SignalWire client
+	client, err := cfg.SignalWire.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Create message service
+	msgSvc := messaging.NewMessageService(messaging.NewSignalWireAdapter(client))
+
+	fmt.Printf("Sending broadcast to %d recipients...\n", len(recipients))
+
+	messages, errors := msgSvc.SendBroadcast(*from, recipients, *message)
+
+	fmt.Printf("Sent: %d messages\n", len(messages))
+	if len(errors) > 0 {
+		fmt.Printf("Errors: %d\n", len(errors))
+		for _, err := range errors {
+			fmt.Printf("  - %v\n", err)
+		}
+	}
+
+	for _, msg := range messages {
+		fmt.Printf("Message SID: %s (Status: %s)\n", msg.SID, msg.Status)
+	}
+}
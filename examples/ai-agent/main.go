@@ -1,144 +0,0 @@
-package main
-
-import (
-	"context"
-	"fmt"
-	"log"
-	"net/http"
-	"time"
-
-	"github.com/birddigital/signalwire-telephony/pkg/signalwire"
-	"github.com/birddigital/signalwire-telephony/pkg/telephony"
-)
-
-func main() {
-	// Initialize SignalWire client
-	client := signalwire.NewClient(
-		"your-project-id",
-		"your-auth-token",
-		"your-space.signalwire.com",
-	)
-
-	// Create audio bridge for real-time streaming
-	bridge := telephony.NewAudioStreamBridge()
-
-	// Create WebSocket server
-	audioServer := telephony.NewSignalWireAudioBridge(
-		"your-project-id",
-		"your-auth-token",
-		"your-space.signalwire.com",
-		bridge,
-	)
-
-	// Create AI handler (you'd implement your AI logic here)
-	aiHandler := &AIAgentHandler{
-		bridge:      bridge,
-		client:      client,
-		conversations: make(map[string]*Conversation),
-	}
-
-	// Create HTTP handlers
-	handlers := telephony.NewCallHandlers(audioServer, bridge)
-	handlers.RegisterAIHandler(aiHandler)
-
-	// Setup HTTP router
-	mux := http.NewServeMux()
-	handlers.RegisterRoutes(mux)
-
-	// AI webhook endpoint
-	mux.HandleFunc("/api/ai/audio", aiHandler.HandleAudio)
-
-	fmt.Println("AI Agent server starting on :8080...")
-	log.Fatal(http.ListenAndServe(":8080", mux))
-}
-
-// AIAgentHandler handles AI-powered phone conversations
-type AIAgentHandler struct {
-	bridge        *telephony.AudioStreamBridge
-	client        *signalwire.SignalWireClient
-	conversations map[string]*Conversation
-}
-
-// Conversation tracks an active AI conversation
-type Conversation struct {
-	SessionID  string
-	CallSID    string
-	StartTime  time.Time
-	Transcript []string
-}
-
-// HandleAudio processes audio from phone calls
-func (h *AIAgentHandler) HandleAudio(w http.ResponseWriter, r *http.Request) {
-	sessionID := r.URL.Query().Get("session_id")
-	if sessionID == "" {
-		http.Error(w, "Missing session_id", http.StatusBadRequest)
-		return
-	}
-
-	// Get audio channel from bridge
-	phoneToAIChan, err := h.bridge.GetPhoneToAIChannel(sessionID)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	// Get AI to phone channel
-	aiToPhoneChan, err := h.bridge.GetAIToPhoneChannel(sessionID)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	// Start audio processing
-	go h.processPhoneAudio(sessionID, phoneToAIChan)
-
-	w.Write([]byte("OK"))
-}
-
-// processPhoneAudio handles audio from phone call
-func (h *AIAgentHandler) processPhoneAudio(sessionID string, audioChan <-chan []byte) {
-	ctx := context.Background()
-
-	for audioChunk := range audioChan {
-		// Transcribe audio (using Deepgram, Whisper, etc.)
-		transcript := h.transcribeAudio(ctx, audioChunk)
-
-		if transcript != "" {
-			log.Printf("[AI] Heard: %s", transcript)
-
-			// Get AI response
-			response := h.getAIResponse(ctx, transcript)
-
-			// Convert to speech (using ElevenLabs, etc.)
-			ttsAudio := h.synthesizeSpeech(ctx, response)
-
-			// Send back to phone
-			if aiToPhoneChan, err := h.bridge.GetAIToPhoneChannel(sessionID); err == nil {
-				select {
-				case aiToPhoneChan <- ttsAudio:
-					log.Printf("[AI] Said: %s", response)
-				case <-time.After(10 * time.Millisecond):
-					log.Printf("[AI] Channel full, dropped audio")
-				}
-			}
-		}
-	}
-}
-
-// transcribeAudio converts audio to text
-func (h *AIAgentHandler) transcribeAudio(ctx context.Context, audio []byte) string {
-	// TODO: Integrate with Deepgram/Whisper
-	return "transcription placeholder"
-}
-
-// getAIResponse generates AI response
-func (h *AIAgentHandler) getAIResponse(ctx context.Context, transcript string) string {
-	// TODO: Integrate with Claude/GPT
-	return "AI response placeholder"
-}
-
-// synthesizeSpeech converts text to audio
-func (h *AIAgentHandler) synthesizeSpeech(ctx context.Context, text string) []byte {
-	// TODO: Integrate with ElevenLabs
-	return []byte("audio placeholder")
-}
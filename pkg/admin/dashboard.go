@@ -0,0 +1,223 @@
+package admin
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// ADMIN DASHBOARD
+// Optional HTML/JSON observability endpoints
+// ============================================
+//
+// The dashboard has no authentication of its own. Mount it behind your
+// auth middleware:
+//
+//	dash := admin.NewDashboard(initiator, bridge)
+//	mux.Handle("/admin/telephony/", requireAdmin(dash.Handler("/admin/telephony")))
+// ============================================
+
+//go:embed templates/dashboard.html
+var dashboardHTML []byte
+
+// Dashboard serves admin views over the call initiator and audio bridge
+type Dashboard struct {
+	initiator *telephony.CallInitiator
+	bridge    *telephony.AudioStreamBridge
+	progress  ProgressSource
+}
+
+// CampaignProgress summarizes calls placed for a campaign
+type CampaignProgress struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	Total      int       `json:"total"`
+	Active     int       `json:"active"`
+	Answered   int       `json:"answered"`
+	Completed  int       `json:"completed"`
+	Failed     int       `json:"failed"`
+}
+
+// ProgressSource reports campaign progress. Campaign engines can implement
+// it to replace the default view derived from tracked calls.
+type ProgressSource interface {
+	CampaignProgress() []CampaignProgress
+}
+
+// SessionMetrics pairs a bridge session with its metrics
+type SessionMetrics struct {
+	SessionID string                   `json:"session_id"`
+	Metrics   *telephony.BridgeMetrics `json:"metrics"`
+}
+
+// NewDashboard creates a new admin dashboard
+func NewDashboard(initiator *telephony.CallInitiator, bridge *telephony.AudioStreamBridge) *Dashboard {
+	return &Dashboard{
+		initiator: initiator,
+		bridge:    bridge,
+	}
+}
+
+// SetProgressSource replaces the default campaign progress source
+func (d *Dashboard) SetProgressSource(source ProgressSource) {
+	d.progress = source
+}
+
+// Handler returns the dashboard routes mounted under prefix
+// (e.g. "/admin/telephony")
+func (d *Dashboard) Handler(prefix string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(prefix+"/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != prefix+"/" {
+			http.NotFound(w, r)
+			return
+		}
+		d.HandleIndex(w, r)
+	})
+	mux.HandleFunc(prefix+"/api/calls", d.HandleActiveCalls)
+	mux.HandleFunc(prefix+"/api/metrics", d.HandleSessionMetrics)
+	mux.HandleFunc(prefix+"/api/campaigns", d.HandleCampaigns)
+	mux.HandleFunc(prefix+"/api/errors", d.HandleErrors)
+	return mux
+}
+
+// ============================================
+// HTTP HANDLERS
+// ============================================
+
+// HandleIndex serves the HTML dashboard
+func (d *Dashboard) HandleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(dashboardHTML)
+}
+
+// HandleActiveCalls returns the calls currently tracked by the initiator
+func (d *Dashboard) HandleActiveCalls(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, d.activeCalls())
+}
+
+// HandleSessionMetrics returns metrics for every active bridge session
+func (d *Dashboard) HandleSessionMetrics(w http.ResponseWriter, r *http.Request) {
+	var sessions []SessionMetrics
+	if d.bridge != nil {
+		for _, id := range d.bridge.ListSessionIDs() {
+			metrics, err := d.bridge.GetMetrics(id)
+			if err != nil {
+				continue
+			}
+			sessions = append(sessions, SessionMetrics{SessionID: id, Metrics: metrics})
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].SessionID < sessions[j].SessionID
+	})
+	writeJSON(w, sessions)
+}
+
+// HandleCampaigns returns campaign progress
+func (d *Dashboard) HandleCampaigns(w http.ResponseWriter, r *http.Request) {
+	if d.progress != nil {
+		writeJSON(w, d.progress.CampaignProgress())
+		return
+	}
+	writeJSON(w, d.campaignProgressFromCalls())
+}
+
+// HandleErrors returns recent errors (?limit=N, default 50)
+func (d *Dashboard) HandleErrors(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	var entries []telephony.ErrorEntry
+	if d.initiator != nil {
+		entries = d.initiator.Errors().Recent(limit)
+	}
+	writeJSON(w, entries)
+}
+
+// ============================================
+// HELPERS
+// ============================================
+
+// activeCalls returns summaries of tracked calls, newest first
+func (d *Dashboard) activeCalls() []telephony.CallSummary {
+	if d.initiator == nil {
+		return nil
+	}
+
+	var calls []telephony.CallSummary
+	for _, session := range d.initiator.ActiveCalls() {
+		calls = append(calls, session.Summary())
+	}
+
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].InitiatedAt.After(calls[j].InitiatedAt)
+	})
+	return calls
+}
+
+// campaignProgressFromCalls derives campaign progress from tracked calls
+func (d *Dashboard) campaignProgressFromCalls() []CampaignProgress {
+	byCampaign := make(map[uuid.UUID]*CampaignProgress)
+
+	for _, call := range d.activeCalls() {
+		if call.CampaignID == nil {
+			continue
+		}
+
+		progress, ok := byCampaign[*call.CampaignID]
+		if !ok {
+			progress = &CampaignProgress{CampaignID: *call.CampaignID}
+			byCampaign[*call.CampaignID] = progress
+		}
+
+		progress.Total++
+		if call.AnsweredAt != nil {
+			progress.Answered++
+		}
+		switch call.Status {
+		case telephony.StatusCompleted:
+			progress.Completed++
+		case telephony.StatusFailed, telephony.StatusNoAnswer, telephony.StatusBusy, telephony.StatusCancelled:
+			progress.Failed++
+		default:
+			progress.Active++
+		}
+	}
+
+	result := make([]CampaignProgress, 0, len(byCampaign))
+	for _, progress := range byCampaign {
+		result = append(result, *progress)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CampaignID.String() < result[j].CampaignID.String()
+	})
+	return result
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":         v,
+		"generated_at": time.Now(),
+	})
+}
@@ -0,0 +1,95 @@
+package agents
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ============================================
+// HTTP HANDLERS
+// Heartbeats and status updates from agent clients
+// ============================================
+
+// statusRequest is the body of heartbeat and status requests
+type statusRequest struct {
+	AgentID string `json:"agent_id"`
+	Status  Status `json:"status,omitempty"`
+}
+
+// HandleHeartbeat records a heartbeat from an agent client
+func (r *Registry) HandleHeartbeat(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body statusRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.AgentID == "" {
+		http.Error(w, "Missing agent_id", http.StatusBadRequest)
+		return
+	}
+
+	writeAgent(w, body.AgentID, r.Heartbeat(body.AgentID), r)
+}
+
+// HandleStatus sets an agent's status
+func (r *Registry) HandleStatus(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body statusRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.AgentID == "" || body.Status == "" {
+		http.Error(w, "Missing agent_id or status", http.StatusBadRequest)
+		return
+	}
+	if !body.Status.valid() {
+		http.Error(w, "Unknown status", http.StatusBadRequest)
+		return
+	}
+
+	writeAgent(w, body.AgentID, r.SetStatus(body.AgentID, body.Status), r)
+}
+
+// HandleList returns every agent and their status
+func (r *Registry) HandleList(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"agents": r.List(),
+	})
+}
+
+// writeAgent responds with the agent after an update, or the update's error
+func writeAgent(w http.ResponseWriter, id string, err error, r *Registry) {
+	if errors.Is(err, ErrAgentNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	agent, err := r.Get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(agent)
+}
+
+// RegisterRoutes registers the agent presence routes
+func (r *Registry) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/agents", r.HandleList)
+	mux.HandleFunc("/api/agents/heartbeat", r.HandleHeartbeat)
+	mux.HandleFunc("/api/agents/status", r.HandleStatus)
+}
@@ -0,0 +1,288 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================
+// AGENT PRESENCE
+// Human agent availability, kept fresh by heartbeats
+// ============================================
+
+// ErrAgentNotFound is returned for agents that aren't registered
+var ErrAgentNotFound = errors.New("agent not found")
+
+// Status is an agent's availability
+type Status string
+
+const (
+	StatusAvailable Status = "available" // Ready to take a call
+	StatusOnCall    Status = "on-call"   // Routed a call or talking
+	StatusWrapUp    Status = "wrap-up"   // Finishing notes after a call
+	StatusOffline   Status = "offline"   // Signed out or heartbeats stopped
+)
+
+// valid reports whether s is a known status
+func (s Status) valid() bool {
+	switch s {
+	case StatusAvailable, StatusOnCall, StatusWrapUp, StatusOffline:
+		return true
+	}
+	return false
+}
+
+// Agent is a human agent calls can be escalated to
+type Agent struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Phone         string    `json:"phone"` // E.164 number or sip: URI the agent is dialed at
+	Skills        []string  `json:"skills,omitempty"`
+	Status        Status    `json:"status"`
+	StatusSince   time.Time `json:"status_since"`
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+	CallSID       string    `json:"call_sid,omitempty"` // Call the agent was routed, while on a call
+}
+
+// hasSkills reports whether have includes every skill in want
+func hasSkills(have, want []string) bool {
+	for _, skill := range want {
+		found := false
+		for _, h := range have {
+			if h == skill {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Config controls heartbeat expiry and wrap-up time
+type Config struct {
+	HeartbeatTimeout time.Duration // Agents silent this long go offline (default 1m)
+	WrapUpDuration   time.Duration // Wrap-up before an agent is available again (default 30s)
+	SweepInterval    time.Duration // How often Run checks heartbeats and wrap-ups (default 5s)
+}
+
+// StatusChange is delivered to listeners when an agent's status changes
+type StatusChange struct {
+	Agent    Agent  `json:"agent"`
+	Previous Status `json:"previous"`
+}
+
+// Registry tracks agents and routes calls to available ones
+type Registry struct {
+	config Config
+
+	agents    map[string]*Agent
+	personas  map[string]*Persona
+	listeners []func(StatusChange)
+	mu        sync.Mutex
+}
+
+// NewRegistry creates an empty agent registry
+func NewRegistry(config Config) *Registry {
+	if config.HeartbeatTimeout <= 0 {
+		config.HeartbeatTimeout = time.Minute
+	}
+	if config.WrapUpDuration <= 0 {
+		config.WrapUpDuration = 30 * time.Second
+	}
+	if config.SweepInterval <= 0 {
+		config.SweepInterval = 5 * time.Second
+	}
+	return &Registry{
+		config:   config,
+		agents:   make(map[string]*Agent),
+		personas: make(map[string]*Persona),
+	}
+}
+
+// OnStatusChange registers a listener for agent status changes
+func (r *Registry) OnStatusChange(listener func(StatusChange)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, listener)
+}
+
+// Register adds or updates an agent. New agents start offline until their
+// first heartbeat or status update.
+func (r *Registry) Register(agent Agent) error {
+	if agent.ID == "" {
+		return fmt.Errorf("agent id is required")
+	}
+	if agent.Phone == "" {
+		return fmt.Errorf("agent phone is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.agents[agent.ID]; ok {
+		existing.Name = agent.Name
+		existing.Phone = agent.Phone
+		existing.Skills = append([]string(nil), agent.Skills...)
+		return nil
+	}
+
+	r.agents[agent.ID] = &Agent{
+		ID:          agent.ID,
+		Name:        agent.Name,
+		Phone:       agent.Phone,
+		Skills:      append([]string(nil), agent.Skills...),
+		Status:      StatusOffline,
+		StatusSince: time.Now(),
+	}
+	return nil
+}
+
+// Remove unregisters an agent
+func (r *Registry) Remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.agents, id)
+}
+
+// Get returns a copy of an agent
+func (r *Registry) Get(id string) (Agent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	agent, ok := r.agents[id]
+	if !ok {
+		return Agent{}, ErrAgentNotFound
+	}
+	return *agent, nil
+}
+
+// List returns copies of all agents, ordered by ID
+func (r *Registry) List() []Agent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	agents := make([]Agent, 0, len(r.agents))
+	for _, agent := range r.agents {
+		agents = append(agents, *agent)
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].ID < agents[j].ID })
+	return agents
+}
+
+// Heartbeat records that an agent's client is still connected. An offline
+// agent coming back becomes available.
+func (r *Registry) Heartbeat(id string) error {
+	r.mu.Lock()
+	agent, ok := r.agents[id]
+	if !ok {
+		r.mu.Unlock()
+		return ErrAgentNotFound
+	}
+	agent.LastHeartbeat = time.Now()
+	change, changed := r.setStatusLocked(agent, StatusAvailable, agent.Status == StatusOffline)
+	r.mu.Unlock()
+
+	if changed {
+		r.notify(change)
+	}
+	return nil
+}
+
+// SetStatus sets an agent's status, e.g. when they sign out or start a
+// break. Setting a status counts as a heartbeat.
+func (r *Registry) SetStatus(id string, status Status) error {
+	if !status.valid() {
+		return fmt.Errorf("unknown agent status %q", status)
+	}
+
+	r.mu.Lock()
+	agent, ok := r.agents[id]
+	if !ok {
+		r.mu.Unlock()
+		return ErrAgentNotFound
+	}
+	agent.LastHeartbeat = time.Now()
+	change, changed := r.setStatusLocked(agent, status, true)
+	r.mu.Unlock()
+
+	if changed {
+		r.notify(change)
+	}
+	return nil
+}
+
+// Run expires agents whose heartbeats stopped and ends wrap-ups until ctx
+// is done
+func (r *Registry) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			r.sweep(time.Now())
+		}
+	}
+}
+
+// sweep applies heartbeat expiry and wrap-up timeouts as of now
+func (r *Registry) sweep(now time.Time) {
+	var changes []StatusChange
+
+	r.mu.Lock()
+	for _, agent := range r.agents {
+		switch {
+		case agent.Status != StatusOffline && now.Sub(agent.LastHeartbeat) > r.config.HeartbeatTimeout:
+			log.Printf("[Agents] Agent %s missed heartbeats, marking offline", agent.ID)
+			if change, ok := r.setStatusLocked(agent, StatusOffline, true); ok {
+				changes = append(changes, change)
+			}
+		case agent.Status == StatusWrapUp && now.Sub(agent.StatusSince) >= r.config.WrapUpDuration:
+			if change, ok := r.setStatusLocked(agent, StatusAvailable, true); ok {
+				changes = append(changes, change)
+			}
+		}
+	}
+	r.mu.Unlock()
+
+	for _, change := range changes {
+		r.notify(change)
+	}
+}
+
+// setStatusLocked moves an agent to status when apply is set, returning the
+// change if the status differs. Caller must hold r.mu.
+func (r *Registry) setStatusLocked(agent *Agent, status Status, apply bool) (StatusChange, bool) {
+	if !apply || agent.Status == status {
+		return StatusChange{}, false
+	}
+
+	previous := agent.Status
+	agent.Status = status
+	agent.StatusSince = time.Now()
+	if status != StatusOnCall {
+		agent.CallSID = ""
+	}
+	return StatusChange{Agent: *agent, Previous: previous}, true
+}
+
+// notify calls the status change listeners
+func (r *Registry) notify(change StatusChange) {
+	r.mu.Lock()
+	listeners := append([]func(StatusChange){}, r.listeners...)
+	r.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(change)
+	}
+}
@@ -0,0 +1,112 @@
+package agents
+
+import (
+	"errors"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// ROUTING
+// Choosing an agent and escalating calls to them
+// ============================================
+
+// ErrNoAgentAvailable is returned when no available agent matches a route
+var ErrNoAgentAvailable = errors.New("no agent available")
+
+// RouteRequest describes the agent a call needs
+type RouteRequest struct {
+	CallSID  string         // Call being routed; recorded on the agent
+	Skills   []string       // Skills the agent must have (all of them), e.g. "lang:es", "license:TX"
+	Overflow []OverflowRule // Tried in order when no agent has the skills (see Match)
+}
+
+// Available reports whether an available agent matches the request,
+// without reserving one
+func (r *Registry) Available(req RouteRequest) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pickLocked(req) != nil
+}
+
+// Route reserves the available agent with the required skills who has been
+// idle longest and marks them on a call. Release the agent with EndCall
+// once the call ends, or Unroute if it never reached them.
+func (r *Registry) Route(req RouteRequest) (Agent, error) {
+	r.mu.Lock()
+	agent := r.pickLocked(req)
+	if agent == nil {
+		r.mu.Unlock()
+		return Agent{}, ErrNoAgentAvailable
+	}
+	change, _ := r.setStatusLocked(agent, StatusOnCall, true)
+	agent.CallSID = req.CallSID
+	change.Agent.CallSID = req.CallSID
+	routed := *agent
+	r.mu.Unlock()
+
+	r.notify(change)
+	return routed, nil
+}
+
+// pickLocked returns the longest-idle available agent matching req. Caller
+// must hold r.mu.
+func (r *Registry) pickLocked(req RouteRequest) *Agent {
+	var best *Agent
+	for _, agent := range r.agents {
+		if agent.Status != StatusAvailable || !hasSkills(agent.Skills, req.Skills) {
+			continue
+		}
+		if best == nil || agent.StatusSince.Before(best.StatusSince) ||
+			(agent.StatusSince.Equal(best.StatusSince) && agent.ID < best.ID) {
+			best = agent
+		}
+	}
+	return best
+}
+
+// EndCall moves an agent from a call into wrap-up
+func (r *Registry) EndCall(id string) error {
+	return r.leaveCall(id, StatusWrapUp)
+}
+
+// Unroute returns an agent reserved by Route to available, for calls that
+// never connected to them
+func (r *Registry) Unroute(id string) error {
+	return r.leaveCall(id, StatusAvailable)
+}
+
+// leaveCall moves an on-call agent to status
+func (r *Registry) leaveCall(id string, status Status) error {
+	r.mu.Lock()
+	agent, ok := r.agents[id]
+	if !ok {
+		r.mu.Unlock()
+		return ErrAgentNotFound
+	}
+	change, changed := r.setStatusLocked(agent, status, agent.Status == StatusOnCall)
+	r.mu.Unlock()
+
+	if changed {
+		r.notify(change)
+	}
+	return nil
+}
+
+// TrackCalls moves agents into wrap-up when the call routed to them ends
+func (r *Registry) TrackCalls(initiator *telephony.CallInitiator) {
+	initiator.OnCompleted(func(summary telephony.CallSummary) {
+		r.mu.Lock()
+		var ids []string
+		for _, agent := range r.agents {
+			if agent.Status == StatusOnCall && agent.CallSID == summary.SignalWireCallSID {
+				ids = append(ids, agent.ID)
+			}
+		}
+		r.mu.Unlock()
+
+		for _, id := range ids {
+			r.EndCall(id)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// SKILLS-BASED ROUTING
+// Matching calls to agents or AI personas, with overflow
+// ============================================
+
+// Persona is an AI configuration calls can be matched to by skill, e.g. a
+// Spanish-speaking auto insurance assistant
+type Persona struct {
+	Name         string   `json:"name"`
+	Skills       []string `json:"skills,omitempty"`
+	VoiceID      string   `json:"voice_id,omitempty"`
+	Locale       string   `json:"locale,omitempty"`
+	SystemPrompt string   `json:"system_prompt,omitempty"`
+}
+
+// OverflowAction is what an overflow rule does
+type OverflowAction string
+
+const (
+	// OverflowRelax retries agents with only the rule's Skills
+	OverflowRelax OverflowAction = "relax"
+	// OverflowPersona matches an AI persona: the named one, or else the
+	// first (by name) with the rule's Skills
+	OverflowPersona OverflowAction = "persona"
+	// OverflowTransfer sends the call to Target, e.g. a backup queue or
+	// voicemail line
+	OverflowTransfer OverflowAction = "transfer"
+)
+
+// OverflowRule is one fallback step when no agent matches a request
+type OverflowRule struct {
+	Action  OverflowAction `json:"action"`
+	Skills  []string       `json:"skills,omitempty"`
+	Persona string         `json:"persona,omitempty"`
+	Target  string         `json:"target,omitempty"` // E.164 or sip: URI
+}
+
+// Match is where a routed call goes; exactly one of Agent, Persona and
+// Target is set
+type Match struct {
+	Agent   *Agent   `json:"agent,omitempty"`   // Reserved human agent
+	Persona *Persona `json:"persona,omitempty"` // AI persona to keep the call with
+	Target  string   `json:"target,omitempty"`  // Number or SIP endpoint to transfer to
+	Step    int      `json:"step"`              // 0 for the requested skills, n for overflow rule n
+}
+
+// RegisterPersona adds or replaces an AI persona
+func (r *Registry) RegisterPersona(persona Persona) error {
+	if persona.Name == "" {
+		return fmt.Errorf("persona name is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	persona.Skills = append([]string(nil), persona.Skills...)
+	r.personas[persona.Name] = &persona
+	return nil
+}
+
+// Personas returns all AI personas, ordered by name
+func (r *Registry) Personas() []Persona {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	personas := make([]Persona, 0, len(r.personas))
+	for _, persona := range r.personas {
+		personas = append(personas, *persona)
+	}
+	sort.Slice(personas, func(i, j int) bool { return personas[i].Name < personas[j].Name })
+	return personas
+}
+
+// Match finds where a call should go: an available agent with the requested
+// skills, otherwise the first overflow rule that matches. A matched agent
+// is reserved as with Route. Returns ErrNoAgentAvailable if nothing matches.
+func (r *Registry) Match(req RouteRequest) (Match, error) {
+	if agent, err := r.Route(req); err == nil {
+		return Match{Agent: &agent}, nil
+	}
+
+	for i, rule := range req.Overflow {
+		step := i + 1
+		switch rule.Action {
+		case OverflowRelax:
+			relaxed := RouteRequest{CallSID: req.CallSID, Skills: rule.Skills}
+			if agent, err := r.Route(relaxed); err == nil {
+				return Match{Agent: &agent, Step: step}, nil
+			}
+		case OverflowPersona:
+			if persona, ok := r.matchPersona(rule); ok {
+				return Match{Persona: &persona, Step: step}, nil
+			}
+		case OverflowTransfer:
+			if rule.Target != "" {
+				return Match{Target: rule.Target, Step: step}, nil
+			}
+		}
+	}
+	return Match{}, ErrNoAgentAvailable
+}
+
+// matchPersona finds the persona an overflow rule names or whose skills fit
+func (r *Registry) matchPersona(rule OverflowRule) (Persona, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rule.Persona != "" {
+		persona, ok := r.personas[rule.Persona]
+		if !ok {
+			return Persona{}, false
+		}
+		return *persona, true
+	}
+
+	var best *Persona
+	for _, persona := range r.personas {
+		if hasSkills(persona.Skills, rule.Skills) && (best == nil || persona.Name < best.Name) {
+			best = persona
+		}
+	}
+	if best == nil {
+		return Persona{}, false
+	}
+	return *best, true
+}
+
+// Escalate routes a live call, inbound or outbound, by skill and carries
+// out the match: agents are handed the call from the AI with a summary,
+// transfer targets get a blind transfer, and persona matches are returned
+// for the caller to apply to its AI. A reserved agent is released if the
+// handoff can't be started.
+func (r *Registry) Escalate(ctx context.Context, initiator *telephony.CallInitiator, req RouteRequest, opts telephony.HandoffOptions) (Match, *telephony.Transfer, error) {
+	if req.CallSID == "" {
+		return Match{}, nil, fmt.Errorf("call_sid is required")
+	}
+
+	match, err := r.Match(req)
+	if err != nil {
+		return Match{}, nil, err
+	}
+
+	switch {
+	case match.Agent != nil:
+		transfer, err := initiator.HandoffToAgent(ctx, req.CallSID, match.Agent.Phone, opts)
+		if err != nil {
+			r.Unroute(match.Agent.ID)
+			return Match{}, nil, err
+		}
+		log.Printf("[Agents] Escalated call %s to agent %s (step %d)", req.CallSID, match.Agent.ID, match.Step)
+		return match, transfer, nil
+
+	case match.Target != "":
+		transfer, err := initiator.TransferCall(ctx, req.CallSID, match.Target, telephony.TransferBlind)
+		if err != nil {
+			return Match{}, nil, err
+		}
+		log.Printf("[Agents] Overflowed call %s to %s (step %d)", req.CallSID, match.Target, match.Step)
+		return match, transfer, nil
+	}
+
+	log.Printf("[Agents] Routed call %s to persona %s (step %d)", req.CallSID, match.Persona.Name, match.Step)
+	return match, nil, nil
+}
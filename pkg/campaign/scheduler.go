@@ -0,0 +1,410 @@
+package campaign
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/birddigital/signalwire-telephony/pkg/dialer"
+)
+
+// ============================================
+// CAMPAIGN SCHEDULER
+// Launches campaign calls inside each target's local calling window
+// ============================================
+
+// Status is a campaign's scheduling state
+type Status string
+
+const (
+	StatusRunning   Status = "running"   // Dialing targets inside their window
+	StatusWaiting   Status = "waiting"   // Every remaining target is outside its window
+	StatusPaused    Status = "paused"    // Paused by the operator
+	StatusCompleted Status = "completed" // Every target has been dialed
+)
+
+// CallingWindow is a daily local-time range in which calls may be placed
+type CallingWindow struct {
+	Start string         // Local opening time, "HH:MM" (e.g. "09:00")
+	End   string         // Local closing time, "HH:MM" (e.g. "20:00")
+	Days  []time.Weekday // Days calls are allowed (empty = every day)
+}
+
+// Campaign is a set of targets dialed within a calling window
+type Campaign struct {
+	ID              uuid.UUID
+	Name            string
+	Window          CallingWindow
+	DefaultTimezone string // Used for targets whose zone can't be derived
+	Targets         []dialer.Target
+
+	// Prompt templates for the campaign's calls, replacing the dialer's call
+	// template's when set, and the settings they can refer to as
+	// {{.Campaign.<key>}}; targets' metadata is {{.Target.<key>}}
+	SystemPrompt   string
+	GreetingScript string
+	Settings       map[string]interface{}
+}
+
+// CampaignState is a snapshot of a scheduled campaign
+type CampaignState struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Status     Status    `json:"status"`
+	Pending    int       `json:"pending"`
+	Dispatched int       `json:"dispatched"`
+	Skipped    int       `json:"skipped"` // Targets without a usable timezone
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// minutes parses "HH:MM" into minutes after midnight
+func minutes(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM): %w", clock, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Validate checks the window's times
+func (w CallingWindow) Validate() error {
+	start, err := minutes(w.Start)
+	if err != nil {
+		return err
+	}
+	end, err := minutes(w.End)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return fmt.Errorf("calling window is empty")
+	}
+	return nil
+}
+
+// Contains reports whether a local time falls inside the window. Windows
+// whose end is before their start run past midnight.
+func (w CallingWindow) Contains(local time.Time) bool {
+	if len(w.Days) > 0 {
+		allowed := false
+		for _, day := range w.Days {
+			if day == local.Weekday() {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	start, err := minutes(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := minutes(w.End)
+	if err != nil {
+		return false
+	}
+
+	now := local.Hour()*60 + local.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// scheduledCampaign is a campaign's pending targets grouped by time zone
+type scheduledCampaign struct {
+	campaign   Campaign
+	queues     map[string][]dialer.Target // zone -> pending targets
+	zones      []string                   // Sorted zone names
+	locations  map[string]*time.Location
+	status     Status
+	paused     bool
+	dispatched int
+	skipped    int
+	updatedAt  time.Time
+}
+
+// pending counts targets not yet dispatched
+func (c *scheduledCampaign) pending() int {
+	count := 0
+	for _, queue := range c.queues {
+		count += len(queue)
+	}
+	return count
+}
+
+// Scheduler feeds campaign targets to a Dialer, only while each target's
+// local time is inside its campaign's calling window. Campaigns pause when
+// all remaining targets are outside their window and resume automatically.
+type Scheduler struct {
+	dialer        *dialer.Dialer
+	campaigns     map[uuid.UUID]*scheduledCampaign
+	order         []uuid.UUID // Round-robin order
+	cursor        int
+	checkInterval time.Duration
+	wake          chan struct{}
+	mu            sync.Mutex
+}
+
+// NewScheduler creates a new campaign scheduler
+func NewScheduler(d *dialer.Dialer) *Scheduler {
+	return &Scheduler{
+		dialer:        d,
+		campaigns:     make(map[uuid.UUID]*scheduledCampaign),
+		checkInterval: time.Minute,
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// SetCheckInterval sets how often idle campaigns are re-checked (default 1m)
+func (s *Scheduler) SetCheckInterval(interval time.Duration) {
+	if interval > 0 {
+		s.checkInterval = interval
+	}
+}
+
+// Add schedules a campaign
+func (s *Scheduler) Add(c Campaign) error {
+	if c.ID == uuid.Nil {
+		return fmt.Errorf("campaign ID is required")
+	}
+	if err := c.Window.Validate(); err != nil {
+		return err
+	}
+
+	var fallback *time.Location
+	if c.DefaultTimezone != "" {
+		loc, err := time.LoadLocation(c.DefaultTimezone)
+		if err != nil {
+			return fmt.Errorf("invalid default timezone: %w", err)
+		}
+		fallback = loc
+	}
+
+	// Catch template mistakes and targets missing variables now, not as
+	// each call is placed
+	targets := make([]dialer.Target, len(c.Targets))
+	for i, target := range c.Targets {
+		if target.CampaignID == uuid.Nil {
+			target.CampaignID = c.ID
+		}
+		targets[i] = target
+	}
+	prompts := dialer.CampaignPrompts{
+		SystemPrompt:   c.SystemPrompt,
+		GreetingScript: c.GreetingScript,
+		Settings:       c.Settings,
+	}
+	if err := s.dialer.ValidatePrompts(prompts, targets); err != nil {
+		return fmt.Errorf("invalid prompts: %w", err)
+	}
+
+	scheduled := &scheduledCampaign{
+		campaign:  c,
+		queues:    make(map[string][]dialer.Target),
+		locations: make(map[string]*time.Location),
+		status:    StatusWaiting,
+		updatedAt: time.Now(),
+	}
+
+	for _, target := range targets {
+		loc, err := TargetLocation(target, fallback)
+		if err != nil {
+			log.Printf("[Scheduler] Skipping target %s in campaign %s: %v", target.Phone, c.ID, err)
+			scheduled.skipped++
+			continue
+		}
+		scheduled.queues[loc.String()] = append(scheduled.queues[loc.String()], target)
+		scheduled.locations[loc.String()] = loc
+	}
+	for zone := range scheduled.queues {
+		scheduled.zones = append(scheduled.zones, zone)
+	}
+	sort.Strings(scheduled.zones)
+
+	s.mu.Lock()
+	if _, exists := s.campaigns[c.ID]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("campaign already scheduled: %s", c.ID)
+	}
+	s.dialer.SetCampaignPrompts(c.ID, prompts)
+	s.campaigns[c.ID] = scheduled
+	s.order = append(s.order, c.ID)
+	s.mu.Unlock()
+
+	log.Printf("[Scheduler] Scheduled campaign %s (%d targets, %d zones)", c.ID, scheduled.pending(), len(scheduled.zones))
+	s.signal()
+	return nil
+}
+
+// Pause stops dispatching a campaign's targets
+func (s *Scheduler) Pause(id uuid.UUID) error {
+	return s.setPaused(id, true)
+}
+
+// Resume continues a paused campaign
+func (s *Scheduler) Resume(id uuid.UUID) error {
+	return s.setPaused(id, false)
+}
+
+// setPaused pauses or resumes a campaign
+func (s *Scheduler) setPaused(id uuid.UUID, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.campaigns[id]
+	if !ok {
+		return fmt.Errorf("campaign not found: %s", id)
+	}
+	c.paused = paused
+	if paused {
+		s.setStatus(c, StatusPaused)
+	} else {
+		s.setStatus(c, StatusWaiting)
+		s.signal()
+	}
+	return nil
+}
+
+// States returns snapshots of all scheduled campaigns
+func (s *Scheduler) States() []CampaignState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]CampaignState, 0, len(s.order))
+	for _, id := range s.order {
+		c := s.campaigns[id]
+		states = append(states, CampaignState{
+			ID:         id,
+			Name:       c.campaign.Name,
+			Status:     c.status,
+			Pending:    c.pending(),
+			Dispatched: c.dispatched,
+			Skipped:    c.skipped,
+			UpdatedAt:  c.updatedAt,
+		})
+	}
+	return states
+}
+
+// Run dials scheduled campaigns until ctx is done
+func (s *Scheduler) Run(ctx context.Context) error {
+	targets := make(chan dialer.Target)
+	dialerDone := make(chan error, 1)
+	go func() {
+		dialerDone <- s.dialer.Run(ctx, targets)
+	}()
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		if target, ok := s.next(time.Now()); ok {
+			select {
+			case targets <- target:
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-dialerDone:
+				return err
+			}
+		}
+
+		// Nothing can be dialed right now
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-dialerDone:
+			return err
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// next picks the next target to dial, rotating between campaigns
+func (s *Scheduler) next(now time.Time) (dialer.Target, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found *dialer.Target
+	for i := 0; i < len(s.order); i++ {
+		idx := (s.cursor + i) % len(s.order)
+		c := s.campaigns[s.order[idx]]
+
+		if c.paused {
+			continue
+		}
+		if c.pending() == 0 {
+			s.setStatus(c, StatusCompleted)
+			continue
+		}
+
+		open := false
+		for _, zone := range c.zones {
+			queue := c.queues[zone]
+			if len(queue) == 0 || !c.campaign.Window.Contains(now.In(c.locations[zone])) {
+				continue
+			}
+			open = true
+
+			if found == nil {
+				target := queue[0]
+				c.queues[zone] = queue[1:]
+				c.dispatched++
+				found = &target
+				s.cursor = (idx + 1) % len(s.order)
+			}
+			break
+		}
+
+		if open {
+			s.setStatus(c, StatusRunning)
+		} else {
+			s.setStatus(c, StatusWaiting)
+		}
+		if found != nil {
+			break
+		}
+	}
+
+	if found == nil {
+		return dialer.Target{}, false
+	}
+	return *found, true
+}
+
+// setStatus records a campaign status change
+func (s *Scheduler) setStatus(c *scheduledCampaign, status Status) {
+	if c.status == status {
+		return
+	}
+
+	switch {
+	case status == StatusWaiting && c.status == StatusRunning:
+		log.Printf("[Scheduler] Campaign %s outside calling window, pausing", c.campaign.ID)
+	case status == StatusRunning && c.status == StatusWaiting:
+		log.Printf("[Scheduler] Campaign %s inside calling window, resuming", c.campaign.ID)
+	default:
+		log.Printf("[Scheduler] Campaign %s: %s → %s", c.campaign.ID, c.status, status)
+	}
+
+	c.status = status
+	c.updatedAt = time.Now()
+}
+
+// signal wakes the run loop
+func (s *Scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,173 @@
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============================================
+// AUDIT TRAIL
+// Durable record of every compliance decision
+// ============================================
+
+// Action is the kind of event recorded in the audit trail
+type Action string
+
+const (
+	ActionAllowed   Action = "allowed"   // Call approved by every rule
+	ActionDenied    Action = "denied"    // Call blocked by a rule
+	ActionAttempted Action = "attempted" // Approved call dialed; counts toward attempt limits
+	ActionAnswered  Action = "answered"  // Call answered and connected
+	ActionAbandoned Action = "abandoned" // Call answered with no agent available
+)
+
+// AuditEntry is one compliance decision or tracked event
+type AuditEntry struct {
+	ID         uuid.UUID              `json:"id"`
+	Action     Action                 `json:"action"`
+	Rule       Rule                   `json:"rule,omitempty"`
+	Reason     string                 `json:"reason,omitempty"`
+	Phone      string                 `json:"phone"`
+	TargetID   uuid.UUID              `json:"target_id"`
+	CampaignID uuid.UUID              `json:"campaign_id"`
+	CallSID    string                 `json:"call_sid,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	Timestamp  time.Time              `json:"timestamp"`
+}
+
+// AuditLog persists audit entries
+type AuditLog interface {
+	Record(ctx context.Context, entry AuditEntry) error
+}
+
+// AuditCounter is optionally implemented by an AuditLog that can count its
+// entries. The Engine then derives attempt limits and abandonment rates from
+// the log, so they survive restarts and are shared by every instance
+// writing to it.
+type AuditCounter interface {
+	// CountAttempts returns a number's attempts recorded after since
+	CountAttempts(ctx context.Context, phone string, since time.Time) (int, error)
+	// CountAnswers returns a campaign's answered and abandoned calls
+	// recorded after since
+	CountAnswers(ctx context.Context, campaignID uuid.UUID, since time.Time) (answered, abandoned int, err error)
+}
+
+// ============================================
+// IN-MEMORY AUDIT LOG
+// ============================================
+
+// MemoryAuditLog keeps the most recent audit entries in memory
+type MemoryAuditLog struct {
+	entries []AuditEntry
+	limit   int
+	mu      sync.RWMutex
+}
+
+// NewMemoryAuditLog creates an audit log holding up to limit entries
+// (0 = unbounded)
+func NewMemoryAuditLog(limit int) *MemoryAuditLog {
+	return &MemoryAuditLog{limit: limit}
+}
+
+// Record appends an entry, dropping the oldest once the limit is reached
+func (l *MemoryAuditLog) Record(ctx context.Context, entry AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, entry)
+	if l.limit > 0 && len(l.entries) > l.limit {
+		l.entries = l.entries[len(l.entries)-l.limit:]
+	}
+	return nil
+}
+
+// Entries returns a copy of the recorded entries, oldest first
+func (l *MemoryAuditLog) Entries() []AuditEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]AuditEntry, len(l.entries))
+	copy(entries, l.entries)
+	return entries
+}
+
+// ============================================
+// POSTGRES AUDIT LOG
+// ============================================
+
+// PostgresAuditLog writes audit entries to the compliance_audit_log table,
+// created by telephony.Migrate
+type PostgresAuditLog struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresAuditLog creates an audit log backed by Postgres
+func NewPostgresAuditLog(db *pgxpool.Pool) *PostgresAuditLog {
+	return &PostgresAuditLog{db: db}
+}
+
+// Record inserts an entry
+func (l *PostgresAuditLog) Record(ctx context.Context, entry AuditEntry) error {
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal details: %w", err)
+	}
+
+	query := `
+		INSERT INTO compliance_audit_log (
+			id, action, rule, reason, phone, target_id, campaign_id, call_sid, details, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err = l.db.Exec(ctx, query,
+		entry.ID,
+		string(entry.Action),
+		string(entry.Rule),
+		entry.Reason,
+		entry.Phone,
+		entry.TargetID,
+		entry.CampaignID,
+		entry.CallSID,
+		details,
+		entry.Timestamp,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// CountAttempts counts a number's attempts after since
+func (l *PostgresAuditLog) CountAttempts(ctx context.Context, phone string, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM compliance_audit_log
+		WHERE phone = $1 AND action = $2 AND created_at > $3
+	`
+
+	var count int
+	if err := l.db.QueryRow(ctx, query, phone, string(ActionAttempted), since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count attempts: %w", err)
+	}
+	return count, nil
+}
+
+// CountAnswers counts a campaign's answered and abandoned calls after since
+func (l *PostgresAuditLog) CountAnswers(ctx context.Context, campaignID uuid.UUID, since time.Time) (answered, abandoned int, err error) {
+	query := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE action = $3)
+		FROM compliance_audit_log
+		WHERE campaign_id = $1 AND action IN ($2, $3) AND created_at > $4
+	`
+
+	err = l.db.QueryRow(ctx, query, campaignID, string(ActionAnswered), string(ActionAbandoned), since).Scan(&answered, &abandoned)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count answers: %w", err)
+	}
+	return answered, abandoned, nil
+}
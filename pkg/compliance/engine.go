@@ -0,0 +1,445 @@
+package compliance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/birddigital/signalwire-telephony/pkg/campaign"
+	"github.com/birddigital/signalwire-telephony/pkg/dialer"
+)
+
+// ============================================
+// COMPLIANCE POLICY ENGINE
+// Quiet hours, attempt limits, consent and abandonment tracking
+// ============================================
+
+// ErrDenied is returned for calls blocked by a compliance rule
+var ErrDenied = errors.New("compliance check failed")
+
+// Rule names the compliance rule behind a decision
+type Rule string
+
+const (
+	RuleQuietHours     Rule = "quiet_hours"
+	RuleTimezone       Rule = "timezone"
+	RuleDailyAttempts  Rule = "daily_attempts"
+	RuleWeeklyAttempts Rule = "weekly_attempts"
+	RuleConsent        Rule = "consent"
+	RuleAbandonRate    Rule = "abandon_rate"
+)
+
+// Policy is the set of rules applied to every outbound call
+type Policy struct {
+	CallingHours       campaign.CallingWindow // Callee-local hours calls are permitted (TCPA: 08:00-21:00)
+	DefaultTimezone    string                 // Used when the callee's zone can't be derived (empty = deny)
+	MaxAttemptsPerDay  int                    // Per number, rolling 24h (0 = unlimited)
+	MaxAttemptsPerWeek int                    // Per number, rolling 7 days (0 = unlimited)
+	RequiredConsent    []string               // Consent flags every target must carry
+	MaxAbandonRate     float64                // Per campaign; calls stop once exceeded (0 = untracked)
+	AbandonWindow      time.Duration          // Period the abandonment rate is measured over
+	MinAbandonSample   int                    // Answered calls needed before the rate is enforced
+}
+
+// DefaultPolicy returns the TCPA baseline: 8am-9pm callee-local time and a
+// 3% abandonment rate measured over 30 days
+func DefaultPolicy() Policy {
+	return Policy{
+		CallingHours:     campaign.CallingWindow{Start: "08:00", End: "21:00"},
+		MaxAbandonRate:   0.03,
+		AbandonWindow:    30 * 24 * time.Hour,
+		MinAbandonSample: 100,
+	}
+}
+
+// Decision is the outcome of a compliance check
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Rule    Rule   `json:"rule,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// attemptReservation is how long an allowed call holds its place under the
+// attempt limits while waiting to be dialed (see dialer.AttemptObserver)
+const attemptReservation = 10 * time.Minute
+
+// answerEvent is one answered call counted toward the abandonment rate
+type answerEvent struct {
+	at        time.Time
+	abandoned bool
+}
+
+// Engine enforces a Policy and records every decision to an audit log.
+// It implements dialer.Policy, dialer.AttemptObserver and
+// dialer.AnswerObserver. Attempt limits and abandonment rates are counted
+// from the audit log when it implements AuditCounter, and in memory
+// otherwise.
+type Engine struct {
+	policy   Policy
+	audit    AuditLog
+	counter  AuditCounter // nil when the audit log can't count
+	fallback *time.Location
+
+	attempts map[string][]time.Time      // Phone -> attempt times within the last week, without a counter
+	pending  map[string][]time.Time      // Phone -> allowed calls not yet attempted or withdrawn
+	answers  map[uuid.UUID][]answerEvent // Campaign -> answered calls within AbandonWindow, without a counter
+	mu       sync.Mutex
+}
+
+// NewEngine creates a compliance engine
+func NewEngine(policy Policy, audit AuditLog) (*Engine, error) {
+	if err := policy.CallingHours.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid calling hours: %w", err)
+	}
+
+	var fallback *time.Location
+	if policy.DefaultTimezone != "" {
+		loc, err := time.LoadLocation(policy.DefaultTimezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default timezone: %w", err)
+		}
+		fallback = loc
+	}
+
+	if audit == nil {
+		audit = NewMemoryAuditLog(10000)
+	}
+
+	counter, _ := audit.(AuditCounter)
+
+	return &Engine{
+		policy:   policy,
+		audit:    audit,
+		counter:  counter,
+		fallback: fallback,
+		attempts: make(map[string][]time.Time),
+		pending:  make(map[string][]time.Time),
+		answers:  make(map[uuid.UUID][]answerEvent),
+	}, nil
+}
+
+// Check evaluates a target against the policy without reserving an attempt
+func (e *Engine) Check(target dialer.Target, now time.Time) Decision {
+	return e.evaluate(context.Background(), target, now, false)
+}
+
+// evaluate applies every rule; when reserve is set an allowed call holds a
+// place under the attempt limits until it is attempted or withdrawn, taken
+// under the same lock so concurrent checks can't both pass
+func (e *Engine) evaluate(ctx context.Context, target dialer.Target, now time.Time, reserve bool) Decision {
+	// Consent
+	for _, required := range e.policy.RequiredConsent {
+		if !hasConsent(target, required) {
+			return deny(RuleConsent, "missing consent flag %q", required)
+		}
+	}
+
+	// Quiet hours, in the callee's local time
+	loc, err := campaign.TargetLocation(target, e.fallback)
+	if err != nil {
+		return deny(RuleTimezone, "%v", err)
+	}
+	local := now.In(loc)
+	if !e.policy.CallingHours.Contains(local) {
+		return deny(RuleQuietHours, "local time %s (%s) is outside %s-%s",
+			local.Format("Mon 15:04"), loc, e.policy.CallingHours.Start, e.policy.CallingHours.End)
+	}
+
+	// Counts from the audit log are read before locking; attempts recorded
+	// meanwhile still hold their reservation, so they're never missed
+	var daily, weekly, answered, abandoned int
+	if e.counter != nil {
+		var err error
+		if daily, weekly, err = e.countAttempts(ctx, target.Phone, now); err != nil {
+			return deny(RuleDailyAttempts, "%v", err)
+		}
+		if e.policy.MaxAbandonRate > 0 {
+			if answered, abandoned, err = e.counter.CountAnswers(ctx, target.CampaignID, e.abandonSince(now)); err != nil {
+				return deny(RuleAbandonRate, "%v", err)
+			}
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.counter == nil {
+		daily, weekly = e.attemptCountsLocked(target.Phone, now)
+		if e.policy.MaxAbandonRate > 0 {
+			answered, abandoned = e.abandonCountsLocked(target.CampaignID, now)
+		}
+	}
+	pending := e.pendingCountLocked(target.Phone, now)
+	daily += pending
+	weekly += pending
+
+	// Attempt limits
+	if e.policy.MaxAttemptsPerDay > 0 && daily >= e.policy.MaxAttemptsPerDay {
+		return deny(RuleDailyAttempts, "%d attempts in the last 24h (max %d)", daily, e.policy.MaxAttemptsPerDay)
+	}
+	if e.policy.MaxAttemptsPerWeek > 0 && weekly >= e.policy.MaxAttemptsPerWeek {
+		return deny(RuleWeeklyAttempts, "%d attempts in the last 7 days (max %d)", weekly, e.policy.MaxAttemptsPerWeek)
+	}
+
+	// Campaign abandonment rate
+	if e.policy.MaxAbandonRate > 0 {
+		if answered > 0 && answered >= e.policy.MinAbandonSample {
+			rate := float64(abandoned) / float64(answered)
+			if rate > e.policy.MaxAbandonRate {
+				return deny(RuleAbandonRate, "campaign abandonment rate %.1f%% exceeds %.1f%%",
+					rate*100, e.policy.MaxAbandonRate*100)
+			}
+		}
+	}
+
+	if reserve {
+		e.pending[target.Phone] = append(e.pending[target.Phone], now)
+	}
+	return Decision{Allowed: true}
+}
+
+// Allow checks a target and records the decision. An allowed call holds a
+// place under the attempt limits until CallAttempted or AttemptWithdrawn.
+// Satisfies dialer.Policy.
+func (e *Engine) Allow(ctx context.Context, target dialer.Target) error {
+	now := time.Now()
+	decision := e.evaluate(ctx, target, now, true)
+
+	entry := newEntry(target, now)
+	entry.Rule = decision.Rule
+	entry.Reason = decision.Reason
+	if decision.Allowed {
+		entry.Action = ActionAllowed
+	} else {
+		entry.Action = ActionDenied
+	}
+	e.record(ctx, entry)
+
+	if !decision.Allowed {
+		return fmt.Errorf("%w: %s: %s", ErrDenied, decision.Rule, decision.Reason)
+	}
+	return nil
+}
+
+// CallAttempted counts an allowed call toward its number's attempt limits
+// once it is dialed. Satisfies dialer.AttemptObserver.
+func (e *Engine) CallAttempted(ctx context.Context, target dialer.Target) {
+	now := time.Now()
+
+	entry := newEntry(target, now)
+	entry.Action = ActionAttempted
+	e.record(ctx, entry)
+
+	// Release the reservation only once the attempt is recorded, so
+	// concurrent checks count it one way or the other
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.counter == nil {
+		e.attempts[target.Phone] = append(e.attempts[target.Phone], now)
+	}
+	e.releaseLocked(target.Phone)
+}
+
+// AttemptWithdrawn releases an allowed call that wasn't dialed, e.g. for
+// lack of a caller ID or a launch SignalWire rejected. Satisfies
+// dialer.AttemptObserver.
+func (e *Engine) AttemptWithdrawn(ctx context.Context, target dialer.Target) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.releaseLocked(target.Phone)
+}
+
+// CallAnswered counts an answered call toward its campaign's abandonment
+// rate. Satisfies dialer.AnswerObserver.
+func (e *Engine) CallAnswered(target dialer.Target, callSID string, abandoned bool) {
+	now := time.Now()
+
+	var answered, dropped int
+	if e.counter != nil {
+		var err error
+		answered, dropped, err = e.counter.CountAnswers(context.Background(), target.CampaignID, e.abandonSince(now))
+		if err != nil {
+			log.Printf("[Compliance] Failed to count answers for campaign %s: %v", target.CampaignID, err)
+		}
+		// This call isn't recorded yet
+		answered++
+		if abandoned {
+			dropped++
+		}
+	} else {
+		e.mu.Lock()
+		e.answers[target.CampaignID] = append(e.answers[target.CampaignID], answerEvent{at: now, abandoned: abandoned})
+		answered, dropped = e.abandonCountsLocked(target.CampaignID, now)
+		e.mu.Unlock()
+	}
+
+	entry := newEntry(target, now)
+	entry.CallSID = callSID
+	entry.Action = ActionAnswered
+	if abandoned {
+		entry.Action = ActionAbandoned
+		entry.Rule = RuleAbandonRate
+	}
+	entry.Details = map[string]interface{}{
+		"campaign_answered":  answered,
+		"campaign_abandoned": dropped,
+	}
+	e.record(context.Background(), entry)
+}
+
+// AbandonRate returns a campaign's abandonment rate over the policy window
+func (e *Engine) AbandonRate(campaignID uuid.UUID) (rate float64, answered int) {
+	now := time.Now()
+
+	var abandoned int
+	if e.counter != nil {
+		var err error
+		if answered, abandoned, err = e.counter.CountAnswers(context.Background(), campaignID, e.abandonSince(now)); err != nil {
+			log.Printf("[Compliance] Failed to count answers for campaign %s: %v", campaignID, err)
+			return 0, 0
+		}
+	} else {
+		e.mu.Lock()
+		answered, abandoned = e.abandonCountsLocked(campaignID, now)
+		e.mu.Unlock()
+	}
+	if answered == 0 {
+		return 0, 0
+	}
+	return float64(abandoned) / float64(answered), answered
+}
+
+// attemptCountsLocked prunes attempts older than a week and returns the
+// counts for the last day and week
+func (e *Engine) attemptCountsLocked(phone string, now time.Time) (daily, weekly int) {
+	attempts := e.attempts[phone]
+	kept := attempts[:0]
+	for _, at := range attempts {
+		if now.Sub(at) < 7*24*time.Hour {
+			kept = append(kept, at)
+		}
+	}
+	if len(kept) == 0 {
+		delete(e.attempts, phone)
+		return 0, 0
+	}
+	e.attempts[phone] = kept
+
+	for _, at := range kept {
+		if now.Sub(at) < 24*time.Hour {
+			daily++
+		}
+	}
+	return daily, len(kept)
+}
+
+// countAttempts reads a number's attempts in the last day and week from the
+// audit log
+func (e *Engine) countAttempts(ctx context.Context, phone string, now time.Time) (daily, weekly int, err error) {
+	if e.policy.MaxAttemptsPerDay <= 0 && e.policy.MaxAttemptsPerWeek <= 0 {
+		return 0, 0, nil
+	}
+	if e.policy.MaxAttemptsPerDay > 0 {
+		if daily, err = e.counter.CountAttempts(ctx, phone, now.Add(-24*time.Hour)); err != nil {
+			return 0, 0, err
+		}
+	}
+	if e.policy.MaxAttemptsPerWeek > 0 {
+		if weekly, err = e.counter.CountAttempts(ctx, phone, now.Add(-7*24*time.Hour)); err != nil {
+			return 0, 0, err
+		}
+	}
+	return daily, weekly, nil
+}
+
+// pendingCountLocked drops expired reservations and returns how many calls
+// to a number are allowed but not yet attempted
+func (e *Engine) pendingCountLocked(phone string, now time.Time) int {
+	pending := e.pending[phone]
+	kept := pending[:0]
+	for _, at := range pending {
+		if now.Sub(at) < attemptReservation {
+			kept = append(kept, at)
+		}
+	}
+	if len(kept) == 0 {
+		delete(e.pending, phone)
+		return 0
+	}
+	e.pending[phone] = kept
+	return len(kept)
+}
+
+// releaseLocked drops a number's oldest reservation
+func (e *Engine) releaseLocked(phone string) {
+	if pending := e.pending[phone]; len(pending) > 1 {
+		e.pending[phone] = pending[1:]
+	} else {
+		delete(e.pending, phone)
+	}
+}
+
+// abandonSince returns the start of the abandonment window (zero when
+// unbounded)
+func (e *Engine) abandonSince(now time.Time) time.Time {
+	if e.policy.AbandonWindow <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-e.policy.AbandonWindow)
+}
+
+// abandonCountsLocked prunes answers outside the window and returns the
+// answered and abandoned counts
+func (e *Engine) abandonCountsLocked(campaignID uuid.UUID, now time.Time) (answered, abandoned int) {
+	events := e.answers[campaignID]
+	kept := events[:0]
+	for _, event := range events {
+		if e.policy.AbandonWindow <= 0 || now.Sub(event.at) < e.policy.AbandonWindow {
+			kept = append(kept, event)
+			if event.abandoned {
+				abandoned++
+			}
+		}
+	}
+	e.answers[campaignID] = kept
+	return len(kept), abandoned
+}
+
+// record writes an audit entry; failures are logged so an audit outage
+// doesn't change the decision
+func (e *Engine) record(ctx context.Context, entry AuditEntry) {
+	if err := e.audit.Record(ctx, entry); err != nil {
+		log.Printf("[Compliance] Failed to record audit entry for %s: %v", entry.Phone, err)
+	}
+}
+
+// newEntry starts an audit entry for a target
+func newEntry(target dialer.Target, now time.Time) AuditEntry {
+	return AuditEntry{
+		ID:         uuid.New(),
+		Phone:      target.Phone,
+		TargetID:   target.ID,
+		CampaignID: target.CampaignID,
+		Timestamp:  now,
+	}
+}
+
+// hasConsent reports whether a target carries a consent flag
+func hasConsent(target dialer.Target, flag string) bool {
+	for _, held := range target.Consent {
+		if held == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// deny builds a denial decision
+func deny(rule Rule, format string, args ...interface{}) Decision {
+	return Decision{Rule: rule, Reason: fmt.Sprintf(format, args...)}
+}
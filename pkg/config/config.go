@@ -0,0 +1,320 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/sentiment"
+	"github.com/birddigital/signalwire-telephony/pkg/signalwire"
+	"github.com/birddigital/signalwire-telephony/pkg/stt"
+	"github.com/birddigital/signalwire-telephony/pkg/tts"
+)
+
+// ============================================
+// CONFIGURATION
+// Environment-driven settings shared by the cmd/ binaries
+// ============================================
+
+// Config holds application settings
+type Config struct {
+	SignalWire SignalWireConfig
+	Server     ServerConfig
+	STT        STTConfig
+	TTS        TTSConfig
+	Sentiment  SentimentConfig
+
+	DatabaseURL string // DATABASE_URL
+	RedisURL    string // REDIS_URL, enables call ownership between replicas
+	SMSFrom     string // SMS_FROM
+}
+
+// SignalWireConfig holds SignalWire credentials and connectivity settings
+type SignalWireConfig struct {
+	ProjectID string        // SIGNALWIRE_PROJECT_ID
+	Token     string        // SIGNALWIRE_TOKEN
+	Space     string        // SIGNALWIRE_SPACE (e.g. example.signalwire.com)
+	Endpoints []string      // SIGNALWIRE_ENDPOINTS (comma-separated regional hosts)
+	ProxyURL  string        // SIGNALWIRE_PROXY_URL
+	Timeout   time.Duration // SIGNALWIRE_TIMEOUT (e.g. 30s)
+
+	// SIGNALWIRE_RELAY_CONTEXTS (comma-separated), receives call state over
+	// the Relay WebSocket for servers SignalWire can't reach
+	RelayContexts []string
+}
+
+// ServerConfig holds HTTP server settings
+type ServerConfig struct {
+	Addr          string // LISTEN_ADDR (default :8080)
+	PublicBaseURL string // PUBLIC_BASE_URL, used to build webhook URLs
+	InstanceURL   string // INSTANCE_URL, internal URL other replicas reach this one at
+}
+
+// STTConfig selects and configures the speech recognition provider
+type STTConfig struct {
+	// STT_PROVIDER: deepgram, whisper, whisper-cpp, google or azure; empty
+	// picks the first with credentials set, in that order
+	Provider string
+
+	DeepgramAPIKey string // DEEPGRAM_API_KEY
+	OpenAIAPIKey   string // OPENAI_API_KEY (whisper)
+	WhisperURL     string // WHISPER_URL, a whisper.cpp server (whisper-cpp)
+	GoogleAPIKey   string // GOOGLE_SPEECH_API_KEY
+	AzureKey       string // AZURE_SPEECH_KEY
+	AzureRegion    string // AZURE_SPEECH_REGION (e.g. eastus)
+}
+
+// TTSConfig selects and configures the speech synthesis provider
+type TTSConfig struct {
+	Provider string // TTS_PROVIDER: elevenlabs, openai or azure; empty picks the first with credentials set
+
+	ElevenLabsAPIKey  string // ELEVENLABS_API_KEY
+	ElevenLabsVoiceID string // ELEVENLABS_VOICE_ID, for calls that don't name a voice
+	OpenAIAPIKey      string // OPENAI_API_KEY
+	AzureKey          string // AZURE_SPEECH_KEY
+	AzureRegion       string // AZURE_SPEECH_REGION (e.g. eastus)
+}
+
+// SentimentConfig selects the sentiment analysis provider and when calls
+// are escalated
+type SentimentConfig struct {
+	Provider     string // SENTIMENT_PROVIDER: lexicon or openai; empty turns analysis off
+	OpenAIAPIKey string // OPENAI_API_KEY
+
+	EscalateBelow float64 // SENTIMENT_ESCALATE_BELOW, the caller score that escalates (default -0.5)
+	EscalateTo    string  // SENTIMENT_ESCALATE_TO, the human agent to hand off to; empty only logs
+}
+
+// Load reads configuration from environment variables
+func Load() (*Config, error) {
+	cfg := &Config{
+		SignalWire: SignalWireConfig{
+			ProjectID: os.Getenv("SIGNALWIRE_PROJECT_ID"),
+			Token:     os.Getenv("SIGNALWIRE_TOKEN"),
+			Space:     os.Getenv("SIGNALWIRE_SPACE"),
+			Endpoints: splitList(os.Getenv("SIGNALWIRE_ENDPOINTS")),
+			ProxyURL:  os.Getenv("SIGNALWIRE_PROXY_URL"),
+
+			RelayContexts: splitList(os.Getenv("SIGNALWIRE_RELAY_CONTEXTS")),
+		},
+		Server: ServerConfig{
+			Addr:          getEnv("LISTEN_ADDR", ":8080"),
+			PublicBaseURL: strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/"),
+			InstanceURL:   strings.TrimRight(os.Getenv("INSTANCE_URL"), "/"),
+		},
+		STT: STTConfig{
+			Provider:       strings.ToLower(os.Getenv("STT_PROVIDER")),
+			DeepgramAPIKey: os.Getenv("DEEPGRAM_API_KEY"),
+			OpenAIAPIKey:   os.Getenv("OPENAI_API_KEY"),
+			WhisperURL:     os.Getenv("WHISPER_URL"),
+			GoogleAPIKey:   os.Getenv("GOOGLE_SPEECH_API_KEY"),
+			AzureKey:       os.Getenv("AZURE_SPEECH_KEY"),
+			AzureRegion:    os.Getenv("AZURE_SPEECH_REGION"),
+		},
+		TTS: TTSConfig{
+			Provider:          strings.ToLower(os.Getenv("TTS_PROVIDER")),
+			ElevenLabsAPIKey:  os.Getenv("ELEVENLABS_API_KEY"),
+			ElevenLabsVoiceID: os.Getenv("ELEVENLABS_VOICE_ID"),
+			OpenAIAPIKey:      os.Getenv("OPENAI_API_KEY"),
+			AzureKey:          os.Getenv("AZURE_SPEECH_KEY"),
+			AzureRegion:       os.Getenv("AZURE_SPEECH_REGION"),
+		},
+		Sentiment: SentimentConfig{
+			Provider:      strings.ToLower(os.Getenv("SENTIMENT_PROVIDER")),
+			OpenAIAPIKey:  os.Getenv("OPENAI_API_KEY"),
+			EscalateBelow: -0.5,
+			EscalateTo:    os.Getenv("SENTIMENT_ESCALATE_TO"),
+		},
+		DatabaseURL: os.Getenv("DATABASE_URL"),
+		RedisURL:    os.Getenv("REDIS_URL"),
+		SMSFrom:     os.Getenv("SMS_FROM"),
+	}
+
+	if v := os.Getenv("SIGNALWIRE_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SIGNALWIRE_TIMEOUT: %w", err)
+		}
+		cfg.SignalWire.Timeout = timeout
+	}
+
+	if v := os.Getenv("SENTIMENT_ESCALATE_BELOW"); v != "" {
+		threshold, err := strconv.ParseFloat(v, 64)
+		if err != nil || threshold < -1 || threshold > 1 {
+			return nil, fmt.Errorf("invalid SENTIMENT_ESCALATE_BELOW %q: want -1 to 1", v)
+		}
+		cfg.Sentiment.EscalateBelow = threshold
+	}
+
+	if err := cfg.SignalWire.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that required SignalWire settings are present
+func (c SignalWireConfig) Validate() error {
+	var missing []string
+	if c.ProjectID == "" {
+		missing = append(missing, "SIGNALWIRE_PROJECT_ID")
+	}
+	if c.Token == "" {
+		missing = append(missing, "SIGNALWIRE_TOKEN")
+	}
+	if c.Space == "" {
+		missing = append(missing, "SIGNALWIRE_SPACE")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required environment variables: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Transport returns the transport configuration for SignalWire connections
+func (c SignalWireConfig) Transport() signalwire.TransportConfig {
+	return signalwire.TransportConfig{
+		ProxyURL: c.ProxyURL,
+		Timeout:  c.Timeout,
+	}
+}
+
+// NewClient creates a SignalWire API client from the configuration
+func (c SignalWireConfig) NewClient() (*signalwire.Client, error) {
+	client := signalwire.NewClient(c.ProjectID, c.Token, c.Space)
+	if len(c.Endpoints) > 0 {
+		client.SetEndpoints(c.Endpoints...)
+	}
+	if err := client.SetTransport(c.Transport()); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// NewProvider creates the configured speech recognition provider; nil when
+// none is configured
+func (c STTConfig) NewProvider() (stt.Provider, error) {
+	provider := c.Provider
+	if provider == "" {
+		switch {
+		case c.DeepgramAPIKey != "":
+			provider = "deepgram"
+		case c.OpenAIAPIKey != "":
+			provider = "whisper"
+		case c.WhisperURL != "":
+			provider = "whisper-cpp"
+		case c.GoogleAPIKey != "":
+			provider = "google"
+		case c.AzureKey != "":
+			provider = "azure"
+		default:
+			return nil, nil
+		}
+	}
+
+	switch provider {
+	case "deepgram":
+		if c.DeepgramAPIKey == "" {
+			return nil, fmt.Errorf("STT_PROVIDER deepgram requires DEEPGRAM_API_KEY")
+		}
+		return stt.NewDeepgram(c.DeepgramAPIKey), nil
+	case "whisper":
+		if c.OpenAIAPIKey == "" {
+			return nil, fmt.Errorf("STT_PROVIDER whisper requires OPENAI_API_KEY")
+		}
+		return stt.NewWhisperAPI(c.OpenAIAPIKey), nil
+	case "whisper-cpp":
+		if c.WhisperURL == "" {
+			return nil, fmt.Errorf("STT_PROVIDER whisper-cpp requires WHISPER_URL")
+		}
+		return stt.NewWhisperServer(c.WhisperURL), nil
+	case "google":
+		if c.GoogleAPIKey == "" {
+			return nil, fmt.Errorf("STT_PROVIDER google requires GOOGLE_SPEECH_API_KEY")
+		}
+		return stt.NewGoogle(c.GoogleAPIKey), nil
+	case "azure":
+		if c.AzureKey == "" || c.AzureRegion == "" {
+			return nil, fmt.Errorf("STT_PROVIDER azure requires AZURE_SPEECH_KEY and AZURE_SPEECH_REGION")
+		}
+		return stt.NewAzure(c.AzureKey, c.AzureRegion), nil
+	}
+	return nil, fmt.Errorf("unknown STT_PROVIDER: %s", provider)
+}
+
+// NewProvider creates the configured speech synthesis provider; nil when
+// none is configured
+func (c TTSConfig) NewProvider() (tts.Provider, error) {
+	provider := c.Provider
+	if provider == "" {
+		switch {
+		case c.ElevenLabsAPIKey != "":
+			provider = "elevenlabs"
+		case c.OpenAIAPIKey != "":
+			provider = "openai"
+		case c.AzureKey != "":
+			provider = "azure"
+		default:
+			return nil, nil
+		}
+	}
+
+	switch provider {
+	case "elevenlabs":
+		if c.ElevenLabsAPIKey == "" {
+			return nil, fmt.Errorf("TTS_PROVIDER elevenlabs requires ELEVENLABS_API_KEY")
+		}
+		elevenLabs := tts.NewElevenLabs(c.ElevenLabsAPIKey)
+		elevenLabs.SetDefaultVoice(c.ElevenLabsVoiceID)
+		return elevenLabs, nil
+	case "openai":
+		if c.OpenAIAPIKey == "" {
+			return nil, fmt.Errorf("TTS_PROVIDER openai requires OPENAI_API_KEY")
+		}
+		return tts.NewOpenAI(c.OpenAIAPIKey), nil
+	case "azure":
+		if c.AzureKey == "" || c.AzureRegion == "" {
+			return nil, fmt.Errorf("TTS_PROVIDER azure requires AZURE_SPEECH_KEY and AZURE_SPEECH_REGION")
+		}
+		return tts.NewAzure(c.AzureKey, c.AzureRegion), nil
+	}
+	return nil, fmt.Errorf("unknown TTS_PROVIDER: %s", provider)
+}
+
+// NewProvider creates the configured sentiment analysis provider; nil when
+// none is configured
+func (c SentimentConfig) NewProvider() (sentiment.Provider, error) {
+	switch c.Provider {
+	case "":
+		return nil, nil
+	case "lexicon":
+		return sentiment.NewLexicon(), nil
+	case "openai":
+		if c.OpenAIAPIKey == "" {
+			return nil, fmt.Errorf("SENTIMENT_PROVIDER openai requires OPENAI_API_KEY")
+		}
+		return sentiment.NewOpenAI(c.OpenAIAPIKey), nil
+	}
+	return nil, fmt.Errorf("unknown SENTIMENT_PROVIDER: %s", c.Provider)
+}
+
+// getEnv returns an environment variable or a default
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// configEnv lists every variable Load reads
+var configEnv = []string{
+	"SIGNALWIRE_PROJECT_ID", "SIGNALWIRE_TOKEN", "SIGNALWIRE_SPACE", "SIGNALWIRE_ENDPOINTS",
+	"SIGNALWIRE_PROXY_URL", "SIGNALWIRE_TIMEOUT", "SIGNALWIRE_RELAY_CONTEXTS",
+	"LISTEN_ADDR", "PUBLIC_BASE_URL", "INSTANCE_URL",
+	"STT_PROVIDER", "DEEPGRAM_API_KEY", "OPENAI_API_KEY", "WHISPER_URL", "GOOGLE_SPEECH_API_KEY",
+	"AZURE_SPEECH_KEY", "AZURE_SPEECH_REGION",
+	"TTS_PROVIDER", "ELEVENLABS_API_KEY", "ELEVENLABS_VOICE_ID",
+	"SENTIMENT_PROVIDER", "SENTIMENT_ESCALATE_BELOW", "SENTIMENT_ESCALATE_TO",
+	"DATABASE_URL", "REDIS_URL", "SMS_FROM",
+}
+
+// setEnv clears the configuration environment, then sets the given
+// variables and the required SignalWire credentials
+func setEnv(t *testing.T, vars map[string]string) {
+	t.Helper()
+	for _, key := range configEnv {
+		t.Setenv(key, "")
+	}
+	t.Setenv("SIGNALWIRE_PROJECT_ID", "project")
+	t.Setenv("SIGNALWIRE_TOKEN", "token")
+	t.Setenv("SIGNALWIRE_SPACE", "example.signalwire.com")
+	for key, value := range vars {
+		t.Setenv(key, value)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setEnv(t, nil)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Addr != ":8080" {
+		t.Errorf("Server.Addr = %q, want :8080", cfg.Server.Addr)
+	}
+	if cfg.Sentiment.EscalateBelow != -0.5 {
+		t.Errorf("Sentiment.EscalateBelow = %v, want -0.5", cfg.Sentiment.EscalateBelow)
+	}
+	if cfg.SignalWire.Timeout != 0 {
+		t.Errorf("SignalWire.Timeout = %v, want 0 (transport default)", cfg.SignalWire.Timeout)
+	}
+	if cfg.SignalWire.Endpoints != nil || cfg.SignalWire.RelayContexts != nil {
+		t.Errorf("lists = %v, %v, want none", cfg.SignalWire.Endpoints, cfg.SignalWire.RelayContexts)
+	}
+
+	if p, err := cfg.STT.NewProvider(); p != nil || err != nil {
+		t.Errorf("STT.NewProvider() = %T, %v, want none without credentials", p, err)
+	}
+	if p, err := cfg.TTS.NewProvider(); p != nil || err != nil {
+		t.Errorf("TTS.NewProvider() = %T, %v, want none without credentials", p, err)
+	}
+	if p, err := cfg.Sentiment.NewProvider(); p != nil || err != nil {
+		t.Errorf("Sentiment.NewProvider() = %T, %v, want none without a provider", p, err)
+	}
+}
+
+func TestLoadParsing(t *testing.T) {
+	setEnv(t, map[string]string{
+		"SIGNALWIRE_ENDPOINTS":      " us-east.signalwire.com, ,us-west.signalwire.com ",
+		"SIGNALWIRE_RELAY_CONTEXTS": "office,support",
+		"SIGNALWIRE_TIMEOUT":        "45s",
+		"SIGNALWIRE_PROXY_URL":      "http://proxy:3128",
+		"LISTEN_ADDR":               ":9000",
+		"PUBLIC_BASE_URL":           "https://calls.example.com/",
+		"INSTANCE_URL":              "http://10.0.0.5:9000/",
+		"STT_PROVIDER":              "Deepgram",
+		"SENTIMENT_ESCALATE_BELOW":  "-0.25",
+	})
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"us-east.signalwire.com", "us-west.signalwire.com"}; !reflect.DeepEqual(cfg.SignalWire.Endpoints, want) {
+		t.Errorf("Endpoints = %q, want %q", cfg.SignalWire.Endpoints, want)
+	}
+	if want := []string{"office", "support"}; !reflect.DeepEqual(cfg.SignalWire.RelayContexts, want) {
+		t.Errorf("RelayContexts = %q, want %q", cfg.SignalWire.RelayContexts, want)
+	}
+	if cfg.SignalWire.Timeout != 45*time.Second {
+		t.Errorf("Timeout = %v, want 45s", cfg.SignalWire.Timeout)
+	}
+	if transport := cfg.SignalWire.Transport(); transport.ProxyURL != "http://proxy:3128" || transport.Timeout != 45*time.Second {
+		t.Errorf("Transport() = %+v", transport)
+	}
+	if cfg.Server.Addr != ":9000" {
+		t.Errorf("Addr = %q, want :9000", cfg.Server.Addr)
+	}
+	if cfg.Server.PublicBaseURL != "https://calls.example.com" || cfg.Server.InstanceURL != "http://10.0.0.5:9000" {
+		t.Errorf("URLs = %q, %q, want trailing slashes trimmed", cfg.Server.PublicBaseURL, cfg.Server.InstanceURL)
+	}
+	if cfg.STT.Provider != "deepgram" {
+		t.Errorf("STT.Provider = %q, want lowercased", cfg.STT.Provider)
+	}
+	if cfg.Sentiment.EscalateBelow != -0.25 {
+		t.Errorf("EscalateBelow = %v, want -0.25", cfg.Sentiment.EscalateBelow)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		want string
+	}{
+		{"missing credentials", map[string]string{"SIGNALWIRE_TOKEN": "", "SIGNALWIRE_SPACE": ""}, "SIGNALWIRE_TOKEN, SIGNALWIRE_SPACE"},
+		{"bad timeout", map[string]string{"SIGNALWIRE_TIMEOUT": "soon"}, "SIGNALWIRE_TIMEOUT"},
+		{"threshold out of range", map[string]string{"SENTIMENT_ESCALATE_BELOW": "-2"}, "SENTIMENT_ESCALATE_BELOW"},
+		{"threshold not a number", map[string]string{"SENTIMENT_ESCALATE_BELOW": "low"}, "SENTIMENT_ESCALATE_BELOW"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.vars)
+			_, err := Load()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want one mentioning %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		stt     STTConfig
+		want    string // Provider type, or "" for none
+		wantErr bool
+	}{
+		{"none", STTConfig{}, "", false},
+		{"first with credentials", STTConfig{OpenAIAPIKey: "k", GoogleAPIKey: "k"}, "*stt.Whisper", false},
+		{"deepgram preferred", STTConfig{DeepgramAPIKey: "k", OpenAIAPIKey: "k"}, "*stt.Deepgram", false},
+		{"named", STTConfig{Provider: "google", GoogleAPIKey: "k", DeepgramAPIKey: "k"}, "*stt.Google", false},
+		{"named without credentials", STTConfig{Provider: "deepgram"}, "", true},
+		{"azure needs region", STTConfig{Provider: "azure", AzureKey: "k"}, "", true},
+		{"unknown", STTConfig{Provider: "dragon"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := tt.stt.NewProvider()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewProvider() error = %v, want error %v", err, tt.wantErr)
+			}
+			got := ""
+			if provider != nil {
+				got = reflect.TypeOf(provider).String()
+			}
+			if got != tt.want {
+				t.Errorf("NewProvider() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCmdBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the cmd/ binaries")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	// The binaries are the config package's only consumers
+	cmd := exec.Command(goTool, "build", "-o", t.TempDir(), "./cmd/...")
+	cmd.Dir = "../.."
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build ./cmd/...: %v\n%s", err, out)
+	}
+}
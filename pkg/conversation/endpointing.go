@@ -0,0 +1,172 @@
+package conversation
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/birddigital/signalwire-telephony/pkg/stt"
+)
+
+// ============================================
+// ENDPOINTING
+// Deciding when the caller has finished their turn
+// ============================================
+
+// EndpointingConfig decides when the caller has finished speaking. A turn
+// ends once the caller has been silent for Silence, or IncompleteSilence
+// when what they said so far sounds unfinished: it trails off on a word
+// like "and" or "um", or a comma, or words are still being recognized.
+// Silence is measured from the last speech heard, by recognition results
+// or VAD events.
+type EndpointingConfig struct {
+	Silence           time.Duration // Pause ending a finished-sounding turn (default 500ms)
+	IncompleteSilence time.Duration // Pause ending an unfinished-sounding turn (default 1.5s)
+}
+
+// DefaultEndpointingConfig returns the default endpointing settings
+func DefaultEndpointingConfig() EndpointingConfig {
+	return EndpointingConfig{
+		Silence:           500 * time.Millisecond,
+		IncompleteSilence: 1500 * time.Millisecond,
+	}
+}
+
+// trailingWords sound unfinished at the end of a turn
+var trailingWords = map[string]bool{
+	"and": true, "but": true, "or": true, "so": true, "because": true, "if": true,
+	"then": true, "that": true, "which": true, "when": true, "while": true,
+	"the": true, "a": true, "an": true, "my": true, "your": true, "our": true,
+	"to": true, "of": true, "for": true, "with": true, "in": true, "on": true,
+	"at": true, "from": true, "about": true, "like": true, "is": true, "was": true,
+	"um": true, "uh": true, "er": true, "hmm": true, "mm": true,
+}
+
+// endpointer gathers recognition results into the caller's turn and
+// decides when it is over
+type endpointer struct {
+	config      EndpointingConfig
+	sttSilence  time.Duration // Silence the recognizer waits before EndOfSpeech
+	finals      []string
+	interim     string    // Words heard since the last final result
+	endOfSpeech bool      // The recognizer saw the caller stop after the last final
+	speaking    bool      // VAD hears the caller
+	lastSpeech  time.Time // When speech was last heard
+	started     time.Time // When the turn's speech was first heard
+	confidence  []float64 // Of the final results, where reported
+}
+
+// heard is a caller turn the endpointer has ended
+type heard struct {
+	text       string
+	started    time.Time
+	confidence float64 // Mean of the final results'; 0 when unknown
+}
+
+// newEndpointer creates an endpointer; sttSilence is the recognizer's own
+// endpointing, already waited when it reports EndOfSpeech
+func newEndpointer(config EndpointingConfig, sttSilence time.Duration) *endpointer {
+	defaults := DefaultEndpointingConfig()
+	if config.Silence <= 0 {
+		config.Silence = defaults.Silence
+	}
+	if config.IncompleteSilence < config.Silence {
+		config.IncompleteSilence = max(defaults.IncompleteSilence, config.Silence)
+	}
+	return &endpointer{config: config, sttSilence: sttSilence}
+}
+
+// result takes a recognition result
+func (e *endpointer) result(result stt.Result, now time.Time) {
+	if result.Transcript == "" && !result.EndOfSpeech {
+		return
+	}
+	if result.Transcript != "" {
+		e.lastSpeech = now
+		if e.started.IsZero() {
+			e.started = now
+		}
+	}
+	if !result.Final {
+		e.interim, e.endOfSpeech = result.Transcript, false
+		return
+	}
+	if result.Transcript != "" {
+		e.finals = append(e.finals, result.Transcript)
+		if result.Confidence > 0 {
+			e.confidence = append(e.confidence, result.Confidence)
+		}
+	}
+	e.interim, e.endOfSpeech = "", result.EndOfSpeech
+}
+
+// speech takes a VAD speech start or end
+func (e *endpointer) speech(started bool, now time.Time) {
+	e.speaking = started
+	e.lastSpeech = now
+	switch {
+	case started:
+		e.endOfSpeech = false
+		if e.started.IsZero() {
+			e.started = now
+		}
+	case len(e.finals) == 0 && e.interim == "":
+		e.started = time.Time{} // Noise, not a turn
+	}
+}
+
+// wait returns how long until the turn may be over, zero meaning it is,
+// and whether there is a turn at all
+func (e *endpointer) wait(now time.Time) (time.Duration, bool) {
+	if len(e.finals) == 0 && e.interim == "" {
+		return 0, false
+	}
+
+	silence := e.config.Silence
+	if e.interim != "" || incomplete(e.transcript()) {
+		silence = e.config.IncompleteSilence
+	} else if e.endOfSpeech {
+		silence -= e.sttSilence // Already waited by the recognizer
+	}
+	if e.speaking {
+		silence = max(silence, e.config.IncompleteSilence)
+	}
+	return max(e.lastSpeech.Add(silence).Sub(now), 0), true
+}
+
+// take returns the turn and starts the next. Words never finalized are
+// included.
+func (e *endpointer) take() heard {
+	turn := heard{text: e.transcript(), started: e.started}
+	for _, c := range e.confidence {
+		turn.confidence += c / float64(len(e.confidence))
+	}
+	e.finals, e.interim, e.endOfSpeech = nil, "", false
+	e.started, e.confidence = time.Time{}, nil
+	return turn
+}
+
+// transcript joins what has been heard this turn
+func (e *endpointer) transcript() string {
+	words := e.finals
+	if e.interim != "" {
+		words = append(words[:len(words):len(words)], e.interim)
+	}
+	return strings.Join(words, " ")
+}
+
+// incomplete reports whether a transcript sounds cut off mid-thought
+func incomplete(text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false
+	}
+	if strings.HasSuffix(text, ",") || strings.HasSuffix(text, "...") || strings.HasSuffix(text, "-") {
+		return true
+	}
+	fields := strings.Fields(text)
+	last := strings.ToLower(strings.TrimFunc(fields[len(fields)-1], func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}))
+	return trailingWords[last]
+}
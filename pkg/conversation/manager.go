@@ -0,0 +1,809 @@
+package conversation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/sentiment"
+	"github.com/birddigital/signalwire-telephony/pkg/stt"
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+	"github.com/birddigital/signalwire-telephony/pkg/tts"
+)
+
+// ============================================
+// CONVERSATION MANAGER
+// The per-call loop of an AI agent: hear the caller, ask the LLM, speak
+// ============================================
+
+// EventTurn is published on the event bus for each TurnEvent
+const EventTurn telephony.EventType = "conversation.turn"
+
+// ErrConversationExists is returned by Start for a session already in a
+// conversation
+var ErrConversationExists = errors.New("conversation already started")
+
+// LLM writes the agent's side of the conversation
+type LLM interface {
+	// Reply returns what the agent says next given the conversation so
+	// far, which ends with the caller's latest turn. An empty reply says
+	// nothing.
+	Reply(ctx context.Context, history []Message) (string, error)
+}
+
+// LLMFunc adapts a function to LLM
+type LLMFunc func(ctx context.Context, history []Message) (string, error)
+
+// Reply calls f
+func (f LLMFunc) Reply(ctx context.Context, history []Message) (string, error) {
+	return f(ctx, history)
+}
+
+// Role is who said a message
+type Role string
+
+const (
+	RoleCaller Role = "caller"
+	RoleAgent  Role = "agent"
+	RoleTool   Role = "tool" // A tool's result (see Tools)
+)
+
+// Message is one turn of the conversation
+type Message struct {
+	Role        Role                 `json:"role"`
+	Text        string               `json:"text"`
+	Time        time.Time            `json:"time"`                  // When the turn ended
+	Interrupted bool                 `json:"interrupted,omitempty"` // The caller cut the agent off part way
+	Sentiment   *telephony.Sentiment `json:"sentiment,omitempty"`   // Caller messages: how they came across, once scored (see SetSentiment)
+	ToolCalls   []ToolCall           `json:"tool_calls,omitempty"`  // Agent messages: tools the LLM called; Text is not spoken
+	ToolResult  *ToolResult          `json:"tool_result,omitempty"` // Tool messages: what the call returned
+}
+
+// State is what a conversation is doing
+type State string
+
+const (
+	StateListening State = "listening" // Waiting for the caller to finish speaking
+	StateThinking  State = "thinking"  // Waiting for the LLM
+	StateSpeaking  State = "speaking"  // Speaking the reply
+)
+
+// TurnType identifies a TurnEvent
+type TurnType string
+
+const (
+	TurnCaller      TurnType = "caller"      // The caller finished speaking
+	TurnAgent       TurnType = "agent"       // The agent's reply was spoken
+	TurnInterrupted TurnType = "interrupted" // The caller talked over the agent's reply, cutting it off
+	TurnTool        TurnType = "tool"        // A tool the LLM called returned
+	TurnOutcome     TurnType = "outcome"     // The goal's outcome was extracted as the conversation ended (see SetGoal)
+	TurnSentiment   TurnType = "sentiment"   // A caller turn was scored (see SetSentiment)
+	TurnError       TurnType = "error"       // Recognition, the LLM, TTS or scoring failed; the conversation carries on
+	TurnEnded       TurnType = "ended"       // The conversation is over
+)
+
+// TurnEvent reports progress through a conversation
+type TurnEvent struct {
+	Type       TurnType               `json:"type"`
+	SessionID  string                 `json:"session_id"`
+	CallSID    string                 `json:"call_sid,omitempty"`
+	Turn       int                    `json:"turn"` // Caller turns so far; 0 for the greeting
+	Text       string                 `json:"text,omitempty"`
+	Latency    time.Duration          `json:"latency,omitempty"`     // Agent and interrupted turns: from the caller finishing to the reply being ready
+	Confidence float64                `json:"confidence,omitempty"`  // Caller turns: recognition confidence, 0-1, if reported
+	ToolCall   *ToolCall              `json:"tool_call,omitempty"`   // Tool turns: the call, with Latency its run time
+	ToolResult *ToolResult            `json:"tool_result,omitempty"` // Tool turns: what it returned
+	Outcome    *telephony.GoalOutcome `json:"outcome,omitempty"`     // Outcome turns: what the conversation achieved
+	Sentiment  *telephony.Sentiment   `json:"sentiment,omitempty"`   // Sentiment turns: the score of the caller turn with this Start and Text
+	Err        error                  `json:"-"`
+	Start      time.Time              `json:"start"` // Caller, agent and interrupted turns: when speaking started
+	Time       time.Time              `json:"time"`
+}
+
+// Config tunes the conversations a Manager runs
+type Config struct {
+	STT      stt.Config // Recognition settings (default en-US, interim results, punctuated, 300ms endpointing)
+	Greeting string     // Said as the conversation starts, if set
+
+	// Endpointing decides when the caller has finished their turn, so the
+	// LLM replies at a natural pause
+	Endpointing EndpointingConfig
+
+	// BargeIn stops the agent mid-reply when the caller starts speaking,
+	// detected by VAD (see AudioStreamBridge.SetVAD) or a transcript
+	// arriving (default on)
+	BargeIn bool
+
+	// Tool calls, when the manager has tools and the LLM is a ToolLLM
+	MaxToolRounds int           // Rounds of tool calls per reply before the LLM must answer (default 5)
+	ToolTimeout   time.Duration // Limit on each tool call (default 10s, 0 = none)
+
+	// OutcomeTimeout limits extracting a goal's outcome when a conversation
+	// ends (default 30s, 0 = none)
+	OutcomeTimeout time.Duration
+
+	// SentimentTimeout limits scoring each caller turn (default 5s, 0 = none)
+	SentimentTimeout time.Duration
+}
+
+// DefaultConfig returns the default conversation settings
+func DefaultConfig() Config {
+	return Config{
+		STT: stt.Config{
+			Language:    "en-US",
+			Interim:     true, // Barge in without waiting for a final result
+			Punctuate:   true,
+			Endpointing: 300 * time.Millisecond,
+		},
+		Endpointing:      DefaultEndpointingConfig(),
+		BargeIn:          true,
+		MaxToolRounds:    5,
+		ToolTimeout:      10 * time.Second,
+		OutcomeTimeout:   30 * time.Second,
+		SentimentTimeout: 5 * time.Second,
+	}
+}
+
+// Manager runs AI conversations on bridge sessions. For each, it
+// transcribes the caller, gathers what they say into turns, asks the LLM
+// for a reply to each and speaks it, keeping the history and reporting
+// each turn to OnTurn listeners and the event bus. It is safe for
+// concurrent use.
+type Manager struct {
+	bridge *telephony.AudioStreamBridge
+	stt    stt.Provider
+	tts    tts.Provider
+	llm    LLM
+	config Config
+	voice  func(ctx context.Context, sessionID string) tts.Voice
+	tools  *Tools
+	goalOf func(ctx context.Context, sessionID string) string
+	goals  map[string]OutcomeSchema
+	mood   sentiment.Provider
+	bus    telephony.EventBus
+
+	listeners     []func(TurnEvent)
+	conversations map[string]*conversation
+	mu            sync.RWMutex
+}
+
+// conversation is one session's conversation
+type conversation struct {
+	sessionID string
+	callSID   string
+	goal      string
+	history   []Message
+	state     State
+	turn      int
+	mu        sync.Mutex
+
+	// Caller turns being scored, reported as they finish
+	scoring sync.WaitGroup
+	scored  chan scoredTurn
+}
+
+// scoredTurn is a caller turn's sentiment
+type scoredTurn struct {
+	index     int // In the history
+	text      string
+	start     time.Time
+	sentiment telephony.Sentiment
+	err       error
+}
+
+// NewManager creates a manager hearing callers through sttProvider and
+// replying with llm through ttsProvider. With no TTS provider, replies are
+// only logged and reported.
+func NewManager(bridge *telephony.AudioStreamBridge, sttProvider stt.Provider, ttsProvider tts.Provider, llm LLM) *Manager {
+	m := &Manager{
+		bridge:        bridge,
+		stt:           sttProvider,
+		tts:           ttsProvider,
+		llm:           llm,
+		config:        DefaultConfig(),
+		goals:         make(map[string]OutcomeSchema),
+		conversations: make(map[string]*conversation),
+	}
+	for _, schema := range DefaultOutcomeSchemas() {
+		m.goals[schema.Goal] = schema
+	}
+	return m
+}
+
+// SetConfig replaces the settings for conversations started from now on
+func (m *Manager) SetConfig(config Config) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config = config
+}
+
+// SetVoice chooses each session's voice, e.g. from its call's config with
+// tts.CallVoice; by default the TTS provider's default voice is used
+func (m *Manager) SetVoice(voice func(ctx context.Context, sessionID string) tts.Voice) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.voice = voice
+}
+
+// SetTools offers tools to the LLM, which must implement ToolLLM to call
+// them. Tool calls and results are added to the history and reported as
+// tool turns.
+func (m *Manager) SetTools(tools *Tools) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tools = tools
+}
+
+// SetGoal chooses each session's conversation goal, e.g. its call's
+// ConversationGoal. When the LLM is an OutcomeLLM and the goal has a schema
+// (see SetOutcomeSchema), the conversation's outcome is extracted as it
+// ends and reported as a TurnOutcome.
+func (m *Manager) SetGoal(goal func(ctx context.Context, sessionID string) string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.goalOf = goal
+}
+
+// SetOutcomeSchema adds the schema for a goal's outcome, replacing any for
+// the same goal. Schemas for quote, claim and appointment are preset.
+func (m *Manager) SetOutcomeSchema(schema OutcomeSchema) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.goals[schema.Goal] = schema
+}
+
+// SetSentiment scores each caller turn with provider, reported as a
+// TurnSentiment once done and kept on the turn's Message. Scoring runs
+// alongside the reply, so it doesn't hold the agent up.
+func (m *Manager) SetSentiment(provider sentiment.Provider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mood = provider
+}
+
+// SetEventBus publishes turn events (EventTurn) and live transcript updates
+// (telephony.EventTranscript) to bus
+func (m *Manager) SetEventBus(bus telephony.EventBus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bus = bus
+}
+
+// OnTurn registers a listener called with each turn event, from the
+// conversation's goroutine
+func (m *Manager) OnTurn(listener func(TurnEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, listener)
+}
+
+// Start begins a conversation on a bridge session, consuming its phone →
+// AI audio. The conversation runs until the session closes or ctx ends.
+func (m *Manager) Start(ctx context.Context, sessionID string) error {
+	session := m.bridge.GetSession(sessionID)
+	if session == nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+	if m.stt == nil {
+		return fmt.Errorf("no speech recognition provider")
+	}
+	if m.llm == nil {
+		return fmt.Errorf("no LLM")
+	}
+
+	m.mu.Lock()
+	if _, exists := m.conversations[sessionID]; exists {
+		m.mu.Unlock()
+		return ErrConversationExists
+	}
+	c := &conversation{
+		sessionID: sessionID,
+		callSID:   session.CallSID,
+		state:     StateListening,
+		scored:    make(chan scoredTurn, 16),
+	}
+	m.conversations[sessionID] = c
+	config := m.config
+	goalOf := m.goalOf
+	m.mu.Unlock()
+
+	if goalOf != nil {
+		c.goal = goalOf(ctx, sessionID)
+	}
+
+	results, err := stt.RecognizeSession(ctx, m.stt, m.bridge, sessionID, config.STT)
+	if err != nil {
+		m.remove(c)
+		return fmt.Errorf("failed to start recognition: %w", err)
+	}
+
+	log.Printf("[Conversation] Started: %s", sessionID)
+	go m.run(ctx, c, results, config)
+	return nil
+}
+
+// History returns a session's conversation so far, and whether it is in one
+func (m *Manager) History(sessionID string) ([]Message, bool) {
+	c := m.get(sessionID)
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Message(nil), c.history...), true
+}
+
+// State returns what a session's conversation is doing, and whether it is
+// in one
+func (m *Manager) State(sessionID string) (State, bool) {
+	c := m.get(sessionID)
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state, true
+}
+
+// get returns a session's conversation, or nil
+func (m *Manager) get(sessionID string) *conversation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.conversations[sessionID]
+}
+
+// remove forgets a conversation
+func (m *Manager) remove(c *conversation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.conversations[c.sessionID] == c {
+		delete(m.conversations, c.sessionID)
+	}
+}
+
+// reply is the agent speaking a turn in the background
+type reply struct {
+	text        string
+	latency     time.Duration
+	started     time.Time
+	cancel      context.CancelFunc
+	done        chan error
+	interrupted bool
+}
+
+// run replies to each thing the caller says until recognition ends
+func (m *Manager) run(ctx context.Context, c *conversation, results <-chan stt.Result, config Config) {
+	var speaking *reply
+	defer func() {
+		if speaking != nil {
+			m.finish(c, speaking, <-speaking.done)
+		}
+		go func() {
+			c.scoring.Wait()
+			close(c.scored)
+		}()
+		for scored := range c.scored {
+			m.reportSentiment(c, scored)
+		}
+		m.extractOutcome(ctx, c, config)
+		m.remove(c)
+		m.fire(c, TurnEvent{Type: TurnEnded})
+		log.Printf("[Conversation] Ended: %s (%d turns)", c.sessionID, c.turns())
+	}()
+
+	// VAD, when enabled on the bridge, notices the caller starting to speak
+	// sooner than recognition does
+	speech, _ := m.bridge.GetSpeechEvents(c.sessionID)
+
+	if config.Greeting != "" {
+		speaking = m.speak(ctx, c, config.Greeting, time.Now())
+	}
+
+	// Results arrive a phrase at a time; the endpointer decides when the
+	// caller has finished their turn
+	turn := newEndpointer(config.Endpointing, config.STT.Endpointing)
+	endpoint := time.NewTimer(0)
+	<-endpoint.C
+	defer endpoint.Stop()
+	for {
+		var done <-chan error
+		if speaking != nil {
+			done = speaking.done
+		}
+
+		select {
+		case err := <-done:
+			m.finish(c, speaking, err)
+			speaking = nil
+			continue
+
+		case scored := <-c.scored:
+			m.reportSentiment(c, scored)
+			continue
+
+		case event, ok := <-speech:
+			if !ok {
+				speech = nil
+				continue
+			}
+			started := event.Type == telephony.EventSpeechStarted
+			if started && speaking != nil && config.BargeIn {
+				m.interrupt(c, speaking)
+				speaking = nil
+			}
+			turn.speech(started, time.Now())
+
+		case result, ok := <-results:
+			if !ok {
+				return
+			}
+			if result.Err != nil {
+				m.fire(c, TurnEvent{Type: TurnError, Err: fmt.Errorf("speech recognition failed: %w", result.Err)})
+				continue
+			}
+			if result.Transcript != "" && speaking != nil && config.BargeIn {
+				m.interrupt(c, speaking)
+				speaking = nil
+			}
+			if result.Transcript != "" {
+				m.publishTranscript(c, telephony.SpeakerCaller, result.Transcript, result.Final, result.Confidence)
+			}
+			turn.result(result, time.Now())
+
+		case <-endpoint.C:
+		}
+
+		// Wait out the pause that ends the turn
+		wait, ok := turn.wait(time.Now())
+		endpoint.Stop()
+		if !ok {
+			continue
+		}
+		if wait > 0 {
+			endpoint.Reset(wait)
+			continue
+		}
+		heard := turn.take()
+		if heard.started.IsZero() {
+			heard.started = time.Now()
+		}
+
+		// Without barge-in, the agent finishes before taking the next turn
+		if speaking != nil {
+			m.finish(c, speaking, <-speaking.done)
+			speaking = nil
+		}
+
+		heardAt := time.Now()
+		c.mu.Lock()
+		c.history = append(c.history, Message{Role: RoleCaller, Text: heard.text, Time: heardAt})
+		index := len(c.history) - 1
+		c.turn++
+		c.state = StateThinking
+		c.mu.Unlock()
+		m.fire(c, TurnEvent{Type: TurnCaller, Text: heard.text, Confidence: heard.confidence, Start: heard.started, Time: heardAt})
+		m.score(ctx, c, index, heard, config)
+
+		text, err := m.respond(ctx, c, config)
+		if err != nil {
+			c.setState(StateListening)
+			m.fire(c, TurnEvent{Type: TurnError, Err: fmt.Errorf("LLM failed: %w", err)})
+			continue
+		}
+		if text == "" {
+			c.setState(StateListening)
+			continue
+		}
+		speaking = m.speak(ctx, c, text, heardAt)
+	}
+}
+
+// respond asks the LLM for a reply to the conversation so far, running the
+// tools it calls along the way
+func (m *Manager) respond(ctx context.Context, c *conversation, config Config) (string, error) {
+	m.mu.RLock()
+	tools := m.tools
+	m.mu.RUnlock()
+
+	llm, ok := m.llm.(ToolLLM)
+	if tools == nil || !ok {
+		return m.llm.Reply(ctx, c.messages())
+	}
+
+	for round := 0; ; round++ {
+		offered := tools.List()
+		if round >= config.MaxToolRounds {
+			offered = nil // Time to answer
+		}
+		response, err := llm.ReplyWithTools(ctx, c.messages(), offered)
+		if err != nil || len(response.ToolCalls) == 0 {
+			return response.Text, err
+		}
+		if len(offered) == 0 {
+			return "", fmt.Errorf("LLM called tools after %d rounds", round)
+		}
+
+		c.mu.Lock()
+		c.history = append(c.history, Message{Role: RoleAgent, Text: response.Text, Time: time.Now(), ToolCalls: response.ToolCalls})
+		c.mu.Unlock()
+
+		for _, call := range response.ToolCalls {
+			call.SessionID, call.CallSID = c.sessionID, c.callSID
+			started := time.Now()
+			result := tools.Call(ctx, call, config.ToolTimeout)
+
+			c.mu.Lock()
+			c.history = append(c.history, Message{Role: RoleTool, Text: result.Output, Time: time.Now(), ToolResult: &result})
+			c.mu.Unlock()
+			m.fire(c, TurnEvent{Type: TurnTool, Text: call.Name, ToolCall: &call, ToolResult: &result, Latency: time.Since(started), Start: started})
+		}
+	}
+}
+
+// speak starts saying text as the agent's turn, heardAt being when the
+// caller's turn ended
+func (m *Manager) speak(ctx context.Context, c *conversation, text string, heardAt time.Time) *reply {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &reply{
+		text:    text,
+		latency: time.Since(heardAt),
+		started: time.Now(),
+		cancel:  cancel,
+		done:    make(chan error, 1),
+	}
+	c.setState(StateSpeaking)
+	m.publishTranscript(c, telephony.SpeakerAgent, text, true, 0)
+
+	m.mu.RLock()
+	voiceFor := m.voice
+	m.mu.RUnlock()
+
+	go func() {
+		defer cancel()
+		if m.tts == nil {
+			log.Printf("[Conversation] Would say: %s", text)
+			r.done <- nil
+			return
+		}
+		var voice tts.Voice
+		if voiceFor != nil {
+			voice = voiceFor(ctx, c.sessionID)
+		}
+		r.done <- tts.Speak(ctx, m.tts, m.bridge, c.sessionID, text, voice)
+	}()
+	return r
+}
+
+// interrupt cuts the agent off because the caller started speaking:
+// synthesis stops and audio not yet played is dropped
+func (m *Manager) interrupt(c *conversation, r *reply) {
+	r.interrupted = true
+	r.cancel()
+	err := <-r.done
+	if flushErr := m.bridge.FlushAIAudio(c.sessionID); flushErr != nil {
+		log.Printf("[Conversation] Failed to flush AI audio: %v", flushErr)
+	}
+	m.finish(c, r, err)
+}
+
+// finish records the agent's turn once it has been spoken or cut off
+func (m *Manager) finish(c *conversation, r *reply, err error) {
+	c.setState(StateListening)
+	if err != nil && !r.interrupted {
+		m.fire(c, TurnEvent{Type: TurnError, Text: r.text, Err: fmt.Errorf("failed to speak: %w", err)})
+		return
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	c.history = append(c.history, Message{Role: RoleAgent, Text: r.text, Time: now, Interrupted: r.interrupted})
+	c.mu.Unlock()
+
+	event := TurnEvent{Type: TurnAgent, Text: r.text, Latency: r.latency, Start: r.started, Time: now}
+	if r.interrupted {
+		event.Type = TurnInterrupted
+	}
+	m.fire(c, event)
+}
+
+// score starts rating a caller turn's sentiment in the background, when
+// there is a provider. The result is reported from run.
+func (m *Manager) score(ctx context.Context, c *conversation, index int, caller heard, config Config) {
+	m.mu.RLock()
+	provider := m.mood
+	m.mu.RUnlock()
+	if provider == nil {
+		return
+	}
+
+	c.scoring.Add(1)
+	go func() {
+		defer c.scoring.Done()
+		ctx := context.WithoutCancel(ctx) // Finish scoring a call that just ended
+		if config.SentimentTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, config.SentimentTimeout)
+			defer cancel()
+		}
+
+		sentiment, err := provider.Analyze(ctx, caller.text)
+		c.scored <- scoredTurn{index: index, text: caller.text, start: caller.started, sentiment: sentiment, err: err}
+	}()
+}
+
+// reportSentiment keeps a scored turn's sentiment on its message and
+// reports it
+func (m *Manager) reportSentiment(c *conversation, scored scoredTurn) {
+	if scored.err != nil {
+		m.fire(c, TurnEvent{Type: TurnError, Text: scored.text, Err: fmt.Errorf("sentiment analysis failed: %w", scored.err)})
+		return
+	}
+
+	c.mu.Lock()
+	if scored.index < len(c.history) {
+		c.history[scored.index].Sentiment = &scored.sentiment
+	}
+	c.mu.Unlock()
+	m.fire(c, TurnEvent{Type: TurnSentiment, Text: scored.text, Sentiment: &scored.sentiment, Start: scored.start})
+}
+
+// extractOutcome asks the LLM what the conversation achieved toward its
+// goal, reporting it as a TurnOutcome. It runs as the conversation ends, so
+// it outlives ctx.
+func (m *Manager) extractOutcome(ctx context.Context, c *conversation, config Config) {
+	if c.goal == "" {
+		return
+	}
+	extractor, ok := m.llm.(OutcomeLLM)
+	if !ok {
+		return
+	}
+	m.mu.RLock()
+	schema, ok := m.goals[c.goal]
+	m.mu.RUnlock()
+	if !ok {
+		log.Printf("[Conversation] %s: no outcome schema for goal %q", c.sessionID, c.goal)
+		return
+	}
+
+	// With nothing heard from the caller there is nothing to extract
+	history := c.messages()
+	heard := false
+	for _, message := range history {
+		if message.Role == RoleCaller {
+			heard = true
+			break
+		}
+	}
+	if !heard {
+		m.fire(c, TurnEvent{Type: TurnOutcome, Outcome: &telephony.GoalOutcome{Goal: c.goal, ExtractedAt: time.Now()}})
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	if config.OutcomeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.OutcomeTimeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	raw, err := extractor.ExtractOutcome(ctx, history, schema)
+	if err != nil {
+		m.fire(c, TurnEvent{Type: TurnError, Err: fmt.Errorf("outcome extraction failed: %w", err)})
+		return
+	}
+	outcome, err := schema.Validate(raw)
+	if err != nil {
+		m.fire(c, TurnEvent{Type: TurnError, Text: string(raw), Err: fmt.Errorf("invalid %s outcome: %w", c.goal, err)})
+		return
+	}
+	outcome.ExtractedAt = time.Now()
+	m.fire(c, TurnEvent{Type: TurnOutcome, Outcome: &outcome, Latency: time.Since(started), Start: started})
+}
+
+// fire completes a turn event and delivers it to the listeners and bus
+func (m *Manager) fire(c *conversation, event TurnEvent) {
+	event.SessionID, event.CallSID = c.sessionID, c.callSID
+	event.Turn = c.turns()
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Err != nil {
+		log.Printf("[Conversation] %s: %v", c.sessionID, event.Err)
+	}
+
+	m.mu.RLock()
+	listeners := append([]func(TurnEvent){}, m.listeners...)
+	bus := m.bus
+	m.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+	if bus != nil {
+		data := map[string]interface{}{
+			"type": string(event.Type),
+			"turn": event.Turn,
+		}
+		if event.Text != "" {
+			data["text"] = event.Text
+		}
+		if event.Latency > 0 {
+			data["latency_ms"] = event.Latency.Milliseconds()
+		}
+		if event.Confidence > 0 {
+			data["confidence"] = event.Confidence
+		}
+		if event.ToolResult != nil {
+			data["tool"] = event.ToolResult.Name
+			data["tool_error"] = event.ToolResult.IsError
+		}
+		if event.Sentiment != nil {
+			data["sentiment"] = event.Sentiment.Score
+			if event.Sentiment.Emotion != "" {
+				data["emotion"] = event.Sentiment.Emotion
+			}
+		}
+		if event.Outcome != nil {
+			data["goal"] = event.Outcome.Goal
+			data["achieved"] = event.Outcome.Achieved
+			data["outcome"] = event.Outcome.Data
+		}
+		if event.Err != nil {
+			data["error"] = event.Err.Error()
+		}
+		bus.Publish(telephony.Event{
+			Type:      EventTurn,
+			CallSID:   event.CallSID,
+			SessionID: event.SessionID,
+			Data:      data,
+			Timestamp: event.Time,
+		})
+	}
+}
+
+// publishTranscript publishes speech heard or said as a live transcript
+// update (telephony.EventTranscript), when there is an event bus
+func (m *Manager) publishTranscript(c *conversation, speaker telephony.Speaker, text string, final bool, confidence float64) {
+	m.mu.RLock()
+	bus := m.bus
+	m.mu.RUnlock()
+	if bus == nil {
+		return
+	}
+
+	telephony.PublishTranscript(bus, telephony.TranscriptUpdate{
+		SessionID:  c.sessionID,
+		CallSID:    c.callSID,
+		Speaker:    speaker,
+		Text:       text,
+		Final:      final,
+		Confidence: confidence,
+		Time:       time.Now(),
+	})
+}
+
+// setState records what the conversation is doing
+func (c *conversation) setState(state State) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state = state
+}
+
+// messages returns the history so far
+func (c *conversation) messages() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Message(nil), c.history...)
+}
+
+// turns returns the caller turns so far
+func (c *conversation) turns() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.turn
+}
@@ -0,0 +1,444 @@
+package conversation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/sentiment"
+	"github.com/birddigital/signalwire-telephony/pkg/stt"
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+	"github.com/birddigital/signalwire-telephony/pkg/tts"
+)
+
+// fakeSTT hands the test's results to the conversation
+type fakeSTT struct {
+	results chan stt.Result
+}
+
+func (f *fakeSTT) Recognize(ctx context.Context, audio <-chan []byte, config stt.Config) (<-chan stt.Result, error) {
+	return f.results, nil
+}
+
+// untilCutOff is how long a held reply plays: until barge-in stops it
+const untilCutOff = time.Hour
+
+// fakeTTS records what the agent says. Replies play instantly unless given
+// a playing time.
+type fakeTTS struct {
+	play map[string]time.Duration
+
+	mu   sync.Mutex
+	said []string
+}
+
+func (f *fakeTTS) Synthesize(ctx context.Context, text string, voice tts.Voice, format telephony.AudioFormat) ([]byte, error) {
+	return nil, nil
+}
+
+func (f *fakeTTS) SynthesizeStream(ctx context.Context, text string, voice tts.Voice, format telephony.AudioFormat) (<-chan tts.Frame, error) {
+	f.mu.Lock()
+	f.said = append(f.said, text)
+	f.mu.Unlock()
+
+	frames := make(chan tts.Frame)
+	go func() {
+		defer close(frames)
+		select {
+		case <-time.After(f.play[text]):
+		case <-ctx.Done():
+		}
+	}()
+	return frames, nil
+}
+
+// outcomeLLM replies with LLM and extracts raw as the outcome
+type outcomeLLM struct {
+	LLM
+	raw string
+}
+
+func (l *outcomeLLM) ExtractOutcome(ctx context.Context, history []Message, schema OutcomeSchema) (json.RawMessage, error) {
+	return json.RawMessage(l.raw), nil
+}
+
+// echo replies to the caller's latest turn
+var echo = LLMFunc(func(ctx context.Context, history []Message) (string, error) {
+	return "You said: " + history[len(history)-1].Text, nil
+})
+
+// step is one thing the caller does: optionally a pause, a recognition
+// result, then waiting for the conversation to report a turn
+type step struct {
+	pause  time.Duration
+	result *stt.Result
+	await  TurnType
+}
+
+// says is the caller finishing a phrase
+func says(text string) step {
+	return step{result: &stt.Result{Transcript: text, Final: true, EndOfSpeech: true, Confidence: 0.9}}
+}
+
+// starts is the caller's words arriving mid-phrase
+func starts(text string) step {
+	return step{result: &stt.Result{Transcript: text}}
+}
+
+// await waits for a turn event
+func await(turn TurnType) step {
+	return step{await: turn}
+}
+
+// then adds a pause before a step
+func (s step) then(pause time.Duration) step {
+	s.pause = pause
+	return s
+}
+
+// recording is a finished conversation as the test saw it
+type recording struct {
+	events  []TurnEvent
+	history []Message // As of the last event while the conversation ran
+	said    []string  // Texts sent to TTS
+}
+
+// types returns the recorded turn types
+func (r *recording) types() []TurnType {
+	types := make([]TurnType, len(r.events))
+	for i, event := range r.events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+// event returns the first recorded event of a type
+func (r *recording) event(t *testing.T, turn TurnType) TurnEvent {
+	t.Helper()
+	for _, event := range r.events {
+		if event.Type == turn {
+			return event
+		}
+	}
+	t.Fatalf("no %s event in %v", turn, r.types())
+	return TurnEvent{}
+}
+
+func TestManager(t *testing.T) {
+	tests := []struct {
+		name     string
+		greeting string
+		play     map[string]time.Duration
+		llm      LLM
+		setup    func(t *testing.T, m *Manager, config *Config)
+		steps    []step
+		want     []TurnType
+		check    func(t *testing.T, r *recording)
+	}{
+		{
+			name:     "greets and replies",
+			greeting: "Hi, how can I help?",
+			llm:      echo,
+			steps:    []step{await(TurnAgent), says("I need a quote."), await(TurnAgent)},
+			want:     []TurnType{TurnAgent, TurnCaller, TurnAgent, TurnEnded},
+			check: func(t *testing.T, r *recording) {
+				want := []string{"Hi, how can I help?", "I need a quote.", "You said: I need a quote."}
+				if got := messageTexts(r.history); !reflect.DeepEqual(got, want) {
+					t.Errorf("history = %q, want %q", got, want)
+				}
+				if caller := r.event(t, TurnCaller); caller.Turn != 1 || caller.Confidence != 0.9 {
+					t.Errorf("caller turn = %d, confidence %v", caller.Turn, caller.Confidence)
+				}
+			},
+		},
+		{
+			name:     "barge-in cuts the agent off",
+			greeting: "Thanks for calling, this call may be recorded for",
+			play:     map[string]time.Duration{"Thanks for calling, this call may be recorded for": untilCutOff},
+			llm:      echo,
+			steps:    []step{starts("wait"), says("Wait, I'm in a hurry."), await(TurnAgent)},
+			want:     []TurnType{TurnInterrupted, TurnCaller, TurnAgent, TurnEnded},
+			check: func(t *testing.T, r *recording) {
+				if len(r.history) == 0 || !r.history[0].Interrupted {
+					t.Errorf("greeting not marked interrupted: %+v", r.history)
+				}
+				if caller := r.event(t, TurnCaller); caller.Text != "Wait, I'm in a hurry." {
+					t.Errorf("caller turn = %q, want the final transcript", caller.Text)
+				}
+			},
+		},
+		{
+			name:     "without barge-in the agent finishes first",
+			greeting: "Thanks for calling.",
+			play:     map[string]time.Duration{"Thanks for calling.": 100 * time.Millisecond},
+			llm:      echo,
+			setup: func(t *testing.T, m *Manager, config *Config) {
+				config.BargeIn = false
+			},
+			steps: []step{starts("hello"), says("Hello?"), await(TurnAgent), await(TurnAgent)},
+			want:  []TurnType{TurnAgent, TurnCaller, TurnAgent, TurnEnded},
+			check: func(t *testing.T, r *recording) {
+				if len(r.history) == 0 || r.history[0].Interrupted {
+					t.Errorf("greeting interrupted with barge-in off: %+v", r.history)
+				}
+			},
+		},
+		{
+			name: "endpointing waits out an unfinished turn",
+			llm:  echo,
+			steps: []step{
+				says("I was calling about"),
+				says("my policy.").then(50 * time.Millisecond),
+				await(TurnAgent),
+			},
+			want: []TurnType{TurnCaller, TurnAgent, TurnEnded},
+			check: func(t *testing.T, r *recording) {
+				if caller := r.event(t, TurnCaller); caller.Text != "I was calling about my policy." {
+					t.Errorf("caller turn = %q, want both phrases", caller.Text)
+				}
+			},
+		},
+		{
+			name:  "endpointing ends finished turns",
+			llm:   echo,
+			steps: []step{says("Yes."), await(TurnAgent), says("No."), await(TurnAgent)},
+			want:  []TurnType{TurnCaller, TurnAgent, TurnCaller, TurnAgent, TurnEnded},
+			check: func(t *testing.T, r *recording) {
+				want := []string{"You said: Yes.", "You said: No."}
+				if !reflect.DeepEqual(r.said, want) {
+					t.Errorf("said %q, want %q", r.said, want)
+				}
+			},
+		},
+		{
+			name: "tool rounds",
+			llm: ToolLLMFunc(func(ctx context.Context, history []Message, tools []Tool) (Response, error) {
+				last := history[len(history)-1]
+				if last.Role == RoleTool {
+					return Response{Text: "Your policy is " + last.Text + "."}, nil
+				}
+				return Response{ToolCalls: []ToolCall{{ID: "call-1", Name: "policy_status", Arguments: json.RawMessage(`{"number":"P-1"}`)}}}, nil
+			}),
+			setup: func(t *testing.T, m *Manager, config *Config) {
+				tools := NewTools()
+				err := tools.Register(Tool{Name: "policy_status", Handler: func(ctx context.Context, call ToolCall) (string, error) {
+					if call.SessionID != "session-1" {
+						return "", errors.New("call not tied to the session")
+					}
+					return "active", nil
+				}})
+				if err != nil {
+					t.Fatal(err)
+				}
+				m.SetTools(tools)
+			},
+			steps: []step{says("Is my policy active?"), await(TurnAgent)},
+			want:  []TurnType{TurnCaller, TurnTool, TurnAgent, TurnEnded},
+			check: func(t *testing.T, r *recording) {
+				if tool := r.event(t, TurnTool); tool.ToolResult.IsError || tool.ToolResult.CallID != "call-1" {
+					t.Errorf("tool result = %+v", tool.ToolResult)
+				}
+				roles := make([]Role, len(r.history))
+				for i, message := range r.history {
+					roles[i] = message.Role
+				}
+				if want := []Role{RoleCaller, RoleAgent, RoleTool, RoleAgent}; !reflect.DeepEqual(roles, want) {
+					t.Errorf("history roles = %v, want %v", roles, want)
+				}
+				if want := []string{"Your policy is active."}; !reflect.DeepEqual(r.said, want) {
+					t.Errorf("said %q, want %q", r.said, want)
+				}
+			},
+		},
+		{
+			name: "tool rounds are capped",
+			llm: ToolLLMFunc(func(ctx context.Context, history []Message, tools []Tool) (Response, error) {
+				if len(tools) == 0 {
+					return Response{Text: "Let me get back to you."}, nil
+				}
+				return Response{ToolCalls: []ToolCall{{ID: "again", Name: "lookup"}}}, nil
+			}),
+			setup: func(t *testing.T, m *Manager, config *Config) {
+				config.MaxToolRounds = 2
+				tools := NewTools()
+				if err := tools.Register(Tool{Name: "lookup", Handler: func(ctx context.Context, call ToolCall) (string, error) {
+					return "nothing yet", nil
+				}}); err != nil {
+					t.Fatal(err)
+				}
+				m.SetTools(tools)
+			},
+			steps: []step{says("Look it up."), await(TurnAgent)},
+			want:  []TurnType{TurnCaller, TurnTool, TurnTool, TurnAgent, TurnEnded},
+		},
+		{
+			name: "LLM failure carries on",
+			llm: LLMFunc(func(ctx context.Context, history []Message) (string, error) {
+				return "", errors.New("rate limited")
+			}),
+			steps: []step{says("Hello?"), await(TurnError)},
+			want:  []TurnType{TurnCaller, TurnError, TurnEnded},
+			check: func(t *testing.T, r *recording) {
+				if event := r.event(t, TurnError); !strings.Contains(event.Err.Error(), "rate limited") {
+					t.Errorf("error = %v", event.Err)
+				}
+			},
+		},
+		{
+			name: "outcome extracted as the conversation ends",
+			llm:  &outcomeLLM{LLM: echo, raw: `{"achieved": true, "product": "auto insurance", "amount": 120}`},
+			setup: func(t *testing.T, m *Manager, config *Config) {
+				m.SetGoal(func(ctx context.Context, sessionID string) string { return GoalQuote })
+			},
+			steps: []step{says("How much for auto insurance?"), await(TurnAgent)},
+			want:  []TurnType{TurnCaller, TurnAgent, TurnOutcome, TurnEnded},
+			check: func(t *testing.T, r *recording) {
+				outcome := r.event(t, TurnOutcome).Outcome
+				if outcome.Goal != GoalQuote || !outcome.Achieved || outcome.Data["product"] != "auto insurance" {
+					t.Errorf("outcome = %+v", outcome)
+				}
+			},
+		},
+		{
+			name: "no outcome extracted when the caller said nothing",
+			llm:  &outcomeLLM{LLM: echo, raw: `{"achieved": true, "product": "auto insurance"}`},
+			setup: func(t *testing.T, m *Manager, config *Config) {
+				m.SetGoal(func(ctx context.Context, sessionID string) string { return GoalQuote })
+			},
+			want: []TurnType{TurnOutcome, TurnEnded},
+			check: func(t *testing.T, r *recording) {
+				if outcome := r.event(t, TurnOutcome).Outcome; outcome.Achieved || outcome.Data != nil {
+					t.Errorf("outcome = %+v, want not achieved", outcome)
+				}
+			},
+		},
+		{
+			name: "shutdown reports turns still being scored",
+			llm:  echo,
+			setup: func(t *testing.T, m *Manager, config *Config) {
+				m.SetSentiment(sentiment.ProviderFunc(func(ctx context.Context, text string) (telephony.Sentiment, error) {
+					time.Sleep(100 * time.Millisecond)
+					return telephony.Sentiment{Score: -0.6, Emotion: "frustrated"}, nil
+				}))
+			},
+			steps: []step{says("This is ridiculous."), await(TurnAgent)},
+			want:  []TurnType{TurnCaller, TurnAgent, TurnSentiment, TurnEnded},
+			check: func(t *testing.T, r *recording) {
+				if scored := r.event(t, TurnSentiment); scored.Text != "This is ridiculous." || scored.Sentiment.Score != -0.6 {
+					t.Errorf("sentiment turn = %+v", scored)
+				}
+				if len(r.history) == 0 || r.history[0].Sentiment == nil || r.history[0].Sentiment.Emotion != "frustrated" {
+					t.Errorf("caller turn not scored in history: %+v", r.history)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const sessionID = "session-1"
+			bridge := telephony.NewAudioStreamBridge()
+			defer bridge.Close()
+			if _, err := bridge.CreateSession(sessionID); err != nil {
+				t.Fatal(err)
+			}
+
+			recognizer := &fakeSTT{results: make(chan stt.Result)}
+			speaker := &fakeTTS{play: tt.play}
+			m := NewManager(bridge, recognizer, speaker, tt.llm)
+
+			config := DefaultConfig()
+			config.Greeting = tt.greeting
+			config.Endpointing = EndpointingConfig{Silence: 10 * time.Millisecond, IncompleteSilence: 300 * time.Millisecond}
+			if tt.setup != nil {
+				tt.setup(t, m, &config)
+			}
+			m.SetConfig(config)
+
+			var (
+				mu sync.Mutex
+				r  recording
+			)
+			events := make(chan TurnEvent, 64)
+			m.OnTurn(func(event TurnEvent) {
+				mu.Lock()
+				r.events = append(r.events, event)
+				if history, ok := m.History(sessionID); ok {
+					r.history = history
+				}
+				mu.Unlock()
+				events <- event
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if err := m.Start(ctx, sessionID); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.Start(ctx, sessionID); !errors.Is(err, ErrConversationExists) {
+				t.Errorf("second Start = %v, want ErrConversationExists", err)
+			}
+
+			awaitTurn := func(turn TurnType) {
+				t.Helper()
+				timeout := time.After(2 * time.Second)
+				for {
+					select {
+					case event := <-events:
+						if event.Type == turn {
+							return
+						}
+					case <-timeout:
+						t.Fatalf("timed out waiting for a %s turn", turn)
+					}
+				}
+			}
+
+			for _, s := range tt.steps {
+				time.Sleep(s.pause)
+				if s.result != nil {
+					recognizer.results <- *s.result
+				}
+				if s.await != "" {
+					awaitTurn(s.await)
+				}
+			}
+			close(recognizer.results)
+			awaitTurn(TurnEnded)
+
+			if _, ok := m.History(sessionID); ok {
+				t.Error("conversation still registered after it ended")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			speaker.mu.Lock()
+			r.said = append(r.said, speaker.said...)
+			speaker.mu.Unlock()
+			if tt.greeting != "" && len(r.said) > 0 {
+				r.said = r.said[1:]
+			}
+
+			if got := r.types(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("turns = %v, want %v", got, tt.want)
+			}
+			if tt.check != nil {
+				tt.check(t, &r)
+			}
+		})
+	}
+}
+
+// messageTexts returns the text of each message
+func messageTexts(history []Message) []string {
+	texts := make([]string, len(history))
+	for i, message := range history {
+		texts[i] = message.Text
+	}
+	return texts
+}
@@ -0,0 +1,275 @@
+package conversation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// GOAL OUTCOMES
+// Structured results extracted from a conversation when it ends
+// ============================================
+
+// Goals with default outcome schemas (see telephony.CallConfig.ConversationGoal)
+const (
+	GoalQuote       = "quote"
+	GoalClaim       = "claim"
+	GoalAppointment = "appointment"
+)
+
+// FieldType is the kind of value an outcome field holds
+type FieldType string
+
+const (
+	FieldString   FieldType = "string"
+	FieldNumber   FieldType = "number"
+	FieldInteger  FieldType = "integer"
+	FieldBoolean  FieldType = "boolean"
+	FieldDate     FieldType = "date"     // YYYY-MM-DD
+	FieldDateTime FieldType = "datetime" // RFC 3339
+)
+
+// OutcomeField is one value to extract from a conversation
+type OutcomeField struct {
+	Name        string    `json:"name"`
+	Type        FieldType `json:"type"`
+	Description string    `json:"description,omitempty"` // Tells the LLM what to put in it
+	Required    bool      `json:"required,omitempty"`    // Must be set when the goal was achieved
+	Enum        []string  `json:"enum,omitempty"`        // Allowed values of a string field
+}
+
+// OutcomeSchema is what to extract from a conversation with a goal
+type OutcomeSchema struct {
+	Goal        string         `json:"goal"`
+	Description string         `json:"description"` // What achieving the goal means
+	Fields      []OutcomeField `json:"fields"`
+}
+
+// OutcomeLLM is an LLM that can extract a conversation's outcome. When a
+// Manager's LLM implements it, conversations with a goal (see SetGoal)
+// report a TurnOutcome as they end.
+type OutcomeLLM interface {
+	// ExtractOutcome returns a JSON object matching schema.JSONSchema() for
+	// the finished conversation, e.g. by sending schema.Prompt() after the
+	// history
+	ExtractOutcome(ctx context.Context, history []Message, schema OutcomeSchema) (json.RawMessage, error)
+}
+
+// DefaultOutcomeSchemas returns the schemas for the built-in goals
+func DefaultOutcomeSchemas() []OutcomeSchema {
+	return []OutcomeSchema{
+		{
+			Goal:        GoalQuote,
+			Description: "The caller got a price quote or agreed to receive one",
+			Fields: []OutcomeField{
+				{Name: "product", Type: FieldString, Required: true, Description: "What the quote is for"},
+				{Name: "amount", Type: FieldNumber, Description: "Quoted price"},
+				{Name: "currency", Type: FieldString, Description: "ISO 4217 code of the amount, e.g. USD"},
+				{Name: "term", Type: FieldString, Description: "Billing period or term of the quote, e.g. monthly"},
+				{Name: "email", Type: FieldString, Description: "Where to send the quote, if the caller gave it"},
+				{Name: "follow_up_date", Type: FieldDate, Description: "When the caller asked to be contacted again"},
+				{Name: "objections", Type: FieldString, Description: "Reasons the caller gave for hesitating or declining"},
+			},
+		},
+		{
+			Goal:        GoalClaim,
+			Description: "The caller reported a claim with enough detail to open it",
+			Fields: []OutcomeField{
+				{Name: "claim_type", Type: FieldString, Required: true, Enum: []string{"auto", "home", "health", "life", "other"}},
+				{Name: "incident_date", Type: FieldDate, Required: true, Description: "When the loss happened"},
+				{Name: "description", Type: FieldString, Required: true, Description: "What happened, in a sentence or two"},
+				{Name: "policy_number", Type: FieldString},
+				{Name: "estimated_loss", Type: FieldNumber, Description: "The caller's estimate of the loss, in dollars"},
+				{Name: "injuries", Type: FieldBoolean, Description: "Whether anyone was hurt"},
+			},
+		},
+		{
+			Goal:        GoalAppointment,
+			Description: "The caller booked an appointment",
+			Fields: []OutcomeField{
+				{Name: "starts_at", Type: FieldDateTime, Required: true, Description: "Agreed start time, with time zone offset"},
+				{Name: "duration_minutes", Type: FieldInteger},
+				{Name: "location", Type: FieldString, Description: "Address, or phone or video"},
+				{Name: "attendee_name", Type: FieldString},
+				{Name: "purpose", Type: FieldString, Description: "What the appointment is about"},
+			},
+		},
+	}
+}
+
+// JSONSchema returns the JSON Schema of the object ExtractOutcome returns:
+// the fields plus a required "achieved" boolean
+func (s OutcomeSchema) JSONSchema() json.RawMessage {
+	properties := map[string]interface{}{
+		"achieved": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Whether the goal was achieved: " + s.Description,
+		},
+	}
+	for _, field := range s.Fields {
+		property := map[string]interface{}{"type": []string{field.Type.jsonType(), "null"}}
+		switch field.Type {
+		case FieldDate:
+			property["format"] = "date"
+		case FieldDateTime:
+			property["format"] = "date-time"
+		}
+		if field.Description != "" {
+			property["description"] = field.Description
+		}
+		if len(field.Enum) > 0 {
+			property["enum"] = field.Enum
+		}
+		properties[field.Name] = property
+	}
+
+	schema, _ := json.Marshal(map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             []string{"achieved"},
+		"additionalProperties": false,
+	})
+	return schema
+}
+
+// Prompt returns an instruction asking an LLM for the outcome as JSON
+func (s OutcomeSchema) Prompt() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The call is over. Its goal was %q: %s.\n", s.Goal, s.Description)
+	b.WriteString("Reply with only a JSON object matching this schema. Set \"achieved\" to whether the goal was met, ")
+	b.WriteString("and use null for anything the caller didn't say; don't guess.\n")
+	b.Write(s.JSONSchema())
+	return b.String()
+}
+
+// Validate checks an extracted outcome against the schema, returning it as a
+// telephony.GoalOutcome. Null values are left out, unknown keys dropped and
+// Markdown code fences around the JSON ignored; required fields are only
+// checked when the goal was achieved.
+func (s OutcomeSchema) Validate(raw json.RawMessage) (telephony.GoalOutcome, error) {
+	outcome := telephony.GoalOutcome{Goal: s.Goal}
+
+	var values map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(trimCodeFence(raw)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil || values == nil {
+		return outcome, fmt.Errorf("outcome is not a JSON object")
+	}
+
+	achieved, ok := values["achieved"].(bool)
+	if !ok {
+		return outcome, fmt.Errorf("outcome has no achieved boolean")
+	}
+	outcome.Achieved = achieved
+
+	data := make(map[string]interface{})
+	for _, field := range s.Fields {
+		value, present := values[field.Name]
+		if !present || value == nil {
+			if field.Required && achieved {
+				return outcome, fmt.Errorf("%s is required", field.Name)
+			}
+			continue
+		}
+		value, err := field.check(value)
+		if err != nil {
+			return outcome, fmt.Errorf("%s: %w", field.Name, err)
+		}
+		data[field.Name] = value
+	}
+	if len(data) > 0 {
+		outcome.Data = data
+	}
+	return outcome, nil
+}
+
+// check validates a field's decoded value, returning it in its stored form
+func (f OutcomeField) check(value interface{}) (interface{}, error) {
+	switch f.Type {
+	case FieldString, FieldDate, FieldDateTime:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("want a string, got %v", value)
+		}
+		switch f.Type {
+		case FieldDate:
+			if _, err := time.Parse(time.DateOnly, text); err != nil {
+				return nil, fmt.Errorf("want a YYYY-MM-DD date, got %q", text)
+			}
+		case FieldDateTime:
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				return nil, fmt.Errorf("want an RFC 3339 time, got %q", text)
+			}
+		}
+		if len(f.Enum) > 0 && !contains(f.Enum, text) {
+			return nil, fmt.Errorf("%q is not one of %s", text, strings.Join(f.Enum, ", "))
+		}
+		return text, nil
+
+	case FieldNumber, FieldInteger:
+		number, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("want a number, got %v", value)
+		}
+		n, err := number.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("want a number, got %s", number)
+		}
+		if f.Type == FieldInteger && n != math.Trunc(n) {
+			return nil, fmt.Errorf("want a whole number, got %s", number)
+		}
+		return n, nil
+
+	case FieldBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("want true or false, got %v", value)
+		}
+		return b, nil
+
+	default:
+		return nil, fmt.Errorf("unknown field type %q", f.Type)
+	}
+}
+
+// jsonType returns the JSON Schema type a field's values have
+func (t FieldType) jsonType() string {
+	switch t {
+	case FieldNumber, FieldInteger, FieldBoolean:
+		return string(t)
+	default:
+		return "string"
+	}
+}
+
+// trimCodeFence strips a Markdown code fence an LLM may wrap JSON in
+func trimCodeFence(raw []byte) []byte {
+	raw = bytes.TrimSpace(raw)
+	if !bytes.HasPrefix(raw, []byte("```")) {
+		return raw
+	}
+	raw = bytes.TrimSuffix(raw, []byte("```"))
+	if newline := bytes.IndexByte(raw, '\n'); newline >= 0 {
+		raw = raw[newline+1:]
+	} else {
+		raw = bytes.TrimPrefix(raw, []byte("```"))
+	}
+	return bytes.TrimSpace(raw)
+}
+
+// contains reports whether values includes value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
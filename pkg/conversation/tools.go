@@ -0,0 +1,171 @@
+package conversation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================
+// TOOLS
+// Go functions the LLM can call mid-conversation
+// ============================================
+
+// ToolHandler runs a tool, returning the result fed back to the LLM. An
+// error is reported to the LLM as the result, so it can tell the caller.
+type ToolHandler func(ctx context.Context, call ToolCall) (string, error)
+
+// Tool is a function the LLM can call, e.g. to look up a policy or book an
+// appointment
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`          // Tells the LLM when to use it
+	Parameters  json.RawMessage `json:"parameters,omitempty"` // JSON Schema of the arguments object
+	Handler     ToolHandler     `json:"-"`
+}
+
+// ToolCall is the LLM asking for a tool to be run
+type ToolCall struct {
+	ID        string          `json:"id"` // The LLM's ID for the call, echoed in its result
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+
+	// The conversation the call is made in, set for handlers
+	SessionID string `json:"-"`
+	CallSID   string `json:"-"`
+}
+
+// ToolResult is what a tool call returned
+type ToolResult struct {
+	CallID  string `json:"call_id"`
+	Name    string `json:"name"`
+	Output  string `json:"output"`
+	IsError bool   `json:"is_error,omitempty"`
+}
+
+// Response is an LLM's reply when tools are offered: either text to speak,
+// or tools to call before it replies
+type Response struct {
+	Text      string     // Spoken when there are no tool calls
+	ToolCalls []ToolCall // Run, with results added to the history, before asking again
+}
+
+// ToolLLM is an LLM that can call tools. A Manager with tools (SetTools)
+// uses ReplyWithTools instead of Reply.
+type ToolLLM interface {
+	LLM
+	// ReplyWithTools returns the agent's next reply or the tools it wants
+	// called. Earlier calls and their results are in history as agent
+	// messages with ToolCalls and RoleTool messages. With no tools
+	// offered, it must reply with text.
+	ReplyWithTools(ctx context.Context, history []Message, tools []Tool) (Response, error)
+}
+
+// ToolLLMFunc adapts a function to ToolLLM
+type ToolLLMFunc func(ctx context.Context, history []Message, tools []Tool) (Response, error)
+
+// Reply calls f without tools
+func (f ToolLLMFunc) Reply(ctx context.Context, history []Message) (string, error) {
+	response, err := f(ctx, history, nil)
+	return response.Text, err
+}
+
+// ReplyWithTools calls f
+func (f ToolLLMFunc) ReplyWithTools(ctx context.Context, history []Message, tools []Tool) (Response, error) {
+	return f(ctx, history, tools)
+}
+
+// ErrToolExists is returned by Register for a name already registered
+var ErrToolExists = errors.New("tool already registered")
+
+// Tools is a registry of tools. It is safe for concurrent use, so tools
+// can be added while conversations run.
+type Tools struct {
+	tools map[string]Tool
+	mu    sync.RWMutex
+}
+
+// NewTools creates an empty tool registry
+func NewTools() *Tools {
+	return &Tools{tools: make(map[string]Tool)}
+}
+
+// Register adds a tool
+func (t *Tools) Register(tool Tool) error {
+	if tool.Name == "" {
+		return fmt.Errorf("tool has no name")
+	}
+	if tool.Handler == nil {
+		return fmt.Errorf("tool %s has no handler", tool.Name)
+	}
+	if len(tool.Parameters) > 0 && !json.Valid(tool.Parameters) {
+		return fmt.Errorf("tool %s parameters are not valid JSON", tool.Name)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, exists := t.tools[tool.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrToolExists, tool.Name)
+	}
+	t.tools[tool.Name] = tool
+	return nil
+}
+
+// Unregister removes a tool
+func (t *Tools) Unregister(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.tools, name)
+}
+
+// List returns the registered tools ordered by name
+func (t *Tools) List() []Tool {
+	t.mu.RLock()
+	tools := make([]Tool, 0, len(t.tools))
+	for _, tool := range t.tools {
+		tools = append(tools, tool)
+	}
+	t.mu.RUnlock()
+
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// Call runs a tool call, limited to timeout when it is positive. Unknown
+// tools, handler errors and panics become error results.
+func (t *Tools) Call(ctx context.Context, call ToolCall, timeout time.Duration) (result ToolResult) {
+	result = ToolResult{CallID: call.ID, Name: call.Name}
+
+	t.mu.RLock()
+	tool, ok := t.tools[call.Name]
+	t.mu.RUnlock()
+	if !ok {
+		result.Output, result.IsError = fmt.Sprintf("unknown tool: %s", call.Name), true
+		return result
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[Conversation] Tool %s panicked: %v", call.Name, r)
+			result.Output, result.IsError = fmt.Sprintf("tool failed: %v", r), true
+		}
+	}()
+
+	output, err := tool.Handler(ctx, call)
+	if err != nil {
+		result.Output, result.IsError = err.Error(), true
+		return result
+	}
+	result.Output = output
+	return result
+}
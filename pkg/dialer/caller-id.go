@@ -0,0 +1,184 @@
+package dialer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================
+// CALLER ID THROTTLING
+// Per-number call rate limits across a pool of From numbers
+// ============================================
+
+// CallerIDLimits caps how often one From number may place calls. Carriers
+// flag DIDs that dial in bursts, so keep these well below the account's
+// overall rate.
+type CallerIDLimits struct {
+	PerSecond int // Calls per rolling second (0 = unlimited)
+	PerMinute int // Calls per rolling minute (0 = unlimited)
+}
+
+// CallerIDUsage is the recent call count of one number in a pool
+type CallerIDUsage struct {
+	Number     string `json:"number"`
+	LastSecond int    `json:"last_second"`
+	LastMinute int    `json:"last_minute"`
+}
+
+// CallerIDPool hands out From numbers within their rate limits, preferring
+// the least used number so load spreads across the pool. When every number
+// is at its limit, callers wait for the first one to free up. A pool may be
+// shared by several dialers using the same numbers.
+type CallerIDPool struct {
+	numbers []string
+	limits  CallerIDLimits
+	history map[string][]time.Time // Launch times within the last minute, oldest first
+	next    int                    // Rotation start, so ties go round-robin
+	mu      sync.Mutex
+}
+
+// NewCallerIDPool creates a pool of E.164 From numbers sharing limits
+func NewCallerIDPool(numbers []string, limits CallerIDLimits) *CallerIDPool {
+	return &CallerIDPool{
+		numbers: append([]string{}, numbers...),
+		limits:  limits,
+		history: make(map[string][]time.Time, len(numbers)),
+	}
+}
+
+// Acquire returns a number that may place a call now, waiting until one is
+// within its limits or ctx is done
+func (p *CallerIDPool) Acquire(ctx context.Context) (string, error) {
+	for {
+		number, wait := p.reserve(time.Now())
+		if number != "" {
+			return number, nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Usage returns each number's recent call counts
+func (p *CallerIDPool) Usage() []CallerIDUsage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	usage := make([]CallerIDUsage, 0, len(p.numbers))
+	for _, number := range p.numbers {
+		usage = append(usage, CallerIDUsage{
+			Number:     number,
+			LastSecond: p.countSince(number, now.Add(-time.Second)),
+			LastMinute: p.countSince(number, now.Add(-time.Minute)),
+		})
+	}
+	return usage
+}
+
+// reserve records a launch on the least used number within its limits.
+// With none available it returns how long until the first frees up.
+func (p *CallerIDPool) reserve(now time.Time) (string, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.numbers) == 0 {
+		return "", time.Second
+	}
+
+	best := ""
+	bestCount := 0
+	var wait time.Duration
+	for i := range p.numbers {
+		number := p.numbers[(p.next+i)%len(p.numbers)]
+		p.prune(number, now)
+
+		if free := p.freeAt(number, now); free.After(now) {
+			if d := free.Sub(now); wait == 0 || d < wait {
+				wait = d
+			}
+			continue
+		}
+		if count := len(p.history[number]); best == "" || count < bestCount {
+			best, bestCount = number, count
+		}
+	}
+
+	if best == "" {
+		return "", wait
+	}
+	p.history[best] = append(p.history[best], now)
+	p.next = (p.next + 1) % len(p.numbers)
+	return best, 0
+}
+
+// freeAt returns when a number may next place a call; callers hold mu
+func (p *CallerIDPool) freeAt(number string, now time.Time) time.Time {
+	history := p.history[number]
+	free := now
+
+	if limit := p.limits.PerSecond; limit > 0 {
+		if recent := p.countSince(number, now.Add(-time.Second)); recent >= limit {
+			// Free once the oldest call that would exceed the limit ages out
+			if at := history[len(history)-limit].Add(time.Second); at.After(free) {
+				free = at
+			}
+		}
+	}
+	if limit := p.limits.PerMinute; limit > 0 && len(history) >= limit {
+		if at := history[len(history)-limit].Add(time.Minute); at.After(free) {
+			free = at
+		}
+	}
+	return free
+}
+
+// countSince counts a number's launches after since; callers hold mu
+func (p *CallerIDPool) countSince(number string, since time.Time) int {
+	history := p.history[number]
+	i := sort.Search(len(history), func(i int) bool { return history[i].After(since) })
+	return len(history) - i
+}
+
+// prune drops launches older than a minute; callers hold mu
+func (p *CallerIDPool) prune(number string, now time.Time) {
+	history := p.history[number]
+	cutoff := now.Add(-time.Minute)
+	i := sort.Search(len(history), func(i int) bool { return history[i].After(cutoff) })
+	if i > 0 {
+		p.history[number] = append(history[:0], history[i:]...)
+	}
+}
+
+// CallerIDSource hands out From numbers for calls, e.g. a CallerIDPool or
+// a numbers.Pool
+type CallerIDSource interface {
+	Acquire(ctx context.Context) (string, error)
+}
+
+// SetCallerIDPool assigns each call a From number from pool, delaying
+// launches while every number is at its rate limit. The template's From is
+// used when no pool is set.
+func (d *Dialer) SetCallerIDPool(pool *CallerIDPool) {
+	if pool == nil {
+		d.SetCallerIDSource(nil)
+		return
+	}
+	d.SetCallerIDSource(pool)
+}
+
+// SetCallerIDSource assigns each call a From number from source; nil uses
+// the template's From
+func (d *Dialer) SetCallerIDSource(source CallerIDSource) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.callerIDs = source
+}
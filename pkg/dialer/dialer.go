@@ -0,0 +1,496 @@
+package dialer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// OUTBOUND DIALER
+// Paced, concurrency-limited call launching from a target list
+// ============================================
+
+// ErrQuotaExceeded is reported for targets beyond their campaign's quota
+var ErrQuotaExceeded = errors.New("campaign quota exceeded")
+
+// ErrPolicyDenied is reported for targets rejected by the dialing policy
+var ErrPolicyDenied = errors.New("call denied by policy")
+
+// Target is a number to call
+type Target struct {
+	ID         uuid.UUID              `json:"id"`
+	CampaignID uuid.UUID              `json:"campaign_id"`
+	AgencyID   uuid.UUID              `json:"agency_id,omitempty"` // Overrides the template's agency
+	Phone      string                 `json:"phone"`               // E.164
+	Locale     string                 `json:"locale,omitempty"`
+	Timezone   string                 `json:"timezone,omitempty"` // IANA zone; derived from the number when empty
+	Consent    []string               `json:"consent,omitempty"`  // Consent flags held for this number
+	Attempt    int                    `json:"attempt,omitempty"`  // Attempt number (0 or 1 = first call)
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+
+	QueueItemID uuid.UUID `json:"queue_item_id,omitempty"` // Set by CallQueue.Feed
+}
+
+// Initiator places calls and reports their state changes
+// (satisfied by *telephony.CallInitiator)
+type Initiator interface {
+	InitiateCall(ctx context.Context, config telephony.CallConfig) (*telephony.CallSession, error)
+	HangupCall(ctx context.Context, callSID string) error
+	OnStateChange(listener func(telephony.CallSummary))
+}
+
+// Policy approves each target right before it is dialed (e.g. compliance
+// rules). A non-nil error skips the target.
+type Policy interface {
+	Allow(ctx context.Context, target Target) error
+}
+
+// AnswerObserver is optionally implemented by a Policy to learn which
+// answered calls were abandoned for lack of an agent
+type AnswerObserver interface {
+	CallAnswered(target Target, callSID string, abandoned bool)
+}
+
+// AttemptObserver is optionally implemented by a Policy to learn whether a
+// target it allowed was dialed. A call counts as attempted once SignalWire
+// has accepted it; calls rejected before that (no caller ID, invalid config,
+// agency limits, API errors) are withdrawn.
+type AttemptObserver interface {
+	CallAttempted(ctx context.Context, target Target)
+	AttemptWithdrawn(ctx context.Context, target Target) // Allowed, but not dialed
+}
+
+// Config controls dialing limits
+type Config struct {
+	MaxConcurrent  int               // Calls in progress at once (default 10)
+	CallsPerSecond float64           // Launch rate (default 1)
+	CampaignQuotas map[uuid.UUID]int // Max calls per campaign (absent = unlimited)
+
+	// Dialing mode (default progressive) and predictive tuning
+	Mode           Mode
+	AgentCapacity  int     // Answered calls the AI agents can handle at once (default MaxConcurrent)
+	MaxAbandonRate float64 // Abandoned/answered cap before falling back to progressive (default 0.03)
+	MaxDialRatio   float64 // Upper bound on calls per free agent (default 3)
+
+	// Base settings for every call (From, AnswerURL, voice, ...). To, TargetID
+	// and CampaignID are filled in per target.
+	CallTemplate telephony.CallConfig
+}
+
+// Result is the outcome of launching a call for one target
+type Result struct {
+	Target    Target    `json:"target"`
+	CallSID   string    `json:"call_sid,omitempty"`
+	SessionID uuid.UUID `json:"session_id,omitempty"`
+	Err       error     `json:"-"`
+}
+
+// Stats summarizes dialer activity
+type Stats struct {
+	Placed    int64 `json:"placed"`
+	Failed    int64 `json:"failed"`
+	Skipped   int64 `json:"skipped"`
+	Completed int64 `json:"completed"`
+	Retried   int64 `json:"retried"`
+	Active    int   `json:"active"`
+
+	// Pacing
+	Answered      int64         `json:"answered"`
+	Abandoned     int64         `json:"abandoned"`
+	AnswerRate    float64       `json:"answer_rate"`
+	AbandonRate   float64       `json:"abandon_rate"`
+	AvgHandleTime time.Duration `json:"avg_handle_time"`
+	DialRatio     float64       `json:"dial_ratio"`
+	EffectiveMode Mode          `json:"effective_mode"`
+}
+
+// Dialer launches calls for targets through a CallInitiator while enforcing
+// concurrency, pacing and campaign quotas
+type Dialer struct {
+	initiator Initiator
+	config    Config
+
+	active         map[string]*activeCall // Calls in progress by call SID
+	launching      int                    // Calls being initiated
+	wake           chan struct{}          // Signalled when capacity may have freed up
+	pacing         pacingStats
+	policy         Policy
+	callerIDs      CallerIDSource
+	retryPolicy    *RetryPolicy
+	retryStore     RetryStore
+	campaignCounts map[uuid.UUID]int
+	prompts        map[uuid.UUID]CampaignPrompts
+	listeners      []func(Result)
+	stats          Stats
+	mu             sync.Mutex
+}
+
+// activeCall is a call in progress launched by the dialer
+type activeCall struct {
+	target     Target
+	launchedAt time.Time
+	answeredAt *time.Time
+}
+
+// NewDialer creates a new dialer
+func NewDialer(initiator Initiator, config Config) *Dialer {
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 10
+	}
+	if config.CallsPerSecond <= 0 {
+		config.CallsPerSecond = 1
+	}
+	if config.Mode == "" {
+		config.Mode = ModeProgressive
+	}
+	if config.AgentCapacity <= 0 {
+		config.AgentCapacity = config.MaxConcurrent
+	}
+	if config.MaxAbandonRate <= 0 {
+		config.MaxAbandonRate = 0.03
+	}
+	if config.MaxDialRatio < 1 {
+		config.MaxDialRatio = 3
+	}
+
+	d := &Dialer{
+		initiator:      initiator,
+		config:         config,
+		active:         make(map[string]*activeCall),
+		wake:           make(chan struct{}, 1),
+		campaignCounts: make(map[uuid.UUID]int),
+		prompts:        make(map[uuid.UUID]CampaignPrompts),
+	}
+
+	// Track answers and free capacity as calls end
+	initiator.OnStateChange(d.handleStateChange)
+
+	return d
+}
+
+// SetPolicy sets the policy consulted before each call
+func (d *Dialer) SetPolicy(policy Policy) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.policy = policy
+}
+
+// OnResult registers a listener for each launched, failed or skipped target
+func (d *Dialer) OnResult(listener func(Result)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.listeners = append(d.listeners, listener)
+}
+
+// Run dials targets from the channel until it is closed or ctx is done.
+// It returns once every target has been launched; calls may still be live.
+// Retries that fall due while Run is active are dialed alongside the
+// targets; later ones stay in the retry store for the next Run.
+func (d *Dialer) Run(ctx context.Context, targets <-chan Target) error {
+	interval := time.Duration(float64(time.Second) / d.config.CallsPerSecond)
+	pacer := time.NewTicker(interval)
+	defer pacer.Stop()
+
+	var launches sync.WaitGroup
+	defer launches.Wait()
+
+	retryCtx, stopRetries := context.WithCancel(ctx)
+	defer stopRetries()
+	retries := d.retryFeed(retryCtx)
+
+	for {
+		var target Target
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case target = <-retries:
+		case target, ok = <-targets:
+			if !ok {
+				return nil
+			}
+		}
+
+		if !d.reserveQuota(target.CampaignID) {
+			d.report(Result{Target: target, Err: ErrQuotaExceeded})
+			continue
+		}
+
+		// Wait for the next launch slot, then for capacity
+		select {
+		case <-ctx.Done():
+			d.refundQuota(target.CampaignID)
+			return ctx.Err()
+		case <-pacer.C:
+		}
+
+		if err := d.acquire(ctx); err != nil {
+			d.refundQuota(target.CampaignID)
+			return err
+		}
+
+		launches.Add(1)
+		go func(target Target) {
+			defer launches.Done()
+			d.place(ctx, target)
+		}(target)
+	}
+}
+
+// DialAll dials a fixed list of targets
+func (d *Dialer) DialAll(ctx context.Context, targets []Target) error {
+	queue := make(chan Target)
+	go func() {
+		defer close(queue)
+		for _, target := range targets {
+			select {
+			case queue <- target:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return d.Run(ctx, queue)
+}
+
+// Wait blocks until no calls launched by the dialer are in progress
+func (d *Dialer) Wait(ctx context.Context) error {
+	for {
+		d.mu.Lock()
+		idle := len(d.active) == 0 && d.launching == 0
+		d.mu.Unlock()
+		if idle {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-d.wake:
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// Stats returns a snapshot of dialer activity
+func (d *Dialer) Stats() Stats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := d.stats
+	stats.Active = len(d.active)
+	stats.Answered = d.pacing.answered
+	stats.Abandoned = d.pacing.abandoned
+	stats.AnswerRate = d.pacing.answerRate()
+	stats.AbandonRate = d.pacing.abandonRate()
+	stats.AvgHandleTime = d.pacing.avgHandleTime
+	stats.DialRatio = d.dialRatioLocked()
+	stats.EffectiveMode = d.effectiveModeLocked()
+	return stats
+}
+
+// acquire waits until the dialing mode allows another call
+func (d *Dialer) acquire(ctx context.Context) error {
+	for {
+		d.mu.Lock()
+		if d.canLaunchLocked() {
+			d.launching++
+			d.mu.Unlock()
+			return nil
+		}
+		d.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-d.wake:
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// signal wakes a goroutine waiting for capacity
+func (d *Dialer) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// place launches one call
+func (d *Dialer) place(ctx context.Context, target Target) {
+	d.mu.Lock()
+	policy := d.policy
+	callerIDs := d.callerIDs
+	d.mu.Unlock()
+
+	if policy != nil {
+		if err := policy.Allow(ctx, target); err != nil {
+			d.mu.Lock()
+			d.launching--
+			d.mu.Unlock()
+			d.signal()
+			d.refundQuota(target.CampaignID)
+			d.report(Result{Target: target, Err: fmt.Errorf("%w: %v", ErrPolicyDenied, err)})
+			return
+		}
+	}
+
+	observer, _ := policy.(AttemptObserver)
+
+	config := d.callConfig(target, d.campaignPromptsFor(target.CampaignID))
+	if callerIDs != nil {
+		from, err := callerIDs.Acquire(ctx)
+		if err != nil {
+			if observer != nil {
+				observer.AttemptWithdrawn(ctx, target)
+			}
+			d.mu.Lock()
+			d.launching--
+			d.mu.Unlock()
+			d.signal()
+			d.refundQuota(target.CampaignID)
+			d.report(Result{Target: target, Err: fmt.Errorf("no caller ID available: %w", err)})
+			return
+		}
+		config.From = from
+	}
+
+	session, err := d.initiator.InitiateCall(ctx, config)
+	if err != nil {
+		if observer != nil {
+			observer.AttemptWithdrawn(ctx, target)
+		}
+		d.mu.Lock()
+		d.launching--
+		d.mu.Unlock()
+		d.signal()
+		d.refundQuota(target.CampaignID)
+		log.Printf("[Dialer] Failed to call %s: %v", target.Phone, err)
+		d.report(Result{Target: target, Err: fmt.Errorf("failed to initiate call: %w", err)})
+		d.scheduleRetry(target, telephony.StateFailed)
+		return
+	}
+
+	if observer != nil {
+		observer.CallAttempted(ctx, target)
+	}
+
+	summary := session.Summary()
+
+	d.mu.Lock()
+	d.launching--
+	d.active[summary.SignalWireCallSID] = &activeCall{target: target, launchedAt: summary.InitiatedAt}
+	d.mu.Unlock()
+
+	// The call may have ended before it was registered
+	if summary := session.Summary(); summary.State.IsTerminal() {
+		d.release(summary)
+	}
+
+	d.report(Result{Target: target, CallSID: summary.SignalWireCallSID, SessionID: summary.ID})
+}
+
+// callConfig builds the call configuration for a target of a campaign with
+// the given prompts
+func (d *Dialer) callConfig(target Target, prompts CampaignPrompts) telephony.CallConfig {
+	config := d.config.CallTemplate
+	config.To = target.Phone
+	config.TargetID = target.ID
+	config.CampaignID = target.CampaignID
+	if target.AgencyID != uuid.Nil {
+		config.AgencyID = target.AgencyID
+	}
+	if target.Locale != "" {
+		config.Locale = target.Locale
+	}
+
+	// Per-target metadata on top of the template's
+	config.Metadata = make(map[string]interface{})
+	for k, v := range d.config.CallTemplate.Metadata {
+		config.Metadata[k] = v
+	}
+	for k, v := range target.Metadata {
+		config.Metadata[k] = v
+	}
+	config.Metadata["attempt"] = target.attempt()
+
+	if prompts.SystemPrompt != "" {
+		config.SystemPrompt = prompts.SystemPrompt
+	}
+	if prompts.GreetingScript != "" {
+		config.GreetingScript = prompts.GreetingScript
+	}
+	if prompts.Settings != nil {
+		config.CampaignSettings = prompts.Settings
+	}
+	return config
+}
+
+// release frees the capacity held by an ended call, records its outcome
+// and schedules a retry when the policy calls for one
+func (d *Dialer) release(summary telephony.CallSummary) {
+	d.mu.Lock()
+	call, ok := d.active[summary.SignalWireCallSID]
+	if ok {
+		delete(d.active, summary.SignalWireCallSID)
+		d.stats.Completed++
+		d.pacing.recordEnd(call, time.Now())
+	}
+	d.mu.Unlock()
+
+	if ok {
+		d.signal()
+		d.scheduleRetry(call.target, summary.State)
+	}
+}
+
+// reserveQuota counts a call against its campaign, reporting false when the
+// campaign has reached its quota
+func (d *Dialer) reserveQuota(campaignID uuid.UUID) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if quota, limited := d.config.CampaignQuotas[campaignID]; limited && d.campaignCounts[campaignID] >= quota {
+		return false
+	}
+	d.campaignCounts[campaignID]++
+	return true
+}
+
+// refundQuota returns a reserved call to its campaign
+func (d *Dialer) refundQuota(campaignID uuid.UUID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.campaignCounts[campaignID] > 0 {
+		d.campaignCounts[campaignID]--
+	}
+}
+
+// report updates stats and notifies listeners
+func (d *Dialer) report(result Result) {
+	d.mu.Lock()
+	switch {
+	case errors.Is(result.Err, ErrQuotaExceeded), errors.Is(result.Err, ErrPolicyDenied):
+		d.stats.Skipped++
+	case result.Err != nil:
+		d.stats.Failed++
+	default:
+		d.stats.Placed++
+	}
+	listeners := append([]func(Result){}, d.listeners...)
+	d.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(result)
+	}
+}
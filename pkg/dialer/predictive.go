@@ -0,0 +1,202 @@
+package dialer
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// DIALING MODES
+// Progressive and predictive pacing
+// ============================================
+
+// Mode selects how many calls are placed per available agent
+type Mode string
+
+const (
+	// ModeProgressive places one call per free agent
+	ModeProgressive Mode = "progressive"
+	// ModePredictive over-dials based on answer rate and handle time
+	ModePredictive Mode = "predictive"
+)
+
+const (
+	pacingWindow     = 100 // Recent call outcomes used for the answer rate
+	pacingMinSamples = 20  // Outcomes needed before over-dialing
+	pacingSmoothing  = 0.2 // Weight of the newest sample in moving averages
+)
+
+// pacingStats tracks call outcomes that drive predictive dialing
+type pacingStats struct {
+	outcomes  []bool // Recent calls: answered or not (ring buffer)
+	next      int
+	answered  int64
+	abandoned int64
+
+	avgHandleTime time.Duration // Answer → hangup
+	avgRingTime   time.Duration // Launch → answer
+}
+
+// record adds a call outcome to the window
+func (p *pacingStats) record(answered bool) {
+	if len(p.outcomes) < pacingWindow {
+		p.outcomes = append(p.outcomes, answered)
+		return
+	}
+	p.outcomes[p.next] = answered
+	p.next = (p.next + 1) % pacingWindow
+}
+
+// recordEnd records a finished call
+func (p *pacingStats) recordEnd(call *activeCall, endedAt time.Time) {
+	p.record(call.answeredAt != nil)
+	if call.answeredAt != nil {
+		p.avgHandleTime = smooth(p.avgHandleTime, endedAt.Sub(*call.answeredAt))
+	}
+}
+
+// answerRate returns the share of recent calls that were answered
+func (p *pacingStats) answerRate() float64 {
+	if len(p.outcomes) == 0 {
+		return 0
+	}
+	answered := 0
+	for _, ok := range p.outcomes {
+		if ok {
+			answered++
+		}
+	}
+	return float64(answered) / float64(len(p.outcomes))
+}
+
+// abandonRate returns abandoned calls as a share of answered calls
+func (p *pacingStats) abandonRate() float64 {
+	if p.answered == 0 {
+		return 0
+	}
+	return float64(p.abandoned) / float64(p.answered)
+}
+
+// smooth folds a sample into an exponential moving average
+func smooth(avg, sample time.Duration) time.Duration {
+	if avg == 0 {
+		return sample
+	}
+	return time.Duration(pacingSmoothing*float64(sample) + (1-pacingSmoothing)*float64(avg))
+}
+
+// handleStateChange tracks answers and call ends for calls this dialer placed
+func (d *Dialer) handleStateChange(summary telephony.CallSummary) {
+	switch {
+	case summary.State.IsTerminal():
+		d.release(summary)
+
+	case summary.State == telephony.StateAnswered || summary.State == telephony.StateInProgress:
+		d.mu.Lock()
+		call, ok := d.active[summary.SignalWireCallSID]
+		if !ok || call.answeredAt != nil {
+			d.mu.Unlock()
+			return
+		}
+
+		now := time.Now()
+		call.answeredAt = &now
+		d.pacing.answered++
+		d.pacing.avgRingTime = smooth(d.pacing.avgRingTime, now.Sub(call.launchedAt))
+
+		// An answer with no free agent is abandoned
+		abandon := d.answeredLocked() > d.config.AgentCapacity
+		if abandon {
+			d.pacing.abandoned++
+		}
+		observer, _ := d.policy.(AnswerObserver)
+		target := call.target
+		d.mu.Unlock()
+
+		if observer != nil {
+			observer.CallAnswered(target, summary.SignalWireCallSID, abandon)
+		}
+
+		if abandon {
+			log.Printf("[Dialer] No agent free for answered call %s, abandoning", summary.SignalWireCallSID)
+			if err := d.initiator.HangupCall(context.Background(), summary.SignalWireCallSID); err != nil {
+				log.Printf("[Dialer] Failed to hang up abandoned call %s: %v", summary.SignalWireCallSID, err)
+			}
+		}
+	}
+}
+
+// answeredLocked counts calls in progress that were answered
+func (d *Dialer) answeredLocked() int {
+	count := 0
+	for _, call := range d.active {
+		if call.answeredAt != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// effectiveModeLocked returns the mode currently in force. Predictive
+// dialing falls back to progressive while warming up, once the abandonment
+// cap is reached, or when every agent is busy.
+func (d *Dialer) effectiveModeLocked() Mode {
+	if d.config.Mode != ModePredictive {
+		return ModeProgressive
+	}
+	if len(d.pacing.outcomes) < pacingMinSamples {
+		return ModeProgressive
+	}
+	if d.pacing.abandonRate() >= d.config.MaxAbandonRate {
+		return ModeProgressive
+	}
+	if d.answeredLocked() >= d.config.AgentCapacity {
+		return ModeProgressive
+	}
+	return ModePredictive
+}
+
+// dialRatioLocked returns the calls placed per free agent
+func (d *Dialer) dialRatioLocked() float64 {
+	if d.effectiveModeLocked() != ModePredictive {
+		return 1
+	}
+
+	rate := d.pacing.answerRate()
+	if rate <= 0 {
+		return d.config.MaxDialRatio
+	}
+
+	// Ease off as abandonment approaches the cap
+	headroom := 1 - d.pacing.abandonRate()/d.config.MaxAbandonRate
+	ratio := 1 + (1/rate-1)*headroom
+
+	return math.Min(ratio, d.config.MaxDialRatio)
+}
+
+// canLaunchLocked reports whether another call may be placed now
+func (d *Dialer) canLaunchLocked() bool {
+	inFlight := len(d.active) + d.launching
+	if inFlight >= d.config.MaxConcurrent {
+		return false
+	}
+
+	answered := d.answeredLocked()
+	ringing := inFlight - answered
+	freeAgents := float64(d.config.AgentCapacity - answered)
+
+	if d.effectiveModeLocked() == ModePredictive && d.pacing.avgHandleTime > 0 {
+		// Agents likely to free up before a new call would be answered
+		finishing := float64(answered) * math.Min(1, float64(d.pacing.avgRingTime)/float64(d.pacing.avgHandleTime))
+		freeAgents += finishing
+	}
+	if freeAgents <= 0 {
+		return false
+	}
+
+	return float64(ringing) < math.Ceil(freeAgents*d.dialRatioLocked())
+}
@@ -0,0 +1,58 @@
+package dialer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// CAMPAIGN PROMPTS
+// Per-campaign prompt templates and the settings they use
+// ============================================
+
+// maxPromptErrors caps the failing targets ValidatePrompts reports
+const maxPromptErrors = 5
+
+// CampaignPrompts are a campaign's prompt templates and the settings they
+// can refer to as {{.Campaign.<key>}} (see telephony.PromptData)
+type CampaignPrompts struct {
+	SystemPrompt   string                 // Replaces the call template's, if set
+	GreetingScript string                 // Replaces the call template's, if set
+	Settings       map[string]interface{} // Campaign variables
+}
+
+// SetCampaignPrompts sets the prompts for a campaign's calls. Check them
+// first with ValidatePrompts.
+func (d *Dialer) SetCampaignPrompts(campaignID uuid.UUID, prompts CampaignPrompts) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.prompts[campaignID] = prompts
+}
+
+// ValidatePrompts renders the prompt templates each target's call would get
+// with prompts, reporting the targets whose templates fail (e.g. a
+// variable missing from their metadata)
+func (d *Dialer) ValidatePrompts(prompts CampaignPrompts, targets []Target) error {
+	var errs []error
+	for _, target := range targets {
+		if err := telephony.ValidatePrompts(d.callConfig(target, prompts)); err != nil {
+			errs = append(errs, fmt.Errorf("target %s: %w", target.Phone, err))
+			if len(errs) == maxPromptErrors {
+				errs = append(errs, fmt.Errorf("(further targets not checked)"))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// campaignPromptsFor returns a campaign's prompts
+func (d *Dialer) campaignPromptsFor(campaignID uuid.UUID) CampaignPrompts {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.prompts[campaignID]
+}
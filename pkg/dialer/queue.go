@@ -0,0 +1,559 @@
+package dialer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============================================
+// PRIORITY CALL QUEUE
+// Buffered outbound calls with priorities, delays and dead-lettering
+// ============================================
+
+// Priority orders queued calls; higher values are dialed first
+type Priority int
+
+const (
+	PriorityLow    Priority = 0
+	PriorityNormal Priority = 10
+	PriorityHigh   Priority = 20
+	PriorityUrgent Priority = 30
+)
+
+// QueueLease is how long a popped item is reserved for the dialer
+// launching it. Feed renews the lease while the item waits for the dialer;
+// items not settled within it (e.g. after a crash) are popped again.
+const QueueLease = 5 * time.Minute
+
+// QueueItem is a target waiting in the call queue
+type QueueItem struct {
+	ID         uuid.UUID `json:"id"`
+	Target     Target    `json:"target"`
+	Priority   Priority  `json:"priority"`
+	NotBefore  time.Time `json:"not_before"` // Earliest time the call may be placed
+	Failures   int       `json:"failures"`
+	LastError  string    `json:"last_error,omitempty"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+}
+
+// QueueStore persists queued and dead-lettered calls
+type QueueStore interface {
+	// Push adds or replaces an item, releasing any claim on it
+	Push(ctx context.Context, item QueueItem) error
+	// Pop claims and returns the highest-priority item due at now that is
+	// unclaimed (or whose lease has run out), or nil
+	Pop(ctx context.Context, now time.Time) (*QueueItem, error)
+	// Extend renews the lease on claimed items from now
+	Extend(ctx context.Context, ids []uuid.UUID, now time.Time) error
+	// Complete removes a claimed item once its launch is settled
+	Complete(ctx context.Context, id uuid.UUID) error
+	DeadLetter(ctx context.Context, item QueueItem) error
+	DeadLetters(ctx context.Context) ([]QueueItem, error)
+	Len(ctx context.Context) (int, error)
+}
+
+// CallQueue feeds queued targets to a Dialer in priority order and
+// dead-letters targets that keep failing to launch
+type CallQueue struct {
+	store        QueueStore
+	maxFailures  int
+	backoff      time.Duration
+	pollInterval time.Duration
+
+	inFlight map[uuid.UUID]QueueItem // Items handed to the dialer, by item ID
+	notify   chan struct{}
+	mu       sync.Mutex
+}
+
+// NewCallQueue creates a call queue. Targets are dead-lettered after
+// maxFailures failed launches (default 3).
+func NewCallQueue(store QueueStore, maxFailures int) *CallQueue {
+	if store == nil {
+		store = NewMemoryQueueStore()
+	}
+	if maxFailures <= 0 {
+		maxFailures = 3
+	}
+	return &CallQueue{
+		store:        store,
+		maxFailures:  maxFailures,
+		backoff:      time.Minute,
+		pollInterval: time.Second,
+		inFlight:     make(map[uuid.UUID]QueueItem),
+		notify:       make(chan struct{}, 1),
+	}
+}
+
+// SetBackoff sets the delay before a failed launch is retried (doubled per
+// failure)
+func (q *CallQueue) SetBackoff(backoff time.Duration) {
+	q.backoff = backoff
+}
+
+// SetPollInterval sets how often delayed items are checked
+func (q *CallQueue) SetPollInterval(interval time.Duration) {
+	q.pollInterval = interval
+}
+
+// Enqueue adds a target; a zero notBefore means as soon as possible
+func (q *CallQueue) Enqueue(ctx context.Context, target Target, priority Priority, notBefore time.Time) (*QueueItem, error) {
+	if target.ID == uuid.Nil {
+		target.ID = uuid.New()
+	}
+
+	now := time.Now()
+	if notBefore.IsZero() {
+		notBefore = now
+	}
+
+	item := QueueItem{
+		ID:         uuid.New(),
+		Target:     target,
+		Priority:   priority,
+		NotBefore:  notBefore,
+		EnqueuedAt: now,
+	}
+	if err := q.store.Push(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to enqueue %s: %w", target.Phone, err)
+	}
+
+	q.wake()
+	return &item, nil
+}
+
+// Len returns the number of queued items
+func (q *CallQueue) Len(ctx context.Context) (int, error) {
+	return q.store.Len(ctx)
+}
+
+// DeadLetters returns targets that exhausted their launch attempts
+func (q *CallQueue) DeadLetters(ctx context.Context) ([]QueueItem, error) {
+	return q.store.DeadLetters(ctx)
+}
+
+// Attach tracks the dialer's results so failed launches are requeued or
+// dead-lettered
+func (q *CallQueue) Attach(d *Dialer) {
+	d.OnResult(q.handleResult)
+}
+
+// Feed returns a channel of due targets in priority order for Dialer.Run.
+// It is closed when ctx is done. Leases on items handed out are renewed
+// until the dialer reports on them.
+func (q *CallQueue) Feed(ctx context.Context) <-chan Target {
+	feed := make(chan Target)
+	go q.renewLeases(ctx)
+	go func() {
+		defer close(feed)
+
+		ticker := time.NewTicker(q.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			item, err := q.store.Pop(ctx, time.Now())
+			if err != nil && ctx.Err() == nil {
+				log.Printf("[CallQueue] Failed to pop queue: %v", err)
+			}
+
+			if item == nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-q.notify:
+				case <-ticker.C:
+				}
+				continue
+			}
+
+			q.mu.Lock()
+			q.inFlight[item.ID] = *item
+			q.mu.Unlock()
+
+			target := item.Target
+			target.QueueItemID = item.ID
+			select {
+			case feed <- target:
+			case <-ctx.Done():
+				q.mu.Lock()
+				delete(q.inFlight, item.ID)
+				q.mu.Unlock()
+				if err := q.store.Push(context.Background(), *item); err != nil {
+					log.Printf("[CallQueue] Failed to requeue %s: %v", item.Target.Phone, err)
+				}
+				return
+			}
+		}
+	}()
+	return feed
+}
+
+// renewLeases extends the leases of in-flight items until ctx is done, so
+// items waiting on the dialer's pacing or capacity aren't popped again
+func (q *CallQueue) renewLeases(ctx context.Context) {
+	ticker := time.NewTicker(QueueLease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		q.mu.Lock()
+		ids := make([]uuid.UUID, 0, len(q.inFlight))
+		for id := range q.inFlight {
+			ids = append(ids, id)
+		}
+		q.mu.Unlock()
+		if len(ids) == 0 {
+			continue
+		}
+
+		if err := q.store.Extend(ctx, ids, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("[CallQueue] Failed to renew %d leases: %v", len(ids), err)
+		}
+	}
+}
+
+// handleResult settles an in-flight item once the dialer reports on it.
+// Items stay claimed in the store until then, so a crash mid-launch only
+// delays them by QueueLease.
+func (q *CallQueue) handleResult(result Result) {
+	if result.Target.QueueItemID == uuid.Nil {
+		return
+	}
+
+	q.mu.Lock()
+	item, ok := q.inFlight[result.Target.QueueItemID]
+	delete(q.inFlight, result.Target.QueueItemID)
+	q.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+
+	// Skipped targets (quota, policy) aren't launch failures
+	if result.Err == nil || errors.Is(result.Err, ErrQuotaExceeded) || errors.Is(result.Err, ErrPolicyDenied) {
+		if err := q.store.Complete(ctx, item.ID); err != nil {
+			log.Printf("[CallQueue] Failed to complete %s: %v", item.Target.Phone, err)
+		}
+		return
+	}
+
+	item.Failures++
+	item.LastError = result.Err.Error()
+
+	if item.Failures >= q.maxFailures {
+		log.Printf("[CallQueue] Dead-lettering %s after %d failures: %v", item.Target.Phone, item.Failures, result.Err)
+		if err := q.store.DeadLetter(ctx, item); err != nil {
+			log.Printf("[CallQueue] Failed to dead-letter %s: %v", item.Target.Phone, err)
+		}
+		return
+	}
+
+	item.NotBefore = time.Now().Add(q.backoff << (item.Failures - 1))
+	if err := q.store.Push(ctx, item); err != nil {
+		log.Printf("[CallQueue] Failed to requeue %s: %v", item.Target.Phone, err)
+	}
+}
+
+// wake nudges the feed after an enqueue
+func (q *CallQueue) wake() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// ============================================
+// IN-MEMORY QUEUE STORE
+// ============================================
+
+// MemoryQueueStore keeps the queue in memory (lost on restart)
+type MemoryQueueStore struct {
+	items   []QueueItem
+	dead    []QueueItem
+	claimed map[uuid.UUID]time.Time // Lease start of claimed items
+	mu      sync.Mutex
+}
+
+// NewMemoryQueueStore creates an in-memory queue store
+func NewMemoryQueueStore() *MemoryQueueStore {
+	return &MemoryQueueStore{claimed: make(map[uuid.UUID]time.Time)}
+}
+
+// Push adds or replaces an item and releases its claim
+func (s *MemoryQueueStore) Push(ctx context.Context, item QueueItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claimed, item.ID)
+	if i := s.index(item.ID); i >= 0 {
+		s.items[i] = item
+		return nil
+	}
+	s.items = append(s.items, item)
+	return nil
+}
+
+// Pop returns the highest-priority due item. Delayed items don't block
+// lower-priority items that are already due.
+func (s *MemoryQueueStore) Pop(ctx context.Context, now time.Time) (*QueueItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	best := -1
+	for i, item := range s.items {
+		if item.NotBefore.After(now) {
+			continue
+		}
+		if claimedAt, ok := s.claimed[item.ID]; ok && now.Sub(claimedAt) < QueueLease {
+			continue
+		}
+		if best < 0 || queuedBefore(item, s.items[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil, nil
+	}
+
+	item := s.items[best]
+	s.claimed[item.ID] = now
+	return &item, nil
+}
+
+// Extend renews the lease on claimed items
+func (s *MemoryQueueStore) Extend(ctx context.Context, ids []uuid.UUID, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := s.claimed[id]; ok {
+			s.claimed[id] = now
+		}
+	}
+	return nil
+}
+
+// Complete removes a settled item
+func (s *MemoryQueueStore) Complete(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(id)
+	return nil
+}
+
+// DeadLetter moves an item that won't be retried out of the queue
+func (s *MemoryQueueStore) DeadLetter(ctx context.Context, item QueueItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(item.ID)
+	s.dead = append(s.dead, item)
+	return nil
+}
+
+// index returns the position of a queued item, or -1. Caller must hold s.mu.
+func (s *MemoryQueueStore) index(id uuid.UUID) int {
+	for i, item := range s.items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// remove drops a queued item and its claim. Caller must hold s.mu.
+func (s *MemoryQueueStore) remove(id uuid.UUID) {
+	delete(s.claimed, id)
+	if i := s.index(id); i >= 0 {
+		s.items = append(s.items[:i], s.items[i+1:]...)
+	}
+}
+
+// DeadLetters returns dead-lettered items, oldest first
+func (s *MemoryQueueStore) DeadLetters(ctx context.Context) ([]QueueItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]QueueItem(nil), s.dead...), nil
+}
+
+// Len returns the number of queued items
+func (s *MemoryQueueStore) Len(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items), nil
+}
+
+// queuedBefore orders items by priority, then due time, then enqueue time
+func queuedBefore(a, b QueueItem) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if !a.NotBefore.Equal(b.NotBefore) {
+		return a.NotBefore.Before(b.NotBefore)
+	}
+	return a.EnqueuedAt.Before(b.EnqueuedAt)
+}
+
+// ============================================
+// POSTGRES QUEUE STORE
+// ============================================
+
+// PostgresQueueStore keeps the queue in the dialer_queue table,
+// created by telephony.Migrate
+type PostgresQueueStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresQueueStore creates a queue store backed by Postgres
+func NewPostgresQueueStore(db *pgxpool.Pool) *PostgresQueueStore {
+	return &PostgresQueueStore{db: db}
+}
+
+// Push upserts an item as queued and releases its claim
+func (s *PostgresQueueStore) Push(ctx context.Context, item QueueItem) error {
+	return s.save(ctx, item, false)
+}
+
+// DeadLetter marks an item as dead
+func (s *PostgresQueueStore) DeadLetter(ctx context.Context, item QueueItem) error {
+	return s.save(ctx, item, true)
+}
+
+// save upserts an item
+func (s *PostgresQueueStore) save(ctx context.Context, item QueueItem, dead bool) error {
+	target, err := json.Marshal(item.Target)
+	if err != nil {
+		return fmt.Errorf("failed to marshal target: %w", err)
+	}
+
+	query := `
+		INSERT INTO dialer_queue (id, target, priority, not_before, failures, last_error, enqueued_at, dead)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			target = EXCLUDED.target,
+			priority = EXCLUDED.priority,
+			not_before = EXCLUDED.not_before,
+			failures = EXCLUDED.failures,
+			last_error = EXCLUDED.last_error,
+			dead = EXCLUDED.dead,
+			claimed_at = NULL
+	`
+
+	_, err = s.db.Exec(ctx, query,
+		item.ID, target, int(item.Priority), item.NotBefore,
+		item.Failures, item.LastError, item.EnqueuedAt, dead,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save queue item: %w", err)
+	}
+	return nil
+}
+
+// Pop claims and returns the highest-priority due item. The row stays until
+// Complete, Push or DeadLetter settles it; SKIP LOCKED lets several dialers
+// share one queue.
+func (s *PostgresQueueStore) Pop(ctx context.Context, now time.Time) (*QueueItem, error) {
+	query := `
+		UPDATE dialer_queue SET claimed_at = $1
+		WHERE id = (
+			SELECT id FROM dialer_queue
+			WHERE NOT dead AND not_before <= $1
+			  AND (claimed_at IS NULL OR claimed_at < $2)
+			ORDER BY priority DESC, not_before, enqueued_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, target, priority, not_before, failures, COALESCE(last_error, ''), enqueued_at
+	`
+
+	item, err := scanQueueItem(s.db.QueryRow(ctx, query, now, now.Add(-QueueLease)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pop queue: %w", err)
+	}
+	return item, nil
+}
+
+// Extend renews the lease on claimed items
+func (s *PostgresQueueStore) Extend(ctx context.Context, ids []uuid.UUID, now time.Time) error {
+	query := `
+		UPDATE dialer_queue SET claimed_at = $2
+		WHERE id = ANY($1) AND claimed_at IS NOT NULL AND NOT dead
+	`
+
+	if _, err := s.db.Exec(ctx, query, ids, now); err != nil {
+		return fmt.Errorf("failed to extend queue leases: %w", err)
+	}
+	return nil
+}
+
+// Complete deletes a settled item
+func (s *PostgresQueueStore) Complete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM dialer_queue WHERE id = $1 AND NOT dead`, id); err != nil {
+		return fmt.Errorf("failed to complete queue item: %w", err)
+	}
+	return nil
+}
+
+// DeadLetters returns dead-lettered items, oldest first
+func (s *PostgresQueueStore) DeadLetters(ctx context.Context) ([]QueueItem, error) {
+	query := `
+		SELECT id, target, priority, not_before, failures, COALESCE(last_error, ''), enqueued_at
+		FROM dialer_queue
+		WHERE dead
+		ORDER BY enqueued_at
+	`
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var items []QueueItem
+	for rows.Next() {
+		item, err := scanQueueItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dead letter: %w", err)
+		}
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+// Len returns the number of queued items
+func (s *PostgresQueueStore) Len(ctx context.Context) (int, error) {
+	var count int
+	if err := s.db.QueryRow(ctx, `SELECT COUNT(*) FROM dialer_queue WHERE NOT dead`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count queue: %w", err)
+	}
+	return count, nil
+}
+
+// scanQueueItem reads one queue row
+func scanQueueItem(row pgx.Row) (*QueueItem, error) {
+	var item QueueItem
+	var target []byte
+	var priority int
+	if err := row.Scan(&item.ID, &target, &priority, &item.NotBefore, &item.Failures, &item.LastError, &item.EnqueuedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(target, &item.Target); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal target: %w", err)
+	}
+	item.Priority = Priority(priority)
+	return &item, nil
+}
@@ -0,0 +1,300 @@
+package dialer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// RETRY POLICIES
+// Re-dialing unanswered calls on a per-outcome schedule
+// ============================================
+
+// RetryRule is the schedule for one call outcome
+type RetryRule struct {
+	Delay      time.Duration // Wait before the first retry
+	Multiplier float64       // Growth of the delay per further attempt (default 1 = fixed)
+	MaxDelay   time.Duration // Cap on the delay (0 = none)
+}
+
+// RetryPolicy decides which ended calls are dialed again and when
+type RetryPolicy struct {
+	Rules        map[telephony.CallState]RetryRule // Outcomes that are retried
+	MaxAttempts  int                               // Total attempts per target, including the first (default 3)
+	PollInterval time.Duration                     // How often due retries are checked (default 30s)
+}
+
+// DefaultRetryPolicy retries busy lines after 15 minutes, unanswered calls
+// after 4 hours and failed calls after 30 minutes, up to 3 attempts
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Rules: map[telephony.CallState]RetryRule{
+			telephony.StateBusy:     {Delay: 15 * time.Minute, Multiplier: 2},
+			telephony.StateNoAnswer: {Delay: 4 * time.Hour},
+			telephony.StateFailed:   {Delay: 30 * time.Minute, Multiplier: 2},
+		},
+		MaxAttempts: 3,
+	}
+}
+
+// delay returns the wait before the given attempt (2 = first retry)
+func (r RetryRule) delay(attempt int) time.Duration {
+	multiplier := r.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := time.Duration(float64(r.Delay) * math.Pow(multiplier, float64(attempt-2)))
+	if r.MaxDelay > 0 && delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	return delay
+}
+
+// PendingRetry is a target waiting to be dialed again
+type PendingRetry struct {
+	Target  Target              `json:"target"`  // Target.Attempt is the attempt to be made
+	Outcome telephony.CallState `json:"outcome"` // How the previous attempt ended
+	DueAt   time.Time           `json:"due_at"`
+}
+
+// RetryStore persists pending retries so they survive restarts
+type RetryStore interface {
+	Save(ctx context.Context, retry PendingRetry) error
+	// ClaimDue removes and returns every retry due at or before now
+	ClaimDue(ctx context.Context, now time.Time) ([]PendingRetry, error)
+}
+
+// SetRetryPolicy enables retries; pending retries are kept in store
+func (d *Dialer) SetRetryPolicy(policy RetryPolicy, store RetryStore) {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 3
+	}
+	if policy.PollInterval <= 0 {
+		policy.PollInterval = 30 * time.Second
+	}
+	if store == nil {
+		store = NewMemoryRetryStore()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.retryPolicy = &policy
+	d.retryStore = store
+}
+
+// scheduleRetry queues another attempt for a target whose call ended with
+// a retryable outcome
+func (d *Dialer) scheduleRetry(target Target, outcome telephony.CallState) {
+	d.mu.Lock()
+	policy, store := d.retryPolicy, d.retryStore
+	d.mu.Unlock()
+	if policy == nil {
+		return
+	}
+
+	rule, ok := policy.Rules[outcome]
+	if !ok {
+		return
+	}
+
+	attempt := target.attempt()
+	if attempt >= policy.MaxAttempts {
+		log.Printf("[Dialer] Giving up on %s after %d attempts (last: %s)", target.Phone, attempt, outcome)
+		return
+	}
+
+	target.Attempt = attempt + 1
+	target.QueueItemID = uuid.Nil // The retry doesn't settle a queue item
+	retry := PendingRetry{
+		Target:  target,
+		Outcome: outcome,
+		DueAt:   time.Now().Add(rule.delay(target.Attempt)),
+	}
+	if err := store.Save(context.Background(), retry); err != nil {
+		log.Printf("[Dialer] Failed to save retry for %s: %v", target.Phone, err)
+		return
+	}
+
+	d.mu.Lock()
+	d.stats.Retried++
+	d.mu.Unlock()
+
+	log.Printf("[Dialer] Retrying %s (%s) at %s, attempt %d/%d",
+		target.Phone, outcome, retry.DueAt.Format(time.RFC3339), target.Attempt, policy.MaxAttempts)
+}
+
+// retryFeed delivers due retries while ctx is live. Retries claimed but not
+// delivered are saved back to the store.
+func (d *Dialer) retryFeed(ctx context.Context) <-chan Target {
+	d.mu.Lock()
+	policy, store := d.retryPolicy, d.retryStore
+	d.mu.Unlock()
+	if policy == nil {
+		return nil
+	}
+
+	feed := make(chan Target)
+	go func() {
+		ticker := time.NewTicker(policy.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			due, err := store.ClaimDue(ctx, time.Now())
+			if err != nil && ctx.Err() == nil {
+				log.Printf("[Dialer] Failed to load due retries: %v", err)
+			}
+
+			for i, retry := range due {
+				select {
+				case feed <- retry.Target:
+				case <-ctx.Done():
+					for _, unsent := range due[i:] {
+						if err := store.Save(context.Background(), unsent); err != nil {
+							log.Printf("[Dialer] Failed to requeue retry for %s: %v", unsent.Target.Phone, err)
+						}
+					}
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return feed
+}
+
+// attempt returns the target's attempt number (the first call is 1)
+func (t Target) attempt() int {
+	if t.Attempt < 1 {
+		return 1
+	}
+	return t.Attempt
+}
+
+// ============================================
+// IN-MEMORY RETRY STORE
+// ============================================
+
+// MemoryRetryStore keeps pending retries in memory (lost on restart)
+type MemoryRetryStore struct {
+	retries map[uuid.UUID]PendingRetry
+	mu      sync.Mutex
+}
+
+// NewMemoryRetryStore creates an in-memory retry store
+func NewMemoryRetryStore() *MemoryRetryStore {
+	return &MemoryRetryStore{retries: make(map[uuid.UUID]PendingRetry)}
+}
+
+// Save stores a retry, replacing any pending retry for the same target
+func (s *MemoryRetryStore) Save(ctx context.Context, retry PendingRetry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries[retry.Target.ID] = retry
+	return nil
+}
+
+// ClaimDue removes and returns due retries, earliest first
+func (s *MemoryRetryStore) ClaimDue(ctx context.Context, now time.Time) ([]PendingRetry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []PendingRetry
+	for id, retry := range s.retries {
+		if !retry.DueAt.After(now) {
+			due = append(due, retry)
+			delete(s.retries, id)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].DueAt.Before(due[j].DueAt) })
+	return due, nil
+}
+
+// ============================================
+// POSTGRES RETRY STORE
+// ============================================
+
+// PostgresRetryStore keeps pending retries in the dialer_retries table,
+// created by telephony.Migrate
+type PostgresRetryStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresRetryStore creates a retry store backed by Postgres
+func NewPostgresRetryStore(db *pgxpool.Pool) *PostgresRetryStore {
+	return &PostgresRetryStore{db: db}
+}
+
+// Save upserts a retry for its target
+func (s *PostgresRetryStore) Save(ctx context.Context, retry PendingRetry) error {
+	target, err := json.Marshal(retry.Target)
+	if err != nil {
+		return fmt.Errorf("failed to marshal target: %w", err)
+	}
+
+	query := `
+		INSERT INTO dialer_retries (target_id, target, outcome, due_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (target_id) DO UPDATE SET
+			target = EXCLUDED.target,
+			outcome = EXCLUDED.outcome,
+			due_at = EXCLUDED.due_at
+	`
+
+	if _, err := s.db.Exec(ctx, query, retry.Target.ID, target, string(retry.Outcome), retry.DueAt); err != nil {
+		return fmt.Errorf("failed to save retry: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue deletes and returns due retries in one statement, so concurrent
+// dialers never claim the same retry
+func (s *PostgresRetryStore) ClaimDue(ctx context.Context, now time.Time) ([]PendingRetry, error) {
+	query := `
+		DELETE FROM dialer_retries
+		WHERE due_at <= $1
+		RETURNING target, outcome, due_at
+	`
+
+	rows, err := s.db.Query(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim retries: %w", err)
+	}
+	defer rows.Close()
+
+	var due []PendingRetry
+	for rows.Next() {
+		var retry PendingRetry
+		var target []byte
+		var outcome string
+		if err := rows.Scan(&target, &outcome, &retry.DueAt); err != nil {
+			return nil, fmt.Errorf("failed to scan retry: %w", err)
+		}
+		if err := json.Unmarshal(target, &retry.Target); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal target: %w", err)
+		}
+		retry.Outcome = telephony.CallState(outcome)
+		due = append(due, retry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read retries: %w", err)
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].DueAt.Before(due[j].DueAt) })
+	return due, nil
+}
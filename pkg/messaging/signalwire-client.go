@@ -0,0 +1,60 @@
+package messaging
+
+import (
+	"errors"
+
+	"github.com/birddigital/signalwire-telephony/pkg/signalwire"
+)
+
+// SignalWireAdapter adapts a signalwire.Client to the messaging interfaces
+type SignalWireAdapter struct {
+	client *signalwire.Client
+}
+
+// NewSignalWireAdapter creates a new adapter for a SignalWire client
+func NewSignalWireAdapter(client *signalwire.Client) *SignalWireAdapter {
+	return &SignalWireAdapter{client: client}
+}
+
+// SendSMS sends a single message
+func (a *SignalWireAdapter) SendSMS(from, to, message string) (*SMSMessage, error) {
+	msg, err := a.client.SendSMS(from, to, message)
+	if err != nil {
+		return nil, err
+	}
+	return toSMSMessage(msg), nil
+}
+
+// SendBulkSMS sends a message to many recipients in one request
+func (a *SignalWireAdapter) SendBulkSMS(from string, recipients []string, message string) ([]BulkResult, error) {
+	results, err := a.client.SendBulkSMS(from, recipients, message)
+	if errors.Is(err, signalwire.ErrBulkNotSupported) {
+		return nil, ErrBulkUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]BulkResult, 0, len(results))
+	for _, result := range results {
+		if result.Error != "" {
+			converted = append(converted, BulkResult{To: result.To, Err: errors.New(result.Error)})
+			continue
+		}
+		converted = append(converted, BulkResult{To: result.To, Message: toSMSMessage(result.Message)})
+	}
+	return converted, nil
+}
+
+// toSMSMessage converts a SignalWire message
+func toSMSMessage(msg *signalwire.Message) *SMSMessage {
+	return &SMSMessage{
+		SID:       msg.SID,
+		From:      msg.From,
+		To:        msg.To,
+		Body:      msg.Body,
+		Status:    msg.Status,
+		Direction: msg.Direction,
+		Price:     msg.Price,
+	}
+}
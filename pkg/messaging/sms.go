@@ -1,12 +1,24 @@
 package messaging
 
 import (
+	"errors"
 	"fmt"
+	"log"
 )
 
+// DefaultBatchSize is the number of recipients sent per bulk request
+const DefaultBatchSize = 1000
+
+// MaxBatchSize is the most recipients SignalWire accepts in one bulk request
+const MaxBatchSize = 1000
+
+// ErrBulkUnsupported is returned by a BulkSMSClient that cannot batch sends
+var ErrBulkUnsupported = errors.New("bulk SMS not supported")
+
 // MessageService handles SMS messaging operations
 type MessageService struct {
 	signalwireClient SignalWireClientInterface
+	batchSize        int
 }
 
 // SignalWireClientInterface defines the interface for SignalWire client
@@ -14,6 +26,19 @@ type SignalWireClientInterface interface {
 	SendSMS(from, to, message string) (*SMSMessage, error)
 }
 
+// BulkSMSClient is implemented by clients that can send one message to many
+// recipients in a single request
+type BulkSMSClient interface {
+	SendBulkSMS(from string, recipients []string, message string) ([]BulkResult, error)
+}
+
+// BulkResult is the outcome for one recipient of a bulk send
+type BulkResult struct {
+	To      string
+	Message *SMSMessage
+	Err     error
+}
+
 // SMSMessage represents an SMS message
 type SMSMessage struct {
 	SID       string `json:"sid"`
@@ -29,11 +54,66 @@ type SMSMessage struct {
 func NewMessageService(client SignalWireClientInterface) *MessageService {
 	return &MessageService{
 		signalwireClient: client,
+		batchSize:        DefaultBatchSize,
 	}
 }
 
-// SendBroadcast sends a message to multiple recipients
+// SetBatchSize sets the number of recipients per bulk request, up to
+// MaxBatchSize
+func (m *MessageService) SetBatchSize(size int) {
+	if size > 0 {
+		m.batchSize = min(size, MaxBatchSize)
+	}
+}
+
+// SendBroadcast sends a message to multiple recipients. When the client
+// supports bulk sends, recipients are sent in batches; otherwise (or once the
+// client reports bulk is unsupported) each message is sent individually.
+// A batch that fails any other way is reported as one error and not
+// retried, since some of its messages may already have gone out.
 func (m *MessageService) SendBroadcast(from string, recipients []string, message string) ([]*SMSMessage, []error) {
+	bulkClient, canBatch := m.signalwireClient.(BulkSMSClient)
+	if !canBatch {
+		return m.sendEach(from, recipients, message)
+	}
+
+	var messages []*SMSMessage
+	var errs []error
+
+	for start := 0; start < len(recipients); start += m.batchSize {
+		end := start + m.batchSize
+		if end > len(recipients) {
+			end = len(recipients)
+		}
+		batch := recipients[start:end]
+
+		results, err := bulkClient.SendBulkSMS(from, batch, message)
+		if errors.Is(err, ErrBulkUnsupported) {
+			// Send everything left one by one
+			sent, failed := m.sendEach(from, recipients[start:], message)
+			return append(messages, sent...), append(errs, failed...)
+		}
+		if err != nil {
+			log.Printf("[MessageService] Bulk send of %d recipients failed: %v", len(batch), err)
+			errs = append(errs, fmt.Errorf("failed to send to %d recipients (%s to %s): %w",
+				len(batch), batch[0], batch[len(batch)-1], err))
+			continue
+		}
+
+		for _, result := range results {
+			if result.Err != nil {
+				errs = append(errs, fmt.Errorf("failed to send to %s: %w", result.To, result.Err))
+				continue
+			}
+			messages = append(messages, result.Message)
+		}
+	}
+
+	return messages, errs
+}
+
+// sendEach sends a message to each recipient with one request per recipient
+func (m *MessageService) sendEach(from string, recipients []string, message string) ([]*SMSMessage, []error) {
 	var messages []*SMSMessage
 	var errors []error
 
@@ -0,0 +1,356 @@
+package numbers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/signalwire"
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// CALLER ID POOL
+// Outbound number rotation with health tracking
+// ============================================
+
+// ErrNoNumbers is returned when no number in the pool is active
+var ErrNoNumbers = errors.New("no active caller ID numbers")
+
+// Status is a number's place in the rotation
+type Status string
+
+const (
+	StatusActive  Status = "active"  // In rotation
+	StatusResting Status = "resting" // Out of rotation until RestingUntil
+	StatusRetired Status = "retired" // Flagged as spam; out until reinstated
+)
+
+// Number is one caller ID and its recent performance
+type Number struct {
+	Number       string    `json:"number"`
+	Status       Status    `json:"status"`
+	StatusReason string    `json:"status_reason,omitempty"`
+	RestingUntil time.Time `json:"resting_until,omitempty"`
+	AddedAt      time.Time `json:"added_at"`
+	LastUsed     time.Time `json:"last_used,omitempty"`
+
+	Calls      int `json:"calls"`       // Calls placed since the number last rested
+	Answered   int `json:"answered"`    // Of those, calls answered
+	CallsToday int `json:"calls_today"` // Calls placed on the current day
+	SpamFlags  int `json:"spam_flags"`
+
+	day string // Day CallsToday counts, as YYYY-MM-DD
+}
+
+// AnswerRate returns the share of calls answered since the number last
+// rested (0 with no calls)
+func (n Number) AnswerRate() float64 {
+	if n.Calls == 0 {
+		return 0
+	}
+	return float64(n.Answered) / float64(n.Calls)
+}
+
+// Purchaser buys numbers to replenish a pool. *signalwire.Client
+// implements it.
+type Purchaser interface {
+	SearchAvailableNumbers(search signalwire.NumberSearch) ([]signalwire.AvailableNumber, error)
+	PurchaseNumber(number string) (*signalwire.PhoneNumber, error)
+}
+
+// Config controls when numbers rest and when the pool buys more
+type Config struct {
+	MaxCallsPerDay int           // Rest a number for the day after this many calls (0 = unlimited)
+	MinAnswerRate  float64       // Rest numbers answering less often than this (0 = off)
+	MinSamples     int           // Calls before the answer rate is judged (default 50)
+	RestDuration   time.Duration // How long low performers rest (default 24h)
+	MaxSpamFlags   int           // Retire numbers flagged this many times (default 1)
+
+	MinActive int                     // Buy numbers when fewer are active (0 = never buy)
+	Search    signalwire.NumberSearch // Where replacement numbers are bought
+	Interval  time.Duration           // How often Run evaluates the pool (default 5m)
+}
+
+// Pool rotates outbound caller IDs, least recently used first, and takes
+// numbers out of rotation when they are overused, answered too rarely or
+// flagged as spam
+type Pool struct {
+	config    Config
+	purchaser Purchaser
+
+	numbers map[string]*Number
+	mu      sync.Mutex
+}
+
+// NewPool creates an empty pool. purchaser may be nil if the pool should
+// never buy numbers.
+func NewPool(config Config, purchaser Purchaser) *Pool {
+	if config.MinSamples <= 0 {
+		config.MinSamples = 50
+	}
+	if config.RestDuration <= 0 {
+		config.RestDuration = 24 * time.Hour
+	}
+	if config.MaxSpamFlags <= 0 {
+		config.MaxSpamFlags = 1
+	}
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	return &Pool{
+		config:    config,
+		purchaser: purchaser,
+		numbers:   make(map[string]*Number),
+	}
+}
+
+// Add puts numbers into rotation; numbers already in the pool are kept as
+// they are
+func (p *Pool) Add(numbers ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for _, number := range numbers {
+		if _, ok := p.numbers[number]; ok {
+			continue
+		}
+		p.numbers[number] = &Number{Number: number, Status: StatusActive, AddedAt: now}
+	}
+}
+
+// Remove takes a number out of the pool entirely
+func (p *Pool) Remove(number string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.numbers, number)
+}
+
+// List returns copies of every number, ordered by number
+func (p *Pool) List() []Number {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	numbers := make([]Number, 0, len(p.numbers))
+	for _, n := range p.numbers {
+		numbers = append(numbers, *n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i].Number < numbers[j].Number })
+	return numbers
+}
+
+// Next returns the active number used least recently and counts a call
+// against it
+func (p *Pool) Next() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.wakeLocked(now)
+
+	var best *Number
+	for _, n := range p.numbers {
+		if n.Status != StatusActive {
+			continue
+		}
+		if best == nil || n.LastUsed.Before(best.LastUsed) ||
+			(n.LastUsed.Equal(best.LastUsed) && n.Number < best.Number) {
+			best = n
+		}
+	}
+	if best == nil {
+		return "", ErrNoNumbers
+	}
+
+	day := now.Format("2006-01-02")
+	if best.day != day {
+		best.day = day
+		best.CallsToday = 0
+	}
+	best.Calls++
+	best.CallsToday++
+	best.LastUsed = now
+
+	if p.config.MaxCallsPerDay > 0 && best.CallsToday >= p.config.MaxCallsPerDay {
+		y, m, d := now.Date()
+		p.restLocked(best, time.Date(y, m, d+1, 0, 0, 0, 0, now.Location()), "daily call limit reached")
+	}
+	return best.Number, nil
+}
+
+// Acquire returns Next, so a pool can be used as a dialer.CallerIDSource
+func (p *Pool) Acquire(ctx context.Context) (string, error) {
+	return p.Next()
+}
+
+// RecordOutcome counts whether a call from number was answered, resting
+// the number if its answer rate falls below MinAnswerRate
+func (p *Pool) RecordOutcome(number string, answered bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n, ok := p.numbers[number]
+	if !ok {
+		return
+	}
+	if answered {
+		n.Answered++
+	}
+
+	if n.Status == StatusActive && p.config.MinAnswerRate > 0 &&
+		n.Calls >= p.config.MinSamples && n.AnswerRate() < p.config.MinAnswerRate {
+		p.restLocked(n, time.Now().Add(p.config.RestDuration),
+			fmt.Sprintf("answer rate %.0f%% below %.0f%%", n.AnswerRate()*100, p.config.MinAnswerRate*100))
+	}
+}
+
+// FlagSpam records a spam signal for a number (e.g. a carrier "Spam
+// Likely" label or a reputation lookup), retiring it after MaxSpamFlags
+func (p *Pool) FlagSpam(number, source string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n, ok := p.numbers[number]
+	if !ok {
+		return
+	}
+	n.SpamFlags++
+	if n.Status != StatusRetired && n.SpamFlags >= p.config.MaxSpamFlags {
+		n.Status = StatusRetired
+		n.StatusReason = "flagged as spam by " + source
+		n.RestingUntil = time.Time{}
+		log.Printf("[Numbers] Retired %s: %s", number, n.StatusReason)
+	}
+}
+
+// Reinstate puts a resting or retired number back into rotation with its
+// statistics cleared
+func (p *Pool) Reinstate(number string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n, ok := p.numbers[number]
+	if !ok {
+		return fmt.Errorf("number %s is not in the pool", number)
+	}
+	n.SpamFlags = 0
+	p.activateLocked(n)
+	return nil
+}
+
+// Run wakes rested numbers and replenishes the pool every Interval until
+// ctx is done
+func (p *Pool) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		p.mu.Lock()
+		p.wakeLocked(time.Now())
+		p.mu.Unlock()
+
+		if _, err := p.Replenish(ctx); err != nil {
+			log.Printf("[Numbers] Failed to replenish pool: %v", err)
+		}
+	}
+}
+
+// Replenish buys numbers matching Config.Search until MinActive numbers are
+// active, returning how many were bought
+func (p *Pool) Replenish(ctx context.Context) (int, error) {
+	if p.purchaser == nil || p.config.MinActive <= 0 {
+		return 0, nil
+	}
+
+	p.mu.Lock()
+	needed := p.config.MinActive
+	for _, n := range p.numbers {
+		if n.Status == StatusActive {
+			needed--
+		}
+	}
+	p.mu.Unlock()
+	if needed <= 0 {
+		return 0, nil
+	}
+
+	search := p.config.Search
+	if search.Limit < needed {
+		search.Limit = needed
+	}
+	available, err := p.purchaser.SearchAvailableNumbers(search)
+	if err != nil {
+		return 0, fmt.Errorf("failed to search numbers: %w", err)
+	}
+
+	bought := 0
+	for _, candidate := range available {
+		if bought == needed || ctx.Err() != nil {
+			break
+		}
+		purchased, err := p.purchaser.PurchaseNumber(candidate.PhoneNumber)
+		if err != nil {
+			log.Printf("[Numbers] Failed to purchase %s: %v", candidate.PhoneNumber, err)
+			continue
+		}
+		p.Add(purchased.PhoneNumber)
+		bought++
+		log.Printf("[Numbers] Purchased %s", purchased.PhoneNumber)
+	}
+
+	if bought < needed {
+		return bought, fmt.Errorf("bought %d of %d numbers needed", bought, needed)
+	}
+	return bought, nil
+}
+
+// TrackCalls records the answer outcome of each outbound call placed from
+// a pool number
+func (p *Pool) TrackCalls(initiator *telephony.CallInitiator) {
+	initiator.OnCompleted(func(summary telephony.CallSummary) {
+		if summary.Direction == telephony.DirectionInbound {
+			return
+		}
+		p.RecordOutcome(summary.FromNumber, summary.AnsweredAt != nil)
+	})
+}
+
+// wakeLocked returns rested numbers to rotation once their rest is over.
+// Caller must hold p.mu.
+func (p *Pool) wakeLocked(now time.Time) {
+	for _, n := range p.numbers {
+		if n.Status == StatusResting && !now.Before(n.RestingUntil) {
+			p.activateLocked(n)
+		}
+	}
+}
+
+// restLocked takes a number out of rotation until until. Caller must hold
+// p.mu.
+func (p *Pool) restLocked(n *Number, until time.Time, reason string) {
+	n.Status = StatusResting
+	n.StatusReason = reason
+	n.RestingUntil = until
+	log.Printf("[Numbers] Resting %s until %s: %s", n.Number, until.Format(time.RFC3339), reason)
+}
+
+// activateLocked puts a number back into rotation with fresh statistics.
+// Caller must hold p.mu.
+func (p *Pool) activateLocked(n *Number) {
+	n.Status = StatusActive
+	n.StatusReason = ""
+	n.RestingUntil = time.Time{}
+	n.Calls = 0
+	n.Answered = 0
+}
@@ -0,0 +1,177 @@
+package sentiment
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// LEXICON
+// Word-list scoring, in process and English only
+// ============================================
+
+const (
+	lexiconNormalize = 15   // Squashes summed word scores into -1..1
+	lexiconNegated   = -0.7 // A negated word counts for this much of its score, reversed
+	lexiconBoost     = 1.3  // An intensified word counts for this much more
+	lexiconWindow    = 3    // Words a negation or intensifier reaches
+)
+
+// lexiconWords scores words from -4 (very negative) to 4 (very positive)
+var lexiconWords = map[string]float64{
+	// Negative
+	"angry": -3, "annoyed": -2, "annoying": -2, "awful": -3, "bad": -2,
+	"broken": -2, "cancel": -1, "complaint": -2, "confused": -1, "confusing": -2,
+	"disappointed": -2, "disappointing": -2, "disgusting": -3, "frustrated": -2, "frustrating": -2,
+	"furious": -4, "garbage": -3, "hate": -3, "horrible": -3, "idiot": -3,
+	"incompetent": -3, "lawyer": -2, "lied": -3, "lies": -3, "mad": -3,
+	"mess": -2, "nonsense": -2, "outrageous": -3, "pathetic": -3,
+	"problem": -1, "ridiculous": -3, "rude": -3, "scam": -4, "scared": -2,
+	"stop": -1, "stupid": -3, "sue": -3, "terrible": -3, "unacceptable": -3,
+	"unfair": -2, "upset": -2, "useless": -3, "waste": -2, "worried": -2,
+	"worse": -2, "worst": -3, "wrong": -2, "sad": -2,
+	"afraid": -2, "nervous": -2, "lost": -1, "unhappy": -2, "ripoff": -3,
+
+	// Positive
+	"amazing": 4, "appreciate": 2, "awesome": 3, "beautiful": 3, "best": 3,
+	"better": 2, "cool": 1, "excellent": 3, "fair": 1, "fantastic": 4,
+	"fine": 1, "glad": 2, "good": 2, "great": 3, "happy": 3,
+	"helpful": 2, "interested": 2, "love": 3, "lovely": 3, "nice": 2,
+	"perfect": 3, "pleased": 2, "sure": 1, "thank": 2, "thanks": 2,
+	"wonderful": 4, "yes": 1, "definitely": 1, "grateful": 3,
+	"easy": 1, "works": 1, "okay": 1,
+}
+
+// lexiconNegations reverse the words after them
+var lexiconNegations = map[string]bool{
+	"not": true, "no": true, "never": true, "dont": true, "don't": true,
+	"didnt": true, "didn't": true, "isnt": true, "isn't": true, "wasnt": true,
+	"wasn't": true, "cant": true, "can't": true, "cannot": true, "wont": true,
+	"won't": true, "aint": true, "ain't": true, "nothing": true, "hardly": true,
+}
+
+// lexiconIntensifiers strengthen the words after them
+var lexiconIntensifiers = map[string]bool{
+	"very": true, "really": true, "so": true, "extremely": true, "totally": true,
+	"completely": true, "absolutely": true, "incredibly": true, "super": true, "too": true,
+}
+
+// lexiconEmotions are words suggesting each emotion, strongest emotion
+// first. Emotions with a sign are only reported for text scoring that way,
+// so "not happy" isn't happy.
+var lexiconEmotions = []struct {
+	emotion string
+	sign    float64
+	words   []string
+}{
+	{EmotionAngry, -1, []string{"angry", "furious", "mad", "hate", "ridiculous", "outrageous", "unacceptable", "scam", "sue", "lawyer", "idiot", "stupid", "rude", "lied", "lies"}},
+	{EmotionFrustrated, -1, []string{"frustrated", "frustrating", "annoyed", "annoying", "useless", "waste", "broken", "ridiculous"}},
+	{EmotionAnxious, 0, []string{"worried", "scared", "afraid", "nervous", "urgent", "emergency", "panic"}},
+	{EmotionConfused, 0, []string{"confused", "confusing", "understand", "huh", "lost"}},
+	{EmotionSad, -1, []string{"sad", "unhappy", "disappointed", "disappointing", "died"}},
+	{EmotionGrateful, 1, []string{"thank", "thanks", "appreciate", "grateful"}},
+	{EmotionHappy, 1, []string{"happy", "glad", "great", "love", "awesome", "wonderful", "perfect", "amazing", "fantastic", "excellent"}},
+}
+
+// Lexicon is a Provider scoring English text against built-in word lists,
+// with simple handling of negation ("not happy") and intensifiers ("very
+// upset"). It needs no service and adds no latency, but misses sarcasm and
+// context; use a model-based provider where that matters.
+type Lexicon struct {
+	words map[string]float64
+}
+
+// NewLexicon creates a lexicon provider with the built-in word list
+func NewLexicon() *Lexicon {
+	words := make(map[string]float64, len(lexiconWords))
+	for word, score := range lexiconWords {
+		words[word] = score
+	}
+	return &Lexicon{words: words}
+}
+
+// SetWord adds a word or replaces its score (-4 to 4), e.g. for terms of
+// your business. It must not be called while the lexicon is in use.
+func (l *Lexicon) SetWord(word string, score float64) {
+	l.words[strings.ToLower(word)] = score
+}
+
+// Analyze scores text
+func (l *Lexicon) Analyze(ctx context.Context, text string) (telephony.Sentiment, error) {
+	var words []string
+	var total float64
+
+	// Negations and intensifiers don't reach past the end of a clause, so
+	// "no, that's great" is positive
+	text = strings.ReplaceAll(strings.ToLower(text), "’", "'")
+	clauses := strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(".,;:!?", r)
+	})
+	for _, clause := range clauses {
+		negatedFor, boostedFor := 0, 0
+		for _, word := range lexiconTokens(clause) {
+			words = append(words, word)
+			score, scored := l.words[word]
+			switch {
+			case lexiconNegations[word] && !scored:
+				negatedFor = lexiconWindow
+				continue
+			case lexiconIntensifiers[word] && !scored:
+				boostedFor = lexiconWindow
+				continue
+			}
+
+			if scored {
+				if boostedFor > 0 {
+					score *= lexiconBoost
+				}
+				if negatedFor > 0 {
+					score *= lexiconNegated
+				}
+				total += score
+			}
+			negatedFor, boostedFor = max(negatedFor-1, 0), max(boostedFor-1, 0)
+		}
+	}
+
+	// Exclamation marks strengthen whatever was said
+	if exclaimed := strings.Count(text, "!"); exclaimed > 0 && total != 0 {
+		total += math.Copysign(0.3*float64(min(exclaimed, 3)), total)
+	}
+
+	return telephony.Sentiment{
+		Score:   clamp(total / math.Sqrt(total*total+lexiconNormalize)),
+		Emotion: lexiconEmotion(words, total),
+	}, nil
+}
+
+// lexiconEmotion returns the strongest emotion words scoring total suggest,
+// or ""
+func lexiconEmotion(words []string, total float64) string {
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		seen[word] = true
+	}
+	for _, e := range lexiconEmotions {
+		if e.sign*total < 0 || (e.sign != 0 && total == 0) {
+			continue
+		}
+		for _, word := range e.words {
+			if seen[word] {
+				return e.emotion
+			}
+		}
+	}
+	return ""
+}
+
+// lexiconTokens splits lowercase text into words, keeping apostrophes
+func lexiconTokens(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+}
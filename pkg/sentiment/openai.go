@@ -0,0 +1,135 @@
+package sentiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// OPENAI SENTIMENT
+// Scores from a chat model asked for JSON
+// ============================================
+
+const (
+	OpenAIURL = "https://api.openai.com/v1"
+
+	openAIModel   = "gpt-4o-mini" // Fast enough to keep up with turns
+	openAITimeout = 10 * time.Second
+)
+
+// openAIInstructions asks the model for a score and emotion
+var openAIInstructions = `You rate the sentiment of what a caller said on a phone call.
+Reply with only a JSON object: {"score": <number from -1 (very negative) to 1 (very positive), 0 for neutral>, "emotion": <one of ` +
+	strings.Join([]string{EmotionAngry, EmotionFrustrated, EmotionAnxious, EmotionConfused, EmotionSad, EmotionHappy, EmotionGrateful}, ", ") +
+	`, or "" if none is clear>}`
+
+// OpenAI is a Provider asking an OpenAI chat model to rate text. It
+// understands context and sarcasm the Lexicon misses, at the cost of a
+// request per turn.
+type OpenAI struct {
+	apiKey string
+	url    string
+	model  string
+	client *http.Client
+}
+
+// NewOpenAI creates a provider authenticating with apiKey
+func NewOpenAI(apiKey string) *OpenAI {
+	return &OpenAI{
+		apiKey: apiKey,
+		url:    OpenAIURL,
+		model:  openAIModel,
+		client: &http.Client{Timeout: openAITimeout},
+	}
+}
+
+// SetModel selects the model (default gpt-4o-mini)
+func (o *OpenAI) SetModel(model string) {
+	o.model = model
+}
+
+// SetURL replaces the API base URL, e.g. for a compatible server
+func (o *OpenAI) SetURL(url string) {
+	o.url = strings.TrimRight(url, "/")
+}
+
+// SetHTTPClient replaces the HTTP client, e.g. for a proxy
+func (o *OpenAI) SetHTTPClient(client *http.Client) {
+	o.client = client
+}
+
+// openAIMessage is a chat message
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIRequest is a chat completion request
+type openAIRequest struct {
+	Model          string            `json:"model"`
+	Messages       []openAIMessage   `json:"messages"`
+	ResponseFormat map[string]string `json:"response_format"`
+	Temperature    float64           `json:"temperature"`
+}
+
+// openAIResponse is the part of a chat completion used
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Analyze asks the model to rate text
+func (o *OpenAI) Analyze(ctx context.Context, text string) (telephony.Sentiment, error) {
+	body, err := json.Marshal(openAIRequest{
+		Model: o.model,
+		Messages: []openAIMessage{
+			{Role: "system", Content: openAIInstructions},
+			{Role: "user", Content: text},
+		},
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return telephony.Sentiment{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return telephony.Sentiment{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return telephony.Sentiment{}, fmt.Errorf("openai request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return telephony.Sentiment{}, fmt.Errorf("openai request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var response openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return telephony.Sentiment{}, fmt.Errorf("failed to decode openai response: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return telephony.Sentiment{}, fmt.Errorf("openai returned no choices")
+	}
+
+	var sentiment telephony.Sentiment
+	if err := json.Unmarshal([]byte(response.Choices[0].Message.Content), &sentiment); err != nil {
+		return telephony.Sentiment{}, fmt.Errorf("invalid openai sentiment %q: %w", response.Choices[0].Message.Content, err)
+	}
+	sentiment.Score = clamp(sentiment.Score)
+	sentiment.Emotion = strings.ToLower(sentiment.Emotion)
+	return sentiment, nil
+}
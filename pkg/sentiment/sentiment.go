@@ -0,0 +1,43 @@
+package sentiment
+
+import (
+	"context"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// SENTIMENT ANALYSIS
+// Scoring how speech comes across, behind a provider interface
+// ============================================
+
+// Emotions providers report, strongest first when several apply
+const (
+	EmotionAngry      = "angry"
+	EmotionFrustrated = "frustrated"
+	EmotionAnxious    = "anxious"
+	EmotionConfused   = "confused"
+	EmotionSad        = "sad"
+	EmotionHappy      = "happy"
+	EmotionGrateful   = "grateful"
+)
+
+// Provider scores the sentiment of text, e.g. a caller's turn
+type Provider interface {
+	// Analyze returns how text comes across: a score from -1 (very
+	// negative) to 1 (very positive), and its strongest emotion if any
+	Analyze(ctx context.Context, text string) (telephony.Sentiment, error)
+}
+
+// ProviderFunc adapts a function to Provider
+type ProviderFunc func(ctx context.Context, text string) (telephony.Sentiment, error)
+
+// Analyze calls f
+func (f ProviderFunc) Analyze(ctx context.Context, text string) (telephony.Sentiment, error) {
+	return f(ctx, text)
+}
+
+// clamp limits a score to -1..1
+func clamp(score float64) float64 {
+	return max(-1, min(1, score))
+}
@@ -0,0 +1,92 @@
+package signalwire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ============================================
+// BULK MESSAGING
+// One request for many recipients where the space supports it
+// ============================================
+
+// MaxBulkRecipients is the most recipients accepted in one bulk request
+const MaxBulkRecipients = 1000
+
+// ErrBulkNotSupported is returned when the space has no bulk messaging endpoint
+var ErrBulkNotSupported = errors.New("bulk messaging not supported")
+
+// BulkMessageResult is the outcome for one recipient of a bulk send
+type BulkMessageResult struct {
+	To      string   `json:"to"`
+	Message *Message `json:"message,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// bulkMessageResponse is the body returned by the bulk endpoint
+type bulkMessageResponse struct {
+	Messages []Message `json:"messages"`
+	Errors   []struct {
+		To      string `json:"to"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// SendBulkSMS sends the same text message to many recipients in a single
+// request. Spaces without bulk messaging return ErrBulkNotSupported, which is
+// remembered so later calls fail fast; callers should then send per message.
+func (c *Client) SendBulkSMS(from string, recipients []string, message string) ([]BulkMessageResult, error) {
+	if c.projectID == "" || c.token == "" {
+		return nil, fmt.Errorf("SignalWire credentials not configured")
+	}
+	if c.bulkUnsupported.Load() {
+		return nil, ErrBulkNotSupported
+	}
+	if len(recipients) > MaxBulkRecipients {
+		return nil, fmt.Errorf("too many recipients for one bulk request (%d > %d)", len(recipients), MaxBulkRecipients)
+	}
+
+	path := fmt.Sprintf("/Accounts/%s/Messages/Bulk.json", c.projectID)
+
+	formData := url.Values{}
+	formData.Set("From", from)
+	formData.Set("Body", message)
+	for _, to := range recipients {
+		formData.Add("To", to)
+	}
+
+	resp, err := c.do("POST", path, formData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		c.bulkUnsupported.Store(true)
+		return nil, ErrBulkNotSupported
+	case http.StatusCreated, http.StatusOK, http.StatusAccepted:
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("SignalWire API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var bulk bulkMessageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bulk); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	results := make([]BulkMessageResult, 0, len(recipients))
+	for i := range bulk.Messages {
+		results = append(results, BulkMessageResult{To: bulk.Messages[i].To, Message: &bulk.Messages[i]})
+	}
+	for _, failure := range bulk.Errors {
+		results = append(results, BulkMessageResult{To: failure.To, Error: failure.Message})
+	}
+
+	return results, nil
+}
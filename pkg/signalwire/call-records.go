@@ -0,0 +1,187 @@
+package signalwire
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================
+// CALL AND MESSAGE RECORDS
+// Paged listing of past calls and messages with their billed price
+// ============================================
+
+// laMLPathPrefix is stripped from next_page_uri values to get request paths
+const laMLPathPrefix = "/api/laml/2010-04-01"
+
+// ListFilter selects calls or messages to list
+type ListFilter struct {
+	Status   string    // e.g. "completed" or "delivered" (empty = any)
+	Since    time.Time // Started/sent on or after this day (UTC)
+	Until    time.Time // Started/sent on or before this day (UTC)
+	PageSize int       // Records per request (default 1000)
+}
+
+// recordPage is one page of a list response
+type recordPage struct {
+	Calls        []callRecord    `json:"calls"`
+	Messages     []messageRecord `json:"messages"`
+	PhoneNumbers []PhoneNumber   `json:"incoming_phone_numbers"`
+	NextPageURI  string          `json:"next_page_uri"`
+}
+
+// callRecord is a listed call; timestamps are RFC 2822 strings
+type callRecord struct {
+	SID        string `json:"sid"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Status     string `json:"status"`
+	Direction  string `json:"direction"`
+	Duration   string `json:"duration"`
+	StartTime  string `json:"start_time"`
+	EndTime    string `json:"end_time"`
+	Price      string `json:"price"`
+	StirStatus string `json:"stir_status"`
+}
+
+// messageRecord is a listed message; timestamps are RFC 2822 strings
+type messageRecord struct {
+	SID       string `json:"sid"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Body      string `json:"body"`
+	Status    string `json:"status"`
+	Direction string `json:"direction"`
+	DateSent  string `json:"date_sent"`
+	Price     string `json:"price"`
+}
+
+// ListCalls returns every call matching filter, following pagination
+func (c *Client) ListCalls(filter ListFilter) ([]Call, error) {
+	query := filter.query("StartTime")
+	path := fmt.Sprintf("/Accounts/%s/Calls.json?%s", c.projectID, query.Encode())
+
+	var calls []Call
+	err := c.listPages(path, func(page *recordPage) {
+		for _, record := range page.Calls {
+			calls = append(calls, Call{
+				SID:        record.SID,
+				From:       record.From,
+				To:         record.To,
+				Status:     record.Status,
+				Direction:  record.Direction,
+				Duration:   record.Duration,
+				StartTime:  parseRecordTime(record.StartTime),
+				EndTime:    parseRecordTime(record.EndTime),
+				Price:      record.Price,
+				StirStatus: record.StirStatus,
+			})
+		}
+	})
+	return calls, err
+}
+
+// ListMessages returns every message matching filter, following pagination
+func (c *Client) ListMessages(filter ListFilter) ([]Message, error) {
+	query := filter.query("DateSent")
+	path := fmt.Sprintf("/Accounts/%s/Messages.json?%s", c.projectID, query.Encode())
+
+	var messages []Message
+	err := c.listPages(path, func(page *recordPage) {
+		for _, record := range page.Messages {
+			messages = append(messages, Message{
+				SID:       record.SID,
+				From:      record.From,
+				To:        record.To,
+				Body:      record.Body,
+				Status:    record.Status,
+				Direction: record.Direction,
+				DateSent:  parseRecordTime(record.DateSent),
+				Price:     record.Price,
+			})
+		}
+	})
+	return messages, err
+}
+
+// listPages fetches path and each following page
+func (c *Client) listPages(path string, handle func(*recordPage)) error {
+	if c.projectID == "" || c.token == "" {
+		return fmt.Errorf("SignalWire credentials not configured")
+	}
+
+	for path != "" {
+		resp, err := c.do("GET", path, nil)
+		if err != nil {
+			return fmt.Errorf("failed to make request: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return fmt.Errorf("SignalWire API error (%d): %s", resp.StatusCode, string(body))
+		}
+
+		var page recordPage
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		handle(&page)
+		path = strings.TrimPrefix(page.NextPageURI, laMLPathPrefix)
+	}
+	return nil
+}
+
+// query builds list parameters, with date bounds on the given field
+func (f ListFilter) query(dateField string) url.Values {
+	query := url.Values{}
+	if f.Status != "" {
+		query.Set("Status", f.Status)
+	}
+	if !f.Since.IsZero() {
+		query.Set(dateField+">", f.Since.UTC().Format("2006-01-02"))
+	}
+	if !f.Until.IsZero() {
+		query.Set(dateField+"<", f.Until.UTC().Format("2006-01-02"))
+	}
+	pageSize := f.PageSize
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+	query.Set("PageSize", strconv.Itoa(pageSize))
+	return query
+}
+
+// parseRecordTime parses an RFC 2822 record timestamp (zero if absent)
+func parseRecordTime(value string) time.Time {
+	t, err := time.Parse(time.RFC1123Z, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ParsePrice returns a billed price in USD as a positive amount. SignalWire
+// reports charges as negative strings ("-0.00750"); ok is false while the
+// record hasn't been rated yet.
+func ParsePrice(price string) (usd float64, ok bool) {
+	if price == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return 0, false
+	}
+	if value < 0 {
+		value = -value
+	}
+	return value, true
+}
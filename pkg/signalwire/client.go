@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,8 +16,10 @@ type Client struct {
 	projectID  string
 	token      string
 	space      string
-	baseURL    string
+	endpoints  *Endpoints
 	httpClient *http.Client
+
+	bulkUnsupported atomic.Bool // Set once the space rejects bulk message requests
 }
 
 // Call represents a SignalWire call
@@ -31,6 +34,7 @@ type Call struct {
 	EndTime      time.Time `json:"end_time"`
 	Price        string    `json:"price"`
 	RecordingURL string    `json:"recording_url,omitempty"`
+	StirStatus   string    `json:"stir_status,omitempty"` // STIR/SHAKEN attestation of outbound calls (A, B or C)
 }
 
 // Message represents an SMS message
@@ -55,6 +59,8 @@ type CallRequest struct {
 	Record           bool   `json:"Record,omitempty"`
 	Timeout          int    `json:"Timeout,omitempty"`
 	MachineDetection string `json:"MachineDetection,omitempty"` // Enable, DetectMessageEnd
+	SipAuthUsername  string `json:"SipAuthUsername,omitempty"`  // Digest credentials when To is a sip: URI
+	SipAuthPassword  string `json:"SipAuthPassword,omitempty"`
 }
 
 // MessageRequest options for sending SMS
@@ -76,40 +82,111 @@ func NewClient(projectID, token, space string) *Client {
 		projectID: projectID,
 		token:     token,
 		space:     space,
-		baseURL:   fmt.Sprintf("https://%s/api/laml/2010-04-01", space),
+		endpoints: NewEndpoints(space),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 }
 
+// SetEndpoints configures regional/edge hosts to use before the space URL.
+// Hosts are tried in order and the space URL is always kept as the final
+// fallback.
+func (c *Client) SetEndpoints(hosts ...string) {
+	c.endpoints = NewEndpoints(append(hosts, c.space)...)
+}
+
+// SetTransport configures proxy, dialer and TLS settings for API requests
+func (c *Client) SetTransport(config TransportConfig) error {
+	httpClient, err := config.HTTPClient()
+	if err != nil {
+		return err
+	}
+	c.httpClient = httpClient
+	return nil
+}
+
+// SetHTTPClient replaces the HTTP client used for API requests
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// Endpoints returns the endpoint set used by the client
+func (c *Client) Endpoints() *Endpoints {
+	return c.endpoints
+}
+
+// do sends an authenticated API request, falling back across endpoints
+func (c *Client) do(method, path string, formData url.Values) (*http.Response, error) {
+	return c.endpoints.Do(c.httpClient, func(host string) (*http.Request, error) {
+		var body io.Reader
+		if formData != nil {
+			body = strings.NewReader(formData.Encode())
+		}
+
+		req, err := http.NewRequest(method, LaMLBaseURL(host)+path, body)
+		if err != nil {
+			return nil, err
+		}
+
+		if formData != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		req.SetBasicAuth(c.projectID, c.token)
+		return req, nil
+	})
+}
+
 // MakeCall initiates an outbound call
 func (c *Client) MakeCall(from, to, webhookURL string, record bool) (*Call, error) {
+	return c.CreateCall(CallRequest{
+		From:             from,
+		To:               to,
+		URL:              webhookURL,
+		Record:           record,
+		MachineDetection: "DetectMessageEnd",
+	})
+}
+
+// CreateCall initiates an outbound call with full options. To may be an
+// E.164 number or a sip: URI for PBXs and SIP trunks.
+func (c *Client) CreateCall(req CallRequest) (*Call, error) {
 	if c.projectID == "" || c.token == "" {
 		return nil, fmt.Errorf("SignalWire credentials not configured")
 	}
+	if req.SipAuthUsername != "" && !IsSIPURI(req.To) {
+		return nil, fmt.Errorf("SIP credentials require a sip: URI")
+	}
 
-	reqURL := fmt.Sprintf("%s/Accounts/%s/Calls.json", c.baseURL, c.projectID)
+	path := fmt.Sprintf("/Accounts/%s/Calls.json", c.projectID)
 
 	formData := url.Values{}
-	formData.Set("From", from)
-	formData.Set("To", to)
-	formData.Set("Url", webhookURL)
-	formData.Set("Method", "POST")
-	if record {
+	formData.Set("From", req.From)
+	formData.Set("To", req.To)
+	formData.Set("Url", req.URL)
+	if req.Method != "" {
+		formData.Set("Method", req.Method)
+	} else {
+		formData.Set("Method", "POST")
+	}
+	if req.StatusCallback != "" {
+		formData.Set("StatusCallback", req.StatusCallback)
+	}
+	if req.Record {
 		formData.Set("Record", "true")
 	}
-	formData.Set("MachineDetection", "DetectMessageEnd")
-
-	req, err := http.NewRequest("POST", reqURL, strings.NewReader(formData.Encode()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if req.Timeout > 0 {
+		formData.Set("Timeout", fmt.Sprintf("%d", req.Timeout))
+	}
+	if req.MachineDetection != "" {
+		formData.Set("MachineDetection", req.MachineDetection)
+	}
+	if req.SipAuthUsername != "" {
+		formData.Set("SipAuthUsername", req.SipAuthUsername)
+		formData.Set("SipAuthPassword", req.SipAuthPassword)
 	}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.SetBasicAuth(c.projectID, c.token)
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("POST", path, formData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -128,22 +205,21 @@ func (c *Client) MakeCall(from, to, webhookURL string, record bool) (*Call, erro
 	return &call, nil
 }
 
+// IsSIPURI reports whether a call target is a sip: or sips: URI
+func IsSIPURI(to string) bool {
+	lower := strings.ToLower(to)
+	return strings.HasPrefix(lower, "sip:") || strings.HasPrefix(lower, "sips:")
+}
+
 // GetCall retrieves call details
 func (c *Client) GetCall(callSID string) (*Call, error) {
 	if c.projectID == "" || c.token == "" {
 		return nil, fmt.Errorf("SignalWire credentials not configured")
 	}
 
-	reqURL := fmt.Sprintf("%s/Accounts/%s/Calls/%s.json", c.baseURL, c.projectID, callSID)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(c.projectID, c.token)
+	path := fmt.Sprintf("/Accounts/%s/Calls/%s.json", c.projectID, callSID)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("GET", path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -168,20 +244,12 @@ func (c *Client) HangupCall(callSID string) error {
 		return fmt.Errorf("SignalWire credentials not configured")
 	}
 
-	reqURL := fmt.Sprintf("%s/Accounts/%s/Calls/%s.json", c.baseURL, c.projectID, callSID)
+	path := fmt.Sprintf("/Accounts/%s/Calls/%s.json", c.projectID, callSID)
 
 	formData := url.Values{}
 	formData.Set("Status", "completed")
 
-	req, err := http.NewRequest("POST", reqURL, strings.NewReader(formData.Encode()))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.SetBasicAuth(c.projectID, c.token)
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("POST", path, formData)
 	if err != nil {
 		return fmt.Errorf("failed to make request: %w", err)
 	}
@@ -201,22 +269,14 @@ func (c *Client) SendSMS(from, to, message string) (*Message, error) {
 		return nil, fmt.Errorf("SignalWire credentials not configured")
 	}
 
-	reqURL := fmt.Sprintf("%s/Accounts/%s/Messages.json", c.baseURL, c.projectID)
+	path := fmt.Sprintf("/Accounts/%s/Messages.json", c.projectID)
 
 	formData := url.Values{}
 	formData.Set("From", from)
 	formData.Set("To", to)
 	formData.Set("Body", message)
 
-	req, err := http.NewRequest("POST", reqURL, strings.NewReader(formData.Encode()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.SetBasicAuth(c.projectID, c.token)
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("POST", path, formData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -271,16 +331,9 @@ func (c *Client) GetRecording(recordingSID string) ([]byte, error) {
 		return nil, fmt.Errorf("SignalWire credentials not configured")
 	}
 
-	reqURL := fmt.Sprintf("%s/Accounts/%s/Recordings/%s.mp3", c.baseURL, c.projectID, recordingSID)
+	path := fmt.Sprintf("/Accounts/%s/Recordings/%s.mp3", c.projectID, recordingSID)
 
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(c.projectID, c.token)
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("GET", path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -319,16 +372,9 @@ func (c *Client) GetAccountInfo() (map[string]interface{}, error) {
 		return nil, fmt.Errorf("SignalWire credentials not configured")
 	}
 
-	reqURL := fmt.Sprintf("%s/Accounts/%s.json", c.baseURL, c.projectID)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(c.projectID, c.token)
+	path := fmt.Sprintf("/Accounts/%s.json", c.projectID)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("GET", path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -0,0 +1,254 @@
+package signalwire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ============================================
+// EMERGENCY ADDRESSES
+// E911 address registration for the project's numbers
+// ============================================
+
+// EmergencyStatusActive is a number's EmergencyStatus once an address is
+// registered for it
+const EmergencyStatusActive = "Active"
+
+// ErrEmergencyAddressInvalid is matched (errors.Is) by address validation
+// errors
+var ErrEmergencyAddressInvalid = errors.New("invalid emergency address")
+
+var (
+	usPostalCode = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+	caPostalCode = regexp.MustCompile(`^[A-Za-z]\d[A-Za-z] ?\d[A-Za-z]\d$`)
+)
+
+// EmergencyAddress is the civic address dispatched to emergency services
+// when a number calls 911
+type EmergencyAddress struct {
+	SID             string `json:"sid,omitempty"`
+	CustomerName    string `json:"customer_name"`
+	Street          string `json:"street"`
+	StreetSecondary string `json:"street_secondary,omitempty"` // Suite, floor or unit
+	City            string `json:"city"`
+	Region          string `json:"region"` // Two-letter state or province
+	PostalCode      string `json:"postal_code"`
+	IsoCountry      string `json:"iso_country"` // "US" or "CA"
+
+	EmergencyEnabled bool `json:"emergency_enabled"`
+	Validated        bool `json:"validated"`
+}
+
+// Validate checks the address is complete enough to register for E911
+func (a EmergencyAddress) Validate() error {
+	var missing []string
+	for _, field := range []struct{ name, value string }{
+		{"customer name", a.CustomerName},
+		{"street", a.Street},
+		{"city", a.City},
+		{"region", a.Region},
+		{"postal code", a.PostalCode},
+		{"country", a.IsoCountry},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrEmergencyAddressInvalid, strings.Join(missing, ", "))
+	}
+
+	switch strings.ToUpper(a.IsoCountry) {
+	case "US":
+		if !usPostalCode.MatchString(a.PostalCode) {
+			return fmt.Errorf("%w: postal code %q is not a US ZIP code", ErrEmergencyAddressInvalid, a.PostalCode)
+		}
+	case "CA":
+		if !caPostalCode.MatchString(a.PostalCode) {
+			return fmt.Errorf("%w: postal code %q is not a Canadian postal code", ErrEmergencyAddressInvalid, a.PostalCode)
+		}
+	default:
+		return fmt.Errorf("%w: E911 is only available for US and CA addresses", ErrEmergencyAddressInvalid)
+	}
+	if len(a.Region) != 2 {
+		return fmt.Errorf("%w: region must be a two-letter state or province code", ErrEmergencyAddressInvalid)
+	}
+	return nil
+}
+
+// form encodes the address for create and update requests
+func (a EmergencyAddress) form() url.Values {
+	formData := url.Values{}
+	formData.Set("CustomerName", a.CustomerName)
+	formData.Set("Street", a.Street)
+	if a.StreetSecondary != "" {
+		formData.Set("StreetSecondary", a.StreetSecondary)
+	}
+	formData.Set("City", a.City)
+	formData.Set("Region", strings.ToUpper(a.Region))
+	formData.Set("PostalCode", a.PostalCode)
+	formData.Set("IsoCountry", strings.ToUpper(a.IsoCountry))
+	formData.Set("EmergencyEnabled", "true")
+	return formData
+}
+
+// CreateEmergencyAddress validates and registers an address for E911
+func (c *Client) CreateEmergencyAddress(address EmergencyAddress) (*EmergencyAddress, error) {
+	if err := address.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/Accounts/%s/Addresses.json", c.projectID)
+	return c.saveEmergencyAddress(path, address.form())
+}
+
+// UpdateEmergencyAddress validates and replaces a registered address; the
+// numbers using it keep it
+func (c *Client) UpdateEmergencyAddress(address EmergencyAddress) (*EmergencyAddress, error) {
+	if address.SID == "" {
+		return nil, fmt.Errorf("address SID is required")
+	}
+	if err := address.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/Accounts/%s/Addresses/%s.json", c.projectID, address.SID)
+	return c.saveEmergencyAddress(path, address.form())
+}
+
+// GetEmergencyAddress fetches a registered address
+func (c *Client) GetEmergencyAddress(addressSID string) (*EmergencyAddress, error) {
+	if c.projectID == "" || c.token == "" {
+		return nil, fmt.Errorf("SignalWire credentials not configured")
+	}
+
+	path := fmt.Sprintf("/Accounts/%s/Addresses/%s.json", c.projectID, addressSID)
+	resp, err := c.do("GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	return decodeEmergencyAddress(resp)
+}
+
+func (c *Client) saveEmergencyAddress(path string, formData url.Values) (*EmergencyAddress, error) {
+	if c.projectID == "" || c.token == "" {
+		return nil, fmt.Errorf("SignalWire credentials not configured")
+	}
+
+	resp, err := c.do("POST", path, formData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	return decodeEmergencyAddress(resp)
+}
+
+func decodeEmergencyAddress(resp *http.Response) (*EmergencyAddress, error) {
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("SignalWire API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var address EmergencyAddress
+	if err := json.NewDecoder(resp.Body).Decode(&address); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &address, nil
+}
+
+// AssignEmergencyAddress registers a number for E911 at an address. The
+// address must be emergency-enabled.
+func (c *Client) AssignEmergencyAddress(phoneNumberSID, addressSID string) (*PhoneNumber, error) {
+	address, err := c.GetEmergencyAddress(addressSID)
+	if err != nil {
+		return nil, err
+	}
+	if !address.EmergencyEnabled {
+		return nil, fmt.Errorf("address %s is not enabled for emergency calling", addressSID)
+	}
+
+	path := fmt.Sprintf("/Accounts/%s/IncomingPhoneNumbers/%s.json", c.projectID, phoneNumberSID)
+
+	formData := url.Values{}
+	formData.Set("EmergencyAddressSid", addressSID)
+	formData.Set("EmergencyStatus", EmergencyStatusActive)
+
+	resp, err := c.do("POST", path, formData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("SignalWire API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var number PhoneNumber
+	if err := json.NewDecoder(resp.Body).Decode(&number); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &number, nil
+}
+
+// EmergencyIssue is a number that can't place emergency calls correctly
+type EmergencyIssue struct {
+	PhoneNumber string `json:"phone_number"`
+	Problem     string `json:"problem"`
+}
+
+// CheckEmergencyReadiness reports which of numbers (every owned number when
+// none are given) have no active E911 registration. Run it before handing
+// numbers to real users.
+func (c *Client) CheckEmergencyReadiness(numbers ...string) ([]EmergencyIssue, error) {
+	owned, err := c.ListPhoneNumbers()
+	if err != nil {
+		return nil, err
+	}
+
+	byNumber := make(map[string]PhoneNumber, len(owned))
+	for _, number := range owned {
+		byNumber[number.PhoneNumber] = number
+	}
+	if len(numbers) == 0 {
+		for _, number := range owned {
+			numbers = append(numbers, number.PhoneNumber)
+		}
+	}
+
+	var issues []EmergencyIssue
+	for _, number := range numbers {
+		owned, ok := byNumber[number]
+		switch {
+		case !ok:
+			issues = append(issues, EmergencyIssue{number, "not owned by the project"})
+		case owned.EmergencyAddressSID == "":
+			issues = append(issues, EmergencyIssue{number, "no emergency address registered"})
+		case owned.EmergencyStatus != EmergencyStatusActive:
+			issues = append(issues, EmergencyIssue{number, fmt.Sprintf("emergency registration is %q", owned.EmergencyStatus)})
+		}
+	}
+	return issues, nil
+}
+
+// EmergencyReadyNumbers returns the owned numbers with an active E911
+// registration
+func (c *Client) EmergencyReadyNumbers() ([]string, error) {
+	owned, err := c.ListPhoneNumbers()
+	if err != nil {
+		return nil, err
+	}
+
+	var ready []string
+	for _, number := range owned {
+		if number.EmergencyAddressSID != "" && number.EmergencyStatus == EmergencyStatusActive {
+			ready = append(ready, number.PhoneNumber)
+		}
+	}
+	return ready, nil
+}
@@ -0,0 +1,139 @@
+package signalwire
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+)
+
+// Endpoints is an ordered set of SignalWire hosts (the space URL plus any
+// regional edges). Requests go to the preferred host first and fall back to
+// the others when it is unreachable.
+type Endpoints struct {
+	hosts     []string
+	preferred int
+	mu        sync.RWMutex
+}
+
+// NewEndpoints creates an endpoint set; the first host is preferred
+func NewEndpoints(hosts ...string) *Endpoints {
+	filtered := make([]string, 0, len(hosts))
+	seen := make(map[string]bool)
+	for _, host := range hosts {
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		filtered = append(filtered, host)
+	}
+
+	return &Endpoints{hosts: filtered}
+}
+
+// Hosts returns all hosts in the order they should be tried, starting with
+// the last host that answered successfully
+func (e *Endpoints) Hosts() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	ordered := make([]string, 0, len(e.hosts))
+	for i := range e.hosts {
+		ordered = append(ordered, e.hosts[(e.preferred+i)%len(e.hosts)])
+	}
+	return ordered
+}
+
+// Primary returns the host currently preferred
+func (e *Endpoints) Primary() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if len(e.hosts) == 0 {
+		return ""
+	}
+	return e.hosts[e.preferred]
+}
+
+// MarkHealthy makes host the preferred endpoint for subsequent requests
+func (e *Endpoints) MarkHealthy(host string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i, h := range e.hosts {
+		if h == host {
+			e.preferred = i
+			return
+		}
+	}
+}
+
+// Do sends the request built by newRequest to each host in turn until one
+// is reachable. Requests that never got a connection (DNS, dial or TLS
+// failures) always move on to the next host. Idempotent requests also do on
+// other network errors and gateway errors (502/503/504) from an edge; other
+// requests may already have been acted on, e.g. a call placed, so those
+// errors are returned rather than repeated elsewhere.
+func (e *Endpoints) Do(client *http.Client, newRequest func(host string) (*http.Request, error)) (*http.Response, error) {
+	hosts := e.Hosts()
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("no SignalWire endpoints configured")
+	}
+
+	var lastErr error
+	for i, host := range hosts {
+		req, err := newRequest(host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		retryable := isIdempotent(req.Method)
+
+		// Nothing is written before a connection is made
+		var connected atomic.Bool
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+			GotConn: func(httptrace.GotConnInfo) { connected.Store(true) },
+		}))
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			if req.Context().Err() != nil || (connected.Load() && !retryable) {
+				break
+			}
+			continue
+		}
+
+		if isGatewayError(resp.StatusCode) && retryable && i < len(hosts)-1 {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("endpoint %s returned %d", host, resp.StatusCode)
+			continue
+		}
+
+		e.MarkHealthy(host)
+		return resp, nil
+	}
+
+	return nil, lastErr
+}
+
+// isIdempotent reports whether repeating a request with method is harmless
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isGatewayError reports whether a status means the edge itself is unavailable
+func isGatewayError(status int) bool {
+	return status == http.StatusBadGateway ||
+		status == http.StatusServiceUnavailable ||
+		status == http.StatusGatewayTimeout
+}
+
+// LaMLBaseURL returns the LaML REST API base URL for a host
+func LaMLBaseURL(host string) string {
+	return fmt.Sprintf("https://%s/api/laml/2010-04-01", host)
+}
@@ -0,0 +1,133 @@
+package signalwire
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ============================================
+// PHONE NUMBERS
+// Searching for and purchasing numbers for the project
+// ============================================
+
+// NumberSearch selects local numbers available for purchase
+type NumberSearch struct {
+	Country  string // ISO country code (default "US")
+	AreaCode string // e.g. "512"
+	Region   string // State or province, e.g. "TX"
+	Contains string // Digit pattern, e.g. "555"
+	Limit    int    // Numbers to return (default 20)
+}
+
+// AvailableNumber is a number offered for purchase
+type AvailableNumber struct {
+	PhoneNumber  string `json:"phone_number"`
+	FriendlyName string `json:"friendly_name"`
+	Locality     string `json:"locality"`
+	Region       string `json:"region"`
+}
+
+// PhoneNumber is a number owned by the project
+type PhoneNumber struct {
+	SID          string `json:"sid"`
+	PhoneNumber  string `json:"phone_number"`
+	FriendlyName string `json:"friendly_name"`
+
+	// E911 registration (see AssignEmergencyAddress)
+	EmergencyAddressSID string `json:"emergency_address_sid,omitempty"`
+	EmergencyStatus     string `json:"emergency_status,omitempty"` // "Active" once registered
+}
+
+// SearchAvailableNumbers lists local numbers that can be purchased
+func (c *Client) SearchAvailableNumbers(search NumberSearch) ([]AvailableNumber, error) {
+	if c.projectID == "" || c.token == "" {
+		return nil, fmt.Errorf("SignalWire credentials not configured")
+	}
+
+	country := search.Country
+	if country == "" {
+		country = "US"
+	}
+	limit := search.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	query := url.Values{}
+	if search.AreaCode != "" {
+		query.Set("AreaCode", search.AreaCode)
+	}
+	if search.Region != "" {
+		query.Set("InRegion", search.Region)
+	}
+	if search.Contains != "" {
+		query.Set("Contains", search.Contains)
+	}
+	query.Set("PageSize", strconv.Itoa(limit))
+
+	path := fmt.Sprintf("/Accounts/%s/AvailablePhoneNumbers/%s/Local.json?%s",
+		c.projectID, url.PathEscape(country), query.Encode())
+
+	resp, err := c.do("GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("SignalWire API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Numbers []AvailableNumber `json:"available_phone_numbers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.Numbers, nil
+}
+
+// ListPhoneNumbers returns every number owned by the project
+func (c *Client) ListPhoneNumbers() ([]PhoneNumber, error) {
+	path := fmt.Sprintf("/Accounts/%s/IncomingPhoneNumbers.json?PageSize=1000", c.projectID)
+
+	var numbers []PhoneNumber
+	err := c.listPages(path, func(page *recordPage) {
+		numbers = append(numbers, page.PhoneNumbers...)
+	})
+	return numbers, err
+}
+
+// PurchaseNumber buys an available number for the project
+func (c *Client) PurchaseNumber(number string) (*PhoneNumber, error) {
+	if c.projectID == "" || c.token == "" {
+		return nil, fmt.Errorf("SignalWire credentials not configured")
+	}
+
+	path := fmt.Sprintf("/Accounts/%s/IncomingPhoneNumbers.json", c.projectID)
+
+	formData := url.Values{}
+	formData.Set("PhoneNumber", number)
+
+	resp, err := c.do("POST", path, formData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("SignalWire API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var purchased PhoneNumber
+	if err := json.NewDecoder(resp.Body).Decode(&purchased); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &purchased, nil
+}
@@ -0,0 +1,90 @@
+package signalwire
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// TransportConfig configures how connections to SignalWire are made, for
+// deployments behind egress proxies or with custom network requirements.
+// It covers outbound traffic only (REST API, RELAY events); media streams
+// are connections SignalWire opens to the audio bridge
+type TransportConfig struct {
+	// ProxyURL routes all traffic through an HTTP(S) proxy. When empty the
+	// standard HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables apply.
+	ProxyURL string
+
+	// DialContext replaces the default TCP dialer
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// TLSConfig customizes TLS (custom CAs, client certificates, etc.)
+	TLSConfig *tls.Config
+
+	// Timeout bounds each HTTP request and WebSocket handshake (default 30s)
+	Timeout time.Duration
+}
+
+// proxyFunc returns the proxy selector for the configuration
+func (t TransportConfig) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if t.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(t.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// timeout returns the configured timeout or the default
+func (t TransportConfig) timeout() time.Duration {
+	if t.Timeout > 0 {
+		return t.Timeout
+	}
+	return 30 * time.Second
+}
+
+// HTTPClient builds an HTTP client honoring the transport configuration
+func (t TransportConfig) HTTPClient() (*http.Client, error) {
+	proxy, err := t.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	if t.DialContext != nil {
+		transport.DialContext = t.DialContext
+	}
+	if t.TLSConfig != nil {
+		transport.TLSClientConfig = t.TLSConfig
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   t.timeout(),
+	}, nil
+}
+
+// WebSocketDialer builds a WebSocket dialer honoring the transport configuration
+func (t TransportConfig) WebSocketDialer() (*websocket.Dialer, error) {
+	proxy, err := t.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	return &websocket.Dialer{
+		Proxy:            proxy,
+		NetDialContext:   t.DialContext,
+		TLSClientConfig:  t.TLSConfig,
+		HandshakeTimeout: t.timeout(),
+	}, nil
+}
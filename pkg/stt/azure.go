@@ -0,0 +1,358 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// AZURE SPEECH
+// Continuous recognition over Azure's speech WebSocket protocol
+// ============================================
+
+const (
+	azureURL        = "wss://%s.stt.speech.microsoft.com/speech/recognition/conversation/cognitiveservices/v1"
+	azureLanguage   = "en-US"          // Azure requires a language
+	azureFinalWait  = 10 * time.Second // For the last results once audio ends
+	azureBufferSize = 64
+)
+
+// Azure is a Provider using Azure AI Speech continuous recognition. Audio
+// is sent as 16kHz PCM.
+type Azure struct {
+	key    string
+	url    string
+	dialer *websocket.Dialer
+}
+
+// NewAzure creates a provider for a Speech resource's key and region
+// (e.g. "eastus")
+func NewAzure(key, region string) *Azure {
+	return &Azure{
+		key:    key,
+		url:    fmt.Sprintf(azureURL, region),
+		dialer: websocket.DefaultDialer,
+	}
+}
+
+// SetURL replaces the endpoint, e.g. for a custom speech endpoint or
+// sovereign cloud
+func (a *Azure) SetURL(url string) {
+	a.url = url
+}
+
+// SetDialer replaces the WebSocket dialer, e.g. one going through a proxy
+func (a *Azure) SetDialer(dialer *websocket.Dialer) {
+	a.dialer = dialer
+}
+
+// Recognize streams audio to Azure. If the connection fails part way, the
+// last result carries the error and audio is no longer read.
+func (a *Azure) Recognize(ctx context.Context, audio <-chan []byte, config Config) (<-chan Result, error) {
+	if err := checkFormat("Azure", config.Format); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("language", azureLanguage)
+	if config.Language != "" {
+		query.Set("language", config.Language)
+	}
+	query.Set("format", "detailed")
+	query.Set("wordLevelTimestamps", "true")
+	query.Set("profanity", "raw")
+	if config.Endpointing > 0 {
+		query.Set("segmentationSilenceTimeoutMs", strconv.FormatInt(config.Endpointing.Milliseconds(), 10))
+	}
+	if config.Model != "" {
+		query.Set("cid", config.Model) // Custom Speech endpoint ID
+	}
+
+	header := http.Header{}
+	header.Set("Ocp-Apim-Subscription-Key", a.key)
+	header.Set("X-ConnectionId", azureID())
+
+	conn, resp, err := a.dialer.DialContext(ctx, a.url+"?"+query.Encode(), header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("failed to connect to Azure (%s): %w", resp.Status, err)
+		}
+		return nil, fmt.Errorf("failed to connect to Azure: %w", err)
+	}
+
+	stream := &azureStream{
+		conn:      conn,
+		requestID: azureID(),
+		config:    config,
+	}
+	results := make(chan Result, azureBufferSize)
+	done := make(chan struct{})
+	go stream.send(ctx, audio, done)
+	go stream.receive(ctx, results, done)
+	return results, nil
+}
+
+// azureStream is one recognition turn
+type azureStream struct {
+	conn      *websocket.Conn
+	requestID string
+	config    Config
+}
+
+// azureID returns an ID in the form Azure expects: a UUID without dashes
+func azureID() string {
+	return strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+// send writes the phrase list, then audio: a WAV header first, then raw
+// PCM, then an empty message marking the end
+func (s *azureStream) send(ctx context.Context, audio <-chan []byte, done <-chan struct{}) {
+	if len(s.config.Keywords) > 0 {
+		if err := s.writeContext(); err != nil {
+			log.Printf("[Azure] Failed to send phrase list: %v", err)
+		}
+	}
+
+	header, _ := telephony.EncodeWAV(nil, telephony.AudioFormatPCM)
+	if err := s.writeAudio(header); err != nil {
+		s.conn.Close()
+		return
+	}
+
+	converter := newPCMConverter(s.config.Format)
+	for {
+		select {
+		case <-ctx.Done():
+			s.conn.Close()
+			return
+
+		case <-done:
+			return
+
+		case chunk, ok := <-audio:
+			if !ok {
+				// Azure ends the turn once it has sent the remaining results
+				s.conn.SetReadDeadline(time.Now().Add(azureFinalWait))
+				if err := s.writeAudio(nil); err != nil {
+					s.conn.Close()
+				}
+				return
+			}
+			pcm, err := converter.convert(chunk)
+			if err != nil {
+				log.Printf("[Azure] Failed to convert audio: %v", err)
+				continue
+			}
+			err = s.writeAudio(pcm)
+			telephony.ReleaseAudioBuffer(pcm)
+			if err != nil {
+				log.Printf("[Azure] Failed to send audio: %v", err)
+				s.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// writeAudio sends an audio message: a length-prefixed header, then the
+// audio
+func (s *azureStream) writeAudio(audio []byte) error {
+	header := s.headers("audio", "audio/x-wav")
+	message := make([]byte, 2, 2+len(header)+len(audio))
+	binary.BigEndian.PutUint16(message, uint16(len(header)))
+	message = append(message, header...)
+	message = append(message, audio...)
+	return s.conn.WriteMessage(websocket.BinaryMessage, message)
+}
+
+// azureContext is a speech.context message, carrying a phrase list
+type azureContext struct {
+	DGI struct {
+		Groups []azurePhraseGroup `json:"Groups"`
+	} `json:"dgi"`
+}
+
+type azurePhraseGroup struct {
+	Type  string            `json:"Type"`
+	Items []azurePhraseItem `json:"Items"`
+}
+
+type azurePhraseItem struct {
+	Text string `json:"Text"`
+}
+
+// writeContext sends the keywords as a phrase list
+func (s *azureStream) writeContext() error {
+	group := azurePhraseGroup{Type: "Generic"}
+	for _, keyword := range s.config.Keywords {
+		group.Items = append(group.Items, azurePhraseItem{Text: keyword})
+	}
+	var message azureContext
+	message.DGI.Groups = []azurePhraseGroup{group}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	text := s.headers("speech.context", "application/json") + "\r\n" + string(body)
+	return s.conn.WriteMessage(websocket.TextMessage, []byte(text))
+}
+
+// headers formats a message's headers
+func (s *azureStream) headers(path, contentType string) string {
+	return "Path: " + path + "\r\n" +
+		"X-RequestId: " + s.requestID + "\r\n" +
+		"X-Timestamp: " + time.Now().UTC().Format("2006-01-02T15:04:05.000Z") + "\r\n" +
+		"Content-Type: " + contentType + "\r\n"
+}
+
+// azureHypothesis is a speech.hypothesis message: an interim result.
+// Offsets and durations are in 100ns ticks.
+type azureHypothesis struct {
+	Text     string `json:"Text"`
+	Offset   int64  `json:"Offset"`
+	Duration int64  `json:"Duration"`
+}
+
+// azurePhrase is a speech.phrase message: a final result
+type azurePhrase struct {
+	RecognitionStatus string `json:"RecognitionStatus"`
+	Offset            int64  `json:"Offset"`
+	Duration          int64  `json:"Duration"`
+	NBest             []struct {
+		Confidence float64 `json:"Confidence"`
+		Lexical    string  `json:"Lexical"`
+		Display    string  `json:"Display"`
+		Words      []struct {
+			Word       string  `json:"Word"`
+			Offset     int64   `json:"Offset"`
+			Duration   int64   `json:"Duration"`
+			Confidence float64 `json:"Confidence"`
+		} `json:"Words"`
+	} `json:"NBest"`
+}
+
+// receive delivers results until the turn ends
+func (s *azureStream) receive(ctx context.Context, results chan<- Result, done chan<- struct{}) {
+	defer close(results)
+	defer close(done)
+	defer s.conn.Close()
+
+	deliver := func(result Result) bool {
+		select {
+		case results <- result:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		kind, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() == nil && !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				deliver(Result{Err: fmt.Errorf("azure stream failed: %w", err)})
+			}
+			return
+		}
+		if kind != websocket.TextMessage {
+			continue
+		}
+
+		path, body := parseAzureMessage(data)
+		switch path {
+		case "speech.hypothesis":
+			if !s.config.Interim {
+				continue
+			}
+			var hypothesis azureHypothesis
+			if err := json.Unmarshal(body, &hypothesis); err != nil || hypothesis.Text == "" {
+				continue
+			}
+			if !deliver(Result{
+				Transcript: hypothesis.Text,
+				Start:      ticks(hypothesis.Offset),
+				Duration:   ticks(hypothesis.Duration),
+			}) {
+				return
+			}
+
+		case "speech.phrase":
+			var phrase azurePhrase
+			if err := json.Unmarshal(body, &phrase); err != nil {
+				log.Printf("[Azure] Ignoring malformed phrase: %v", err)
+				continue
+			}
+			if phrase.RecognitionStatus == "Error" {
+				deliver(Result{Err: fmt.Errorf("azure recognition error")})
+				return
+			}
+			if result, ok := phrase.result(s.config.Punctuate); ok && !deliver(result) {
+				return
+			}
+
+		case "turn.end":
+			return
+		}
+	}
+}
+
+// parseAzureMessage splits a text message into its path and body
+func parseAzureMessage(data []byte) (string, []byte) {
+	headers, body, _ := bytes.Cut(data, []byte("\r\n\r\n"))
+	for _, line := range strings.Split(string(headers), "\r\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Path") {
+			return strings.ToLower(strings.TrimSpace(value)), body
+		}
+	}
+	return "", body
+}
+
+// result converts a recognized phrase; other statuses (no match, silence
+// timeouts) have nothing to say
+func (p *azurePhrase) result(punctuate bool) (Result, bool) {
+	if p.RecognitionStatus != "Success" || len(p.NBest) == 0 {
+		return Result{}, false
+	}
+	best := p.NBest[0]
+	result := Result{
+		Transcript:  best.Lexical,
+		Final:       true,
+		EndOfSpeech: true,
+		Confidence:  best.Confidence,
+		Start:       ticks(p.Offset),
+		Duration:    ticks(p.Duration),
+	}
+	if punctuate {
+		result.Transcript = best.Display
+	}
+	for _, w := range best.Words {
+		result.Words = append(result.Words, Word{
+			Word:       w.Word,
+			Start:      ticks(w.Offset),
+			End:        ticks(w.Offset + w.Duration),
+			Confidence: w.Confidence,
+		})
+	}
+	return result, result.Transcript != ""
+}
+
+// ticks converts an Azure time in 100ns units
+func ticks(t int64) time.Duration {
+	return time.Duration(t * 100)
+}
@@ -0,0 +1,263 @@
+package stt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// DEEPGRAM
+// Live transcription over Deepgram's streaming WebSocket API
+// ============================================
+
+const (
+	DeepgramURL = "wss://api.deepgram.com/v1/listen"
+
+	deepgramKeepAlive  = 5 * time.Second  // Deepgram closes streams idle for 10s, e.g. under silence suppression
+	deepgramFinalWait  = 10 * time.Second // For the last results once audio ends
+	deepgramBufferSize = 64
+)
+
+// Deepgram is a Provider using Deepgram's live transcription API
+type Deepgram struct {
+	apiKey string
+	url    string
+	dialer *websocket.Dialer
+}
+
+// NewDeepgram creates a Deepgram provider authenticating with apiKey
+func NewDeepgram(apiKey string) *Deepgram {
+	return &Deepgram{
+		apiKey: apiKey,
+		url:    DeepgramURL,
+		dialer: websocket.DefaultDialer,
+	}
+}
+
+// SetURL replaces the streaming endpoint, e.g. for a regional or
+// self-hosted deployment
+func (d *Deepgram) SetURL(url string) {
+	d.url = url
+}
+
+// SetDialer replaces the WebSocket dialer, e.g. one going through a proxy
+// (see signalwire.TransportConfig.WebSocketDialer)
+func (d *Deepgram) SetDialer(dialer *websocket.Dialer) {
+	d.dialer = dialer
+}
+
+// Recognize streams audio to Deepgram. If the connection fails part way,
+// the last result carries the error and audio is no longer read.
+func (d *Deepgram) Recognize(ctx context.Context, audio <-chan []byte, config Config) (<-chan Result, error) {
+	query, err := deepgramQuery(config)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Token "+d.apiKey)
+
+	conn, resp, err := d.dialer.DialContext(ctx, d.url+"?"+query.Encode(), header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("failed to connect to Deepgram (%s): %w", resp.Status, err)
+		}
+		return nil, fmt.Errorf("failed to connect to Deepgram: %w", err)
+	}
+
+	results := make(chan Result, deepgramBufferSize)
+	done := make(chan struct{})
+	go d.send(ctx, conn, audio, done)
+	go d.receive(ctx, conn, results, done)
+	return results, nil
+}
+
+// deepgramQuery builds the stream's query parameters
+func deepgramQuery(config Config) (url.Values, error) {
+	query := url.Values{}
+	switch {
+	case config.Format == telephony.AudioFormatMulaw:
+		query.Set("encoding", "mulaw")
+	case config.Format.Encoding == telephony.AudioFormatPCM.Encoding && config.Format.BitDepth == 16 && config.Format.Channels == 1:
+		query.Set("encoding", "linear16")
+	default:
+		return nil, fmt.Errorf("unsupported Deepgram audio format: %+v", config.Format)
+	}
+	query.Set("sample_rate", strconv.Itoa(config.Format.SampleRate))
+	query.Set("channels", "1")
+
+	if config.Language != "" {
+		query.Set("language", config.Language)
+	}
+	if config.Model != "" {
+		query.Set("model", config.Model)
+	}
+	query.Set("interim_results", strconv.FormatBool(config.Interim))
+	query.Set("punctuate", strconv.FormatBool(config.Punctuate))
+	if config.Endpointing > 0 {
+		query.Set("endpointing", strconv.FormatInt(config.Endpointing.Milliseconds(), 10))
+	}
+	for _, keyword := range config.Keywords {
+		query.Add("keywords", keyword)
+	}
+	return query, nil
+}
+
+// send writes audio to the stream until it ends, keeping the stream open
+// through pauses in the audio
+func (d *Deepgram) send(ctx context.Context, conn *websocket.Conn, audio <-chan []byte, done <-chan struct{}) {
+	keepAlive := time.NewTicker(deepgramKeepAlive)
+	defer keepAlive.Stop()
+	sent := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			return
+
+		case <-done:
+			return
+
+		case chunk, ok := <-audio:
+			if !ok {
+				// Ask for the remaining results; Deepgram closes the stream
+				// once they're sent
+				conn.SetReadDeadline(time.Now().Add(deepgramFinalWait))
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"CloseStream"}`)); err != nil {
+					conn.Close()
+				}
+				return
+			}
+			err := conn.WriteMessage(websocket.BinaryMessage, chunk)
+			telephony.ReleaseAudioBuffer(chunk)
+			if err != nil {
+				log.Printf("[Deepgram] Failed to send audio: %v", err)
+				conn.Close()
+				return
+			}
+			sent = true
+
+		case <-keepAlive.C:
+			if !sent {
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"KeepAlive"}`)); err != nil {
+					conn.Close()
+					return
+				}
+			}
+			sent = false
+		}
+	}
+}
+
+// deepgramMessage is a message from the stream; only Results messages
+// carry transcripts
+type deepgramMessage struct {
+	Type        string  `json:"type"`
+	Start       float64 `json:"start"`
+	Duration    float64 `json:"duration"`
+	IsFinal     bool    `json:"is_final"`
+	SpeechFinal bool    `json:"speech_final"`
+	Channel     struct {
+		Alternatives []struct {
+			Transcript string  `json:"transcript"`
+			Confidence float64 `json:"confidence"`
+			Words      []struct {
+				Word           string  `json:"word"`
+				PunctuatedWord string  `json:"punctuated_word"`
+				Start          float64 `json:"start"`
+				End            float64 `json:"end"`
+				Confidence     float64 `json:"confidence"`
+			} `json:"words"`
+		} `json:"alternatives"`
+	} `json:"channel"`
+	Description string `json:"description"` // Error messages
+}
+
+// receive delivers results until the stream closes
+func (d *Deepgram) receive(ctx context.Context, conn *websocket.Conn, results chan<- Result, done chan<- struct{}) {
+	defer close(results)
+	defer close(done)
+	defer conn.Close()
+
+	deliver := func(result Result) bool {
+		select {
+		case results <- result:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() == nil && !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				deliver(Result{Err: fmt.Errorf("deepgram stream failed: %w", err)})
+			}
+			return
+		}
+
+		var msg deepgramMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			log.Printf("[Deepgram] Ignoring malformed message: %v", err)
+			continue
+		}
+		switch msg.Type {
+		case "Results":
+			if result, ok := msg.result(); ok && !deliver(result) {
+				return
+			}
+		case "Error":
+			deliver(Result{Err: fmt.Errorf("deepgram error: %s", msg.Description)})
+			return
+		}
+	}
+}
+
+// result converts a Results message, skipping those with nothing to say
+func (msg *deepgramMessage) result() (Result, bool) {
+	if len(msg.Channel.Alternatives) == 0 {
+		return Result{}, false
+	}
+	best := msg.Channel.Alternatives[0]
+	if best.Transcript == "" && !msg.SpeechFinal {
+		return Result{}, false
+	}
+
+	result := Result{
+		Transcript:  best.Transcript,
+		Final:       msg.IsFinal,
+		EndOfSpeech: msg.SpeechFinal,
+		Confidence:  best.Confidence,
+		Start:       seconds(msg.Start),
+		Duration:    seconds(msg.Duration),
+	}
+	for _, w := range best.Words {
+		word := w.PunctuatedWord
+		if word == "" {
+			word = w.Word
+		}
+		result.Words = append(result.Words, Word{
+			Word:       word,
+			Start:      seconds(w.Start),
+			End:        seconds(w.End),
+			Confidence: w.Confidence,
+		})
+	}
+	return result, true
+}
+
+// seconds converts a Deepgram timestamp
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
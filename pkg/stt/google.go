@@ -0,0 +1,225 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// GOOGLE CLOUD SPEECH-TO-TEXT
+// Google's REST recognize API, fed one utterance at a time
+// ============================================
+
+const (
+	GoogleSpeechURL = "https://speech.googleapis.com/v1/speech:recognize"
+
+	googleTimeout  = 30 * time.Second
+	googleLanguage = "en-US" // Google requires a language
+)
+
+// Google is a Provider using Google Cloud Speech-to-Text. Its streaming
+// API is gRPC only, so like Whisper the REST API is given one utterance at
+// a time, cut from the audio at pauses: results are final, and arrive
+// shortly after the caller stops speaking. Config.Model picks a Google
+// model, e.g. "phone_call" or "telephony".
+type Google struct {
+	url        string
+	apiKey     string
+	token      func(ctx context.Context) (string, error)
+	client     *http.Client
+	vad        telephony.VADConfig
+	maxSegment time.Duration
+}
+
+// NewGoogle creates a provider authenticating with an API key; see
+// SetTokenSource for OAuth
+func NewGoogle(apiKey string) *Google {
+	return &Google{
+		url:    GoogleSpeechURL,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: googleTimeout},
+		vad:    telephony.DefaultVADConfig(),
+	}
+}
+
+// SetTokenSource authenticates with OAuth access tokens instead of an API
+// key, e.g. a service account's from golang.org/x/oauth2/google
+func (g *Google) SetTokenSource(token func(ctx context.Context) (string, error)) {
+	g.token = token
+}
+
+// SetURL replaces the recognize endpoint, e.g. a regional one
+func (g *Google) SetURL(url string) {
+	g.url = url
+}
+
+// SetHTTPClient replaces the HTTP client, e.g. for a proxy or timeout
+func (g *Google) SetHTTPClient(client *http.Client) {
+	g.client = client
+}
+
+// SetVAD tunes how speech is detected to find utterances
+func (g *Google) SetVAD(config telephony.VADConfig) {
+	g.vad = config
+}
+
+// SetMaxSegment limits how much speech goes into one request (default
+// 25s; Google takes up to a minute)
+func (g *Google) SetMaxSegment(d time.Duration) {
+	g.maxSegment = d
+}
+
+// Recognize transcribes each utterance in audio. A request that fails is
+// logged and its utterance skipped, unless Google rejects the request
+// outright (e.g. a bad key), which ends recognition.
+func (g *Google) Recognize(ctx context.Context, audio <-chan []byte, config Config) (<-chan Result, error) {
+	recognizer := clipRecognizer{
+		name:       "Google",
+		vad:        g.vad,
+		maxSegment: g.maxSegment,
+		transcribe: g.transcribe,
+	}
+	return recognizer.recognize(ctx, audio, config)
+}
+
+// googleRequest is a recognize request
+type googleRequest struct {
+	Config struct {
+		Encoding                   string                `json:"encoding"`
+		SampleRateHertz            int                   `json:"sampleRateHertz"`
+		LanguageCode               string                `json:"languageCode"`
+		Model                      string                `json:"model,omitempty"`
+		EnableAutomaticPunctuation bool                  `json:"enableAutomaticPunctuation"`
+		EnableWordTimeOffsets      bool                  `json:"enableWordTimeOffsets"`
+		EnableWordConfidence       bool                  `json:"enableWordConfidence"`
+		SpeechContexts             []googleSpeechContext `json:"speechContexts,omitempty"`
+	} `json:"config"`
+	Audio struct {
+		Content []byte `json:"content"`
+	} `json:"audio"`
+}
+
+// googleSpeechContext lists phrases to favour
+type googleSpeechContext struct {
+	Phrases []string `json:"phrases"`
+}
+
+// googleResponse is a recognize response; times are durations like "1.5s"
+type googleResponse struct {
+	Results []struct {
+		Alternatives []struct {
+			Transcript string  `json:"transcript"`
+			Confidence float64 `json:"confidence"`
+			Words      []struct {
+				Word       string  `json:"word"`
+				StartTime  string  `json:"startTime"`
+				EndTime    string  `json:"endTime"`
+				Confidence float64 `json:"confidence"`
+			} `json:"words"`
+		} `json:"alternatives"`
+	} `json:"results"`
+}
+
+// transcribe sends one utterance
+func (g *Google) transcribe(ctx context.Context, seg segment, config Config) (Result, error) {
+	var request googleRequest
+	request.Config.Encoding = "LINEAR16"
+	request.Config.SampleRateHertz = telephony.AudioFormatPCM.SampleRate
+	request.Config.LanguageCode = config.Language
+	if request.Config.LanguageCode == "" {
+		request.Config.LanguageCode = googleLanguage
+	}
+	request.Config.Model = config.Model
+	request.Config.EnableAutomaticPunctuation = config.Punctuate
+	request.Config.EnableWordTimeOffsets = true
+	request.Config.EnableWordConfidence = true
+	if len(config.Keywords) > 0 {
+		request.Config.SpeechContexts = []googleSpeechContext{{Phrases: config.Keywords}}
+	}
+	request.Audio.Content = seg.audio
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return Result{}, err
+	}
+	endpoint := g.url
+	if g.token == nil {
+		endpoint += "?key=" + url.QueryEscape(g.apiKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.token != nil {
+		token, err := g.token(ctx)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to get Google access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("google request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read google response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, &statusError{service: "google", status: resp.StatusCode, message: strings.TrimSpace(string(data))}
+	}
+
+	var parsed googleResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return Result{}, fmt.Errorf("failed to parse google response: %w", err)
+	}
+	return parsed.result(seg), nil
+}
+
+// result joins the response's consecutive results into one for the
+// utterance, placing word timings in the stream
+func (r *googleResponse) result(seg segment) Result {
+	result := Result{
+		Final:       true,
+		EndOfSpeech: seg.endOfSpeech,
+		Start:       seg.start,
+		Duration:    byteDuration(int64(len(seg.audio))),
+	}
+
+	var transcripts []string
+	for _, part := range r.Results {
+		if len(part.Alternatives) == 0 {
+			continue
+		}
+		best := part.Alternatives[0]
+		transcripts = append(transcripts, strings.TrimSpace(best.Transcript))
+		result.Confidence += best.Confidence
+		for _, w := range best.Words {
+			start, _ := time.ParseDuration(w.StartTime)
+			end, _ := time.ParseDuration(w.EndTime)
+			result.Words = append(result.Words, Word{
+				Word:       w.Word,
+				Start:      seg.start + start,
+				End:        seg.start + end,
+				Confidence: w.Confidence,
+			})
+		}
+	}
+	if len(transcripts) > 0 {
+		result.Transcript = strings.Join(transcripts, " ")
+		result.Confidence /= float64(len(transcripts))
+	}
+	return result
+}
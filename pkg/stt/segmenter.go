@@ -0,0 +1,258 @@
+package stt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// SEGMENTATION
+// Cutting a live stream into utterances for recognizers that transcribe
+// whole clips
+// ============================================
+
+const (
+	segmentPreRoll    = 300 * time.Millisecond // Audio kept from before speech starts
+	segmentHistory    = time.Second            // Audio held outside speech, covering VAD's delay in reporting it
+	segmentTail       = 200 * time.Millisecond // Audio kept after speech ends
+	defaultMaxSegment = 25 * time.Second       // Whisper transcribes up to 30s at a time
+	segmentBytesPerS  = 32000                  // 16kHz 16-bit mono
+	clipQueueLength   = 16
+)
+
+// segment is one utterance of 16kHz PCM
+type segment struct {
+	audio       []byte
+	start       time.Duration // Offset from the start of the stream
+	endOfSpeech bool          // False when cut short at the maximum length
+}
+
+// segmenter collects 16kHz PCM into utterances, from just before speech
+// starts to just after it ends. Audio outside speech is discarded.
+type segmenter struct {
+	vad      *telephony.VAD
+	audio    []byte
+	start    int64 // Stream byte offset of audio[0]
+	speaking bool
+	maxBytes int
+}
+
+func newSegmenter(vad *telephony.VAD, maxSegment time.Duration) *segmenter {
+	if maxSegment <= 0 {
+		maxSegment = defaultMaxSegment
+	}
+	return &segmenter{
+		vad:      vad,
+		maxBytes: durationBytes(maxSegment),
+	}
+}
+
+// push adds audio, returning the utterances it completes
+func (s *segmenter) push(pcm []byte) ([]segment, error) {
+	result, err := s.vad.Process(pcm)
+	if err != nil {
+		return nil, err
+	}
+	s.audio = append(s.audio, pcm...)
+
+	var segments []segment
+	for _, event := range result.Events {
+		if event.Speaking {
+			s.speaking = true
+			start := int64(durationBytes(max(event.Offset-segmentPreRoll, 0))) - s.start
+			s.discard(int(min(max(start, 0), int64(len(s.audio)))))
+			continue
+		}
+		s.speaking = false
+		end := int64(durationBytes(event.Offset+segmentTail)) - s.start
+		segments = append(segments, s.cut(int(min(max(end, 0), int64(len(s.audio)))), true))
+	}
+	if s.speaking && len(s.audio) >= s.maxBytes {
+		segments = append(segments, s.cut(len(s.audio), false))
+	}
+
+	if !s.speaking {
+		if excess := len(s.audio) - durationBytes(segmentHistory); excess > 0 {
+			s.discard(excess)
+		}
+	}
+	return segments, nil
+}
+
+// flush returns the utterance in progress when the stream ends
+func (s *segmenter) flush() (segment, bool) {
+	if !s.speaking || len(s.audio) == 0 {
+		return segment{}, false
+	}
+	s.speaking = false
+	return s.cut(len(s.audio), true), true
+}
+
+// cut takes the first n bytes of audio as a segment
+func (s *segmenter) cut(n int, endOfSpeech bool) segment {
+	n &^= 1
+	seg := segment{
+		audio:       append([]byte(nil), s.audio[:n]...),
+		start:       byteDuration(s.start),
+		endOfSpeech: endOfSpeech,
+	}
+	s.discard(n)
+	return seg
+}
+
+// discard drops the first n bytes of audio
+func (s *segmenter) discard(n int) {
+	n &^= 1
+	s.audio = append(s.audio[:0], s.audio[n:]...)
+	s.start += int64(n)
+}
+
+// durationBytes is the length of d of audio, in whole samples
+func durationBytes(d time.Duration) int {
+	return int(int64(d)*segmentBytesPerS/int64(time.Second)) &^ 1
+}
+
+// byteDuration is the duration of n bytes of audio
+func byteDuration(n int64) time.Duration {
+	return time.Duration(n * int64(time.Second) / segmentBytesPerS)
+}
+
+// clipRecognizer runs recognition for services that transcribe whole clips
+// rather than streams, sending them one utterance at a time, in order
+type clipRecognizer struct {
+	name       string // Service name for logs and errors
+	vad        telephony.VADConfig
+	maxSegment time.Duration
+	transcribe func(ctx context.Context, seg segment, config Config) (Result, error)
+}
+
+// recognize cuts audio into utterances and transcribes each as it ends.
+// Endpointing sets the pause that ends an utterance.
+func (r clipRecognizer) recognize(ctx context.Context, audio <-chan []byte, config Config) (<-chan Result, error) {
+	if err := checkFormat(r.name, config.Format); err != nil {
+		return nil, err
+	}
+	vadConfig := r.vad
+	if config.Endpointing > 0 {
+		vadConfig.SpeechEnd = config.Endpointing
+	}
+	vad, err := telephony.NewVAD(telephony.AudioFormatPCM, vadConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	segments := make(chan segment, clipQueueLength)
+	results := make(chan Result, clipQueueLength)
+	go r.segment(ctx, audio, config.Format, newSegmenter(vad, r.maxSegment), segments)
+	go r.transcribeSegments(ctx, cancel, segments, config, results)
+	return results, nil
+}
+
+// segment converts audio to 16kHz PCM and cuts it into utterances
+func (r clipRecognizer) segment(ctx context.Context, audio <-chan []byte, format telephony.AudioFormat, segmenter *segmenter, segments chan<- segment) {
+	defer close(segments)
+	defer segmenter.vad.Close()
+
+	converter := newPCMConverter(format)
+	send := func(seg segment) bool {
+		select {
+		case segments <- seg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case chunk, ok := <-audio:
+			if !ok {
+				if seg, ok := segmenter.flush(); ok {
+					send(seg)
+				}
+				return
+			}
+
+			pcm, err := converter.convert(chunk)
+			if err != nil {
+				log.Printf("[%s] Failed to convert audio: %v", r.name, err)
+				continue
+			}
+			completed, err := segmenter.push(pcm)
+			telephony.ReleaseAudioBuffer(pcm)
+			if err != nil {
+				log.Printf("[%s] VAD failed: %v", r.name, err)
+				continue
+			}
+			for _, seg := range completed {
+				if !send(seg) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// transcribeSegments transcribes utterances in order, delivering their
+// results
+func (r clipRecognizer) transcribeSegments(ctx context.Context, cancel context.CancelFunc, segments <-chan segment, config Config, results chan<- Result) {
+	defer close(results)
+	defer cancel()
+
+	deliver := func(result Result) bool {
+		select {
+		case results <- result:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for seg := range segments {
+		result, err := r.transcribe(ctx, seg, config)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			var status *statusError
+			if errors.As(err, &status) && status.permanent() {
+				deliver(Result{Err: err})
+				return
+			}
+			log.Printf("[%s] Skipping %v of audio at %v: %v", r.name, byteDuration(int64(len(seg.audio))), seg.start, err)
+			continue
+		}
+		if result.Transcript == "" && !seg.endOfSpeech {
+			continue
+		}
+		if !deliver(result) {
+			return
+		}
+	}
+}
+
+// statusError is a transcription request the service refused
+type statusError struct {
+	service string
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s request failed (%d): %s", e.service, e.status, e.message)
+}
+
+// permanent reports whether retrying can't help, e.g. a bad API key
+func (e *statusError) permanent() bool {
+	return e.status >= 400 && e.status < 500 && e.status != http.StatusRequestTimeout && e.status != http.StatusTooManyRequests
+}
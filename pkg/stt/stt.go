@@ -0,0 +1,120 @@
+package stt
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// SPEECH TO TEXT
+// Streaming transcription of call audio, behind a provider interface
+// ============================================
+
+// Provider transcribes a stream of audio as it arrives
+type Provider interface {
+	// Recognize sends audio to the recognizer until the channel closes or
+	// ctx is done, delivering results as they come. Chunks are the
+	// provider's once received; it hands them to telephony.ReleaseAudioBuffer
+	// when done. The results channel closes once the recognizer has
+	// returned its last result.
+	Recognize(ctx context.Context, audio <-chan []byte, config Config) (<-chan Result, error)
+}
+
+// Config describes the audio and how to recognize it
+type Config struct {
+	Format      telephony.AudioFormat // Mulaw at 8kHz, or mono 16-bit PCM
+	Language    string                // BCP-47 tag, e.g. "en-US"; empty uses the provider's default
+	Model       string                // Provider-specific model name
+	Interim     bool                  // Deliver interim results while speech is in progress
+	Punctuate   bool
+	Endpointing time.Duration // Silence that ends an utterance; zero uses the provider's default
+	Keywords    []string      // Words to favour, e.g. product names
+}
+
+// Result is a transcript of a stretch of audio. Interim results cover the
+// speech so far and are replaced by later ones; a final result's audio is
+// done and won't be transcribed again.
+type Result struct {
+	Transcript  string
+	Final       bool
+	EndOfSpeech bool          // The speaker has finished the utterance (final results only)
+	Confidence  float64       // 0-1
+	Start       time.Duration // Offset of the audio from the start of the stream
+	Duration    time.Duration
+	Words       []Word
+	Err         error // Set on the last result when recognition failed
+}
+
+// Word is one recognized word with its timing
+type Word struct {
+	Word       string
+	Start      time.Duration // Offset from the start of the stream
+	End        time.Duration
+	Confidence float64
+}
+
+// RecognizeSession transcribes a bridge session's caller audio from its
+// phone → AI channel. config.Format must match the input format declared
+// with SetAIFormat; a zero format declares AudioFormatPCM.
+func RecognizeSession(ctx context.Context, provider Provider, bridge *telephony.AudioStreamBridge, sessionID string, config Config) (<-chan Result, error) {
+	if config.Format == (telephony.AudioFormat{}) {
+		_, output, err := bridge.AIFormat(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		config.Format = telephony.AudioFormatPCM
+		if err := bridge.SetAIFormat(sessionID, config.Format, output); err != nil {
+			return nil, err
+		}
+	}
+	audio, err := bridge.GetPhoneToAIChannel(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := provider.Recognize(ctx, audio, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start recognition for session %s: %w", sessionID, err)
+	}
+	return results, nil
+}
+
+// checkFormat validates the audio format of a recognizer that takes mulaw
+// or mono 16-bit PCM
+func checkFormat(provider string, format telephony.AudioFormat) error {
+	if format == telephony.AudioFormatMulaw {
+		return nil
+	}
+	if format.Encoding != telephony.AudioFormatPCM.Encoding || format.BitDepth != 16 || format.Channels != 1 || format.SampleRate <= 0 {
+		return fmt.Errorf("unsupported %s audio format: %+v", provider, format)
+	}
+	return nil
+}
+
+// pcmConverter converts a stream to 16kHz PCM for recognizers that take
+// it, or gain from resampling narrower audio to it
+type pcmConverter struct {
+	format    telephony.AudioFormat
+	converter *telephony.AudioConverter
+}
+
+func newPCMConverter(format telephony.AudioFormat) *pcmConverter {
+	return &pcmConverter{
+		format:    format,
+		converter: telephony.NewAudioConverter(format.SampleRate, telephony.AudioFormatPCM.SampleRate, 1, 1),
+	}
+}
+
+// convert returns chunk as 16kHz PCM, which the caller then owns in place
+// of chunk
+func (c *pcmConverter) convert(chunk []byte) ([]byte, error) {
+	if c.format == telephony.AudioFormatPCM {
+		return chunk, nil
+	}
+	pcm, err := c.converter.ConvertAudio(chunk, c.format, telephony.AudioFormatPCM)
+	telephony.ReleaseAudioBuffer(chunk)
+	return pcm, err
+}
@@ -0,0 +1,228 @@
+package stt
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// WHISPER
+// OpenAI's Whisper API and local whisper.cpp servers, fed one utterance at
+// a time
+// ============================================
+
+const (
+	WhisperAPIURL = "https://api.openai.com/v1/audio/transcriptions"
+
+	whisperAPIModel = "whisper-1"
+	whisperTimeout  = 30 * time.Second
+)
+
+// Whisper is a Provider transcribing with OpenAI's Whisper API or a
+// whisper.cpp server. Whisper transcribes whole clips rather than streams,
+// so caller audio is cut into utterances at pauses (by VAD) and each is
+// transcribed once it ends: results are all final, and arrive shortly
+// after the caller stops speaking. Config.Interim and Punctuate don't
+// apply; Endpointing sets the pause that ends an utterance.
+type Whisper struct {
+	url        string
+	apiKey     string
+	model      string
+	openAI     bool
+	client     *http.Client
+	vad        telephony.VADConfig
+	maxSegment time.Duration
+}
+
+// NewWhisperAPI creates a provider using OpenAI's transcription API
+func NewWhisperAPI(apiKey string) *Whisper {
+	return &Whisper{
+		url:    WhisperAPIURL,
+		apiKey: apiKey,
+		model:  whisperAPIModel,
+		openAI: true,
+		client: &http.Client{Timeout: whisperTimeout},
+		vad:    telephony.DefaultVADConfig(),
+	}
+}
+
+// NewWhisperServer creates a provider using a whisper.cpp server at
+// baseURL (e.g. http://localhost:8080), which must be running with a model
+// loaded
+func NewWhisperServer(baseURL string) *Whisper {
+	return &Whisper{
+		url:    strings.TrimRight(baseURL, "/") + "/inference",
+		client: &http.Client{Timeout: whisperTimeout},
+		vad:    telephony.DefaultVADConfig(),
+	}
+}
+
+// SetModel selects the model; Config.Model overrides it per stream.
+// whisper.cpp servers use the model they were started with.
+func (w *Whisper) SetModel(model string) {
+	w.model = model
+}
+
+// SetHTTPClient replaces the HTTP client, e.g. for a proxy or timeout
+func (w *Whisper) SetHTTPClient(client *http.Client) {
+	w.client = client
+}
+
+// SetVAD tunes how speech is detected to find utterances
+func (w *Whisper) SetVAD(config telephony.VADConfig) {
+	w.vad = config
+}
+
+// SetMaxSegment limits how much speech goes into one request (default
+// 25s); longer utterances are transcribed in parts
+func (w *Whisper) SetMaxSegment(d time.Duration) {
+	w.maxSegment = d
+}
+
+// Recognize transcribes each utterance in audio. A request that fails is
+// logged and its utterance skipped, unless the server rejects the request
+// outright (e.g. a bad API key), which ends recognition.
+func (w *Whisper) Recognize(ctx context.Context, audio <-chan []byte, config Config) (<-chan Result, error) {
+	recognizer := clipRecognizer{
+		name:       "Whisper",
+		vad:        w.vad,
+		maxSegment: w.maxSegment,
+		transcribe: w.transcribe,
+	}
+	return recognizer.recognize(ctx, audio, config)
+}
+
+// whisperResponse is the verbose_json response of both the OpenAI API
+// (words at the top level) and whisper.cpp (words in each segment)
+type whisperResponse struct {
+	Text     string        `json:"text"`
+	Words    []whisperWord `json:"words"`
+	Segments []struct {
+		AvgLogprob float64       `json:"avg_logprob"`
+		Words      []whisperWord `json:"words"`
+	} `json:"segments"`
+}
+
+type whisperWord struct {
+	Word        string   `json:"word"`
+	Start       float64  `json:"start"`
+	End         float64  `json:"end"`
+	Probability *float64 `json:"probability"`
+}
+
+// transcribe sends one utterance as a WAV file
+func (w *Whisper) transcribe(ctx context.Context, seg segment, config Config) (Result, error) {
+	wav, err := telephony.EncodeWAV(seg.audio, telephony.AudioFormatPCM)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", "audio.wav")
+	if err != nil {
+		return Result{}, err
+	}
+	file.Write(wav)
+
+	fields := map[string]string{
+		"response_format": "verbose_json",
+		"temperature":     "0",
+	}
+	if model := cmp.Or(config.Model, w.model); model != "" {
+		fields["model"] = model
+	}
+	if config.Language != "" {
+		// Whisper takes ISO-639-1 codes: "en", not "en-US"
+		language, _, _ := strings.Cut(config.Language, "-")
+		fields["language"] = strings.ToLower(language)
+	}
+	if len(config.Keywords) > 0 {
+		fields["prompt"] = strings.Join(config.Keywords, ", ")
+	}
+	for name, value := range fields {
+		form.WriteField(name, value)
+	}
+	if w.openAI {
+		form.WriteField("timestamp_granularities[]", "word")
+		form.WriteField("timestamp_granularities[]", "segment")
+	}
+	if err := form.Close(); err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, &body)
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if w.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+w.apiKey)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("whisper request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read whisper response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, &statusError{service: "whisper", status: resp.StatusCode, message: strings.TrimSpace(string(data))}
+	}
+
+	var parsed whisperResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return Result{}, fmt.Errorf("failed to parse whisper response: %w", err)
+	}
+	return parsed.result(seg), nil
+}
+
+// result converts a response for an utterance, placing its timings in the
+// stream
+func (r *whisperResponse) result(seg segment) Result {
+	result := Result{
+		Transcript:  strings.TrimSpace(r.Text),
+		Final:       true,
+		EndOfSpeech: seg.endOfSpeech,
+		Start:       seg.start,
+		Duration:    byteDuration(int64(len(seg.audio))),
+	}
+
+	// Confidence from the segments' mean token log probability
+	words := r.Words
+	for _, s := range r.Segments {
+		result.Confidence += math.Exp(s.AvgLogprob) / float64(len(r.Segments))
+		if len(r.Words) == 0 {
+			words = append(words, s.Words...)
+		}
+	}
+
+	for _, w := range words {
+		word := Word{
+			Word:  strings.TrimSpace(w.Word),
+			Start: seg.start + seconds(w.Start),
+			End:   seg.start + seconds(w.End),
+		}
+		if w.Probability != nil {
+			word.Confidence = *w.Probability
+		}
+		if word.Word != "" {
+			result.Words = append(result.Words, word)
+		}
+	}
+	return result
+}
@@ -0,0 +1,348 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+)
+
+// ============================================
+// ACOUSTIC ECHO CANCELLATION
+// Removes the AI's own TTS, echoed back by the caller's device, from
+// caller audio
+// ============================================
+
+const (
+	aecEnvelopeBlock    = 2 * time.Millisecond // Resolution of delay estimation
+	aecEstimateWindow   = 2 * time.Second      // Audio compared when estimating delay
+	aecEstimateInterval = time.Second          // How often the delay is re-estimated
+	aecMinCorrelation   = 0.4                  // Envelope correlation that counts as echo
+	aecDelayMargin      = 4 * time.Millisecond // Taps placed before the estimated delay
+	aecGeigelRatio      = 0.5                  // Near/far peak ratio signalling double talk
+	aecDoubleTalkHold   = 30 * time.Millisecond
+	aecResidualGain     = 0.25  // Residual suppression while only the AI talks (-12dB)
+	aecMaxQueued        = 10    // Seconds of reference audio held for playout
+	aecRegularization   = 100.0 // Reference amplitude the NLMS step is regularized to, so quiet reference tails don't mis-adapt the filter
+	aecFarActiveLevel   = 100.0 // Reference peak above which the AI counts as talking
+)
+
+// AECConfig tunes the echo canceller
+type AECConfig struct {
+	// Echo tail modelled after the round-trip delay (default 64ms)
+	FilterLength time.Duration `json:"filter_length"`
+	// Longest round trip searched for the echo (default 500ms)
+	MaxDelay time.Duration `json:"max_delay"`
+	// NLMS adaptation step, 0-1 (default 0.3)
+	StepSize float64 `json:"step_size"`
+	// Attenuate what echo remains while only the AI is talking
+	SuppressResidual bool `json:"suppress_residual"`
+}
+
+// DefaultAECConfig returns the default echo canceller settings
+func DefaultAECConfig() AECConfig {
+	return AECConfig{
+		FilterLength:     64 * time.Millisecond,
+		MaxDelay:         500 * time.Millisecond,
+		StepSize:         0.3,
+		SuppressResidual: true,
+	}
+}
+
+// EchoCanceller cancels echo of outbound (far-end) audio from inbound
+// (near-end) audio of the same call. Outbound audio passed to Reference is
+// assumed to play out in real time, in step with inbound audio; the
+// round-trip delay is found by cross-correlating the two, and an NLMS
+// adaptive filter models the echo path from there. It is safe for
+// concurrent use.
+type EchoCanceller struct {
+	config   AECConfig
+	format   AudioFormat
+	mulaw    bool
+	taps     int
+	maxLag   int
+	envBlock int
+
+	queue   []float64 // Reference audio not yet played
+	far     []float64 // Played reference; far[i] is at stream sample farBase+i
+	farBase int64
+	n       int64 // Near-end samples processed
+
+	delay   int // Bulk delay in samples, -1 until estimated
+	weights []float64
+	power   float64 // Energy of the reference window the filter sees
+	farPeak float64 // Decaying peak of the reference window
+	hold    int     // Samples left of adaptation freeze (double talk)
+
+	nearEnv, farEnv []float64 // Envelopes for delay estimation
+	nearAcc, farAcc float64
+	envCount        int
+	sinceEstimate   int
+
+	mu sync.Mutex
+}
+
+// NewEchoCanceller creates an echo canceller for audio in format (mulaw or
+// 16-bit PCM, mono; both directions), filling in zero config fields with
+// defaults
+func NewEchoCanceller(format AudioFormat, config AECConfig) *EchoCanceller {
+	defaults := DefaultAECConfig()
+	if config.FilterLength <= 0 {
+		config.FilterLength = defaults.FilterLength
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = defaults.MaxDelay
+	}
+	if config.StepSize <= 0 || config.StepSize > 1 {
+		config.StepSize = defaults.StepSize
+	}
+
+	samples := func(d time.Duration) int {
+		return int(int64(format.SampleRate) * int64(d) / int64(time.Second))
+	}
+	taps := samples(config.FilterLength)
+	return &EchoCanceller{
+		config:   config,
+		format:   format,
+		mulaw:    format.Encoding == AudioFormatMulaw.Encoding,
+		taps:     taps,
+		maxLag:   samples(config.MaxDelay),
+		envBlock: max(samples(aecEnvelopeBlock), 1),
+		delay:    -1,
+		weights:  make([]float64, taps),
+	}
+}
+
+// Reference queues outbound audio as it is sent toward the caller
+func (e *EchoCanceller) Reference(audio []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.queue = append(e.queue, e.decode(audio)...)
+	if limit := aecMaxQueued * e.format.SampleRate; len(e.queue) > limit {
+		e.queue = e.queue[len(e.queue)-limit:]
+	}
+}
+
+// Process returns inbound audio with the echo of the reference removed, in
+// the same format
+func (e *EchoCanceller) Process(audio []byte) []byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	near := e.decode(audio)
+	for i, d := range near {
+		near[i] = e.processSample(d)
+	}
+	e.trim()
+	return e.encode(near)
+}
+
+// Delay returns the estimated round-trip echo delay, or -1 before one has
+// been found
+func (e *EchoCanceller) Delay() time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.delay < 0 {
+		return -1
+	}
+	return time.Duration(e.delay) * time.Second / time.Duration(e.format.SampleRate)
+}
+
+// processSample advances the timeline by one sample and cancels echo from
+// the near-end sample d
+func (e *EchoCanceller) processSample(d float64) float64 {
+	// The reference plays out in step with inbound audio
+	x := 0.0
+	if len(e.queue) > 0 {
+		x = e.queue[0]
+		e.queue = e.queue[1:]
+	}
+	e.far = append(e.far, x)
+	e.track(d, x)
+
+	n := e.n
+	e.n++
+	if e.delay < 0 {
+		return d
+	}
+
+	// Slide the filter's reference window
+	entering := e.farAt(n - int64(e.delay))
+	leaving := e.farAt(n - int64(e.delay) - int64(e.taps))
+	e.power += entering*entering - leaving*leaving
+	if e.power < 0 {
+		e.power = 0
+	}
+	e.farPeak = math.Max(math.Abs(entering), e.farPeak*(1-1/float64(e.taps)))
+
+	// Nothing to cancel while the reference is silent
+	regularization := aecRegularization * aecRegularization * float64(e.taps)
+	if e.power < regularization/100 {
+		return d
+	}
+
+	// Echo estimate
+	start := n - int64(e.delay)
+	y := 0.0
+	for k, w := range e.weights {
+		y += w * e.farAt(start-int64(k))
+	}
+	residual := d - y
+
+	// Geigel double-talk detection: the caller is talking over the AI
+	if math.Abs(d) > aecGeigelRatio*e.farPeak {
+		e.hold = int(int64(e.format.SampleRate) * int64(aecDoubleTalkHold) / int64(time.Second))
+	}
+	if e.hold > 0 {
+		e.hold--
+		return residual
+	}
+
+	// NLMS update
+	step := e.config.StepSize * residual / (e.power + regularization)
+	for k := range e.weights {
+		e.weights[k] += step * e.farAt(start-int64(k))
+	}
+
+	if e.config.SuppressResidual && e.farPeak > aecFarActiveLevel {
+		residual *= aecResidualGain
+	}
+	return residual
+}
+
+// farAt returns the reference sample at a stream position (0 outside the
+// history)
+func (e *EchoCanceller) farAt(i int64) float64 {
+	i -= e.farBase
+	if i < 0 || i >= int64(len(e.far)) {
+		return 0
+	}
+	return e.far[i]
+}
+
+// track builds the envelopes and periodically re-estimates the delay
+func (e *EchoCanceller) track(near, far float64) {
+	e.nearAcc += math.Abs(near)
+	e.farAcc += math.Abs(far)
+	if e.envCount++; e.envCount < e.envBlock {
+		return
+	}
+	e.nearEnv = append(e.nearEnv, e.nearAcc)
+	e.farEnv = append(e.farEnv, e.farAcc)
+	e.nearAcc, e.farAcc, e.envCount = 0, 0, 0
+
+	window := int(aecEstimateWindow / aecEnvelopeBlock)
+	lags := e.maxLag / e.envBlock
+	if keep := window + lags; len(e.farEnv) > 2*keep {
+		e.nearEnv = append(e.nearEnv[:0], e.nearEnv[len(e.nearEnv)-keep:]...)
+		e.farEnv = append(e.farEnv[:0], e.farEnv[len(e.farEnv)-keep:]...)
+	}
+
+	if e.sinceEstimate++; e.sinceEstimate >= int(aecEstimateInterval/aecEnvelopeBlock) && len(e.farEnv) >= window+lags {
+		e.sinceEstimate = 0
+		e.estimateDelay(window, lags)
+	}
+}
+
+// estimateDelay finds the lag at which the near-end envelope best matches
+// the reference envelope, and re-targets the filter when it moves
+func (e *EchoCanceller) estimateDelay(window, lags int) {
+	near := e.nearEnv[len(e.nearEnv)-window:]
+	nearMean, nearVar := meanVar(near)
+	if nearVar == 0 {
+		return
+	}
+
+	best, bestLag := 0.0, -1
+	for lag := 0; lag <= lags; lag++ {
+		end := len(e.farEnv) - lag
+		far := e.farEnv[end-window : end]
+		farMean, farVar := meanVar(far)
+		if farVar == 0 {
+			continue
+		}
+		cov := 0.0
+		for i := range near {
+			cov += (near[i] - nearMean) * (far[i] - farMean)
+		}
+		if c := cov / math.Sqrt(nearVar*farVar); c > best {
+			best, bestLag = c, lag
+		}
+	}
+	if best < aecMinCorrelation {
+		return
+	}
+
+	margin := int(int64(e.format.SampleRate) * int64(aecDelayMargin) / int64(time.Second))
+	delay := max(bestLag*e.envBlock-margin, 0)
+	if e.delay >= 0 && absInt(delay-e.delay) <= 2*e.envBlock {
+		return
+	}
+
+	// New echo path: restart the filter at the new delay
+	e.delay = delay
+	for k := range e.weights {
+		e.weights[k] = 0
+	}
+	e.power, e.farPeak = 0, 0
+	start := e.n - int64(delay)
+	for k := 0; k < e.taps; k++ {
+		v := e.farAt(start - int64(k))
+		e.power += v * v
+		e.farPeak = math.Max(e.farPeak, math.Abs(v))
+	}
+}
+
+// trim drops reference history no filter position can reach
+func (e *EchoCanceller) trim() {
+	keep := e.maxLag + e.taps + 1
+	if drop := len(e.far) - keep; drop > keep {
+		e.far = append(e.far[:0], e.far[drop:]...)
+		e.farBase += int64(drop)
+	}
+}
+
+func (e *EchoCanceller) decode(audio []byte) []float64 {
+	if e.mulaw {
+		samples := make([]float64, len(audio))
+		for i, b := range audio {
+			samples[i] = float64(mulawToLinear(b))
+		}
+		return samples
+	}
+	samples := make([]float64, len(audio)/2)
+	for i := range samples {
+		samples[i] = float64(int16(binary.LittleEndian.Uint16(audio[i*2:])))
+	}
+	return samples
+}
+
+func (e *EchoCanceller) encode(samples []float64) []byte {
+	if e.mulaw {
+		out := GetAudioBuffer(len(samples))
+		for i, s := range samples {
+			out[i] = linearToMulaw(clampInt16(s))
+		}
+		return out
+	}
+	out := GetAudioBuffer(len(samples) * 2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(clampInt16(s)))
+	}
+	return out
+}
+
+// meanVar returns the mean and the sum of squared deviations
+func meanVar(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, variance
+}
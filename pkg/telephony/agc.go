@@ -0,0 +1,166 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+)
+
+// ============================================
+// AUTOMATIC GAIN CONTROL
+// Level normalization for caller audio (before STT) and TTS (before playback)
+// ============================================
+
+const (
+	agcFrameDuration = 10 * time.Millisecond
+	agcAttack        = 0.3   // Level tracking rate when audio gets louder
+	agcRelease       = 0.03  // Level tracking rate when it gets quieter
+	agcPeakLimit     = 32000 // Highest sample magnitude gain may produce
+)
+
+// AGCConfig tunes automatic gain control
+type AGCConfig struct {
+	TargetLevel float64 `json:"target_level"` // RMS level to normalize speech to, dBFS (default -20)
+	MaxGain     float64 `json:"max_gain"`     // Most boost applied, dB (default 20)
+	MaxCut      float64 `json:"max_cut"`      // Most attenuation applied, dB (default 10)
+
+	// Frames below this level (dBFS) don't move the gain, so pauses and
+	// line noise aren't pumped up (default -50)
+	NoiseGate float64 `json:"noise_gate"`
+}
+
+// DefaultAGCConfig returns the default AGC settings
+func DefaultAGCConfig() AGCConfig {
+	return AGCConfig{
+		TargetLevel: -20,
+		MaxGain:     20,
+		MaxCut:      10,
+		NoiseGate:   -50,
+	}
+}
+
+// AGC normalizes the level of a stream of mulaw or 16-bit PCM audio. It
+// tracks the speech level over 10ms frames and ramps gain smoothly, limiting
+// peaks so boosted audio doesn't clip. It is safe for concurrent use.
+type AGC struct {
+	config       AGCConfig
+	format       AudioFormat
+	mulaw        bool
+	frameSamples int
+
+	level float64 // Tracked speech level, dBFS
+	gain  float64 // Linear gain applied at the end of the last frame
+	mu    sync.Mutex
+}
+
+// NewAGC creates a gain controller for audio in format (mulaw or 16-bit PCM,
+// mono), filling in zero config fields with defaults
+func NewAGC(format AudioFormat, config AGCConfig) *AGC {
+	defaults := DefaultAGCConfig()
+	if config.TargetLevel == 0 {
+		config.TargetLevel = defaults.TargetLevel
+	}
+	if config.MaxGain <= 0 {
+		config.MaxGain = defaults.MaxGain
+	}
+	if config.MaxCut <= 0 {
+		config.MaxCut = defaults.MaxCut
+	}
+	if config.NoiseGate == 0 {
+		config.NoiseGate = defaults.NoiseGate
+	}
+
+	return &AGC{
+		config:       config,
+		format:       format,
+		mulaw:        format.Encoding == AudioFormatMulaw.Encoding,
+		frameSamples: format.SampleRate * int(agcFrameDuration/time.Millisecond) / 1000,
+		level:        config.TargetLevel,
+		gain:         1,
+	}
+}
+
+// Process returns the chunk with gain applied, in the same format
+func (a *AGC) Process(audio []byte) []byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	samples := a.decode(audio)
+	for start := 0; start < len(samples); start += a.frameSamples {
+		end := min(start+a.frameSamples, len(samples))
+		a.processFrame(samples[start:end])
+	}
+	return a.encode(samples)
+}
+
+// Gain returns the gain currently applied, in dB
+func (a *AGC) Gain() float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return 20 * math.Log10(a.gain)
+}
+
+// processFrame updates the level estimate from a frame and applies gain to
+// it, ramping from the previous frame's gain
+func (a *AGC) processFrame(frame []int16) {
+	if level := frameLevel(frame); level > a.config.NoiseGate {
+		rate := agcRelease
+		if level > a.level {
+			rate = agcAttack
+		}
+		a.level += (level - a.level) * rate
+	}
+
+	gainDB := math.Max(-a.config.MaxCut, math.Min(a.config.MaxGain, a.config.TargetLevel-a.level))
+	target := math.Pow(10, gainDB/20)
+
+	// Never push the frame's peak past the limit
+	peak := 0.0
+	for _, s := range frame {
+		peak = math.Max(peak, math.Abs(float64(s)))
+	}
+	if peak*target > agcPeakLimit {
+		target = agcPeakLimit / peak
+	}
+	from := a.gain
+	if peak*from > agcPeakLimit {
+		from = target
+	}
+
+	for i, s := range frame {
+		g := from + (target-from)*float64(i+1)/float64(len(frame))
+		frame[i] = clampInt16(float64(s) * g)
+	}
+	a.gain = target
+}
+
+func (a *AGC) decode(audio []byte) []int16 {
+	if a.mulaw {
+		samples := make([]int16, len(audio))
+		for i, b := range audio {
+			samples[i] = mulawToLinear(b)
+		}
+		return samples
+	}
+	samples := make([]int16, len(audio)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(audio[i*2:]))
+	}
+	return samples
+}
+
+func (a *AGC) encode(samples []int16) []byte {
+	if a.mulaw {
+		out := GetAudioBuffer(len(samples))
+		for i, s := range samples {
+			out[i] = linearToMulaw(s)
+		}
+		return out
+	}
+	out := GetAudioBuffer(len(samples) * 2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(s))
+	}
+	return out
+}
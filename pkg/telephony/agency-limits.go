@@ -0,0 +1,261 @@
+package telephony
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ============================================
+// AGENCY LIMITS
+// Per-agency caps on concurrent outbound calls
+// ============================================
+
+// ErrAgencyLimit is returned when an agency is at its concurrent call cap
+var ErrAgencyLimit = errors.New("agency concurrent call limit reached")
+
+// AgencyUsagePath is where hosts conventionally mount HandleAgencyUsage,
+// which RegisterRoutes leaves out
+const AgencyUsagePath = "/api/telephony/agencies/usage"
+
+// AgencyLimitMode controls what InitiateCall does when an agency is at its cap
+type AgencyLimitMode string
+
+const (
+	// AgencyLimitReject fails the call with ErrAgencyLimit
+	AgencyLimitReject AgencyLimitMode = "reject"
+	// AgencyLimitQueue waits for a slot until the call's context is done
+	AgencyLimitQueue AgencyLimitMode = "queue"
+)
+
+// AgencyUsage is the current concurrency of one agency
+type AgencyUsage struct {
+	AgencyID uuid.UUID `json:"agency_id"`
+	Active   int       `json:"active"`
+	Waiting  int       `json:"waiting"`
+	Limit    int       `json:"limit"` // 0 means unlimited
+}
+
+// AgencyLimits tracks live outbound calls per agency so one tenant's
+// campaign can't take every line. A slot is held from InitiateCall until
+// the call reaches a terminal state.
+type AgencyLimits struct {
+	limits       map[uuid.UUID]int
+	defaultLimit int
+	mode         AgencyLimitMode
+
+	active   map[uuid.UUID]int
+	waiting  map[uuid.UUID]int
+	held     map[uuid.UUID]uuid.UUID // session ID -> agency ID
+	released chan struct{}           // Closed and replaced when a slot frees
+	mu       sync.Mutex
+}
+
+// NewAgencyLimits creates limits applying defaultLimit (0 for unlimited) to
+// agencies without their own cap
+func NewAgencyLimits(defaultLimit int, mode AgencyLimitMode) *AgencyLimits {
+	if mode == "" {
+		mode = AgencyLimitReject
+	}
+	return &AgencyLimits{
+		limits:       make(map[uuid.UUID]int),
+		defaultLimit: defaultLimit,
+		mode:         mode,
+		active:       make(map[uuid.UUID]int),
+		waiting:      make(map[uuid.UUID]int),
+		held:         make(map[uuid.UUID]uuid.UUID),
+		released:     make(chan struct{}),
+	}
+}
+
+// SetLimit sets an agency's cap (0 for unlimited, negative to fall back to
+// the default)
+func (l *AgencyLimits) SetLimit(agencyID uuid.UUID, limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit < 0 {
+		delete(l.limits, agencyID)
+	} else {
+		l.limits[agencyID] = limit
+	}
+	// A raised cap may admit queued calls
+	l.broadcast()
+}
+
+// Usage returns the concurrency of every agency with live or waiting calls
+// or its own cap, ordered by agency ID
+func (l *AgencyLimits) Usage() []AgencyUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seen := make(map[uuid.UUID]bool)
+	var usage []AgencyUsage
+	add := func(agencyID uuid.UUID) {
+		if seen[agencyID] {
+			return
+		}
+		seen[agencyID] = true
+		usage = append(usage, AgencyUsage{
+			AgencyID: agencyID,
+			Active:   l.active[agencyID],
+			Waiting:  l.waiting[agencyID],
+			Limit:    l.limitFor(agencyID),
+		})
+	}
+	for agencyID := range l.active {
+		add(agencyID)
+	}
+	for agencyID := range l.waiting {
+		add(agencyID)
+	}
+	for agencyID := range l.limits {
+		add(agencyID)
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].AgencyID.String() < usage[j].AgencyID.String()
+	})
+	return usage
+}
+
+// acquire takes a slot for a session, waiting in queue mode
+func (l *AgencyLimits) acquire(ctx context.Context, agencyID, sessionID uuid.UUID) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	waiting := false
+	defer func() {
+		if waiting {
+			l.waiting[agencyID]--
+			if l.waiting[agencyID] <= 0 {
+				delete(l.waiting, agencyID)
+			}
+		}
+	}()
+
+	for {
+		limit := l.limitFor(agencyID)
+		if limit == 0 || l.active[agencyID] < limit {
+			l.active[agencyID]++
+			l.held[sessionID] = agencyID
+			return nil
+		}
+
+		if l.mode != AgencyLimitQueue {
+			return fmt.Errorf("%w: agency %s has %d live calls", ErrAgencyLimit, agencyID, limit)
+		}
+		if !waiting {
+			waiting = true
+			l.waiting[agencyID]++
+		}
+
+		released := l.released
+		l.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			l.mu.Lock()
+			return fmt.Errorf("%w: %v", ErrAgencyLimit, ctx.Err())
+		case <-released:
+		}
+		l.mu.Lock()
+	}
+}
+
+// release frees the slot held by a session, if any
+func (l *AgencyLimits) release(sessionID uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	agencyID, ok := l.held[sessionID]
+	if !ok {
+		return
+	}
+	delete(l.held, sessionID)
+
+	l.active[agencyID]--
+	if l.active[agencyID] <= 0 {
+		delete(l.active, agencyID)
+	}
+	l.broadcast()
+}
+
+// broadcast wakes every queued acquire; callers hold mu
+func (l *AgencyLimits) broadcast() {
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+// limitFor returns an agency's cap; callers hold mu
+func (l *AgencyLimits) limitFor(agencyID uuid.UUID) int {
+	if limit, ok := l.limits[agencyID]; ok {
+		return limit
+	}
+	return l.defaultLimit
+}
+
+// ============================================
+// INITIATOR INTEGRATION
+// ============================================
+
+// SetAgencyLimits enforces per-agency concurrency caps in InitiateCall.
+// Slots are released when calls reach a terminal state.
+func (ci *CallInitiator) SetAgencyLimits(limits *AgencyLimits) {
+	ci.agencyLimits = limits
+	ci.OnCompleted(func(summary CallSummary) {
+		limits.release(summary.ID)
+	})
+}
+
+// AgencyUsage returns per-agency concurrency, or nil without limits
+func (ci *CallInitiator) AgencyUsage() []AgencyUsage {
+	if ci.agencyLimits == nil {
+		return nil
+	}
+	return ci.agencyLimits.Usage()
+}
+
+// acquireAgencySlot takes a concurrency slot for a new call
+func (ci *CallInitiator) acquireAgencySlot(ctx context.Context, agencyID, sessionID uuid.UUID) error {
+	if ci.agencyLimits == nil {
+		return nil
+	}
+	return ci.agencyLimits.acquire(ctx, agencyID, sessionID)
+}
+
+// releaseAgencySlot frees a call's slot when it ends before connecting
+func (ci *CallInitiator) releaseAgencySlot(sessionID uuid.UUID) {
+	if ci.agencyLimits != nil {
+		ci.agencyLimits.release(sessionID)
+	}
+}
+
+// HandleAgencyUsage returns current per-agency call concurrency.
+//
+// It reports every agency's usage and has no authentication of its own, so
+// it is not part of RegisterRoutes. Mount it behind your auth middleware:
+//
+//	mux.Handle(telephony.AgencyUsagePath, requireAdmin(http.HandlerFunc(handlers.HandleAgencyUsage)))
+func (h *CallHandlers) HandleAgencyUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	usage := h.callInitiator.AgencyUsage()
+	if usage == nil {
+		usage = []AgencyUsage{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"agencies": usage,
+	})
+}
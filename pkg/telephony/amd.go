@@ -0,0 +1,206 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ============================================
+// ANSWERING MACHINE DETECTION
+// AnsweredBy results and the human/machine call branches
+// ============================================
+
+// AMDPath is the webhook path that receives asynchronous AMD results
+const AMDPath = "/api/telephony/calls/amd"
+
+// AnsweredBy is SignalWire's answering machine detection result
+type AnsweredBy string
+
+const (
+	AnsweredByHuman             AnsweredBy = "human"
+	AnsweredByMachineStart      AnsweredBy = "machine_start"       // Machine, detected at the greeting's start
+	AnsweredByMachineEndBeep    AnsweredBy = "machine_end_beep"    // Greeting ended with a beep
+	AnsweredByMachineEndSilence AnsweredBy = "machine_end_silence" // Greeting ended in silence
+	AnsweredByMachineEndOther   AnsweredBy = "machine_end_other"   // Greeting ended otherwise
+	AnsweredByFax               AnsweredBy = "fax"
+	AnsweredByUnknown           AnsweredBy = "unknown"
+)
+
+// ParseAnsweredBy maps a webhook AnsweredBy value to its constant.
+// Unrecognized values are AnsweredByUnknown; empty stays empty.
+func ParseAnsweredBy(value string) AnsweredBy {
+	switch answeredBy := AnsweredBy(strings.ToLower(strings.TrimSpace(value))); answeredBy {
+	case "":
+		return ""
+	case AnsweredByHuman, AnsweredByMachineStart, AnsweredByMachineEndBeep,
+		AnsweredByMachineEndSilence, AnsweredByMachineEndOther, AnsweredByFax:
+		return answeredBy
+	case "machine":
+		return AnsweredByMachineStart
+	default:
+		return AnsweredByUnknown
+	}
+}
+
+// IsMachine reports whether an answering machine picked up
+func (a AnsweredBy) IsMachine() bool {
+	return a == AnsweredByMachineStart || a.IsMessageEnd()
+}
+
+// IsMessageEnd reports whether a machine greeting has finished, so a
+// message left now is recorded
+func (a AnsweredBy) IsMessageEnd() bool {
+	switch a {
+	case AnsweredByMachineEndBeep, AnsweredByMachineEndSilence, AnsweredByMachineEndOther:
+		return true
+	}
+	return false
+}
+
+// ApplyAMDResult records a call's AMD result. Machine results mark the
+// call as voicemail; whether a message was left is set separately.
+func (ci *CallInitiator) ApplyAMDResult(ctx context.Context, callSID string, answeredBy AnsweredBy) error {
+	session, err := ci.updateCall(ctx, callSID, func(session *CallSession) error {
+		session.AnsweredBy = answeredBy
+		if answeredBy.IsMachine() {
+			session.VoicemailDetected = true
+			session.Outcome = OutcomeVoicemailDetected
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ci.hooks.fireAMD(AMDEvent{Call: session.Summary(), AnsweredBy: answeredBy})
+	return nil
+}
+
+// usesAMD reports whether a call asks for answering machine detection
+func (c *CallConfig) usesAMD() bool {
+	return c.DetectVoicemail || c.VoicemailDrop != nil || c.AMDCallbackURL != ""
+}
+
+// amdPolicy is the part of a call's config that picks its AMD branch. It is
+// stored with the session, so whichever instance receives the AMD result
+// can branch the call.
+type amdPolicy struct {
+	HumanURL         string         `json:"human_url,omitempty"`
+	MachineURL       string         `json:"machine_url,omitempty"`
+	VoicemailDrop    *VoicemailDrop `json:"voicemail_drop,omitempty"`
+	VoicemailDropURL string         `json:"voicemail_drop_url,omitempty"` // Where the drop reports back
+}
+
+// amdPolicy returns the AMD branches of a config, or nil without AMD
+func (ci *CallInitiator) amdPolicy(config *CallConfig) (*amdPolicy, error) {
+	if !config.usesAMD() {
+		return nil, nil
+	}
+
+	policy := &amdPolicy{
+		HumanURL:      config.HumanURL,
+		MachineURL:    config.MachineURL,
+		VoicemailDrop: config.VoicemailDrop,
+	}
+	if config.VoicemailDrop != nil {
+		// Async branches are sent inline with the call update, where a
+		// relative redirect has nothing to resolve against
+		dropURL, err := ci.webhookURL(VoicemailDropPath, nil)
+		switch {
+		case err == nil:
+			policy.VoicemailDropURL = dropURL
+		case config.AMDCallbackURL != "":
+			return nil, fmt.Errorf("voicemail drop with async AMD: %w", err)
+		default:
+			policy.VoicemailDropURL = VoicemailDropPath
+		}
+	}
+	return policy, nil
+}
+
+// amdBranch returns the instructions a call switches to for an AMD result,
+// or nil to carry on with the current flow
+func (ci *CallInitiator) amdBranch(ctx context.Context, callSID string, answeredBy AnsweredBy) *TwiML {
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return nil
+	}
+	session.mu.RLock()
+	policy := session.amd
+	session.mu.RUnlock()
+	if policy == nil {
+		return nil
+	}
+
+	switch {
+	case answeredBy == AnsweredByHuman && policy.HumanURL != "":
+		return NewTwiML().Redirect(policy.HumanURL)
+	case answeredBy.IsMessageEnd() && policy.VoicemailDrop != nil:
+		return policy.VoicemailDrop.twiml(policy.VoicemailDropURL)
+	case answeredBy.IsMachine() && policy.MachineURL != "":
+		return NewTwiML().Redirect(policy.MachineURL)
+	case answeredBy == AnsweredByFax:
+		return NewTwiML().Hangup()
+	}
+	return nil
+}
+
+// answerWithAMDBranch handles an answer webhook carrying a synchronous AMD
+// result: the result is recorded and, when the call has a branch for it,
+// the branch is returned as the call's instructions. Reports whether it
+// answered the request.
+func (h *CallHandlers) answerWithAMDBranch(w http.ResponseWriter, r *http.Request) bool {
+	callSID := r.FormValue("CallSid")
+	answeredBy := ParseAnsweredBy(r.FormValue("AnsweredBy"))
+	if answeredBy == "" {
+		return false
+	}
+
+	if err := h.callInitiator.ApplyAMDResult(r.Context(), callSID, answeredBy); err != nil {
+		log.Printf("[CallHandlers] Failed to record AMD result for %s: %v", callSID, err)
+	}
+
+	branch := h.callInitiator.amdBranch(r.Context(), callSID, answeredBy)
+	if branch == nil {
+		return false
+	}
+
+	log.Printf("[CallHandlers] Call %s answered by %s, branching", callSID, answeredBy)
+	writeTwiML(w, branch)
+	return true
+}
+
+// HandleAMDResult receives asynchronous AMD results (AsyncAmdStatusCallback),
+// records them and moves the live call to its human or machine branch
+func (h *CallHandlers) HandleAMDResult(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	callSID := r.FormValue("CallSid")
+	answeredBy := ParseAnsweredBy(r.FormValue("AnsweredBy"))
+	if callSID == "" || answeredBy == "" {
+		http.Error(w, "Missing CallSid or AnsweredBy", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("[CallHandlers] AMD result for %s: %s", callSID, answeredBy)
+
+	if err := h.callInitiator.ApplyAMDResult(r.Context(), callSID, answeredBy); err != nil {
+		log.Printf("[CallHandlers] Failed to record AMD result for %s: %v", callSID, err)
+		h.callInitiator.Errors().Record("handlers", callSID, err)
+	}
+
+	if branch := h.callInitiator.amdBranch(r.Context(), callSID, answeredBy); branch != nil {
+		if err := h.callInitiator.updateLiveCall(r.Context(), callSID, branch); err != nil {
+			log.Printf("[CallHandlers] Failed to branch call %s on AMD result: %v", callSID, err)
+			h.callInitiator.Errors().Record("handlers", callSID, err)
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
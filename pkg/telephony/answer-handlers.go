@@ -0,0 +1,103 @@
+package telephony
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ============================================
+// ANSWER HANDLERS
+// Per-call instructions for answered outbound calls
+// ============================================
+
+// AnswerPath is the answer webhook for calls with an AnswerHandler;
+// SignalWire reaches it through the initiator's public base URL
+const AnswerPath = "/api/telephony/calls/answer"
+
+// AnswerContext describes an answered call to its answer handler
+type AnswerContext struct {
+	Context    context.Context
+	Call       CallSummary
+	Config     *CallConfig // Config the call was placed with
+	AnsweredBy string      // AMD result when detection ran (human, machine_start, ...)
+	SIPHeaders map[string]string
+	Request    *http.Request
+}
+
+// AnswerHandler returns the instructions for an answered call, e.g. its
+// greeting script, stream URL or gather menu (nil hangs up)
+type AnswerHandler func(call AnswerContext) *TwiML
+
+// SetAnswerHandler registers the answer handler for a call placed with
+// AnswerURL pointing at AnswerPath?session_id=<id>. Setting
+// CallConfig.AnswerHandler does this automatically. Handlers live in memory
+// and are dropped once the call ends.
+func (ci *CallInitiator) SetAnswerHandler(sessionID uuid.UUID, handler AnswerHandler) {
+	ci.answerHandlers.Store(sessionID, handler)
+}
+
+// answerHandler returns the handler registered for a session
+func (ci *CallInitiator) answerHandler(sessionID uuid.UUID) (AnswerHandler, bool) {
+	handler, ok := ci.answerHandlers.Load(sessionID)
+	if !ok {
+		return nil, false
+	}
+	return handler.(AnswerHandler), true
+}
+
+// HandleAnswer runs the answer handler registered for the call. The
+// session is found from the session_id query parameter, the X-Session-ID
+// SIP header, or the CallSid.
+func (h *CallHandlers) HandleAnswer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ci := h.callInitiator
+	callSID := r.FormValue("CallSid")
+	headers := SIPHeadersFromRequest(r)
+
+	var session *CallSession
+	if callSID != "" {
+		session, _ = ci.lookupSession(r.Context(), callSID)
+	}
+
+	sessionID, err := uuid.Parse(r.URL.Query().Get("session_id"))
+	if err != nil {
+		sessionID, err = uuid.Parse(headers[SIPHeaderSessionID])
+	}
+	if err != nil && session != nil {
+		sessionID = session.Summary().ID
+	}
+
+	handler, ok := ci.answerHandler(sessionID)
+	if !ok {
+		log.Printf("[CallHandlers] No answer handler for call %s (session %s)", callSID, sessionID)
+		writeTwiML(w, NewTwiML().Hangup())
+		return
+	}
+
+	call := AnswerContext{
+		Context:    r.Context(),
+		Call:       CallSummary{ID: sessionID, SignalWireCallSID: callSID},
+		AnsweredBy: r.FormValue("AnsweredBy"),
+		SIPHeaders: headers,
+		Request:    r,
+	}
+	if session != nil {
+		call.Call = session.Summary()
+		session.mu.RLock()
+		call.Config = session.Config
+		session.mu.RUnlock()
+	}
+
+	twiml := handler(call)
+	if twiml == nil {
+		twiml = NewTwiML().Hangup()
+	}
+	writeTwiML(w, twiml)
+}
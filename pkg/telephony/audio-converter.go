@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"sync"
 )
 
 // Common audio format constants
@@ -22,17 +23,45 @@ var (
 // Supported conversions:
 // - mulaw 8kHz → PCM 16kHz (for Deepgram)
 // - PCM 16kHz → mulaw 8kHz (for telephony playback)
-// - Sample rate conversion
+// - 16-bit PCM at other rates (e.g. 24kHz TTS) → PCM 16kHz or mulaw 8kHz
+// - mulaw 8kHz or 16-bit PCM → 16-bit PCM at any rate
+// - WAV in or out (see DecodeWAV and EncodeWAV)
+// - MP3 and Ogg in, decoded as a stream across chunks (see DecodeMP3 and
+//   DecodeOgg)
+// - Sample rate conversion (windowed-sinc by default, see ResampleMode)
 // - Channel conversion (mono/stereo)
 // ============================================
 
-// AudioConverter handles audio format conversions
+// AudioConverter handles audio format conversions. Converted audio comes
+// from the audio buffer pool; release it with ReleaseAudioBuffer once done.
+// When no conversion is needed the input is returned as is.
 type AudioConverter struct {
 	// Configuration
 	inputSampleRate  int
 	outputSampleRate int
 	inputChannels    int
 	outputChannels   int
+
+	// Sample rate conversion algorithm (default ResampleSinc)
+	resampleMode     ResampleMode
+
+	// Streaming resamplers by [from, to] rate, so consecutive chunks of a
+	// stream resample without clicks at the boundaries
+	resamplers       map[[2]int]*Resampler
+	resamplersMu     sync.Mutex
+
+	// Decoder of compressed input (MP3 or Ogg), holding partial frames and
+	// codec state between chunks
+	decoder          streamDecoder
+	decoderEncoding  string
+	decoderMu        sync.Mutex
+}
+
+// streamDecoder decodes compressed audio received in arbitrary chunks to
+// mono 16-bit PCM
+type streamDecoder interface {
+	decode(chunk []byte) ([]byte, AudioFormat, error)
+	close()
 }
 
 // NewAudioConverter creates a new audio converter
@@ -45,6 +74,30 @@ func NewAudioConverter(inputSampleRate, outputSampleRate int, inputChannels, out
 	}
 }
 
+// SetResampleMode selects the resampling algorithm, e.g. ResampleLinear to
+// save CPU at the cost of aliasing
+func (c *AudioConverter) SetResampleMode(mode ResampleMode) {
+	c.resamplersMu.Lock()
+	defer c.resamplersMu.Unlock()
+	c.resampleMode = mode
+	c.resamplers = nil
+}
+
+// Reset discards resampling history and partially decoded input; call it
+// before converting an unrelated stream with the same converter
+func (c *AudioConverter) Reset() {
+	c.resamplersMu.Lock()
+	c.resamplers = nil
+	c.resamplersMu.Unlock()
+
+	c.decoderMu.Lock()
+	defer c.decoderMu.Unlock()
+	if c.decoder != nil {
+		c.decoder.close()
+		c.decoder = nil
+	}
+}
+
 // MulawToPCM16kHz converts mulaw 8kHz mono to PCM 16kHz mono
 // This is the primary conversion needed for Deepgram streaming
 func (c *AudioConverter) MulawToPCM16kHz(mulawData []byte) ([]byte, error) {
@@ -56,6 +109,7 @@ func (c *AudioConverter) MulawToPCM16kHz(mulawData []byte) ([]byte, error) {
 
 	// Step 2: Resample from 8kHz to 16kHz
 	pcm16kHz, err := c.resamplePCM16(pcm8kHz, 8000, 16000)
+	ReleaseAudioBuffer(pcm8kHz)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resample: %w", err)
 	}
@@ -74,6 +128,7 @@ func (c *AudioConverter) PCM16kHzToMulaw(pcmData []byte) ([]byte, error) {
 
 	// Step 2: Encode PCM to mulaw
 	mulawData, err := c.encodeMulaw(pcm8kHz)
+	ReleaseAudioBuffer(pcm8kHz)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode mulaw: %w", err)
 	}
@@ -83,28 +138,12 @@ func (c *AudioConverter) PCM16kHzToMulaw(pcmData []byte) ([]byte, error) {
 
 // decodeMulaw decodes mulaw encoded audio to 16-bit PCM
 func (c *AudioConverter) decodeMulaw(mulawData []byte) ([]byte, error) {
-	// Mulaw decoding table (standard G.711)
 	// Each mulaw byte (8-bit) maps to a 16-bit PCM sample
-	pcmData := make([]byte, len(mulawData)*2)
+	pcmData := GetAudioBuffer(len(mulawData) * 2)
 
 	for i, mulawByte := range mulawData {
-		// Complement the mulaw byte (flip all bits)
-		mulawByte ^= 0xFF
-
-		// Extract components
-		sign := int16(1)
-		if (mulawByte & 0x80) != 0 {
-			sign = -1
-		}
-
-		exponent := (mulawByte >> 4) & 0x07
-		mantissa := mulawByte & 0x0F
-
-		// Convert to linear 16-bit PCM using G.711 formula
-		sample := int16(sign * (((int16(mantissa) << 3) + 0x84) << exponent))
-
 		// Store as little-endian 16-bit PCM
-		binary.LittleEndian.PutUint16(pcmData[i*2:i*2+2], uint16(sample))
+		binary.LittleEndian.PutUint16(pcmData[i*2:], uint16(mulawDecodeTable[mulawByte]))
 	}
 
 	return pcmData, nil
@@ -118,14 +157,11 @@ func (c *AudioConverter) encodeMulaw(pcmData []byte) ([]byte, error) {
 
 	// Number of 16-bit samples
 	numSamples := len(pcmData) / 2
-	mulawData := make([]byte, numSamples)
+	mulawData := GetAudioBuffer(numSamples)
 
-	for i := 0; i < numSamples; i++ {
-		// Read 16-bit PCM sample (little-endian)
-		sample := int16(binary.LittleEndian.Uint16(pcmData[i*2 : i*2+2]))
-
-		// Encode to mulaw
-		mulawData[i] = c.linearToMulaw(sample)
+	for i := range mulawData {
+		// Read 16-bit PCM sample (little-endian) and look up its code
+		mulawData[i] = mulawEncodeTable[binary.LittleEndian.Uint16(pcmData[i*2:])]
 	}
 
 	return mulawData, nil
@@ -133,92 +169,114 @@ func (c *AudioConverter) encodeMulaw(pcmData []byte) ([]byte, error) {
 
 // linearToMulaw converts a linear 16-bit PCM sample to mulaw
 func (c *AudioConverter) linearToMulaw(sample int16) byte {
-	// Get the absolute value and sign
-	sign := int16(1)
-	if sample < 0 {
-		sign = -1
-		sample = -sample
-	}
+	return linearToMulaw(sample)
+}
 
-	// Clamp to maximum mulaw value
-	if sample > 32635 {
-		sample = 32635
-	}
+// G.711 mulaw constants
+const (
+	mulawBias = 0x84
+	mulawClip = 32635
+)
 
-	// Convert to mulaw using the standard G.711 algorithm
-	exponent := int16(7)
-	for exp := int16(0); exp < 7; exp++ {
-		if sample <= (int16(1) << (exp + 5)) {
-			exponent = exp
-			break
+// G.711 mulaw codec tables: the code for every 16-bit sample (indexed by
+// its bits as uint16) and the sample for every code. Encoding and decoding
+// sit on the hottest path in the package, so each is a single lookup.
+var (
+	mulawEncodeTable = func() (table [65536]byte) {
+		for i := range table {
+			table[i] = encodeMulawSample(int16(i))
 		}
-	}
+		return table
+	}()
+	mulawDecodeTable = func() (table [256]int16) {
+		for i := range table {
+			table[i] = decodeMulawSample(byte(i))
+		}
+		return table
+	}()
+)
 
-	mantissa := sample >> (exponent + 1)
+// linearToMulaw encodes a linear 16-bit PCM sample as G.711 mulaw
+func linearToMulaw(sample int16) byte {
+	return mulawEncodeTable[uint16(sample)]
+}
 
-	// Compose the mulaw byte
-	mulawByte := byte((exponent << 4) | mantissa)
+// mulawToLinear decodes a G.711 mulaw byte to a linear 16-bit PCM sample
+func mulawToLinear(mulawByte byte) int16 {
+	return mulawDecodeTable[mulawByte]
+}
 
-	// Set sign bit
-	if sign < 0 {
-		mulawByte |= 0x80
+// encodeMulawSample computes the mulaw code for a sample, bit-exact with
+// the ITU-T G.191 reference encoder
+func encodeMulawSample(sample int16) byte {
+	// Negative samples are ones' complemented, as in the reference, which
+	// also avoids negating -32768
+	value := int32(sample)
+	sign := byte(0)
+	if value < 0 {
+		sign = 0x80
+		value = ^value
 	}
 
-	// Invert for transmission (MSB is sign bit)
-	return mulawByte ^ 0xFF
-}
-
-// resamplePCM16 resamples 16-bit PCM audio from one sample rate to another
-// Uses simple linear interpolation (good enough for telephony)
-func (c *AudioConverter) resamplePCM16(pcmData []byte, fromRate, toRate int) ([]byte, error) {
-	if len(pcmData)%2 != 0 {
-		return nil, fmt.Errorf("PCM data length must be even (16-bit samples)")
+	// Clamp and bias
+	if value > mulawClip {
+		value = mulawClip
 	}
+	value += mulawBias
 
-	// Calculate number of samples
-	numInputSamples := len(pcmData) / 2
-	numOutputSamples := (numInputSamples * toRate) / fromRate
+	// Exponent is the position of the highest set bit above bit 7
+	exponent := byte(7)
+	for mask := int32(0x4000); value&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
 
-	// Create output buffer
-	outputData := make([]byte, numOutputSamples*2)
+	mantissa := byte(value>>(exponent+3)) & 0x0F
 
-	// Resample using linear interpolation
-	ratio := float64(fromRate) / float64(toRate)
+	// Invert for transmission
+	return ^(sign | exponent<<4 | mantissa)
+}
 
-	for i := 0; i < numOutputSamples; i++ {
-		// Calculate source position
-		srcPos := float64(i) * ratio
+// decodeMulawSample computes the sample for a mulaw code
+func decodeMulawSample(mulawByte byte) int16 {
+	mulawByte = ^mulawByte
 
-		// Get integer sample index
-		srcIndex := int(srcPos)
+	exponent := (mulawByte >> 4) & 0x07
+	mantissa := mulawByte & 0x0F
 
-		// Bounds check
-		if srcIndex >= numInputSamples-1 {
-			srcIndex = numInputSamples - 2
-		}
+	sample := ((int32(mantissa) << 3) + mulawBias) << exponent
+	sample -= mulawBias
 
-		// Get fractional part for interpolation
-		fraction := srcPos - float64(srcIndex)
+	if mulawByte&0x80 != 0 {
+		return int16(-sample)
+	}
+	return int16(sample)
+}
 
-		// Read two consecutive samples
-		sample1 := int16(binary.LittleEndian.Uint16(pcmData[srcIndex*2 : (srcIndex+1)*2]))
-		sample2 := int16(binary.LittleEndian.Uint16(pcmData[(srcIndex+1)*2 : (srcIndex+2)*2]))
+// resamplePCM16 resamples 16-bit PCM audio from one sample rate to another
+// with the converter's resample mode (see SetResampleMode). Each direction
+// is treated as one continuous stream, so output lags input slightly.
+func (c *AudioConverter) resamplePCM16(pcmData []byte, fromRate, toRate int) ([]byte, error) {
+	if fromRate == toRate {
+		return pcmData, nil
+	}
 
-		// Linear interpolation
-		interpolated := float64(sample1)*(1-fraction) + float64(sample2)*fraction
+	c.resamplersMu.Lock()
+	defer c.resamplersMu.Unlock()
 
-		// Clamp to 16-bit range
-		if interpolated > math.MaxInt16 {
-			interpolated = math.MaxInt16
-		} else if interpolated < math.MinInt16 {
-			interpolated = math.MinInt16
+	key := [2]int{fromRate, toRate}
+	resampler, ok := c.resamplers[key]
+	if !ok {
+		var err error
+		resampler, err = NewResampler(fromRate, toRate, c.resampleMode)
+		if err != nil {
+			return nil, err
 		}
-
-		// Write output sample
-		binary.LittleEndian.PutUint16(outputData[i*2:(i+1)*2], uint16(int16(interpolated)))
+		if c.resamplers == nil {
+			c.resamplers = make(map[[2]int]*Resampler)
+		}
+		c.resamplers[key] = resampler
 	}
-
-	return outputData, nil
+	return resampler.Process(pcmData)
 }
 
 // ConvertAudio converts audio data based on input/output formats
@@ -236,9 +294,96 @@ func (c *AudioConverter) ConvertAudio(data []byte, inputFormat, outputFormat Aud
 	case inputFormat == AudioFormatPCM && outputFormat == AudioFormatMulaw:
 		return c.PCM16kHzToMulaw(data)
 
+	// WAV input is unwrapped and converted from the format in its header
+	case inputFormat.Encoding == AudioFormatWAV.Encoding:
+		audio, format, err := DecodeWAV(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode WAV: %w", err)
+		}
+		return c.ConvertAudio(audio, format, outputFormat)
+
+	// MP3 and Ogg input is decoded as one stream across calls; a chunk
+	// holding no whole frame yet converts to nothing
+	case inputFormat.Encoding == AudioFormatMP3.Encoding || inputFormat.Encoding == AudioFormatOgg.Encoding:
+		pcm, format, err := c.decodeStream(data, inputFormat.Encoding)
+		if err != nil || len(pcm) == 0 {
+			return nil, err
+		}
+		return c.ConvertAudio(pcm, format, outputFormat)
+
+	// WAV output is converted to PCM at the requested rate, then wrapped
+	case outputFormat.Encoding == AudioFormatWAV.Encoding:
+		pcmFormat := outputFormat
+		pcmFormat.Encoding = AudioFormatPCM.Encoding
+		pcm, err := c.ConvertAudio(data, inputFormat, pcmFormat)
+		if err != nil {
+			return nil, err
+		}
+		wav, err := EncodeWAV(pcm, pcmFormat)
+		if !sameBuffer(pcm, data) {
+			ReleaseAudioBuffer(pcm)
+		}
+		return wav, err
+
+	// 16-bit mono PCM at other rates, e.g. 22.05kHz or 24kHz from TTS
+	case isMonoPCM16(inputFormat) && isMonoPCM16(outputFormat):
+		return c.resamplePCM16(data, inputFormat.SampleRate, outputFormat.SampleRate)
+
+	case inputFormat == AudioFormatMulaw && isMonoPCM16(outputFormat):
+		pcm8kHz, err := c.decodeMulaw(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode mulaw: %w", err)
+		}
+		pcm, err := c.resamplePCM16(pcm8kHz, AudioFormatMulaw.SampleRate, outputFormat.SampleRate)
+		if !sameBuffer(pcm, pcm8kHz) {
+			ReleaseAudioBuffer(pcm8kHz)
+		}
+		return pcm, err
+
+	case isMonoPCM16(inputFormat) && outputFormat == AudioFormatMulaw:
+		pcm8kHz, err := c.resamplePCM16(data, inputFormat.SampleRate, AudioFormatMulaw.SampleRate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resample: %w", err)
+		}
+		mulawData, err := c.encodeMulaw(pcm8kHz)
+		if !sameBuffer(pcm8kHz, data) {
+			ReleaseAudioBuffer(pcm8kHz)
+		}
+		return mulawData, err
+
 	default:
-		return nil, fmt.Errorf("unsupported conversion: %s -> %s", inputFormat, outputFormat)
+		return nil, fmt.Errorf("unsupported conversion: %+v -> %+v", inputFormat, outputFormat)
+	}
+}
+
+// decodeStream decodes a chunk of compressed audio with the converter's
+// decoder, starting a new one when the encoding changes
+func (c *AudioConverter) decodeStream(data []byte, encoding string) ([]byte, AudioFormat, error) {
+	c.decoderMu.Lock()
+	defer c.decoderMu.Unlock()
+
+	if c.decoder == nil || c.decoderEncoding != encoding {
+		if c.decoder != nil {
+			c.decoder.close()
+		}
+		if encoding == AudioFormatMP3.Encoding {
+			c.decoder = &mp3Decoder{}
+		} else {
+			c.decoder = &oggDecoder{}
+		}
+		c.decoderEncoding = encoding
+	}
+	pcm, format, err := c.decoder.decode(data)
+	if err != nil {
+		return nil, AudioFormat{}, fmt.Errorf("failed to decode %s: %w", encoding, err)
 	}
+	return pcm, format, nil
+}
+
+// isMonoPCM16 reports whether format is single-channel 16-bit PCM at any
+// sample rate
+func isMonoPCM16(format AudioFormat) bool {
+	return format.Encoding == AudioFormatPCM.Encoding && format.BitDepth == 16 && format.Channels == 1 && format.SampleRate > 0
 }
 
 // DetectAudioFormat attempts to detect the audio format from raw data
@@ -253,6 +398,19 @@ func DetectAudioFormat(data []byte) (AudioFormat, error) {
 		return AudioFormatWAV, nil
 	}
 
+	// Check for Ogg page, or MP3 ID3 tag or frame header
+	if len(data) >= 4 && string(data[0:4]) == "OggS" {
+		return AudioFormatOgg, nil
+	}
+	if len(data) >= 3 && string(data[0:3]) == "ID3" {
+		return AudioFormatMP3, nil
+	}
+	if len(data) >= 4 {
+		if size, _, _ := mp3FrameSize(binary.BigEndian.Uint32(data)); data[0] == 0xFF && data[1]&0xE0 == 0xE0 && size > 0 {
+			return AudioFormatMP3, nil
+		}
+	}
+
 	// For raw audio, we can't reliably detect between mulaw and PCM
 	// without additional context. Return unknown format.
 	return AudioFormat{}, fmt.Errorf("unable to detect audio format (no header found)")
@@ -290,21 +448,20 @@ func ApplyGain(pcmData []byte, gain float64) ([]byte, error) {
 }
 
 // MixAudio mixes multiple PCM audio streams together
-// All inputs must have the same length, sample rate, and format
+// All inputs must have the same sample rate and format; shorter streams are
+// padded with silence to the longest. For live streams, see StreamMixer.
 func MixAudio(streams ...[]byte) ([]byte, error) {
 	if len(streams) == 0 {
 		return nil, fmt.Errorf("no audio streams provided")
 	}
 
-	// Check all streams have same length
-	length := len(streams[0])
+	// Mix to the longest stream
+	length := 0
 	for _, stream := range streams {
-		if len(stream) != length {
-			return nil, fmt.Errorf("all audio streams must have the same length")
-		}
-		if length%2 != 0 {
+		if len(stream)%2 != 0 {
 			return nil, fmt.Errorf("PCM data length must be even (16-bit samples)")
 		}
+		length = max(length, len(stream))
 	}
 
 	// Mix streams
@@ -314,10 +471,11 @@ func MixAudio(streams ...[]byte) ([]byte, error) {
 	for i := 0; i < numSamples; i++ {
 		sum := int32(0)
 
-		// Sum samples from all streams
+		// Sum samples from all streams; ended streams are silent
 		for _, stream := range streams {
-			sample := int16(binary.LittleEndian.Uint16(stream[i*2 : (i+1)*2]))
-			sum += int32(sample)
+			if i*2 < len(stream) {
+				sum += int32(int16(binary.LittleEndian.Uint16(stream[i*2 : (i+1)*2])))
+			}
 		}
 
 		// Average the samples
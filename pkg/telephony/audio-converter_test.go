@@ -0,0 +1,145 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// G.711 mulaw reference values, from the ITU-T G.191 codec
+var mulawReference = []struct {
+	sample int16
+	code   byte
+	decode int16 // The code's decoded value
+}{
+	{0, 0xFF, 0},
+	{-1, 0x7F, 0},
+	{8, 0xFE, 8},
+	{-8, 0x7E, -8},
+	{120, 0xF0, 120},
+	{1000, 0xCE, 988},
+	{-1000, 0x4E, -988},
+	{8000, 0xA0, 7932},
+	{16764, 0x8F, 16764},
+	{32124, 0x80, 32124},
+	{32767, 0x80, 32124},
+	{-32124, 0x00, -32124},
+	{-32768, 0x00, -32124},
+}
+
+func TestMulawReference(t *testing.T) {
+	for _, tc := range mulawReference {
+		if got := linearToMulaw(tc.sample); got != tc.code {
+			t.Errorf("linearToMulaw(%d) = %#02x, want %#02x", tc.sample, got, tc.code)
+		}
+		if got := mulawToLinear(tc.code); got != tc.decode {
+			t.Errorf("mulawToLinear(%#02x) = %d, want %d", tc.code, got, tc.decode)
+		}
+	}
+}
+
+func TestMulawTablesMatchArithmetic(t *testing.T) {
+	for i := 0; i < 65536; i++ {
+		sample := int16(i)
+		if got, want := linearToMulaw(sample), encodeMulawSample(sample); got != want {
+			t.Fatalf("linearToMulaw(%d) = %#02x, arithmetic gives %#02x", sample, got, want)
+		}
+	}
+	for i := 0; i < 256; i++ {
+		code := byte(i)
+		if got, want := mulawToLinear(code), decodeMulawSample(code); got != want {
+			t.Fatalf("mulawToLinear(%#02x) = %d, arithmetic gives %d", code, got, want)
+		}
+	}
+}
+
+func TestMulawRoundTrip(t *testing.T) {
+	// Every code decodes to a value that encodes back to it, except
+	// negative zero (0x7F), which decodes to 0 and encodes as 0xFF
+	for i := 0; i < 256; i++ {
+		code := byte(i)
+		want := code
+		if code == 0x7F {
+			want = 0xFF
+		}
+		if got := linearToMulaw(mulawToLinear(code)); got != want {
+			t.Errorf("round trip of %#02x = %#02x", code, got)
+		}
+	}
+
+	converter := NewAudioConverter(8000, 8000, 1, 1)
+	codes := make([]byte, 256)
+	for i := range codes {
+		codes[i] = byte(i)
+	}
+	pcm, err := converter.decodeMulaw(codes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := converter.encodeMulaw(pcm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, code := range encoded {
+		if want := linearToMulaw(mulawToLinear(byte(i))); code != want {
+			t.Errorf("encodeMulaw(decodeMulaw(%#02x)) = %#02x, want %#02x", i, code, want)
+		}
+	}
+}
+
+// mulawBenchmarkPCM is one second of 8kHz speech-level audio
+func mulawBenchmarkPCM() []byte {
+	pcm := make([]byte, 8000*2)
+	for i := 0; i < 8000; i++ {
+		v := 12000 * math.Sin(2*math.Pi*440*float64(i)/8000)
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(int16(v)))
+	}
+	return pcm
+}
+
+func BenchmarkEncode(b *testing.B) {
+	pcm := mulawBenchmarkPCM()
+	out := make([]byte, len(pcm)/2)
+
+	b.Run("table", func(b *testing.B) {
+		b.SetBytes(int64(len(pcm)))
+		for n := 0; n < b.N; n++ {
+			for i := range out {
+				out[i] = linearToMulaw(int16(binary.LittleEndian.Uint16(pcm[i*2:])))
+			}
+		}
+	})
+	b.Run("arithmetic", func(b *testing.B) {
+		b.SetBytes(int64(len(pcm)))
+		for n := 0; n < b.N; n++ {
+			for i := range out {
+				out[i] = encodeMulawSample(int16(binary.LittleEndian.Uint16(pcm[i*2:])))
+			}
+		}
+	})
+}
+
+func BenchmarkDecode(b *testing.B) {
+	pcm := mulawBenchmarkPCM()
+	codes := make([]byte, len(pcm)/2)
+	for i := range codes {
+		codes[i] = linearToMulaw(int16(binary.LittleEndian.Uint16(pcm[i*2:])))
+	}
+
+	b.Run("table", func(b *testing.B) {
+		b.SetBytes(int64(len(codes)))
+		for n := 0; n < b.N; n++ {
+			for i, code := range codes {
+				binary.LittleEndian.PutUint16(pcm[i*2:], uint16(mulawToLinear(code)))
+			}
+		}
+	})
+	b.Run("arithmetic", func(b *testing.B) {
+		b.SetBytes(int64(len(codes)))
+		for n := 0; n < b.N; n++ {
+			for i, code := range codes {
+				binary.LittleEndian.PutUint16(pcm[i*2:], uint16(decodeMulawSample(code)))
+			}
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package telephony
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ============================================
+// AUDIO FAN-OUT
+// Extra consumers of a session's phone → AI audio, each with its own queue
+// ============================================
+
+// AudioSubscription is one consumer's copy of a session's phone → AI audio
+type AudioSubscription struct {
+	session *BridgeSession
+	ring    *AudioRing
+	audio   chan []byte
+	once    sync.Once
+}
+
+// SubscribePhoneAudio returns a subscription to the audio delivered on the
+// session's phone → AI channel. Each subscriber gets its own copy of every
+// chunk, queued per the bridge's inbound queue settings (see
+// SetAudioQueues), so a slow subscriber loses only its own audio. Chunks
+// received are the subscriber's to release.
+func (bridge *AudioStreamBridge) SubscribePhoneAudio(sessionID string) (*AudioSubscription, error) {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	bridge.mu.RLock()
+	config := bridge.inboundQueue
+	bridge.mu.RUnlock()
+
+	sub := &AudioSubscription{
+		session: session,
+		ring:    NewAudioRing(config),
+		audio:   make(chan []byte),
+	}
+
+	session.mu.Lock()
+	if !session.Active {
+		session.mu.Unlock()
+		return nil, fmt.Errorf("session closed: %s", sessionID)
+	}
+	if session.subscribers == nil {
+		session.subscribers = make(map[*AudioSubscription]struct{})
+	}
+	session.subscribers[sub] = struct{}{}
+	session.mu.Unlock()
+
+	go sub.ring.deliver(session.ctx, sub.audio)
+	return sub, nil
+}
+
+// Audio returns the subscription's channel, closed once the subscription or
+// session is closed and queued audio has been delivered
+func (sub *AudioSubscription) Audio() <-chan []byte {
+	return sub.audio
+}
+
+// Stats returns the subscription's queue occupancy and drop counts
+func (sub *AudioSubscription) Stats() AudioRingStats {
+	return sub.ring.Stats()
+}
+
+// Close ends the subscription
+func (sub *AudioSubscription) Close() {
+	sub.once.Do(func() {
+		sub.session.mu.Lock()
+		delete(sub.session.subscribers, sub)
+		sub.session.mu.Unlock()
+		sub.ring.Close()
+	})
+}
+
+// fanOut queues a copy of phone → AI audio for each subscriber. It must run
+// before the chunk is queued for the AI consumer, who may release it.
+func (s *BridgeSession) fanOut(chunk []byte) {
+	s.mu.RLock()
+	if len(s.subscribers) == 0 {
+		s.mu.RUnlock()
+		return
+	}
+	subs := make([]*AudioSubscription, 0, len(s.subscribers))
+	for sub := range s.subscribers {
+		subs = append(subs, sub)
+	}
+	s.mu.RUnlock()
+
+	for _, sub := range subs {
+		audio := append(GetAudioBuffer(len(chunk))[:0], chunk...)
+		if lost := sub.ring.Push(s.ctx, audio); lost != nil {
+			ReleaseAudioBuffer(lost)
+		}
+	}
+}
+
+// closeSubscriptions ends every subscription when the session closes.
+// Caller must hold the session lock.
+func (s *BridgeSession) closeSubscriptions() {
+	for sub := range s.subscribers {
+		sub.ring.Close()
+	}
+	s.subscribers = nil
+}
@@ -0,0 +1,154 @@
+package telephony
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ============================================
+// AUDIO FILTERS
+// Custom processing stages composed per session, on caller audio before the
+// AI pipeline and on AI audio before it plays
+// ============================================
+
+// AudioFilter processes a stream of audio a chunk at a time. Process may
+// modify frame in place and return it, or return a new buffer (ideally from
+// GetAudioBuffer) which the bridge then owns; either way the audio stays in
+// the format the filter was created for. Format changes, such as
+// resampling for the AI pipeline, are declared with SetAIFormat instead.
+// A filter with a Close() method is closed when it is discarded.
+type AudioFilter interface {
+	Process(frame []byte) []byte
+}
+
+// AudioFilterFunc adapts a function to a stateless AudioFilter
+type AudioFilterFunc func(frame []byte) []byte
+
+// Process calls f(frame)
+func (f AudioFilterFunc) Process(frame []byte) []byte {
+	return f(frame)
+}
+
+// AudioFilterFactory creates a filter for audio in format (the stream's
+// negotiated format: mulaw at 8kHz, or 16-bit PCM for wideband streams).
+// Filters usually keep state, so each session gets its own instances, made
+// again if the stream's format changes.
+type AudioFilterFactory func(format AudioFormat) AudioFilter
+
+// AudioFilters configures a session's filter chains; each runs its filters
+// in order
+type AudioFilters struct {
+	Caller   []AudioFilterFactory // Caller audio, after echo cancellation and before speech detection
+	Playback []AudioFilterFactory // AI audio, after conversion to the stream's format and before it plays
+}
+
+// AGCFilter returns a factory of gain controllers, to place AGC at a chosen
+// point in a chain rather than where SetAGC runs it
+func AGCFilter(config AGCConfig) AudioFilterFactory {
+	return func(format AudioFormat) AudioFilter {
+		return NewAGC(format, config)
+	}
+}
+
+// SetDefaultFilters sets the filter chains of sessions created afterwards
+func (bridge *AudioStreamBridge) SetDefaultFilters(filters AudioFilters) {
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+	bridge.filters = filters
+}
+
+// SetFilters replaces a session's filter chains, closing the filters in use
+func (bridge *AudioStreamBridge) SetFilters(sessionID string, filters AudioFilters) error {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.filters = filters
+	session.closeFilters()
+	return nil
+}
+
+// filterChain is one direction's filters, created for one format. It is
+// safe for concurrent use; once closed it passes audio through.
+type filterChain struct {
+	format  AudioFormat
+	filters []AudioFilter
+	closed  bool
+	mu      sync.Mutex
+}
+
+// filterChain returns the session's chain for a direction, creating it for
+// the stream's format on first use; nil when no filters are configured
+func (s *BridgeSession) filterChain(playback bool, format AudioFormat) *filterChain {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	factories, chain := s.filters.Caller, &s.callerFilters
+	if playback {
+		factories, chain = s.filters.Playback, &s.playbackFilters
+	}
+	if len(factories) == 0 {
+		return nil
+	}
+	if *chain == nil || (*chain).format != format {
+		if *chain != nil {
+			(*chain).close()
+		}
+		*chain = newFilterChain(format, factories)
+	}
+	return *chain
+}
+
+// closeFilters closes the session's filter chains; the caller holds s.mu
+func (s *BridgeSession) closeFilters() {
+	for _, chain := range []**filterChain{&s.callerFilters, &s.playbackFilters} {
+		if *chain != nil {
+			(*chain).close()
+			*chain = nil
+		}
+	}
+}
+
+func newFilterChain(format AudioFormat, factories []AudioFilterFactory) *filterChain {
+	chain := &filterChain{format: format}
+	for _, factory := range factories {
+		if filter := factory(format); filter != nil {
+			chain.filters = append(chain.filters, filter)
+		}
+	}
+	return chain
+}
+
+// process runs frame through the filters in order, releasing buffers
+// filters replace along the way. frame must be the caller's to modify.
+func (c *filterChain) process(frame []byte) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return frame
+	}
+	for _, filter := range c.filters {
+		frame = replaceBuffer(frame, filter.Process(frame))
+	}
+	return frame
+}
+
+// close closes filters that hold resources
+func (c *filterChain) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+	c.closed = true
+	for _, filter := range c.filters {
+		if closer, ok := filter.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// ============================================
+// OUTBOUND FRAMING
+// Re-chunking audio sent to SignalWire into fixed frames paced in real time
+// ============================================
+
+const (
+	outboundFrame = 20 * time.Millisecond // Length of each media message sent to SignalWire
+	outboundLead  = 40 * time.Millisecond // How far ahead of real time frames are sent
+
+	driftWindow = 5 * time.Second        // Period over which standing latency is measured
+	driftHigh   = 200 * time.Millisecond // Standing latency at which playout is sped up
+	driftTarget = 100 * time.Millisecond // Latency sped-up playout is brought back to
+)
+
+// PlayoutStats describes audio sent toward the caller. Over a long call the
+// rate audio is produced (e.g. streaming TTS) and the rate it plays drift
+// apart; the framer measures the drift and compensates by removing a sample
+// a frame when latency builds up, or inserting one when audio runs short.
+type PlayoutStats struct {
+	Frames   uint64  `json:"frames"`      // Frames sent
+	Padded   uint64  `json:"padded"`      // Partial frames padded with silence
+	Removed  uint64  `json:"removed"`     // Samples removed to shed built-up latency
+	Inserted uint64  `json:"inserted"`    // Samples inserted to stretch audio running short
+	DriftPPM float64 `json:"drift_ppm"`   // Audio produced faster (+) or slower (-) than it plays, parts per million
+	Buffered float64 `json:"buffered_ms"` // Audio waiting to be sent after the last frame
+}
+
+// audioFramer re-chunks audio written in arbitrary sizes, such as TTS
+// output, into outboundFrame frames and paces them so SignalWire receives
+// audio at the rate it plays: a burst of TTS is spread over its duration
+// rather than sent at once, and short writes are joined into whole frames.
+// It is safe for concurrent use.
+type audioFramer struct {
+	pending []byte
+	read    int       // Start of unsent audio in pending
+	due     time.Time // When the next frame starts playing
+	frame   []byte    // Scratch for frames with a sample added or removed
+
+	// Drift over the current run of continuous audio, measured from the
+	// least audio buffered in each driftWindow
+	runStart     time.Time
+	windowStart  time.Time
+	windowFloor  int // Bytes
+	windowAdjust int // Samples removed less samples inserted this window
+	lastFloor    int
+	haveFloor    bool
+	compress     bool
+
+	stats PlayoutStats
+	mu    sync.Mutex
+}
+
+// write queues audio for framing. Audio arriving after the line has gone
+// quiet starts playing now rather than catching up on the idle time.
+func (f *audioFramer) write(now time.Time, audio []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.buffered() == 0 {
+		f.pending, f.read = f.pending[:0], 0
+		if f.due.Before(now) {
+			f.due = now
+			f.runStart = time.Time{} // A new run of audio
+		}
+	} else if f.read > len(f.pending)/2 {
+		n := copy(f.pending, f.pending[f.read:])
+		f.pending, f.read = f.pending[:n], 0
+	}
+	f.pending = append(f.pending, audio...)
+}
+
+// clear drops the audio waiting to be sent; the next write starts a new
+// run of audio
+func (f *audioFramer) clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending, f.read = f.pending[:0], 0
+	f.runStart = time.Time{}
+}
+
+// next returns the next frame of audio in format once it is due to be
+// sent, or how long until one may be. The frame is valid until the next
+// call. A trailing partial frame waits for more audio until it is due to
+// play, then goes out padded with silence.
+func (f *audioFramer) next(now time.Time, format AudioFormat) ([]byte, time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	buffered := f.buffered()
+	if buffered == 0 {
+		return nil, 0
+	}
+	if sendAt := f.due.Add(-outboundLead); now.Before(sendAt) {
+		return nil, sendAt.Sub(now)
+	}
+
+	size := frameBytes(format, outboundFrame)
+	sampleBytes := frameBytes(format, 0)
+	frame := f.pending[f.read:]
+	switch adjust := f.measure(now, format, buffered, size); {
+	case buffered < size:
+		if now.Before(f.due) {
+			return nil, f.due.Sub(now)
+		}
+		silence := byte(0)
+		if format.Encoding == AudioFormatMulaw.Encoding {
+			silence = 0xFF
+		}
+		for len(f.pending)-f.read < size {
+			f.pending = append(f.pending, silence)
+		}
+		frame = f.pending[f.read : f.read+size]
+		f.read += size
+		f.stats.Padded++
+	case adjust < 0:
+		frame = f.removeSample(format, frame[:size+sampleBytes])
+		f.read += size + sampleBytes
+	case adjust > 0:
+		frame = f.insertSample(format, frame[:size-sampleBytes])
+		f.read += size - sampleBytes
+	default:
+		frame = frame[:size]
+		f.read += size
+	}
+
+	f.due = f.due.Add(outboundFrame)
+	f.stats.Frames++
+	f.stats.Buffered = float64(audioDuration(format, f.buffered())) / float64(time.Millisecond)
+	return frame, 0
+}
+
+// measure tracks the audio left buffered as a frame is cut and returns
+// whether to remove (-1) or insert (+1) a sample in it to counter drift
+func (f *audioFramer) measure(now time.Time, format AudioFormat, buffered, size int) int {
+	sampleBytes := frameBytes(format, 0)
+	level := buffered - size
+	if format.Channels > 1 || level < 0 {
+		return 0
+	}
+
+	if f.runStart.IsZero() {
+		f.runStart, f.windowStart, f.windowFloor, f.windowAdjust = now, now, level, 0
+		f.haveFloor, f.compress = false, false
+	}
+	f.windowFloor = min(f.windowFloor, level)
+
+	if elapsed := now.Sub(f.windowStart); elapsed >= driftWindow {
+		// Growth of the floor, had nothing been removed or inserted, is drift
+		if f.haveFloor {
+			grew := audioDuration(format, absInt(f.windowFloor+f.windowAdjust*sampleBytes-f.lastFloor))
+			ppm := grew.Seconds() / elapsed.Seconds() * 1e6
+			if f.windowFloor+f.windowAdjust*sampleBytes < f.lastFloor {
+				ppm = -ppm
+			}
+			f.stats.DriftPPM = 0.7*f.stats.DriftPPM + 0.3*ppm
+		}
+		if audioDuration(format, f.windowFloor) > driftHigh {
+			f.compress = true
+		}
+		f.lastFloor, f.haveFloor = f.windowFloor, true
+		f.windowStart, f.windowFloor, f.windowAdjust = now, level, 0
+	}
+
+	if f.compress && audioDuration(format, level) <= driftTarget {
+		f.compress = false
+	}
+	switch {
+	case f.compress && level >= sampleBytes:
+		f.windowAdjust++
+		f.stats.Removed++
+		return -1
+	case level < size && now.Sub(f.runStart) >= driftWindow:
+		// Well into a run of audio and about to run dry
+		f.windowAdjust--
+		f.stats.Inserted++
+		return 1
+	}
+	return 0
+}
+
+// removeSample returns audio less the sample whose neighbours are closest,
+// where the splice is least audible
+func (f *audioFramer) removeSample(format AudioFormat, audio []byte) []byte {
+	samples := pcmSamples(format, audio)
+	at, best := len(samples)-1, -1
+	for i := 1; i < len(samples)-1; i++ {
+		if d := absInt(int(samples[i+1]) - int(samples[i-1])); best < 0 || d < best {
+			at, best = i, d
+		}
+	}
+	sampleBytes := len(audio) / len(samples)
+	f.frame = append(append(f.frame[:0], audio[:at*sampleBytes]...), audio[(at+1)*sampleBytes:]...)
+	return f.frame
+}
+
+// insertSample returns audio with a sample interpolated between the two
+// closest neighbours
+func (f *audioFramer) insertSample(format AudioFormat, audio []byte) []byte {
+	samples := pcmSamples(format, audio)
+	at, best := len(samples)-1, -1
+	for i := 0; i < len(samples)-1; i++ {
+		if d := absInt(int(samples[i+1]) - int(samples[i])); best < 0 || d < best {
+			at, best = i, d
+		}
+	}
+	value := samples[at]
+	if at+1 < len(samples) {
+		value = int16((int(samples[at]) + int(samples[at+1])) / 2)
+	}
+
+	sampleBytes := len(audio) / len(samples)
+	f.frame = append(f.frame[:0], audio[:(at+1)*sampleBytes]...)
+	if format.Encoding == AudioFormatMulaw.Encoding {
+		f.frame = append(f.frame, linearToMulaw(value))
+	} else {
+		f.frame = binary.LittleEndian.AppendUint16(f.frame, uint16(value))
+	}
+	return append(f.frame, audio[(at+1)*sampleBytes:]...)
+}
+
+// Stats returns the framer's counters
+func (f *audioFramer) Stats() PlayoutStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+// buffered returns the bytes of audio waiting to be sent
+func (f *audioFramer) buffered() int {
+	return len(f.pending) - f.read
+}
+
+// frameBytes returns the size of a d-long frame of audio in format, in
+// whole samples (at least one)
+func frameBytes(format AudioFormat, d time.Duration) int {
+	sampleBytes := max(format.Channels, 1) * max(format.BitDepth/8, 1)
+	samples := max(int(int64(format.SampleRate)*int64(d)/int64(time.Second)), 1)
+	return samples * sampleBytes
+}
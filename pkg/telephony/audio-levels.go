@@ -0,0 +1,205 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// ============================================
+// AUDIO LEVEL METERING
+// Live RMS/peak levels per direction, for VU meters and dead-air detection
+// ============================================
+
+const (
+	levelIntegration = 300 * time.Millisecond // RMS averaging time, as on a VU meter
+	levelPeakFall    = 20.0                   // Held peak decay, dB per second
+	levelActivity    = -50.0                  // dBFS above which a direction counts as carrying audio
+)
+
+// AudioLevelsPath is where hosts conventionally mount HandleAudioLevels,
+// which RegisterRoutes leaves out
+const AudioLevelsPath = "/api/telephony/calls/bridge/levels"
+
+// AudioLevels are a session's live audio levels in dBFS (-96 for silence)
+type AudioLevels struct {
+	SessionID    string  `json:"session_id"`
+	InboundRMS   float64 `json:"inbound_rms_level"`
+	InboundPeak  float64 `json:"inbound_peak_level"`
+	OutboundRMS  float64 `json:"outbound_rms_level"`
+	OutboundPeak float64 `json:"outbound_peak_level"`
+	DeadAirMs    int64   `json:"dead_air_ms"` // Time since either side last had audio above -50 dBFS
+}
+
+// levelMeter integrates one direction's level with meter ballistics: RMS
+// averaged over levelIntegration, peaks held and falling at levelPeakFall
+type levelMeter struct {
+	power   float64   // Smoothed mean square, linear
+	peak    float64   // Held peak, dBFS
+	updated time.Time // When the last metered audio ended
+	active  time.Time // When audio was last above levelActivity
+}
+
+// update meters a chunk of d of audio that finished arriving at now
+func (l *levelMeter) update(now time.Time, samples []int16, d time.Duration) {
+	if len(samples) == 0 {
+		return
+	}
+	sum, peak := 0.0, 0.0
+	for _, s := range samples {
+		v := float64(s)
+		sum += v * v
+		peak = math.Max(peak, math.Abs(v))
+	}
+	meanSquare := sum / float64(len(samples))
+
+	// Let the meter fall over any gap before this chunk, then integrate it
+	power, held := l.at(now.Add(-d))
+	alpha := 1 - math.Exp(-float64(d)/float64(levelIntegration))
+	l.power = power + (meanSquare-power)*alpha
+	l.peak = math.Max(held, powerLevel(peak*peak))
+	l.updated = now
+	if powerLevel(meanSquare) > levelActivity {
+		l.active = now
+	}
+}
+
+// at returns the smoothed power and held peak as of now, decayed over any
+// time since audio was last metered
+func (l *levelMeter) at(now time.Time) (float64, float64) {
+	if l.updated.IsZero() {
+		return 0, vadSilenceLevel
+	}
+	gap := now.Sub(l.updated)
+	if gap <= 0 {
+		return l.power, l.peak
+	}
+	power := l.power * math.Exp(-float64(gap)/float64(levelIntegration))
+	return power, math.Max(l.peak-levelPeakFall*gap.Seconds(), vadSilenceLevel)
+}
+
+// levels returns the RMS and peak levels as of now, in dBFS to 0.1dB
+func (l *levelMeter) levels(now time.Time) (float64, float64) {
+	power, peak := l.at(now)
+	return math.Round(powerLevel(power)*10) / 10, math.Round(peak*10) / 10
+}
+
+// powerLevel converts a mean square sample value to dBFS
+func powerLevel(power float64) float64 {
+	if power < 1 {
+		return vadSilenceLevel
+	}
+	return math.Max(10*math.Log10(power/(32768*32768)), vadSilenceLevel)
+}
+
+// pcmSamples decodes mulaw or 16-bit PCM audio to linear samples
+func pcmSamples(format AudioFormat, audio []byte) []int16 {
+	if format.Encoding == AudioFormatMulaw.Encoding {
+		samples := make([]int16, len(audio))
+		for i, b := range audio {
+			samples[i] = mulawToLinear(b)
+		}
+		return samples
+	}
+	samples := make([]int16, len(audio)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(audio[i*2:]))
+	}
+	return samples
+}
+
+// meterLevel records a chunk of audio in format on the inbound (caller) or
+// outbound (toward the caller) meter
+func (m *BridgeMetrics) meterLevel(outbound bool, format AudioFormat, audio []byte) {
+	samples := pcmSamples(format, audio)
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.meteredFrom.IsZero() {
+		m.meteredFrom = now
+	}
+	meter := &m.inbound
+	if outbound {
+		meter = &m.outbound
+	}
+	meter.update(now, samples, audioDuration(format, len(audio)))
+}
+
+// fillLevels sets the exported level fields of a snapshot as of now. Caller
+// must hold the metrics lock.
+func (m *BridgeMetrics) fillLevels(snapshot *BridgeMetrics, now time.Time) {
+	snapshot.InboundRMSLevel, snapshot.InboundPeakLevel = m.inbound.levels(now)
+	snapshot.OutboundRMSLevel, snapshot.OutboundPeakLevel = m.outbound.levels(now)
+
+	if m.meteredFrom.IsZero() {
+		return
+	}
+	lastAudio := m.meteredFrom
+	for _, t := range []time.Time{m.inbound.active, m.outbound.active} {
+		if t.After(lastAudio) {
+			lastAudio = t
+		}
+	}
+	snapshot.DeadAirMs = now.Sub(lastAudio).Milliseconds()
+}
+
+// GetAudioLevels returns a session's live audio levels
+func (bridge *AudioStreamBridge) GetAudioLevels(sessionID string) (*AudioLevels, error) {
+	metrics, err := bridge.GetMetrics(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AudioLevels{
+		SessionID:    sessionID,
+		InboundRMS:   metrics.InboundRMSLevel,
+		InboundPeak:  metrics.InboundPeakLevel,
+		OutboundRMS:  metrics.OutboundRMSLevel,
+		OutboundPeak: metrics.OutboundPeakLevel,
+		DeadAirMs:    metrics.DeadAirMs,
+	}, nil
+}
+
+// HandleAudioLevels returns live audio levels for a session (?session_id=),
+// or for every bridge session.
+//
+// Without a session ID it lists every call on the bridge and it has no
+// authentication of its own, so it is not part of RegisterRoutes. Mount it
+// behind your auth middleware:
+//
+//	mux.Handle(telephony.AudioLevelsPath, requireSupervisor(http.HandlerFunc(handlers.HandleAudioLevels)))
+func (h *CallHandlers) HandleAudioLevels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	levels := []*AudioLevels{}
+	if sessionID := r.URL.Query().Get("session_id"); sessionID != "" {
+		l, err := h.streamBridge.GetAudioLevels(sessionID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		levels = append(levels, l)
+	} else {
+		ids := h.streamBridge.ListSessionIDs()
+		sort.Strings(ids)
+		for _, id := range ids {
+			if l, err := h.streamBridge.GetAudioLevels(id); err == nil {
+				levels = append(levels, l)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sessions": levels,
+	})
+}
@@ -0,0 +1,241 @@
+package telephony
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ============================================
+// AUDIO MIXER
+// Real-time mixing of several live sources into one stream
+// ============================================
+
+const (
+	mixerFrameSamples = 160              // 20ms at 8kHz
+	mixerMaxBuffered  = 30 * time.Second // Per-source backlog before oldest audio is dropped
+	mixerFrameTime    = 20 * time.Millisecond
+)
+
+// StreamMixer mixes live sources of mono mulaw or 16-bit PCM, written in
+// chunks of any length and each starting at its own offset, into one
+// stream read a frame at a time. Where a source has no audio (before it
+// starts, in gaps, after it ends) it contributes silence. It is safe for
+// concurrent use.
+type StreamMixer struct {
+	format      AudioFormat
+	sources     map[string]*mixerSource
+	position    int64 // Sample the next frame starts at
+	maxBuffered int   // Samples held per source
+	mu          sync.Mutex
+}
+
+// mixerSource is one source's audio not yet mixed
+type mixerSource struct {
+	start   int64 // Sample position of samples[0]
+	samples []int16
+}
+
+// NewStreamMixer creates a mixer of sources in format (mulaw, or mono
+// 16-bit PCM at any rate); the mix is in the same format
+func NewStreamMixer(format AudioFormat) (*StreamMixer, error) {
+	if format != AudioFormatMulaw && !isMonoPCM16(format) {
+		return nil, fmt.Errorf("unsupported mixer format: %+v", format)
+	}
+	return &StreamMixer{
+		format:      format,
+		sources:     make(map[string]*mixerSource),
+		maxBuffered: int(int64(format.SampleRate) * int64(mixerMaxBuffered) / int64(time.Second)),
+	}, nil
+}
+
+// Write queues audio from a source, following on from its previous audio.
+// A new source, or one that ran dry, starts at the next frame.
+func (m *StreamMixer) Write(source string, audio []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	at := m.position
+	if s := m.sources[source]; s != nil {
+		at = max(at, s.start+int64(len(s.samples)))
+	}
+	m.place(source, at, pcmSamples(m.format, audio))
+}
+
+// WriteAt places audio from a source at offset from the start of the mix.
+// Any gap since the source's previous audio is silence, audio overlapping
+// it replaces it, and audio before the next frame (already mixed) is
+// dropped.
+func (m *StreamMixer) WriteAt(source string, offset time.Duration, audio []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	at := int64(m.format.SampleRate) * int64(offset) / int64(time.Second)
+	m.place(source, at, pcmSamples(m.format, audio))
+}
+
+// place puts samples for a source at sample position at
+func (m *StreamMixer) place(source string, at int64, samples []int16) {
+	if skip := m.position - at; skip > 0 {
+		if skip >= int64(len(samples)) {
+			return
+		}
+		samples, at = samples[skip:], m.position
+	}
+	if len(samples) == 0 {
+		return
+	}
+
+	s := m.sources[source]
+	if s == nil || len(s.samples) == 0 {
+		s = &mixerSource{start: at}
+		m.sources[source] = s
+	}
+	if at < s.start {
+		// Earlier than anything held: pad forward to what's held
+		gap := make([]int16, s.start-at)
+		s.samples, s.start = append(gap, s.samples...), at
+	}
+
+	offset := int(at - s.start)
+	if end := offset + len(samples); end > len(s.samples) {
+		s.samples = append(s.samples, make([]int16, end-len(s.samples))...)
+	}
+	copy(s.samples[offset:], samples)
+
+	if excess := len(s.samples) - m.maxBuffered; excess > 0 {
+		s.samples, s.start = s.samples[excess:], s.start+int64(excess)
+	}
+}
+
+// Read mixes the next frame of n samples, or returns nil (still advancing)
+// when no source has audio in it. The frame comes from the audio buffer
+// pool.
+func (m *StreamMixer) Read(n int) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mix := make([]int32, n)
+	active := false
+	end := m.position + int64(n)
+	for name, s := range m.sources {
+		if s.start >= end {
+			continue // Not started yet
+		}
+		active = true
+
+		// Queued audio never starts before the frame, but may start into it
+		lead := int(s.start - m.position)
+		consumed := min(n-lead, len(s.samples))
+		for i, v := range s.samples[:consumed] {
+			mix[lead+i] += int32(v)
+		}
+		s.samples, s.start = s.samples[consumed:], s.start+int64(consumed)
+		if len(s.samples) == 0 {
+			delete(m.sources, name)
+		}
+	}
+	m.position = end
+
+	if !active {
+		return nil
+	}
+	return m.encode(mix)
+}
+
+// Remove discards a source's queued audio, e.g. when a participant leaves
+func (m *StreamMixer) Remove(source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sources, source)
+}
+
+// Reset discards all queued audio and restarts the mix at offset zero
+func (m *StreamMixer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sources = make(map[string]*mixerSource)
+	m.position = 0
+}
+
+// encode converts a mix to the mixer's format, clamped to 16 bits
+func (m *StreamMixer) encode(mix []int32) []byte {
+	mulaw := m.format.Encoding == AudioFormatMulaw.Encoding
+	size := len(mix)
+	if !mulaw {
+		size *= 2
+	}
+	frame := GetAudioBuffer(size)
+	for i, sum := range mix {
+		v := int16(max(min(sum, math.MaxInt16), math.MinInt16))
+		if mulaw {
+			frame[i] = linearToMulaw(v)
+		} else {
+			binary.LittleEndian.PutUint16(frame[2*i:], uint16(v))
+		}
+	}
+	return frame
+}
+
+// AudioMixer combines named 8kHz mulaw sources frame by frame and delivers
+// the mix at playback speed. Sources that have nothing buffered contribute
+// silence; frames where every source is empty are skipped.
+type AudioMixer struct {
+	mixer  *StreamMixer
+	output func(frame []byte)
+	cancel context.CancelFunc
+	mu     sync.Mutex
+}
+
+// NewAudioMixer creates a mixer delivering mixed mulaw frames to output
+func NewAudioMixer(output func(frame []byte)) *AudioMixer {
+	mixer, _ := NewStreamMixer(AudioFormatMulaw)
+	return &AudioMixer{
+		mixer:  mixer,
+		output: output,
+	}
+}
+
+// Write queues mulaw audio from a source
+func (m *AudioMixer) Write(source string, mulaw []byte) {
+	m.mixer.Write(source, mulaw)
+}
+
+// Start mixes until ctx is cancelled or Stop is called
+func (m *AudioMixer) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	m.mu.Lock()
+	m.cancel = cancel
+	m.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(mixerFrameTime)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if frame := m.mixer.Read(mixerFrameSamples); frame != nil {
+					m.output(frame)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops mixing and discards buffered audio
+func (m *AudioMixer) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.mixer.Reset()
+}
@@ -0,0 +1,291 @@
+package telephony
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ============================================
+// AUDIO RING BUFFER
+// Bounded audio queues between the bridge and its consumers, with a
+// configurable overflow policy
+// ============================================
+
+// OverflowPolicy decides what a full ring does with a new chunk
+type OverflowPolicy string
+
+const (
+	// OverflowDropOldest evicts the oldest chunk: latency stays bounded,
+	// stale audio is lost
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowDropNewest refuses the new chunk
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+	// OverflowBlock waits for space, up to BlockTimeout, then drops the new
+	// chunk
+	OverflowBlock OverflowPolicy = "block"
+)
+
+// AudioRingConfig sizes an audio queue and sets its overflow policy
+type AudioRingConfig struct {
+	Capacity int            `json:"capacity"` // Chunks held (default 500)
+	Overflow OverflowPolicy `json:"overflow"` // Default OverflowBlock
+
+	// How long OverflowBlock waits for space (default 10ms); negative waits
+	// until space frees or the session ends
+	BlockTimeout time.Duration `json:"block_timeout"`
+}
+
+// DefaultAudioRingConfig returns the default queue settings: 500 chunks,
+// waiting up to 10ms for space before dropping
+func DefaultAudioRingConfig() AudioRingConfig {
+	return AudioRingConfig{
+		Capacity:     500,
+		Overflow:     OverflowBlock,
+		BlockTimeout: 10 * time.Millisecond,
+	}
+}
+
+// AudioRingStats reports a queue's occupancy and traffic
+type AudioRingStats struct {
+	Capacity  int    `json:"capacity"`
+	Depth     int    `json:"depth"`      // Chunks waiting now
+	HighWater int    `json:"high_water"` // Most chunks ever waiting
+	Pushed    uint64 `json:"pushed"`
+	Popped    uint64 `json:"popped"`
+	Dropped   uint64 `json:"dropped"` // Chunks lost to overflow or closing
+}
+
+// AudioRing is a fixed-capacity FIFO of audio chunks. It is safe for
+// concurrent use.
+type AudioRing struct {
+	config AudioRingConfig
+	chunks [][]byte
+	head   int // Index of the oldest chunk
+	count  int
+	closed bool
+	stats  AudioRingStats
+
+	ready chan struct{} // Signalled when a chunk is pushed
+	space chan struct{} // Signalled when a chunk is popped
+	done  chan struct{} // Closed by Close
+	mu    sync.Mutex
+}
+
+// NewAudioRing creates a ring, filling in zero config fields with defaults
+func NewAudioRing(config AudioRingConfig) *AudioRing {
+	defaults := DefaultAudioRingConfig()
+	if config.Capacity <= 0 {
+		config.Capacity = defaults.Capacity
+	}
+	if config.Overflow == "" {
+		config.Overflow = defaults.Overflow
+	}
+	if config.BlockTimeout == 0 {
+		config.BlockTimeout = defaults.BlockTimeout
+	}
+
+	return &AudioRing{
+		config: config,
+		chunks: make([][]byte, config.Capacity),
+		stats:  AudioRingStats{Capacity: config.Capacity},
+		ready:  make(chan struct{}, 1),
+		space:  make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// Push adds a chunk, applying the overflow policy when the ring is full. It
+// returns the chunk lost to overflow (the oldest for OverflowDropOldest,
+// otherwise chunk itself), or nil when nothing was lost.
+func (r *AudioRing) Push(ctx context.Context, chunk []byte) []byte {
+	var timeout <-chan time.Time
+	for {
+		r.mu.Lock()
+		switch {
+		case r.closed:
+			r.stats.Dropped++
+			r.mu.Unlock()
+			return chunk
+
+		case r.count < len(r.chunks):
+			r.put(chunk)
+			r.mu.Unlock()
+			return nil
+
+		case r.config.Overflow == OverflowDropOldest:
+			oldest := r.take()
+			r.put(chunk)
+			r.stats.Dropped++
+			r.mu.Unlock()
+			return oldest
+
+		case r.config.Overflow == OverflowDropNewest:
+			r.stats.Dropped++
+			r.mu.Unlock()
+			return chunk
+		}
+		r.mu.Unlock()
+
+		// OverflowBlock: wait for a pop, then try again
+		if timeout == nil && r.config.BlockTimeout > 0 {
+			timer := time.NewTimer(r.config.BlockTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case <-r.space:
+		case <-r.done:
+		case <-ctx.Done():
+			return r.drop(chunk)
+		case <-timeout:
+			return r.drop(chunk)
+		}
+	}
+}
+
+// Pop removes the oldest chunk, waiting for one. It returns false once the
+// ring is closed and empty, or ctx ends.
+func (r *AudioRing) Pop(ctx context.Context) ([]byte, bool) {
+	for {
+		if chunk, ok := r.TryPop(); ok {
+			return chunk, true
+		}
+
+		r.mu.Lock()
+		closed := r.closed && r.count == 0
+		r.mu.Unlock()
+		if closed {
+			return nil, false
+		}
+
+		select {
+		case <-r.ready:
+		case <-r.done:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// TryPop removes the oldest chunk if there is one, without waiting
+func (r *AudioRing) TryPop() ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.count == 0 {
+		return nil, false
+	}
+	chunk := r.take()
+	r.stats.Popped++
+	if r.count > 0 {
+		signal(r.ready) // Keep Ready firing while chunks remain
+	}
+	return chunk, true
+}
+
+// Ready fires when chunks may be waiting, for use in a select with TryPop
+func (r *AudioRing) Ready() <-chan struct{} {
+	return r.ready
+}
+
+// Len returns the number of chunks waiting
+func (r *AudioRing) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+// Stats returns the ring's occupancy and counters
+func (r *AudioRing) Stats() AudioRingStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := r.stats
+	stats.Depth = r.count
+	return stats
+}
+
+// Close stops the ring accepting chunks; those already queued can still be
+// popped
+func (r *AudioRing) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.closed {
+		r.closed = true
+		close(r.done)
+	}
+}
+
+// put appends a chunk. Caller must hold the lock and have checked for space.
+func (r *AudioRing) put(chunk []byte) {
+	r.chunks[(r.head+r.count)%len(r.chunks)] = chunk
+	r.count++
+	r.stats.Pushed++
+	if r.count > r.stats.HighWater {
+		r.stats.HighWater = r.count
+	}
+	signal(r.ready)
+}
+
+// take removes the oldest chunk. Caller must hold the lock and have checked
+// there is one.
+func (r *AudioRing) take() []byte {
+	chunk := r.chunks[r.head]
+	r.chunks[r.head] = nil
+	r.head = (r.head + 1) % len(r.chunks)
+	r.count--
+	signal(r.space)
+	return chunk
+}
+
+// drop counts a chunk given up on while blocked and returns it
+func (r *AudioRing) drop(chunk []byte) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.Dropped++
+	return chunk
+}
+
+// deliver moves chunks from the ring to out until the ring is closed and
+// drained or ctx ends, then closes out. Consumers that read a channel get
+// the ring's buffering and overflow policy this way.
+func (r *AudioRing) deliver(ctx context.Context, out chan<- []byte) {
+	defer close(out)
+	for {
+		chunk, ok := r.Pop(ctx)
+		if !ok {
+			return
+		}
+		select {
+		case out <- chunk:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// collect moves chunks sent on in into the ring until in is closed or ctx
+// ends, passing chunks lost to overflow to dropped
+func (r *AudioRing) collect(ctx context.Context, in <-chan []byte, dropped func(chunk []byte)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case chunk, ok := <-in:
+			if !ok {
+				return
+			}
+			if lost := r.Push(ctx, chunk); lost != nil {
+				dropped(lost)
+			}
+		}
+	}
+}
+
+// signal wakes a waiter on a one-slot notification channel without blocking
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
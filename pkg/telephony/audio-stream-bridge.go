@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,9 +20,43 @@ type AudioStreamBridge struct {
 	// Active streaming sessions
 	sessions map[string]*BridgeSession
 
+	// SignalWire call SID -> session ID (multiple streams per call share one session)
+	callSIDs map[string]string
+
 	// Session management
 	mu sync.RWMutex
 
+	// Optional event bus for stream events
+	events EventBus
+
+	// Voice activity detection for AI-routed streams (nil when disabled)
+	vadConfig *VADConfig
+
+	// Silence suppression and comfort noise for AI-routed streams
+	silence SilenceConfig
+
+	// In-band DTMF detection on AI-routed streams
+	detectDTMF bool
+
+	// Audio queue sizing and overflow policy toward consumers (phone → AI
+	// and tap streams) and toward the caller (AI → phone)
+	inboundQueue  AudioRingConfig
+	outboundQueue AudioRingConfig
+
+	// Dual-channel recording of new sessions (nil when disabled)
+	recording *SessionRecordingConfig
+
+	// Formats the AI pipeline exchanges audio in, for new sessions (zero
+	// for the stream's own format)
+	aiInput  AudioFormat
+	aiOutput AudioFormat
+
+	// Filter chains of new sessions (see SetDefaultFilters)
+	filters AudioFilters
+
+	// Set once Drain starts; new sessions are refused
+	draining atomic.Bool
+
 	// Lifecycle
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -33,11 +68,222 @@ func NewAudioStreamBridge() *AudioStreamBridge {
 
 	return &AudioStreamBridge{
 		sessions: make(map[string]*BridgeSession),
+		callSIDs: make(map[string]string),
 		ctx:      ctx,
 		cancel:   cancel,
 	}
 }
 
+// SetEventBus publishes stream events (started, stopped, packet dropped) to bus
+func (bridge *AudioStreamBridge) SetEventBus(bus EventBus) {
+	bridge.events = bus
+}
+
+// SetVAD enables voice activity detection on the caller audio of AI-routed
+// streams. Speech start/end events are published to the event bus and
+// delivered on each session's speech channel (see GetSpeechEvents).
+func (bridge *AudioStreamBridge) SetVAD(config VADConfig) {
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+	bridge.vadConfig = &config
+}
+
+// SetDTMFDetection enables detection of in-band DTMF tones in caller audio,
+// for carriers that don't deliver digits out of band. Digits are published
+// to the event bus and delivered on each session's digit channel (see
+// GetDigitEvents).
+func (bridge *AudioStreamBridge) SetDTMFDetection(enabled bool) {
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+	bridge.detectDTMF = enabled
+}
+
+// SetAGC enables automatic gain control on a session's caller audio (before
+// the AI pipeline) and TTS audio (before playback); a nil config disables
+// that direction
+func (bridge *AudioStreamBridge) SetAGC(sessionID string, caller, playback *AGCConfig) error {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.callerAGCConfig, session.playbackAGCConfig = caller, playback
+	session.callerAGC, session.playbackAGC = nil, nil
+	return nil
+}
+
+// agc returns the session's gain controller for a direction, creating it for
+// the stream's format on first use; nil when AGC is disabled
+func (s *BridgeSession) agc(playback bool, format AudioFormat) *AGC {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config, agc := s.callerAGCConfig, &s.callerAGC
+	if playback {
+		config, agc = s.playbackAGCConfig, &s.playbackAGC
+	}
+	if config == nil {
+		return nil
+	}
+	if *agc == nil || (*agc).format != format {
+		*agc = NewAGC(format, *config)
+	}
+	return *agc
+}
+
+// SetEchoCancellation enables cancelling the AI's own audio, echoed back by
+// the caller's device, from the session's caller audio; a nil config
+// disables it
+func (bridge *AudioStreamBridge) SetEchoCancellation(sessionID string, config *AECConfig) error {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.aecConfig = config
+	session.aec = nil
+	return nil
+}
+
+// echoCanceller returns the session's echo canceller, creating it for the
+// stream's format on first use; nil when echo cancellation is disabled
+func (s *BridgeSession) echoCanceller(format AudioFormat) *EchoCanceller {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.aecConfig == nil {
+		return nil
+	}
+	if s.aec == nil || s.aec.format != format {
+		s.aec = NewEchoCanceller(format, *s.aecConfig)
+	}
+	return s.aec
+}
+
+// SetDefaultAIFormat sets the formats the AI pipeline receives caller audio
+// in and sends its audio in, for sessions created afterwards; see SetAIFormat
+func (bridge *AudioStreamBridge) SetDefaultAIFormat(input, output AudioFormat) error {
+	if err := checkAIFormats(input, output); err != nil {
+		return err
+	}
+
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+	bridge.aiInput, bridge.aiOutput = input, output
+	return nil
+}
+
+// SetAIFormat declares the formats a session's AI pipeline exchanges audio
+// in: caller audio is converted from the stream's negotiated format to
+// input before it is delivered, and AI audio from output before it plays.
+// A zero format leaves that direction in the stream's format (mulaw, or
+// 16kHz PCM for wideband streams). Formats are mulaw at 8kHz or mono
+// 16-bit PCM at any rate; output may also be AudioFormatMP3 or
+// AudioFormatOgg, so TTS provider output can be pushed as it arrives.
+func (bridge *AudioStreamBridge) SetAIFormat(sessionID string, input, output AudioFormat) error {
+	if err := checkAIFormats(input, output); err != nil {
+		return err
+	}
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.aiInput, session.aiOutput = input, output
+	session.inConverter, session.outConverter = nil, nil
+	return nil
+}
+
+// AIFormat returns the formats declared for a session's AI pipeline; a zero
+// format follows the stream
+func (bridge *AudioStreamBridge) AIFormat(sessionID string) (input, output AudioFormat, err error) {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return AudioFormat{}, AudioFormat{}, fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	return session.aiInput, session.aiOutput, nil
+}
+
+// checkAIFormats validates the input and output formats declared by an AI
+// pipeline
+func checkAIFormats(input, output AudioFormat) error {
+	raw := func(format AudioFormat) bool {
+		return format == (AudioFormat{}) || format == AudioFormatMulaw || isMonoPCM16(format)
+	}
+	if !raw(input) {
+		return fmt.Errorf("unsupported AI input format: %+v", input)
+	}
+	if !raw(output) && !isCompressedAudio(output) {
+		return fmt.Errorf("unsupported AI output format: %+v", output)
+	}
+	return nil
+}
+
+// isCompressedAudio reports whether format is a compressed stream the
+// converter decodes (MP3 or Ogg)
+func isCompressedAudio(format AudioFormat) bool {
+	return format.Encoding == AudioFormatMP3.Encoding || format.Encoding == AudioFormatOgg.Encoding
+}
+
+// aiFormats returns the formats the session's AI pipeline exchanges audio
+// in for a stream in format
+func (s *BridgeSession) aiFormats(format AudioFormat) (input, output AudioFormat) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	input, output = s.aiInput, s.aiOutput
+	if input == (AudioFormat{}) {
+		input = format
+	}
+	if output == (AudioFormat{}) {
+		output = format
+	}
+	return input, output
+}
+
+// SetAudioQueues sizes the audio queues of sessions created afterwards and
+// sets what happens when they fill: inbound queues hold caller audio for
+// the consumer (phone → AI and tap streams), outbound queues hold AI audio
+// waiting to play. Zero fields keep the defaults: 500 chunks, inbound
+// dropping audio after a 10ms wait and outbound blocking the AI until there
+// is room.
+func (bridge *AudioStreamBridge) SetAudioQueues(inbound, outbound AudioRingConfig) {
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+	bridge.inboundQueue, bridge.outboundQueue = inbound, outbound
+}
+
+// SetSilenceConfig configures silence suppression toward the AI pipeline and
+// comfort noise toward the caller for streams linked afterwards
+func (bridge *AudioStreamBridge) SetSilenceConfig(config SilenceConfig) {
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+	bridge.silence = config.withDefaults()
+}
+
+// streamEvent builds an event for a stream on a session
+func streamEvent(eventType EventType, session *BridgeSession, stream *BridgeStream, data map[string]interface{}) Event {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["stream"] = stream.Name
+	data["route"] = string(stream.Route)
+
+	session.mu.RLock()
+	callSID := session.CallSID
+	session.mu.RUnlock()
+
+	return Event{Type: eventType, CallSID: callSID, SessionID: session.SessionID, Data: data}
+}
+
 // ============================================
 // BRIDGE SESSION
 // ============================================
@@ -48,20 +294,77 @@ type BridgeSession struct {
 	ID           string `json:"id"`
 	SessionID    string `json:"session_id"`    // External session ID
 
-	// SignalWire connection
-	SignalWireSession *SignalWireCallSession `json:"-"`
+	// SignalWire call this session belongs to
+	CallSID      string `json:"call_sid,omitempty"`
 
-	// Audio buffers for bidirectional streaming
-	phoneToAIChan  chan []byte // Audio FROM phone → TO AI
-	aiToPhoneChan  chan []byte // Audio FROM AI → TO phone
+	// SignalWire connection (primary AI stream)
+	SignalWireSession *SignalWireCallSession `json:"-"`
 
-	// Format conversion
-	InputFormat   AudioFormat `json:"input_format"`   // From phone
-	OutputFormat  AudioFormat `json:"output_format"`  // To phone
+	// All WebSocket streams attached to this call, keyed by stream name
+	streams map[string]*BridgeStream
+
+	// Supervisor leg mixing (nil when no supervisor is attached)
+	supervisorMode SupervisorMode
+	monitorMixer   *AudioMixer // Caller + AI audio → supervisor
+	phoneMixer     *AudioMixer // AI + supervisor audio → caller (barge)
+
+	// Audio queues for bidirectional streaming; the channels are the
+	// consumer's ends, pumped to and from the rings
+	phoneToAI      *AudioRing  // Audio FROM phone → TO AI
+	aiToPhone      *AudioRing  // Audio FROM AI → TO phone
+	playback       *AudioRing  // Audio file playback → phone, in the stream's format
+	phoneToAIChan  chan []byte
+	aiToPhoneChan  chan []byte
+
+	// Further consumers of phone → AI audio (see SubscribePhoneAudio)
+	subscribers    map[*AudioSubscription]struct{}
+
+	// Caller speech start/end events (when VAD is enabled)
+	speechChan     chan Event
+
+	// In-band DTMF digit events (when detection is enabled)
+	digitChan      chan Event
+
+	// Automatic gain control on caller and TTS audio (nil when disabled)
+	callerAGCConfig   *AGCConfig
+	playbackAGCConfig *AGCConfig
+	callerAGC         *AGC
+	playbackAGC       *AGC
+
+	// Echo cancellation on caller audio (nil when disabled)
+	aecConfig         *AECConfig
+	aec               *EchoCanceller
+
+	// Custom filters on caller and TTS audio (see SetFilters)
+	filters           AudioFilters
+	callerFilters     *filterChain
+	playbackFilters   *filterChain
+
+	// Local recording of caller and played audio (nil when disabled)
+	recorder          *sessionRecorder
+
+	// Recording notice tone in both directions (nil when disabled)
+	noticeConfig      *RecordingNoticeConfig
+	callerNotice      *recordingNotice
+	playbackNotice    *recordingNotice
+
+	// Audio file playback in progress (see PlayAudio)
+	stopPlayback      context.CancelFunc
+	playbackDone      chan struct{}
+
+	// Format conversion between the stream's negotiated format and the
+	// formats the AI pipeline declared (see SetAIFormat)
+	InputFormat   AudioFormat `json:"input_format"`   // Phone → AI audio as the AI receives it
+	OutputFormat  AudioFormat `json:"output_format"`  // AI → phone audio as the AI sends it
+	aiInput       AudioFormat // Declared formats; zero follows the stream
+	aiOutput      AudioFormat
+	inConverter   *AudioConverter
+	outConverter  *AudioConverter
 
 	// State
 	Active        bool `json:"active"`
 	Streaming     bool `json:"streaming"`
+	Speaking      bool `json:"speaking"` // Caller is talking (when VAD is enabled)
 
 	// Metrics
 	Metrics       *BridgeMetrics `json:"metrics"`
@@ -75,6 +378,46 @@ type BridgeSession struct {
 	mu            sync.RWMutex
 }
 
+// StreamRoute determines where a stream's inbound audio is delivered
+type StreamRoute string
+
+const (
+	StreamRouteAI  StreamRoute = "ai"  // Feeds phoneToAIChan and carries AI → phone audio
+	StreamRouteTap StreamRoute = "tap" // Delivered only on the stream's own channel (recorders, analytics)
+
+	// StreamRouteSupervisor is a supervisor's call leg: it hears the caller
+	// and AI, and its audio is delivered on its own channel (and to the
+	// caller when barging)
+	StreamRouteSupervisor StreamRoute = "supervisor"
+)
+
+// BridgeStream is one WebSocket media stream attached to a bridge session
+type BridgeStream struct {
+	Name        string      `json:"name"`
+	Route       StreamRoute `json:"route"`
+	Track       string      `json:"track,omitempty"`
+	ConnectedAt time.Time   `json:"connected_at"`
+
+	// SignalWire connection carrying this stream, and the track's audio
+	// from it
+	SignalWireSession *SignalWireCallSession `json:"-"`
+	input             <-chan []byte
+
+	// Audio FROM phone for tap streams, and the consumer's end of it
+	audio     *AudioRing
+	audioChan chan []byte
+
+	// Voice activity detector, created on the first audio (AI route only)
+	vad       *VAD
+	vadFailed bool
+
+	// In-band DTMF detector, created on the first audio (AI route only)
+	dtmf *DTMFDetector
+
+	// Per-stream metrics
+	Metrics *BridgeMetrics `json:"metrics"`
+}
+
 // AudioFormat defines audio format specifications
 type AudioFormat struct {
 	SampleRate   int    `json:"sample_rate"`   // 8000 for telephony
@@ -101,9 +444,27 @@ type BridgeMetrics struct {
 
 	// Quality
 	DroppedPackets           int64 `json:"dropped_packets"`
+	SuppressedPackets        int64 `json:"suppressed_packets"` // Caller silence not forwarded
 	Overruns                 int64 `json:"overruns"`
 	Underruns                int64 `json:"underruns"`
 
+	// Live levels (dBFS, -96 when silent)
+	InboundRMSLevel          float64 `json:"inbound_rms_level"`   // Caller audio
+	InboundPeakLevel         float64 `json:"inbound_peak_level"`
+	OutboundRMSLevel         float64 `json:"outbound_rms_level"`  // Audio played to the caller
+	OutboundPeakLevel        float64 `json:"outbound_peak_level"`
+	DeadAirMs                int64   `json:"dead_air_ms"`         // Time since either side last had audio
+
+	// Queue occupancy (chunks waiting; sessions and tap streams)
+	InboundQueueDepth        int `json:"inbound_queue_depth"`       // Caller audio for the consumer
+	InboundQueueHighWater    int `json:"inbound_queue_high_water"`
+	OutboundQueueDepth       int `json:"outbound_queue_depth"`      // AI audio waiting to play
+	OutboundQueueHighWater   int `json:"outbound_queue_high_water"`
+
+	inbound                  levelMeter
+	outbound                 levelMeter
+	meteredFrom              time.Time
+
 	mu                       sync.RWMutex
 }
 
@@ -113,6 +474,10 @@ type BridgeMetrics struct {
 
 // CreateSession creates a new bridge session
 func (bridge *AudioStreamBridge) CreateSession(sessionID string) (*BridgeSession, error) {
+	if bridge.draining.Load() {
+		return nil, ErrDraining
+	}
+
 	bridge.mu.Lock()
 	defer bridge.mu.Unlock()
 
@@ -123,23 +488,28 @@ func (bridge *AudioStreamBridge) CreateSession(sessionID string) (*BridgeSession
 
 	ctx, cancel := context.WithCancel(bridge.ctx)
 
+	// Push back on the AI rather than drop its audio, unless configured
+	outbound := bridge.outboundQueue
+	if outbound.BlockTimeout == 0 {
+		outbound.BlockTimeout = -1
+	}
+
 	session := &BridgeSession{
 		ID:              sessionID,
 		SessionID:       sessionID,
-		phoneToAIChan:   make(chan []byte, 500),
-		aiToPhoneChan:   make(chan []byte, 500),
-		InputFormat:     AudioFormat{
-			SampleRate: 8000,
-			Channels:   1,
-			Encoding:   "mulaw",
-			BitDepth:   8,
-		},
-		OutputFormat:    AudioFormat{
-			SampleRate: 8000,
-			Channels:   1,
-			Encoding:   "mulaw",
-			BitDepth:   8,
-		},
+		streams:         make(map[string]*BridgeStream),
+		phoneToAI:       NewAudioRing(bridge.inboundQueue),
+		aiToPhone:       NewAudioRing(outbound),
+		playback:        NewAudioRing(outbound),
+		phoneToAIChan:   make(chan []byte),
+		aiToPhoneChan:   make(chan []byte),
+		speechChan:      make(chan Event, 64),
+		digitChan:       make(chan Event, 64),
+		InputFormat:     AudioFormatMulaw,
+		OutputFormat:    AudioFormatMulaw,
+		aiInput:         bridge.aiInput,
+		aiOutput:        bridge.aiOutput,
+		filters:         bridge.filters,
 		Active:          true,
 		Streaming:       false,
 		Metrics:         &BridgeMetrics{},
@@ -148,8 +518,18 @@ func (bridge *AudioStreamBridge) CreateSession(sessionID string) (*BridgeSession
 		cancel:          cancel,
 	}
 
+	if bridge.recording != nil {
+		session.recorder = newSessionRecorder(*bridge.recording, sessionID, session.CreatedAt)
+		session.noticeConfig = bridge.recording.Notice
+	}
+
 	bridge.sessions[sessionID] = session
 
+	go session.phoneToAI.deliver(ctx, session.phoneToAIChan)
+	go session.aiToPhone.collect(ctx, session.aiToPhoneChan, func([]byte) {
+		bridge.dropOutbound(session)
+	})
+
 	log.Printf("[AudioStreamBridge] Created session: %s", sessionID)
 	return session, nil
 }
@@ -162,7 +542,52 @@ func (bridge *AudioStreamBridge) GetSession(sessionID string) *BridgeSession {
 	return bridge.sessions[sessionID]
 }
 
-// LinkSignalWireSession links a SignalWire call session to a bridge session
+// BindCallSID associates a SignalWire call SID with a bridge session so that
+// every stream SignalWire opens for the call lands on the same session
+func (bridge *AudioStreamBridge) BindCallSID(sessionID, callSID string) error {
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+
+	session, exists := bridge.sessions[sessionID]
+	if !exists {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	session.mu.Lock()
+	session.CallSID = callSID
+	session.mu.Unlock()
+
+	bridge.callSIDs[callSID] = sessionID
+	return nil
+}
+
+// GetSessionByCallSID retrieves the session bound to a SignalWire call SID
+func (bridge *AudioStreamBridge) GetSessionByCallSID(callSID string) *BridgeSession {
+	bridge.mu.RLock()
+	defer bridge.mu.RUnlock()
+
+	sessionID, ok := bridge.callSIDs[callSID]
+	if !ok {
+		return nil
+	}
+	return bridge.sessions[sessionID]
+}
+
+// ListSessionIDs returns the IDs of all active bridge sessions
+func (bridge *AudioStreamBridge) ListSessionIDs() []string {
+	bridge.mu.RLock()
+	defer bridge.mu.RUnlock()
+
+	ids := make([]string, 0, len(bridge.sessions))
+	for id := range bridge.sessions {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// LinkSignalWireSession links a SignalWire call session to a bridge session.
+// A session may have several streams; the first AI-routed stream becomes the
+// primary stream that carries AI → phone audio.
 func (bridge *AudioStreamBridge) LinkSignalWireSession(sessionID string, swSession *SignalWireCallSession) error {
 	bridge.mu.Lock()
 	defer bridge.mu.Unlock()
@@ -172,154 +597,673 @@ func (bridge *AudioStreamBridge) LinkSignalWireSession(sessionID string, swSessi
 		return fmt.Errorf("session not found: %s", sessionID)
 	}
 
+	name := swSession.StreamName
+	if name == "" {
+		name = swSession.ID
+	}
+	route := swSession.Route
+	if route == "" {
+		route = StreamRouteAI
+	}
+
+	// Validate before starting any goroutines, so a rejected stream leaks none
 	session.mu.Lock()
-	session.SignalWireSession = swSession
+	if _, exists := session.streams[name]; exists {
+		session.mu.Unlock()
+		return fmt.Errorf("stream already exists: %s", name)
+	}
+	if swSession.Track == "both" {
+		if _, exists := session.streams[OutboundTrackStreamName(name)]; exists {
+			session.mu.Unlock()
+			return fmt.Errorf("stream already exists: %s", OutboundTrackStreamName(name))
+		}
+	}
+	if route == StreamRouteSupervisor && session.monitorMixer != nil {
+		session.mu.Unlock()
+		return fmt.Errorf("session already has a supervisor: %s", sessionID)
+	}
+
+	stream := &BridgeStream{
+		Name:              name,
+		Route:             route,
+		Track:             swSession.Track,
+		ConnectedAt:       time.Now(),
+		SignalWireSession: swSession,
+		input:             swSession.AudioInChan,
+		Metrics:           &BridgeMetrics{},
+	}
+	if swSession.Track == "outbound" {
+		stream.input = swSession.OutboundTrackChan
+	}
+	if route == StreamRouteTap || route == StreamRouteSupervisor {
+		stream.audio = NewAudioRing(bridge.inboundQueue)
+		stream.audioChan = make(chan []byte)
+		go stream.audio.deliver(session.ctx, stream.audioChan)
+	}
+
+	// With both tracks, the outbound track is a tap stream of its own
+	var outboundTrack *BridgeStream
+	if swSession.Track == "both" {
+		outboundTrack = &BridgeStream{
+			Name:              OutboundTrackStreamName(name),
+			Route:             StreamRouteTap,
+			Track:             "outbound",
+			ConnectedAt:       stream.ConnectedAt,
+			SignalWireSession: swSession,
+			input:             swSession.OutboundTrackChan,
+			audio:             NewAudioRing(bridge.inboundQueue),
+			audioChan:         make(chan []byte),
+			Metrics:           &BridgeMetrics{},
+		}
+		go outboundTrack.audio.deliver(session.ctx, outboundTrack.audioChan)
+	}
+	if route == StreamRouteSupervisor {
+		session.monitorMixer = NewAudioMixer(func(frame []byte) {
+			swSession.SendAudio(frame)
+		})
+		session.monitorMixer.Start(session.ctx)
+		if session.supervisorMode == "" {
+			session.supervisorMode = SupervisorMonitor
+		}
+		session.applySupervisorMode()
+	}
+	session.streams[name] = stream
+	if outboundTrack != nil {
+		session.streams[outboundTrack.Name] = outboundTrack
+	}
+
+	primary := false
+	if route == StreamRouteAI && session.SignalWireSession == nil {
+		session.SignalWireSession = swSession
+		primary = true
+	}
 	session.mu.Unlock()
 
-	log.Printf("[AudioStreamBridge] Linked SignalWire session %s to bridge %s",
-		swSession.ID, sessionID)
+	log.Printf("[AudioStreamBridge] Linked SignalWire session %s to bridge %s (stream: %s, route: %s)",
+		swSession.ID, sessionID, name, route)
 
-	// Start bidirectional audio routing
-	go bridge.routePhoneToAI(session)
-	go bridge.routeAIToPhone(session)
+	publishEvent(bridge.events, streamEvent(EventStreamStarted, session, stream, map[string]interface{}{
+		"track": stream.Track,
+	}))
+
+	// Start audio routing for this stream
+	go bridge.routePhoneToAI(session, stream)
+	if primary {
+		go bridge.routeAIToPhone(session, stream)
+	}
+	if outboundTrack != nil {
+		publishEvent(bridge.events, streamEvent(EventStreamStarted, session, outboundTrack, map[string]interface{}{
+			"track": outboundTrack.Track,
+		}))
+		go bridge.routePhoneToAI(session, outboundTrack)
+	}
 
 	return nil
 }
 
-// ============================================
-// BIDIRECTIONAL AUDIO ROUTING
-// ============================================
+// OutboundTrackStreamName is the name of the tap stream carrying the
+// outbound track (what the caller hears) of a stream requested with both
+// tracks
+func OutboundTrackStreamName(streamName string) string {
+	return streamName + "/outbound"
+}
+
+// GetStreams returns the streams attached to a session
+func (bridge *AudioStreamBridge) GetStreams(sessionID string) ([]*BridgeStream, error) {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
 
-// routePhoneToAI routes audio from phone call to AI pipeline
-func (bridge *AudioStreamBridge) routePhoneToAI(session *BridgeSession) {
 	session.mu.RLock()
-	swSession := session.SignalWireSession
-	session.mu.RUnlock()
+	defer session.mu.RUnlock()
 
-	if swSession == nil {
-		log.Printf("[AudioStreamBridge] No SignalWire session linked for %s", session.ID)
-		return
+	streams := make([]*BridgeStream, 0, len(session.streams))
+	for _, stream := range session.streams {
+		streams = append(streams, stream)
 	}
+	return streams, nil
+}
 
-	log.Printf("[AudioStreamBridge] Starting phone → AI audio routing: %s", session.ID)
+// getStream retrieves a named stream from a session
+func (bridge *AudioStreamBridge) getStream(sessionID, streamName string) (*BridgeStream, error) {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+
+	stream, ok := session.streams[streamName]
+	if !ok {
+		return nil, fmt.Errorf("stream not found: %s", streamName)
+	}
+	return stream, nil
+}
+
+// ============================================
+// BIDIRECTIONAL AUDIO ROUTING
+// ============================================
+
+// routePhoneToAI routes audio from one of the call's streams to the AI
+// pipeline, or to the stream's own channel for tap streams
+func (bridge *AudioStreamBridge) routePhoneToAI(session *BridgeSession, stream *BridgeStream) {
+	swSession := stream.SignalWireSession
+
+	log.Printf("[AudioStreamBridge] Starting phone → AI audio routing: %s (stream: %s)", session.ID, stream.Name)
 
 	// Mark streaming as active
 	session.mu.Lock()
 	session.Streaming = true
-	now := time.Now()
-	session.StartedAt = &now
+	if session.StartedAt == nil {
+		now := time.Now()
+		session.StartedAt = &now
+	}
 	session.mu.Unlock()
 
 	defer func() {
+		// Tap consumers get what's queued, then their channel closes
+		if stream.audio != nil {
+			stream.audio.Close()
+		}
+
 		session.mu.Lock()
-		session.Streaming = false
-		endTime := time.Now()
-		session.EndedAt = &endTime
+		if session.streams[stream.Name] == stream {
+			delete(session.streams, stream.Name)
+		}
+		if session.SignalWireSession == swSession && stream.Route == StreamRouteAI {
+			session.SignalWireSession = nil
+			session.aec = nil // The next leg has its own echo path
+			// The barge mix writes to this leg, so supervision ends with it
+			session.stopSupervisorMixing()
+		}
+		if stream.Route == StreamRouteSupervisor {
+			session.stopSupervisorMixing()
+		}
+		if stream.vad != nil {
+			stream.vad.Close()
+		}
+		if len(session.streams) == 0 {
+			session.Streaming = false
+			endTime := time.Now()
+			session.EndedAt = &endTime
+		}
 		session.mu.Unlock()
+
+		publishEvent(bridge.events, streamEvent(EventStreamStopped, session, stream, nil))
 	}()
 
+	output := session.phoneToAI
+	if stream.Route == StreamRouteTap || stream.Route == StreamRouteSupervisor {
+		output = stream.audio
+	}
+
+	bridge.mu.RLock()
+	silence := bridge.silence
+	bridge.mu.RUnlock()
+	var gate *silenceGate
+
 	for {
 		select {
 		case <-session.ctx.Done():
-			log.Printf("[AudioStreamBridge] Stopping phone → AI routing: %s", session.ID)
+			log.Printf("[AudioStreamBridge] Stopping phone → AI routing: %s (stream: %s)", session.ID, stream.Name)
 			return
 
-		case audioChunk := <-swSession.AudioInChan:
+		case audioChunk, ok := <-stream.input:
+			if !ok {
+				log.Printf("[AudioStreamBridge] Stream disconnected: %s (stream: %s)", session.ID, stream.Name)
+				return
+			}
+
 			startTime := time.Now()
 
 			// Validate audio data
 			if len(audioChunk) == 0 {
+				ReleaseAudioBuffer(audioChunk)
 				continue
 			}
 
-			// Process audio format if needed
-			processedAudio, err := bridge.processIncomingAudio(audioChunk, session)
-			if err != nil {
-				log.Printf("[AudioStreamBridge] Audio processing error: %v", err)
-				continue
+			processedAudio := audioChunk
+
+			// Meter the caller (or for the outbound track, what they hear) as
+			// it sounds on the line
+			stream.Metrics.meterLevel(stream.Track == "outbound", swSession.PipelineFormat(), processedAudio)
+			if stream.Route == StreamRouteAI {
+				session.Metrics.meterLevel(false, swSession.PipelineFormat(), processedAudio)
+				if session.recorder != nil {
+					session.recorder.caller(swSession.PipelineFormat(), processedAudio)
+				}
 			}
 
-			// Send to AI pipeline (non-blocking)
-			select {
-			case session.phoneToAIChan <- processedAudio:
-				session.Metrics.mu.Lock()
-				session.Metrics.PhoneToAIPacketsSent++
-				session.Metrics.BytesReceived += int64(len(audioChunk))
-				session.Metrics.mu.Unlock()
+			// Detect caller speech for turn-taking and barge-in, and digits
+			// from carriers that send DTMF in-band
+			if stream.Route == StreamRouteAI {
+				// Remove the AI's own voice first, so it isn't heard as the caller
+				session.mu.RLock()
+				primary := session.SignalWireSession == swSession
+				session.mu.RUnlock()
+				if primary {
+					if aec := session.echoCanceller(swSession.PipelineFormat()); aec != nil {
+						processedAudio = replaceBuffer(processedAudio, aec.Process(processedAudio))
+					}
+				}
+
+				// Custom stages (noise suppression and the like)
+				if chain := session.filterChain(false, swSession.PipelineFormat()); chain != nil {
+					processedAudio = chain.process(processedAudio)
+				}
+
+				bridge.detectSpeech(session, stream, processedAudio)
+				bridge.detectDigits(session, stream, processedAudio)
+
+				// Normalize the caller's level for STT
+				if agc := session.agc(false, swSession.PipelineFormat()); agc != nil {
+					processedAudio = replaceBuffer(processedAudio, agc.Process(processedAudio))
+				}
+
+				// The AI side hears the recording notice too, past speech
+				// detection so the tone isn't taken for the caller
+				if notice := session.recordingNotice(false, swSession.PipelineFormat()); notice != nil {
+					processedAudio = notice.Process(processedAudio)
+				}
+			}
 
-				// Track latency
-				latency := time.Since(startTime).Microseconds()
-				session.updateLatency(latency)
+			// Feed the supervisor leg: the caller to its ear, itself to the caller when barging
+			monitorMixer, phoneMixer, mode := session.supervisorRouting()
+			if monitorMixer != nil && stream.Route == StreamRouteAI {
+				monitorMixer.Write("caller", processedAudio)
+			}
+			if stream.Route == StreamRouteSupervisor {
+				if mode == SupervisorMonitor {
+					ReleaseAudioBuffer(processedAudio)
+					continue // Listen-only
+				}
+				if phoneMixer != nil {
+					phoneMixer.Write("supervisor", processedAudio)
+				}
+			}
 
-			case <-time.After(10 * time.Millisecond):
-				// Channel full, drop packet
-				session.Metrics.mu.Lock()
-				session.Metrics.PhoneToAIPacketsDropped++
-				session.Metrics.DroppedPackets++
-				session.Metrics.mu.Unlock()
+			// Hold back caller silence from the AI pipeline
+			outgoing := [][]byte{processedAudio}
+			if silence.SuppressSilence && stream.Route == StreamRouteAI && stream.vad != nil {
+				if gate == nil {
+					gate = newSilenceGate(swSession.PipelineFormat(), silence.PreRoll)
+				}
+				if outgoing = gate.filter(processedAudio, stream.vad.Speaking()); len(outgoing) == 0 {
+					for _, m := range []*BridgeMetrics{session.Metrics, stream.Metrics} {
+						m.mu.Lock()
+						m.SuppressedPackets++
+						m.mu.Unlock()
+					}
+					continue
+				}
+			}
+
+			// Queue for the AI pipeline or tap consumer, per the queue's
+			// overflow policy
+			for _, chunk := range outgoing {
+				// Convert to the AI pipeline's format, and copy for
+				// subscribers first; queued, the chunk is the consumer's
+				if output == session.phoneToAI {
+					converted, err := bridge.processIncomingAudio(chunk, session, swSession.PipelineFormat())
+					if err != nil {
+						log.Printf("[AudioStreamBridge] Audio conversion error: %v", err)
+						ReleaseAudioBuffer(chunk)
+						continue
+					}
+					chunk = replaceBuffer(chunk, converted)
+					session.fanOut(chunk)
+				}
+				lost := output.Push(session.ctx, chunk)
+				if lost != nil {
+					// Queue full, drop packet
+					ReleaseAudioBuffer(lost)
+					for _, m := range []*BridgeMetrics{session.Metrics, stream.Metrics} {
+						m.mu.Lock()
+						m.PhoneToAIPacketsDropped++
+						m.DroppedPackets++
+						m.mu.Unlock()
+					}
+
+					log.Printf("[AudioStreamBridge] Phone → AI queue full, dropped packet (stream: %s)", stream.Name)
+					publishEvent(bridge.events, streamEvent(EventPacketDropped, session, stream, map[string]interface{}{
+						"direction": "phone_to_ai",
+					}))
+				}
+				if sameBuffer(lost, chunk) {
+					continue
+				}
+
+				for _, m := range []*BridgeMetrics{session.Metrics, stream.Metrics} {
+					m.mu.Lock()
+					m.PhoneToAIPacketsSent++
+					m.BytesReceived += int64(len(chunk))
+					m.mu.Unlock()
+				}
 
-				log.Printf("[AudioStreamBridge] Phone → AI channel full, dropped packet")
+				// Track latency
+				latency := time.Since(startTime).Microseconds()
+				session.Metrics.updateLatency(latency)
+				stream.Metrics.updateLatency(latency)
 			}
 		}
 	}
 }
 
-// routeAIToPhone routes audio from AI pipeline to phone call
-func (bridge *AudioStreamBridge) routeAIToPhone(session *BridgeSession) {
-	session.mu.RLock()
-	swSession := session.SignalWireSession
-	session.mu.RUnlock()
-
-	if swSession == nil {
-		log.Printf("[AudioStreamBridge] No SignalWire session linked for %s", session.ID)
-		return
-	}
+// routeAIToPhone routes audio from AI pipeline to the call's primary stream
+func (bridge *AudioStreamBridge) routeAIToPhone(session *BridgeSession, stream *BridgeStream) {
+	swSession := stream.SignalWireSession
 
 	log.Printf("[AudioStreamBridge] Starting AI → phone audio routing: %s", session.ID)
 
+	bridge.mu.RLock()
+	silence := bridge.silence
+	bridge.mu.RUnlock()
+
+	// Comfort noise, and the recording notice's tones, fill gaps in AI
+	// audio, paced by the playout estimate
+	var clock playoutClock
+	var noise *comfortNoise
+	ticker := time.NewTicker(comfortNoiseFrame / 2)
+	defer ticker.Stop()
+
 	for {
+		var audioChunk []byte
+		played := false // From PlayAudio, already in the stream's format
 		select {
 		case <-session.ctx.Done():
 			log.Printf("[AudioStreamBridge] Stopping AI → phone routing: %s", session.ID)
 			return
 
-		case audioChunk := <-session.aiToPhoneChan:
-			startTime := time.Now()
+		case now := <-ticker.C:
+			format := swSession.PipelineFormat()
+			notice := session.recordingNotice(true, format)
+			delay := silence.ComfortNoiseDelay
+			if !silence.ComfortNoise {
+				if notice == nil {
+					continue
+				}
+				delay = 0 // Gaps are silent but for the notice
+			}
+			if !clock.wantsNoise(now, delay) {
+				continue
+			}
+			session.mu.RLock()
+			current, barging := session.SignalWireSession, session.phoneMixer != nil
+			session.mu.RUnlock()
+			if current != swSession {
+				log.Printf("[AudioStreamBridge] Primary stream gone, stopping AI → phone routing: %s", session.ID)
+				return
+			}
+			if barging {
+				continue // The supervisor mix owns the caller's audio
+			}
 
-			// Validate audio data
-			if len(audioChunk) == 0 {
+			var frame []byte
+			switch samples := frameBytes(format, comfortNoiseFrame) / frameBytes(format, 0); {
+			case silence.ComfortNoise:
+				if noise == nil || noise.format != format {
+					noise = newComfortNoise(format, silence.ComfortNoiseLevel)
+				}
+				frame = noise.frame(comfortNoiseFrame)
+			case notice.sounding(samples):
+				frame = silenceFrame(format, comfortNoiseFrame)
+			default:
+				// Nothing to send; the notice keeps time through the gap
+				notice.skip(samples)
+				clock.sent(now, format, frameBytes(format, comfortNoiseFrame), false)
 				continue
 			}
+			if notice != nil {
+				frame = notice.Process(frame)
+			}
 
-			// Convert audio format if needed
-			processedAudio, err := bridge.processOutgoingAudio(audioChunk, session)
+			// The frame is the write pump's once sent, so meter it first
+			for _, m := range []*BridgeMetrics{session.Metrics, stream.Metrics} {
+				m.meterLevel(true, format, frame)
+			}
+			if aec := session.echoCanceller(format); aec != nil {
+				aec.Reference(frame)
+			}
+			if session.recorder != nil {
+				session.recorder.played(format, frame)
+			}
+			select {
+			case swSession.AudioOutChan <- frame:
+				clock.sent(now, format, len(frame), false)
+			default:
+				ReleaseAudioBuffer(frame)
+			}
+			continue
+
+		case <-session.aiToPhone.Ready():
+			chunk, ok := session.aiToPhone.TryPop()
+			if !ok {
+				continue
+			}
+			audioChunk = chunk
+
+		case <-session.playback.Ready():
+			chunk, ok := session.playback.TryPop()
+			if !ok {
+				continue
+			}
+			audioChunk, played = chunk, true
+		}
+
+		startTime := time.Now()
+
+		// Validate audio data
+		if len(audioChunk) == 0 {
+			continue
+		}
+
+		// Stop when the primary stream has gone away
+		session.mu.RLock()
+		current := session.SignalWireSession
+		session.mu.RUnlock()
+		if current != swSession {
+			log.Printf("[AudioStreamBridge] Primary stream gone, stopping AI → phone routing: %s", session.ID)
+			return
+		}
+
+		// Convert audio format if needed. The AI's chunk stays its own;
+		// owned tracks whether processedAudio is a buffer of ours.
+		processedAudio := audioChunk
+		if !played {
+			converted, err := bridge.processOutgoingAudio(audioChunk, session, swSession.PipelineFormat())
 			if err != nil {
 				log.Printf("[AudioStreamBridge] Audio conversion error: %v", err)
 				continue
 			}
+			if len(converted) == 0 {
+				continue // Compressed audio short of a whole frame so far
+			}
+			processedAudio = converted
+		}
+		owned := !sameBuffer(processedAudio, audioChunk)
+
+		// Custom stages, on a buffer of ours as filters may work in place
+		if chain := session.filterChain(true, swSession.PipelineFormat()); chain != nil {
+			if !owned {
+				processedAudio = append(GetAudioBuffer(len(processedAudio))[:0], processedAudio...)
+			}
+			processedAudio, owned = chain.process(processedAudio), true
+		}
+
+		// Normalize TTS level for playback
+		if agc := session.agc(true, swSession.PipelineFormat()); agc != nil {
+			normalized := agc.Process(processedAudio)
+			if owned {
+				ReleaseAudioBuffer(processedAudio)
+			}
+			processedAudio, owned = normalized, true
+		}
+
+		// Recording notice, at its set level whatever the TTS level
+		if notice := session.recordingNotice(true, swSession.PipelineFormat()); notice != nil {
+			if !owned {
+				processedAudio = append(GetAudioBuffer(len(processedAudio))[:0], processedAudio...)
+			}
+			processedAudio, owned = notice.Process(processedAudio), true
+		}
 
-			// Send to SignalWire session (non-blocking)
+		// The supervisor hears the AI; when barging, the caller hears the mix
+		monitorMixer, phoneMixer, _ := session.supervisorRouting()
+		if monitorMixer != nil {
+			monitorMixer.Write("ai", processedAudio)
+		}
+		if phoneMixer != nil {
+			phoneMixer.Write("ai", processedAudio)
+			if owned {
+				ReleaseAudioBuffer(processedAudio)
+			}
+			continue
+		}
+
+		// The write pump releases what it sends, so hand it a buffer of
+		// our own, metered before it goes
+		if !owned {
+			processedAudio = append(GetAudioBuffer(len(processedAudio))[:0], processedAudio...)
+		}
+		for _, m := range []*BridgeMetrics{session.Metrics, stream.Metrics} {
+			m.meterLevel(true, swSession.PipelineFormat(), processedAudio)
+		}
+		if aec := session.echoCanceller(swSession.PipelineFormat()); aec != nil {
+			aec.Reference(processedAudio)
+		}
+		if session.recorder != nil {
+			session.recorder.played(swSession.PipelineFormat(), processedAudio)
+		}
+
+		// Send to SignalWire session (non-blocking)
+		select {
+		case swSession.AudioOutChan <- processedAudio:
+			clock.sent(time.Now(), swSession.PipelineFormat(), len(processedAudio), true)
+			for _, m := range []*BridgeMetrics{session.Metrics, stream.Metrics} {
+				m.mu.Lock()
+				m.AIToPhonePacketsSent++
+				m.BytesSent += int64(len(processedAudio))
+				m.mu.Unlock()
+			}
+
+			// Track latency
+			latency := time.Since(startTime).Microseconds()
+			session.Metrics.updateLatency(latency)
+			stream.Metrics.updateLatency(latency)
+
+		case <-time.After(10 * time.Millisecond):
+			// Channel full, drop packet
+			ReleaseAudioBuffer(processedAudio)
+			for _, m := range []*BridgeMetrics{session.Metrics, stream.Metrics} {
+				m.mu.Lock()
+				m.AIToPhonePacketsDropped++
+				m.DroppedPackets++
+				m.mu.Unlock()
+			}
+
+			log.Printf("[AudioStreamBridge] AI → phone channel full, dropped packet")
+			publishEvent(bridge.events, streamEvent(EventPacketDropped, session, stream, map[string]interface{}{
+				"direction": "ai_to_phone",
+			}))
+		}
+	}
+}
+
+// detectSpeech runs the stream's VAD over caller audio and reports speech
+// starting and ending
+func (bridge *AudioStreamBridge) detectSpeech(session *BridgeSession, stream *BridgeStream, audio []byte) {
+	if stream.vad == nil {
+		bridge.mu.RLock()
+		config := bridge.vadConfig
+		bridge.mu.RUnlock()
+		if config == nil || stream.vadFailed {
+			return
+		}
+
+		vad, err := NewVAD(stream.SignalWireSession.PipelineFormat(), *config)
+		if err != nil && config.Algorithm == VADWebRTC {
+			log.Printf("[AudioStreamBridge] WebRTC VAD unavailable (%v), using energy VAD (stream: %s)", err, stream.Name)
+			fallback := *config
+			fallback.Algorithm = VADEnergy
+			vad, err = NewVAD(stream.SignalWireSession.PipelineFormat(), fallback)
+		}
+		if err != nil {
+			log.Printf("[AudioStreamBridge] VAD setup failed, disabling for stream %s: %v", stream.Name, err)
+			stream.vadFailed = true
+			return
+		}
+		stream.vad = vad
+	}
+
+	result, err := stream.vad.Process(audio)
+	if err != nil {
+		log.Printf("[AudioStreamBridge] VAD error (stream: %s): %v", stream.Name, err)
+		return
+	}
+
+	for _, vadEvent := range result.Events {
+		eventType := EventSpeechEnded
+		data := map[string]interface{}{
+			"offset_ms": vadEvent.Offset.Milliseconds(),
+		}
+		if vadEvent.Speaking {
+			eventType = EventSpeechStarted
+		} else {
+			data["duration_ms"] = vadEvent.Duration.Milliseconds()
+		}
+		event := streamEvent(eventType, session, stream, data)
+		event.Timestamp = time.Now()
+
+		session.mu.Lock()
+		session.Speaking = vadEvent.Speaking
+		if session.Active {
 			select {
-			case swSession.AudioOutChan <- processedAudio:
-				session.Metrics.mu.Lock()
-				session.Metrics.AIToPhonePacketsSent++
-				session.Metrics.BytesSent += int64(len(processedAudio))
-				session.Metrics.mu.Unlock()
+			case session.speechChan <- event:
+			default:
+				// Nobody is reading speech events
+			}
+		}
+		session.mu.Unlock()
 
-				// Track latency
-				latency := time.Since(startTime).Microseconds()
-				session.updateLatency(latency)
+		publishEvent(bridge.events, event)
+	}
+}
+
+// detectDigits runs the stream's DTMF detector over caller audio and
+// reports each key press
+func (bridge *AudioStreamBridge) detectDigits(session *BridgeSession, stream *BridgeStream, audio []byte) {
+	if stream.dtmf == nil {
+		bridge.mu.RLock()
+		enabled := bridge.detectDTMF
+		bridge.mu.RUnlock()
+		if !enabled {
+			return
+		}
+		stream.dtmf = NewDTMFDetector(stream.SignalWireSession.PipelineFormat())
+	}
+
+	for _, digit := range stream.dtmf.Process(audio) {
+		event := streamEvent(EventDTMFDetected, session, stream, map[string]interface{}{
+			"digit":     string(digit.Digit),
+			"offset_ms": digit.Offset.Milliseconds(),
+		})
+		event.Timestamp = time.Now()
 
-			case <-time.After(10 * time.Millisecond):
-				// Channel full, drop packet
-				session.Metrics.mu.Lock()
-				session.Metrics.AIToPhonePacketsDropped++
-				session.Metrics.DroppedPackets++
-				session.Metrics.mu.Unlock()
+		log.Printf("[AudioStreamBridge] In-band DTMF %c detected (session: %s, stream: %s)", digit.Digit, session.ID, stream.Name)
 
-				log.Printf("[AudioStreamBridge] AI → phone channel full, dropped packet")
+		session.mu.RLock()
+		if session.Active {
+			select {
+			case session.digitChan <- event:
+			default:
+				// Nobody is reading digit events
 			}
 		}
+		session.mu.RUnlock()
+
+		publishEvent(bridge.events, event)
 	}
 }
 
@@ -327,33 +1271,46 @@ func (bridge *AudioStreamBridge) routeAIToPhone(session *BridgeSession) {
 // AUDIO FORMAT CONVERSION
 // ============================================
 
-// processIncomingAudio processes audio from phone (8kHz mulaw → pipeline format)
-func (bridge *AudioStreamBridge) processIncomingAudio(audioData []byte, session *BridgeSession) ([]byte, error) {
-	// Currently, we pass through mulaw audio directly
-	// The audio pipeline handles mulaw decoding
+// processIncomingAudio converts caller audio from the stream's format to
+// the AI pipeline's input format
+func (bridge *AudioStreamBridge) processIncomingAudio(audioData []byte, session *BridgeSession, format AudioFormat) ([]byte, error) {
+	input, _ := session.aiFormats(format)
 
-	// TODO: Add resampling if pipeline requires different sample rate
-	// TODO: Add codec conversion if needed
+	session.mu.Lock()
+	session.InputFormat = input
+	if session.inConverter == nil {
+		session.inConverter = NewAudioConverter(format.SampleRate, input.SampleRate, format.Channels, input.Channels)
+	}
+	converter := session.inConverter
+	session.mu.Unlock()
 
-	return audioData, nil
+	return converter.ConvertAudio(audioData, format, input)
 }
 
-// processOutgoingAudio processes audio from AI (pipeline format → 8kHz mulaw)
-func (bridge *AudioStreamBridge) processOutgoingAudio(audioData []byte, session *BridgeSession) ([]byte, error) {
-	// Currently, we assume TTS generates mulaw audio
-	// If TTS outputs different format, conversion happens here
+// processOutgoingAudio converts AI audio from the AI pipeline's output
+// format to the stream's format
+func (bridge *AudioStreamBridge) processOutgoingAudio(audioData []byte, session *BridgeSession, format AudioFormat) ([]byte, error) {
+	_, output := session.aiFormats(format)
 
-	// TODO: Add resampling if TTS outputs different sample rate
-	// TODO: Add codec conversion if needed
+	session.mu.Lock()
+	session.OutputFormat = output
+	if session.outConverter == nil {
+		session.outConverter = NewAudioConverter(output.SampleRate, format.SampleRate, output.Channels, format.Channels)
+	}
+	converter := session.outConverter
+	session.mu.Unlock()
 
-	return audioData, nil
+	return converter.ConvertAudio(audioData, output, format)
 }
 
 // ============================================
 // CHANNEL ACCESS
 // ============================================
 
-// GetPhoneToAIChannel returns the channel for phone → AI audio
+// GetPhoneToAIChannel returns the channel for phone → AI audio. Received
+// chunks are the receiver's; hand them to ReleaseAudioBuffer once processed
+// to spare the garbage collector. The channel has one consumer; further
+// consumers (a recorder, a second STT) use SubscribePhoneAudio.
 func (bridge *AudioStreamBridge) GetPhoneToAIChannel(sessionID string) (<-chan []byte, error) {
 	session := bridge.GetSession(sessionID)
 	if session == nil {
@@ -363,7 +1320,8 @@ func (bridge *AudioStreamBridge) GetPhoneToAIChannel(sessionID string) (<-chan [
 	return session.phoneToAIChan, nil
 }
 
-// GetAIToPhoneChannel returns the channel for AI → phone audio
+// GetAIToPhoneChannel returns the channel for AI → phone audio. The bridge
+// copies what it needs, so sent chunks stay the sender's.
 func (bridge *AudioStreamBridge) GetAIToPhoneChannel(sessionID string) (chan<- []byte, error) {
 	session := bridge.GetSession(sessionID)
 	if session == nil {
@@ -373,29 +1331,120 @@ func (bridge *AudioStreamBridge) GetAIToPhoneChannel(sessionID string) (chan<- [
 	return session.aiToPhoneChan, nil
 }
 
+// FlushAIAudio drops AI audio queued for the caller but not yet played,
+// e.g. to stop the AI mid-sentence when the caller talks over it. Audio the
+// AI sends afterwards plays as usual.
+func (bridge *AudioStreamBridge) FlushAIAudio(sessionID string) error {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	// Queued chunks are still the AI's, so they are dropped, not released
+	dropped := 0
+	for {
+		if _, ok := session.aiToPhone.TryPop(); !ok {
+			break
+		}
+		dropped++
+	}
+
+	session.mu.RLock()
+	swSession := session.SignalWireSession
+	session.mu.RUnlock()
+	if swSession != nil {
+		if err := swSession.ClearAudio(); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[AudioStreamBridge] Flushed AI audio (%d queued chunks): %s", dropped, sessionID)
+	return nil
+}
+
+// GetSpeechEvents returns the channel of caller speech start/end events
+// (EventSpeechStarted, EventSpeechEnded) for a session; see SetVAD
+func (bridge *AudioStreamBridge) GetSpeechEvents(sessionID string) (<-chan Event, error) {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	return session.speechChan, nil
+}
+
+// GetDigitEvents returns the channel of in-band DTMF digit events
+// (EventDTMFDetected, digit in Data["digit"]) for a session; see
+// SetDTMFDetection
+func (bridge *AudioStreamBridge) GetDigitEvents(sessionID string) (<-chan Event, error) {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	return session.digitChan, nil
+}
+
+// GetStreamChannel returns the inbound audio channel of a tap stream.
+// Received chunks may be released like those of GetPhoneToAIChannel.
+func (bridge *AudioStreamBridge) GetStreamChannel(sessionID, streamName string) (<-chan []byte, error) {
+	stream, err := bridge.getStream(sessionID, streamName)
+	if err != nil {
+		return nil, err
+	}
+	if stream.audioChan == nil {
+		return nil, fmt.Errorf("stream %s is routed to the AI pipeline, use GetPhoneToAIChannel", streamName)
+	}
+
+	return stream.audioChan, nil
+}
+
 // ============================================
 // METRICS & MONITORING
 // ============================================
 
 // updateLatency updates latency metrics
-func (session *BridgeSession) updateLatency(latencyUs int64) {
-	session.Metrics.mu.Lock()
-	defer session.Metrics.mu.Unlock()
+func (m *BridgeMetrics) updateLatency(latencyUs int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
 	// Calculate rolling average
-	if session.Metrics.AverageLatencyUs == 0 {
-		session.Metrics.AverageLatencyUs = latencyUs
+	if m.AverageLatencyUs == 0 {
+		m.AverageLatencyUs = latencyUs
 	} else {
 		// Exponential moving average (alpha = 0.1)
-		session.Metrics.AverageLatencyUs = (session.Metrics.AverageLatencyUs*9 + latencyUs) / 10
+		m.AverageLatencyUs = (m.AverageLatencyUs*9 + latencyUs) / 10
 	}
 
 	// Track max latency
-	if latencyUs > session.Metrics.MaxLatencyUs {
-		session.Metrics.MaxLatencyUs = latencyUs
+	if latencyUs > m.MaxLatencyUs {
+		m.MaxLatencyUs = latencyUs
 	}
 }
 
+// snapshot returns a copy of the metrics without the mutex
+func (m *BridgeMetrics) snapshot() *BridgeMetrics {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot := &BridgeMetrics{
+		PhoneToAIPacketsSent:    m.PhoneToAIPacketsSent,
+		PhoneToAIPacketsDropped: m.PhoneToAIPacketsDropped,
+		AIToPhonePacketsSent:    m.AIToPhonePacketsSent,
+		AIToPhonePacketsDropped: m.AIToPhonePacketsDropped,
+		AverageLatencyUs:        m.AverageLatencyUs,
+		MaxLatencyUs:            m.MaxLatencyUs,
+		BytesReceived:           m.BytesReceived,
+		BytesSent:               m.BytesSent,
+		DroppedPackets:          m.DroppedPackets,
+		SuppressedPackets:       m.SuppressedPackets,
+		Overruns:                m.Overruns,
+		Underruns:               m.Underruns,
+	}
+	m.fillLevels(snapshot, time.Now())
+	return snapshot
+}
+
 // GetMetrics returns current bridge metrics for a session
 func (bridge *AudioStreamBridge) GetMetrics(sessionID string) (*BridgeMetrics, error) {
 	session := bridge.GetSession(sessionID)
@@ -403,24 +1452,65 @@ func (bridge *AudioStreamBridge) GetMetrics(sessionID string) (*BridgeMetrics, e
 		return nil, fmt.Errorf("session not found: %s", sessionID)
 	}
 
-	session.Metrics.mu.RLock()
-	defer session.Metrics.mu.RUnlock()
+	metrics := session.Metrics.snapshot()
+	metrics.fillQueue(false, session.phoneToAI.Stats())
+	metrics.fillQueue(true, session.aiToPhone.Stats())
+	return metrics, nil
+}
 
-	// Create a copy without the mutex
-	metricsCopy := BridgeMetrics{
-		PhoneToAIPacketsSent:    session.Metrics.PhoneToAIPacketsSent,
-		PhoneToAIPacketsDropped: session.Metrics.PhoneToAIPacketsDropped,
-		AIToPhonePacketsSent:    session.Metrics.AIToPhonePacketsSent,
-		AIToPhonePacketsDropped: session.Metrics.AIToPhonePacketsDropped,
-		AverageLatencyUs:        session.Metrics.AverageLatencyUs,
-		MaxLatencyUs:            session.Metrics.MaxLatencyUs,
-		BytesReceived:           session.Metrics.BytesReceived,
-		BytesSent:               session.Metrics.BytesSent,
-		DroppedPackets:          session.Metrics.DroppedPackets,
-		Overruns:                session.Metrics.Overruns,
-		Underruns:               session.Metrics.Underruns,
+// GetStreamMetrics returns current metrics for a single stream of a session
+func (bridge *AudioStreamBridge) GetStreamMetrics(sessionID, streamName string) (*BridgeMetrics, error) {
+	stream, err := bridge.getStream(sessionID, streamName)
+	if err != nil {
+		return nil, err
 	}
-	return &metricsCopy, nil
+
+	metrics := stream.Metrics.snapshot()
+	if stream.audio != nil {
+		metrics.fillQueue(false, stream.audio.Stats())
+	}
+	return metrics, nil
+}
+
+// GetAudioQueueStats returns the occupancy and counters of a session's
+// inbound (phone → AI) and outbound (AI → phone) audio queues
+func (bridge *AudioStreamBridge) GetAudioQueueStats(sessionID string) (AudioRingStats, AudioRingStats, error) {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return AudioRingStats{}, AudioRingStats{}, fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	return session.phoneToAI.Stats(), session.aiToPhone.Stats(), nil
+}
+
+// fillQueue sets a snapshot's occupancy fields for one direction
+func (m *BridgeMetrics) fillQueue(outbound bool, stats AudioRingStats) {
+	if outbound {
+		m.OutboundQueueDepth, m.OutboundQueueHighWater = stats.Depth, stats.HighWater
+		return
+	}
+	m.InboundQueueDepth, m.InboundQueueHighWater = stats.Depth, stats.HighWater
+}
+
+// dropOutbound records AI audio lost to a full outbound queue. The audio
+// is the AI's, so it isn't released.
+func (bridge *AudioStreamBridge) dropOutbound(session *BridgeSession) {
+	session.Metrics.mu.Lock()
+	session.Metrics.AIToPhonePacketsDropped++
+	session.Metrics.DroppedPackets++
+	session.Metrics.mu.Unlock()
+
+	session.mu.RLock()
+	callSID := session.CallSID
+	session.mu.RUnlock()
+
+	log.Printf("[AudioStreamBridge] AI → phone queue full, dropped packet: %s", session.ID)
+	publishEvent(bridge.events, Event{
+		Type:      EventPacketDropped,
+		CallSID:   callSID,
+		SessionID: session.SessionID,
+		Data:      map[string]interface{}{"direction": "ai_to_phone"},
+	})
 }
 
 // GetSessionStatus returns the status of a bridge session
@@ -433,9 +1523,16 @@ func (bridge *AudioStreamBridge) GetSessionStatus(sessionID string) (map[string]
 	session.mu.RLock()
 	defer session.mu.RUnlock()
 
+	streams := make([]string, 0, len(session.streams))
+	for name := range session.streams {
+		streams = append(streams, name)
+	}
+
 	status := map[string]interface{}{
 		"id":              session.ID,
 		"session_id":      session.SessionID,
+		"call_sid":        session.CallSID,
+		"streams":         streams,
 		"active":          session.Active,
 		"streaming":       session.Streaming,
 		"created_at":      session.CreatedAt,
@@ -474,6 +1571,13 @@ func (s *BridgeSession) GetContext() context.Context {
 	return s.ctx
 }
 
+// IsSpeaking returns whether the caller is talking, per the VAD
+func (s *BridgeSession) IsSpeaking() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Speaking
+}
+
 // IsActive returns whether the session is active
 func (s *BridgeSession) IsActive() bool {
 	s.mu.RLock()
@@ -500,11 +1604,24 @@ func (bridge *AudioStreamBridge) CloseSession(sessionID string) error {
 	session.cancel()
 	session.mu.Unlock()
 
-	// Close channels
-	close(session.phoneToAIChan)
+	// Close queues; the phone → AI channel closes once its pump stops
+	session.phoneToAI.Close()
+	session.aiToPhone.Close()
+	session.playback.Close()
 	close(session.aiToPhoneChan)
+	bridge.finishRecording(session)
+
+	session.mu.Lock()
+	close(session.speechChan)
+	close(session.digitChan)
+	session.closeSubscriptions()
+	session.closeFilters()
+	session.mu.Unlock()
 
 	delete(bridge.sessions, sessionID)
+	if session.CallSID != "" {
+		delete(bridge.callSIDs, session.CallSID)
+	}
 
 	log.Printf("[AudioStreamBridge] Closed session: %s", sessionID)
 	return nil
@@ -514,11 +1631,8 @@ func (bridge *AudioStreamBridge) CloseSession(sessionID string) error {
 func (bridge *AudioStreamBridge) Close() error {
 	bridge.cancel()
 
-	bridge.mu.Lock()
-	defer bridge.mu.Unlock()
-
-	// Close all sessions
-	for sessionID := range bridge.sessions {
+	// Close all sessions (CloseSession takes the lock itself)
+	for _, sessionID := range bridge.ListSessionIDs() {
 		bridge.CloseSession(sessionID)
 	}
 
@@ -0,0 +1,77 @@
+package telephony
+
+import (
+	"math/bits"
+	"sync"
+	"unsafe"
+)
+
+// ============================================
+// AUDIO BUFFER POOL
+// Reusable byte slices for audio chunks, keeping GC pressure down at high
+// call volumes
+// ============================================
+//
+// Ownership rules along the bridge:
+// - Chunks received from the phone → AI channel (and tap/supervisor stream
+//   channels) belong to the receiver, who may release them once done
+// - Chunks sent on the AI → phone channel stay the sender's; the bridge
+//   never keeps or releases them
+// - AudioConverter results are pooled, except when no conversion was needed
+//   and the input is returned as is
+// - Audio on a SignalWireCallSession's AudioInChan and AudioOutChan is owned
+//   by the receiving side, which releases it
+// ============================================
+
+// Buffers are pooled in power-of-two capacity classes from 64B to 64KB. The
+// pools hold pointers to the backing arrays, which box into an interface
+// without allocating as a *[]byte would.
+const (
+	audioBufferMinShift = 6
+	audioBufferMaxShift = 16
+)
+
+var audioBufferPools [audioBufferMaxShift - audioBufferMinShift + 1]sync.Pool
+
+// GetAudioBuffer returns a buffer of length n, reusing a released one when
+// possible. Its contents are undefined.
+func GetAudioBuffer(n int) []byte {
+	if n > 1<<audioBufferMaxShift {
+		return make([]byte, n)
+	}
+	class := 0
+	if n > 1<<audioBufferMinShift {
+		class = bits.Len(uint(n-1)) - audioBufferMinShift
+	}
+	size := 1 << (class + audioBufferMinShift)
+	if data, ok := audioBufferPools[class].Get().(*byte); ok {
+		return unsafe.Slice(data, size)[:n]
+	}
+	return make([]byte, n, size)
+}
+
+// ReleaseAudioBuffer hands a buffer back for reuse by GetAudioBuffer. Only
+// release a buffer you own, once nothing else references it; the pool
+// ignores buffers whose capacity isn't one of its classes.
+func ReleaseAudioBuffer(buf []byte) {
+	c := cap(buf)
+	if c < 1<<audioBufferMinShift || c > 1<<audioBufferMaxShift || c&(c-1) != 0 {
+		return
+	}
+	audioBufferPools[bits.Len(uint(c))-1-audioBufferMinShift].Put(unsafe.SliceData(buf))
+}
+
+// sameBuffer reports whether two slices start at the same array element,
+// i.e. a processing stage passed its input through
+func sameBuffer(a, b []byte) bool {
+	return cap(a) > 0 && cap(b) > 0 && &a[:1][0] == &b[:1][0]
+}
+
+// replaceBuffer returns next, the output of a processing stage given prev,
+// releasing prev unless the stage passed it through
+func replaceBuffer(prev, next []byte) []byte {
+	if !sameBuffer(prev, next) {
+		ReleaseAudioBuffer(prev)
+	}
+	return next
+}
@@ -0,0 +1,94 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================
+// CALL BRIDGING
+// Connecting two independently placed calls
+// ============================================
+
+// Bridge describes two live calls joined in a conference
+type Bridge struct {
+	ID             uuid.UUID `json:"id"`
+	ConferenceName string    `json:"conference_name"`
+	CallSIDs       [2]string `json:"call_sids"`
+	StartedAt      time.Time `json:"started_at"`
+}
+
+// BridgeCalls connects two answered calls, e.g. for click-to-call where the
+// agent is dialed first and then the customer. Both legs are moved into a
+// private conference; when either hangs up the conference ends and the
+// other leg is disconnected.
+func (ci *CallInitiator) BridgeCalls(ctx context.Context, callSIDa, callSIDb string) (*Bridge, error) {
+	if callSIDa == "" || callSIDb == "" {
+		return nil, fmt.Errorf("both call SIDs are required")
+	}
+	if callSIDa == callSIDb {
+		return nil, fmt.Errorf("cannot bridge call %s to itself", callSIDa)
+	}
+
+	sessions := make([]*CallSession, 2)
+	for i, callSID := range []string{callSIDa, callSIDb} {
+		session, err := ci.lookupSession(ctx, callSID)
+		if err != nil {
+			return nil, err
+		}
+		session.mu.RLock()
+		state := session.State
+		session.mu.RUnlock()
+		if state != StateAnswered && state != StateInProgress {
+			return nil, fmt.Errorf("call %s is not answered (state %s)", callSID, state)
+		}
+		sessions[i] = session
+	}
+
+	bridge := &Bridge{
+		ID:        uuid.New(),
+		CallSIDs:  [2]string{callSIDa, callSIDb},
+		StartedAt: time.Now(),
+	}
+	bridge.ConferenceName = fmt.Sprintf("bridge-%s", bridge.ID)
+
+	join := NewTwiML().Dial(Dial{
+		Conference: &Conference{
+			Name:                   bridge.ConferenceName,
+			StartConferenceOnEnter: boolPtr(true),
+			EndConferenceOnExit:    true,
+			MaxParticipants:        2,
+			Beep:                   "false",
+		},
+	})
+
+	if err := ci.updateLiveCall(ctx, callSIDa, join); err != nil {
+		return nil, fmt.Errorf("failed to redirect call %s: %w", callSIDa, err)
+	}
+	if err := ci.updateLiveCall(ctx, callSIDb, join); err != nil {
+		// Don't leave the first leg waiting alone in the conference
+		ci.HangupCall(context.Background(), callSIDa)
+		return nil, fmt.Errorf("failed to redirect call %s: %w", callSIDb, err)
+	}
+
+	// Record the bridge on both sessions
+	for i, session := range sessions {
+		other := bridge.CallSIDs[1-i]
+
+		_, err := ci.updateSession(ctx, session, func(session *CallSession) error {
+			session.Metadata.Set("bridge_id", bridge.ID.String())
+			session.Metadata.Set("bridged_with", other)
+			return nil
+		})
+		if err != nil {
+			log.Printf("[CallInitiator] Failed to record bridge on session: %v", err)
+		}
+	}
+
+	log.Printf("[CallInitiator] Bridged calls %s and %s in %s", callSIDa, callSIDb, bridge.ConferenceName)
+	return bridge, nil
+}
@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"path"
+	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -21,15 +25,73 @@ type CallHandlers struct {
 	callInitiator *CallInitiator
 	audioBridge   *SignalWireAudioBridge
 	streamBridge  *AudioStreamBridge
+
+	// Additional streams requested for every incoming call
+	tapStreams []TapStream
+
+	// Codec requested for the primary stream (empty = SignalWire's default mulaw)
+	streamCodec string
+
+	// Digit handlers for <Gather> results
+	gather *gatherRegistry
+
+	// Post-call surveys calls can be sent to
+	surveys *surveyRegistry
+
+	// Event bus shared with the initiator and bridge (live transcripts)
+	events EventBus
+
+	// Set once Drain starts; incoming calls get 503
+	draining atomic.Bool
+}
+
+// TapStream describes an extra media stream attached to each call alongside
+// the primary AI stream (e.g. a recorder or analytics consumer)
+type TapStream struct {
+	Name  string // Stream name, used with AudioStreamBridge.GetStreamChannel
+	Track string // "inbound", "outbound", "both"
 }
 
 // NewCallHandlers creates a new call handlers instance
 func NewCallHandlers(initiator *CallInitiator, audioBridge *SignalWireAudioBridge, streamBridge *AudioStreamBridge) *CallHandlers {
-	return &CallHandlers{
+	h := &CallHandlers{
 		callInitiator: initiator,
 		audioBridge:   audioBridge,
 		streamBridge:  streamBridge,
+		gather:        newGatherRegistry(),
+		surveys:       newSurveyRegistry(),
+	}
+	if OpusAvailable() {
+		h.streamCodec = StreamCodecOpus
 	}
+	return h
+}
+
+// SetStreamCodec sets the codec requested for each call's primary media
+// stream. Builds with Opus support request StreamCodecOpus by default; its
+// audio reaches the pipeline as 16kHz PCM (see
+// SignalWireCallSession.PipelineFormat). Pass "" for mulaw.
+func (h *CallHandlers) SetStreamCodec(codec string) error {
+	if isOpusEncoding(codec) && !OpusAvailable() {
+		return ErrOpusUnavailable
+	}
+	h.streamCodec = codec
+	return nil
+}
+
+// SetEventBus wires the initiator and audio bridge to one event bus
+func (h *CallHandlers) SetEventBus(bus EventBus) {
+	h.events = bus
+	h.callInitiator.SetEventBus(bus)
+	h.streamBridge.SetEventBus(bus)
+}
+
+// AddTapStream requests an additional media stream for every incoming call
+func (h *CallHandlers) AddTapStream(name, track string) {
+	if track == "" {
+		track = "inbound"
+	}
+	h.tapStreams = append(h.tapStreams, TapStream{Name: name, Track: track})
 }
 
 // ============================================
@@ -53,6 +115,7 @@ type Stream struct {
 	XMLName    xml.Name `xml:"Stream"`
 	URL        string   `xml:"url,attr"`
 	Track      string   `xml:"track,attr"` // "inbound", "outbound", "both"
+	Codec      string   `xml:"codec,attr,omitempty"`
 }
 
 // ============================================
@@ -67,6 +130,11 @@ func (h *CallHandlers) HandleIncomingCall(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// Outbound calls already placed still need their stream while draining
+	if !InboundCallParamsFromRequest(r).IsOutbound() && h.rejectWhileDraining(w) {
+		return
+	}
+
 	// Extract call parameters
 	callSID := r.FormValue("CallSid")
 	from := r.FormValue("From")
@@ -80,6 +148,12 @@ func (h *CallHandlers) HandleIncomingCall(w http.ResponseWriter, r *http.Request
 
 	log.Printf("[CallHandlers] Incoming call: %s (from: %s, to: %s)", callSID, from, to)
 
+	// Outbound calls answered with AMD take their human/machine branch
+	// (e.g. a voicemail drop) instead of streaming
+	if InboundCallParamsFromRequest(r).IsOutbound() && h.answerWithAMDBranch(w, r) {
+		return
+	}
+
 	// Create bridge session
 	sessionID := uuid.New().String()
 	_, err := h.streamBridge.CreateSession(sessionID)
@@ -89,8 +163,26 @@ func (h *CallHandlers) HandleIncomingCall(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if err := h.streamBridge.BindCallSID(sessionID, callSID); err != nil {
+		log.Printf("[CallHandlers] Failed to bind call SID: %v", err)
+	}
+
 	log.Printf("[CallHandlers] Created bridge session: %s for call: %s", sessionID, callSID)
 
+	// Tie the bridge session to the call's lifecycle session: outbound calls
+	// already have one, inbound calls get one now
+	params := InboundCallParamsFromRequest(r)
+	params.BridgeSessionID = sessionID
+	if params.IsOutbound() {
+		if _, err := h.callInitiator.AttachBridgeSession(r.Context(), callSID, sessionID); err != nil {
+			log.Printf("[CallHandlers] Failed to attach bridge session: %v", err)
+			h.callInitiator.Errors().Record("handlers", callSID, err)
+		}
+	} else if _, err := h.callInitiator.RegisterInboundCall(r.Context(), params); err != nil {
+		log.Printf("[CallHandlers] Failed to register inbound call: %v", err)
+		h.callInitiator.Errors().Record("handlers", callSID, err)
+	}
+
 	// Construct WebSocket URL for SignalWire
 	scheme := "https"
 	if r.TLS != nil {
@@ -105,19 +197,38 @@ func (h *CallHandlers) HandleIncomingCall(w http.ResponseWriter, r *http.Request
 		scheme, host, sessionID)
 
 	// Add query parameters
-	wsURL = fmt.Sprintf("%s?call_sid=%s", wsURL, callSID)
+	baseURL := wsURL
+	wsURL = fmt.Sprintf("%s?session_id=%s&call_sid=%s&track=both", baseURL, sessionID, callSID)
 
 	log.Printf("[CallHandlers] WebSocket URL: %s", wsURL)
 
+	streams := []Stream{
+		{
+			URL:   wsURL,
+			Track: "both", // Stream both inbound and outbound audio
+			Codec: h.streamCodec,
+		},
+	}
+
+	// Additional tap streams share the same bridge session
+	for _, tap := range h.tapStreams {
+		query := url.Values{
+			"session_id": {sessionID},
+			"call_sid":   {callSID},
+			"stream":     {tap.Name},
+			"route":      {string(StreamRouteTap)},
+			"track":      {tap.Track},
+		}
+		streams = append(streams, Stream{
+			URL:   baseURL + "?" + query.Encode(),
+			Track: tap.Track,
+		})
+	}
+
 	// Generate TwiML with WebSocket streaming
 	twiml := &TwiMLResponse{
 		Start: &Start{
-			Streams: []Stream{
-				{
-					URL:   wsURL,
-					Track: "both", // Stream both inbound and outbound audio
-				},
-			},
+			Streams: streams,
 		},
 	}
 
@@ -156,38 +267,41 @@ func (h *CallHandlers) HandleCallStateChange(w http.ResponseWriter, r *http.Requ
 	log.Printf("[CallHandlers] Call state change: %s (status: %s)", callSID, callStatus)
 
 	// Map SignalWire status to CallState
-	var newState CallState
-	switch callStatus {
-	case "ringing":
-		newState = StateRinging
-	case "in-progress", "answered":
-		newState = StateAnswered
-	case "completed":
-		newState = StateCompleted
-	case "failed", "error":
-		newState = StateFailed
-	case "no-answer":
-		newState = StateNoAnswer
-	case "busy":
-		newState = StateBusy
-	case "canceled":
-		newState = StateCancelled
-	default:
-		log.Printf("[CallHandlers] Unknown call status: %s", callStatus)
-		newState = StateFailed
+	newState, ok := StateFromStatus(callStatus)
+	if !ok {
+		// Don't end the call over a status we don't understand
+		log.Printf("[CallHandlers] Ignoring unknown call status %q for call: %s", callStatus, callSID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// SignalWire includes ordering info so retried/out-of-order webhooks can be detected
+	event := CallEvent{
+		CallSID: callSID,
+		State:   newState,
 	}
+	if seq, err := strconv.Atoi(r.FormValue("SequenceNumber")); err == nil {
+		event.SequenceNumber = seq
+	}
+	if ts, err := time.Parse(time.RFC1123Z, r.FormValue("Timestamp")); err == nil {
+		event.Timestamp = ts
+	}
+	if newState.IsTerminal() {
+		event.HangupCause, event.SIPResponseCode = HangupCauseFromRequest(r)
+	}
+	event.Attestation, event.Verstat = AttestationFromRequest(r)
 
 	// Update call state in initiator
 	ctx := context.Background()
-	if err := h.callInitiator.UpdateCallState(ctx, callSID, newState, nil); err != nil {
+	if err := h.callInitiator.ApplyCallEvent(ctx, event); err != nil {
 		log.Printf("[CallHandlers] Failed to update call state: %v", err)
+		h.callInitiator.Errors().Record("handlers", callSID, err)
 		// Don't return error - SignalWire doesn't care about our internal state
 	}
 
 	// Handle call completion
-	if newState == StateCompleted || newState == StateFailed ||
-	   newState == StateNoAnswer || newState == StateBusy ||
-	   newState == StateCancelled {
+	if newState.IsTerminal() {
+		h.clearGather(callSID)
 
 		// Find and close associated bridge session
 		if swSession := h.audioBridge.GetCallSessionBySignalWireSID(callSID); swSession != nil {
@@ -248,7 +362,13 @@ func (h *CallHandlers) HandleBridgeMetrics(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	metrics, err := h.streamBridge.GetMetrics(sessionID)
+	var metrics *BridgeMetrics
+	var err error
+	if streamName := r.URL.Query().Get("stream"); streamName != "" {
+		metrics, err = h.streamBridge.GetStreamMetrics(sessionID, streamName)
+	} else {
+		metrics, err = h.streamBridge.GetMetrics(sessionID)
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
@@ -267,12 +387,22 @@ func (h *CallHandlers) HandleBridgeMetrics(w http.ResponseWriter, r *http.Reques
 
 // RegisterRoutes registers all call handler routes
 func (h *CallHandlers) RegisterRoutes(mux *http.ServeMux) {
-	// TwiML endpoints
-	mux.HandleFunc("/api/telephony/calls/incoming", h.HandleIncomingCall)
-	mux.HandleFunc("/api/telephony/calls/status", h.HandleCallStateChange)
+	// TwiML endpoints (forwarded to the call's owner, see SetCallOwnership)
+	mux.HandleFunc("/api/telephony/calls/incoming", h.sticky(h.HandleIncomingCall))
+	mux.HandleFunc("/api/telephony/calls/status", h.sticky(h.HandleCallStateChange))
+	mux.HandleFunc(GatherPath, h.sticky(h.HandleGather))
+	mux.HandleFunc(legacyGatherPath, h.sticky(h.HandleGather))
+	mux.HandleFunc(VoicemailDropPath, h.sticky(h.HandleVoicemailDrop))
+	mux.HandleFunc(AMDPath, h.sticky(h.HandleAMDResult))
+	mux.HandleFunc(SurveyPath, h.sticky(h.HandleSurvey))
+	mux.HandleFunc(RecordingPath, h.sticky(h.HandleRecordingStatus))
+	mux.HandleFunc(DialGroupAnswerPath, h.sticky(h.HandleDialGroupAnswer))
+	mux.HandleFunc(DialGroupResultPath, h.sticky(h.HandleDialGroupResult))
+	mux.HandleFunc(ClickToCallAnswerPath, h.sticky(h.HandleClickToCallAnswer))
+	mux.HandleFunc(AnswerPath, h.sticky(h.HandleAnswer))
 
 	// WebSocket endpoint
-	mux.HandleFunc("/api/telephony/calls/stream/", h.HandleCallStream)
+	mux.HandleFunc("/api/telephony/calls/stream/", h.sticky(h.HandleCallStream))
 
 	// Status endpoints
 	mux.HandleFunc("/api/telephony/calls/bridge/status", h.HandleBridgeStatus)
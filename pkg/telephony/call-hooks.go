@@ -0,0 +1,154 @@
+package telephony
+
+import (
+	"sync"
+)
+
+// ============================================
+// CALL LIFECYCLE HOOKS
+// Application callbacks for call lifecycle events
+// ============================================
+
+// VoicemailEvent is delivered when voicemail is detected on a call
+type VoicemailEvent struct {
+	Call        CallSummary
+	MessageLeft bool
+}
+
+// AMDEvent is delivered when answering machine detection reports a result
+type AMDEvent struct {
+	Call       CallSummary
+	AnsweredBy AnsweredBy
+}
+
+// RecordingEvent is delivered when a call's recording is available
+type RecordingEvent struct {
+	Call            CallSummary
+	URL             string
+	DurationSeconds int
+	SID             string
+	Channels        int
+	StoredAt        string // Where RecordingStorage saved the media, if set
+}
+
+// callHooks holds the registered lifecycle callbacks
+type callHooks struct {
+	stateChange []func(CallSummary)
+	answered    []func(CallSummary)
+	completed   []func(CallSummary)
+	voicemail   []func(VoicemailEvent)
+	amd         []func(AMDEvent)
+	recording   []func(RecordingEvent)
+	sentiment   []sentimentListener
+	mu          sync.RWMutex
+}
+
+// OnStateChange registers a listener called after each call state
+// transition (e.g. to track answers and free dialer capacity on hangup)
+func (ci *CallInitiator) OnStateChange(listener func(CallSummary)) {
+	ci.hooks.mu.Lock()
+	defer ci.hooks.mu.Unlock()
+	ci.hooks.stateChange = append(ci.hooks.stateChange, listener)
+}
+
+// OnAnswered registers a listener called when a call is answered
+func (ci *CallInitiator) OnAnswered(listener func(CallSummary)) {
+	ci.hooks.mu.Lock()
+	defer ci.hooks.mu.Unlock()
+	ci.hooks.answered = append(ci.hooks.answered, listener)
+}
+
+// OnCompleted registers a listener called once a call reaches a terminal
+// state (completed, failed, busy, no-answer or cancelled)
+func (ci *CallInitiator) OnCompleted(listener func(CallSummary)) {
+	ci.hooks.mu.Lock()
+	defer ci.hooks.mu.Unlock()
+	ci.hooks.completed = append(ci.hooks.completed, listener)
+}
+
+// OnVoicemail registers a listener called when voicemail is detected
+func (ci *CallInitiator) OnVoicemail(listener func(VoicemailEvent)) {
+	ci.hooks.mu.Lock()
+	defer ci.hooks.mu.Unlock()
+	ci.hooks.voicemail = append(ci.hooks.voicemail, listener)
+}
+
+// OnAMDResult registers a listener called with each AMD result
+func (ci *CallInitiator) OnAMDResult(listener func(AMDEvent)) {
+	ci.hooks.mu.Lock()
+	defer ci.hooks.mu.Unlock()
+	ci.hooks.amd = append(ci.hooks.amd, listener)
+}
+
+// OnRecordingReady registers a listener called when a recording is stored
+func (ci *CallInitiator) OnRecordingReady(listener func(RecordingEvent)) {
+	ci.hooks.mu.Lock()
+	defer ci.hooks.mu.Unlock()
+	ci.hooks.recording = append(ci.hooks.recording, listener)
+}
+
+// fireTransition delivers an applied state transition to the state-change
+// listeners, then to the answered or completed listeners
+func (h *callHooks) fireTransition(summary CallSummary) {
+	h.mu.RLock()
+	listeners := append([]func(CallSummary){}, h.stateChange...)
+	switch {
+	case summary.State == StateAnswered:
+		listeners = append(listeners, h.answered...)
+	case summary.State.IsTerminal():
+		listeners = append(listeners, h.completed...)
+	}
+	h.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(summary)
+	}
+}
+
+// fireVoicemail delivers a voicemail detection
+func (h *callHooks) fireVoicemail(event VoicemailEvent) {
+	h.mu.RLock()
+	listeners := append([]func(VoicemailEvent){}, h.voicemail...)
+	h.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// fireAMD delivers an AMD result
+func (h *callHooks) fireAMD(event AMDEvent) {
+	h.mu.RLock()
+	listeners := append([]func(AMDEvent){}, h.amd...)
+	h.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// fireRecording delivers a stored recording
+func (h *callHooks) fireRecording(event RecordingEvent) {
+	h.mu.RLock()
+	listeners := append([]func(RecordingEvent){}, h.recording...)
+	h.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// fireSentiment delivers a caller turn's sentiment to the listeners whose
+// threshold it dropped below; previous is the call's last score, if any
+func (h *callHooks) fireSentiment(call CallSummary, sentiment Sentiment, summary SentimentSummary, previous *float64) {
+	h.mu.RLock()
+	listeners := append([]sentimentListener{}, h.sentiment...)
+	h.mu.RUnlock()
+
+	for _, l := range listeners {
+		if sentiment.Score >= l.threshold || (previous != nil && *previous < l.threshold) {
+			continue
+		}
+		l.listener(SentimentEvent{Call: call, Sentiment: sentiment, Summary: summary, Threshold: l.threshold})
+	}
+}
@@ -3,16 +3,21 @@ package telephony
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/birddigital/signalwire-telephony/pkg/signalwire"
 )
 
 // ============================================
@@ -25,25 +30,221 @@ type CallInitiator struct {
 	projectID    string
 	authToken    string
 	space        string
-	baseURL      string
+	endpoints    *signalwire.Endpoints
 	httpClient   *http.Client
-	db           *pgxpool.Pool
+	store        CallSessionStore
+	schedules    ScheduleStore
+	surveys      SurveyStore
+	transcripts  TranscriptStore
 
 	// Active call tracking
 	activeCalls sync.Map // callSID -> *CallSession
 	callsMutex  sync.RWMutex
+
+	// Max-duration timers for answered calls
+	durationTimers sync.Map // callSID -> *time.Timer
+
+	// Optional status polling, for calls without status callbacks
+	statusPolling *StatusPollingConfig
+	pollers       sync.Map // callSID -> struct{}
+
+	// Per-call answer handlers
+	answerHandlers sync.Map // session ID -> AnswerHandler
+
+	// Per-locale voice/script selection
+	localeProfiles *LocaleProfiles
+
+	// Recent failures for monitoring
+	errorLog *ErrorLog
+
+	// Agency assignment for inbound calls
+	inboundAgencyResolver InboundAgencyResolver
+
+	// SIP passwords for calls whose config has none (e.g. scheduled calls)
+	sipPasswords SIPPasswordResolver
+
+	// Lowest expected STIR/SHAKEN attestation (default A)
+	minAttestation Attestation
+
+	// Audio bridge for in-band audio (DTMF tones)
+	audioBridge *AudioStreamBridge
+
+	// Conversation summaries for agent handoffs
+	summarizer ConversationSummarizer
+
+	// Wrap-up codes each agency may record
+	dispositions *Dispositions
+
+	// Lifecycle hooks
+	hooks callHooks
+
+	// Optional event bus for call events
+	events EventBus
+
+	// Set once Drain starts; new calls are refused
+	draining atomic.Bool
+
+	// Optional per-agency concurrency caps
+	agencyLimits *AgencyLimits
+
+	// Public base URL of our webhook routes, for callbacks in inline TwiML
+	publicBaseURL string
+
+	// Optional owner records for routing webhooks between instances
+	ownership *CallOwnership
+
+	// Optional copies of recording media
+	recordingStorage RecordingStorage
+
+	// Registers the listener ending click-to-call legs together
+	clickToCallOnce sync.Once
 }
 
-// NewCallInitiator creates a new SignalWire call initiator
+// NewCallInitiator creates a new SignalWire call initiator. Sessions,
+// scheduled calls, survey responses and transcripts are stored in Postgres
+// when db is set, otherwise in memory; use SetSessionStore,
+// SetScheduleStore, SetSurveyStore and SetTranscriptStore for other
+// backends.
 func NewCallInitiator(projectID, authToken, space string, db *pgxpool.Pool) *CallInitiator {
+	var store CallSessionStore
+	var schedules ScheduleStore
+	var surveys SurveyStore
+	var transcripts TranscriptStore
+	if db != nil {
+		store = NewPostgresSessionStore(db)
+		schedules = NewPostgresScheduleStore(db)
+		surveys = NewPostgresSurveyStore(db)
+		transcripts = NewPostgresTranscriptStore(db)
+	} else {
+		store = NewMemorySessionStore()
+		schedules = NewMemoryScheduleStore()
+		surveys = NewMemorySurveyStore()
+		transcripts = NewMemoryTranscriptStore()
+	}
+
 	return &CallInitiator{
 		projectID:  projectID,
 		authToken:  authToken,
 		space:      space,
-		baseURL:    fmt.Sprintf("https://%s/api/laml/2010-04-01", space),
+		endpoints:  signalwire.NewEndpoints(space),
 		httpClient: &http.Client{Timeout: 30 * time.Second},
-		db:         db,
+		store:      store,
+		schedules:  schedules,
+		surveys:    surveys,
+		transcripts: transcripts,
+		errorLog:   NewErrorLog(100),
+		dispositions: NewDispositions(DefaultDispositions()),
+	}
+}
+
+// SetSessionStore replaces the call session store
+func (ci *CallInitiator) SetSessionStore(store CallSessionStore) {
+	ci.store = store
+}
+
+// SessionStore returns the call session store
+func (ci *CallInitiator) SessionStore() CallSessionStore {
+	return ci.store
+}
+
+// Errors returns the log of recent call errors
+func (ci *CallInitiator) Errors() *ErrorLog {
+	return ci.errorLog
+}
+
+// SetEndpoints configures regional/edge hosts to try before the space URL
+func (ci *CallInitiator) SetEndpoints(hosts ...string) {
+	ci.endpoints = signalwire.NewEndpoints(append(hosts, ci.space)...)
+}
+
+// SetTransport configures proxy, dialer and TLS settings for API requests
+func (ci *CallInitiator) SetTransport(config signalwire.TransportConfig) error {
+	httpClient, err := config.HTTPClient()
+	if err != nil {
+		return err
+	}
+	ci.httpClient = httpClient
+	return nil
+}
+
+// SetLocaleProfiles enables automatic voice, language and script selection
+// from each call's Locale, and per-locale performance tracking
+func (ci *CallInitiator) SetLocaleProfiles(profiles *LocaleProfiles) {
+	ci.localeProfiles = profiles
+}
+
+// SetAudioBridge connects the initiator to the audio bridge carrying its
+// calls' media, enabling in-band tone injection
+func (ci *CallInitiator) SetAudioBridge(bridge *AudioStreamBridge) {
+	ci.audioBridge = bridge
+}
+
+// SetEventBus publishes call events (initiated, answered, completed) to bus
+func (ci *CallInitiator) SetEventBus(bus EventBus) {
+	ci.events = bus
+}
+
+// SetPublicBaseURL sets the externally reachable base URL (e.g.
+// https://calls.example.com) of the routes registered by CallHandlers.
+// Features that hand SignalWire callback URLs in inline TwiML need it.
+func (ci *CallInitiator) SetPublicBaseURL(baseURL string) {
+	ci.publicBaseURL = strings.TrimRight(baseURL, "/")
+}
+
+// SIPPasswordResolver returns the digest password of a SIP username for a
+// sip: target
+type SIPPasswordResolver func(ctx context.Context, to, username string) (string, error)
+
+// SetSIPPasswordResolver supplies SIP passwords for calls with a SIPUsername
+// but no SIPPassword. Scheduled calls need it: passwords are never stored.
+func (ci *CallInitiator) SetSIPPasswordResolver(resolver SIPPasswordResolver) {
+	ci.sipPasswords = resolver
+}
+
+// resolveSIPPassword fills in a missing SIP password from the resolver
+func (ci *CallInitiator) resolveSIPPassword(ctx context.Context, config *CallConfig) error {
+	if config.SIPUsername == "" || config.SIPPassword != "" || ci.sipPasswords == nil {
+		return nil
+	}
+	password, err := ci.sipPasswords(ctx, config.To, config.SIPUsername)
+	if err != nil {
+		return fmt.Errorf("failed to resolve sip password: %w", err)
+	}
+	config.SIPPassword = password
+	return nil
+}
+
+// webhookURL returns the absolute URL of one of our webhook routes
+func (ci *CallInitiator) webhookURL(path string, query url.Values) (string, error) {
+	if ci.publicBaseURL == "" {
+		return "", fmt.Errorf("public base URL is not set (see SetPublicBaseURL)")
 	}
+	u := ci.publicBaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u, nil
+}
+
+// doRequest sends an authenticated LaML API request, falling back across endpoints
+func (ci *CallInitiator) doRequest(ctx context.Context, method, path string, formData url.Values) (*http.Response, error) {
+	return ci.endpoints.Do(ci.httpClient, func(host string) (*http.Request, error) {
+		var body io.Reader
+		if formData != nil {
+			body = strings.NewReader(formData.Encode())
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, signalwire.LaMLBaseURL(host)+path, body)
+		if err != nil {
+			return nil, err
+		}
+
+		if formData != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		req.SetBasicAuth(ci.projectID, ci.authToken)
+		return req, nil
+	})
 }
 
 // ============================================
@@ -54,13 +255,22 @@ func NewCallInitiator(projectID, authToken, space string, db *pgxpool.Pool) *Cal
 type CallConfig struct {
 	// Phone Numbers (E.164 format required)
 	From string `json:"from"` // Your SignalWire number
-	To   string `json:"to"`   // Target number
+	To   string `json:"to"`   // Target number, or a sip: URI for PBXs and SIP trunks
+
+	// Digest credentials for SIP endpoints that challenge the call. The
+	// password is never serialized (see SetSIPPasswordResolver).
+	SIPUsername string `json:"sip_username,omitempty"`
+	SIPPassword string `json:"-"`
 
 	// Campaign Context
 	CampaignID uuid.UUID `json:"campaign_id,omitempty"`
 	TargetID   uuid.UUID `json:"target_id,omitempty"`
 	AgencyID   uuid.UUID `json:"agency_id"`
 
+	// Target locale (BCP 47, e.g. "es-MX"); drives voice and script selection
+	Locale      string `json:"locale,omitempty"`
+	STTLanguage string `json:"stt_language,omitempty"` // Speech recognition language
+
 	// Voice Settings
 	VoiceID           string  `json:"voice_id,omitempty"`
 	VoiceStability    float64 `json:"voice_stability,omitempty"`
@@ -70,21 +280,42 @@ type CallConfig struct {
 
 	// Call Settings
 	RingTimeout      int  `json:"ring_timeout,omitempty"`       // Seconds to ring before timeout
-	MaxDuration      int  `json:"max_duration,omitempty"`       // Max call duration in seconds
+	MaxDuration      int  `json:"max_duration,omitempty"`       // Max call duration in seconds (0 = no limit)
 	RecordCall       bool `json:"record_call,omitempty"`        // Enable recording
 	RecordStereo     bool `json:"record_stereo,omitempty"`      // Stereo recording
 	TranscribeCall   bool `json:"transcribe_call,omitempty"`    // Enable transcription
 	DetectVoicemail  bool `json:"detect_voicemail,omitempty"`   // Enable AMD
 
+	// Message left on voicemail (enables AMD)
+	VoicemailDrop *VoicemailDrop `json:"voicemail_drop,omitempty"`
+
+	// Asynchronous AMD: results are posted to AMDCallbackURL (served at
+	// AMDPath) while the call runs, and the call is redirected to the
+	// human or machine branch
+	AMDCallbackURL string `json:"amd_callback_url,omitempty"`
+	HumanURL       string `json:"human_url,omitempty"`
+	MachineURL     string `json:"machine_url,omitempty"`
+
 	// Callback URLs (webhooks)
 	AnswerURL          string `json:"answer_url"`           // Called when answered
+
+	// Builds this call's instructions when it answers; defaults AnswerURL to
+	// AnswerPath (requires SetPublicBaseURL)
+	AnswerHandler AnswerHandler `json:"-"`
+
 	StatusCallbackURL  string `json:"status_callback_url"`  // Status updates
 	RecordingCallback  string `json:"recording_callback"`   // Recording ready
 
 	// AI Conversation
 	ConversationGoal string `json:"conversation_goal,omitempty"` // quote, claim, appointment
-	SystemPrompt     string `json:"system_prompt,omitempty"`     // AI system prompt
-	GreetingScript   string `json:"greeting_script,omitempty"`   // Initial greeting
+	SystemPrompt     string `json:"system_prompt,omitempty"`     // AI system prompt (template, see PromptData)
+	GreetingScript   string `json:"greeting_script,omitempty"`   // Initial greeting (template)
+
+	// Campaign variables for the prompt templates ({{.Campaign.<key>}})
+	CampaignSettings map[string]interface{} `json:"campaign_settings,omitempty"`
+
+	// Custom X- SIP headers sent with the call
+	SIPHeaders map[string]string `json:"sip_headers,omitempty"`
 
 	// Metadata
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
@@ -106,6 +337,14 @@ const (
 	StateCancelled   CallState = "cancelled"
 )
 
+// CallDirection distinguishes calls we placed from calls we received
+type CallDirection string
+
+const (
+	DirectionOutbound CallDirection = "outbound"
+	DirectionInbound  CallDirection = "inbound"
+)
+
 // CallStatus represents the overall outcome
 type CallStatus string
 
@@ -146,8 +385,13 @@ type CallSession struct {
 	AgencyID        uuid.UUID              `json:"agency_id"`
 
 	// Call Details
+	Direction       CallDirection          `json:"direction"`
 	FromNumber      string                 `json:"from_number"`
 	ToNumber        string                 `json:"to_number"`
+	CallerName      string                 `json:"caller_name,omitempty"`
+
+	// Audio bridge session carrying this call's media
+	BridgeSessionID string                 `json:"bridge_session_id,omitempty"`
 
 	// State Machine
 	Status          CallStatus             `json:"status"`
@@ -166,10 +410,19 @@ type CallSession struct {
 	// Outcome
 	Outcome         CallOutcome            `json:"outcome,omitempty"`
 	OutcomeReason   string                 `json:"outcome_reason,omitempty"`
+	HangupCause     HangupCause            `json:"hangup_cause,omitempty"`
+	SIPResponseCode int                    `json:"sip_response_code,omitempty"`
+
+	// Caller ID attestation (STIR/SHAKEN)
+	Attestation     Attestation            `json:"attestation,omitempty"`
+	Verstat         string                 `json:"verstat,omitempty"` // Raw StirVerstat/StirStatus value
 
 	// Recording
 	RecordingURL    string                 `json:"recording_url,omitempty"`
 	RecordingDuration int                  `json:"recording_duration,omitempty"`
+	RecordingSID    string                 `json:"recording_sid,omitempty"`
+	RecordingChannels int                  `json:"recording_channels,omitempty"`
+	RecordingStoredAt string               `json:"recording_stored_at,omitempty"` // Copy in RecordingStorage
 
 	// Transcription
 	TranscriptURL   string                 `json:"transcript_url,omitempty"`
@@ -178,6 +431,18 @@ type CallSession struct {
 	// Voicemail Detection
 	VoicemailDetected bool                 `json:"voicemail_detected"`
 	VoicemailMessageLeft bool              `json:"voicemail_message_left"`
+	AnsweredBy      AnsweredBy             `json:"answered_by,omitempty"`
+
+	// Disposition (wrap-up code, see SetDisposition)
+	Disposition      string                `json:"disposition,omitempty"`
+	DispositionNotes string                `json:"disposition_notes,omitempty"`
+	DispositionAt    *time.Time            `json:"disposition_at,omitempty"`
+
+	// Conversation goal result (see SetGoalOutcome)
+	GoalOutcome     *GoalOutcome           `json:"goal_outcome,omitempty"`
+
+	// Caller sentiment (see RecordSentiment)
+	Sentiment       *SentimentSummary      `json:"sentiment,omitempty"`
 
 	// Quality Metrics
 	AudioQuality    float64                `json:"audio_quality,omitempty"`
@@ -190,13 +455,17 @@ type CallSession struct {
 	ErrorCode       string                 `json:"error_code,omitempty"`
 	ErrorMessage    string                 `json:"error_message,omitempty"`
 
-	// Metadata
-	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	// Metadata (see Metadata for typed access)
+	Metadata        Metadata               `json:"metadata,omitempty"`
 
 	// Internal
 	Config          *CallConfig            `json:"-"`
+	amd             *amdPolicy             // AMD branches, saved with the session
+	lastSequence    int
+	lastEventAt     time.Time
 	CreatedAt       time.Time              `json:"created_at"`
 	UpdatedAt       time.Time              `json:"updated_at"`
+	Version         int                    `json:"version"` // Bumped by each store update (optimistic locking)
 
 	mu sync.RWMutex
 }
@@ -207,18 +476,47 @@ type CallSession struct {
 
 // InitiateCall starts an outbound call
 func (ci *CallInitiator) InitiateCall(ctx context.Context, config CallConfig) (*CallSession, error) {
+	if ci.draining.Load() {
+		return nil, ErrDraining
+	}
+
+	// Select voice, language and scripts for the target's locale
+	if ci.localeProfiles != nil {
+		ci.localeProfiles.Apply(&config)
+	}
+	if err := RenderPrompts(&config); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	// Calls with an answer handler are answered by our own route
+	sessionID := uuid.New()
+	if config.AnswerHandler != nil && config.AnswerURL == "" {
+		answerURL, err := ci.webhookURL(AnswerPath, url.Values{"session_id": {sessionID.String()}})
+		if err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+		config.AnswerURL = answerURL
+	}
+	amd, err := ci.amdPolicy(&config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	// Validate configuration
+	if err := ci.resolveSIPPassword(ctx, &config); err != nil {
+		return nil, err
+	}
 	if err := ci.validateConfig(&config); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
 	// Create call session in database
-	sessionID := uuid.New()
 	session := &CallSession{
 		ID:          sessionID,
 		AgencyID:    config.AgencyID,
 		CampaignID:  nilUUIDToPtr(config.CampaignID),
 		TargetID:    nilUUIDToPtr(config.TargetID),
+		Direction:   DirectionOutbound,
 		FromNumber:  config.From,
 		ToNumber:    config.To,
 		Status:      StatusInitiated,
@@ -227,40 +525,66 @@ func (ci *CallInitiator) InitiateCall(ctx context.Context, config CallConfig) (*
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 		Config:      &config,
-		Metadata:    config.Metadata,
+		Metadata:    NewMetadata(config.Metadata),
+		amd:         amd,
 	}
 
-	// Insert into database
-	if err := ci.insertCallSession(ctx, session); err != nil {
+	// Hold an agency concurrency slot until the call ends
+	if err := ci.acquireAgencySlot(ctx, config.AgencyID, sessionID); err != nil {
+		return nil, err
+	}
+
+	// Persist the session
+	if err := ci.store.Insert(ctx, session); err != nil {
+		ci.releaseAgencySlot(sessionID)
+		ci.errorLog.Record("initiator", "", err)
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
+	if config.AnswerHandler != nil {
+		ci.SetAnswerHandler(sessionID, config.AnswerHandler)
+	}
+
 	// Make SignalWire API call
 	swCall, err := ci.makeSignalWireCall(ctx, config, sessionID)
 	if err != nil {
+		ci.answerHandlers.Delete(sessionID)
 		// Update session with error
 		session.Status = StatusFailed
 		session.State = StateFailed
 		session.Outcome = OutcomeError
 		session.ErrorMessage = err.Error()
-		ci.updateCallSession(ctx, session)
+		if updateErr := ci.store.Update(ctx, session); updateErr != nil {
+			log.Printf("[CallInitiator] Failed to save failed session %s: %v", sessionID, updateErr)
+		}
+		ci.releaseAgencySlot(sessionID)
+		ci.errorLog.Record("initiator", "", err)
 		return nil, fmt.Errorf("SignalWire API error: %w", err)
 	}
 
 	// Update session with SignalWire SID
 	session.SignalWireCallSID = swCall.SID
 	session.State = StateInitiated
-	ci.updateCallSession(ctx, session)
+	if err := ci.store.Update(ctx, session); err != nil {
+		// The call is already placed, so keep tracking it; status callbacks
+		// find it by SID in memory until the row catches up
+		log.Printf("[CallInitiator] Failed to save call SID %s for session %s: %v", swCall.SID, sessionID, err)
+		ci.errorLog.Record("initiator", swCall.SID, err)
+	}
 
 	// Track active call
 	ci.activeCalls.Store(swCall.SID, session)
+	ci.claimCall(ctx, swCall.SID)
+	ci.startPolling(swCall.SID, &config)
+
+	publishEvent(ci.events, callEvent(EventCallInitiated, session.Summary()))
 
 	return session, nil
 }
 
 // makeSignalWireCall makes the actual API call to SignalWire
 func (ci *CallInitiator) makeSignalWireCall(ctx context.Context, config CallConfig, sessionID uuid.UUID) (*SignalWireCallResponse, error) {
-	reqURL := fmt.Sprintf("%s/Accounts/%s/Calls.json", ci.baseURL, ci.projectID)
+	path := fmt.Sprintf("/Accounts/%s/Calls.json", ci.projectID)
 
 	// Build form data
 	formData := url.Values{}
@@ -283,40 +607,44 @@ func (ci *CallInitiator) makeSignalWireCall(ctx context.Context, config CallConf
 		}
 		if config.RecordingCallback != "" {
 			formData.Set("RecordingStatusCallback", config.RecordingCallback)
+		} else if callback, err := ci.webhookURL(RecordingPath, nil); err == nil {
+			formData.Set("RecordingStatusCallback", callback)
 		}
 	}
 
+	if config.MaxDuration > 0 {
+		formData.Set("TimeLimit", fmt.Sprintf("%d", config.MaxDuration))
+	}
+
 	if config.RingTimeout > 0 {
 		formData.Set("Timeout", fmt.Sprintf("%d", config.RingTimeout))
 	} else {
 		formData.Set("Timeout", "30") // Default 30 seconds
 	}
 
-	if config.DetectVoicemail {
+	if config.usesAMD() {
 		formData.Set("MachineDetection", "DetectMessageEnd")
 		formData.Set("MachineDetectionTimeout", "5000")  // 5 seconds
 		formData.Set("MachineDetectionSpeechThreshold", "2500")
 		formData.Set("MachineDetectionSpeechEndThreshold", "1200")
 		formData.Set("MachineDetectionSilenceTimeout", "2000")
 	}
-
-	// Add custom parameters
-	formData.Set("SipHeaders", fmt.Sprintf("X-Session-ID=%s", sessionID.String()))
-	if config.CampaignID != uuid.Nil {
-		formData.Set("SipHeaders", fmt.Sprintf("X-Campaign-ID=%s", config.CampaignID.String()))
+	if config.AMDCallbackURL != "" {
+		formData.Set("AsyncAmd", "true")
+		formData.Set("AsyncAmdStatusCallback", config.AMDCallbackURL)
+		formData.Set("AsyncAmdStatusCallbackMethod", "POST")
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, strings.NewReader(formData.Encode()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if config.SIPUsername != "" {
+		formData.Set("SipAuthUsername", config.SIPUsername)
+		formData.Set("SipAuthPassword", config.SIPPassword)
 	}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.SetBasicAuth(ci.projectID, ci.authToken)
+	// Custom SIP headers, including our session and campaign IDs
+	formData.Set("SipHeaders", EncodeSIPHeaders(outboundSIPHeaders(config, sessionID)))
 
 	// Execute request
-	resp, err := ci.httpClient.Do(req)
+	resp, err := ci.doRequest(ctx, "POST", path, formData)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -360,24 +688,99 @@ type SignalWireCallResponse struct {
 
 // UpdateCallState updates the state of an active call
 func (ci *CallInitiator) UpdateCallState(ctx context.Context, callSID string, newState CallState, metadata map[string]interface{}) error {
+	return ci.ApplyCallEvent(ctx, CallEvent{
+		CallSID:  callSID,
+		State:    newState,
+		Metadata: metadata,
+	})
+}
+
+// maxEventAttempts bounds how often an event or session change is
+// re-applied after losing an update race to another process
+const maxEventAttempts = 3
+
+// ApplyCallEvent applies a state change to a call. Transitions are checked
+// against the call state machine; events older than the last one applied
+// (by sequence number or timestamp) are ignored. If another process updated
+// the call first, the event is re-applied to the stored copy.
+func (ci *CallInitiator) ApplyCallEvent(ctx context.Context, event CallEvent) error {
 	// Get session
-	sessionRaw, ok := ci.activeCalls.Load(callSID)
-	if !ok {
-		// Try to load from database
-		session, err := ci.getCallSessionBySID(ctx, callSID)
-		if err != nil {
-			return fmt.Errorf("call not found: %s", callSID)
+	session, err := ci.lookupSession(ctx, event.CallSID)
+	if err != nil {
+		return err
+	}
+
+	session.mu.RLock()
+	verstat := session.Verstat
+	session.mu.RUnlock()
+
+	for attempt := 1; ; attempt++ {
+		changed, err := ci.applyCallEvent(ctx, session, event)
+		if errors.Is(err, ErrSessionConflict) && attempt < maxEventAttempts {
+			log.Printf("[CallInitiator] Call %s was updated concurrently, retrying %s event",
+				event.CallSID, event.State)
+			if session, err = ci.refreshSession(ctx, event.CallSID); err != nil {
+				return err
+			}
+			continue
 		}
-		sessionRaw = session
+
+		// Listeners run once the session is unlocked
+		if changed && !errors.Is(err, ErrSessionConflict) {
+			summary := session.Summary()
+			ci.hooks.fireTransition(summary)
+			ci.publishTransition(summary)
+			ci.trackMaxDuration(session)
+			if summary.State.IsTerminal() {
+				ci.answerHandlers.Delete(summary.ID)
+			}
+			if event.Verstat != "" && event.Verstat != verstat {
+				ci.checkAttestation(session)
+			}
+		}
+		return err
 	}
+}
 
-	session := sessionRaw.(*CallSession)
+// applyCallEvent applies an event to one copy of a session, reporting
+// whether the state changed
+func (ci *CallInitiator) applyCallEvent(ctx context.Context, session *CallSession, event CallEvent) (changed bool, err error) {
 	session.mu.Lock()
 	defer session.mu.Unlock()
 
+	newState := event.State
+	metadata := event.Metadata
+
+	// Drop out-of-order webhooks
+	if session.isStaleEvent(event) {
+		log.Printf("[CallInitiator] Ignoring stale %s event for call %s (sequence: %d)",
+			newState, event.CallSID, event.SequenceNumber)
+		return false, nil
+	}
+
+	// Retries of the current state are no-ops
+	if newState == session.State {
+		session.recordEvent(event)
+		return false, nil
+	}
+
+	if !CanTransition(session.State, newState) {
+		log.Printf("[CallInitiator] Rejected transition %s → %s for call %s",
+			session.State, newState, event.CallSID)
+		return false, &TransitionError{CallSID: event.CallSID, From: session.State, To: newState}
+	}
+
 	now := time.Now()
+	if !event.Timestamp.IsZero() {
+		now = event.Timestamp
+	}
+	session.recordEvent(event)
 	session.State = newState
-	session.UpdatedAt = now
+	session.UpdatedAt = time.Now()
+	changed = true
+
+	// Terminal states have no way out, so entering one ends the call
+	completed := newState.IsTerminal()
 
 	// Update timing based on state
 	switch newState {
@@ -388,7 +791,7 @@ func (ci *CallInitiator) UpdateCallState(ctx context.Context, callSID string, ne
 			// Calculate time to ring
 			ringDelay := now.Sub(session.InitiatedAt).Seconds()
 			if ringDelay > 0 {
-				session.Metadata["ring_delay_seconds"] = ringDelay
+				session.Metadata.Set("ring_delay_seconds", ringDelay)
 			}
 		}
 
@@ -402,13 +805,20 @@ func (ci *CallInitiator) UpdateCallState(ctx context.Context, callSID string, ne
 	case StateCompleted:
 		session.Status = StatusCompleted
 		session.CompletedAt = &now
-		session.Outcome = OutcomeCompleted
 		if session.AnsweredAt != nil {
 			session.TalkTimeSeconds = int(now.Sub(*session.AnsweredAt).Seconds())
 			session.DurationSeconds = session.RingTimeSeconds + session.TalkTimeSeconds
 		} else {
 			session.DurationSeconds = int(now.Sub(session.InitiatedAt).Seconds())
 		}
+		// Calls SignalWire ended at their TimeLimit also count as timeouts
+		if session.Outcome != OutcomeTimeout {
+			session.Outcome = OutcomeCompleted
+			if session.Config != nil && session.Config.MaxDuration > 0 && session.TalkTimeSeconds >= session.Config.MaxDuration {
+				session.Outcome = OutcomeTimeout
+				session.OutcomeReason = "reached time limit"
+			}
+		}
 
 	case StateFailed:
 		session.Status = StatusFailed
@@ -427,76 +837,199 @@ func (ci *CallInitiator) UpdateCallState(ctx context.Context, callSID string, ne
 		session.CompletedAt = &now
 		session.Outcome = OutcomeBusy
 		session.DurationSeconds = int(now.Sub(session.InitiatedAt).Seconds())
+
+	case StateCancelled:
+		session.Status = StatusCancelled
+		session.CompletedAt = &now
+		if session.AnsweredAt != nil {
+			session.TalkTimeSeconds = int(now.Sub(*session.AnsweredAt).Seconds())
+			session.DurationSeconds = session.RingTimeSeconds + session.TalkTimeSeconds
+		} else {
+			session.DurationSeconds = int(now.Sub(session.InitiatedAt).Seconds())
+		}
 	}
 
-	// Merge metadata
-	if metadata != nil {
-		for k, v := range metadata {
-			session.Metadata[k] = v
+	if completed {
+		if event.HangupCause != "" {
+			session.HangupCause = event.HangupCause
+		}
+		if event.SIPResponseCode != 0 {
+			session.SIPResponseCode = event.SIPResponseCode
 		}
+		ci.fillTranscript(ctx, session)
 	}
 
-	// Update in database
-	if err := ci.updateCallSession(ctx, session); err != nil {
-		return fmt.Errorf("failed to update session: %w", err)
+	session.setAttestation(event.Attestation, event.Verstat)
+
+	// Merge metadata
+	for k, v := range metadata {
+		session.Metadata.Set(k, v)
 	}
 
-	return nil
+	// Update in database; on a conflict the caller retries on a fresh copy
+	err = ci.store.Update(ctx, session)
+	if errors.Is(err, ErrSessionConflict) {
+		return false, err
+	}
+
+	// Record per-locale performance once the call has ended
+	if ci.localeProfiles != nil && completed {
+		ci.localeProfiles.RecordOutcome(session)
+	}
+
+	if err != nil {
+		return true, fmt.Errorf("failed to update session: %w", err)
+	}
+	return true, nil
 }
 
-// MarkVoicemailDetected marks a call as having detected voicemail
-func (ci *CallInitiator) MarkVoicemailDetected(ctx context.Context, callSID string, messageLeft bool) error {
-	sessionRaw, ok := ci.activeCalls.Load(callSID)
-	if !ok {
-		return fmt.Errorf("call not found: %s", callSID)
+// publishTransition publishes answered and completed transitions
+func (ci *CallInitiator) publishTransition(summary CallSummary) {
+	switch {
+	case summary.State == StateAnswered:
+		publishEvent(ci.events, callEvent(EventCallAnswered, summary))
+	case summary.State.IsTerminal():
+		publishEvent(ci.events, callEvent(EventCallCompleted, summary))
 	}
+}
 
-	session := sessionRaw.(*CallSession)
-	session.mu.Lock()
-	defer session.mu.Unlock()
+// lookupSession finds a session in active tracking, falling back to the
+// store. Shared stores are always read, since another instance may have
+// updated the call.
+func (ci *CallInitiator) lookupSession(ctx context.Context, callSID string) (*CallSession, error) {
+	if shared, ok := ci.store.(SharedSessionStore); ok && shared.Shared() {
+		return ci.refreshSession(ctx, callSID)
+	}
 
-	session.VoicemailDetected = true
-	session.VoicemailMessageLeft = messageLeft
-	session.Outcome = OutcomeVoicemailDetected
-	session.UpdatedAt = time.Now()
+	if sessionRaw, ok := ci.activeCalls.Load(callSID); ok {
+		return sessionRaw.(*CallSession), nil
+	}
 
-	return ci.updateCallSession(ctx, session)
+	session, err := ci.store.GetBySID(ctx, callSID)
+	if err != nil {
+		return nil, fmt.Errorf("call not found: %s", callSID)
+	}
+	return session, nil
 }
 
-// SetCallRecording updates recording information
-func (ci *CallInitiator) SetCallRecording(ctx context.Context, callSID, recordingURL string, duration int) error {
-	sessionRaw, ok := ci.activeCalls.Load(callSID)
-	if !ok {
-		return fmt.Errorf("call not found: %s", callSID)
+// GetCallConfig returns the config a call was placed with, or nil if this
+// instance didn't place it
+func (ci *CallInitiator) GetCallConfig(ctx context.Context, callSID string) *CallConfig {
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return nil
+	}
+
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	return session.Config
+}
+
+// refreshSession loads the stored copy of a session, keeping the local call
+// config, and makes it the tracked active session
+func (ci *CallInitiator) refreshSession(ctx context.Context, callSID string) (*CallSession, error) {
+	session, err := ci.store.GetBySID(ctx, callSID)
+	if err != nil {
+		if sessionRaw, ok := ci.activeCalls.Load(callSID); ok {
+			return sessionRaw.(*CallSession), nil
+		}
+		return nil, fmt.Errorf("call not found: %s", callSID)
+	}
+
+	if sessionRaw, ok := ci.activeCalls.Load(callSID); ok {
+		local := sessionRaw.(*CallSession)
+		local.mu.RLock()
+		session.Config = local.Config
+		local.mu.RUnlock()
+		ci.activeCalls.Store(callSID, session)
+	}
+	return session, nil
+}
+
+// errNoChange is returned by an updateSession change to skip saving
+var errNoChange = errors.New("session unchanged")
+
+// updateCall applies change to a call's session and saves it (see
+// updateSession)
+func (ci *CallInitiator) updateCall(ctx context.Context, callSID string, change func(*CallSession) error) (*CallSession, error) {
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return nil, err
+	}
+	return ci.updateSession(ctx, session, change)
+}
+
+// updateSession applies change to a session under its lock and saves it.
+// If another process saved the call first, change is re-applied to a fresh
+// copy rather than leaving the write on a stale version. Returns the copy
+// saved, which listeners should read.
+func (ci *CallInitiator) updateSession(ctx context.Context, session *CallSession, change func(*CallSession) error) (*CallSession, error) {
+	for attempt := 1; ; attempt++ {
+		err := ci.saveSession(ctx, session, change)
+		if !errors.Is(err, ErrSessionConflict) || attempt == maxEventAttempts {
+			return session, err
+		}
+
+		session.mu.RLock()
+		callSID := session.SignalWireCallSID
+		session.mu.RUnlock()
+		if callSID == "" {
+			return session, err
+		}
+		log.Printf("[CallInitiator] Call %s was updated concurrently, retrying update", callSID)
+		if session, err = ci.refreshSession(ctx, callSID); err != nil {
+			return nil, err
+		}
 	}
+}
 
-	session := sessionRaw.(*CallSession)
+// saveSession applies change to one copy of a session and saves it
+func (ci *CallInitiator) saveSession(ctx context.Context, session *CallSession, change func(*CallSession) error) error {
 	session.mu.Lock()
 	defer session.mu.Unlock()
 
-	session.RecordingURL = recordingURL
-	session.RecordingDuration = duration
+	if err := change(session); err != nil {
+		return err
+	}
 	session.UpdatedAt = time.Now()
+	return ci.store.Update(ctx, session)
+}
 
-	return ci.updateCallSession(ctx, session)
+// MarkVoicemailDetected marks a call as having detected voicemail
+func (ci *CallInitiator) MarkVoicemailDetected(ctx context.Context, callSID string, messageLeft bool) error {
+	session, err := ci.updateCall(ctx, callSID, func(session *CallSession) error {
+		session.VoicemailDetected = true
+		session.VoicemailMessageLeft = messageLeft
+		session.Outcome = OutcomeVoicemailDetected
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ci.hooks.fireVoicemail(VoicemailEvent{Call: session.Summary(), MessageLeft: messageLeft})
+	return nil
 }
 
-// SetCallTranscript updates transcript information
+// SetCallRecording updates recording information (see AttachRecording)
+func (ci *CallInitiator) SetCallRecording(ctx context.Context, callSID, recordingURL string, duration int) error {
+	return ci.AttachRecording(ctx, Recording{CallSID: callSID, URL: recordingURL, DurationSeconds: duration})
+}
+
+// SetCallTranscript updates transcript information. TranscriptText is
+// also written at call end from segments stored with RecordTranscript.
 func (ci *CallInitiator) SetCallTranscript(ctx context.Context, callSID, transcriptURL, transcriptText string) error {
 	sessionRaw, ok := ci.activeCalls.Load(callSID)
 	if !ok {
 		return fmt.Errorf("call not found: %s", callSID)
 	}
 
-	session := sessionRaw.(*CallSession)
-	session.mu.Lock()
-	defer session.mu.Unlock()
-
-	session.TranscriptURL = transcriptURL
-	session.TranscriptText = transcriptText
-	session.UpdatedAt = time.Now()
-
-	return ci.updateCallSession(ctx, session)
+	_, err := ci.updateSession(ctx, sessionRaw.(*CallSession), func(session *CallSession) error {
+		session.TranscriptURL = transcriptURL
+		session.TranscriptText = transcriptText
+		return nil
+	})
+	return err
 }
 
 // ============================================
@@ -505,20 +1038,24 @@ func (ci *CallInitiator) SetCallTranscript(ctx context.Context, callSID, transcr
 
 // HangupCall terminates an active call
 func (ci *CallInitiator) HangupCall(ctx context.Context, callSID string) error {
-	reqURL := fmt.Sprintf("%s/Accounts/%s/Calls/%s.json", ci.baseURL, ci.projectID, callSID)
+	if err := ci.endCall(ctx, callSID); err != nil {
+		return err
+	}
 
-	formData := url.Values{}
-	formData.Set("Status", "completed")
+	// Update local state
+	return ci.UpdateCallState(ctx, callSID, StateCancelled, map[string]interface{}{
+		"hung_up_by": "system",
+	})
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, strings.NewReader(formData.Encode()))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+// endCall asks SignalWire to end a call, leaving its session as it is
+func (ci *CallInitiator) endCall(ctx context.Context, callSID string) error {
+	path := fmt.Sprintf("/Accounts/%s/Calls/%s.json", ci.projectID, callSID)
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.SetBasicAuth(ci.projectID, ci.authToken)
+	formData := url.Values{}
+	formData.Set("Status", "completed")
 
-	resp, err := ci.httpClient.Do(req)
+	resp, err := ci.doRequest(ctx, "POST", path, formData)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -528,30 +1065,22 @@ func (ci *CallInitiator) HangupCall(ctx context.Context, callSID string) error {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
 	}
-
-	// Update local state
-	return ci.UpdateCallState(ctx, callSID, StateCancelled, map[string]interface{}{
-		"hung_up_by": "system",
-	})
+	return nil
 }
 
 // GetCallStatus retrieves current call status from SignalWire
 func (ci *CallInitiator) GetCallStatus(ctx context.Context, callSID string) (*SignalWireCallResponse, error) {
-	reqURL := fmt.Sprintf("%s/Accounts/%s/Calls/%s.json", ci.baseURL, ci.projectID, callSID)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(ci.projectID, ci.authToken)
+	path := fmt.Sprintf("/Accounts/%s/Calls/%s.json", ci.projectID, callSID)
 
-	resp, err := ci.httpClient.Do(req)
+	resp, err := ci.doRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCall, callSID)
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
@@ -565,141 +1094,6 @@ func (ci *CallInitiator) GetCallStatus(ctx context.Context, callSID string) (*Si
 	return &swCall, nil
 }
 
-// ============================================
-// DATABASE OPERATIONS
-// ============================================
-
-// insertCallSession inserts a new call session
-func (ci *CallInitiator) insertCallSession(ctx context.Context, session *CallSession) error {
-	query := `
-		INSERT INTO call_sessions (
-			id, campaign_id, target_id, agency_id,
-			from_number, to_number, status, call_state,
-			initiated_at, metadata, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-	`
-
-	metadataJSON, _ := json.Marshal(session.Metadata)
-
-	_, err := ci.db.Exec(ctx, query,
-		session.ID, session.CampaignID, session.TargetID, session.AgencyID,
-		session.FromNumber, session.ToNumber, session.Status, session.State,
-		session.InitiatedAt, metadataJSON, session.CreatedAt, session.UpdatedAt,
-	)
-
-	return err
-}
-
-// updateCallSession updates an existing call session
-func (ci *CallInitiator) updateCallSession(ctx context.Context, session *CallSession) error {
-	query := `
-		UPDATE call_sessions SET
-			signalwire_call_sid = $1,
-			status = $2,
-			call_state = $3,
-			ringing_at = $4,
-			answered_at = $5,
-			completed_at = $6,
-			duration_seconds = $7,
-			talk_time_seconds = $8,
-			ring_time_seconds = $9,
-			outcome = $10,
-			outcome_reason = $11,
-			recording_url = $12,
-			recording_duration_seconds = $13,
-			transcript_url = $14,
-			transcript_text = $15,
-			voicemail_detected = $16,
-			voicemail_message_left = $17,
-			audio_quality_score = $18,
-			transcription_confidence = $19,
-			cost_usd = $20,
-			error_code = $21,
-			error_message = $22,
-			metadata = $23,
-			updated_at = $24
-		WHERE id = $25
-	`
-
-	metadataJSON, _ := json.Marshal(session.Metadata)
-
-	_, err := ci.db.Exec(ctx, query,
-		session.SignalWireCallSID,
-		session.Status,
-		session.State,
-		session.RingingAt,
-		session.AnsweredAt,
-		session.CompletedAt,
-		session.DurationSeconds,
-		session.TalkTimeSeconds,
-		session.RingTimeSeconds,
-		session.Outcome,
-		session.OutcomeReason,
-		session.RecordingURL,
-		session.RecordingDuration,
-		session.TranscriptURL,
-		session.TranscriptText,
-		session.VoicemailDetected,
-		session.VoicemailMessageLeft,
-		session.AudioQuality,
-		session.Confidence,
-		session.CostUSD,
-		session.ErrorCode,
-		session.ErrorMessage,
-		metadataJSON,
-		session.UpdatedAt,
-		session.ID,
-	)
-
-	return err
-}
-
-// getCallSessionBySID retrieves a call session by SignalWire SID
-func (ci *CallInitiator) getCallSessionBySID(ctx context.Context, callSID string) (*CallSession, error) {
-	query := `
-		SELECT id, campaign_id, target_id, agency_id,
-		       signalwire_call_sid, from_number, to_number,
-		       status, call_state,
-		       initiated_at, ringing_at, answered_at, completed_at,
-		       duration_seconds, talk_time_seconds, ring_time_seconds,
-		       outcome, outcome_reason,
-		       recording_url, recording_duration_seconds,
-		       transcript_url, transcript_text,
-		       voicemail_detected, voicemail_message_left,
-		       audio_quality_score, transcription_confidence,
-		       cost_usd, error_code, error_message,
-		       metadata, created_at, updated_at
-		FROM call_sessions
-		WHERE signalwire_call_sid = $1
-	`
-
-	var session CallSession
-	var metadataJSON []byte
-
-	err := ci.db.QueryRow(ctx, query, callSID).Scan(
-		&session.ID, &session.CampaignID, &session.TargetID, &session.AgencyID,
-		&session.SignalWireCallSID, &session.FromNumber, &session.ToNumber,
-		&session.Status, &session.State,
-		&session.InitiatedAt, &session.RingingAt, &session.AnsweredAt, &session.CompletedAt,
-		&session.DurationSeconds, &session.TalkTimeSeconds, &session.RingTimeSeconds,
-		&session.Outcome, &session.OutcomeReason,
-		&session.RecordingURL, &session.RecordingDuration,
-		&session.TranscriptURL, &session.TranscriptText,
-		&session.VoicemailDetected, &session.VoicemailMessageLeft,
-		&session.AudioQuality, &session.Confidence,
-		&session.CostUSD, &session.ErrorCode, &session.ErrorMessage,
-		&metadataJSON, &session.CreatedAt, &session.UpdatedAt,
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	json.Unmarshal(metadataJSON, &session.Metadata)
-
-	return &session, nil
-}
-
 // ============================================
 // VALIDATION & HELPERS
 // ============================================
@@ -723,17 +1117,30 @@ func (ci *CallInitiator) validateConfig(config *CallConfig) error {
 	if !isValidE164(config.From) {
 		return fmt.Errorf("from number must be in E.164 format (+1234567890)")
 	}
-	if !isValidE164(config.To) {
-		return fmt.Errorf("to number must be in E.164 format (+1234567890)")
+	if !isSIPURI(config.To) && !isValidE164(config.To) {
+		return fmt.Errorf("to number must be in E.164 format (+1234567890) or a sip: URI")
+	}
+	if config.SIPUsername != "" || config.SIPPassword != "" {
+		if !isSIPURI(config.To) {
+			return fmt.Errorf("sip credentials require a sip: URI")
+		}
+		if config.SIPUsername == "" || config.SIPPassword == "" {
+			return fmt.Errorf("sip username and password must be set together")
+		}
+	}
+	if config.VoicemailDrop != nil {
+		if err := config.VoicemailDrop.validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateSIPHeaders(config.SIPHeaders); err != nil {
+		return err
 	}
 
 	// Set defaults
 	if config.RingTimeout == 0 {
 		config.RingTimeout = 30
 	}
-	if config.MaxDuration == 0 {
-		config.MaxDuration = 900 // 15 minutes
-	}
 
 	return nil
 }
@@ -762,6 +1169,59 @@ func nilUUIDToPtr(id uuid.UUID) *uuid.UUID {
 	return &id
 }
 
+// CallSummary is a point-in-time view of a call session for monitoring
+type CallSummary struct {
+	ID                uuid.UUID     `json:"id"`
+	SignalWireCallSID string        `json:"signalwire_call_sid"`
+	CampaignID        *uuid.UUID    `json:"campaign_id,omitempty"`
+	AgencyID          uuid.UUID     `json:"agency_id"`
+	Direction         CallDirection `json:"direction"`
+	FromNumber        string        `json:"from_number"`
+	ToNumber          string        `json:"to_number"`
+	Status            CallStatus    `json:"status"`
+	State             CallState     `json:"state"`
+	InitiatedAt       time.Time     `json:"initiated_at"`
+	AnsweredAt        *time.Time    `json:"answered_at,omitempty"`
+	DurationSeconds   int           `json:"duration_seconds,omitempty"`
+	Outcome           CallOutcome   `json:"outcome,omitempty"`
+	HangupCause       HangupCause   `json:"hangup_cause,omitempty"`
+	Attestation       Attestation   `json:"attestation,omitempty"`
+}
+
+// Summary returns a consistent snapshot of the session's key fields
+func (s *CallSession) Summary() CallSummary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return CallSummary{
+		ID:                s.ID,
+		SignalWireCallSID: s.SignalWireCallSID,
+		CampaignID:        s.CampaignID,
+		AgencyID:          s.AgencyID,
+		Direction:         s.Direction,
+		FromNumber:        s.FromNumber,
+		ToNumber:          s.ToNumber,
+		Status:            s.Status,
+		State:             s.State,
+		InitiatedAt:       s.InitiatedAt,
+		AnsweredAt:        s.AnsweredAt,
+		DurationSeconds:   s.DurationSeconds,
+		Outcome:           s.Outcome,
+		HangupCause:       s.HangupCause,
+		Attestation:       s.Attestation,
+	}
+}
+
+// ActiveCalls returns the sessions currently tracked in memory
+func (ci *CallInitiator) ActiveCalls() []*CallSession {
+	var sessions []*CallSession
+	ci.activeCalls.Range(func(key, value interface{}) bool {
+		sessions = append(sessions, value.(*CallSession))
+		return true
+	})
+	return sessions
+}
+
 // GetActiveCallsCount returns the number of active calls
 func (ci *CallInitiator) GetActiveCallsCount() int {
 	count := 0
@@ -0,0 +1,256 @@
+package telephony
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ============================================
+// CALL OWNERSHIP
+// Sticky webhook routing between server instances
+// ============================================
+
+// ForwardedHeader marks webhooks forwarded from another instance, which are
+// always handled locally to avoid forwarding loops
+const ForwardedHeader = "X-Telephony-Forwarded"
+
+// releaseScript deletes an ownership record only if we still hold it
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// CallOwnership records which server instance owns each live call. Bridge
+// sessions, media streams and digit handlers only exist on the instance
+// that set the call up, so webhooks for the call landing on another
+// instance are forwarded to the owner:
+//
+//	<prefix>owner:<sid>  internal base URL of the owning instance
+type CallOwnership struct {
+	client      *redis.Client
+	instanceURL string
+	prefix      string
+	ttl         time.Duration
+	transport   http.RoundTripper
+}
+
+// NewCallOwnership creates ownership records in Redis. instanceURL is the
+// internal base URL other instances reach this one at (e.g.
+// http://10.0.3.7:8080). Keys are namespaced by prefix (default
+// "telephony:") and expire after ttl (default 4 hours) in case a call's
+// completion is never seen.
+func NewCallOwnership(client *redis.Client, instanceURL, prefix string, ttl time.Duration) *CallOwnership {
+	if prefix == "" {
+		prefix = "telephony:"
+	}
+	if ttl <= 0 {
+		ttl = 4 * time.Hour
+	}
+	return &CallOwnership{
+		client:      client,
+		instanceURL: strings.TrimRight(instanceURL, "/"),
+		prefix:      prefix,
+		ttl:         ttl,
+		transport:   http.DefaultTransport,
+	}
+}
+
+// InstanceURL returns this instance's internal base URL
+func (o *CallOwnership) InstanceURL() string {
+	return o.instanceURL
+}
+
+// Claim takes ownership of a call unless another instance already holds
+// it, returning the owner's URL
+func (o *CallOwnership) Claim(ctx context.Context, callSID string) (string, error) {
+	claimed, err := o.client.SetNX(ctx, o.ownerKey(callSID), o.instanceURL, o.ttl).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to claim call: %w", err)
+	}
+	if claimed {
+		return o.instanceURL, nil
+	}
+	return o.Owner(ctx, callSID)
+}
+
+// Takeover makes this instance the owner of a call, e.g. when the previous
+// owner stopped answering
+func (o *CallOwnership) Takeover(ctx context.Context, callSID string) error {
+	if err := o.client.Set(ctx, o.ownerKey(callSID), o.instanceURL, o.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to take over call: %w", err)
+	}
+	return nil
+}
+
+// Owner returns the URL of the instance owning a call, or "" if no
+// instance does
+func (o *CallOwnership) Owner(ctx context.Context, callSID string) (string, error) {
+	owner, err := o.client.Get(ctx, o.ownerKey(callSID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up call owner: %w", err)
+	}
+	return owner, nil
+}
+
+// Release gives up ownership of a call, if this instance holds it
+func (o *CallOwnership) Release(ctx context.Context, callSID string) error {
+	if err := releaseScript.Run(ctx, o.client, []string{o.ownerKey(callSID)}, o.instanceURL).Err(); err != nil {
+		return fmt.Errorf("failed to release call: %w", err)
+	}
+	return nil
+}
+
+func (o *CallOwnership) ownerKey(callSID string) string {
+	return o.prefix + "owner:" + callSID
+}
+
+// ============================================
+// INITIATOR INTEGRATION
+// ============================================
+
+// SetCallOwnership enables sticky routing between instances. Calls placed
+// or answered here are claimed, released once they end, and the webhook
+// routes registered by CallHandlers forward requests for calls owned by
+// another instance.
+func (ci *CallInitiator) SetCallOwnership(ownership *CallOwnership) {
+	ci.ownership = ownership
+
+	ci.OnCompleted(func(summary CallSummary) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := ownership.Release(ctx, summary.SignalWireCallSID); err != nil {
+			log.Printf("[CallOwnership] Failed to release %s: %v", summary.SignalWireCallSID, err)
+		}
+	})
+}
+
+// claimCall records this instance as the owner of a call it set up
+func (ci *CallInitiator) claimCall(ctx context.Context, callSID string) {
+	if ci.ownership == nil || callSID == "" {
+		return
+	}
+	owner, err := ci.ownership.Claim(ctx, callSID)
+	if err != nil {
+		log.Printf("[CallOwnership] Failed to claim %s: %v", callSID, err)
+		ci.errorLog.Record("ownership", callSID, err)
+		return
+	}
+	if owner != ci.ownership.InstanceURL() {
+		log.Printf("[CallOwnership] Call %s is already owned by %s", callSID, owner)
+	}
+}
+
+// ============================================
+// WEBHOOK FORWARDING
+// ============================================
+
+// sticky wraps a webhook handler so requests for a call owned by another
+// instance are proxied there (WebSocket upgrades included). Calls with no
+// owner are claimed by whichever instance sees them first. If the owner
+// can't be reached, this instance takes the call over.
+func (h *CallHandlers) sticky(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ownership := h.callInitiator.ownership
+		if ownership == nil || r.Header.Get(ForwardedHeader) != "" {
+			next(w, r)
+			return
+		}
+
+		callSID, err := webhookCallSID(r)
+		if err != nil {
+			http.Error(w, "Failed to read request", http.StatusBadRequest)
+			return
+		}
+		if callSID == "" {
+			next(w, r)
+			return
+		}
+
+		owner, err := ownership.Claim(r.Context(), callSID)
+		if err != nil {
+			// Redis trouble shouldn't drop the webhook; handle it here
+			log.Printf("[CallOwnership] %v", err)
+			next(w, r)
+			return
+		}
+		if owner == "" || owner == ownership.InstanceURL() {
+			next(w, r)
+			return
+		}
+
+		h.forward(w, r, owner, callSID, next)
+	}
+}
+
+// forward proxies a webhook to the owning instance, falling back to next
+// if the owner is unreachable
+func (h *CallHandlers) forward(w http.ResponseWriter, r *http.Request, owner, callSID string, next http.HandlerFunc) {
+	target, err := url.Parse(owner)
+	if err != nil {
+		log.Printf("[CallOwnership] Invalid owner %q for %s: %v", owner, callSID, err)
+		next(w, r)
+		return
+	}
+
+	ownership := h.callInitiator.ownership
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.Out.Host = pr.In.Host // Webhook URLs are built from the public host
+			pr.Out.Header.Set(ForwardedHeader, ownership.InstanceURL())
+		},
+		Transport: ownership.transport,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Printf("[CallOwnership] Owner %s unreachable for %s, taking over: %v", owner, callSID, err)
+			if err := ownership.Takeover(r.Context(), callSID); err != nil {
+				log.Printf("[CallOwnership] %v", err)
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next(w, r)
+		},
+	}
+
+	log.Printf("[CallOwnership] Forwarding %s for %s to %s", r.URL.Path, callSID, owner)
+	proxy.ServeHTTP(w, r)
+}
+
+// webhookCallSID finds the call a webhook is about: the call_sid query
+// parameter (streams, dial group legs) or the CallSid form field. The body
+// is restored so it can still be parsed or forwarded.
+func webhookCallSID(r *http.Request) (string, error) {
+	if sid := r.URL.Query().Get("call_sid"); sid != "" {
+		return sid, nil
+	}
+	if r.Body == nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", nil
+	}
+	return form.Get("CallSid"), nil
+}
@@ -0,0 +1,171 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ============================================
+// STUCK CALL REAPER
+// Closing sessions whose final webhooks never arrived
+// ============================================
+
+// ErrUnknownCall is returned when SignalWire has no record of a call
+var ErrUnknownCall = errors.New("call unknown to SignalWire")
+
+// ReaperConfig controls when a live session counts as stuck
+type ReaperConfig struct {
+	Interval           time.Duration // Time between sweeps (default 1m)
+	Grace              time.Duration // Allowance past the call's max duration (default 5m)
+	DefaultMaxDuration time.Duration // For calls without config, e.g. restored or inbound, or without a MaxDuration (default 15m)
+}
+
+// ReapReport summarizes one sweep
+type ReapReport struct {
+	Checked int `json:"checked"` // Sessions past their deadline
+	Synced  int `json:"synced"`  // Ended per SignalWire; state applied from its status
+	HungUp  int `json:"hung_up"` // Still live past the deadline; hung up
+	Live    int `json:"live"`    // Still live with no max duration; left up
+	Forced  int `json:"forced"`  // Unknown to SignalWire; completed locally
+	Errors  int `json:"errors"`
+}
+
+// RunReaper sweeps for stuck calls every Interval until ctx is done
+func (ci *CallInitiator) RunReaper(ctx context.Context, config ReaperConfig) error {
+	config = config.withDefaults()
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if _, err := ci.ReapStuckCalls(ctx, config); err != nil {
+			log.Printf("[CallInitiator] Stuck call sweep failed: %v", err)
+		}
+	}
+}
+
+// ReapStuckCalls finds live sessions older than their max duration plus
+// grace and settles them: calls SignalWire reports as ended get that state,
+// calls still live are hung up (unless they have no max duration), and calls
+// SignalWire doesn't know are completed locally.
+func (ci *CallInitiator) ReapStuckCalls(ctx context.Context, config ReaperConfig) (ReapReport, error) {
+	config = config.withDefaults()
+	var report ReapReport
+
+	// Nothing initiated within the shortest deadline can be stuck yet
+	now := time.Now()
+	sessions, err := ci.store.Query(ctx, SessionFilter{
+		States: []CallState{StateInitiated, StateRinging, StateAnswered, StateInProgress},
+		Until:  now.Add(-config.Grace),
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to load live sessions: %w", err)
+	}
+
+	for _, session := range sessions {
+		session.mu.RLock()
+		callSID := session.SignalWireCallSID
+		deadline := session.reapDeadline(config)
+		unlimited := session.Config != nil && session.Config.MaxDuration <= 0
+		session.mu.RUnlock()
+
+		if callSID == "" || now.Before(deadline) {
+			continue
+		}
+		report.Checked++
+
+		if err := ci.reapCall(ctx, callSID, unlimited, &report); err != nil {
+			log.Printf("[CallInitiator] Failed to reap call %s: %v", callSID, err)
+			ci.errorLog.Record("reaper", callSID, err)
+			report.Errors++
+		}
+	}
+
+	if report.Checked > 0 {
+		log.Printf("[CallInitiator] Reaped %d stuck calls (%d synced, %d hung up, %d live, %d forced, %d errors)",
+			report.Checked, report.Synced, report.HungUp, report.Live, report.Forced, report.Errors)
+	}
+	return report, nil
+}
+
+// reapCall settles one stuck call. Calls with no max duration are only
+// synced with SignalWire, never hung up.
+func (ci *CallInitiator) reapCall(ctx context.Context, callSID string, unlimited bool, report *ReapReport) error {
+	state, _, err := ci.resyncCall(ctx, callSID)
+	switch {
+	case errors.Is(err, ErrUnknownCall):
+		report.Forced++
+		return ci.ApplyCallEvent(ctx, CallEvent{
+			CallSID:  callSID,
+			State:    StateCompleted,
+			Metadata: map[string]interface{}{"reaped": "unknown to SignalWire"},
+		})
+	case err != nil:
+		return err
+	case state.IsTerminal():
+		report.Synced++
+		return nil
+	case unlimited:
+		report.Live++
+		return nil
+	}
+
+	// Still live on SignalWire well past its max duration. Its final
+	// webhook may be lost too, so close the session now rather than hang
+	// it up again on the next sweep.
+	hangupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := ci.endCall(hangupCtx, callSID); err != nil {
+		return err
+	}
+	report.HungUp++
+	return ci.ApplyCallEvent(ctx, CallEvent{
+		CallSID:  callSID,
+		State:    StateCompleted,
+		Metadata: map[string]interface{}{"reaped": "hung up past max duration", "hung_up_by": "system"},
+	})
+}
+
+// reapDeadline returns when a live session counts as stuck. Caller must
+// hold the session lock.
+func (s *CallSession) reapDeadline(config ReaperConfig) time.Time {
+	maxDuration := config.DefaultMaxDuration
+	ringTimeout := 0
+	if s.Config != nil {
+		if s.Config.MaxDuration > 0 {
+			maxDuration = time.Duration(s.Config.MaxDuration) * time.Second
+		}
+		ringTimeout = s.Config.RingTimeout
+	}
+
+	start := s.InitiatedAt
+	if s.AnsweredAt != nil {
+		start = *s.AnsweredAt
+	} else {
+		// Unanswered calls get their ring time on top
+		start = start.Add(time.Duration(ringTimeout) * time.Second)
+	}
+	return start.Add(maxDuration + config.Grace)
+}
+
+// withDefaults fills in unset reaper settings
+func (c ReaperConfig) withDefaults() ReaperConfig {
+	if c.Interval <= 0 {
+		c.Interval = time.Minute
+	}
+	if c.Grace <= 0 {
+		c.Grace = 5 * time.Minute
+	}
+	if c.DefaultMaxDuration <= 0 {
+		c.DefaultMaxDuration = 15 * time.Minute
+	}
+	return c
+}
@@ -0,0 +1,329 @@
+package telephony
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============================================
+// SCHEDULED CALLS
+// Calls persisted now and placed at a later time
+// ============================================
+
+// ErrScheduleNotFound is returned when cancelling a schedule that doesn't
+// exist or whose call has already been placed
+var ErrScheduleNotFound = errors.New("scheduled call not found")
+
+// ScheduleLease is how long a claimed call is reserved for the instance
+// placing it. Calls not completed within it (e.g. after a crash) are claimed
+// again.
+const ScheduleLease = 5 * time.Minute
+
+// MaxScheduleAttempts is how often a scheduled call is claimed before it is
+// left in the store as failed
+const MaxScheduleAttempts = 3
+
+// ScheduledCall is a call waiting for its scheduled time
+type ScheduledCall struct {
+	ID        uuid.UUID  `json:"id"`
+	Config    CallConfig `json:"config"`
+	At        time.Time  `json:"at"`
+	CreatedAt time.Time  `json:"created_at"`
+	Attempts  int        `json:"attempts"` // Times claimed, including the current claim
+}
+
+// ScheduleStore persists scheduled calls so they survive restarts
+type ScheduleStore interface {
+	// Save stores a call, releasing any claim on it
+	Save(ctx context.Context, call ScheduledCall) error
+	// Cancel removes a pending call, returning ErrScheduleNotFound if absent
+	Cancel(ctx context.Context, id uuid.UUID) error
+	// ClaimDue leases and returns every call due at or before now that is
+	// unclaimed (or whose lease has run out) and has attempts left
+	ClaimDue(ctx context.Context, now time.Time) ([]ScheduledCall, error)
+	// Complete removes a claimed call once it has been placed
+	Complete(ctx context.Context, id uuid.UUID) error
+}
+
+// SetScheduleStore replaces the store for scheduled calls
+func (ci *CallInitiator) SetScheduleStore(store ScheduleStore) {
+	ci.schedules = store
+}
+
+// ScheduleCall stores a call to be placed at the given time by
+// RunScheduler and returns its schedule ID
+func (ci *CallInitiator) ScheduleCall(ctx context.Context, config CallConfig, at time.Time) (uuid.UUID, error) {
+	// Reject bad configs now rather than when the call is due
+	check := config
+	if err := ci.resolveSIPPassword(ctx, &check); err != nil {
+		return uuid.Nil, err
+	}
+	if err := ci.validateConfig(&check); err != nil {
+		return uuid.Nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	// The password is looked up again when the call is placed
+	if config.SIPUsername != "" && ci.sipPasswords == nil {
+		return uuid.Nil, fmt.Errorf("invalid config: scheduled sip calls need a sip password resolver (see SetSIPPasswordResolver)")
+	}
+	config.SIPPassword = ""
+
+	call := ScheduledCall{
+		ID:        uuid.New(),
+		Config:    config,
+		At:        at,
+		CreatedAt: time.Now(),
+	}
+	if err := ci.schedules.Save(ctx, call); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to save scheduled call: %w", err)
+	}
+
+	log.Printf("[CallInitiator] Scheduled call %s to %s at %s", call.ID, config.To, at.Format(time.RFC3339))
+	return call.ID, nil
+}
+
+// CancelScheduledCall cancels a call that hasn't been placed yet
+func (ci *CallInitiator) CancelScheduledCall(ctx context.Context, id uuid.UUID) error {
+	return ci.schedules.Cancel(ctx, id)
+}
+
+// RunScheduler places scheduled calls as they fall due, polling the store
+// every pollInterval (default 15s) until ctx is done. Run it on every
+// instance; each due call is leased by one at a time. A call stays in the
+// store until it is placed, so a crash or failed attempt leaves it to be
+// claimed again once its lease runs out, up to MaxScheduleAttempts times.
+// A crash right after placing a call can therefore dial it twice.
+func (ci *CallInitiator) RunScheduler(ctx context.Context, pollInterval time.Duration) error {
+	if pollInterval <= 0 {
+		pollInterval = 15 * time.Second
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if !ci.draining.Load() {
+			due, err := ci.schedules.ClaimDue(ctx, time.Now())
+			if err != nil && ctx.Err() == nil {
+				log.Printf("[CallInitiator] Failed to load scheduled calls: %v", err)
+			}
+			for _, call := range due {
+				go ci.placeScheduled(call)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// placeScheduled places a claimed call and removes it from the store.
+// Calls refused because of a drain are released for another instance or the
+// next start without using up an attempt; other failures are retried once
+// the lease runs out.
+func (ci *CallInitiator) placeScheduled(call ScheduledCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	session, err := ci.InitiateCall(ctx, call.Config)
+	if errors.Is(err, ErrDraining) {
+		call.Attempts--
+		if err := ci.schedules.Save(ctx, call); err != nil {
+			log.Printf("[CallInitiator] Failed to requeue scheduled call %s: %v", call.ID, err)
+		}
+		return
+	}
+	if err != nil {
+		log.Printf("[CallInitiator] Scheduled call %s to %s failed (attempt %d of %d): %v",
+			call.ID, call.Config.To, call.Attempts, MaxScheduleAttempts, err)
+		ci.errorLog.Record("scheduler", "", fmt.Errorf("scheduled call %s: %w", call.ID, err))
+		return
+	}
+
+	if err := ci.schedules.Complete(ctx, call.ID); err != nil {
+		log.Printf("[CallInitiator] Failed to remove placed scheduled call %s: %v", call.ID, err)
+		ci.errorLog.Record("scheduler", session.SignalWireCallSID, fmt.Errorf("scheduled call %s: %w", call.ID, err))
+	}
+	log.Printf("[CallInitiator] Placed scheduled call %s (session %s)", call.ID, session.ID)
+}
+
+// ============================================
+// IN-MEMORY SCHEDULE STORE
+// ============================================
+
+// MemoryScheduleStore keeps scheduled calls in memory (lost on restart)
+type MemoryScheduleStore struct {
+	calls   map[uuid.UUID]ScheduledCall
+	claimed map[uuid.UUID]time.Time // Lease start of claimed calls
+	mu      sync.Mutex
+}
+
+// NewMemoryScheduleStore creates an in-memory schedule store
+func NewMemoryScheduleStore() *MemoryScheduleStore {
+	return &MemoryScheduleStore{
+		calls:   make(map[uuid.UUID]ScheduledCall),
+		claimed: make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Save stores a scheduled call
+func (s *MemoryScheduleStore) Save(ctx context.Context, call ScheduledCall) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[call.ID] = call
+	delete(s.claimed, call.ID)
+	return nil
+}
+
+// Cancel removes a pending call
+func (s *MemoryScheduleStore) Cancel(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.calls[id]; !ok {
+		return ErrScheduleNotFound
+	}
+	delete(s.calls, id)
+	delete(s.claimed, id)
+	return nil
+}
+
+// ClaimDue leases and returns due calls, earliest first
+func (s *MemoryScheduleStore) ClaimDue(ctx context.Context, now time.Time) ([]ScheduledCall, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []ScheduledCall
+	for id, call := range s.calls {
+		if call.At.After(now) || call.Attempts >= MaxScheduleAttempts {
+			continue
+		}
+		if claimedAt, ok := s.claimed[id]; ok && now.Sub(claimedAt) < ScheduleLease {
+			continue
+		}
+		call.Attempts++
+		s.calls[id] = call
+		s.claimed[id] = now
+		due = append(due, call)
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].At.Before(due[j].At) })
+	return due, nil
+}
+
+// Complete removes a placed call
+func (s *MemoryScheduleStore) Complete(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.calls, id)
+	delete(s.claimed, id)
+	return nil
+}
+
+// ============================================
+// POSTGRES SCHEDULE STORE
+// ============================================
+
+// PostgresScheduleStore keeps scheduled calls in the call_schedules table
+// (see migrations)
+type PostgresScheduleStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresScheduleStore creates a schedule store backed by Postgres
+func NewPostgresScheduleStore(db *pgxpool.Pool) *PostgresScheduleStore {
+	return &PostgresScheduleStore{db: db}
+}
+
+// Save upserts a scheduled call
+func (s *PostgresScheduleStore) Save(ctx context.Context, call ScheduledCall) error {
+	config, err := json.Marshal(call.Config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	query := `
+		INSERT INTO call_schedules (id, config, scheduled_at, created_at, attempts)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			config = EXCLUDED.config,
+			scheduled_at = EXCLUDED.scheduled_at,
+			attempts = EXCLUDED.attempts,
+			claimed_at = NULL
+	`
+
+	if _, err := s.db.Exec(ctx, query, call.ID, config, call.At, call.CreatedAt, max(call.Attempts, 0)); err != nil {
+		return fmt.Errorf("failed to save scheduled call: %w", err)
+	}
+	return nil
+}
+
+// Cancel deletes a pending call
+func (s *PostgresScheduleStore) Cancel(ctx context.Context, id uuid.UUID) error {
+	tag, err := s.db.Exec(ctx, `DELETE FROM call_schedules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel scheduled call: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// ClaimDue leases and returns due calls in one statement, so concurrent
+// instances never claim the same call. Rows whose config can't be decoded
+// are logged and skipped.
+func (s *PostgresScheduleStore) ClaimDue(ctx context.Context, now time.Time) ([]ScheduledCall, error) {
+	query := `
+		UPDATE call_schedules
+		SET claimed_at = $1, attempts = attempts + 1
+		WHERE scheduled_at <= $1
+		  AND attempts < $3
+		  AND (claimed_at IS NULL OR claimed_at < $2)
+		RETURNING id, config, scheduled_at, created_at, attempts
+	`
+
+	rows, err := s.db.Query(ctx, query, now, now.Add(-ScheduleLease), MaxScheduleAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim scheduled calls: %w", err)
+	}
+	defer rows.Close()
+
+	var due []ScheduledCall
+	for rows.Next() {
+		var call ScheduledCall
+		var config []byte
+		if err := rows.Scan(&call.ID, &config, &call.At, &call.CreatedAt, &call.Attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled call: %w", err)
+		}
+		if err := json.Unmarshal(config, &call.Config); err != nil {
+			log.Printf("[CallInitiator] Skipping scheduled call %s with unreadable config: %v", call.ID, err)
+			continue
+		}
+		due = append(due, call)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read scheduled calls: %w", err)
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].At.Before(due[j].At) })
+	return due, nil
+}
+
+// Complete deletes a placed call
+func (s *PostgresScheduleStore) Complete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM call_schedules WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to complete scheduled call: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package telephony
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ============================================
+// CALL STATE MACHINE
+// Allowed CallState transitions and webhook ordering
+// ============================================
+
+// callTransitions lists the states reachable from each state
+var callTransitions = map[CallState][]CallState{
+	StateQueued: {
+		StateInitiated, StateRinging, StateAnswered, StateInProgress,
+		StateCompleted, StateFailed, StateNoAnswer, StateBusy, StateCancelled,
+	},
+	StateInitiated: {
+		StateRinging, StateAnswered, StateInProgress,
+		StateCompleted, StateFailed, StateNoAnswer, StateBusy, StateCancelled,
+	},
+	StateRinging: {
+		StateAnswered, StateInProgress,
+		StateCompleted, StateFailed, StateNoAnswer, StateBusy, StateCancelled,
+	},
+	StateAnswered: {
+		StateInProgress, StateCompleted, StateFailed, StateCancelled,
+	},
+	StateInProgress: {
+		StateCompleted, StateFailed, StateCancelled,
+	},
+
+	// Terminal states
+	StateCompleted: {},
+	StateFailed:    {},
+	StateNoAnswer:  {},
+	StateBusy:      {},
+	StateCancelled: {},
+}
+
+// ErrInvalidTransition is matched (errors.Is) by every TransitionError
+var ErrInvalidTransition = errors.New("invalid call state transition")
+
+// TransitionError reports a state change the state machine doesn't allow
+type TransitionError struct {
+	CallSID string
+	From    CallState
+	To      CallState
+}
+
+func (e *TransitionError) Error() string {
+	if e.CallSID == "" {
+		return fmt.Sprintf("invalid call state transition: %s → %s", e.From, e.To)
+	}
+	return fmt.Sprintf("invalid call state transition for %s: %s → %s", e.CallSID, e.From, e.To)
+}
+
+// Unwrap lets errors.Is match ErrInvalidTransition
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// CanTransition reports whether a call may move from one state to another
+func CanTransition(from, to CallState) bool {
+	for _, allowed := range callTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns a *TransitionError when from → to isn't allowed
+func ValidateTransition(from, to CallState) error {
+	if !CanTransition(from, to) {
+		return &TransitionError{From: from, To: to}
+	}
+	return nil
+}
+
+// AllowedTransitions returns the states reachable from a state
+func AllowedTransitions(from CallState) []CallState {
+	return append([]CallState(nil), callTransitions[from]...)
+}
+
+// TransitionGraph returns a copy of the full transition graph
+func TransitionGraph() map[CallState][]CallState {
+	graph := make(map[CallState][]CallState, len(callTransitions))
+	for from, to := range callTransitions {
+		graph[from] = append([]CallState{}, to...)
+	}
+	return graph
+}
+
+// IsValid reports whether a state is part of the state machine
+func (s CallState) IsValid() bool {
+	_, known := callTransitions[s]
+	return known
+}
+
+// IsTerminal reports whether a state ends the call
+func (s CallState) IsTerminal() bool {
+	next, known := callTransitions[s]
+	return known && len(next) == 0
+}
+
+// StateFromStatus maps a SignalWire CallStatus (from webhooks or the call
+// API) to a CallState, reporting false for unknown statuses
+func StateFromStatus(status string) (CallState, bool) {
+	switch status {
+	case "queued", "initiated":
+		return StateInitiated, true
+	case "ringing":
+		return StateRinging, true
+	case "in-progress", "answered":
+		return StateAnswered, true
+	case "completed":
+		return StateCompleted, true
+	case "failed", "error":
+		return StateFailed, true
+	case "no-answer":
+		return StateNoAnswer, true
+	case "busy":
+		return StateBusy, true
+	case "canceled":
+		return StateCancelled, true
+	}
+	return "", false
+}
+
+// CallEvent is a state change reported for a call, typically from a
+// SignalWire status callback
+type CallEvent struct {
+	CallSID string
+	State   CallState
+
+	// Ordering information from the webhook (zero when unknown)
+	SequenceNumber int
+	Timestamp      time.Time
+
+	// Why the call ended, for terminal states (see HangupCauseFromRequest)
+	HangupCause     HangupCause
+	SIPResponseCode int
+
+	// Caller ID attestation, when the webhook reports it (see AttestationFromRequest)
+	Attestation Attestation
+	Verstat     string
+
+	Metadata map[string]interface{}
+}
+
+// isStaleEvent reports whether an event is older than the last one applied.
+// Caller must hold the session lock.
+func (s *CallSession) isStaleEvent(event CallEvent) bool {
+	if event.SequenceNumber > 0 && s.lastSequence > 0 {
+		return event.SequenceNumber <= s.lastSequence
+	}
+	if !event.Timestamp.IsZero() && !s.lastEventAt.IsZero() {
+		return event.Timestamp.Before(s.lastEventAt)
+	}
+	return false
+}
+
+// recordEvent remembers ordering information of an applied event.
+// Caller must hold the session lock.
+func (s *CallSession) recordEvent(event CallEvent) {
+	if event.SequenceNumber > s.lastSequence {
+		s.lastSequence = event.SequenceNumber
+	}
+	if event.Timestamp.After(s.lastEventAt) {
+		s.lastEventAt = event.Timestamp
+	}
+}
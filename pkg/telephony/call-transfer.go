@@ -0,0 +1,286 @@
+package telephony
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================
+// CALL TRANSFER
+// Blind and warm transfer of live calls
+// ============================================
+
+// TransferMode selects how a live call is handed over
+type TransferMode string
+
+const (
+	// TransferBlind redirects the caller straight to the target
+	TransferBlind TransferMode = "blind"
+	// TransferWarm holds the caller until the target answers, then bridges them
+	TransferWarm TransferMode = "warm"
+)
+
+// TransferStatus tracks the progress of a transfer
+type TransferStatus string
+
+const (
+	TransferPending   TransferStatus = "pending"
+	TransferRinging   TransferStatus = "ringing"
+	TransferConnected TransferStatus = "connected"
+	TransferFailed    TransferStatus = "failed"
+)
+
+// TransferOptions customizes a transfer
+type TransferOptions struct {
+	CallerID     string // Caller ID for the new leg (defaults to our number on the call)
+	HoldMusicURL string // Played to the caller while a warm transfer rings
+	RingTimeout  int    // Seconds to ring the target (default 30)
+	FallbackURL  string // Where to send the caller if a warm transfer fails (defaults to the call's AnswerURL)
+	Whisper      string // Said only to the target when they answer, before bridging (warm transfers)
+	WhisperVoice string
+}
+
+// Transfer describes a transfer in progress
+type Transfer struct {
+	ID             uuid.UUID      `json:"id"`
+	CallSID        string         `json:"call_sid"`
+	Target         string         `json:"target"`
+	Mode           TransferMode   `json:"mode"`
+	ConferenceName string         `json:"conference_name,omitempty"`
+	TargetCallSID  string         `json:"target_call_sid,omitempty"`
+	Status         TransferStatus `json:"status"`
+	StartedAt      time.Time      `json:"started_at"`
+}
+
+// TransferCall moves a live call to another number or SIP endpoint
+func (ci *CallInitiator) TransferCall(ctx context.Context, callSID, target string, mode TransferMode) (*Transfer, error) {
+	return ci.TransferCallWithOptions(ctx, callSID, target, mode, TransferOptions{})
+}
+
+// TransferCallWithOptions moves a live call to another number or SIP
+// endpoint. Blind transfers dial the target directly from the caller's leg.
+// Warm transfers park the caller in a conference on hold, ring the target,
+// and let the conference bridge both once the target answers.
+func (ci *CallInitiator) TransferCallWithOptions(ctx context.Context, callSID, target string, mode TransferMode, opts TransferOptions) (*Transfer, error) {
+	if !isSIPURI(target) && !isValidE164(target) {
+		return nil, fmt.Errorf("transfer target must be E.164 or a sip: URI")
+	}
+
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mu.RLock()
+	callerID := session.FromNumber
+	if session.Direction == DirectionInbound {
+		callerID = session.ToNumber
+	}
+	fallbackURL := opts.FallbackURL
+	if fallbackURL == "" && session.Config != nil {
+		fallbackURL = session.Config.AnswerURL
+	}
+	session.mu.RUnlock()
+
+	if opts.CallerID != "" {
+		callerID = opts.CallerID
+	}
+	if opts.RingTimeout <= 0 {
+		opts.RingTimeout = 30
+	}
+
+	transfer := &Transfer{
+		ID:        uuid.New(),
+		CallSID:   callSID,
+		Target:    target,
+		Mode:      mode,
+		Status:    TransferPending,
+		StartedAt: time.Now(),
+	}
+
+	switch mode {
+	case TransferBlind:
+		dial := DialTarget(target, callerID)
+		dial.Timeout = opts.RingTimeout
+		twiml := NewTwiML().Dial(dial)
+
+		if err := ci.updateLiveCall(ctx, callSID, twiml); err != nil {
+			return nil, fmt.Errorf("failed to redirect call: %w", err)
+		}
+		transfer.Status = TransferConnected
+
+	case TransferWarm:
+		transfer.ConferenceName = fmt.Sprintf("transfer-%s", transfer.ID)
+
+		// Park the caller; the conference doesn't start until the target joins
+		hold := NewTwiML().Dial(Dial{
+			Conference: &Conference{
+				Name:                   transfer.ConferenceName,
+				StartConferenceOnEnter: boolPtr(false),
+				EndConferenceOnExit:    true,
+				WaitURL:                opts.HoldMusicURL,
+				Beep:                   "false",
+			},
+		})
+		if err := ci.updateLiveCall(ctx, callSID, hold); err != nil {
+			return nil, fmt.Errorf("failed to place call on hold: %w", err)
+		}
+
+		// Ring the target straight into the conference
+		join := NewTwiML()
+		if opts.Whisper != "" {
+			join.Say(opts.Whisper, opts.WhisperVoice)
+		}
+		join.Dial(Dial{
+			Conference: &Conference{
+				Name:                   transfer.ConferenceName,
+				StartConferenceOnEnter: boolPtr(true),
+				EndConferenceOnExit:    true,
+				Beep:                   "false",
+			},
+		})
+		targetCall, err := ci.createCallWithTwiML(ctx, callerID, target, join, opts.RingTimeout)
+		if err != nil {
+			ci.resumeAfterFailedTransfer(ctx, callSID, fallbackURL)
+			return nil, fmt.Errorf("failed to dial transfer target: %w", err)
+		}
+		transfer.TargetCallSID = targetCall.SID
+		transfer.Status = TransferRinging
+
+		go ci.monitorWarmTransfer(transfer, fallbackURL, time.Duration(opts.RingTimeout+10)*time.Second)
+
+	default:
+		return nil, fmt.Errorf("unknown transfer mode: %s", mode)
+	}
+
+	// Record the transfer on the session
+	_, err = ci.updateSession(ctx, session, func(session *CallSession) error {
+		session.Metadata.Set("transfer_id", transfer.ID.String())
+		session.Metadata.Set("transfer_target", target)
+		session.Metadata.Set("transfer_mode", string(mode))
+		return nil
+	})
+	if err != nil {
+		log.Printf("[CallInitiator] Failed to record transfer on session: %v", err)
+	}
+
+	log.Printf("[CallInitiator] Transfer %s (%s) of call %s to %s", transfer.ID, mode, callSID, target)
+	return transfer, nil
+}
+
+// monitorWarmTransfer watches the target leg and returns the caller to the
+// fallback flow if the target never answers
+func (ci *CallInitiator) monitorWarmTransfer(transfer *Transfer, fallbackURL string, deadline time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("[CallInitiator] Transfer %s timed out waiting for target", transfer.ID)
+			ci.HangupCall(context.Background(), transfer.TargetCallSID)
+			ci.resumeAfterFailedTransfer(context.Background(), transfer.CallSID, fallbackURL)
+			return
+
+		case <-ticker.C:
+			status, err := ci.GetCallStatus(ctx, transfer.TargetCallSID)
+			if err != nil {
+				continue
+			}
+
+			switch status.Status {
+			case "in-progress":
+				log.Printf("[CallInitiator] Transfer %s connected", transfer.ID)
+				return
+			case "busy", "no-answer", "failed", "canceled", "completed":
+				log.Printf("[CallInitiator] Transfer %s target leg ended: %s", transfer.ID, status.Status)
+				ci.resumeAfterFailedTransfer(context.Background(), transfer.CallSID, fallbackURL)
+				return
+			}
+		}
+	}
+}
+
+// resumeAfterFailedTransfer takes the caller off hold
+func (ci *CallInitiator) resumeAfterFailedTransfer(ctx context.Context, callSID, fallbackURL string) {
+	twiml := NewTwiML()
+	if fallbackURL != "" {
+		twiml.Redirect(fallbackURL)
+	} else {
+		twiml.Say("We're sorry, nobody is available to take your call. Goodbye.", "").Hangup()
+	}
+
+	if err := ci.updateLiveCall(ctx, callSID, twiml); err != nil {
+		log.Printf("[CallInitiator] Failed to resume call %s after transfer: %v", callSID, err)
+		ci.errorLog.Record("transfer", callSID, err)
+	}
+}
+
+// ============================================
+// LIVE CALL UPDATES
+// ============================================
+
+// updateLiveCall replaces the instructions a live call is executing
+func (ci *CallInitiator) updateLiveCall(ctx context.Context, callSID string, twiml *TwiML) error {
+	path := fmt.Sprintf("/Accounts/%s/Calls/%s.json", ci.projectID, callSID)
+
+	formData := url.Values{}
+	formData.Set("Twiml", twiml.String())
+
+	resp, err := ci.doRequest(ctx, "POST", path, formData)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// createCallWithTwiML places a call that executes inline TwiML when answered
+func (ci *CallInitiator) createCallWithTwiML(ctx context.Context, from, to string, twiml *TwiML, ringTimeout int) (*SignalWireCallResponse, error) {
+	path := fmt.Sprintf("/Accounts/%s/Calls.json", ci.projectID)
+
+	formData := url.Values{}
+	formData.Set("From", from)
+	formData.Set("To", to)
+	formData.Set("Twiml", twiml.String())
+	formData.Set("Timeout", fmt.Sprintf("%d", ringTimeout))
+
+	resp, err := ci.doRequest(ctx, "POST", path, formData)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var swCall SignalWireCallResponse
+	if err := json.Unmarshal(body, &swCall); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &swCall, nil
+}
@@ -0,0 +1,282 @@
+package telephony
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================
+// CDR EXPORT
+// Streaming call detail records as CSV or JSON Lines
+// ============================================
+
+// CDRFormat is an export file format
+type CDRFormat string
+
+const (
+	CDRFormatCSV   CDRFormat = "csv"
+	CDRFormatJSONL CDRFormat = "jsonl"
+)
+
+// CDRExportOptions selects what is exported and how
+type CDRExportOptions struct {
+	Format    CDRFormat     // Default CSV
+	Fields    []string      // Columns in order, from CDRFields (default all)
+	Filter    SessionFilter // Date range, agency, campaign, ...; States defaults to ended calls
+	BatchSize int           // Sessions read per store query (default 500)
+}
+
+// cdrField renders one column of a call record
+type cdrField struct {
+	name  string
+	value func(s *CallSession) interface{}
+}
+
+// cdrFields lists the exportable columns in their default order
+var cdrFields = []cdrField{
+	{"id", func(s *CallSession) interface{} { return s.ID }},
+	{"signalwire_call_sid", func(s *CallSession) interface{} { return s.SignalWireCallSID }},
+	{"agency_id", func(s *CallSession) interface{} { return s.AgencyID }},
+	{"campaign_id", func(s *CallSession) interface{} { return s.CampaignID }},
+	{"target_id", func(s *CallSession) interface{} { return s.TargetID }},
+	{"direction", func(s *CallSession) interface{} { return s.Direction }},
+	{"from_number", func(s *CallSession) interface{} { return s.FromNumber }},
+	{"to_number", func(s *CallSession) interface{} { return s.ToNumber }},
+	{"caller_name", func(s *CallSession) interface{} { return s.CallerName }},
+	{"state", func(s *CallSession) interface{} { return s.State }},
+	{"outcome", func(s *CallSession) interface{} { return s.Outcome }},
+	{"outcome_reason", func(s *CallSession) interface{} { return s.OutcomeReason }},
+	{"answered_by", func(s *CallSession) interface{} { return s.AnsweredBy }},
+	{"hangup_cause", func(s *CallSession) interface{} { return s.HangupCause }},
+	{"sip_response_code", func(s *CallSession) interface{} { return s.SIPResponseCode }},
+	{"attestation", func(s *CallSession) interface{} { return s.Attestation }},
+	{"verstat", func(s *CallSession) interface{} { return s.Verstat }},
+	{"disposition", func(s *CallSession) interface{} { return s.Disposition }},
+	{"disposition_notes", func(s *CallSession) interface{} { return s.DispositionNotes }},
+	{"goal", func(s *CallSession) interface{} { return s.GoalOutcome.goal() }},
+	{"goal_achieved", func(s *CallSession) interface{} { return s.GoalOutcome.achieved() }},
+	{"sentiment", func(s *CallSession) interface{} { return s.Sentiment.average() }},
+	{"sentiment_min", func(s *CallSession) interface{} { return s.Sentiment.min() }},
+	{"initiated_at", func(s *CallSession) interface{} { return s.InitiatedAt }},
+	{"ringing_at", func(s *CallSession) interface{} { return s.RingingAt }},
+	{"answered_at", func(s *CallSession) interface{} { return s.AnsweredAt }},
+	{"completed_at", func(s *CallSession) interface{} { return s.CompletedAt }},
+	{"duration_seconds", func(s *CallSession) interface{} { return s.DurationSeconds }},
+	{"talk_time_seconds", func(s *CallSession) interface{} { return s.TalkTimeSeconds }},
+	{"ring_time_seconds", func(s *CallSession) interface{} { return s.RingTimeSeconds }},
+	{"voicemail_detected", func(s *CallSession) interface{} { return s.VoicemailDetected }},
+	{"voicemail_message_left", func(s *CallSession) interface{} { return s.VoicemailMessageLeft }},
+	{"recording_url", func(s *CallSession) interface{} { return s.RecordingURL }},
+	{"recording_sid", func(s *CallSession) interface{} { return s.RecordingSID }},
+	{"cost_usd", func(s *CallSession) interface{} { return s.CostUSD }},
+	{"error_code", func(s *CallSession) interface{} { return s.ErrorCode }},
+	{"error_message", func(s *CallSession) interface{} { return s.ErrorMessage }},
+}
+
+// CDRFields returns the names of the exportable columns
+func CDRFields() []string {
+	names := make([]string, len(cdrFields))
+	for i, field := range cdrFields {
+		names[i] = field.name
+	}
+	return names
+}
+
+// ExportCDRs writes a record for every ended call matching the options to
+// w, newest first, and returns how many were written. Sessions are read from
+// the store a batch at a time, so exports of any size use constant memory.
+func ExportCDRs(ctx context.Context, store CallSessionStore, w io.Writer, opts CDRExportOptions) (int, error) {
+	fields, err := selectCDRFields(opts.Fields)
+	if err != nil {
+		return 0, err
+	}
+
+	filter := opts.Filter
+	if len(filter.States) == 0 {
+		filter.States = []CallState{StateCompleted, StateFailed, StateNoAnswer, StateBusy, StateCancelled}
+	}
+	filter.Limit = opts.BatchSize
+	if filter.Limit <= 0 {
+		filter.Limit = 500
+	}
+
+	var writer cdrWriter
+	switch opts.Format {
+	case CDRFormatCSV, "":
+		writer = newCSVCDRWriter(w, fields)
+	case CDRFormatJSONL:
+		writer = newJSONLCDRWriter(w, fields)
+	default:
+		return 0, fmt.Errorf("unknown CDR format %q", opts.Format)
+	}
+
+	written := 0
+	for {
+		sessions, err := store.Query(ctx, filter)
+		if err != nil {
+			return written, fmt.Errorf("failed to query sessions: %w", err)
+		}
+
+		for _, session := range sessions {
+			session.mu.RLock()
+			err := writer.write(session)
+			session.mu.RUnlock()
+			if err != nil {
+				return written, fmt.Errorf("failed to write record: %w", err)
+			}
+			written++
+		}
+
+		if len(sessions) < filter.Limit {
+			break
+		}
+		last := sessions[len(sessions)-1]
+		last.mu.RLock()
+		filter.After = CursorFor(last)
+		last.mu.RUnlock()
+	}
+
+	if err := writer.flush(); err != nil {
+		return written, fmt.Errorf("failed to write records: %w", err)
+	}
+	return written, nil
+}
+
+// selectCDRFields resolves column names, defaulting to every column
+func selectCDRFields(names []string) ([]cdrField, error) {
+	if len(names) == 0 {
+		return cdrFields, nil
+	}
+
+	byName := make(map[string]cdrField, len(cdrFields))
+	for _, field := range cdrFields {
+		byName[field.name] = field
+	}
+
+	fields := make([]cdrField, 0, len(names))
+	for _, name := range names {
+		field, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown CDR field %q", name)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// cdrWriter encodes records in one format
+type cdrWriter interface {
+	write(session *CallSession) error
+	flush() error
+}
+
+// csvCDRWriter writes a header row, then one row per call
+type csvCDRWriter struct {
+	out         *csv.Writer
+	fields      []cdrField
+	wroteHeader bool
+}
+
+func newCSVCDRWriter(w io.Writer, fields []cdrField) *csvCDRWriter {
+	return &csvCDRWriter{out: csv.NewWriter(w), fields: fields}
+}
+
+func (c *csvCDRWriter) write(session *CallSession) error {
+	if !c.wroteHeader {
+		header := make([]string, len(c.fields))
+		for i, field := range c.fields {
+			header[i] = field.name
+		}
+		if err := c.out.Write(header); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	row := make([]string, len(c.fields))
+	for i, field := range c.fields {
+		row[i] = csvValue(field.value(session))
+	}
+	return c.out.Write(row)
+}
+
+func (c *csvCDRWriter) flush() error {
+	c.out.Flush()
+	return c.out.Error()
+}
+
+// csvValue formats a column value as text; absent values are empty
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *float64:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	case *bool:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatBool(*v)
+	case *uuid.UUID:
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// jsonlCDRWriter writes one JSON object per line, keys in field order
+type jsonlCDRWriter struct {
+	out    *bufio.Writer
+	fields []cdrField
+}
+
+func newJSONLCDRWriter(w io.Writer, fields []cdrField) *jsonlCDRWriter {
+	return &jsonlCDRWriter{out: bufio.NewWriter(w), fields: fields}
+}
+
+func (j *jsonlCDRWriter) write(session *CallSession) error {
+	j.out.WriteByte('{')
+	for i, field := range j.fields {
+		if i > 0 {
+			j.out.WriteByte(',')
+		}
+		key, _ := json.Marshal(field.name)
+		value, err := json.Marshal(field.value(session))
+		if err != nil {
+			return err
+		}
+		j.out.Write(key)
+		j.out.WriteByte(':')
+		j.out.Write(value)
+	}
+	j.out.WriteString("}\n")
+	return nil
+}
+
+func (j *jsonlCDRWriter) flush() error {
+	return j.out.Flush()
+}
@@ -0,0 +1,358 @@
+package telephony
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================
+// CLICK-TO-CALL
+// Dialing an agent first, then connecting them to a customer
+// ============================================
+
+// Routes for click-to-call; SignalWire reaches the answer route through the
+// initiator's public base URL. ClickToCallPath is where hosts conventionally
+// mount HandleClickToCall, which RegisterRoutes leaves out.
+const (
+	ClickToCallPath       = "/api/telephony/click-to-call"
+	ClickToCallAnswerPath = "/api/telephony/click-to-call/answer"
+)
+
+// Session metadata linking the two legs
+const (
+	clickToCallCustomerKey = "click_to_call_customer"  // Customer number, on the agent leg
+	clickToCallCallerIDKey = "click_to_call_caller_id" // Caller ID shown to the customer, on the agent leg
+	clickToCallWhisperKey  = "click_to_call_whisper"   // Said to the agent before dialing, on the agent leg
+	clickToCallPeerKey     = "click_to_call_peer"      // Call SID of the other leg
+	clickToCallPeerIDKey   = "click_to_call_peer_session_id"
+)
+
+// ClickToCallRequest describes an agent-first call
+type ClickToCallRequest struct {
+	AgentNumber      string    `json:"agent_number"`                 // E.164 number or sip: URI
+	CustomerNumber   string    `json:"customer_number"`              // E.164 number
+	From             string    `json:"from"`                         // Our number, shown to the agent
+	CustomerCallerID string    `json:"customer_caller_id,omitempty"` // Shown to the customer (defaults to From)
+	AgencyID         uuid.UUID `json:"agency_id"`
+	CampaignID       uuid.UUID `json:"campaign_id,omitempty"`
+	Whisper          string    `json:"whisper,omitempty"`      // Said to the agent before the customer is dialed
+	RingTimeout      int       `json:"ring_timeout,omitempty"` // Seconds to ring each leg (default 30)
+}
+
+// ClickToCall is an agent-first call in progress
+type ClickToCall struct {
+	AgentSessionID    uuid.UUID  `json:"agent_session_id"`
+	AgentCallSID      string     `json:"agent_call_sid"`
+	AgentState        CallState  `json:"agent_state"`
+	CustomerSessionID *uuid.UUID `json:"customer_session_id,omitempty"` // Set once the agent answers
+	CustomerCallSID   string     `json:"customer_call_sid,omitempty"`
+	CustomerState     CallState  `json:"customer_state,omitempty"`
+	ConferenceName    string     `json:"conference_name"`
+}
+
+// ClickToCall rings the agent and, once they answer, dials the customer.
+// Both legs join a private conference; when either hangs up the other is
+// disconnected. The customer's session appears on the result of
+// GetClickToCall after the agent answers. Requires SetPublicBaseURL.
+func (ci *CallInitiator) ClickToCall(ctx context.Context, req ClickToCallRequest) (*ClickToCall, error) {
+	if !isValidE164(req.CustomerNumber) {
+		return nil, fmt.Errorf("customer number must be in E.164 format (+1234567890)")
+	}
+	callerID := req.CustomerCallerID
+	if callerID == "" {
+		callerID = req.From
+	}
+	if !isValidE164(callerID) {
+		return nil, fmt.Errorf("customer caller ID must be in E.164 format (+1234567890)")
+	}
+
+	answerURL, err := ci.webhookURL(ClickToCallAnswerPath, url.Values{"leg": {"agent"}})
+	if err != nil {
+		return nil, err
+	}
+	statusURL, err := ci.webhookURL("/api/telephony/calls/status", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ci.watchClickToCalls()
+
+	agent, err := ci.InitiateCall(ctx, CallConfig{
+		From:              req.From,
+		To:                req.AgentNumber,
+		AgencyID:          req.AgencyID,
+		CampaignID:        req.CampaignID,
+		RingTimeout:       req.RingTimeout,
+		AnswerURL:         answerURL,
+		StatusCallbackURL: statusURL,
+		Metadata: map[string]interface{}{
+			clickToCallCustomerKey: req.CustomerNumber,
+			clickToCallCallerIDKey: callerID,
+			clickToCallWhisperKey:  req.Whisper,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call agent: %w", err)
+	}
+
+	log.Printf("[CallInitiator] Click-to-call: ringing agent %s for customer %s", req.AgentNumber, req.CustomerNumber)
+	return clickToCallFromSession(agent, nil), nil
+}
+
+// GetClickToCall returns the state of a click-to-call by its agent call SID
+func (ci *CallInitiator) GetClickToCall(ctx context.Context, agentCallSID string) (*ClickToCall, error) {
+	agent, err := ci.lookupSession(ctx, agentCallSID)
+	if err != nil {
+		return nil, err
+	}
+
+	agent.mu.RLock()
+	customerCallSID, _ := agent.Metadata.GetString(clickToCallPeerKey)
+	agent.mu.RUnlock()
+
+	var customer *CallSession
+	if customerCallSID != "" {
+		if customer, err = ci.lookupSession(ctx, customerCallSID); err != nil {
+			return nil, err
+		}
+	}
+	return clickToCallFromSession(agent, customer), nil
+}
+
+// clickToCallFromSession describes a click-to-call from its legs' sessions
+func clickToCallFromSession(agent, customer *CallSession) *ClickToCall {
+	agentSummary := agent.Summary()
+	c := &ClickToCall{
+		AgentSessionID: agentSummary.ID,
+		AgentCallSID:   agentSummary.SignalWireCallSID,
+		AgentState:     agentSummary.State,
+		ConferenceName: clickToCallConference(agentSummary.ID),
+	}
+	if customer != nil {
+		customerSummary := customer.Summary()
+		c.CustomerSessionID = &customerSummary.ID
+		c.CustomerCallSID = customerSummary.SignalWireCallSID
+		c.CustomerState = customerSummary.State
+	}
+	return c
+}
+
+func clickToCallConference(agentSessionID uuid.UUID) string {
+	return fmt.Sprintf("click-to-call-%s", agentSessionID)
+}
+
+// dialClickToCallCustomer places the customer leg once the agent answers
+func (ci *CallInitiator) dialClickToCallCustomer(ctx context.Context, agentCallSID string) error {
+	agent, err := ci.lookupSession(ctx, agentCallSID)
+	if err != nil {
+		return err
+	}
+
+	agent.mu.RLock()
+	customerNumber, _ := agent.Metadata.GetString(clickToCallCustomerKey)
+	callerID, _ := agent.Metadata.GetString(clickToCallCallerIDKey)
+	config := CallConfig{
+		From:     callerID,
+		To:       customerNumber,
+		AgencyID: agent.AgencyID,
+	}
+	if agent.CampaignID != nil {
+		config.CampaignID = *agent.CampaignID
+	}
+	if agent.Config != nil {
+		config.RingTimeout = agent.Config.RingTimeout
+		config.StatusCallbackURL = agent.Config.StatusCallbackURL
+	}
+	conference := clickToCallConference(agent.ID)
+	agent.mu.RUnlock()
+
+	if config.AnswerURL, err = ci.webhookURL(ClickToCallAnswerPath, url.Values{
+		"leg":        {"customer"},
+		"conference": {conference},
+	}); err != nil {
+		return err
+	}
+	config.Metadata = map[string]interface{}{clickToCallPeerKey: agentCallSID}
+
+	customer, err := ci.InitiateCall(ctx, config)
+	if err != nil {
+		return fmt.Errorf("failed to call customer: %w", err)
+	}
+
+	customerSummary := customer.Summary()
+	_, err = ci.updateSession(ctx, agent, func(agent *CallSession) error {
+		agent.Metadata.Set(clickToCallPeerKey, customerSummary.SignalWireCallSID)
+		agent.Metadata.Set(clickToCallPeerIDKey, customerSummary.ID.String())
+		return nil
+	})
+	if err != nil {
+		log.Printf("[CallInitiator] Failed to record click-to-call customer on session: %v", err)
+	}
+	return nil
+}
+
+// watchClickToCalls hangs up the other leg when either leg of a
+// click-to-call ends, e.g. the customer didn't answer or the agent gave up
+// while the customer was ringing
+func (ci *CallInitiator) watchClickToCalls() {
+	ci.clickToCallOnce.Do(func() {
+		ci.OnCompleted(func(summary CallSummary) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			session, err := ci.store.GetBySID(ctx, summary.SignalWireCallSID)
+			if err != nil {
+				return
+			}
+			session.mu.RLock()
+			peer, _ := session.Metadata.GetString(clickToCallPeerKey)
+			session.mu.RUnlock()
+			if peer == "" {
+				return
+			}
+
+			if peerSession, err := ci.lookupSession(ctx, peer); err == nil {
+				peerSession.mu.RLock()
+				done := peerSession.State.IsTerminal()
+				peerSession.mu.RUnlock()
+				if done {
+					return
+				}
+			}
+			log.Printf("[CallInitiator] Click-to-call leg %s ended, hanging up %s", summary.SignalWireCallSID, peer)
+			if err := ci.HangupCall(ctx, peer); err != nil {
+				log.Printf("[CallInitiator] Failed to hang up click-to-call leg %s: %v", peer, err)
+			}
+		})
+	})
+}
+
+// HandleClickToCall starts a click-to-call (POST) or reports one's state
+// (GET ?call_sid=<agent call SID>).
+//
+// It dials whatever numbers it is given and has no authentication of its
+// own, so it is not part of RegisterRoutes. Mount it behind your auth
+// middleware:
+//
+//	mux.Handle(telephony.ClickToCallPath, requireAgent(http.HandlerFunc(handlers.HandleClickToCall)))
+func (h *CallHandlers) HandleClickToCall(w http.ResponseWriter, r *http.Request) {
+	var (
+		call *ClickToCall
+		err  error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		callSID := r.URL.Query().Get("call_sid")
+		if callSID == "" {
+			http.Error(w, "Missing call_sid", http.StatusBadRequest)
+			return
+		}
+		if call, err = h.callInitiator.GetClickToCall(r.Context(), callSID); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+	case http.MethodPost:
+		var req ClickToCallRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AgentNumber == "" || req.CustomerNumber == "" {
+			http.Error(w, "Missing agent_number or customer_number", http.StatusBadRequest)
+			return
+		}
+		if call, err = h.callInitiator.ClickToCall(r.Context(), req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(call)
+		return
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(call)
+}
+
+// HandleClickToCallAnswer runs when either leg answers. The agent waits in
+// the conference on hold while the customer is dialed; the customer joins
+// and the conference starts.
+func (h *CallHandlers) HandleClickToCallAnswer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	callSID := r.FormValue("CallSid")
+	if callSID == "" {
+		http.Error(w, "Missing CallSid", http.StatusBadRequest)
+		return
+	}
+
+	switch r.URL.Query().Get("leg") {
+	case "agent":
+		session, err := h.callInitiator.lookupSession(r.Context(), callSID)
+		if err != nil {
+			log.Printf("[CallHandlers] Click-to-call answer for unknown call %s", callSID)
+			writeTwiML(w, NewTwiML().Hangup())
+			return
+		}
+		session.mu.RLock()
+		whisper, _ := session.Metadata.GetString(clickToCallWhisperKey)
+		conference := clickToCallConference(session.ID)
+		session.mu.RUnlock()
+
+		// Dialing outlasts the webhook, so place the customer leg in the background
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := h.callInitiator.dialClickToCallCustomer(ctx, callSID); err != nil {
+				log.Printf("[CallHandlers] Click-to-call for %s: %v", callSID, err)
+				h.callInitiator.Errors().Record("click-to-call", callSID, err)
+				h.callInitiator.HangupCall(context.Background(), callSID)
+			}
+		}()
+
+		twiml := NewTwiML()
+		if whisper != "" {
+			twiml.Say(whisper, "")
+		}
+		writeTwiML(w, twiml.Dial(Dial{
+			Conference: &Conference{
+				Name:                   conference,
+				StartConferenceOnEnter: boolPtr(false),
+				EndConferenceOnExit:    true,
+				MaxParticipants:        2,
+				Beep:                   "false",
+			},
+		}))
+
+	case "customer":
+		conference := r.URL.Query().Get("conference")
+		if conference == "" {
+			http.Error(w, "Missing conference", http.StatusBadRequest)
+			return
+		}
+		writeTwiML(w, NewTwiML().Dial(Dial{
+			Conference: &Conference{
+				Name:                   conference,
+				StartConferenceOnEnter: boolPtr(true),
+				EndConferenceOnExit:    true,
+				MaxParticipants:        2,
+				Beep:                   "false",
+			},
+		}))
+
+	default:
+		http.Error(w, "Unknown leg", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,583 @@
+package telephony
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================
+// CONFERENCE MANAGER
+// Named conferences with tracked, controllable call legs
+// ============================================
+
+// ConferenceEventsPath is the webhook path receiving conference status callbacks
+const ConferenceEventsPath = "/api/telephony/conferences/events"
+
+// ConferenceStatus is the lifecycle state of a conference
+type ConferenceStatus string
+
+const (
+	ConferencePending    ConferenceStatus = "pending"     // Created, nobody joined yet
+	ConferenceInProgress ConferenceStatus = "in-progress" // Started on SignalWire
+	ConferenceCompleted  ConferenceStatus = "completed"   // Ended
+)
+
+// ConferenceEventType identifies a conference event
+type ConferenceEventType string
+
+const (
+	ConferenceStarted   ConferenceEventType = "conference-start"
+	ConferenceEnded     ConferenceEventType = "conference-end"
+	ParticipantJoined   ConferenceEventType = "participant-join"
+	ParticipantLeft     ConferenceEventType = "participant-leave"
+	ParticipantMuted    ConferenceEventType = "participant-mute"
+	ParticipantUnmuted  ConferenceEventType = "participant-unmute"
+	ParticipantDeafened ConferenceEventType = "participant-hold"
+	ParticipantUndeafed ConferenceEventType = "participant-unhold"
+)
+
+// ConferenceEvent is delivered to listeners when a conference changes
+type ConferenceEvent struct {
+	Type          ConferenceEventType `json:"type"`
+	Conference    string              `json:"conference"`
+	ConferenceSID string              `json:"conference_sid,omitempty"`
+	CallSID       string              `json:"call_sid,omitempty"`
+	Label         string              `json:"label,omitempty"`
+	Timestamp     time.Time           `json:"timestamp"`
+}
+
+// ConferenceParticipant is one call leg in a conference
+type ConferenceParticipant struct {
+	CallSID  string     `json:"call_sid"`
+	Label    string     `json:"label,omitempty"` // e.g. "ai", "customer", "agent"
+	Muted    bool       `json:"muted"`
+	Deaf     bool       `json:"deaf"`
+	Coaching string     `json:"coaching,omitempty"` // Call SID this leg whispers to
+	Joined   bool       `json:"joined"`
+	AddedAt  time.Time  `json:"added_at"`
+	JoinedAt *time.Time `json:"joined_at,omitempty"`
+}
+
+// ConferenceRoom is a named conference and its participants
+type ConferenceRoom struct {
+	Name      string           `json:"name"`
+	SID       string           `json:"sid,omitempty"`
+	Status    ConferenceStatus `json:"status"`
+	CreatedAt time.Time        `json:"created_at"`
+	StartedAt *time.Time       `json:"started_at,omitempty"`
+	EndedAt   *time.Time       `json:"ended_at,omitempty"`
+
+	participants map[string]*ConferenceParticipant
+	mu           sync.RWMutex
+}
+
+// ParticipantOptions controls how a leg joins a conference
+type ParticipantOptions struct {
+	Label        string
+	Muted        bool
+	StartOnEnter *bool  // Start the conference when this leg joins (default true)
+	EndOnExit    bool   // End the conference when this leg leaves
+	WaitURL      string // Hold music until the conference starts
+	Coach        string // Call SID to whisper to (heard only by that leg)
+	CallerID     string // Caller ID for dialed legs
+	RingTimeout  int    // Seconds to ring dialed legs (default 30)
+}
+
+// ConferenceManager creates conferences and controls their participants
+type ConferenceManager struct {
+	initiator   *CallInitiator
+	callbackURL string // Absolute URL of ConferenceEventsPath
+	holdURL     string // Played to deafened participants
+
+	conferences map[string]*ConferenceRoom
+	listeners   []func(ConferenceEvent)
+	mu          sync.RWMutex
+}
+
+// NewConferenceManager creates a new conference manager. callbackURL is the
+// public URL of ConferenceEventsPath used for participant tracking.
+func NewConferenceManager(initiator *CallInitiator, callbackURL string) *ConferenceManager {
+	return &ConferenceManager{
+		initiator:   initiator,
+		callbackURL: callbackURL,
+		conferences: make(map[string]*ConferenceRoom),
+	}
+}
+
+// SetHoldURL sets what deafened participants hear (e.g. TwiML with a <Pause>
+// for silence); SignalWire's default hold music is used when empty
+func (m *ConferenceManager) SetHoldURL(holdURL string) {
+	m.holdURL = holdURL
+}
+
+// OnEvent registers a listener for conference events
+func (m *ConferenceManager) OnEvent(listener func(ConferenceEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, listener)
+}
+
+// Create registers a named conference. Creating an existing name returns it.
+func (m *ConferenceManager) Create(name string) *ConferenceRoom {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if room, ok := m.conferences[name]; ok {
+		return room
+	}
+
+	room := &ConferenceRoom{
+		Name:         name,
+		Status:       ConferencePending,
+		CreatedAt:    time.Now(),
+		participants: make(map[string]*ConferenceParticipant),
+	}
+	m.conferences[name] = room
+	log.Printf("[ConferenceManager] Created conference: %s", name)
+	return room
+}
+
+// Get returns a conference by name
+func (m *ConferenceManager) Get(name string) *ConferenceRoom {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.conferences[name]
+}
+
+// List returns all tracked conferences; ended conferences are dropped once
+// their end is reported
+func (m *ConferenceManager) List() []*ConferenceRoom {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rooms := make([]*ConferenceRoom, 0, len(m.conferences))
+	for _, room := range m.conferences {
+		rooms = append(rooms, room)
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].CreatedAt.Before(rooms[j].CreatedAt)
+	})
+	return rooms
+}
+
+// Participants returns a snapshot of a conference's participants
+func (room *ConferenceRoom) Participants() []ConferenceParticipant {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	participants := make([]ConferenceParticipant, 0, len(room.participants))
+	for _, p := range room.participants {
+		participants = append(participants, *p)
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].AddedAt.Before(participants[j].AddedAt)
+	})
+	return participants
+}
+
+// Participant returns one participant by call SID
+func (room *ConferenceRoom) Participant(callSID string) (ConferenceParticipant, bool) {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	p, ok := room.participants[callSID]
+	if !ok {
+		return ConferenceParticipant{}, false
+	}
+	return *p, true
+}
+
+// ============================================
+// CALL LEGS
+// ============================================
+
+// AddCall moves a live call into a conference
+func (m *ConferenceManager) AddCall(ctx context.Context, name, callSID string, opts ParticipantOptions) error {
+	room := m.Create(name)
+
+	if err := m.initiator.updateLiveCall(ctx, callSID, m.joinTwiML(room, opts)); err != nil {
+		m.initiator.errorLog.Record("conference", callSID, err)
+		return fmt.Errorf("failed to move call into conference: %w", err)
+	}
+
+	room.addParticipant(callSID, opts)
+	log.Printf("[ConferenceManager] Added call %s to %s", callSID, name)
+	return nil
+}
+
+// Dial calls a number or SIP endpoint straight into a conference and
+// returns the new leg's call SID
+func (m *ConferenceManager) Dial(ctx context.Context, name, to string, opts ParticipantOptions) (string, error) {
+	if !isSIPURI(to) && !isValidE164(to) {
+		return "", fmt.Errorf("conference participant must be E.164 or a sip: URI")
+	}
+	if opts.CallerID == "" {
+		return "", fmt.Errorf("caller ID is required to dial a participant")
+	}
+	if opts.RingTimeout <= 0 {
+		opts.RingTimeout = 30
+	}
+
+	room := m.Create(name)
+
+	call, err := m.initiator.createCallWithTwiML(ctx, opts.CallerID, to, m.joinTwiML(room, opts), opts.RingTimeout)
+	if err != nil {
+		m.initiator.errorLog.Record("conference", "", err)
+		return "", fmt.Errorf("failed to dial participant: %w", err)
+	}
+
+	room.addParticipant(call.SID, opts)
+	log.Printf("[ConferenceManager] Dialing %s into %s (call: %s)", to, name, call.SID)
+	return call.SID, nil
+}
+
+// Remove kicks a participant out of a conference
+func (m *ConferenceManager) Remove(ctx context.Context, name, callSID string) error {
+	room, sid, err := m.resolve(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/Accounts/%s/Conferences/%s/Participants/%s.json", m.initiator.projectID, sid, callSID)
+	resp, err := m.initiator.doRequest(ctx, "DELETE", path, nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	room.mu.Lock()
+	delete(room.participants, callSID)
+	room.mu.Unlock()
+
+	log.Printf("[ConferenceManager] Removed call %s from %s", callSID, name)
+	return nil
+}
+
+// Mute mutes or unmutes a participant
+func (m *ConferenceManager) Mute(ctx context.Context, name, callSID string, muted bool) error {
+	formData := url.Values{}
+	formData.Set("Muted", fmt.Sprintf("%t", muted))
+
+	if err := m.updateParticipant(ctx, name, callSID, formData); err != nil {
+		return err
+	}
+	return m.setParticipant(name, callSID, func(p *ConferenceParticipant) { p.Muted = muted })
+}
+
+// Deafen stops or resumes a participant hearing the conference. Deafened
+// participants are put on hold, which also mutes them.
+func (m *ConferenceManager) Deafen(ctx context.Context, name, callSID string, deaf bool) error {
+	formData := url.Values{}
+	formData.Set("Hold", fmt.Sprintf("%t", deaf))
+	if deaf && m.holdURL != "" {
+		formData.Set("HoldUrl", m.holdURL)
+	}
+
+	if err := m.updateParticipant(ctx, name, callSID, formData); err != nil {
+		return err
+	}
+	return m.setParticipant(name, callSID, func(p *ConferenceParticipant) { p.Deaf = deaf })
+}
+
+// End ends a conference for all participants
+func (m *ConferenceManager) End(ctx context.Context, name string) error {
+	room, sid, err := m.resolve(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/Accounts/%s/Conferences/%s.json", m.initiator.projectID, sid)
+	formData := url.Values{}
+	formData.Set("Status", "completed")
+
+	resp, err := m.initiator.doRequest(ctx, "POST", path, formData)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	room.end()
+	m.forget(name, room)
+	return nil
+}
+
+// joinTwiML builds the instructions joining a leg to a conference
+func (m *ConferenceManager) joinTwiML(room *ConferenceRoom, opts ParticipantOptions) *TwiML {
+	startOnEnter := opts.StartOnEnter
+	if startOnEnter == nil {
+		startOnEnter = boolPtr(true)
+	}
+
+	conference := &Conference{
+		Name:                   room.Name,
+		Muted:                  opts.Muted,
+		Beep:                   "false",
+		StartConferenceOnEnter: startOnEnter,
+		EndConferenceOnExit:    opts.EndOnExit,
+		WaitURL:                opts.WaitURL,
+		Coach:                  opts.Coach,
+	}
+	if m.callbackURL != "" {
+		conference.StatusCallback = m.callbackURL
+		conference.StatusCallbackEvent = "start end join leave mute hold"
+	}
+
+	return NewTwiML().Dial(Dial{Conference: conference})
+}
+
+// addParticipant records a leg that is joining
+func (room *ConferenceRoom) addParticipant(callSID string, opts ParticipantOptions) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	room.participants[callSID] = &ConferenceParticipant{
+		CallSID:  callSID,
+		Label:    opts.Label,
+		Muted:    opts.Muted,
+		Coaching: opts.Coach,
+		AddedAt:  time.Now(),
+	}
+}
+
+// end marks a conference completed
+func (room *ConferenceRoom) end() {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if room.Status == ConferenceCompleted {
+		return
+	}
+	now := time.Now()
+	room.Status = ConferenceCompleted
+	room.EndedAt = &now
+}
+
+// forget stops tracking an ended conference, unless its name has already
+// been reused
+func (m *ConferenceManager) forget(name string, room *ConferenceRoom) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.conferences[name] == room {
+		delete(m.conferences, name)
+	}
+}
+
+// setParticipant applies a change to a tracked participant
+func (m *ConferenceManager) setParticipant(name, callSID string, apply func(*ConferenceParticipant)) error {
+	room := m.Get(name)
+	if room == nil {
+		return fmt.Errorf("conference not found: %s", name)
+	}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	p, ok := room.participants[callSID]
+	if !ok {
+		return fmt.Errorf("participant not found: %s", callSID)
+	}
+	apply(p)
+	return nil
+}
+
+// ============================================
+// SIGNALWIRE API
+// ============================================
+
+// resolve returns a conference and its SignalWire SID, looking the SID up
+// by name if no status callback has reported it yet
+func (m *ConferenceManager) resolve(ctx context.Context, name string) (*ConferenceRoom, string, error) {
+	room := m.Get(name)
+	if room == nil {
+		return nil, "", fmt.Errorf("conference not found: %s", name)
+	}
+
+	room.mu.RLock()
+	sid := room.SID
+	room.mu.RUnlock()
+	if sid != "" {
+		return room, sid, nil
+	}
+
+	query := url.Values{}
+	query.Set("FriendlyName", name)
+	query.Set("Status", string(ConferenceInProgress))
+	path := fmt.Sprintf("/Accounts/%s/Conferences.json?%s", m.initiator.projectID, query.Encode())
+
+	resp, err := m.initiator.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var list struct {
+		Conferences []struct {
+			SID string `json:"sid"`
+		} `json:"conferences"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(list.Conferences) == 0 {
+		return nil, "", fmt.Errorf("conference not started: %s", name)
+	}
+
+	sid = list.Conferences[0].SID
+	room.mu.Lock()
+	room.SID = sid
+	room.mu.Unlock()
+
+	return room, sid, nil
+}
+
+// updateParticipant changes a participant's settings on SignalWire
+func (m *ConferenceManager) updateParticipant(ctx context.Context, name, callSID string, formData url.Values) error {
+	_, sid, err := m.resolve(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/Accounts/%s/Conferences/%s/Participants/%s.json", m.initiator.projectID, sid, callSID)
+	resp, err := m.initiator.doRequest(ctx, "POST", path, formData)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// ============================================
+// STATUS CALLBACKS
+// ============================================
+
+// HandleConferenceEvent tracks participants from conference status callbacks
+func (m *ConferenceManager) HandleConferenceEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.FormValue("FriendlyName")
+	if name == "" {
+		http.Error(w, "Missing FriendlyName", http.StatusBadRequest)
+		return
+	}
+
+	event := ConferenceEvent{
+		Type:          ConferenceEventType(r.FormValue("StatusCallbackEvent")),
+		Conference:    name,
+		ConferenceSID: r.FormValue("ConferenceSid"),
+		CallSID:       r.FormValue("CallSid"),
+		Timestamp:     time.Now(),
+	}
+
+	// Late leave/end events for a conference no longer tracked don't
+	// bring it back
+	if (event.Type == ParticipantLeft || event.Type == ConferenceEnded) && m.Get(name) == nil {
+		m.emit(event)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Conferences started elsewhere (e.g. warm transfers) are tracked too
+	room := m.Create(name)
+	now := time.Now()
+
+	room.mu.Lock()
+	if event.ConferenceSID != "" {
+		room.SID = event.ConferenceSID
+	}
+
+	switch event.Type {
+	case ConferenceStarted:
+		room.Status = ConferenceInProgress
+		room.StartedAt = &now
+
+	case ConferenceEnded:
+		room.Status = ConferenceCompleted
+		room.EndedAt = &now
+
+	case ParticipantJoined:
+		p, ok := room.participants[event.CallSID]
+		if !ok {
+			p = &ConferenceParticipant{CallSID: event.CallSID, AddedAt: now}
+			room.participants[event.CallSID] = p
+		}
+		p.Joined = true
+		p.JoinedAt = &now
+		p.Muted = r.FormValue("Muted") == "true"
+
+	case ParticipantLeft:
+		delete(room.participants, event.CallSID)
+
+	case ParticipantMuted, ParticipantUnmuted:
+		if p, ok := room.participants[event.CallSID]; ok {
+			p.Muted = event.Type == ParticipantMuted
+		}
+
+	case ParticipantDeafened, ParticipantUndeafed:
+		if p, ok := room.participants[event.CallSID]; ok {
+			p.Deaf = event.Type == ParticipantDeafened
+		}
+	}
+
+	if p, ok := room.participants[event.CallSID]; ok {
+		event.Label = p.Label
+	}
+	room.mu.Unlock()
+
+	// Ended conferences are reported once, then dropped
+	if event.Type == ConferenceEnded {
+		m.forget(name, room)
+	}
+
+	log.Printf("[ConferenceManager] %s: %s (call: %s)", name, event.Type, event.CallSID)
+	m.emit(event)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// emit delivers an event to all listeners
+func (m *ConferenceManager) emit(event ConferenceEvent) {
+	m.mu.RLock()
+	listeners := append([]func(ConferenceEvent){}, m.listeners...)
+	m.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// RegisterRoutes registers the conference status callback route
+func (m *ConferenceManager) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc(ConferenceEventsPath, m.HandleConferenceEvent)
+}
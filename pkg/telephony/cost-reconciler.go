@@ -0,0 +1,268 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/birddigital/signalwire-telephony/pkg/signalwire"
+)
+
+// ============================================
+// COST RECONCILIATION
+// Filling in billed price and duration from SignalWire's records
+// ============================================
+
+// CallRecordSource lists billed calls and messages
+// (satisfied by *signalwire.Client)
+type CallRecordSource interface {
+	ListCalls(filter signalwire.ListFilter) ([]signalwire.Call, error)
+	ListMessages(filter signalwire.ListFilter) ([]signalwire.Message, error)
+}
+
+// ReconcileConfig controls how often and how far back calls are reconciled
+type ReconcileConfig struct {
+	Interval          time.Duration // Time between runs (default 1h)
+	Lookback          time.Duration // How far back each run looks (default 48h)
+	CostTolerance     float64       // Cost difference in USD flagged as a discrepancy (default 0.01)
+	DurationTolerance int           // Duration difference in seconds flagged as a discrepancy (default 5)
+}
+
+// CostDiscrepancy is a stored value that disagreed with SignalWire's record
+type CostDiscrepancy struct {
+	CallSID   string    `json:"call_sid"`
+	SessionID uuid.UUID `json:"session_id"`
+	Field     string    `json:"field"` // "cost_usd" or "duration_seconds"
+	Stored    float64   `json:"stored"`
+	Actual    float64   `json:"actual"`
+}
+
+// ReconcileReport summarizes one reconciliation run
+type ReconcileReport struct {
+	Since          time.Time         `json:"since"`
+	Until          time.Time         `json:"until"`
+	Calls          int               `json:"calls"`     // Completed calls listed
+	Updated        int               `json:"updated"`   // Sessions whose cost or duration changed
+	Unmatched      int               `json:"unmatched"` // Calls with no stored session
+	Unrated        int               `json:"unrated"`   // Calls not priced yet; picked up by a later run
+	CallCostUSD    float64           `json:"call_cost_usd"`
+	Messages       int               `json:"messages"`
+	MessageCostUSD float64           `json:"message_cost_usd"`
+	Discrepancies  []CostDiscrepancy `json:"discrepancies,omitempty"`
+}
+
+// CostReconciler periodically replaces estimated or missing call costs and
+// durations with the billed values from SignalWire. Message costs are
+// totalled and passed to OnMessageCost listeners, since messages are not
+// stored as sessions.
+type CostReconciler struct {
+	source    CallRecordSource
+	initiator *CallInitiator
+	config    ReconcileConfig
+
+	discrepancyListeners []func(CostDiscrepancy)
+	messageListeners     []func(signalwire.Message, float64)
+	last                 *ReconcileReport
+	mu                   sync.Mutex
+}
+
+// NewCostReconciler creates a reconciler updating the initiator's sessions
+func NewCostReconciler(source CallRecordSource, initiator *CallInitiator, config ReconcileConfig) *CostReconciler {
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = 48 * time.Hour
+	}
+	if config.CostTolerance <= 0 {
+		config.CostTolerance = 0.01
+	}
+	if config.DurationTolerance <= 0 {
+		config.DurationTolerance = 5
+	}
+	return &CostReconciler{
+		source:    source,
+		initiator: initiator,
+		config:    config,
+	}
+}
+
+// OnDiscrepancy registers a listener for stored values that disagreed with
+// the billed record
+func (r *CostReconciler) OnDiscrepancy(listener func(CostDiscrepancy)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.discrepancyListeners = append(r.discrepancyListeners, listener)
+}
+
+// OnMessageCost registers a listener for each rated message and its cost
+func (r *CostReconciler) OnMessageCost(listener func(signalwire.Message, float64)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messageListeners = append(r.messageListeners, listener)
+}
+
+// LastReport returns the report of the most recent run, or nil
+func (r *CostReconciler) LastReport() *ReconcileReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// Run reconciles immediately and then every Interval until ctx is done
+func (r *CostReconciler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		if _, err := r.Reconcile(ctx, now.Add(-r.config.Lookback), now); err != nil {
+			log.Printf("[CostReconciler] Reconciliation failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile updates sessions for calls completed between since and until
+// and totals message costs over the same range
+func (r *CostReconciler) Reconcile(ctx context.Context, since, until time.Time) (*ReconcileReport, error) {
+	report := &ReconcileReport{Since: since, Until: until}
+
+	calls, err := r.source.ListCalls(signalwire.ListFilter{Status: "completed", Since: since, Until: until})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calls: %w", err)
+	}
+
+	for _, call := range calls {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report.Calls++
+
+		cost, rated := signalwire.ParsePrice(call.Price)
+		if !rated {
+			report.Unrated++
+			continue
+		}
+		report.CallCostUSD += cost
+		duration, _ := strconv.Atoi(call.Duration)
+
+		if err := r.reconcileCall(ctx, call.SID, cost, duration, call.StirStatus, report); err != nil {
+			log.Printf("[CostReconciler] Failed to update %s: %v", call.SID, err)
+		}
+	}
+
+	messages, err := r.source.ListMessages(signalwire.ListFilter{Since: since, Until: until})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	r.mu.Lock()
+	messageListeners := append([]func(signalwire.Message, float64){}, r.messageListeners...)
+	r.mu.Unlock()
+
+	for _, message := range messages {
+		cost, rated := signalwire.ParsePrice(message.Price)
+		if !rated {
+			continue
+		}
+		report.Messages++
+		report.MessageCostUSD += cost
+		for _, listener := range messageListeners {
+			listener(message, cost)
+		}
+	}
+
+	r.mu.Lock()
+	r.last = report
+	discrepancyListeners := append([]func(CostDiscrepancy){}, r.discrepancyListeners...)
+	r.mu.Unlock()
+
+	for _, discrepancy := range report.Discrepancies {
+		for _, listener := range discrepancyListeners {
+			listener(discrepancy)
+		}
+	}
+
+	log.Printf("[CostReconciler] Reconciled %d calls ($%.2f, %d updated, %d unmatched, %d discrepancies) and %d messages ($%.2f)",
+		report.Calls, report.CallCostUSD, report.Updated, report.Unmatched, len(report.Discrepancies),
+		report.Messages, report.MessageCostUSD)
+	return report, nil
+}
+
+// reconcileCall writes the billed cost and duration to a call's session,
+// and its attestation if no webhook reported one
+func (r *CostReconciler) reconcileCall(ctx context.Context, callSID string, cost float64, duration int, stirStatus string, report *ReconcileReport) error {
+	// Go through the initiator so a tracked session isn't overwritten later
+	// by its stale in-memory copy
+	session, err := r.initiator.lookupSession(ctx, callSID)
+	if err != nil {
+		report.Unmatched++
+		return nil
+	}
+
+	// Discrepancies are collected per attempt, since a conflicting update
+	// re-applies the change to a fresh copy
+	var discrepancies []CostDiscrepancy
+	_, err = r.initiator.updateSession(ctx, session, func(session *CallSession) error {
+		discrepancies = nil
+		changed := false
+		if session.CostUSD != cost {
+			if session.CostUSD > 0 && math.Abs(session.CostUSD-cost) > r.config.CostTolerance {
+				discrepancies = append(discrepancies, CostDiscrepancy{
+					CallSID: callSID, SessionID: session.ID, Field: "cost_usd",
+					Stored: session.CostUSD, Actual: cost,
+				})
+			}
+			session.CostUSD = cost
+			changed = true
+		}
+		if duration > 0 && session.DurationSeconds != duration {
+			if session.DurationSeconds > 0 && absInt(session.DurationSeconds-duration) > r.config.DurationTolerance {
+				discrepancies = append(discrepancies, CostDiscrepancy{
+					CallSID: callSID, SessionID: session.ID, Field: "duration_seconds",
+					Stored: float64(session.DurationSeconds), Actual: float64(duration),
+				})
+			}
+			session.DurationSeconds = duration
+			changed = true
+		}
+		if stirStatus != "" && session.Verstat == "" {
+			session.setAttestation(ParseVerstat(stirStatus), stirStatus)
+			changed = true
+		}
+		if !changed {
+			return errNoChange
+		}
+		return nil
+	})
+	report.Discrepancies = append(report.Discrepancies, discrepancies...)
+	if errors.Is(err, errNoChange) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	report.Updated++
+	return nil
+}
+
+// absInt returns the absolute value of n
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
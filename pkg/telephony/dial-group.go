@@ -0,0 +1,207 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ============================================
+// DIAL GROUPS
+// Ringing several targets at once; the first to answer is bridged
+// ============================================
+
+// Webhook routes for dial groups; SignalWire reaches them through the
+// initiator's public base URL
+const (
+	DialGroupAnswerPath = "/api/telephony/calls/dial-group/answer"
+	DialGroupResultPath = "/api/telephony/calls/dial-group/result"
+)
+
+// maxDialGroupTargets is the most legs a single <Dial> can ring
+const maxDialGroupTargets = 10
+
+// DialGroup rings several numbers or SIP endpoints in parallel, such as an
+// on-call rotation
+type DialGroup struct {
+	Targets     []string // E.164 numbers or sip: URIs
+	CallerID    string   // Caller ID for the legs (defaults to our number on the call)
+	RingTimeout int      // Seconds to ring before giving up (default 30)
+	FallbackURL string   // Where to send the caller if nobody answers (defaults to the call's AnswerURL)
+}
+
+// DialGroupWinner is the leg that answered first
+type DialGroupWinner struct {
+	Target  string `json:"target"`
+	CallSID string `json:"call_sid"`
+}
+
+// DialGroup rings every target in the group from a live call. The first
+// leg to answer is bridged to the caller and the others are cancelled; the
+// winner is recorded on the session (see CallSession.DialGroupWinner).
+// Requires SetPublicBaseURL.
+func (ci *CallInitiator) DialGroup(ctx context.Context, callSID string, group DialGroup) error {
+	if len(group.Targets) == 0 {
+		return fmt.Errorf("dial group has no targets")
+	}
+	if len(group.Targets) > maxDialGroupTargets {
+		return fmt.Errorf("dial group has %d targets, at most %d allowed", len(group.Targets), maxDialGroupTargets)
+	}
+	for _, target := range group.Targets {
+		if !isSIPURI(target) && !isValidE164(target) {
+			return fmt.Errorf("dial group target %q must be E.164 or a sip: URI", target)
+		}
+	}
+
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return err
+	}
+
+	session.mu.RLock()
+	callerID := session.FromNumber
+	if session.Direction == DirectionInbound {
+		callerID = session.ToNumber
+	}
+	fallbackURL := group.FallbackURL
+	if fallbackURL == "" && session.Config != nil {
+		fallbackURL = session.Config.AnswerURL
+	}
+	session.mu.RUnlock()
+
+	if group.CallerID != "" {
+		callerID = group.CallerID
+	}
+	if group.RingTimeout <= 0 {
+		group.RingTimeout = 30
+	}
+
+	resultQuery := url.Values{}
+	if fallbackURL != "" {
+		resultQuery.Set("fallback", fallbackURL)
+	}
+	action, err := ci.webhookURL(DialGroupResultPath, resultQuery)
+	if err != nil {
+		return err
+	}
+	dial := Dial{
+		CallerID: callerID,
+		Timeout:  group.RingTimeout,
+		Action:   action,
+	}
+	for _, target := range group.Targets {
+		// Runs on the answering leg, telling us which target won
+		answerURL, err := ci.webhookURL(DialGroupAnswerPath, url.Values{
+			"call_sid": {callSID},
+			"target":   {target},
+		})
+		if err != nil {
+			return err
+		}
+		if isSIPURI(target) {
+			dial.Sips = append(dial.Sips, Sip{URI: target, URL: answerURL})
+		} else {
+			dial.Numbers = append(dial.Numbers, Number{Number: target, URL: answerURL})
+		}
+	}
+
+	if err := ci.updateLiveCall(ctx, callSID, NewTwiML().Dial(dial)); err != nil {
+		return fmt.Errorf("failed to dial group: %w", err)
+	}
+
+	_, err = ci.updateSession(ctx, session, func(session *CallSession) error {
+		session.Metadata.Set("dial_group_targets", group.Targets)
+		session.Metadata.Delete("dial_group_winner")
+		session.Metadata.Delete("dial_group_winner_call_sid")
+		return nil
+	})
+	if err != nil {
+		log.Printf("[CallInitiator] Failed to record dial group on session: %v", err)
+	}
+
+	log.Printf("[CallInitiator] Ringing %d targets for call %s", len(group.Targets), callSID)
+	return nil
+}
+
+// recordDialGroupWinner stores the answering leg on the caller's session
+func (ci *CallInitiator) recordDialGroupWinner(ctx context.Context, callSID string, winner DialGroupWinner) error {
+	_, err := ci.updateCall(ctx, callSID, func(session *CallSession) error {
+		session.Metadata.Set("dial_group_winner", winner.Target)
+		session.Metadata.Set("dial_group_winner_call_sid", winner.CallSID)
+		return nil
+	})
+	return err
+}
+
+// DialGroupWinner returns the leg that answered the call's last dial group,
+// if one has
+func (s *CallSession) DialGroupWinner() (DialGroupWinner, bool) {
+	s.mu.RLock()
+	target, _ := s.Metadata.GetString("dial_group_winner")
+	callSID, _ := s.Metadata.GetString("dial_group_winner_call_sid")
+	s.mu.RUnlock()
+	if target == "" {
+		return DialGroupWinner{}, false
+	}
+	return DialGroupWinner{Target: target, CallSID: callSID}, true
+}
+
+// HandleDialGroupAnswer runs on the first leg of a dial group to answer:
+// it records the winner and lets SignalWire bridge the leg
+func (h *CallHandlers) HandleDialGroupAnswer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	callSID := r.URL.Query().Get("call_sid")
+	winner := DialGroupWinner{
+		Target:  r.URL.Query().Get("target"),
+		CallSID: r.FormValue("CallSid"),
+	}
+	if callSID == "" || winner.Target == "" {
+		http.Error(w, "Missing call_sid or target", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("[CallHandlers] Dial group for %s answered by %s (%s)", callSID, winner.Target, winner.CallSID)
+
+	if err := h.callInitiator.recordDialGroupWinner(r.Context(), callSID, winner); err != nil {
+		log.Printf("[CallHandlers] Failed to record dial group winner for %s: %v", callSID, err)
+		h.callInitiator.Errors().Record("handlers", callSID, err)
+	}
+
+	// An empty response connects the leg
+	writeTwiML(w, NewTwiML())
+}
+
+// HandleDialGroupResult is the dial group's <Dial> action: once the bridged
+// call ends the caller hangs up too, and if nobody answered the caller goes
+// to the fallback flow
+func (h *CallHandlers) HandleDialGroupResult(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	callSID := r.FormValue("CallSid")
+	status := r.FormValue("DialCallStatus")
+	duration, _ := strconv.Atoi(r.FormValue("DialCallDuration"))
+	log.Printf("[CallHandlers] Dial group for %s ended: %s (%ds)", callSID, status, duration)
+
+	if status == "completed" || status == "answered" {
+		writeTwiML(w, NewTwiML().Hangup())
+		return
+	}
+
+	twiml := NewTwiML()
+	if fallbackURL := r.URL.Query().Get("fallback"); fallbackURL != "" {
+		twiml.Redirect(fallbackURL)
+	} else {
+		twiml.Say("We're sorry, nobody is available to take your call. Goodbye.", "").Hangup()
+	}
+	writeTwiML(w, twiml)
+}
@@ -0,0 +1,189 @@
+package telephony
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================
+// CALL DISPOSITIONS
+// Per-agency wrap-up codes recorded on call sessions
+// ============================================
+
+// ErrUnknownDisposition is returned for codes not in the agency's list
+var ErrUnknownDisposition = errors.New("unknown disposition code")
+
+// DispositionsPath is where hosts conventionally mount HandleDispositions,
+// which RegisterRoutes leaves out
+const DispositionsPath = "/api/telephony/calls/disposition"
+
+// Disposition is a wrap-up code describing how a call went
+type Disposition struct {
+	Code  string `json:"code"`  // Stored on the session, e.g. "quoted"
+	Label string `json:"label"` // Shown to agents, e.g. "Quote given"
+}
+
+// DefaultDispositions returns the codes used by agencies without their own
+func DefaultDispositions() []Disposition {
+	return []Disposition{
+		{Code: "quoted", Label: "Quote given"},
+		{Code: "sold", Label: "Sold"},
+		{Code: "callback_requested", Label: "Callback requested"},
+		{Code: "not_interested", Label: "Not interested"},
+		{Code: "wrong_number", Label: "Wrong number"},
+		{Code: "do_not_call", Label: "Do not call"},
+		{Code: "voicemail_left", Label: "Voicemail left"},
+		{Code: "no_contact", Label: "No contact"},
+	}
+}
+
+// Dispositions holds the disposition codes each agency may use
+type Dispositions struct {
+	defaults []Disposition
+	agencies map[uuid.UUID][]Disposition
+	mu       sync.RWMutex
+}
+
+// NewDispositions creates a code list with defaults for every agency
+func NewDispositions(defaults []Disposition) *Dispositions {
+	return &Dispositions{
+		defaults: append([]Disposition(nil), defaults...),
+		agencies: make(map[uuid.UUID][]Disposition),
+	}
+}
+
+// SetAgencyCodes replaces an agency's codes; nil restores the defaults
+func (d *Dispositions) SetAgencyCodes(agencyID uuid.UUID, codes []Disposition) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if codes == nil {
+		delete(d.agencies, agencyID)
+		return
+	}
+	d.agencies[agencyID] = append([]Disposition(nil), codes...)
+}
+
+// Codes returns the codes an agency may use
+func (d *Dispositions) Codes(agencyID uuid.UUID) []Disposition {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	codes, ok := d.agencies[agencyID]
+	if !ok {
+		codes = d.defaults
+	}
+	return append([]Disposition(nil), codes...)
+}
+
+// Lookup finds a code in an agency's list
+func (d *Dispositions) Lookup(agencyID uuid.UUID, code string) (Disposition, bool) {
+	for _, disposition := range d.Codes(agencyID) {
+		if disposition.Code == code {
+			return disposition, true
+		}
+	}
+	return Disposition{}, false
+}
+
+// ============================================
+// INITIATOR INTEGRATION
+// ============================================
+
+// SetDispositions replaces the disposition code lists
+func (ci *CallInitiator) SetDispositions(dispositions *Dispositions) {
+	ci.dispositions = dispositions
+}
+
+// Dispositions returns the disposition code lists
+func (ci *CallInitiator) Dispositions() *Dispositions {
+	return ci.dispositions
+}
+
+// SetDisposition records a wrap-up code and notes on a call, replacing any
+// earlier disposition. The code must be in the call's agency's list.
+func (ci *CallInitiator) SetDisposition(ctx context.Context, callSID, code, notes string) error {
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return err
+	}
+
+	_, err = ci.updateSession(ctx, session, func(session *CallSession) error {
+		if _, ok := ci.dispositions.Lookup(session.AgencyID, code); !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownDisposition, code)
+		}
+
+		now := time.Now()
+		session.Disposition = code
+		session.DispositionNotes = notes
+		session.DispositionAt = &now
+		return nil
+	})
+	if errors.Is(err, ErrUnknownDisposition) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save disposition: %w", err)
+	}
+
+	log.Printf("[CallInitiator] Call %s dispositioned: %s", callSID, code)
+	return nil
+}
+
+// ============================================
+// HTTP HANDLERS
+// ============================================
+
+// HandleDispositions lists the codes for ?agency_id= (GET) or records a
+// disposition from a JSON body {call_sid, code, notes} (POST).
+//
+// It can rewrite any call's disposition and has no authentication of its
+// own, so it is not part of RegisterRoutes. Mount it behind your auth
+// middleware:
+//
+//	mux.Handle(telephony.DispositionsPath, requireAgent(http.HandlerFunc(handlers.HandleDispositions)))
+func (h *CallHandlers) HandleDispositions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		agencyID, err := uuid.Parse(r.URL.Query().Get("agency_id"))
+		if err != nil {
+			agencyID = uuid.Nil
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"dispositions": h.callInitiator.Dispositions().Codes(agencyID),
+		})
+
+	case http.MethodPost:
+		var body struct {
+			CallSID string `json:"call_sid"`
+			Code    string `json:"code"`
+			Notes   string `json:"notes"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.CallSID == "" || body.Code == "" {
+			http.Error(w, "Missing call_sid or code", http.StatusBadRequest)
+			return
+		}
+
+		err := h.callInitiator.SetDisposition(r.Context(), body.CallSID, body.Code, body.Notes)
+		switch {
+		case errors.Is(err, ErrUnknownDisposition):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
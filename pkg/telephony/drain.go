@@ -0,0 +1,158 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// ============================================
+// GRACEFUL SHUTDOWN
+// Draining live calls and bridge sessions before exit
+// ============================================
+
+// ErrDraining is returned for new calls and sessions once draining starts
+var ErrDraining = errors.New("draining: not accepting new calls")
+
+// drainPollInterval is how often drains check for remaining work
+const drainPollInterval = 500 * time.Millisecond
+
+// IsDraining reports whether the initiator has stopped accepting calls
+func (ci *CallInitiator) IsDraining() bool {
+	return ci.draining.Load()
+}
+
+// Drain stops new outbound calls, waits for live calls to end and flushes
+// session state to the store. Calls still live when ctx is done are hung
+// up. Returns an error naming how many calls had to be forced.
+func (ci *CallInitiator) Drain(ctx context.Context) error {
+	ci.draining.Store(true)
+	log.Printf("[CallInitiator] Draining %d live calls", len(ci.liveCalls()))
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	var forced int
+wait:
+	for {
+		live := ci.liveCalls()
+		if len(live) == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			forced = len(live)
+			ci.forceHangup(live)
+			break wait
+		case <-ticker.C:
+		}
+	}
+
+	// Persist the final state of every tracked session
+	flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, session := range ci.ActiveCalls() {
+		_, err := ci.updateSession(flushCtx, session, func(*CallSession) error { return nil })
+		if err != nil {
+			log.Printf("[CallInitiator] Failed to flush session %s: %v", session.ID, err)
+		}
+	}
+	ci.CleanupCompletedCalls()
+
+	if forced > 0 {
+		return fmt.Errorf("drain deadline reached: hung up %d live calls", forced)
+	}
+	log.Printf("[CallInitiator] Drained")
+	return nil
+}
+
+// liveCalls returns the call SIDs of tracked calls not yet in a terminal state
+func (ci *CallInitiator) liveCalls() []string {
+	var live []string
+	for _, session := range ci.ActiveCalls() {
+		session.mu.RLock()
+		if !session.State.IsTerminal() {
+			live = append(live, session.SignalWireCallSID)
+		}
+		session.mu.RUnlock()
+	}
+	return live
+}
+
+// forceHangup ends calls that outlived a drain
+func (ci *CallInitiator) forceHangup(callSIDs []string) {
+	for _, callSID := range callSIDs {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := ci.HangupCall(ctx, callSID); err != nil {
+			log.Printf("[CallInitiator] Failed to hang up %s during drain: %v", callSID, err)
+			ci.errorLog.Record("drain", callSID, err)
+		}
+		cancel()
+	}
+}
+
+// ============================================
+// BRIDGE DRAIN
+// ============================================
+
+// IsDraining reports whether the bridge has stopped accepting sessions
+func (bridge *AudioStreamBridge) IsDraining() bool {
+	return bridge.draining.Load()
+}
+
+// Drain stops new sessions, waits for existing ones to close, then closes
+// the bridge. Sessions still open when ctx is done are closed forcibly.
+func (bridge *AudioStreamBridge) Drain(ctx context.Context) error {
+	bridge.draining.Store(true)
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	var err error
+	for len(bridge.ListSessionIDs()) > 0 {
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("drain deadline reached: closing %d bridge sessions", len(bridge.ListSessionIDs()))
+		case <-ticker.C:
+			continue
+		}
+		break
+	}
+
+	bridge.Close()
+	return err
+}
+
+// ============================================
+// HANDLER DRAIN
+// ============================================
+
+// Drain stops accepting calls, drains the initiator and the audio bridge,
+// then closes the WebSocket server. New inbound calls get 503 meanwhile, so
+// SignalWire fails over to the number's fallback URL; outbound calls placed
+// before the drain still connect.
+func (h *CallHandlers) Drain(ctx context.Context) error {
+	h.draining.Store(true)
+
+	err := h.callInitiator.Drain(ctx)
+	if bridgeErr := h.streamBridge.Drain(ctx); err == nil {
+		err = bridgeErr
+	}
+	h.audioBridge.Close()
+	return err
+}
+
+// rejectWhileDraining answers 503 once draining starts, reporting whether
+// the request was rejected
+func (h *CallHandlers) rejectWhileDraining(w http.ResponseWriter) bool {
+	if !h.draining.Load() {
+		return false
+	}
+	w.Header().Set("Retry-After", "30")
+	http.Error(w, ErrDraining.Error(), http.StatusServiceUnavailable)
+	return true
+}
@@ -0,0 +1,203 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+)
+
+// ============================================
+// DTMF DETECTION
+// Goertzel detection of in-band touch tones in caller audio
+// ============================================
+
+const (
+	dtmfBlockDuration = 12750 * time.Microsecond // 102 samples at 8kHz
+	dtmfMinLevel      = -36.0                    // Per-tone level (dBFS) a digit must reach
+	dtmfMaxTwist      = 6.3                      // Row/column power ratio allowed either way (8dB)
+	dtmfRelativePeak  = 6.3                      // Margin over the other rows/columns (8dB)
+	dtmfToneFraction  = 0.6                      // Share of the block's energy in the two tones
+	dtmfHitBlocks     = 2                        // Consecutive blocks to register a digit
+	dtmfMissBlocks    = 2                        // Consecutive blocks without it to end a digit
+)
+
+var (
+	dtmfRows = [4]float64{697, 770, 852, 941}
+	dtmfCols = [4]float64{1209, 1336, 1477, 1633}
+	dtmfKeys = [4][4]rune{
+		{'1', '2', '3', 'A'},
+		{'4', '5', '6', 'B'},
+		{'7', '8', '9', 'C'},
+		{'*', '0', '#', 'D'},
+	}
+)
+
+// DTMFDigit is a touch tone detected in audio
+type DTMFDigit struct {
+	Digit  rune          // '0'-'9', '*', '#', 'A'-'D'
+	Offset time.Duration // Stream position where the tone began
+}
+
+// DTMFDetector finds DTMF tones in a stream of mulaw or PCM audio with the
+// Goertzel algorithm, reporting each key press once. It is safe for
+// concurrent use.
+type DTMFDetector struct {
+	mulaw      bool
+	sampleRate int
+	blockSize  int
+	rowCoeffs  [4]float64
+	colCoeffs  [4]float64
+	minPower   float64
+
+	pending   []float64 // Samples short of a full block
+	blocks    int64     // Blocks analyzed so far
+	current   rune      // Digit being held, 0 when none
+	candidate rune      // Digit seen in the latest blocks, not yet registered
+	hits      int       // Consecutive blocks showing the candidate
+	misses    int       // Consecutive blocks without the current digit
+	mu        sync.Mutex
+}
+
+// NewDTMFDetector creates a detector for audio in format (mulaw or 16-bit
+// PCM, mono)
+func NewDTMFDetector(format AudioFormat) *DTMFDetector {
+	blockSize := int(int64(format.SampleRate) * int64(dtmfBlockDuration) / int64(time.Second))
+	d := &DTMFDetector{
+		mulaw:      format.Encoding == AudioFormatMulaw.Encoding,
+		sampleRate: format.SampleRate,
+		blockSize:  blockSize,
+	}
+	for i := range dtmfRows {
+		d.rowCoeffs[i] = goertzelCoeff(dtmfRows[i], format.SampleRate)
+		d.colCoeffs[i] = goertzelCoeff(dtmfCols[i], format.SampleRate)
+	}
+
+	// Goertzel power of a tone at the minimum level: (A·N/2)²
+	amplitude := 32768 * math.Pow(10, dtmfMinLevel/20)
+	d.minPower = math.Pow(amplitude*float64(blockSize)/2, 2)
+	return d
+}
+
+// Process analyzes the next chunk of audio and returns the key presses that
+// began in it
+func (d *DTMFDetector) Process(audio []byte) []DTMFDigit {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.mulaw {
+		for _, b := range audio {
+			d.pending = append(d.pending, float64(mulawToLinear(b)))
+		}
+	} else {
+		for i := 0; i+1 < len(audio); i += 2 {
+			d.pending = append(d.pending, float64(int16(binary.LittleEndian.Uint16(audio[i:]))))
+		}
+	}
+
+	var digits []DTMFDigit
+	for len(d.pending) >= d.blockSize {
+		if digit, ok := d.advance(d.classify(d.pending[:d.blockSize])); ok {
+			digits = append(digits, digit)
+		}
+		d.pending = d.pending[d.blockSize:]
+	}
+	d.pending = append([]float64(nil), d.pending...)
+	return digits
+}
+
+// classify returns the key a block holds, or 0
+func (d *DTMFDetector) classify(block []float64) rune {
+	var rows, cols [4]float64
+	for i := range rows {
+		rows[i] = goertzelPower(block, d.rowCoeffs[i])
+		cols[i] = goertzelPower(block, d.colCoeffs[i])
+	}
+	row, col := strongest(rows), strongest(cols)
+	rowPower, colPower := rows[row], cols[col]
+
+	if rowPower < d.minPower || colPower < d.minPower {
+		return 0
+	}
+	if rowPower > colPower*dtmfMaxTwist || colPower > rowPower*dtmfMaxTwist {
+		return 0
+	}
+	for i := range rows {
+		if (i != row && rows[i]*dtmfRelativePeak > rowPower) || (i != col && cols[i]*dtmfRelativePeak > colPower) {
+			return 0
+		}
+	}
+
+	// A pure tone's Goertzel power is N/2 times its energy in the block;
+	// most of the block must be the two tones, which rules out speech
+	energy := 0.0
+	for _, s := range block {
+		energy += s * s
+	}
+	if rowPower+colPower < dtmfToneFraction*energy*float64(len(block))/2 {
+		return 0
+	}
+	return dtmfKeys[row][col]
+}
+
+// advance debounces per-block results into key presses
+func (d *DTMFDetector) advance(key rune) (DTMFDigit, bool) {
+	block := d.blocks
+	d.blocks++
+
+	if d.current != 0 {
+		if key == d.current {
+			d.misses = 0
+			return DTMFDigit{}, false
+		}
+		if d.misses++; d.misses < dtmfMissBlocks {
+			return DTMFDigit{}, false
+		}
+		d.current, d.misses, d.hits = 0, 0, 0
+	}
+
+	if key == 0 {
+		d.hits = 0
+		return DTMFDigit{}, false
+	}
+	if key != d.candidate {
+		d.candidate, d.hits = key, 0
+	}
+	d.hits++
+	if d.hits < dtmfHitBlocks {
+		return DTMFDigit{}, false
+	}
+
+	d.current, d.hits = key, 0
+	start := block - dtmfHitBlocks + 1
+	return DTMFDigit{
+		Digit:  key,
+		Offset: time.Duration(start) * time.Duration(d.blockSize) * time.Second / time.Duration(d.sampleRate),
+	}, true
+}
+
+// goertzelCoeff is the Goertzel recurrence coefficient for a frequency
+func goertzelCoeff(freq float64, sampleRate int) float64 {
+	return 2 * math.Cos(2*math.Pi*freq/float64(sampleRate))
+}
+
+// goertzelPower is the power of a block at the coefficient's frequency
+func goertzelPower(block []float64, coeff float64) float64 {
+	var s1, s2 float64
+	for _, x := range block {
+		s0 := x + coeff*s1 - s2
+		s2, s1 = s1, s0
+	}
+	return s1*s1 + s2*s2 - coeff*s1*s2
+}
+
+// strongest returns the index of the largest power
+func strongest(powers [4]float64) int {
+	best := 0
+	for i := 1; i < len(powers); i++ {
+		if powers[i] > powers[best] {
+			best = i
+		}
+	}
+	return best
+}
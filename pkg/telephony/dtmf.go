@@ -0,0 +1,179 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// ============================================
+// DTMF
+// Sending touch tones to live calls (IVR navigation)
+// ============================================
+
+const (
+	dtmfToneDuration = 100 * time.Millisecond // Length of each tone
+	dtmfDefaultPace  = 100 * time.Millisecond // Silence between tones
+	dtmfFrameSize    = 160                    // 20ms of 8kHz mulaw
+	dtmfAmplitude    = 0.3                    // Per-tone amplitude (of full scale)
+)
+
+// dtmfFrequencies maps each key to its row and column frequencies (Hz)
+var dtmfFrequencies = map[rune][2]float64{
+	'1': {697, 1209}, '2': {697, 1336}, '3': {697, 1477}, 'A': {697, 1633},
+	'4': {770, 1209}, '5': {770, 1336}, '6': {770, 1477}, 'B': {770, 1633},
+	'7': {852, 1209}, '8': {852, 1336}, '9': {852, 1477}, 'C': {852, 1633},
+	'*': {941, 1209}, '0': {941, 1336}, '#': {941, 1477}, 'D': {941, 1633},
+}
+
+// validateDigits checks a digit string; "w" (0.5s) and "W" (1s) are waits
+func validateDigits(digits string) error {
+	if digits == "" {
+		return fmt.Errorf("no digits to send")
+	}
+	for _, d := range digits {
+		if _, ok := dtmfFrequencies[d]; !ok && d != 'w' && d != 'W' {
+			return fmt.Errorf("invalid DTMF digit: %q", d)
+		}
+	}
+	return nil
+}
+
+// SendDigits sends DTMF digits to a live call, waiting pace between digits.
+// Calls with an active media stream on the audio bridge get in-band tones;
+// otherwise the digits are played through the REST API.
+func (ci *CallInitiator) SendDigits(ctx context.Context, callSID, digits string, pace time.Duration) error {
+	if ci.audioBridge != nil {
+		if session := ci.audioBridge.GetSessionByCallSID(callSID); session != nil && session.IsActive() {
+			return ci.SendDigitsInBand(ctx, callSID, digits, pace)
+		}
+	}
+	return ci.SendDigitsREST(ctx, callSID, digits, pace)
+}
+
+// SendDigitsREST plays DTMF digits on a live call by updating its
+// instructions. Pace is rounded to the 0.5s granularity of <Play digits>.
+// Forked media streams keep running; the call is then held open on a pause.
+func (ci *CallInitiator) SendDigitsREST(ctx context.Context, callSID, digits string, pace time.Duration) error {
+	if err := validateDigits(digits); err != nil {
+		return err
+	}
+
+	waits := strings.Repeat("w", int(math.Round(float64(pace)/float64(500*time.Millisecond))))
+
+	var sequence strings.Builder
+	for i, d := range digits {
+		if i > 0 {
+			sequence.WriteString(waits)
+		}
+		sequence.WriteRune(d)
+	}
+
+	holdSeconds := 3600
+	if session, err := ci.lookupSession(ctx, callSID); err == nil && session.Config != nil && session.Config.MaxDuration > 0 {
+		holdSeconds = session.Config.MaxDuration
+	}
+
+	twiml := NewTwiML().PlayDigits(sequence.String()).Pause(holdSeconds)
+	if err := ci.updateLiveCall(ctx, callSID, twiml); err != nil {
+		ci.errorLog.Record("dtmf", callSID, err)
+		return fmt.Errorf("failed to send digits: %w", err)
+	}
+
+	log.Printf("[CallInitiator] Sent digits %s to call %s", digits, callSID)
+	return nil
+}
+
+// SendDigitsInBand injects DTMF tones into the call's outbound audio through
+// the audio bridge, in real time. Pace defaults to 100ms of silence.
+func (ci *CallInitiator) SendDigitsInBand(ctx context.Context, callSID, digits string, pace time.Duration) error {
+	if err := validateDigits(digits); err != nil {
+		return err
+	}
+	if ci.audioBridge == nil {
+		return fmt.Errorf("no audio bridge configured")
+	}
+
+	session := ci.audioBridge.GetSessionByCallSID(callSID)
+	if session == nil {
+		return fmt.Errorf("no bridge session for call: %s", callSID)
+	}
+	out, err := ci.audioBridge.GetAIToPhoneChannel(session.SessionID)
+	if err != nil {
+		return err
+	}
+
+	if pace <= 0 {
+		pace = dtmfDefaultPace
+	}
+
+	var audio []byte
+	for i, d := range digits {
+		if i > 0 {
+			audio = append(audio, dtmfSilence(pace)...)
+		}
+		switch d {
+		case 'w':
+			audio = append(audio, dtmfSilence(500*time.Millisecond)...)
+		case 'W':
+			audio = append(audio, dtmfSilence(time.Second)...)
+		default:
+			audio = append(audio, dtmfTone(d, dtmfToneDuration)...)
+		}
+	}
+
+	// Feed 20ms frames at playback speed so the outbound path doesn't drop them
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+
+	for offset := 0; offset < len(audio); offset += dtmfFrameSize {
+		end := offset + dtmfFrameSize
+		if end > len(audio) {
+			end = len(audio)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-session.GetContext().Done():
+			return fmt.Errorf("bridge session closed")
+		case out <- audio[offset:end]:
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	log.Printf("[CallInitiator] Injected digits %s into call %s", digits, callSID)
+	return nil
+}
+
+// dtmfTone generates a dual-tone 8kHz mulaw signal for a key
+func dtmfTone(key rune, duration time.Duration) []byte {
+	freqs := dtmfFrequencies[key]
+	samples := int(duration.Seconds() * 8000)
+	converter := NewAudioConverter(8000, 8000, 1, 1)
+
+	tone := make([]byte, samples)
+	for i := range tone {
+		t := float64(i) / 8000
+		v := dtmfAmplitude * (math.Sin(2*math.Pi*freqs[0]*t) + math.Sin(2*math.Pi*freqs[1]*t))
+		tone[i] = converter.linearToMulaw(int16(v * math.MaxInt16))
+	}
+	return tone
+}
+
+// dtmfSilence generates 8kHz mulaw silence
+func dtmfSilence(duration time.Duration) []byte {
+	silence := make([]byte, int(duration.Seconds()*8000))
+	for i := range silence {
+		silence[i] = 0xFF // mulaw zero
+	}
+	return silence
+}
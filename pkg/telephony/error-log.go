@@ -0,0 +1,77 @@
+package telephony
+
+import (
+	"sync"
+	"time"
+)
+
+// ============================================
+// ERROR LOG
+// Bounded in-memory record of recent call errors
+// ============================================
+
+// ErrorEntry is a single recorded error
+type ErrorEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Component string    `json:"component"`
+	CallSID   string    `json:"call_sid,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// ErrorLog keeps the most recent errors in a ring buffer
+type ErrorLog struct {
+	entries []ErrorEntry
+	next    int
+	full    bool
+	mu      sync.RWMutex
+}
+
+// NewErrorLog creates an error log holding up to capacity entries
+func NewErrorLog(capacity int) *ErrorLog {
+	if capacity <= 0 {
+		capacity = 100
+	}
+	return &ErrorLog{entries: make([]ErrorEntry, capacity)}
+}
+
+// Record adds an error to the log
+func (l *ErrorLog) Record(component, callSID string, err error) {
+	if err == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = ErrorEntry{
+		Timestamp: time.Now(),
+		Component: component,
+		CallSID:   callSID,
+		Message:   err.Error(),
+	}
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Recent returns up to limit errors, newest first (limit <= 0 returns all)
+func (l *ErrorLog) Recent(limit int) []ErrorEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.entries)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+
+	recent := make([]ErrorEntry, 0, count)
+	for i := 1; i <= count; i++ {
+		idx := (l.next - i + len(l.entries)) % len(l.entries)
+		recent = append(recent, l.entries[idx])
+	}
+	return recent
+}
@@ -0,0 +1,175 @@
+package telephony
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================
+// EVENT BUS
+// Publish/subscribe for call and audio bridge events
+// ============================================
+
+// EventType identifies a bus event
+type EventType string
+
+const (
+	EventCallInitiated  EventType = "call.initiated"
+	EventCallAnswered   EventType = "call.answered"
+	EventCallCompleted  EventType = "call.completed"
+	EventCallTimeout    EventType = "call.timeout" // Hung up at CallConfig.MaxDuration
+	EventStreamStarted  EventType = "stream.started"
+	EventStreamStopped  EventType = "stream.stopped"
+	EventPacketDropped  EventType = "stream.packet_dropped"
+	EventSpeechStarted  EventType = "stream.speech_started" // VAD, see AudioStreamBridge.SetVAD
+	EventSpeechEnded    EventType = "stream.speech_ended"
+	EventDTMFDetected   EventType = "stream.dtmf"            // In-band digit, see AudioStreamBridge.SetDTMFDetection
+	EventRecordingSaved EventType = "stream.recording_saved" // See AudioStreamBridge.SetSessionRecording
+)
+
+// Event is a call or audio bridge event
+type Event struct {
+	Type      EventType              `json:"type"`
+	CallSID   string                 `json:"call_sid,omitempty"`
+	SessionID string                 `json:"session_id,omitempty"` // Audio bridge session
+	Call      *CallSummary           `json:"call,omitempty"`       // Set for call events
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// EventBus delivers events from the initiator and audio bridge to
+// subscribers. Implement it to publish straight to an external broker
+// (NATS, Kafka, ...), or subscribe to a LocalEventBus and forward.
+type EventBus interface {
+	// Publish must not block; it is called from audio routing goroutines
+	Publish(event Event)
+	// Subscribe calls handler for events of the given types (all if none)
+	// and returns a function that cancels the subscription
+	Subscribe(handler func(Event), types ...EventType) (unsubscribe func())
+}
+
+// LocalEventBus is an in-process EventBus. Each subscriber has its own
+// buffered queue and goroutine, so a slow subscriber only drops its own
+// events rather than stalling publishers.
+type LocalEventBus struct {
+	subscribers map[int]*eventSubscriber
+	nextID      int
+	buffer      int
+	dropped     atomic.Int64
+	mu          sync.RWMutex
+}
+
+// eventSubscriber is one subscription on a LocalEventBus
+type eventSubscriber struct {
+	types map[EventType]bool
+	queue chan Event
+}
+
+// NewLocalEventBus creates an in-process event bus with the given
+// per-subscriber buffer (default 256)
+func NewLocalEventBus(buffer int) *LocalEventBus {
+	if buffer <= 0 {
+		buffer = 256
+	}
+	return &LocalEventBus{
+		subscribers: make(map[int]*eventSubscriber),
+		buffer:      buffer,
+	}
+}
+
+// Publish queues an event for every matching subscriber
+func (b *LocalEventBus) Publish(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.subscribers {
+		if len(sub.types) > 0 && !sub.types[event.Type] {
+			continue
+		}
+		select {
+		case sub.queue <- event:
+		default:
+			if b.dropped.Add(1)%100 == 1 {
+				log.Printf("[EventBus] Subscriber queue full, dropping %s events", event.Type)
+			}
+		}
+	}
+}
+
+// Subscribe registers a handler for the given event types (all if none)
+func (b *LocalEventBus) Subscribe(handler func(Event), types ...EventType) func() {
+	sub := &eventSubscriber{
+		types: make(map[EventType]bool, len(types)),
+		queue: make(chan Event, b.buffer),
+	}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+	b.mu.Unlock()
+
+	go func() {
+		for event := range sub.queue {
+			handler(event)
+		}
+	}()
+
+	return func() {
+		b.mu.Lock()
+		_, subscribed := b.subscribers[id]
+		delete(b.subscribers, id)
+		b.mu.Unlock()
+
+		if subscribed {
+			close(sub.queue)
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because a subscriber's
+// queue was full
+func (b *LocalEventBus) Dropped() int64 {
+	return b.dropped.Load()
+}
+
+// Close cancels every subscription
+func (b *LocalEventBus) Close() {
+	b.mu.Lock()
+	subscribers := b.subscribers
+	b.subscribers = make(map[int]*eventSubscriber)
+	b.mu.Unlock()
+
+	for _, sub := range subscribers {
+		close(sub.queue)
+	}
+}
+
+// publishEvent publishes to bus when one is configured
+func publishEvent(bus EventBus, event Event) {
+	if bus == nil {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	bus.Publish(event)
+}
+
+// callEvent builds an event for a call summary
+func callEvent(eventType EventType, summary CallSummary) Event {
+	return Event{
+		Type:    eventType,
+		CallSID: summary.SignalWireCallSID,
+		Call:    &summary,
+	}
+}
@@ -0,0 +1,302 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// ============================================
+// FLAC ENCODING
+// Streaming lossless encoder for 16-bit PCM recordings
+// ============================================
+//
+// Frames use fixed-blocksize FIXED (polynomial) prediction with a single
+// Rice partition, or CONSTANT subframes for digital silence. That gives
+// roughly half the size of WAV on speech without LPC analysis. Total
+// sample count and MD5 in STREAMINFO are left unknown (zero), as allowed
+// for streams written before their length is known.
+// ============================================
+
+const (
+	flacBlockSize    = 4096
+	flacMaxRiceParam = 14 // 15 is the escape code
+)
+
+// flacEncoder writes 16-bit PCM as a FLAC stream
+type flacEncoder struct {
+	w        io.Writer
+	rate     int
+	channels int
+	block    [][]int32 // Pending samples by channel
+	frame    uint64    // Frames written
+	bw       flacBitWriter
+}
+
+// newFLACEncoder writes the stream header and returns an encoder for
+// interleaved 16-bit samples
+func newFLACEncoder(w io.Writer, sampleRate, channels int) (*flacEncoder, error) {
+	if sampleRate <= 0 || sampleRate >= 1<<20 {
+		return nil, fmt.Errorf("invalid FLAC sample rate: %d", sampleRate)
+	}
+	if channels < 1 || channels > 8 {
+		return nil, fmt.Errorf("invalid FLAC channel count: %d", channels)
+	}
+
+	e := &flacEncoder{w: w, rate: sampleRate, channels: channels, block: make([][]int32, channels)}
+	for ch := range e.block {
+		e.block[ch] = make([]int32, 0, flacBlockSize)
+	}
+
+	// "fLaC", then STREAMINFO as the only (last) metadata block
+	header := []byte("fLaC")
+	header = append(header, 0x80, 0, 0, 34)
+	header = binary.BigEndian.AppendUint16(header, flacBlockSize) // Min block size
+	header = binary.BigEndian.AppendUint16(header, flacBlockSize) // Max block size
+	header = append(header, 0, 0, 0, 0, 0, 0)                     // Min/max frame size unknown
+	// 20 bits rate, 3 bits channels-1, 5 bits bps-1, 36 bits total samples (unknown)
+	info := uint64(sampleRate)<<44 | uint64(channels-1)<<41 | uint64(15)<<36
+	header = binary.BigEndian.AppendUint64(header, info)
+	header = append(header, make([]byte, 16)...) // MD5 unknown
+
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// write encodes interleaved samples, emitting a frame per full block
+func (e *flacEncoder) write(samples []int16) error {
+	for i := 0; i+e.channels <= len(samples); i += e.channels {
+		for ch := 0; ch < e.channels; ch++ {
+			e.block[ch] = append(e.block[ch], int32(samples[i+ch]))
+		}
+		if len(e.block[0]) == flacBlockSize {
+			if err := e.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// close writes any partial final block
+func (e *flacEncoder) close() error {
+	if len(e.block[0]) == 0 {
+		return nil
+	}
+	return e.flush()
+}
+
+// flush encodes the pending block as one frame
+func (e *flacEncoder) flush() error {
+	n := len(e.block[0])
+	bw := &e.bw
+	bw.reset()
+
+	// Frame header: sync, fixed blocksize, blocksize from the 16-bit field
+	// at the end of the header
+	bw.write(0x3FFE, 14)
+	bw.write(0, 2)
+	bw.write(7, 4)
+	bw.write(uint64(flacRateCode(e.rate)), 4)
+	bw.write(uint64(e.channels-1), 4) // Independent channels
+	bw.write(4, 3)                    // 16 bits per sample
+	bw.write(0, 1)
+	bw.writeUTF8(e.frame)
+	bw.write(uint64(n-1), 16)
+	bw.write(uint64(flacCRC8(bw.buf)), 8)
+
+	for _, samples := range e.block {
+		encodeFLACSubframe(bw, samples)
+	}
+
+	bw.align()
+	bw.write(uint64(flacCRC16(bw.buf)), 16)
+
+	for ch := range e.block {
+		e.block[ch] = e.block[ch][:0]
+	}
+	e.frame++
+	_, err := e.w.Write(bw.buf)
+	return err
+}
+
+// encodeFLACSubframe writes one channel's block, choosing the FIXED
+// predictor order with the smallest residual
+func encodeFLACSubframe(bw *flacBitWriter, samples []int32) {
+	constant := true
+	for _, s := range samples[1:] {
+		if s != samples[0] {
+			constant = false
+			break
+		}
+	}
+	if constant {
+		bw.write(0, 8) // CONSTANT
+		bw.writeSigned(samples[0], 16)
+		return
+	}
+
+	order, best := 0, uint64(1<<63)
+	for o := 0; o <= 4 && o < len(samples); o++ {
+		var sum uint64
+		for i := o; i < len(samples); i++ {
+			r := flacResidual(samples, i, o)
+			if r < 0 {
+				r = -r
+			}
+			sum += uint64(r)
+		}
+		if sum < best {
+			order, best = o, sum
+		}
+	}
+
+	bw.write(uint64(0x08|order)<<1, 8) // FIXED, no wasted bits
+	for i := 0; i < order; i++ {
+		bw.writeSigned(samples[i], 16)
+	}
+
+	// Rice parameter for a geometric distribution with the residuals' mean
+	count := uint64(len(samples) - order)
+	k := 0
+	if count > 0 {
+		if mean := 2 * best / count; mean > 0 {
+			k = min(bits.Len64(mean)-1, flacMaxRiceParam)
+		}
+	}
+	bw.write(0, 2) // Rice, 4-bit parameters
+	bw.write(0, 4) // One partition
+	bw.write(uint64(k), 4)
+	for i := order; i < len(samples); i++ {
+		r := flacResidual(samples, i, order)
+		u := uint64(uint32(r<<1) ^ uint32(r>>31)) // Zigzag
+		bw.writeUnary(u >> k)
+		bw.write(u&(1<<k-1), k)
+	}
+}
+
+// flacResidual is the FIXED predictor residual of sample i at order
+func flacResidual(s []int32, i, order int) int32 {
+	switch order {
+	case 1:
+		return s[i] - s[i-1]
+	case 2:
+		return s[i] - 2*s[i-1] + s[i-2]
+	case 3:
+		return s[i] - 3*s[i-1] + 3*s[i-2] - s[i-3]
+	case 4:
+		return s[i] - 4*s[i-1] + 6*s[i-2] - 4*s[i-3] + s[i-4]
+	}
+	return s[i]
+}
+
+// flacRateCode returns the frame header code for a sample rate, or 0 to
+// take it from STREAMINFO
+func flacRateCode(rate int) int {
+	switch rate {
+	case 8000:
+		return 4
+	case 16000:
+		return 5
+	case 22050:
+		return 6
+	case 24000:
+		return 7
+	case 32000:
+		return 8
+	case 44100:
+		return 9
+	case 48000:
+		return 10
+	}
+	return 0
+}
+
+// flacBitWriter packs big-endian bit fields into a byte slice
+type flacBitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits int
+}
+
+func (b *flacBitWriter) reset() {
+	b.buf, b.acc, b.nbits = b.buf[:0], 0, 0
+}
+
+// write appends the low n bits of v (n <= 32)
+func (b *flacBitWriter) write(v uint64, n int) {
+	b.acc = b.acc<<n | v&(1<<n-1)
+	b.nbits += n
+	for b.nbits >= 8 {
+		b.nbits -= 8
+		b.buf = append(b.buf, byte(b.acc>>b.nbits))
+	}
+}
+
+func (b *flacBitWriter) writeSigned(v int32, n int) {
+	b.write(uint64(uint32(v)), n)
+}
+
+// writeUnary writes q zero bits then a one
+func (b *flacBitWriter) writeUnary(q uint64) {
+	for ; q >= 32; q -= 32 {
+		b.write(0, 32)
+	}
+	b.write(1, int(q)+1)
+}
+
+// writeUTF8 writes a frame number in FLAC's extended UTF-8 coding
+func (b *flacBitWriter) writeUTF8(v uint64) {
+	if v < 0x80 {
+		b.write(v, 8)
+		return
+	}
+	n := 2 // Bytes needed: each continuation byte carries 6 bits
+	for v >= 1<<(5*n+1) {
+		n++
+	}
+	b.write(uint64(0xFF00>>n)&0xFF|v>>(6*(n-1)), 8)
+	for i := n - 2; i >= 0; i-- {
+		b.write(0x80|(v>>(6*i))&0x3F, 8)
+	}
+}
+
+// align pads with zero bits to a byte boundary
+func (b *flacBitWriter) align() {
+	if b.nbits > 0 {
+		b.write(0, 8-b.nbits)
+	}
+}
+
+func flacCRC8(data []byte) byte {
+	var crc byte
+	for _, d := range data {
+		crc ^= d
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func flacCRC16(data []byte) uint16 {
+	var crc uint16
+	for _, d := range data {
+		crc ^= uint16(d) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
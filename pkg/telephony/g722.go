@@ -0,0 +1,373 @@
+package telephony
+
+import (
+	"encoding/binary"
+)
+
+// ============================================
+// G.722 CODEC
+// Pure-Go 64 kbit/s G.722 (ITU-T G.722 sub-band ADPCM) for HD voice
+// ============================================
+
+// AudioFormatG722 is G.722-coded audio: 16kHz audio at 8 bits per sample
+// pair, decoded to and encoded from AudioFormatPCM
+var AudioFormatG722 = AudioFormat{SampleRate: 16000, Channels: 1, Encoding: "g722", BitDepth: 4}
+
+// StreamCodecG722 is the <Stream> codec requesting G.722 media
+const StreamCodecG722 = "G722"
+
+// G.722 quantizer and adaptation tables (ITU-T G.722 tables 6-17)
+var (
+	g722q6   = [32]int{0, 35, 72, 110, 150, 190, 233, 276, 323, 370, 422, 473, 530, 587, 650, 714, 786, 858, 940, 1023, 1121, 1219, 1339, 1458, 1612, 1765, 1980, 2195, 2557, 2919, 0, 0}
+	g722iln  = [32]int{0, 63, 62, 31, 30, 29, 28, 27, 26, 25, 24, 23, 22, 21, 20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 0}
+	g722ilp  = [32]int{0, 61, 60, 59, 58, 57, 56, 55, 54, 53, 52, 51, 50, 49, 48, 47, 46, 45, 44, 43, 42, 41, 40, 39, 38, 37, 36, 35, 34, 33, 32, 0}
+	g722wl   = [8]int{-60, -30, 58, 172, 334, 538, 1198, 3042}
+	g722rl42 = [16]int{0, 7, 6, 5, 4, 3, 2, 1, 7, 6, 5, 4, 3, 2, 1, 0}
+	g722ilb  = [32]int{2048, 2093, 2139, 2186, 2233, 2282, 2332, 2383, 2435, 2489, 2543, 2599, 2656, 2714, 2774, 2834, 2896, 2960, 3025, 3091, 3158, 3228, 3298, 3371, 3444, 3520, 3597, 3676, 3756, 3838, 3922, 4008}
+	g722qm4  = [16]int{0, -20456, -12896, -8968, -6288, -4240, -2584, -1200, 20456, 12896, 8968, 6288, 4240, 2584, 1200, 0}
+	g722qm6  = [64]int{
+		-136, -136, -136, -136, -24808, -21904, -19008, -16704, -14984, -13512, -12280, -11192, -10232, -9360, -8576, -7856,
+		-7192, -6576, -6000, -5456, -4944, -4464, -4008, -3576, -3168, -2776, -2400, -2032, -1688, -1360, -1040, -728,
+		24808, 21904, 19008, 16704, 14984, 13512, 12280, 11192, 10232, 9360, 8576, 7856, 7192, 6576, 6000, 5456,
+		4944, 4464, 4008, 3576, 3168, 2776, 2400, 2032, 1688, 1360, 1040, 728, 432, 136, -432, -136,
+	}
+	g722qm2 = [4]int{-7408, -1616, 7408, 1616}
+	g722qmf = [12]int{3, -11, 12, 32, -210, 951, 3876, -805, 362, -156, 53, -11}
+	g722ihn = [3]int{0, 1, 0}
+	g722ihp = [3]int{0, 3, 2}
+	g722wh  = [3]int{0, -214, 798}
+	g722rh2 = [4]int{2, 1, 2, 1}
+)
+
+// g722Band is the ADPCM predictor state of one sub-band
+type g722Band struct {
+	s, sp, sz int
+	r, a, ap  [3]int
+	p         [3]int
+	d, b, bp  [7]int
+	sg        [7]int
+	nb, det   int
+}
+
+// saturate16 clamps to the int16 range
+func saturate16(v int) int {
+	if v > 32767 {
+		return 32767
+	}
+	if v < -32768 {
+		return -32768
+	}
+	return v
+}
+
+// update adapts the band's predictor to a quantized difference (block 4)
+func (b *g722Band) update(d int) {
+	// RECONS, PARREC
+	b.d[0] = d
+	b.r[0] = saturate16(b.s + d)
+	b.p[0] = saturate16(b.sz + d)
+
+	// UPPOL2
+	for i := 0; i < 3; i++ {
+		b.sg[i] = b.p[i] >> 15
+	}
+	wd1 := saturate16(b.a[1] << 2)
+	wd2 := wd1
+	if b.sg[0] == b.sg[1] {
+		wd2 = -wd1
+	}
+	if wd2 > 32767 {
+		wd2 = 32767
+	}
+	wd3 := wd2 >> 7
+	if b.sg[0] == b.sg[2] {
+		wd3 += 128
+	} else {
+		wd3 -= 128
+	}
+	wd3 += (b.a[2] * 32512) >> 15
+	if wd3 > 12288 {
+		wd3 = 12288
+	} else if wd3 < -12288 {
+		wd3 = -12288
+	}
+	b.ap[2] = wd3
+
+	// UPPOL1
+	b.sg[0] = b.p[0] >> 15
+	b.sg[1] = b.p[1] >> 15
+	wd1 = -192
+	if b.sg[0] == b.sg[1] {
+		wd1 = 192
+	}
+	wd2 = (b.a[1] * 32640) >> 15
+	b.ap[1] = saturate16(wd1 + wd2)
+	wd3 = saturate16(15360 - b.ap[2])
+	if b.ap[1] > wd3 {
+		b.ap[1] = wd3
+	} else if b.ap[1] < -wd3 {
+		b.ap[1] = -wd3
+	}
+
+	// UPZERO
+	wd1 = 128
+	if d == 0 {
+		wd1 = 0
+	}
+	b.sg[0] = d >> 15
+	for i := 1; i < 7; i++ {
+		b.sg[i] = b.d[i] >> 15
+		wd2 = -wd1
+		if b.sg[i] == b.sg[0] {
+			wd2 = wd1
+		}
+		wd3 = (b.b[i] * 32640) >> 15
+		b.bp[i] = saturate16(wd2 + wd3)
+	}
+
+	// DELAYA
+	for i := 6; i > 0; i-- {
+		b.d[i] = b.d[i-1]
+		b.b[i] = b.bp[i]
+	}
+	for i := 2; i > 0; i-- {
+		b.r[i] = b.r[i-1]
+		b.p[i] = b.p[i-1]
+		b.a[i] = b.ap[i]
+	}
+
+	// FILTEP
+	wd1 = saturate16(b.r[1] + b.r[1])
+	wd1 = (b.a[1] * wd1) >> 15
+	wd2 = saturate16(b.r[2] + b.r[2])
+	wd2 = (b.a[2] * wd2) >> 15
+	b.sp = saturate16(wd1 + wd2)
+
+	// FILTEZ
+	b.sz = 0
+	for i := 6; i > 0; i-- {
+		wd1 = saturate16(b.d[i] + b.d[i])
+		b.sz += (b.b[i] * wd1) >> 15
+	}
+	b.sz = saturate16(b.sz)
+
+	// PREDIC
+	b.s = saturate16(b.sp + b.sz)
+}
+
+// g722Scale computes a band's step size from its log scale factor
+// (SCALEL/SCALEH)
+func g722Scale(nb, shift int) int {
+	wd1 := (nb >> 6) & 31
+	wd2 := shift - (nb >> 11)
+	if wd2 < 0 {
+		return (g722ilb[wd1] << -wd2) << 2
+	}
+	return (g722ilb[wd1] >> wd2) << 2
+}
+
+// adaptLow updates the low band's scale factor (LOGSCL, SCALEL)
+func (b *g722Band) adaptLow(il4 int) {
+	nb := (b.nb*127)>>7 + g722wl[il4]
+	if nb < 0 {
+		nb = 0
+	} else if nb > 18432 {
+		nb = 18432
+	}
+	b.nb = nb
+	b.det = g722Scale(nb, 8)
+}
+
+// adaptHigh updates the high band's scale factor (LOGSCH, SCALEH)
+func (b *g722Band) adaptHigh(ih2 int) {
+	nb := (b.nb*127)>>7 + g722wh[ih2]
+	if nb < 0 {
+		nb = 0
+	} else if nb > 22528 {
+		nb = 22528
+	}
+	b.nb = nb
+	b.det = g722Scale(nb, 10)
+}
+
+// G722Encoder encodes 16kHz 16-bit PCM to 64 kbit/s G.722. It keeps state
+// between calls, so use one encoder per stream.
+type G722Encoder struct {
+	band [2]g722Band
+	x    [24]int
+	odd  []byte // Trailing sample held for the next call
+}
+
+// NewG722Encoder creates a G.722 encoder
+func NewG722Encoder() *G722Encoder {
+	e := &G722Encoder{}
+	e.band[0].det = 32
+	e.band[1].det = 8
+	return e
+}
+
+// Encode encodes little-endian 16-bit PCM, one output byte per two
+// samples. An odd trailing sample is carried into the next call.
+func (e *G722Encoder) Encode(pcm []byte) []byte {
+	if len(e.odd) > 0 {
+		pcm = append(append([]byte(nil), e.odd...), pcm...)
+		e.odd = nil
+	}
+	pairs := len(pcm) / 4
+	if rest := pcm[pairs*4:]; len(rest) > 0 {
+		e.odd = append([]byte(nil), rest...)
+	}
+
+	out := make([]byte, pairs)
+	for n := 0; n < pairs; n++ {
+		// Transmit QMF: split the sample pair into low and high bands
+		copy(e.x[:22], e.x[2:])
+		e.x[22] = int(int16(binary.LittleEndian.Uint16(pcm[n*4:])))
+		e.x[23] = int(int16(binary.LittleEndian.Uint16(pcm[n*4+2:])))
+
+		sumEven, sumOdd := 0, 0
+		for i := 0; i < 12; i++ {
+			sumOdd += e.x[2*i] * g722qmf[i]
+			sumEven += e.x[2*i+1] * g722qmf[11-i]
+		}
+		xlow := (sumEven + sumOdd) >> 14
+		xhigh := (sumEven - sumOdd) >> 14
+
+		// Low band: 6-bit quantizer (SUBTRA, QUANTL)
+		low := &e.band[0]
+		el := saturate16(xlow - low.s)
+		wd := el
+		if el < 0 {
+			wd = -(el + 1)
+		}
+		i := 1
+		for ; i < 30; i++ {
+			if wd < (g722q6[i]*low.det)>>12 {
+				break
+			}
+		}
+		ilow := g722ilp[i]
+		if el < 0 {
+			ilow = g722iln[i]
+		}
+
+		// INVQAL, then adapt on the 4-bit core
+		ril := ilow >> 2
+		dlow := (low.det * g722qm4[ril]) >> 15
+		low.adaptLow(g722rl42[ril])
+		low.update(dlow)
+
+		// High band: 2-bit quantizer (SUBTRA, QUANTH)
+		high := &e.band[1]
+		eh := saturate16(xhigh - high.s)
+		wd = eh
+		if eh < 0 {
+			wd = -(eh + 1)
+		}
+		mih := 1
+		if wd >= (564*high.det)>>12 {
+			mih = 2
+		}
+		ihigh := g722ihp[mih]
+		if eh < 0 {
+			ihigh = g722ihn[mih]
+		}
+
+		dhigh := (high.det * g722qm2[ihigh]) >> 15
+		high.adaptHigh(g722rh2[ihigh])
+		high.update(dhigh)
+
+		out[n] = byte(ihigh<<6 | ilow)
+	}
+	return out
+}
+
+// G722Decoder decodes 64 kbit/s G.722 to 16kHz 16-bit PCM. It keeps state
+// between calls, so use one decoder per stream.
+type G722Decoder struct {
+	band [2]g722Band
+	x    [24]int
+}
+
+// NewG722Decoder creates a G.722 decoder
+func NewG722Decoder() *G722Decoder {
+	d := &G722Decoder{}
+	d.band[0].det = 32
+	d.band[1].det = 8
+	return d
+}
+
+// Decode decodes G.722 bytes to little-endian 16-bit PCM, two samples per
+// byte
+func (d *G722Decoder) Decode(data []byte) []byte {
+	out := make([]byte, len(data)*4)
+	for n, code := range data {
+		ilow := int(code & 0x3F)
+		ihigh := int(code>>6) & 0x03
+
+		// Low band (INVQBL, RECONS, LIMIT)
+		low := &d.band[0]
+		rlow := low.s + (low.det*g722qm6[ilow])>>15
+		if rlow > 16383 {
+			rlow = 16383
+		} else if rlow < -16384 {
+			rlow = -16384
+		}
+		ril := ilow >> 2
+		dlow := (low.det * g722qm4[ril]) >> 15
+		low.adaptLow(g722rl42[ril])
+		low.update(dlow)
+
+		// High band (INVQAH, RECONS, LIMIT)
+		high := &d.band[1]
+		dhigh := (high.det * g722qm2[ihigh]) >> 15
+		rhigh := dhigh + high.s
+		if rhigh > 16383 {
+			rhigh = 16383
+		} else if rhigh < -16384 {
+			rhigh = -16384
+		}
+		high.adaptHigh(g722rh2[ihigh])
+		high.update(dhigh)
+
+		// Receive QMF: recombine the bands into a sample pair
+		copy(d.x[:22], d.x[2:])
+		d.x[22] = rlow + rhigh
+		d.x[23] = rlow - rhigh
+
+		xout1, xout2 := 0, 0
+		for i := 0; i < 12; i++ {
+			xout2 += d.x[2*i] * g722qmf[i]
+			xout1 += d.x[2*i+1] * g722qmf[11-i]
+		}
+		binary.LittleEndian.PutUint16(out[n*4:], uint16(int16(saturate16(xout1>>11))))
+		binary.LittleEndian.PutUint16(out[n*4+2:], uint16(int16(saturate16(xout2>>11))))
+	}
+	return out
+}
+
+// g722Codec adapts the G.722 encoder and decoder to a stream
+type g722Codec struct {
+	enc *G722Encoder
+	dec *G722Decoder
+}
+
+func newG722Codec() *g722Codec {
+	return &g722Codec{enc: NewG722Encoder(), dec: NewG722Decoder()}
+}
+
+func (c *g722Codec) Format() AudioFormat { return AudioFormatG722 }
+
+func (c *g722Codec) Decode(payload []byte) ([]byte, error) {
+	return c.dec.Decode(payload), nil
+}
+
+func (c *g722Codec) Encode(pcm []byte) ([][]byte, error) {
+	encoded := c.enc.Encode(pcm)
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+	return [][]byte{encoded}, nil
+}
+
+func (c *g722Codec) Close() {}
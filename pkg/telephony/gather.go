@@ -0,0 +1,215 @@
+package telephony
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// ============================================
+// GATHER / DTMF WEBHOOKS
+// Digit input collected with <Gather>, dispatched to registered handlers
+// ============================================
+
+// GatherPath is the webhook path that receives <Gather> results
+const GatherPath = "/api/telephony/calls/gather"
+
+// legacyGatherPath is the action used by signalwire.Client.GenerateTwiML
+const legacyGatherPath = "/api/webhooks/signalwire/gather"
+
+// DigitHandler handles digits entered by a caller and returns the next
+// instructions for the call (nil ends the call)
+type DigitHandler func(digits string) *TwiML
+
+// GatherPrompt describes how digits are collected and re-prompted
+type GatherPrompt struct {
+	Text        string // Prompt spoken to the caller
+	Voice       string
+	NumDigits   int    // Digits to collect (0 = until FinishOnKey or timeout)
+	Timeout     int    // Seconds to wait for input (default 5)
+	FinishOnKey string // Key ending input (default "#")
+	MaxRetries  int    // Re-prompts after a timeout (default 2)
+	RetryText   string // Spoken before re-prompting
+	GoodbyeText string // Spoken when retries run out
+}
+
+// gatherRegistry tracks digit handlers and per-call gather state
+type gatherRegistry struct {
+	byCall  map[string]DigitHandler
+	byRoute map[string]DigitHandler
+	prompts map[string]gatherState // callSID -> last prompt issued
+	mu      sync.Mutex
+}
+
+// gatherState is the prompt a call is answering and how often it timed out
+type gatherState struct {
+	prompt   GatherPrompt
+	route    string
+	attempts int
+}
+
+func newGatherRegistry() *gatherRegistry {
+	return &gatherRegistry{
+		byCall:  make(map[string]DigitHandler),
+		byRoute: make(map[string]DigitHandler),
+		prompts: make(map[string]gatherState),
+	}
+}
+
+// withDefaults fills in unset prompt fields
+func (p GatherPrompt) withDefaults() GatherPrompt {
+	if p.Timeout <= 0 {
+		p.Timeout = 5
+	}
+	if p.FinishOnKey == "" {
+		p.FinishOnKey = "#"
+	}
+	if p.MaxRetries <= 0 {
+		p.MaxRetries = 2
+	}
+	if p.RetryText == "" {
+		p.RetryText = "Sorry, we didn't get that."
+	}
+	if p.GoodbyeText == "" {
+		p.GoodbyeText = "We didn't receive any input. Goodbye!"
+	}
+	return p
+}
+
+// OnDigits registers the handler for digits entered on a specific call.
+// Per-call handlers take precedence over route handlers.
+func (h *CallHandlers) OnDigits(callSID string, handler DigitHandler) {
+	h.gather.mu.Lock()
+	defer h.gather.mu.Unlock()
+	h.gather.byCall[callSID] = handler
+}
+
+// OnRouteDigits registers the handler for digits gathered on a named route
+// (e.g. "main-menu"), shared by all calls
+func (h *CallHandlers) OnRouteDigits(route string, handler DigitHandler) {
+	h.gather.mu.Lock()
+	defer h.gather.mu.Unlock()
+	h.gather.byRoute[route] = handler
+}
+
+// GatherTwiML builds the TwiML prompting a call for digits. Results are
+// posted to GatherPath and dispatched to the call's handler, or to the
+// route's handler when route is set. Timeouts re-prompt up to MaxRetries.
+func (h *CallHandlers) GatherTwiML(callSID, route string, prompt GatherPrompt) *TwiML {
+	prompt = prompt.withDefaults()
+
+	h.gather.mu.Lock()
+	h.gather.prompts[callSID] = gatherState{prompt: prompt, route: route}
+	h.gather.mu.Unlock()
+
+	return gatherTwiML(route, prompt)
+}
+
+// gatherTwiML renders a <Gather> followed by a redirect back to the gather
+// webhook, which fires only when the caller entered nothing
+func gatherTwiML(route string, prompt GatherPrompt) *TwiML {
+	action := GatherPath
+	if route != "" {
+		action += "?route=" + url.QueryEscape(route)
+	}
+
+	gather := Gather{
+		Input:       "dtmf",
+		Action:      action,
+		Method:      "POST",
+		NumDigits:   prompt.NumDigits,
+		Timeout:     prompt.Timeout,
+		FinishOnKey: prompt.FinishOnKey,
+	}
+	if prompt.Text != "" {
+		gather.Prompts = append(gather.Prompts, Say{Text: prompt.Text, Voice: prompt.Voice})
+	}
+
+	return NewTwiML().Gather(gather).Redirect(action)
+}
+
+// HandleGather receives <Gather> results and dispatches them to the
+// registered digit handler
+func (h *CallHandlers) HandleGather(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	callSID := r.FormValue("CallSid")
+	digits := r.FormValue("Digits")
+	route := r.URL.Query().Get("route")
+
+	if callSID == "" {
+		http.Error(w, "Missing CallSid", http.StatusBadRequest)
+		return
+	}
+
+	h.gather.mu.Lock()
+	state, prompted := h.gather.prompts[callSID]
+	if !prompted {
+		state = gatherState{prompt: GatherPrompt{}.withDefaults(), route: route}
+	}
+
+	// No input: re-prompt or give up
+	if digits == "" {
+		state.attempts++
+		h.gather.prompts[callSID] = state
+		h.gather.mu.Unlock()
+
+		var twiml *TwiML
+		if state.attempts > state.prompt.MaxRetries {
+			log.Printf("[CallHandlers] No digits from call %s after %d attempts", callSID, state.attempts)
+			twiml = NewTwiML().Say(state.prompt.GoodbyeText, state.prompt.Voice).Hangup()
+		} else {
+			twiml = NewTwiML().Say(state.prompt.RetryText, state.prompt.Voice)
+			twiml.Verbs = append(twiml.Verbs, gatherTwiML(state.route, state.prompt).Verbs...)
+		}
+		writeTwiML(w, twiml)
+		return
+	}
+
+	handler, ok := h.gather.byCall[callSID]
+	if !ok && route != "" {
+		handler, ok = h.gather.byRoute[route]
+	}
+	delete(h.gather.prompts, callSID)
+	h.gather.mu.Unlock()
+
+	log.Printf("[CallHandlers] Digits from call %s: %s (route: %s)", callSID, digits, route)
+
+	if !ok {
+		log.Printf("[CallHandlers] No digit handler for call %s (route: %s)", callSID, route)
+		writeTwiML(w, NewTwiML().Say("Sorry, that option is not available. Goodbye!", state.prompt.Voice).Hangup())
+		return
+	}
+
+	twiml := handler(digits)
+	if twiml == nil {
+		twiml = NewTwiML().Hangup()
+	}
+	writeTwiML(w, twiml)
+}
+
+// clearGather forgets digit handlers and prompts for a finished call
+func (h *CallHandlers) clearGather(callSID string) {
+	h.gather.mu.Lock()
+	defer h.gather.mu.Unlock()
+	delete(h.gather.byCall, callSID)
+	delete(h.gather.prompts, callSID)
+}
+
+// writeTwiML writes a TwiML document as the HTTP response
+func writeTwiML(w http.ResponseWriter, twiml *TwiML) {
+	output, err := twiml.Bytes()
+	if err != nil {
+		log.Printf("[CallHandlers] Failed to marshal TwiML: %v", err)
+		http.Error(w, "Failed to generate TwiML", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
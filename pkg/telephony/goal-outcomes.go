@@ -0,0 +1,61 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ============================================
+// GOAL OUTCOMES
+// What a call achieved toward its ConversationGoal
+// ============================================
+
+// GoalOutcome is the structured result of a call's ConversationGoal, e.g.
+// the quote details gathered or the appointment booked. Data holds the
+// fields of the goal's schema (see conversation.OutcomeSchema).
+type GoalOutcome struct {
+	Goal        string                 `json:"goal"`
+	Achieved    bool                   `json:"achieved"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	ExtractedAt time.Time              `json:"extracted_at"`
+}
+
+// goal returns the outcome's goal, or "" for no outcome
+func (o *GoalOutcome) goal() string {
+	if o == nil {
+		return ""
+	}
+	return o.Goal
+}
+
+// achieved returns whether the goal was achieved, or nil for no outcome
+func (o *GoalOutcome) achieved() *bool {
+	if o == nil {
+		return nil
+	}
+	return &o.Achieved
+}
+
+// SetGoalOutcome records what a call achieved toward its goal, replacing
+// any earlier outcome
+func (ci *CallInitiator) SetGoalOutcome(ctx context.Context, callSID string, outcome GoalOutcome) error {
+	if outcome.Goal == "" {
+		return fmt.Errorf("goal outcome has no goal")
+	}
+	if outcome.ExtractedAt.IsZero() {
+		outcome.ExtractedAt = time.Now()
+	}
+
+	_, err := ci.updateCall(ctx, callSID, func(session *CallSession) error {
+		session.GoalOutcome = &outcome
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save goal outcome: %w", err)
+	}
+
+	log.Printf("[CallInitiator] Call %s %s goal achieved: %v", callSID, outcome.Goal, outcome.Achieved)
+	return nil
+}
@@ -0,0 +1,87 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// ============================================
+// AGENT HANDOFF
+// Warm handoff from the AI to a human agent
+// ============================================
+
+// ConversationSummarizer summarizes the conversation on a call so far, for
+// the agent taking it over
+type ConversationSummarizer interface {
+	Summarize(ctx context.Context, callSID string) (string, error)
+}
+
+// SetConversationSummarizer sets where handoff summaries come from
+func (ci *CallInitiator) SetConversationSummarizer(summarizer ConversationSummarizer) {
+	ci.summarizer = summarizer
+}
+
+// HandoffOptions customizes a handoff
+type HandoffOptions struct {
+	TransferOptions
+	Summary string // Used instead of asking the summarizer
+}
+
+// HandoffToAgent hands a call from the AI to a human agent. The caller is
+// held in a conference while the agent is dialed; when the agent answers
+// they hear a summary of the conversation, then are bridged to the caller.
+// The call's audio stream to the AI is closed once the caller is on hold.
+func (ci *CallInitiator) HandoffToAgent(ctx context.Context, callSID, agent string, opts HandoffOptions) (*Transfer, error) {
+	summary := opts.Summary
+	if summary == "" && ci.summarizer != nil {
+		generated, err := ci.summarizer.Summarize(ctx, callSID)
+		if err != nil {
+			// Hand off without context rather than leave the caller with the AI
+			log.Printf("[CallInitiator] Failed to summarize call %s for handoff: %v", callSID, err)
+		}
+		summary = generated
+	}
+
+	whisper := "Incoming transfer from the virtual assistant."
+	if summary != "" {
+		whisper += " " + summary
+	}
+	transferOpts := opts.TransferOptions
+	transferOpts.Whisper = whisper
+
+	transfer, err := ci.TransferCallWithOptions(ctx, callSID, agent, TransferWarm, transferOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hand off call: %w", err)
+	}
+
+	ci.detachAI(callSID)
+
+	_, err = ci.updateCall(ctx, callSID, func(session *CallSession) error {
+		session.Metadata.Set("handoff_agent", agent)
+		session.Metadata.Set("handoff_summary", summary)
+		return nil
+	})
+	if err != nil {
+		log.Printf("[CallInitiator] Failed to record handoff on session: %v", err)
+	}
+
+	log.Printf("[CallInitiator] Handing off call %s to agent %s", callSID, agent)
+	return transfer, nil
+}
+
+// detachAI closes the bridge session feeding the call's audio to the AI.
+// Moving the call into the conference already stops its media stream;
+// closing the session ends the AI's side immediately.
+func (ci *CallInitiator) detachAI(callSID string) {
+	if ci.audioBridge == nil {
+		return
+	}
+	bridgeSession := ci.audioBridge.GetSessionByCallSID(callSID)
+	if bridgeSession == nil {
+		return
+	}
+	if err := ci.audioBridge.CloseSession(bridgeSession.GetSessionID()); err != nil {
+		log.Printf("[CallInitiator] Failed to detach AI from call %s: %v", callSID, err)
+	}
+}
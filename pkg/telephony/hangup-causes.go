@@ -0,0 +1,137 @@
+package telephony
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ============================================
+// HANGUP CAUSES
+// Q.850 causes for why a call ended, from SignalWire callbacks
+// ============================================
+
+// HangupCause is the Q.850 cause a call ended with
+type HangupCause string
+
+const (
+	CauseNormalClearing        HangupCause = "NORMAL_CLEARING"           // 16: hung up after talking
+	CauseUserBusy              HangupCause = "USER_BUSY"                 // 17: callee busy
+	CauseNoUserResponse        HangupCause = "NO_USER_RESPONSE"          // 18: device didn't respond
+	CauseNoAnswer              HangupCause = "NO_ANSWER"                 // 19: rang out
+	CauseCallRejected          HangupCause = "CALL_REJECTED"             // 21: declined by callee or carrier
+	CauseUnallocatedNumber     HangupCause = "UNALLOCATED_NUMBER"        // 1: number doesn't exist
+	CauseNumberChanged         HangupCause = "NUMBER_CHANGED"            // 22
+	CauseDestinationOutOfOrder HangupCause = "DESTINATION_OUT_OF_ORDER"  // 27
+	CauseInvalidNumberFormat   HangupCause = "INVALID_NUMBER_FORMAT"     // 28
+	CauseNormalUnspecified     HangupCause = "NORMAL_UNSPECIFIED"        // 31
+	CauseCircuitCongestion     HangupCause = "NORMAL_CIRCUIT_CONGESTION" // 34
+	CauseNetworkOutOfOrder     HangupCause = "NETWORK_OUT_OF_ORDER"      // 38
+	CauseTemporaryFailure      HangupCause = "NORMAL_TEMPORARY_FAILURE"  // 41
+	CauseTimerExpired          HangupCause = "RECOVERY_ON_TIMER_EXPIRE"  // 102
+	CauseOriginatorCancel      HangupCause = "ORIGINATOR_CANCEL"         // We hung up before answer
+)
+
+// q850Causes maps Q.850 cause codes to causes
+var q850Causes = map[int]HangupCause{
+	1:   CauseUnallocatedNumber,
+	16:  CauseNormalClearing,
+	17:  CauseUserBusy,
+	18:  CauseNoUserResponse,
+	19:  CauseNoAnswer,
+	21:  CauseCallRejected,
+	22:  CauseNumberChanged,
+	27:  CauseDestinationOutOfOrder,
+	28:  CauseInvalidNumberFormat,
+	31:  CauseNormalUnspecified,
+	34:  CauseCircuitCongestion,
+	38:  CauseNetworkOutOfOrder,
+	41:  CauseTemporaryFailure,
+	102: CauseTimerExpired,
+}
+
+// sipCauses maps final SIP response codes to causes (per RFC 3398)
+var sipCauses = map[int]HangupCause{
+	200: CauseNormalClearing,
+	403: CauseCallRejected,
+	404: CauseUnallocatedNumber,
+	408: CauseTimerExpired,
+	410: CauseNumberChanged,
+	480: CauseNoUserResponse,
+	484: CauseInvalidNumberFormat,
+	486: CauseUserBusy,
+	487: CauseOriginatorCancel,
+	502: CauseNetworkOutOfOrder,
+	503: CauseTemporaryFailure,
+	504: CauseTimerExpired,
+	600: CauseUserBusy,
+	603: CauseCallRejected,
+	604: CauseUnallocatedNumber,
+}
+
+// statusCauses gives the cause implied by a final CallStatus, used when a
+// callback carries nothing more specific
+var statusCauses = map[string]HangupCause{
+	"completed": CauseNormalClearing,
+	"busy":      CauseUserBusy,
+	"no-answer": CauseNoAnswer,
+	"canceled":  CauseOriginatorCancel,
+	"failed":    CauseTemporaryFailure,
+}
+
+// HangupCauseFromQ850 returns the cause for a Q.850 code
+func HangupCauseFromQ850(code int) (HangupCause, bool) {
+	cause, ok := q850Causes[code]
+	return cause, ok
+}
+
+// HangupCauseFromSIP returns the cause for a final SIP response code.
+// Unlisted codes map by class: 4xx to CALL_REJECTED, 5xx to
+// NORMAL_TEMPORARY_FAILURE, 6xx to CALL_REJECTED.
+func HangupCauseFromSIP(code int) (HangupCause, bool) {
+	if cause, ok := sipCauses[code]; ok {
+		return cause, true
+	}
+	switch {
+	case code >= 400 && code < 500, code >= 600 && code < 700:
+		return CauseCallRejected, true
+	case code >= 500 && code < 600:
+		return CauseTemporaryFailure, true
+	}
+	return "", false
+}
+
+// HangupCauseFromRequest reads the cause from a status callback: the
+// HangupCause parameter (a cause name or Q.850 code), else the
+// SipResponseCode, else the final CallStatus. It returns "" for calls still
+// in progress.
+func HangupCauseFromRequest(r *http.Request) (HangupCause, int) {
+	sipCode, _ := strconv.Atoi(r.FormValue("SipResponseCode"))
+
+	if value := strings.TrimSpace(r.FormValue("HangupCause")); value != "" {
+		if code, err := strconv.Atoi(value); err == nil {
+			if cause, ok := HangupCauseFromQ850(code); ok {
+				return cause, sipCode
+			}
+		} else {
+			return HangupCause(strings.ToUpper(value)), sipCode
+		}
+	}
+	if cause, ok := HangupCauseFromSIP(sipCode); ok {
+		return cause, sipCode
+	}
+	return statusCauses[r.FormValue("CallStatus")], sipCode
+}
+
+// IsCarrierRejection reports whether the network or carrier refused the
+// call (blocked, invalid or unreachable number), as opposed to the callee
+// being busy or not answering
+func (c HangupCause) IsCarrierRejection() bool {
+	switch c {
+	case CauseCallRejected, CauseUnallocatedNumber, CauseNumberChanged,
+		CauseDestinationOutOfOrder, CauseInvalidNumberFormat,
+		CauseCircuitCongestion, CauseNetworkOutOfOrder:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================
+// INBOUND CALLS
+// Call sessions for calls received on our numbers
+// ============================================
+
+// InboundCallParams holds the SignalWire webhook parameters describing an
+// incoming call
+type InboundCallParams struct {
+	CallSID     string
+	AccountSID  string
+	From        string
+	To          string
+	Direction   string // "inbound", "outbound-api", "outbound-dial"
+	CallerName  string
+	FromCity    string
+	FromState   string
+	FromZip     string
+	FromCountry string
+	StirVerstat string // STIR/SHAKEN verification status (see ParseVerstat)
+
+	// Custom SIP headers on the incoming INVITE
+	SIPHeaders map[string]string
+
+	// Bridge session created for the call's media stream
+	BridgeSessionID string
+}
+
+// InboundCallParamsFromRequest extracts call parameters from a SignalWire webhook
+func InboundCallParamsFromRequest(r *http.Request) InboundCallParams {
+	return InboundCallParams{
+		CallSID:     r.FormValue("CallSid"),
+		AccountSID:  r.FormValue("AccountSid"),
+		From:        r.FormValue("From"),
+		To:          r.FormValue("To"),
+		Direction:   r.FormValue("Direction"),
+		CallerName:  r.FormValue("CallerName"),
+		FromCity:    r.FormValue("FromCity"),
+		FromState:   r.FormValue("FromState"),
+		FromZip:     r.FormValue("FromZip"),
+		FromCountry: r.FormValue("FromCountry"),
+		StirVerstat: r.FormValue("StirVerstat"),
+		SIPHeaders:  SIPHeadersFromRequest(r),
+	}
+}
+
+// IsOutbound reports whether the webhook is for a call we placed
+func (p InboundCallParams) IsOutbound() bool {
+	return p.Direction == "outbound-api" || p.Direction == "outbound-dial"
+}
+
+// InboundAgencyResolver maps the dialed number of an inbound call to the
+// agency that owns it
+type InboundAgencyResolver func(ctx context.Context, to string) (uuid.UUID, error)
+
+// SetInboundAgencyResolver configures how inbound calls are assigned to agencies
+func (ci *CallInitiator) SetInboundAgencyResolver(resolver InboundAgencyResolver) {
+	ci.inboundAgencyResolver = resolver
+}
+
+// RegisterInboundCall creates, persists and tracks a session for an incoming
+// call so inbound and outbound calls share one lifecycle
+func (ci *CallInitiator) RegisterInboundCall(ctx context.Context, params InboundCallParams) (*CallSession, error) {
+	if params.CallSID == "" {
+		return nil, fmt.Errorf("call_sid is required")
+	}
+
+	if existing, ok := ci.activeCalls.Load(params.CallSID); ok {
+		return existing.(*CallSession), nil
+	}
+
+	agencyID := uuid.Nil
+	if ci.inboundAgencyResolver != nil {
+		resolved, err := ci.inboundAgencyResolver(ctx, params.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve agency for %s: %w", params.To, err)
+		}
+		agencyID = resolved
+	}
+
+	metadata := Metadata{}
+	for key, value := range map[string]string{
+		"from_city":    params.FromCity,
+		"from_state":   params.FromState,
+		"from_zip":     params.FromZip,
+		"from_country": params.FromCountry,
+	} {
+		if value != "" {
+			metadata.Set(key, value)
+		}
+	}
+	if len(params.SIPHeaders) > 0 {
+		metadata.Set("sip_headers", params.SIPHeaders)
+	}
+
+	// The TwiML response answers the call, so it is live from here on
+	now := time.Now()
+	session := &CallSession{
+		ID:                uuid.New(),
+		SignalWireCallSID: params.CallSID,
+		AgencyID:          agencyID,
+		Direction:         DirectionInbound,
+		FromNumber:        params.From,
+		ToNumber:          params.To,
+		CallerName:        params.CallerName,
+		BridgeSessionID:   params.BridgeSessionID,
+		Status:            StatusInProgress,
+		State:             StateAnswered,
+		InitiatedAt:       now,
+		RingingAt:         &now,
+		AnsweredAt:        &now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		Metadata:          metadata,
+	}
+	session.setAttestation(ParseVerstat(params.StirVerstat), params.StirVerstat)
+
+	if err := ci.store.Insert(ctx, session); err != nil {
+		ci.errorLog.Record("initiator", params.CallSID, err)
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	// Persist the fields insert doesn't cover (timing, bridge link)
+	if err := ci.store.Update(ctx, session); err != nil {
+		ci.errorLog.Record("initiator", params.CallSID, err)
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	ci.activeCalls.Store(params.CallSID, session)
+	ci.claimCall(ctx, params.CallSID)
+
+	// Inbound calls are answered as soon as they are registered
+	summary := session.Summary()
+	publishEvent(ci.events, callEvent(EventCallInitiated, summary))
+	publishEvent(ci.events, callEvent(EventCallAnswered, summary))
+
+	return session, nil
+}
+
+// AttachBridgeSession links an existing call session to the audio bridge
+// session carrying its media
+func (ci *CallInitiator) AttachBridgeSession(ctx context.Context, callSID, bridgeSessionID string) (*CallSession, error) {
+	session, err := ci.updateCall(ctx, callSID, func(session *CallSession) error {
+		session.BridgeSessionID = bridgeSessionID
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+	return session, nil
+}
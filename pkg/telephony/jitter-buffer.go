@@ -0,0 +1,344 @@
+package telephony
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ============================================
+// JITTER BUFFER
+// Reorders inbound media and re-frames it into fixed-size frames
+// ============================================
+
+// JitterBufferConfig tunes the inbound jitter buffer
+type JitterBufferConfig struct {
+	// Audio held before frames are released, absorbing network jitter and
+	// giving out-of-order packets time to arrive (default 60ms)
+	TargetDelay time.Duration
+	// Most audio held, counting frames the pipeline hasn't taken yet; the
+	// oldest audio is dropped beyond it (default 500ms)
+	MaxDelay time.Duration
+	// Length of each emitted frame (default 20ms)
+	FrameDuration time.Duration
+	// How lost audio is filled (default ConcealWaveform)
+	Concealment ConcealmentMode
+}
+
+// DefaultJitterBufferConfig returns the default jitter buffer settings
+func DefaultJitterBufferConfig() JitterBufferConfig {
+	return JitterBufferConfig{
+		TargetDelay:   60 * time.Millisecond,
+		MaxDelay:      500 * time.Millisecond,
+		FrameDuration: 20 * time.Millisecond,
+	}
+}
+
+// JitterBufferStats counts what the jitter buffer did with inbound media
+type JitterBufferStats struct {
+	Received   uint64 `json:"received"`   // Packets pushed
+	Reordered  uint64 `json:"reordered"`  // Packets that arrived after a later one
+	Late       uint64 `json:"late"`       // Packets discarded for arriving after their playout
+	Duplicates uint64 `json:"duplicates"` // Packets discarded as repeats
+	Concealed  uint64 `json:"concealed"`  // Frames with lost audio concealed
+	Dropped    uint64 `json:"dropped"`    // Frames discarded because the pipeline fell behind
+	Emitted    uint64 `json:"emitted"`    // Frames delivered
+}
+
+// JitterBuffer places inbound media on the stream's timeline by timestamp,
+// holds TargetDelay of audio and releases it as fixed FrameDuration frames,
+// concealing gaps. It is safe for concurrent use.
+type JitterBuffer struct {
+	config      JitterBufferConfig
+	sampleBytes int
+	frameBytes  int
+	targetBytes int64
+	maxBytes    int64
+	bytesPerMs  float64
+	silence     byte
+	concealer   *concealer // Nil when gaps are filled with silence
+	received    []bool     // Per-sample scratch for nextFrame
+
+	packets  []jitterPacket // Sorted by offset
+	playhead int64          // Stream offset (bytes) of the next frame
+	end      int64          // Furthest stream offset received
+	started  bool
+	ready    [][]byte // Frames awaiting delivery
+	stats    JitterBufferStats
+	mu       sync.Mutex
+}
+
+// jitterPacket is media positioned on the stream timeline
+type jitterPacket struct {
+	offset int64
+	data   []byte
+	buf    []byte // The whole pushed buffer, released once played out
+}
+
+// NewJitterBuffer creates a jitter buffer for audio in format, filling in
+// zero config fields with defaults
+func NewJitterBuffer(format AudioFormat, config JitterBufferConfig) *JitterBuffer {
+	defaults := DefaultJitterBufferConfig()
+	if config.TargetDelay < 0 {
+		config.TargetDelay = 0
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = defaults.MaxDelay
+	}
+	if config.FrameDuration <= 0 {
+		config.FrameDuration = defaults.FrameDuration
+	}
+	if config.MaxDelay < config.TargetDelay+config.FrameDuration {
+		config.MaxDelay = config.TargetDelay + config.FrameDuration
+	}
+
+	sampleBytes := format.Channels * format.BitDepth / 8
+	if sampleBytes <= 0 {
+		sampleBytes = 1
+	}
+	bytesPerMs := float64(format.SampleRate*sampleBytes) / 1000
+
+	silence := byte(0)
+	if format.Encoding == AudioFormatMulaw.Encoding {
+		silence = 0xFF
+	}
+
+	// Waveform substitution works on mono μ-law or 16-bit PCM
+	var plc *concealer
+	if config.Concealment == ConcealWaveform && format.Channels == 1 &&
+		(format.Encoding == AudioFormatMulaw.Encoding || format.BitDepth == 16) {
+		plc = newConcealer(format)
+	}
+
+	return &JitterBuffer{
+		config:      config,
+		sampleBytes: sampleBytes,
+		frameBytes:  durationBytes(config.FrameDuration, bytesPerMs, sampleBytes),
+		targetBytes: int64(durationBytes(config.TargetDelay, bytesPerMs, sampleBytes)),
+		maxBytes:    int64(durationBytes(config.MaxDelay, bytesPerMs, sampleBytes)),
+		bytesPerMs:  bytesPerMs,
+		silence:     silence,
+		concealer:   plc,
+	}
+}
+
+// durationBytes converts a duration to a whole number of samples' bytes
+func durationBytes(d time.Duration, bytesPerMs float64, sampleBytes int) int {
+	n := int(float64(d) / float64(time.Millisecond) * bytesPerMs)
+	return n - n%sampleBytes
+}
+
+// Push adds a packet at its timestamp from the start of the stream. A
+// negative timestamp places it directly after the audio received so far.
+// The buffer takes ownership of data and releases it to the audio buffer
+// pool once played out.
+func (jb *JitterBuffer) Push(timestamp time.Duration, data []byte) {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+
+	offset := jb.end
+	if timestamp >= 0 {
+		offset = int64(durationBytes(timestamp, jb.bytesPerMs, jb.sampleBytes))
+	}
+	jb.push(offset, data)
+}
+
+// PushChunk adds a packet by its 1-based chunk number, for media without
+// timestamps; packets are assumed to be the same length
+func (jb *JitterBuffer) PushChunk(chunk int64, data []byte) {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+
+	offset := jb.end
+	if chunk > 0 {
+		offset = (chunk - 1) * int64(len(data))
+	}
+	jb.push(offset, data)
+}
+
+// push places a packet at a stream offset and releases any frames it
+// completes
+func (jb *JitterBuffer) push(offset int64, data []byte) {
+	jb.stats.Received++
+	if len(data) == 0 {
+		return
+	}
+	buf := data
+	if !jb.started {
+		jb.playhead = offset
+		jb.end = offset
+		jb.started = true
+	}
+
+	// Already played out
+	if offset+int64(len(data)) <= jb.playhead {
+		jb.stats.Late++
+		ReleaseAudioBuffer(buf)
+		return
+	}
+	if offset < jb.playhead {
+		data = data[jb.playhead-offset:]
+		offset = jb.playhead
+	}
+
+	i := sort.Search(len(jb.packets), func(i int) bool { return jb.packets[i].offset >= offset })
+	if i < len(jb.packets) && jb.packets[i].offset == offset {
+		jb.stats.Duplicates++
+		ReleaseAudioBuffer(buf)
+		return
+	}
+	if offset < jb.end {
+		jb.stats.Reordered++
+	}
+
+	jb.packets = append(jb.packets, jitterPacket{})
+	copy(jb.packets[i+1:], jb.packets[i:])
+	jb.packets[i] = jitterPacket{offset: offset, data: data, buf: buf}
+	if packetEnd := offset + int64(len(data)); packetEnd > jb.end {
+		jb.end = packetEnd
+	}
+
+	jb.release(false)
+}
+
+// Drain hands ready frames to send in order until send returns false; frames
+// send refuses stay buffered for the next Drain. Frames send accepts are
+// pooled buffers now owned by the receiver.
+func (jb *JitterBuffer) Drain(send func(frame []byte) bool) {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+
+	sent := 0
+	for sent < len(jb.ready) && send(jb.ready[sent]) {
+		sent++
+		jb.stats.Emitted++
+	}
+
+	// Shift the rest down, keeping the queue's capacity for reuse
+	n := copy(jb.ready, jb.ready[sent:])
+	clear(jb.ready[n:])
+	jb.ready = jb.ready[:n]
+}
+
+// Flush releases all buffered audio as frames, padding the last with
+// silence, for when the stream ends
+func (jb *JitterBuffer) Flush() {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+	jb.release(true)
+}
+
+// Stats returns the buffer's counters
+func (jb *JitterBuffer) Stats() JitterBufferStats {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+	return jb.stats
+}
+
+// release cuts frames once more than TargetDelay is buffered past them (or
+// everything, when final), then enforces MaxDelay
+func (jb *JitterBuffer) release(final bool) {
+	frameBytes := int64(jb.frameBytes)
+
+	// After a long gap in timestamps, skip ahead rather than emit the whole
+	// gap as silence
+	if lag := jb.end - jb.playhead; lag > jb.maxBytes {
+		skip := (lag - jb.targetBytes) / frameBytes * frameBytes
+		jb.playhead += skip
+		jb.trimPackets()
+	}
+
+	for jb.end-jb.playhead >= jb.targetBytes+frameBytes || (final && jb.playhead < jb.end) {
+		jb.ready = append(jb.ready, jb.nextFrame())
+	}
+
+	// The pipeline isn't keeping up: drop the oldest frames
+	for len(jb.ready) > 0 && int64(len(jb.ready))*frameBytes+(jb.end-jb.playhead) > jb.maxBytes {
+		ReleaseAudioBuffer(jb.ready[0])
+		jb.ready[0] = nil
+		jb.ready = jb.ready[1:]
+		jb.stats.Dropped++
+	}
+}
+
+// nextFrame assembles the frame at the playhead from buffered packets and
+// advances past it
+func (jb *JitterBuffer) nextFrame() []byte {
+	frame := GetAudioBuffer(jb.frameBytes)
+	for i := range frame {
+		frame[i] = jb.silence
+	}
+
+	start, stop := jb.playhead, jb.playhead+int64(jb.frameBytes)
+	samples := jb.frameBytes / jb.sampleBytes
+	if cap(jb.received) < samples {
+		jb.received = make([]bool, samples)
+	}
+	received := jb.received[:samples]
+	clear(received)
+
+	filled := 0
+	for _, p := range jb.packets {
+		if p.offset >= stop {
+			break
+		}
+		from, to := max(p.offset, start), min(p.offset+int64(len(p.data)), stop)
+		if from < to {
+			copy(frame[from-start:to-start], p.data[from-p.offset:to-p.offset])
+			for i := (from - start) / int64(jb.sampleBytes); i < (to-start)/int64(jb.sampleBytes); i++ {
+				if !received[i] {
+					received[i] = true
+					filled++
+				}
+			}
+		}
+	}
+
+	// Gaps short of the end are lost audio; past it, the stream has ended
+	limit := int(min(max(jb.end-start, 0), int64(jb.frameBytes))) / jb.sampleBytes
+	if filled < limit {
+		jb.stats.Concealed++
+	}
+	if jb.concealer != nil {
+		jb.concealer.process(frame, received, limit)
+	}
+
+	jb.playhead = stop
+	jb.trimPackets()
+	return frame
+}
+
+// trimPackets discards audio before the playhead
+func (jb *JitterBuffer) trimPackets() {
+	n := 0
+	for _, p := range jb.packets {
+		if p.offset+int64(len(p.data)) <= jb.playhead {
+			ReleaseAudioBuffer(p.buf)
+			continue
+		}
+		if p.offset < jb.playhead {
+			p.data = p.data[jb.playhead-p.offset:]
+			p.offset = jb.playhead
+		}
+		jb.packets[n] = p
+		n++
+	}
+	for i := n; i < len(jb.packets); i++ {
+		jb.packets[i] = jitterPacket{}
+	}
+	jb.packets = jb.packets[:n]
+}
+
+// mediaNumber reads a numeric media event field, which SignalWire sends
+// as a string
+func mediaNumber(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case string:
+		parsed, err := strconv.ParseInt(n, 10, 64)
+		return parsed, err == nil
+	case float64:
+		return int64(n), true
+	}
+	return 0, false
+}
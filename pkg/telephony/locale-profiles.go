@@ -0,0 +1,174 @@
+package telephony
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ============================================
+// LOCALE PROFILES
+// Per-target voice, language and script selection
+// ============================================
+
+// LocaleProfile defines the voice, recognition language and scripts used for
+// calls to targets of a given locale (BCP 47, e.g. "es-MX")
+type LocaleProfile struct {
+	Locale         string `json:"locale"`
+	VoiceID        string `json:"voice_id,omitempty"`        // TTS voice
+	STTLanguage    string `json:"stt_language,omitempty"`    // Speech recognition language
+	GreetingScript string `json:"greeting_script,omitempty"` // Script template
+	SystemPrompt   string `json:"system_prompt,omitempty"`
+}
+
+// LocaleStats tracks call performance for a locale
+type LocaleStats struct {
+	Locale           string  `json:"locale"`
+	Attempts         int64   `json:"attempts"`
+	Answered         int64   `json:"answered"`
+	Completed        int64   `json:"completed"`
+	Voicemail        int64   `json:"voicemail"`
+	Failed           int64   `json:"failed"`
+	TotalTalkSeconds int64   `json:"total_talk_seconds"`
+	AnswerRate       float64 `json:"answer_rate"`
+	AverageTalkTime  float64 `json:"average_talk_time_seconds"`
+}
+
+// LocaleProfiles resolves locale profiles for call targets and reports
+// per-locale performance
+type LocaleProfiles struct {
+	profiles map[string]LocaleProfile
+	fallback LocaleProfile
+	stats    map[string]*LocaleStats
+	mu       sync.RWMutex
+}
+
+// NewLocaleProfiles creates a profile registry with a fallback profile used
+// when a target's locale has no match
+func NewLocaleProfiles(fallback LocaleProfile) *LocaleProfiles {
+	return &LocaleProfiles{
+		profiles: make(map[string]LocaleProfile),
+		fallback: fallback,
+		stats:    make(map[string]*LocaleStats),
+	}
+}
+
+// Register adds or replaces the profile for a locale
+func (lp *LocaleProfiles) Register(profile LocaleProfile) {
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+
+	lp.profiles[normalizeLocale(profile.Locale)] = profile
+}
+
+// Resolve returns the profile for a locale, trying the exact locale, then
+// its base language ("es-MX" → "es"), then the fallback profile
+func (lp *LocaleProfiles) Resolve(locale string) LocaleProfile {
+	lp.mu.RLock()
+	defer lp.mu.RUnlock()
+
+	locale = normalizeLocale(locale)
+	if profile, ok := lp.profiles[locale]; ok {
+		return profile
+	}
+	if i := strings.Index(locale, "-"); i > 0 {
+		if profile, ok := lp.profiles[locale[:i]]; ok {
+			return profile
+		}
+	}
+	return lp.fallback
+}
+
+// Apply fills voice, language and script settings on a call config from the
+// profile matching config.Locale. Values already set on the config win.
+func (lp *LocaleProfiles) Apply(config *CallConfig) {
+	profile := lp.Resolve(config.Locale)
+
+	if config.Locale == "" {
+		config.Locale = profile.Locale
+	}
+	if config.VoiceID == "" {
+		config.VoiceID = profile.VoiceID
+	}
+	if config.STTLanguage == "" {
+		config.STTLanguage = profile.STTLanguage
+	}
+	if config.GreetingScript == "" {
+		config.GreetingScript = profile.GreetingScript
+	}
+	if config.SystemPrompt == "" {
+		config.SystemPrompt = profile.SystemPrompt
+	}
+}
+
+// RecordOutcome records a finished call against its locale
+func (lp *LocaleProfiles) RecordOutcome(session *CallSession) {
+	locale := ""
+	if session.Config != nil {
+		locale = normalizeLocale(session.Config.Locale)
+	}
+	if locale == "" {
+		locale = "unknown"
+	}
+
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+
+	stats, ok := lp.stats[locale]
+	if !ok {
+		stats = &LocaleStats{Locale: locale}
+		lp.stats[locale] = stats
+	}
+
+	stats.Attempts++
+	if session.AnsweredAt != nil {
+		stats.Answered++
+		stats.TotalTalkSeconds += int64(session.TalkTimeSeconds)
+	}
+	switch {
+	case session.VoicemailDetected:
+		stats.Voicemail++
+	case session.Status == StatusCompleted:
+		stats.Completed++
+	case session.Status == StatusFailed:
+		stats.Failed++
+	}
+}
+
+// Report returns performance statistics for every locale seen, sorted by locale
+func (lp *LocaleProfiles) Report() []LocaleStats {
+	lp.mu.RLock()
+	defer lp.mu.RUnlock()
+
+	report := make([]LocaleStats, 0, len(lp.stats))
+	for _, stats := range lp.stats {
+		entry := *stats
+		if entry.Attempts > 0 {
+			entry.AnswerRate = float64(entry.Answered) / float64(entry.Attempts)
+		}
+		if entry.Answered > 0 {
+			entry.AverageTalkTime = float64(entry.TotalTalkSeconds) / float64(entry.Answered)
+		}
+		report = append(report, entry)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Locale < report[j].Locale
+	})
+	return report
+}
+
+// normalizeLocale canonicalizes locale tags ("es_mx" → "es-MX")
+func normalizeLocale(locale string) string {
+	locale = strings.TrimSpace(strings.ReplaceAll(locale, "_", "-"))
+	if locale == "" {
+		return ""
+	}
+
+	parts := strings.SplitN(locale, "-", 2)
+	parts[0] = strings.ToLower(parts[0])
+	if len(parts) == 2 {
+		parts[1] = strings.ToUpper(parts[1])
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,79 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ============================================
+// MAX DURATION ENFORCEMENT
+// Hanging up calls that run past CallConfig.MaxDuration
+// ============================================
+
+// trackMaxDuration starts a call's max-duration timer once it is answered
+// and stops it once the call ends. SignalWire also ends calls at the
+// TimeLimit sent when placing them; the timer covers calls it doesn't.
+func (ci *CallInitiator) trackMaxDuration(session *CallSession) {
+	session.mu.RLock()
+	callSID := session.SignalWireCallSID
+	state := session.State
+	answeredAt := session.AnsweredAt
+	limit := 0
+	if session.Config != nil {
+		limit = session.Config.MaxDuration
+	}
+	session.mu.RUnlock()
+
+	if state.IsTerminal() {
+		if timer, ok := ci.durationTimers.LoadAndDelete(callSID); ok {
+			timer.(*time.Timer).Stop()
+		}
+		return
+	}
+	if state != StateAnswered || answeredAt == nil || limit <= 0 {
+		return
+	}
+
+	maxDuration := time.Duration(limit) * time.Second
+	remaining := time.Until(answeredAt.Add(maxDuration))
+	timer := time.AfterFunc(remaining, func() {
+		ci.durationTimers.Delete(callSID)
+		ci.enforceMaxDuration(callSID, maxDuration)
+	})
+	if previous, loaded := ci.durationTimers.Swap(callSID, timer); loaded {
+		previous.(*time.Timer).Stop()
+	}
+}
+
+// enforceMaxDuration marks a call as timed out, publishes EventCallTimeout
+// and hangs it up
+func (ci *CallInitiator) enforceMaxDuration(callSID string, maxDuration time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := ci.updateCall(ctx, callSID, func(session *CallSession) error {
+		if session.State.IsTerminal() {
+			return errNoChange
+		}
+		session.Outcome = OutcomeTimeout
+		session.OutcomeReason = fmt.Sprintf("exceeded max duration of %s", maxDuration)
+		return nil
+	})
+	if session == nil || errors.Is(err, errNoChange) {
+		return
+	}
+	if err != nil {
+		log.Printf("[CallInitiator] Failed to save timeout for call %s: %v", callSID, err)
+	}
+
+	log.Printf("[CallInitiator] Call %s exceeded max duration of %s, hanging up", callSID, maxDuration)
+	publishEvent(ci.events, callEvent(EventCallTimeout, session.Summary()))
+
+	if err := ci.HangupCall(ctx, callSID); err != nil {
+		log.Printf("[CallInitiator] Failed to hang up call %s: %v", callSID, err)
+		ci.errorLog.Record("max-duration", callSID, err)
+	}
+}
@@ -0,0 +1,94 @@
+package telephony
+
+import (
+	"strings"
+)
+
+// ============================================
+// MEDIA CODECS
+// Negotiating the codec of SignalWire media streams
+// ============================================
+
+// mediaCodec converts a stream's media payloads to and from the 16kHz PCM
+// the audio pipeline gets for wideband streams
+type mediaCodec interface {
+	Format() AudioFormat
+	Decode(payload []byte) ([]byte, error)
+	// Encode may buffer, returning zero or more payloads
+	Encode(pcm []byte) ([][]byte, error)
+	Close()
+}
+
+// newMediaCodec returns the codec for a stream's media encoding (e.g.
+// "audio/G722"), or nil for mulaw, which is passed through as is
+func newMediaCodec(encoding string) (mediaCodec, error) {
+	encoding = strings.ToLower(encoding)
+	switch {
+	case isOpusEncoding(encoding):
+		return newOpusCodec()
+	case strings.Contains(encoding, "g722"):
+		return newG722Codec(), nil
+	}
+	return nil, nil
+}
+
+// negotiateMedia sets up the stream's codec from its start event's
+// mediaFormat
+func (cs *SignalWireCallSession) negotiateMedia(msg map[string]interface{}) error {
+	start, _ := msg["start"].(map[string]interface{})
+	mediaFormat, _ := start["mediaFormat"].(map[string]interface{})
+	encoding, _ := mediaFormat["encoding"].(string)
+
+	codec, err := newMediaCodec(encoding)
+	if err != nil || codec == nil {
+		return err
+	}
+
+	cs.codecMu.Lock()
+	defer cs.codecMu.Unlock()
+	cs.MediaFormat = codec.Format()
+	cs.codec = codec
+	return nil
+}
+
+// PipelineFormat is the format of the audio the stream exchanges with the
+// audio pipeline: mulaw as sent by SignalWire, or 16kHz PCM for wideband
+// (Opus, G.722) streams
+func (cs *SignalWireCallSession) PipelineFormat() AudioFormat {
+	cs.codecMu.Lock()
+	defer cs.codecMu.Unlock()
+	if cs.codec != nil {
+		return AudioFormatPCM
+	}
+	return AudioFormatMulaw
+}
+
+// decodeMedia converts an inbound media payload to the pipeline format
+func (cs *SignalWireCallSession) decodeMedia(payload []byte) ([]byte, error) {
+	cs.codecMu.Lock()
+	defer cs.codecMu.Unlock()
+	if cs.codec == nil {
+		return payload, nil
+	}
+	return cs.codec.Decode(payload)
+}
+
+// encodeMedia converts pipeline audio to outbound media payloads
+func (cs *SignalWireCallSession) encodeMedia(audio []byte) ([][]byte, error) {
+	cs.codecMu.Lock()
+	defer cs.codecMu.Unlock()
+	if cs.codec == nil {
+		return [][]byte{audio}, nil
+	}
+	return cs.codec.Encode(audio)
+}
+
+// closeCodecs frees the stream's codec
+func (cs *SignalWireCallSession) closeCodecs() {
+	cs.codecMu.Lock()
+	defer cs.codecMu.Unlock()
+	if cs.codec != nil {
+		cs.codec.Close()
+		cs.codec = nil
+	}
+}
@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS call_sessions (
+    id                          UUID PRIMARY KEY,
+    signalwire_call_sid         TEXT UNIQUE,
+    campaign_id                 UUID,
+    target_id                   UUID,
+    agency_id                   UUID NOT NULL,
+
+    direction                   TEXT NOT NULL DEFAULT 'outbound',
+    from_number                 TEXT NOT NULL,
+    to_number                   TEXT NOT NULL,
+    caller_name                 TEXT NOT NULL DEFAULT '',
+    bridge_session_id           TEXT NOT NULL DEFAULT '',
+
+    status                      TEXT NOT NULL,
+    call_state                  TEXT NOT NULL,
+
+    initiated_at                TIMESTAMPTZ NOT NULL,
+    ringing_at                  TIMESTAMPTZ,
+    answered_at                 TIMESTAMPTZ,
+    completed_at                TIMESTAMPTZ,
+    duration_seconds            INTEGER NOT NULL DEFAULT 0,
+    talk_time_seconds           INTEGER NOT NULL DEFAULT 0,
+    ring_time_seconds           INTEGER NOT NULL DEFAULT 0,
+
+    outcome                     TEXT NOT NULL DEFAULT '',
+    outcome_reason              TEXT NOT NULL DEFAULT '',
+
+    recording_url               TEXT NOT NULL DEFAULT '',
+    recording_duration_seconds  INTEGER NOT NULL DEFAULT 0,
+    transcript_url              TEXT NOT NULL DEFAULT '',
+    transcript_text             TEXT NOT NULL DEFAULT '',
+
+    voicemail_detected          BOOLEAN NOT NULL DEFAULT FALSE,
+    voicemail_message_left      BOOLEAN NOT NULL DEFAULT FALSE,
+
+    audio_quality_score         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    transcription_confidence    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    cost_usd                    DOUBLE PRECISION NOT NULL DEFAULT 0,
+
+    error_code                  TEXT NOT NULL DEFAULT '',
+    error_message               TEXT NOT NULL DEFAULT '',
+
+    metadata                    JSONB,
+    created_at                  TIMESTAMPTZ NOT NULL,
+    updated_at                  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_call_sessions_agency ON call_sessions (agency_id, initiated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_call_sessions_campaign ON call_sessions (campaign_id, initiated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_call_sessions_state ON call_sessions (call_state);
@@ -0,0 +1 @@
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS answered_by;
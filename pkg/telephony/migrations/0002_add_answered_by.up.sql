@@ -0,0 +1 @@
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS answered_by TEXT NOT NULL DEFAULT '';
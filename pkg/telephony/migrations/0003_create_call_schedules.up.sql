@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS call_schedules (
+    id            UUID PRIMARY KEY,
+    config        JSONB NOT NULL,
+    scheduled_at  TIMESTAMPTZ NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_call_schedules_due ON call_schedules (scheduled_at);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS survey_responses (
+    id             UUID PRIMARY KEY,
+    session_id     UUID NOT NULL REFERENCES call_sessions (id) ON DELETE CASCADE,
+    call_sid       TEXT NOT NULL,
+    survey_id      TEXT NOT NULL,
+    question_id    TEXT NOT NULL,
+    question_type  TEXT NOT NULL,
+    value          INTEGER NOT NULL,
+    answered_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_survey_responses_session ON survey_responses (session_id);
+CREATE INDEX IF NOT EXISTS idx_survey_responses_survey ON survey_responses (survey_id, answered_at);
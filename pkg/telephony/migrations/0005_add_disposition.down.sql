@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_call_sessions_disposition;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS disposition_at;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS disposition_notes;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS disposition;
@@ -0,0 +1,5 @@
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS disposition TEXT NOT NULL DEFAULT '';
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS disposition_notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS disposition_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_call_sessions_disposition ON call_sessions (campaign_id, disposition);
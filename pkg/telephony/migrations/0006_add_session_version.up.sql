@@ -0,0 +1 @@
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 0;
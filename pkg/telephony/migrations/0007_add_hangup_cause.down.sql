@@ -0,0 +1,2 @@
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS sip_response_code;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS hangup_cause;
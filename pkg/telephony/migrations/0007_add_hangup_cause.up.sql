@@ -0,0 +1,2 @@
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS hangup_cause TEXT NOT NULL DEFAULT '';
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS sip_response_code INTEGER NOT NULL DEFAULT 0;
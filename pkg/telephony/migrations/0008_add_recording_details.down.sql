@@ -0,0 +1,3 @@
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS recording_stored_at;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS recording_channels;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS recording_sid;
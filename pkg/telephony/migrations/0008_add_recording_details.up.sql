@@ -0,0 +1,3 @@
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS recording_sid TEXT NOT NULL DEFAULT '';
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS recording_channels INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS recording_stored_at TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_call_sessions_attestation;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS verstat;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS attestation;
@@ -0,0 +1,3 @@
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS attestation TEXT NOT NULL DEFAULT '';
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS verstat TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_call_sessions_attestation ON call_sessions (attestation, initiated_at DESC) WHERE attestation <> '';
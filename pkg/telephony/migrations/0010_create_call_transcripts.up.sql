@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS call_transcripts (
+    id          UUID PRIMARY KEY,
+    session_id  UUID NOT NULL REFERENCES call_sessions (id) ON DELETE CASCADE,
+    call_sid    TEXT NOT NULL,
+    speaker     TEXT NOT NULL,
+    text        TEXT NOT NULL,
+    start_ms    BIGINT NOT NULL,
+    end_ms      BIGINT NOT NULL,
+    confidence  DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_call_transcripts_session ON call_transcripts (session_id, start_ms);
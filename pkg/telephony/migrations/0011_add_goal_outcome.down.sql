@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_call_sessions_goal_outcome;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS goal_outcome;
@@ -0,0 +1,2 @@
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS goal_outcome JSONB;
+CREATE INDEX IF NOT EXISTS idx_call_sessions_goal_outcome ON call_sessions ((goal_outcome->>'goal'), initiated_at DESC) WHERE goal_outcome IS NOT NULL;
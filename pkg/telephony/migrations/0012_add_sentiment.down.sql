@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_call_sessions_sentiment_min;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS sentiment;
+ALTER TABLE call_transcripts DROP COLUMN IF EXISTS emotion;
+ALTER TABLE call_transcripts DROP COLUMN IF EXISTS sentiment;
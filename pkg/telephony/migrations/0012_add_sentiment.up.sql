@@ -0,0 +1,4 @@
+ALTER TABLE call_transcripts ADD COLUMN IF NOT EXISTS sentiment DOUBLE PRECISION;
+ALTER TABLE call_transcripts ADD COLUMN IF NOT EXISTS emotion TEXT;
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS sentiment JSONB;
+CREATE INDEX IF NOT EXISTS idx_call_sessions_sentiment_min ON call_sessions (((sentiment->>'min')::double precision)) WHERE sentiment IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE call_schedules DROP COLUMN IF EXISTS attempts;
+ALTER TABLE call_schedules DROP COLUMN IF EXISTS claimed_at;
@@ -0,0 +1,2 @@
+ALTER TABLE call_schedules ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ;
+ALTER TABLE call_schedules ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
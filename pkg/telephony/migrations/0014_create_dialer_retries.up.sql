@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS dialer_retries (
+    target_id UUID PRIMARY KEY,
+    target    JSONB NOT NULL,
+    outcome   TEXT NOT NULL,
+    due_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_dialer_retries_due ON dialer_retries (due_at);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS dialer_queue (
+    id          UUID PRIMARY KEY,
+    target      JSONB NOT NULL,
+    priority    INT NOT NULL,
+    not_before  TIMESTAMPTZ NOT NULL,
+    failures    INT NOT NULL DEFAULT 0,
+    last_error  TEXT,
+    enqueued_at TIMESTAMPTZ NOT NULL,
+    dead        BOOLEAN NOT NULL DEFAULT FALSE,
+    claimed_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_dialer_queue_ready ON dialer_queue (dead, priority DESC, not_before);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS compliance_audit_log (
+    id          UUID PRIMARY KEY,
+    action      TEXT NOT NULL,
+    rule        TEXT,
+    reason      TEXT,
+    phone       TEXT NOT NULL,
+    target_id   UUID,
+    campaign_id UUID,
+    call_sid    TEXT,
+    details     JSONB,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_compliance_audit_log_phone ON compliance_audit_log (phone, created_at);
+CREATE INDEX IF NOT EXISTS idx_compliance_audit_log_campaign ON compliance_audit_log (campaign_id, created_at);
@@ -0,0 +1 @@
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS amd_policy JSONB;
@@ -0,0 +1,2 @@
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS last_event_at;
+ALTER TABLE call_sessions DROP COLUMN IF EXISTS last_sequence;
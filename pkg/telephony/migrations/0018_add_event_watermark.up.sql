@@ -0,0 +1,2 @@
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS last_sequence INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE call_sessions ADD COLUMN IF NOT EXISTS last_event_at TIMESTAMPTZ;
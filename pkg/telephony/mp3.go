@@ -0,0 +1,233 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hajimehoshi/go-mp3"
+)
+
+// ============================================
+// MP3 DECODING
+// MP3 as returned by TTS APIs, decoded in pure Go as it streams in
+// ============================================
+
+// AudioFormatMP3 is MPEG-1 or MPEG-2 Layer III audio, decoded to mono
+// 16-bit PCM at its own sample rate
+var AudioFormatMP3 = AudioFormat{Channels: 1, Encoding: "mp3"}
+
+// ErrInvalidMP3 is returned for data with no decodable MP3 frames
+var ErrInvalidMP3 = errors.New("invalid MP3 data")
+
+// Layer III bitrates (kbps) by bitrate index, for MPEG-1 and MPEG-2
+var (
+	mp3Bitrates1 = [15]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320}
+	mp3Bitrates2 = [15]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160}
+	mp3Rates1    = [3]int{44100, 48000, 32000}
+	mp3Rates2    = [3]int{22050, 24000, 16000}
+)
+
+// DecodeMP3 decodes MP3 data to mono 16-bit PCM, returning the PCM and its
+// format. ID3 tags are skipped. MPEG-2.5 (rates below 16kHz) and
+// free-format MP3 aren't supported.
+func DecodeMP3(data []byte) ([]byte, AudioFormat, error) {
+	var d mp3Decoder
+	pcm, format, err := d.decode(data)
+	if err != nil {
+		return nil, AudioFormat{}, err
+	}
+	if d.dec == nil {
+		return nil, AudioFormat{}, ErrInvalidMP3
+	}
+	return pcm, format, nil
+}
+
+// mp3Decoder decodes MP3 received in arbitrary chunks. It cuts whole frames
+// from the input and hands them to go-mp3 one at a time, so decoding never
+// waits on a frame that hasn't fully arrived.
+type mp3Decoder struct {
+	input []byte // Received, not yet cut into frames
+	frame []byte // Whole frame go-mp3 reads next
+	dec   *mp3.Decoder
+	rate  rateLock
+	pcm   [4608]byte // One frame of go-mp3 output: 1152 stereo 16-bit samples
+}
+
+// Read serves go-mp3 the frame cut for it
+func (d *mp3Decoder) Read(p []byte) (int, error) {
+	if len(d.frame) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, d.frame)
+	d.frame = d.frame[n:]
+	return n, nil
+}
+
+// decode returns mono PCM for the whole frames received so far, holding
+// back a partial frame for the next chunk
+func (d *mp3Decoder) decode(chunk []byte) ([]byte, AudioFormat, error) {
+	d.input = append(d.input, chunk...)
+
+	var pcm []byte
+	for {
+		skip, size, rate, err := nextMP3Frame(d.input, d.dec == nil)
+		d.input = d.input[skip:]
+		if err != nil {
+			return nil, AudioFormat{}, err
+		}
+		if size == 0 {
+			break
+		}
+		d.frame, d.input = d.input[:size], d.input[size:]
+
+		if d.dec == nil {
+			// go-mp3 decodes the first frame on creation
+			dec, err := mp3.NewDecoder(d)
+			if err != nil {
+				return nil, AudioFormat{}, fmt.Errorf("failed to decode MP3: %w", err)
+			}
+			d.dec = dec
+		}
+		n, err := d.dec.Read(d.pcm[:])
+		if err != nil {
+			return nil, AudioFormat{}, fmt.Errorf("failed to decode MP3: %w", err)
+		}
+
+		// go-mp3 always writes stereo
+		mono := downmixPCM16(d.pcm[:n], 2)
+		converted := d.rate.convert(mono, rate)
+		pcm = append(pcm, converted...)
+		if !sameBuffer(converted, mono) {
+			ReleaseAudioBuffer(converted)
+		}
+	}
+
+	// Keep the unread tail from pinning a large buffer
+	if cap(d.input) > 1<<16 && len(d.input) < cap(d.input)/4 {
+		d.input = append([]byte(nil), d.input...)
+	}
+	return pcm, d.rate.format(), nil
+}
+
+// nextMP3Frame finds the next frame in data, returning the bytes to skip
+// before it (ID3 tags, junk), and its size and sample rate; size is 0 when
+// no whole frame has arrived yet. Headers go-mp3 can't decode are errors
+// when strict (before the first frame), and otherwise skipped as junk.
+func nextMP3Frame(data []byte, strict bool) (skip, size, rate int, err error) {
+	for i := 0; ; {
+		rest := data[i:]
+		switch {
+		case len(rest) < 4:
+			return i, 0, 0, nil
+
+		case string(rest[:3]) == "ID3":
+			if len(rest) < 10 {
+				return i, 0, 0, nil
+			}
+			tag := 10 + (int(rest[6]&0x7F)<<21 | int(rest[7]&0x7F)<<14 | int(rest[8]&0x7F)<<7 | int(rest[9]&0x7F))
+			if rest[5]&0x10 != 0 {
+				tag += 10 // Footer
+			}
+			if len(rest) < tag {
+				return i, 0, 0, nil
+			}
+			i += tag
+
+		case string(rest[:3]) == "TAG":
+			if len(rest) < 128 {
+				return i, 0, 0, nil
+			}
+			i += 128
+
+		case rest[0] == 0xFF && rest[1]&0xE0 == 0xE0:
+			size, rate, err := mp3FrameSize(binary.BigEndian.Uint32(rest))
+			if err != nil {
+				if strict {
+					return i, 0, 0, err
+				}
+				i++
+				continue
+			}
+			if size == 0 {
+				i++ // Not a frame header
+				continue
+			}
+			if len(rest) < size {
+				return i, 0, 0, nil
+			}
+			return i, size, rate, nil
+
+		default:
+			i++
+		}
+	}
+}
+
+// mp3FrameSize returns the size and sample rate of the Layer III frame
+// with header, a size of 0 if header isn't a valid frame header, or an
+// error for frames go-mp3 doesn't support
+func mp3FrameSize(header uint32) (size, rate int, err error) {
+	version := header >> 19 & 3 // 3: MPEG-1, 2: MPEG-2, 0: MPEG-2.5
+	layer := header >> 17 & 3   // 1: Layer III
+	bitrateIndex := header >> 12 & 15
+	rateIndex := header >> 10 & 3
+	padding := int(header >> 9 & 1)
+
+	if version == 1 || layer == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return 0, 0, nil
+	}
+	switch {
+	case layer != 1:
+		return 0, 0, fmt.Errorf("unsupported MP3: only Layer III is supported")
+	case version == 0:
+		return 0, 0, fmt.Errorf("unsupported MP3: MPEG-2.5 (sample rates below 16kHz) is not supported")
+	case bitrateIndex == 0:
+		return 0, 0, fmt.Errorf("unsupported MP3: free-format bitrate is not supported")
+	}
+
+	if version == 3 {
+		rate = mp3Rates1[rateIndex]
+		return 144000*mp3Bitrates1[bitrateIndex]/rate + padding, rate, nil
+	}
+	rate = mp3Rates2[rateIndex]
+	return 72000*mp3Bitrates2[bitrateIndex]/rate + padding, rate, nil
+}
+
+// rateLock keeps decoded audio at the first stream's sample rate, so
+// concatenated streams (e.g. one file per TTS sentence) at other rates
+// arrive as one format
+type rateLock struct {
+	rate      int
+	resampler *Resampler
+}
+
+// convert returns mono 16-bit PCM at rate at the locked rate
+func (l *rateLock) convert(pcm []byte, rate int) []byte {
+	if l.rate == 0 {
+		l.rate = rate
+	}
+	if rate == l.rate {
+		return pcm
+	}
+	if l.resampler == nil || l.resampler.fromRate != rate {
+		resampler, err := NewResampler(rate, l.rate, ResampleSinc)
+		if err != nil {
+			return nil
+		}
+		l.resampler = resampler
+	}
+	out, err := l.resampler.Process(pcm)
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// format returns the format of converted audio
+func (l *rateLock) format() AudioFormat {
+	return AudioFormat{SampleRate: l.rate, Channels: 1, Encoding: AudioFormatPCM.Encoding, BitDepth: 16}
+}
+
+func (d *mp3Decoder) close() {}
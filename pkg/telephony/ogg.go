@@ -0,0 +1,277 @@
+package telephony
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jfreymuth/vorbis"
+)
+
+// ============================================
+// OGG DECODING
+// Ogg Vorbis (pure Go) and Ogg Opus (with Opus support) as returned by TTS
+// APIs, decoded as they stream in
+// ============================================
+
+// AudioFormatOgg is Ogg Vorbis or Ogg Opus audio, decoded to mono 16-bit
+// PCM at the stream's sample rate (48kHz for Opus)
+var AudioFormatOgg = AudioFormat{Channels: 1, Encoding: "ogg"}
+
+// ErrInvalidOgg is returned for data with no decodable Ogg stream
+var ErrInvalidOgg = errors.New("invalid Ogg data")
+
+const (
+	oggHeaderSize   = 27
+	oggContinued    = 0x01 // Page starts with the rest of the previous page's packet
+	oggFirstPage    = 0x02 // Beginning of a logical stream
+	oggLastPage     = 0x04 // End of a logical stream
+	opusDecoderRate = 48000
+)
+
+// oggCRCTable is the Ogg page checksum table (CRC-32, polynomial
+// 0x04C11DB7, unreflected)
+var oggCRCTable = func() (table [256]uint32) {
+	for i := range table {
+		crc := uint32(i) << 24
+		for range 8 {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+// DecodeOgg decodes Ogg Vorbis or Ogg Opus data to mono 16-bit PCM,
+// returning the PCM and its format. Opus needs a build with Opus support
+// (see OpusAvailable).
+func DecodeOgg(data []byte) ([]byte, AudioFormat, error) {
+	var d oggDecoder
+	pcm, format, err := d.decode(data)
+	if err != nil {
+		return nil, AudioFormat{}, err
+	}
+	if d.rate.rate == 0 {
+		return nil, AudioFormat{}, ErrInvalidOgg
+	}
+	return pcm, format, nil
+}
+
+// oggDecoder decodes an Ogg stream received in arbitrary chunks, page by
+// page. Chained streams (one file after another) are decoded in turn.
+type oggDecoder struct {
+	input  []byte // Received, not yet read as pages
+	serial uint32 // Logical stream being decoded
+	packet []byte // Packet continuing onto the next page
+	rate   rateLock
+
+	// Codec of the current logical stream
+	vorbis   *vorbis.Decoder
+	opus     *OpusDecoder
+	preSkip  int   // Opus samples (at 48kHz) to discard from the start
+	position int64 // Samples decoded, to compare with granule positions
+}
+
+// decode returns mono PCM for the whole pages received so far, holding
+// back a partial page for the next chunk
+func (d *oggDecoder) decode(chunk []byte) ([]byte, AudioFormat, error) {
+	d.input = append(d.input, chunk...)
+
+	var pcm []byte
+	for {
+		page, ok := d.nextPage()
+		if !ok {
+			break
+		}
+		flags := page[5]
+		serial := binary.LittleEndian.Uint32(page[14:])
+		if flags&oggFirstPage != 0 {
+			d.startStream(serial)
+		} else if serial != d.serial {
+			continue // Another multiplexed stream
+		}
+		if flags&oggContinued == 0 {
+			d.packet = d.packet[:0]
+		}
+
+		// Split the page body into packets by its lacing values
+		segments := int(page[26])
+		lacings := page[oggHeaderSize : oggHeaderSize+segments]
+		body := page[oggHeaderSize+segments:]
+		for i, lacing := range lacings {
+			d.packet = append(d.packet, body[:lacing]...)
+			body = body[lacing:]
+			if lacing == 255 {
+				continue // The packet goes on
+			}
+			audio, rate, err := d.decodePacket(d.packet)
+			d.packet = d.packet[:0]
+			if err != nil {
+				return nil, AudioFormat{}, err
+			}
+			if len(audio) == 0 {
+				continue
+			}
+
+			// The last page's granule position marks where the audio ends,
+			// within its last packet
+			d.position += int64(len(audio) / 2)
+			granule := int64(binary.LittleEndian.Uint64(page[6:]))
+			if flags&oggLastPage != 0 && i == len(lacings)-1 && granule >= 0 && d.position > granule {
+				audio = audio[:max(len(audio)-2*int(d.position-granule), 0)]
+			}
+			if d.opus != nil {
+				skip := min(d.preSkip, len(audio)/2)
+				audio, d.preSkip = audio[2*skip:], d.preSkip-skip
+			}
+			converted := d.rate.convert(audio, rate)
+			pcm = append(pcm, converted...)
+			if !sameBuffer(converted, audio) {
+				ReleaseAudioBuffer(converted)
+			}
+		}
+	}
+
+	if cap(d.input) > 1<<16 && len(d.input) < cap(d.input)/4 {
+		d.input = append([]byte(nil), d.input...)
+	}
+	return pcm, d.rate.format(), nil
+}
+
+// nextPage cuts the next whole page with a valid checksum from the input,
+// skipping anything else
+func (d *oggDecoder) nextPage() ([]byte, bool) {
+	for {
+		start := bytes.Index(d.input, []byte("OggS"))
+		if start < 0 {
+			d.input = d.input[max(len(d.input)-3, 0):] // Keep a partial capture pattern
+			return nil, false
+		}
+		d.input = d.input[start:]
+		if len(d.input) < oggHeaderSize || len(d.input) < oggHeaderSize+int(d.input[26]) {
+			return nil, false
+		}
+		segments := int(d.input[26])
+		size := oggHeaderSize + segments
+		for _, lacing := range d.input[oggHeaderSize : oggHeaderSize+segments] {
+			size += int(lacing)
+		}
+		if len(d.input) < size {
+			return nil, false
+		}
+
+		page := d.input[:size]
+		if oggChecksum(page) != binary.LittleEndian.Uint32(page[22:]) {
+			d.input = d.input[1:] // Not a page after all; look further on
+			continue
+		}
+		d.input = d.input[size:]
+		return page, true
+	}
+}
+
+// oggChecksum computes a page's CRC, with its own checksum field as zero
+func oggChecksum(page []byte) uint32 {
+	var crc uint32
+	for i, b := range page {
+		if i >= 22 && i < 26 {
+			b = 0
+		}
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+func (d *oggDecoder) close() {
+	d.startStream(0)
+}
+
+// startStream resets the codec for a new logical stream
+func (d *oggDecoder) startStream(serial uint32) {
+	d.serial = serial
+	d.packet = d.packet[:0]
+	d.position = 0
+	d.vorbis = nil
+	if d.opus != nil {
+		d.opus.Close()
+		d.opus = nil
+	}
+}
+
+// decodePacket decodes one packet of the current stream to mono PCM,
+// returning it and its sample rate, and identifies the codec from the
+// stream's first packet
+func (d *oggDecoder) decodePacket(packet []byte) ([]byte, int, error) {
+	switch {
+	case d.vorbis == nil && d.opus == nil:
+		return nil, 0, d.identify(packet)
+
+	case d.vorbis != nil:
+		if !d.vorbis.HeadersRead() {
+			if err := d.vorbis.ReadHeader(packet); err != nil {
+				return nil, 0, fmt.Errorf("failed to read Vorbis header: %w", err)
+			}
+			return nil, 0, nil
+		}
+		samples, err := d.vorbis.Decode(packet)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decode Vorbis: %w", err)
+		}
+		return mixFloat32(samples, d.vorbis.Channels()), d.vorbis.SampleRate(), nil
+
+	default:
+		if bytes.HasPrefix(packet, []byte("OpusTags")) {
+			return nil, 0, nil
+		}
+		pcm, err := d.opus.Decode(packet)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decode Opus: %w", err)
+		}
+		return pcm, opusDecoderRate, nil
+	}
+}
+
+// identify sets up the codec from a stream's first packet
+func (d *oggDecoder) identify(packet []byte) error {
+	switch {
+	case bytes.HasPrefix(packet, []byte("\x01vorbis")):
+		d.vorbis = &vorbis.Decoder{}
+		if err := d.vorbis.ReadHeader(packet); err != nil {
+			return fmt.Errorf("failed to read Vorbis header: %w", err)
+		}
+		return nil
+
+	case bytes.HasPrefix(packet, []byte("OpusHead")) && len(packet) >= 19:
+		// Opus decodes any stream to mono
+		opus, err := NewOpusDecoder(opusDecoderRate, 1)
+		if err != nil {
+			return err
+		}
+		d.opus = opus
+		d.preSkip = int(binary.LittleEndian.Uint16(packet[10:]))
+		return nil
+	}
+	return fmt.Errorf("unsupported Ogg codec")
+}
+
+// mixFloat32 mixes interleaved float samples down to mono 16-bit PCM
+func mixFloat32(samples []float32, channels int) []byte {
+	channels = max(channels, 1)
+	frames := len(samples) / channels
+	out := make([]byte, 2*frames)
+	for i := 0; i < frames; i++ {
+		sum := float32(0)
+		for ch := 0; ch < channels; ch++ {
+			sum += samples[i*channels+ch]
+		}
+		v := float64(sum) / float64(channels) * math.MaxInt16
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(clampInt16(v)))
+	}
+	return out
+}
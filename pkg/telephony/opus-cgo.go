@@ -0,0 +1,161 @@
+//go:build opus && cgo
+
+package telephony
+
+/*
+#cgo pkg-config: opus
+#include <opus.h>
+
+static int opus_set_bitrate(OpusEncoder *enc, opus_int32 bitrate) {
+	return opus_encoder_ctl(enc, OPUS_SET_BITRATE(bitrate));
+}
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// opusAvailable is true in builds with the opus tag
+const opusAvailable = true
+
+// opusError converts a libopus error code
+func opusError(code C.int) error {
+	return fmt.Errorf("opus: %s", C.GoString(C.opus_strerror(code)))
+}
+
+// OpusEncoder encodes 16-bit PCM frames to Opus packets. It is safe for
+// concurrent use.
+type OpusEncoder struct {
+	enc        *C.OpusEncoder
+	channels   int
+	frameBytes int
+	mu         sync.Mutex
+}
+
+// NewOpusEncoder creates a VoIP-tuned encoder for little-endian 16-bit PCM
+// at sampleRate (8000, 12000, 16000, 24000 or 48000). bitrate is in bits/s
+// (0 lets libopus choose).
+func NewOpusEncoder(sampleRate, channels, bitrate int) (*OpusEncoder, error) {
+	var code C.int
+	enc := C.opus_encoder_create(C.opus_int32(sampleRate), C.int(channels), C.OPUS_APPLICATION_VOIP, &code)
+	if code != C.OPUS_OK {
+		return nil, opusError(code)
+	}
+	if bitrate > 0 {
+		if code := C.opus_set_bitrate(enc, C.opus_int32(bitrate)); code != C.OPUS_OK {
+			C.opus_encoder_destroy(enc)
+			return nil, opusError(code)
+		}
+	}
+	return &OpusEncoder{
+		enc:        enc,
+		channels:   channels,
+		frameBytes: opusFrameBytes(sampleRate, channels),
+	}, nil
+}
+
+// Encode encodes exactly one frame (FrameBytes of PCM) to a packet
+func (e *OpusEncoder) Encode(pcm []byte) ([]byte, error) {
+	if len(pcm) != e.frameBytes {
+		return nil, fmt.Errorf("opus: frame is %d bytes, want %d", len(pcm), e.frameBytes)
+	}
+
+	samples := make([]C.opus_int16, len(pcm)/2)
+	for i := range samples {
+		samples[i] = C.opus_int16(int16(binary.LittleEndian.Uint16(pcm[i*2:])))
+	}
+	packet := make([]byte, opusMaxPacket)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.enc == nil {
+		return nil, fmt.Errorf("opus: encoder closed")
+	}
+
+	n := C.opus_encode(e.enc, &samples[0], C.int(len(samples)/e.channels),
+		(*C.uchar)(unsafe.Pointer(&packet[0])), C.opus_int32(len(packet)))
+	if n < 0 {
+		return nil, opusError(C.int(n))
+	}
+	return packet[:n], nil
+}
+
+// FrameBytes is the PCM frame size Encode takes
+func (e *OpusEncoder) FrameBytes() int {
+	return e.frameBytes
+}
+
+// Close frees the encoder
+func (e *OpusEncoder) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.enc != nil {
+		C.opus_encoder_destroy(e.enc)
+		e.enc = nil
+	}
+}
+
+// OpusDecoder decodes Opus packets to 16-bit PCM. It is safe for
+// concurrent use.
+type OpusDecoder struct {
+	dec        *C.OpusDecoder
+	channels   int
+	maxSamples int
+	mu         sync.Mutex
+}
+
+// NewOpusDecoder creates a decoder producing little-endian 16-bit PCM at
+// sampleRate, whatever rate the packets were encoded at
+func NewOpusDecoder(sampleRate, channels int) (*OpusDecoder, error) {
+	var code C.int
+	dec := C.opus_decoder_create(C.opus_int32(sampleRate), C.int(channels), &code)
+	if code != C.OPUS_OK {
+		return nil, opusError(code)
+	}
+	return &OpusDecoder{
+		dec:      dec,
+		channels: channels,
+		// Packets hold at most 120ms of audio
+		maxSamples: sampleRate * 120 / 1000,
+	}, nil
+}
+
+// Decode decodes one packet
+func (d *OpusDecoder) Decode(packet []byte) ([]byte, error) {
+	if len(packet) == 0 {
+		return nil, fmt.Errorf("opus: empty packet")
+	}
+	samples := make([]C.opus_int16, d.maxSamples*d.channels)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dec == nil {
+		return nil, fmt.Errorf("opus: decoder closed")
+	}
+
+	n := C.opus_decode(d.dec, (*C.uchar)(unsafe.Pointer(&packet[0])), C.opus_int32(len(packet)),
+		&samples[0], C.int(d.maxSamples), 0)
+	if n < 0 {
+		return nil, opusError(n)
+	}
+
+	pcm := make([]byte, int(n)*d.channels*2)
+	for i := 0; i < int(n)*d.channels; i++ {
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(int16(samples[i])))
+	}
+	return pcm, nil
+}
+
+// Close frees the decoder
+func (d *OpusDecoder) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dec != nil {
+		C.opus_decoder_destroy(d.dec)
+		d.dec = nil
+	}
+}
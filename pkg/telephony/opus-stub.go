@@ -0,0 +1,43 @@
+//go:build !opus || !cgo
+
+package telephony
+
+// opusAvailable is false without the opus build tag
+const opusAvailable = false
+
+// OpusEncoder encodes 16-bit PCM frames to Opus packets
+type OpusEncoder struct{}
+
+// NewOpusEncoder returns ErrOpusUnavailable in builds without Opus support
+func NewOpusEncoder(sampleRate, channels, bitrate int) (*OpusEncoder, error) {
+	return nil, ErrOpusUnavailable
+}
+
+// Encode returns ErrOpusUnavailable
+func (e *OpusEncoder) Encode(pcm []byte) ([]byte, error) {
+	return nil, ErrOpusUnavailable
+}
+
+// FrameBytes is the PCM frame size Encode takes
+func (e *OpusEncoder) FrameBytes() int {
+	return 0
+}
+
+// Close is a no-op
+func (e *OpusEncoder) Close() {}
+
+// OpusDecoder decodes Opus packets to 16-bit PCM
+type OpusDecoder struct{}
+
+// NewOpusDecoder returns ErrOpusUnavailable in builds without Opus support
+func NewOpusDecoder(sampleRate, channels int) (*OpusDecoder, error) {
+	return nil, ErrOpusUnavailable
+}
+
+// Decode returns ErrOpusUnavailable
+func (d *OpusDecoder) Decode(packet []byte) ([]byte, error) {
+	return nil, ErrOpusUnavailable
+}
+
+// Close is a no-op
+func (d *OpusDecoder) Close() {}
@@ -0,0 +1,95 @@
+package telephony
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ============================================
+// OPUS CODEC
+// Opus encode/decode for media streams (libopus, built with -tags opus)
+// ============================================
+
+// AudioFormatOpus is Opus-coded audio, decoded to and encoded from
+// AudioFormatPCM
+var AudioFormatOpus = AudioFormat{SampleRate: 48000, Channels: 1, Encoding: "opus"}
+
+// StreamCodecOpus is the <Stream> codec requesting Opus media
+const StreamCodecOpus = "OPUS@48000h"
+
+// OpusFrameDuration is the length of audio in each encoded packet
+const OpusFrameDuration = 20 * time.Millisecond
+
+// opusMaxPacket is the largest encoded packet accepted (RFC 6716 §3.4)
+const opusMaxPacket = 1275
+
+// ErrOpusUnavailable is returned when the package was built without Opus
+// support. Build with -tags opus (cgo and libopus required).
+var ErrOpusUnavailable = errors.New("opus support not built (build with -tags opus)")
+
+// OpusAvailable reports whether Opus support was built in
+func OpusAvailable() bool {
+	return opusAvailable
+}
+
+// isOpusEncoding reports whether a stream's media encoding (e.g.
+// "audio/opus") is Opus
+func isOpusEncoding(encoding string) bool {
+	return strings.Contains(strings.ToLower(encoding), "opus")
+}
+
+// opusFrameBytes is the size of one frame of 16-bit PCM
+func opusFrameBytes(sampleRate, channels int) int {
+	return sampleRate * int(OpusFrameDuration/time.Millisecond) / 1000 * channels * 2
+}
+
+// opusCodec adapts an Opus encoder and decoder to a stream. Opus audio is
+// exchanged with the pipeline as 16kHz PCM.
+type opusCodec struct {
+	enc     *OpusEncoder
+	dec     *OpusDecoder
+	pending []byte // PCM short of a full frame
+}
+
+func newOpusCodec() (*opusCodec, error) {
+	dec, err := NewOpusDecoder(AudioFormatPCM.SampleRate, AudioFormatPCM.Channels)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := NewOpusEncoder(AudioFormatPCM.SampleRate, AudioFormatPCM.Channels, 0)
+	if err != nil {
+		dec.Close()
+		return nil, err
+	}
+	return &opusCodec{enc: enc, dec: dec}, nil
+}
+
+func (c *opusCodec) Format() AudioFormat { return AudioFormatOpus }
+
+func (c *opusCodec) Decode(payload []byte) ([]byte, error) {
+	return c.dec.Decode(payload)
+}
+
+// Encode cuts PCM into frames, holding back any remainder for the next call
+func (c *opusCodec) Encode(pcm []byte) ([][]byte, error) {
+	c.pending = append(c.pending, pcm...)
+	frameBytes := c.enc.FrameBytes()
+
+	var packets [][]byte
+	for len(c.pending) >= frameBytes {
+		packet, err := c.enc.Encode(c.pending[:frameBytes])
+		if err != nil {
+			return packets, err
+		}
+		packets = append(packets, packet)
+		c.pending = c.pending[frameBytes:]
+	}
+	c.pending = append([]byte(nil), c.pending...)
+	return packets, nil
+}
+
+func (c *opusCodec) Close() {
+	c.enc.Close()
+	c.dec.Close()
+}
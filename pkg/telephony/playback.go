@@ -0,0 +1,321 @@
+package telephony
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ============================================
+// AUDIO PLAYBACK
+// Playing audio files into a live call: hold music, disclosures, messages
+// ============================================
+
+const (
+	playbackFrame = 20 * time.Millisecond // Chunk size sent toward the caller
+	playbackLead  = 40 * time.Millisecond // How far ahead of real time chunks are queued
+
+	playbackRead      = 4096 // Read size for compressed audio
+	playbackReadAhead = 8    // Decoded pieces waiting to be queued
+)
+
+// ErrPlaybackStopped is returned by PlayFile and PlayAudio when playback is
+// stopped by StopPlayback or replaced by another playback
+var ErrPlaybackStopped = errors.New("playback stopped")
+
+// ErrNoPrimaryStream is returned when a session has no stream to play to
+var ErrNoPrimaryStream = errors.New("session has no primary stream")
+
+// PlayFile plays an audio file to the caller; see PlayAudio
+func (s *BridgeSession) PlayFile(ctx context.Context, path string, format AudioFormat) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer f.Close()
+	return s.PlayAudio(ctx, f, format)
+}
+
+// PlayAudio plays audio to the caller through the AI → phone path, paced in
+// real time, and returns once it has played. Audio is decoded as it is
+// read, so playback starts with the first of it: a streaming TTS response
+// plays while it is still being synthesized. format describes the data: a
+// zero format detects WAV, MP3 or Ogg from its header, and raw audio needs
+// its format given. Audio is converted to the stream's format; stereo is
+// mixed down.
+//
+// Cancel ctx to cut playback short, e.g. on caller speech (barge-in);
+// PlayAudio then returns ctx.Err(). Starting another playback or calling
+// StopPlayback stops this one with ErrPlaybackStopped. Audio the AI sends
+// meanwhile is interleaved, so pause TTS while playing.
+func (s *BridgeSession) PlayAudio(ctx context.Context, audio io.Reader, format AudioFormat) error {
+	s.mu.RLock()
+	swSession := s.SignalWireSession
+	s.mu.RUnlock()
+	if swSession == nil {
+		return ErrNoPrimaryStream
+	}
+	target := swSession.PipelineFormat()
+
+	decoder, err := newPlaybackDecoder(audio, format, target)
+	if err != nil {
+		return err
+	}
+
+	// Replace any playback in progress
+	playCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	done := make(chan struct{})
+	defer close(done)
+
+	s.mu.Lock()
+	previous, previousDone := s.stopPlayback, s.playbackDone
+	s.stopPlayback, s.playbackDone = stop, done
+	s.mu.Unlock()
+	if previous != nil {
+		previous()
+		<-previousDone
+	}
+	defer func() {
+		s.mu.Lock()
+		if s.playbackDone == done {
+			s.stopPlayback, s.playbackDone = nil, nil
+		}
+		s.mu.Unlock()
+	}()
+
+	return s.pacePlayback(ctx, playCtx, decoder, target)
+}
+
+// StopPlayback stops the session's playback, if any
+func (s *BridgeSession) StopPlayback() {
+	s.mu.RLock()
+	stop := s.stopPlayback
+	s.mu.RUnlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// playbackPiece is decoded audio, or the error that ended decoding
+type playbackPiece struct {
+	audio []byte
+	err   error
+}
+
+// pacePlayback queues audio in frames as it is decoded, keeping
+// playbackLead ahead of real time, then waits for the last frame to play
+func (s *BridgeSession) pacePlayback(ctx, playCtx context.Context, decoder *playbackDecoder, format AudioFormat) error {
+	frame := int(int64(format.SampleRate) * int64(playbackFrame) / int64(time.Second) * int64(format.BitDepth/8))
+
+	stopped := func() error {
+		if s.ctx.Err() != nil {
+			return fmt.Errorf("session closed")
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return ErrPlaybackStopped
+	}
+	wait := func(until time.Time) error {
+		d := time.Until(until)
+		if d <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return nil
+		case <-s.ctx.Done():
+			return stopped()
+		case <-playCtx.Done():
+			return stopped()
+		}
+	}
+
+	// Read ahead of playback, so a slow source is waited on while earlier
+	// audio plays
+	pieces := make(chan playbackPiece, playbackReadAhead)
+	go func() {
+		defer close(pieces)
+		for {
+			audio, err := decoder.next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			select {
+			case pieces <- playbackPiece{audio: audio, err: err}:
+			case <-playCtx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// The ring doesn't keep or release what's pushed, so frames can be
+	// slices of one buffer
+	var start time.Time
+	sent := 0
+	for {
+		var piece playbackPiece
+		var ok bool
+		select {
+		case piece, ok = <-pieces:
+		case <-s.ctx.Done():
+			return stopped()
+		case <-playCtx.Done():
+			return stopped()
+		}
+		if !ok {
+			break
+		}
+		if piece.err != nil {
+			return piece.err
+		}
+
+		// Start with the first audio. If the source fell behind and
+		// playback ran dry, carry on from now rather than catch up.
+		if now := time.Now(); start.IsZero() || now.After(start.Add(audioDuration(format, sent))) {
+			start = now.Add(-audioDuration(format, sent))
+		}
+		for pos := 0; pos < len(piece.audio); {
+			if err := wait(start.Add(audioDuration(format, sent) - playbackLead)); err != nil {
+				return err
+			}
+			chunk := piece.audio[pos:min(pos+frame, len(piece.audio))]
+			s.playback.Push(playCtx, chunk)
+			pos += len(chunk)
+			sent += len(chunk)
+		}
+	}
+	return wait(start.Add(audioDuration(format, sent)))
+}
+
+// playbackDecoder reads audio for playback a piece at a time, converting
+// each to the stream's format as it arrives
+type playbackDecoder struct {
+	r         io.Reader
+	format    AudioFormat // Of the audio as read (after WAV decoding)
+	wav       *wavFormat  // How WAV stores its samples; nil for other audio
+	size      int         // Bytes read at a time
+	frame     int         // Bytes in a sample of every channel of raw audio
+	target    AudioFormat
+	converter *AudioConverter
+}
+
+// newPlaybackDecoder starts decoding audio in format (detected from a WAV,
+// MP3 or Ogg header when zero) to mono audio in target
+func newPlaybackDecoder(r io.Reader, format, target AudioFormat) (*playbackDecoder, error) {
+	if format == (AudioFormat{}) {
+		buffered := bufio.NewReader(r)
+		header, _ := buffered.Peek(12)
+		detected, err := DetectAudioFormat(header)
+		if err != nil {
+			return nil, fmt.Errorf("unknown audio format, pass one for raw audio: %w", err)
+		}
+		r, format = buffered, detected
+	}
+
+	d := &playbackDecoder{r: r, format: format, size: playbackRead, target: target}
+	switch format.Encoding {
+	case AudioFormatWAV.Encoding:
+		stored, err := readWAVHeader(r)
+		if err != nil {
+			return nil, err
+		}
+		if _, d.format, err = stored.decode(nil); err != nil {
+			return nil, err
+		}
+		d.wav, d.frame = &stored, stored.frameSize()
+	case AudioFormatMP3.Encoding, AudioFormatOgg.Encoding:
+	default:
+		d.frame = max(format.Channels, 1) * max(format.BitDepth/8, 1)
+	}
+	if d.frame > 0 {
+		d.size = max(int(int64(d.format.SampleRate)*int64(playbackFrame)/int64(time.Second)), 1) * d.frame
+	}
+
+	channels := d.format.Channels
+	if d.format.Encoding == AudioFormatPCM.Encoding && d.format.BitDepth == 16 {
+		channels = 1 // Mixed down
+	}
+	d.converter = NewAudioConverter(d.format.SampleRate, target.SampleRate, channels, target.Channels)
+	return d, nil
+}
+
+// next returns the next piece of audio in the target format, or io.EOF
+// once the source is done
+func (d *playbackDecoder) next() ([]byte, error) {
+	for {
+		data := make([]byte, d.size)
+		n, err := io.ReadFull(d.r, data)
+		if d.frame > 0 {
+			n -= n % d.frame // Whole samples
+		}
+
+		var audio []byte
+		if n > 0 {
+			converted, convErr := d.convert(data[:n])
+			if convErr != nil {
+				return nil, convErr
+			}
+			audio = converted
+		}
+		switch {
+		case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+			if len(audio) > 0 {
+				return audio, nil
+			}
+			return nil, io.EOF
+		case err != nil:
+			return nil, fmt.Errorf("failed to read audio: %w", err)
+		case len(audio) > 0:
+			return audio, nil
+		}
+		// Compressed audio short of a whole frame so far: read on
+	}
+}
+
+// convert decodes and converts a piece of the source
+func (d *playbackDecoder) convert(data []byte) ([]byte, error) {
+	format := d.format
+	if d.wav != nil {
+		decoded, _, err := d.wav.decode(data)
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	}
+	if format.Channels > 1 && format.Encoding == AudioFormatPCM.Encoding && format.BitDepth == 16 {
+		data = downmixPCM16(data, format.Channels)
+		format.Channels = 1
+	}
+
+	converted, err := d.converter.ConvertAudio(data, format, d.target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert audio: %w", err)
+	}
+	return converted, nil
+}
+
+// downmixPCM16 averages interleaved 16-bit PCM channels to mono
+func downmixPCM16(pcm []byte, channels int) []byte {
+	frames := len(pcm) / (2 * channels)
+	out := make([]byte, 2*frames)
+	for i := 0; i < frames; i++ {
+		sum := 0
+		for ch := 0; ch < channels; ch++ {
+			sum += int(int16(binary.LittleEndian.Uint16(pcm[2*(i*channels+ch):])))
+		}
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(int16(sum/channels)))
+	}
+	return out
+}
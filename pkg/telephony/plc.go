@@ -0,0 +1,183 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// ============================================
+// PACKET LOSS CONCEALMENT
+// Filling lost inbound audio with a continuation of the signal instead of
+// holes, after ITU-T G.711 Appendix I
+// ============================================
+
+const (
+	plcMinPitch = 2500 * time.Microsecond // Shortest pitch period searched (400Hz)
+	plcMaxPitch = 15 * time.Millisecond   // Longest pitch period searched (66Hz)
+	plcHold     = 10 * time.Millisecond   // Concealment played at full level
+	plcFadeOut  = 60 * time.Millisecond   // Concealment reaches silence
+	plcBlend    = 4 * time.Millisecond    // Crossfade back into received audio
+)
+
+// ConcealmentMode selects how the jitter buffer fills lost audio
+type ConcealmentMode int
+
+const (
+	// ConcealWaveform repeats the last pitch period of audio, fading to
+	// silence over 60ms, and blends back into the audio that follows
+	ConcealWaveform ConcealmentMode = iota
+	// ConcealSilence fills lost audio with silence
+	ConcealSilence
+)
+
+// concealer synthesizes lost audio by waveform substitution: the pitch
+// period of the audio before a gap is repeated with a fade, and blended
+// into the audio after it
+type concealer struct {
+	mulaw    bool
+	minPitch int
+	maxPitch int
+	hold     int
+	fadeOut  int
+	blend    int
+
+	history []float64 // Recent output, newest last
+
+	// While concealing, and blending out of it
+	active    bool
+	template  []float64 // One pitch period repeated
+	phase     int
+	run       int // Samples concealed in this gap
+	blendLeft int
+}
+
+func newConcealer(format AudioFormat) *concealer {
+	samples := func(d time.Duration) int {
+		return max(int(int64(format.SampleRate)*int64(d)/int64(time.Second)), 1)
+	}
+	return &concealer{
+		mulaw:    format.Encoding == AudioFormatMulaw.Encoding,
+		minPitch: samples(plcMinPitch),
+		maxPitch: samples(plcMaxPitch),
+		hold:     samples(plcHold),
+		fadeOut:  samples(plcFadeOut),
+		blend:    samples(plcBlend),
+	}
+}
+
+// process fills the samples of frame not marked received with concealment,
+// blending received samples that follow concealment. Samples at and after
+// limit are past the end of the received audio and left as they are.
+func (c *concealer) process(frame []byte, received []bool, limit int) {
+	for i, got := range received {
+		if !got && i < limit {
+			if !c.active {
+				c.start()
+			}
+			v := c.next()
+			c.run++
+			c.set(frame, i, v)
+			c.remember(v)
+			continue
+		}
+
+		v := c.get(frame, i)
+		if c.active {
+			// Received audio resumes: blend out of the concealment
+			c.active, c.blendLeft = false, c.blend
+		}
+		if c.blendLeft > 0 {
+			w := float64(c.blendLeft) / float64(c.blend+1)
+			v = v*(1-w) + c.next()*w
+			c.blendLeft--
+			c.set(frame, i, v)
+		}
+		c.remember(v)
+	}
+}
+
+// start begins concealing a gap, taking the last pitch period as template
+func (c *concealer) start() {
+	c.active, c.phase, c.run, c.blendLeft = true, 0, 0, 0
+
+	period := c.pitch()
+	if period == 0 {
+		c.template = c.template[:0]
+		return
+	}
+	c.template = append(c.template[:0], c.history[len(c.history)-period:]...)
+}
+
+// next returns the next concealment sample, faded by how long the gap has run
+func (c *concealer) next() float64 {
+	if len(c.template) == 0 || c.run >= c.fadeOut {
+		return 0
+	}
+	v := c.template[c.phase%len(c.template)]
+	c.phase++
+	if c.run > c.hold {
+		v *= 1 - float64(c.run-c.hold)/float64(c.fadeOut-c.hold)
+	}
+	return v
+}
+
+// pitch estimates the history's pitch period by normalized
+// autocorrelation, or 0 when there isn't enough audio
+func (c *concealer) pitch() int {
+	window := c.maxPitch
+	n := len(c.history)
+	if n < window+c.maxPitch {
+		return 0
+	}
+	recent := c.history[n-window:]
+
+	energy := 0.0
+	for _, v := range recent {
+		energy += v * v
+	}
+	if energy < float64(window) { // Below ~0dB of sample value: silence
+		return 0
+	}
+
+	best, bestPeriod := math.Inf(-1), c.maxPitch
+	for period := c.minPitch; period <= c.maxPitch; period++ {
+		past := c.history[n-window-period : n-period]
+		corr, pastEnergy := 0.0, 0.0
+		for i, v := range recent {
+			corr += v * past[i]
+			pastEnergy += past[i] * past[i]
+		}
+		if pastEnergy == 0 {
+			continue
+		}
+		if score := corr / math.Sqrt(pastEnergy); score > best {
+			best, bestPeriod = score, period
+		}
+	}
+	return bestPeriod
+}
+
+// remember appends a sample to the history
+func (c *concealer) remember(v float64) {
+	limit := 2 * c.maxPitch
+	if len(c.history) >= 2*limit {
+		c.history = append(c.history[:0], c.history[len(c.history)-limit:]...)
+	}
+	c.history = append(c.history, v)
+}
+
+func (c *concealer) get(frame []byte, i int) float64 {
+	if c.mulaw {
+		return float64(mulawToLinear(frame[i]))
+	}
+	return float64(int16(binary.LittleEndian.Uint16(frame[2*i:])))
+}
+
+func (c *concealer) set(frame []byte, i int, v float64) {
+	if c.mulaw {
+		frame[i] = linearToMulaw(clampInt16(v))
+		return
+	}
+	binary.LittleEndian.PutUint16(frame[2*i:], uint16(clampInt16(v)))
+}
@@ -0,0 +1,77 @@
+package telephony
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ============================================
+// PROMPT TEMPLATES
+// SystemPrompt and GreetingScript filled in per call
+// ============================================
+
+// PromptData is what prompt templates can refer to:
+//
+//	Hi {{.Target.first_name}}, this is Ava from {{.Campaign.company}}.
+//
+// Referring to a missing key is an error; use index for optional values,
+// e.g. {{or (index .Target "first_name") "there"}}.
+type PromptData struct {
+	Target   map[string]interface{} // The call's Metadata, including its target's (see dialer.Target)
+	Campaign map[string]interface{} // The call's CampaignSettings
+	To       string
+	Locale   string
+}
+
+// promptData returns the data a call's templates are rendered with
+func promptData(config *CallConfig) PromptData {
+	return PromptData{
+		Target:   config.Metadata,
+		Campaign: config.CampaignSettings,
+		To:       config.To,
+		Locale:   config.Locale,
+	}
+}
+
+// RenderPrompts executes the SystemPrompt and GreetingScript templates
+// (Go text/template) of a config in place. InitiateCall renders them after
+// applying locale profiles, so they are sent to the AI filled in.
+func RenderPrompts(config *CallConfig) error {
+	data := promptData(config)
+
+	systemPrompt, err := renderPrompt("system_prompt", config.SystemPrompt, data)
+	if err != nil {
+		return err
+	}
+	greeting, err := renderPrompt("greeting_script", config.GreetingScript, data)
+	if err != nil {
+		return err
+	}
+	config.SystemPrompt, config.GreetingScript = systemPrompt, greeting
+	return nil
+}
+
+// ValidatePrompts reports whether a config's prompt templates parse and
+// render with its variables, without changing it. Check campaign calls with
+// it up front (see campaign.Scheduler.Add) rather than at call time.
+func ValidatePrompts(config CallConfig) error {
+	return RenderPrompts(&config)
+}
+
+// renderPrompt executes one template; text without actions is returned as is
+func renderPrompt(name, text string, data PromptData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,171 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ============================================
+// RECORDING NOTICE
+// A tone telling both parties the call is being recorded, as two-party
+// consent jurisdictions require
+// ============================================
+
+const noticeRamp = 5 * time.Millisecond // Fade in and out of each tone, so it doesn't click
+
+// RecordingNoticeConfig sets the notice tone. The defaults follow the US
+// FCC recorder warning tone: 1400Hz for 200ms every 15 seconds.
+type RecordingNoticeConfig struct {
+	Frequency float64       `json:"frequency"` // Tone pitch in Hz
+	Duration  time.Duration `json:"duration"`  // Length of each tone
+	Interval  time.Duration `json:"interval"`  // Time from one tone to the next; zero plays it once
+	Level     float64       `json:"level"`     // Tone level in dBFS
+}
+
+// DefaultRecordingNoticeConfig returns the FCC-style periodic beep
+func DefaultRecordingNoticeConfig() RecordingNoticeConfig {
+	return RecordingNoticeConfig{
+		Frequency: 1400,
+		Duration:  200 * time.Millisecond,
+		Interval:  15 * time.Second,
+		Level:     -18,
+	}
+}
+
+// SetRecordingNotice plays a notice tone into both directions of a session:
+// to the caller, and into caller audio on its way to the AI pipeline. Use it
+// for recordings the bridge doesn't make itself (e.g. SignalWire call
+// recording); sessions recorded by the bridge take the notice from
+// SessionRecordingConfig. A nil config stops the notice.
+func (bridge *AudioStreamBridge) SetRecordingNotice(sessionID string, config *RecordingNoticeConfig) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.noticeConfig = config
+	session.callerNotice, session.playbackNotice = nil, nil
+	return nil
+}
+
+// validate checks a notice config; nil is valid (no notice)
+func (c *RecordingNoticeConfig) validate() error {
+	if c != nil && (c.Frequency <= 0 || c.Duration <= 0 || c.Interval < 0) {
+		return fmt.Errorf("invalid recording notice: %+v", *c)
+	}
+	return nil
+}
+
+// recordingNotice returns the session's notice stage for a direction,
+// creating it for the stream's format on first use; nil when no notice is
+// configured
+func (s *BridgeSession) recordingNotice(playback bool, format AudioFormat) *recordingNotice {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.noticeConfig == nil {
+		return nil
+	}
+	notice := &s.callerNotice
+	if playback {
+		notice = &s.playbackNotice
+	}
+	if *notice == nil || (*notice).format != format {
+		*notice = newRecordingNotice(format, *s.noticeConfig)
+	}
+	return *notice
+}
+
+// recordingNotice is an AudioFilter mixing the notice tone into audio,
+// timed by the audio it has processed: the first tone starts straight away.
+// It is safe for concurrent use.
+type recordingNotice struct {
+	format    AudioFormat
+	mulaw     bool
+	amplitude float64
+	step      float64 // Phase advance per sample, radians
+	tone      int     // Samples
+	interval  int     // Samples; 0 plays the tone once
+	ramp      int
+	position  int // Samples processed
+	mu        sync.Mutex
+}
+
+func newRecordingNotice(format AudioFormat, config RecordingNoticeConfig) *recordingNotice {
+	samples := func(d time.Duration) int {
+		return int(int64(format.SampleRate) * int64(d) / int64(time.Second))
+	}
+	return &recordingNotice{
+		format:    format,
+		mulaw:     format.Encoding == AudioFormatMulaw.Encoding,
+		amplitude: 32768 * math.Pow(10, config.Level/20),
+		step:      2 * math.Pi * config.Frequency / float64(format.SampleRate),
+		tone:      max(samples(config.Duration), 1),
+		interval:  samples(config.Interval),
+		ramp:      max(samples(noticeRamp), 1),
+	}
+}
+
+// Process mixes the tone into frame in place
+func (n *recordingNotice) Process(frame []byte) []byte {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	samples := len(frame)
+	if !n.mulaw {
+		samples /= 2
+	}
+	for i := 0; i < samples; i++ {
+		at := n.position
+		n.position++
+		if n.interval > 0 {
+			at %= n.interval
+		}
+		if at >= n.tone {
+			continue
+		}
+
+		// Raised-cosine fade at both ends of the tone
+		gain := 1.0
+		if edge := min(at, n.tone-1-at); edge < n.ramp {
+			gain = 0.5 - 0.5*math.Cos(math.Pi*float64(edge)/float64(n.ramp))
+		}
+		tone := n.amplitude * gain * math.Sin(n.step*float64(at))
+
+		if n.mulaw {
+			frame[i] = linearToMulaw(clampInt16(float64(mulawToLinear(frame[i])) + tone))
+		} else {
+			v := float64(int16(binary.LittleEndian.Uint16(frame[2*i:])))
+			binary.LittleEndian.PutUint16(frame[2*i:], uint16(clampInt16(v+tone)))
+		}
+	}
+	return frame
+}
+
+// skip advances the notice's timing over samples of audio it didn't see
+func (n *recordingNotice) skip(samples int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.position += samples
+}
+
+// sounding reports whether the tone plays in the next samples of audio
+func (n *recordingNotice) sounding(samples int) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	at := n.position
+	if n.interval > 0 {
+		at %= n.interval
+		return at < n.tone || at+samples > n.interval
+	}
+	return at < n.tone
+}
@@ -0,0 +1,182 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ============================================
+// CALL RECORDINGS
+// Attaching finished recordings to their sessions
+// ============================================
+
+// RecordingPath is the webhook path for recording status callbacks. Calls
+// placed with RecordCall use it when no RecordingCallback is set (requires
+// SetPublicBaseURL).
+const RecordingPath = "/api/telephony/calls/recording"
+
+// Recording describes a finished call recording
+type Recording struct {
+	CallSID         string
+	SID             string // SignalWire recording SID
+	URL             string
+	DurationSeconds int
+	Channels        int // 1 mono, 2 dual-channel
+}
+
+// RecordingStorage keeps copies of recording media, e.g. on disk or in an
+// object store
+type RecordingStorage interface {
+	// Save stores the media under key and returns where it was stored
+	Save(ctx context.Context, key, contentType string, media io.Reader) (string, error)
+}
+
+// FileRecordingStorage stores recordings under a local directory
+type FileRecordingStorage struct {
+	Dir string
+}
+
+// Save writes the media to Dir/key
+func (s FileRecordingStorage) Save(ctx context.Context, key, contentType string, media io.Reader) (string, error) {
+	path := filepath.Join(s.Dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create recording file: %w", err)
+	}
+	if _, err := io.Copy(f, media); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("failed to write recording: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write recording: %w", err)
+	}
+	return path, nil
+}
+
+// SetRecordingStorage makes AttachRecording download each recording's
+// media into storage
+func (ci *CallInitiator) SetRecordingStorage(storage RecordingStorage) {
+	ci.recordingStorage = storage
+}
+
+// AttachRecording stores a finished recording on its call's session,
+// copies the media to the recording storage if one is set, and fires the
+// OnRecordingReady listeners
+func (ci *CallInitiator) AttachRecording(ctx context.Context, recording Recording) error {
+	session, err := ci.lookupSession(ctx, recording.CallSID)
+	if err != nil {
+		return err
+	}
+
+	// Download before locking; a failed copy still attaches the recording
+	var storedAt string
+	if ci.recordingStorage != nil && recording.SID != "" {
+		session.mu.RLock()
+		key := fmt.Sprintf("recordings/%s/%s.mp3", session.ID, recording.SID)
+		session.mu.RUnlock()
+
+		if storedAt, err = ci.storeRecording(ctx, key, recording.SID); err != nil {
+			log.Printf("[CallInitiator] Failed to store recording %s: %v", recording.SID, err)
+			ci.errorLog.Record("recording", recording.CallSID, err)
+		}
+	}
+
+	session, err = ci.updateSession(ctx, session, func(session *CallSession) error {
+		session.RecordingURL = recording.URL
+		session.RecordingDuration = recording.DurationSeconds
+		if recording.SID != "" {
+			session.RecordingSID = recording.SID
+		}
+		if recording.Channels > 0 {
+			session.RecordingChannels = recording.Channels
+		}
+		if storedAt != "" {
+			session.RecordingStoredAt = storedAt
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save recording: %w", err)
+	}
+
+	log.Printf("[CallInitiator] Recording %s attached to call %s", recording.SID, recording.CallSID)
+	ci.hooks.fireRecording(RecordingEvent{
+		Call:            session.Summary(),
+		URL:             recording.URL,
+		DurationSeconds: recording.DurationSeconds,
+		SID:             recording.SID,
+		Channels:        recording.Channels,
+		StoredAt:        storedAt,
+	})
+	return nil
+}
+
+// storeRecording downloads a recording's MP3 into the recording storage
+func (ci *CallInitiator) storeRecording(ctx context.Context, key, recordingSID string) (string, error) {
+	path := fmt.Sprintf("/Accounts/%s/Recordings/%s.mp3", ci.projectID, recordingSID)
+	resp, err := ci.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+	return ci.recordingStorage.Save(ctx, key, "audio/mpeg", resp.Body)
+}
+
+// HandleRecordingStatus attaches recordings to their sessions as
+// SignalWire reports them completed
+func (h *CallHandlers) HandleRecordingStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	callSID := r.FormValue("CallSid")
+	if callSID == "" {
+		http.Error(w, "Missing CallSid", http.StatusBadRequest)
+		return
+	}
+
+	status := r.FormValue("RecordingStatus")
+	if status != "" && status != "completed" {
+		log.Printf("[CallHandlers] Recording %s for call %s: %s", r.FormValue("RecordingSid"), callSID, status)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	recording := Recording{
+		CallSID: callSID,
+		SID:     r.FormValue("RecordingSid"),
+		URL:     r.FormValue("RecordingUrl"),
+	}
+	recording.DurationSeconds, _ = strconv.Atoi(r.FormValue("RecordingDuration"))
+	recording.Channels, _ = strconv.Atoi(r.FormValue("RecordingChannels"))
+
+	// Downloads can outlast the webhook timeout, so attach in the background
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		if err := h.callInitiator.AttachRecording(ctx, recording); err != nil {
+			log.Printf("[CallHandlers] Failed to attach recording for %s: %v", callSID, err)
+			h.callInitiator.Errors().Record("recording", callSID, err)
+		}
+	}()
+
+	w.WriteHeader(http.StatusNoContent)
+}
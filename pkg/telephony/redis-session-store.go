@@ -0,0 +1,244 @@
+package telephony
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ============================================
+// REDIS SESSION STORE
+// Call state shared between server instances
+// ============================================
+
+// SharedSessionStore is implemented by stores shared between server
+// instances. The initiator then reads sessions from the store on every
+// lookup instead of trusting its in-memory copy, since webhooks for a call
+// may land on an instance other than the one that placed it.
+type SharedSessionStore interface {
+	CallSessionStore
+	Shared() bool
+}
+
+// RedisSessionStore keeps call sessions in Redis:
+//
+//	<prefix>session:<id>  JSON session (expires after TTL)
+//	<prefix>sid:<sid>     session ID for a SignalWire call SID
+//	<prefix>sessions      sorted set of session IDs by initiation time
+type RedisSessionStore struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// redisSessionRecord is the stored form of a session, including the
+// ordering state needed to reject stale webhooks on any instance and the
+// call's AMD branches
+type redisSessionRecord struct {
+	*CallSession
+	LastSequence int        `json:"last_sequence,omitempty"`
+	LastEventAt  time.Time  `json:"last_event_at,omitempty"`
+	AMDPolicy    *amdPolicy `json:"amd_policy,omitempty"`
+}
+
+// NewRedisSessionStore creates a session store backed by Redis. Keys are
+// namespaced by prefix (default "telephony:") and kept for ttl (default 7
+// days) after the last update.
+func NewRedisSessionStore(client *redis.Client, prefix string, ttl time.Duration) *RedisSessionStore {
+	if prefix == "" {
+		prefix = "telephony:"
+	}
+	if ttl <= 0 {
+		ttl = 7 * 24 * time.Hour
+	}
+	return &RedisSessionStore{client: client, prefix: prefix, ttl: ttl}
+}
+
+// Shared reports that the store is visible to every server instance
+func (s *RedisSessionStore) Shared() bool {
+	return true
+}
+
+// Insert stores a new session
+func (s *RedisSessionStore) Insert(ctx context.Context, session *CallSession) error {
+	created, err := s.client.SetNX(ctx, s.sessionKey(session.ID), "", s.ttl).Result()
+	if err != nil {
+		return fmt.Errorf("failed to reserve session: %w", err)
+	}
+	if !created {
+		return fmt.Errorf("session %s already exists", session.ID)
+	}
+
+	if err := s.save(ctx, s.client, session); err != nil {
+		return err
+	}
+
+	score := float64(session.InitiatedAt.UnixNano())
+	if err := s.client.ZAdd(ctx, s.indexKey(), redis.Z{Score: score, Member: session.ID.String()}).Err(); err != nil {
+		return fmt.Errorf("failed to index session: %w", err)
+	}
+	return nil
+}
+
+// Update overwrites a stored session if its version still matches. The
+// session key is watched, so a write from another instance between the
+// check and the save fails with ErrSessionConflict.
+func (s *RedisSessionStore) Update(ctx context.Context, session *CallSession) error {
+	key := s.sessionKey(session.ID)
+	err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+		stored, err := s.getWith(ctx, tx, session.ID)
+		if err != nil {
+			return err
+		}
+		if stored.Version != session.Version {
+			return ErrSessionConflict
+		}
+
+		session.Version++
+		if err := s.save(ctx, tx, session); err != nil {
+			session.Version--
+			return err
+		}
+		return nil
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return ErrSessionConflict
+	}
+	return err
+}
+
+// save writes the session and its SID mapping in one transaction
+func (s *RedisSessionStore) save(ctx context.Context, c redis.Cmdable, session *CallSession) error {
+	data, err := json.Marshal(redisSessionRecord{
+		CallSession:  session,
+		LastSequence: session.lastSequence,
+		LastEventAt:  session.lastEventAt,
+		AMDPolicy:    session.amd,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	_, err = c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.sessionKey(session.ID), data, s.ttl)
+		if session.SignalWireCallSID != "" {
+			pipe.Set(ctx, s.sidKey(session.SignalWireCallSID), session.ID.String(), s.ttl)
+		}
+		return nil
+	})
+	if errors.Is(err, redis.TxFailedErr) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// GetBySID loads the session for a SignalWire call SID
+func (s *RedisSessionStore) GetBySID(ctx context.Context, callSID string) (*CallSession, error) {
+	id, err := s.client.Get(ctx, s.sidKey(callSID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up call SID: %w", err)
+	}
+
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID %q: %w", id, err)
+	}
+	return s.get(ctx, sessionID)
+}
+
+// get loads a session by ID
+func (s *RedisSessionStore) get(ctx context.Context, id uuid.UUID) (*CallSession, error) {
+	return s.getWith(ctx, s.client, id)
+}
+
+// getWith loads a session by ID through c (a client or watched transaction)
+func (s *RedisSessionStore) getWith(ctx context.Context, c redis.Cmdable, id uuid.UUID) (*CallSession, error) {
+	data, err := c.Get(ctx, s.sessionKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) || (err == nil && len(data) == 0) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	record := redisSessionRecord{CallSession: &CallSession{}}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	session := record.CallSession
+	session.lastSequence = record.LastSequence
+	session.lastEventAt = record.LastEventAt
+	session.amd = record.AMDPolicy
+	return session, nil
+}
+
+// Query returns sessions matching a filter, newest first. Index entries for
+// expired sessions are pruned as they are found.
+func (s *RedisSessionStore) Query(ctx context.Context, filter SessionFilter) ([]*CallSession, error) {
+	max := "+inf"
+	if !filter.Until.IsZero() {
+		max = fmt.Sprintf("(%d", filter.Until.UnixNano())
+	}
+	if filter.After != nil && (filter.Until.IsZero() || filter.After.InitiatedAt.Before(filter.Until)) {
+		// Ties with the cursor are settled by filter.matches
+		max = fmt.Sprintf("%d", filter.After.InitiatedAt.UnixNano())
+	}
+	min := "-inf"
+	if !filter.Since.IsZero() {
+		min = fmt.Sprintf("%d", filter.Since.UnixNano())
+	}
+
+	ids, err := s.client.ZRevRangeByScore(ctx, s.indexKey(), &redis.ZRangeBy{Min: min, Max: max}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session index: %w", err)
+	}
+
+	var sessions []*CallSession
+	for _, id := range ids {
+		sessionID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+
+		session, err := s.get(ctx, sessionID)
+		if errors.Is(err, ErrSessionNotFound) {
+			s.client.ZRem(ctx, s.indexKey(), id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if filter.matches(session) {
+			sessions = append(sessions, session)
+			if filter.Limit > 0 && len(sessions) >= filter.Limit {
+				break
+			}
+		}
+	}
+	return sessions, nil
+}
+
+func (s *RedisSessionStore) sessionKey(id uuid.UUID) string {
+	return s.prefix + "session:" + id.String()
+}
+
+func (s *RedisSessionStore) sidKey(callSID string) string {
+	return s.prefix + "sid:" + callSID
+}
+
+func (s *RedisSessionStore) indexKey() string {
+	return s.prefix + "sessions"
+}
@@ -0,0 +1,351 @@
+package telephony
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/birddigital/signalwire-telephony/pkg/signalwire"
+)
+
+// ============================================
+// RELAY EVENTS
+// Call and message state from SignalWire's realtime WebSocket
+// ============================================
+
+// RelayPath is the WebSocket path of SignalWire's realtime (Relay) API
+const RelayPath = "/api/relay/wss"
+
+// MessageState is a messaging state change reported over Relay
+type MessageState struct {
+	MessageID string    `json:"message_id"`
+	State     string    `json:"state"` // queued, initiated, sent, delivered, undelivered, failed
+	Reason    string    `json:"reason,omitempty"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// relayEndStates maps Relay end reasons to final call states
+var relayEndStates = map[string]CallState{
+	"hangup":   StateCompleted,
+	"busy":     StateBusy,
+	"noAnswer": StateNoAnswer,
+	"cancel":   StateCancelled,
+	"decline":  StateFailed,
+	"error":    StateFailed,
+}
+
+// relayEndCauses maps Relay end reasons to hangup causes
+var relayEndCauses = map[string]HangupCause{
+	"hangup":   CauseNormalClearing,
+	"busy":     CauseUserBusy,
+	"noAnswer": CauseNoAnswer,
+	"cancel":   CauseOriginatorCancel,
+	"decline":  CauseCallRejected,
+	"error":    CauseTemporaryFailure,
+}
+
+// RelayConsumer receives call and message state over a WebSocket instead of
+// HTTP status callbacks, for servers SignalWire can't reach (behind NAT, on
+// a laptop). Call states go through ApplyCallEvent just as status callbacks
+// do; calls that aren't ours are ignored.
+type RelayConsumer struct {
+	initiator *CallInitiator
+	projectID string
+	authToken string
+	space     string
+	endpoints *signalwire.Endpoints
+	dialer    *websocket.Dialer
+	contexts  []string
+
+	listeners []func(MessageState)
+	mu        sync.Mutex
+}
+
+// NewRelayConsumer creates a consumer for the given Relay contexts (the
+// contexts our numbers are configured to deliver events to)
+func NewRelayConsumer(initiator *CallInitiator, projectID, authToken, space string, contexts ...string) *RelayConsumer {
+	return &RelayConsumer{
+		initiator: initiator,
+		projectID: projectID,
+		authToken: authToken,
+		space:     space,
+		endpoints: signalwire.NewEndpoints(space),
+		dialer:    websocket.DefaultDialer,
+		contexts:  contexts,
+	}
+}
+
+// SetEndpoints configures regional/edge hosts to try before the space URL
+func (c *RelayConsumer) SetEndpoints(hosts ...string) {
+	c.endpoints = signalwire.NewEndpoints(append(hosts, c.space)...)
+}
+
+// SetTransport configures proxy, dialer and TLS settings for the WebSocket
+func (c *RelayConsumer) SetTransport(config signalwire.TransportConfig) error {
+	dialer, err := config.WebSocketDialer()
+	if err != nil {
+		return err
+	}
+	c.dialer = dialer
+	return nil
+}
+
+// OnMessageState registers a listener for messaging state changes
+func (c *RelayConsumer) OnMessageState(listener func(MessageState)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, listener)
+}
+
+// Run consumes events until ctx is done, reconnecting with backoff when the
+// connection drops
+func (c *RelayConsumer) Run(ctx context.Context) error {
+	for attempt := 0; ; attempt++ {
+		connected, err := c.consume(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if connected {
+			attempt = 0
+		}
+
+		delay := time.Duration(math.Min(30, math.Pow(2, float64(attempt)))) * time.Second
+		log.Printf("[Relay] Connection lost (%v), reconnecting in %s", err, delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// relayMessage is a JSON-RPC 2.0 request or response on the Relay socket
+type relayMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      string          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// consume runs one connection, reporting whether it got as far as
+// authenticating
+func (c *RelayConsumer) consume(ctx context.Context) (bool, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	// Unblock reads when we're asked to stop
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := c.call(ctx, conn, "signalwire.connect", map[string]interface{}{
+		"version":        map[string]int{"major": 3, "minor": 0, "revision": 0},
+		"authentication": map[string]string{"project": c.projectID, "token": c.authToken},
+	}); err != nil {
+		return false, fmt.Errorf("failed to authenticate: %w", err)
+	}
+	if len(c.contexts) > 0 {
+		if err := c.call(ctx, conn, "signalwire.receive", map[string]interface{}{"contexts": c.contexts}); err != nil {
+			return true, fmt.Errorf("failed to subscribe to contexts: %w", err)
+		}
+	}
+	log.Printf("[Relay] Receiving events for contexts %s", strings.Join(c.contexts, ", "))
+
+	for {
+		var msg relayMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return true, err
+		}
+		if msg.Method == "" {
+			continue // Response to a request we no longer wait for
+		}
+
+		// Every request must be acknowledged or SignalWire redelivers it
+		if err := conn.WriteJSON(relayMessage{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(`{}`)}); err != nil {
+			return true, err
+		}
+		if msg.Method == "signalwire.event" {
+			c.handleEvent(ctx, msg.Params)
+		}
+	}
+}
+
+// dial opens the Relay WebSocket, trying each configured endpoint in turn
+func (c *RelayConsumer) dial(ctx context.Context) (*websocket.Conn, error) {
+	var lastErr error
+	for _, host := range c.endpoints.Hosts() {
+		conn, _, err := c.dialer.DialContext(ctx, fmt.Sprintf("wss://%s%s", host, RelayPath), nil)
+		if err != nil {
+			lastErr = err
+			log.Printf("[Relay] WebSocket dial to %s failed: %v", host, err)
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		c.endpoints.MarkHealthy(host)
+		return conn, nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no SignalWire endpoints configured")
+	}
+	return nil, fmt.Errorf("failed to connect to SignalWire: %w", lastErr)
+}
+
+// call sends a request and waits for its response, handling any requests
+// that arrive in between
+func (c *RelayConsumer) call(ctx context.Context, conn *websocket.Conn, method string, params interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	id := uuid.New().String()
+	if err := conn.WriteJSON(relayMessage{JSONRPC: "2.0", ID: id, Method: method, Params: raw}); err != nil {
+		return err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(15 * time.Second))
+	defer conn.SetReadDeadline(time.Time{})
+	for {
+		var msg relayMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+		if msg.Method != "" {
+			if err := conn.WriteJSON(relayMessage{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(`{}`)}); err != nil {
+				return err
+			}
+			if msg.Method == "signalwire.event" {
+				c.handleEvent(ctx, msg.Params)
+			}
+			continue
+		}
+		if msg.ID != id {
+			continue
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("%s (%d)", msg.Error.Message, msg.Error.Code)
+		}
+		return nil
+	}
+}
+
+// relayEvent is the params of a signalwire.event request
+type relayEvent struct {
+	EventType string          `json:"event_type"`
+	Timestamp float64         `json:"timestamp"`
+	Params    json.RawMessage `json:"params"`
+}
+
+// handleEvent dispatches a call or messaging state event
+func (c *RelayConsumer) handleEvent(ctx context.Context, raw json.RawMessage) {
+	var event relayEvent
+	if err := json.Unmarshal(raw, &event); err != nil {
+		log.Printf("[Relay] Malformed event: %v", err)
+		return
+	}
+	var at time.Time
+	if event.Timestamp > 0 {
+		sec, frac := math.Modf(event.Timestamp)
+		at = time.Unix(int64(sec), int64(frac*1e9))
+	}
+
+	switch event.EventType {
+	case "calling.call.state":
+		var params struct {
+			CallID    string `json:"call_id"`
+			CallState string `json:"call_state"`
+			EndReason string `json:"end_reason"`
+		}
+		if err := json.Unmarshal(event.Params, &params); err != nil || params.CallID == "" {
+			return
+		}
+		callEvent, ok := relayCallEvent(params.CallID, params.CallState, params.EndReason)
+		if !ok {
+			return
+		}
+		callEvent.Timestamp = at
+
+		// Contexts carry every call on their numbers, not just ours
+		if _, err := c.initiator.lookupSession(ctx, params.CallID); err != nil {
+			return
+		}
+		if err := c.initiator.ApplyCallEvent(ctx, callEvent); err != nil {
+			log.Printf("[Relay] Failed to apply %s for %s: %v", params.CallState, params.CallID, err)
+			c.initiator.Errors().Record("relay", params.CallID, err)
+		}
+
+	case "messaging.state":
+		var params struct {
+			MessageID    string `json:"message_id"`
+			MessageState string `json:"message_state"`
+			Reason       string `json:"reason"`
+			From         string `json:"from_number"`
+			To           string `json:"to_number"`
+		}
+		if err := json.Unmarshal(event.Params, &params); err != nil || params.MessageID == "" {
+			return
+		}
+		c.fireMessageState(MessageState{
+			MessageID: params.MessageID,
+			State:     params.MessageState,
+			Reason:    params.Reason,
+			From:      params.From,
+			To:        params.To,
+			Timestamp: at,
+		})
+	}
+}
+
+// relayCallEvent maps a Relay call state to a call event. The transitional
+// "ending" state carries nothing we track.
+func relayCallEvent(callID, state, endReason string) (CallEvent, bool) {
+	event := CallEvent{CallSID: callID}
+	switch state {
+	case "created":
+		event.State = StateInitiated
+	case "ringing":
+		event.State = StateRinging
+	case "answered":
+		event.State = StateAnswered
+	case "ended":
+		final, ok := relayEndStates[endReason]
+		if !ok {
+			final = StateCompleted
+		}
+		event.State = final
+		event.HangupCause = relayEndCauses[endReason]
+	default:
+		return CallEvent{}, false
+	}
+	return event, true
+}
+
+func (c *RelayConsumer) fireMessageState(state MessageState) {
+	c.mu.Lock()
+	listeners := make([]func(MessageState), len(c.listeners))
+	copy(listeners, c.listeners)
+	c.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(state)
+	}
+}
@@ -0,0 +1,354 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// ============================================
+// RESAMPLING
+// Sample rate conversion for 16-bit PCM
+// ============================================
+
+// ResampleMode selects the sample rate conversion algorithm
+type ResampleMode int
+
+const (
+	// ResampleSinc is a polyphase windowed-sinc resampler with an
+	// anti-aliasing low-pass filter (default)
+	ResampleSinc ResampleMode = iota
+	// ResampleLinear interpolates linearly between samples: cheaper, but
+	// aliases when downsampling (e.g. 16kHz → 8kHz)
+	ResampleLinear
+)
+
+// String returns the mode's name
+func (r ResampleMode) String() string {
+	switch r {
+	case ResampleSinc:
+		return "sinc"
+	case ResampleLinear:
+		return "linear"
+	}
+	return fmt.Sprintf("ResampleMode(%d)", int(r))
+}
+
+const (
+	// sincZeroCrossings is the number of sinc zero crossings on each side
+	// of the filter center; more is sharper and slower
+	sincZeroCrossings = 16
+	// sincPassband is the cutoff as a fraction of the lower Nyquist
+	// frequency, leaving room for the filter's transition band
+	sincPassband = 0.95
+)
+
+// sincFilter is the polyphase filter bank for one rate ratio
+type sincFilter struct {
+	up, down int         // Ratio as up/down (reduced)
+	half     int         // Taps on each side of the output position, in input samples
+	phases   [][]float64 // Coefficients for each output phase
+}
+
+// sincFilters caches filter banks by ratio
+var sincFilters sync.Map // [2]int{up, down} -> *sincFilter
+
+// ResamplePCM16 converts little-endian 16-bit mono PCM between sample rates.
+// Each call stands alone; resample a continuous stream with a Resampler.
+func ResamplePCM16(pcmData []byte, fromRate, toRate int, mode ResampleMode) ([]byte, error) {
+	if len(pcmData)%2 != 0 {
+		return nil, fmt.Errorf("PCM data length must be even (16-bit samples)")
+	}
+	if fromRate <= 0 || toRate <= 0 {
+		return nil, fmt.Errorf("invalid sample rates %d → %d", fromRate, toRate)
+	}
+	if fromRate == toRate {
+		return pcmData, nil
+	}
+	if len(pcmData) < 4 {
+		// Too short to interpolate
+		return nil, fmt.Errorf("need at least 2 samples to resample")
+	}
+
+	switch mode {
+	case ResampleLinear:
+		return resampleLinear(pcmData, fromRate, toRate), nil
+	default:
+		return resampleSinc(pcmData, fromRate, toRate), nil
+	}
+}
+
+// resampleLinear resamples using linear interpolation
+func resampleLinear(pcmData []byte, fromRate, toRate int) []byte {
+	numInputSamples := len(pcmData) / 2
+	numOutputSamples := (numInputSamples * toRate) / fromRate
+	outputData := make([]byte, numOutputSamples*2)
+
+	ratio := float64(fromRate) / float64(toRate)
+	for i := 0; i < numOutputSamples; i++ {
+		srcPos := float64(i) * ratio
+		srcIndex := int(srcPos)
+		if srcIndex >= numInputSamples-1 {
+			srcIndex = numInputSamples - 2
+		}
+		fraction := srcPos - float64(srcIndex)
+
+		sample1 := int16(binary.LittleEndian.Uint16(pcmData[srcIndex*2:]))
+		sample2 := int16(binary.LittleEndian.Uint16(pcmData[(srcIndex+1)*2:]))
+		interpolated := float64(sample1)*(1-fraction) + float64(sample2)*fraction
+
+		binary.LittleEndian.PutUint16(outputData[i*2:], uint16(clampInt16(interpolated)))
+	}
+	return outputData
+}
+
+// resampleSinc resamples through a polyphase windowed-sinc filter. Samples
+// beyond the buffer's edges repeat the edge samples; use a Resampler to
+// filter across chunk boundaries.
+func resampleSinc(pcmData []byte, fromRate, toRate int) []byte {
+	filter := sincFilterFor(fromRate, toRate)
+
+	numInputSamples := len(pcmData) / 2
+	input := make([]float64, numInputSamples)
+	for i := range input {
+		input[i] = float64(int16(binary.LittleEndian.Uint16(pcmData[i*2:])))
+	}
+
+	numOutputSamples := (numInputSamples * toRate) / fromRate
+	outputData := make([]byte, numOutputSamples*2)
+
+	for i := 0; i < numOutputSamples; i++ {
+		// Output i sits at input position (i*down)/up
+		pos := i * filter.down
+		base := pos / filter.up
+		coeffs := filter.phases[pos%filter.up]
+
+		start := base - filter.half + 1
+		sum := 0.0
+		for j, coeff := range coeffs {
+			k := start + j
+			if k < 0 {
+				k = 0
+			} else if k >= numInputSamples {
+				k = numInputSamples - 1
+			}
+			sum += input[k] * coeff
+		}
+
+		binary.LittleEndian.PutUint16(outputData[i*2:], uint16(clampInt16(sum)))
+	}
+	return outputData
+}
+
+// Resampler resamples a continuous stream chunk by chunk, carrying filter
+// history across chunks so chunk boundaries aren't audible. Output lags input
+// by the filter's half width (about 2ms for sinc); Flush drains the tail when
+// the stream ends. A Resampler is not safe for concurrent use.
+type Resampler struct {
+	fromRate, toRate int
+	mode             ResampleMode
+	filter           *sincFilter
+	buf              []float64 // Input samples from absolute index bufStart
+	bufStart         int
+	next             int // Absolute index of the next output sample
+}
+
+// NewResampler creates a streaming resampler between two rates
+func NewResampler(fromRate, toRate int, mode ResampleMode) (*Resampler, error) {
+	if fromRate <= 0 || toRate <= 0 {
+		return nil, fmt.Errorf("invalid sample rates %d → %d", fromRate, toRate)
+	}
+	r := &Resampler{fromRate: fromRate, toRate: toRate, mode: mode}
+	switch mode {
+	case ResampleLinear:
+		r.filter = newLinearFilter(fromRate, toRate)
+	default:
+		r.filter = sincFilterFor(fromRate, toRate)
+	}
+	return r, nil
+}
+
+// Process resamples the next chunk of little-endian 16-bit mono PCM,
+// returning the output that chunk completes in a pooled buffer (see
+// ReleaseAudioBuffer), or pcmData itself when the rates match
+func (r *Resampler) Process(pcmData []byte) ([]byte, error) {
+	if len(pcmData)%2 != 0 {
+		return nil, fmt.Errorf("PCM data length must be even (16-bit samples)")
+	}
+	if r.fromRate == r.toRate {
+		return pcmData, nil
+	}
+	for i := 0; i+1 < len(pcmData); i += 2 {
+		r.buf = append(r.buf, float64(int16(binary.LittleEndian.Uint16(pcmData[i:]))))
+	}
+	return r.drain(false), nil
+}
+
+// Flush returns the output still held back for lookahead, treating the
+// stream as ended (its last sample repeats), and resets the resampler
+func (r *Resampler) Flush() []byte {
+	out := r.drain(true)
+	r.Reset()
+	return out
+}
+
+// Reset discards filter history so the next Process starts a new stream
+func (r *Resampler) Reset() {
+	r.buf = nil
+	r.bufStart = 0
+	r.next = 0
+}
+
+// drain computes every output sample the buffered input allows. Without
+// final, it stops where the filter would run past the input; with final, it
+// continues to the end of the stream.
+func (r *Resampler) drain(final bool) []byte {
+	f := r.filter
+	available := r.bufStart + len(r.buf)
+	if len(r.buf) == 0 {
+		return nil
+	}
+	// Total output for the stream so far, as ResamplePCM16 would produce
+	end := (available * f.up) / f.down
+
+	var out []byte
+	if end > r.next {
+		out = GetAudioBuffer((end - r.next) * 2)[:0]
+	}
+	for r.next < end {
+		pos := r.next * f.down
+		base := pos / f.up
+		if !final && base+f.half >= available {
+			break
+		}
+		coeffs := f.phases[pos%f.up]
+
+		start := base - f.half + 1
+		sum := 0.0
+		for j, coeff := range coeffs {
+			// Before the stream began, repeat its first sample; past its
+			// end, its last
+			k := start + j - r.bufStart
+			if k < 0 {
+				k = 0
+			} else if k >= len(r.buf) {
+				k = len(r.buf) - 1
+			}
+			sum += r.buf[k] * coeff
+		}
+		out = binary.LittleEndian.AppendUint16(out, uint16(clampInt16(sum)))
+		r.next++
+	}
+
+	// Drop input no later output needs
+	keepFrom := (r.next*f.down)/f.up - f.half + 1 - r.bufStart
+	if keepFrom > len(r.buf)-1 {
+		// Keep the last sample for edge padding
+		keepFrom = len(r.buf) - 1
+	}
+	if keepFrom > 0 {
+		r.buf = append(r.buf[:0], r.buf[keepFrom:]...)
+		r.bufStart += keepFrom
+	}
+	return out
+}
+
+// newLinearFilter builds a two-tap filter bank that interpolates linearly
+// between neighbouring samples
+func newLinearFilter(fromRate, toRate int) *sincFilter {
+	g := gcd(fromRate, toRate)
+	up, down := toRate/g, fromRate/g
+
+	filter := &sincFilter{up: up, down: down, half: 1, phases: make([][]float64, up)}
+	for phase := 0; phase < up; phase++ {
+		frac := float64(phase) / float64(up)
+		filter.phases[phase] = []float64{1 - frac, frac}
+	}
+	return filter
+}
+
+// sincFilterFor returns the cached filter bank for a rate ratio
+func sincFilterFor(fromRate, toRate int) *sincFilter {
+	g := gcd(fromRate, toRate)
+	up, down := toRate/g, fromRate/g
+
+	key := [2]int{up, down}
+	if cached, ok := sincFilters.Load(key); ok {
+		return cached.(*sincFilter)
+	}
+	filter := newSincFilter(up, down)
+	sincFilters.Store(key, filter)
+	return filter
+}
+
+// newSincFilter designs a Blackman-windowed sinc low-pass for the ratio,
+// split into one set of taps per output phase
+func newSincFilter(up, down int) *sincFilter {
+	// Cut off below the lower of the two Nyquist frequencies, relative to
+	// the input rate
+	cutoff := sincPassband
+	if down > up {
+		cutoff *= float64(up) / float64(down)
+	}
+
+	// The filter spans the same number of zero crossings at any cutoff
+	half := int(math.Ceil(sincZeroCrossings / cutoff))
+	width := float64(half)
+
+	filter := &sincFilter{up: up, down: down, half: half, phases: make([][]float64, up)}
+	for phase := 0; phase < up; phase++ {
+		offset := float64(phase) / float64(up)
+		coeffs := make([]float64, 2*half)
+
+		sum := 0.0
+		for j := range coeffs {
+			// Distance from the output position to input sample j
+			t := offset + float64(half-1-j)
+			coeffs[j] = cutoff * sinc(cutoff*t) * blackman(t/width)
+			sum += coeffs[j]
+		}
+		// Unity gain at DC for every phase
+		for j := range coeffs {
+			coeffs[j] /= sum
+		}
+		filter.phases[phase] = coeffs
+	}
+	return filter
+}
+
+// sinc is the normalized sinc function sin(πx)/(πx)
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman is the Blackman window over [-1, 1]
+func blackman(x float64) float64 {
+	if x <= -1 || x >= 1 {
+		return 0
+	}
+	return 0.42 + 0.5*math.Cos(math.Pi*x) + 0.08*math.Cos(2*math.Pi*x)
+}
+
+// clampInt16 rounds and clamps a sample to the 16-bit range
+func clampInt16(v float64) int16 {
+	v = math.Round(v)
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
+
+// gcd returns the greatest common divisor
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,149 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// resampleConversions are the rate pairs telephony audio goes through
+var resampleConversions = []struct{ from, to int }{
+	{16000, 8000},
+	{8000, 16000},
+	{24000, 16000},
+	{24000, 8000},
+}
+
+// tone generates a sine wave as 16-bit PCM
+func tone(freq float64, rate, samples int) []byte {
+	pcm := make([]byte, samples*2)
+	for i := 0; i < samples; i++ {
+		v := 16000 * math.Sin(2*math.Pi*freq*float64(i)/float64(rate))
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(int16(v)))
+	}
+	return pcm
+}
+
+// rms is the root mean square of 16-bit PCM
+func rms(pcm []byte) float64 {
+	sum := 0.0
+	for i := 0; i+1 < len(pcm); i += 2 {
+		v := float64(int16(binary.LittleEndian.Uint16(pcm[i:])))
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(pcm)/2))
+}
+
+// resampleGain resamples one second of a tone and returns the output level
+// relative to the input in dB, skipping the edges where the filter runs off
+// the buffer
+func resampleGain(t *testing.T, freq float64, from, to int, mode ResampleMode) float64 {
+	t.Helper()
+	input := tone(freq, from, from)
+	output, err := ResamplePCM16(input, from, to, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return 20 * math.Log10(rms(output[len(output)/10:len(output)*9/10])/rms(input))
+}
+
+func TestResampleSincPassband(t *testing.T) {
+	for _, conv := range resampleConversions {
+		// Tones up to 80% of the lower Nyquist frequency pass unchanged
+		nyquist := float64(min(conv.from, conv.to)) / 2
+		for _, freq := range []float64{300, 1000, nyquist * 0.8} {
+			if gain := resampleGain(t, freq, conv.from, conv.to, ResampleSinc); math.Abs(gain) > 0.5 {
+				t.Errorf("%d → %d: %.0fHz tone gain %.2fdB, want within ±0.5dB", conv.from, conv.to, freq, gain)
+			}
+		}
+	}
+}
+
+func TestResampleSincStopband(t *testing.T) {
+	for _, conv := range resampleConversions {
+		if conv.to >= conv.from {
+			continue
+		}
+
+		// Tones between the output and input Nyquist frequencies would
+		// alias; the anti-aliasing filter must remove them
+		for _, frac := range []float64{0.2, 0.45, 0.7} {
+			freq := float64(conv.to)/2 + float64(conv.from-conv.to)/2*frac
+			sinc := resampleGain(t, freq, conv.from, conv.to, ResampleSinc)
+			if sinc > -40 {
+				t.Errorf("%d → %d: %.0fHz tone leaks at %.1fdB, want below -40dB", conv.from, conv.to, freq, sinc)
+			}
+			if linear := resampleGain(t, freq, conv.from, conv.to, ResampleLinear); sinc >= linear {
+				t.Errorf("%d → %d: %.0fHz tone leaks at %.1fdB with sinc, %.1fdB with linear", conv.from, conv.to, freq, sinc, linear)
+			}
+		}
+	}
+}
+
+func TestResamplerMatchesOneShot(t *testing.T) {
+	// Feeding a stream in 20ms chunks gives the same samples as one call,
+	// apart from the stream's lag
+	input := tone(1000, 16000, 16000)
+	whole, err := ResamplePCM16(input, 16000, 8000, ResampleSinc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resampler, err := NewResampler(16000, 8000, ResampleSinc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streamed []byte
+	for i := 0; i < len(input); i += 640 {
+		out, err := resampler.Process(input[i : i+640])
+		if err != nil {
+			t.Fatal(err)
+		}
+		streamed = append(streamed, out...)
+	}
+	streamed = append(streamed, resampler.Flush()...)
+
+	if len(streamed) != len(whole) {
+		t.Fatalf("streamed %d bytes, one-shot %d", len(streamed), len(whole))
+	}
+	if gain := 20 * math.Log10(rms(streamed)/rms(whole)); math.Abs(gain) > 0.1 {
+		t.Errorf("streamed level differs from one-shot by %.2fdB", gain)
+	}
+}
+
+func BenchmarkResamplePCM16(b *testing.B) {
+	for _, conv := range resampleConversions {
+		chunk := tone(1000, conv.from, conv.from/50) // 20ms
+		for _, mode := range []ResampleMode{ResampleSinc, ResampleLinear} {
+			b.Run(fmt.Sprintf("%dk-%dk/%s", conv.from/1000, conv.to/1000, mode), func(b *testing.B) {
+				b.SetBytes(int64(len(chunk)))
+				for i := 0; i < b.N; i++ {
+					if _, err := ResamplePCM16(chunk, conv.from, conv.to, mode); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkResampler(b *testing.B) {
+	for _, conv := range resampleConversions {
+		chunk := tone(1000, conv.from, conv.from/50) // 20ms
+		for _, mode := range []ResampleMode{ResampleSinc, ResampleLinear} {
+			b.Run(fmt.Sprintf("%dk-%dk/%s", conv.from/1000, conv.to/1000, mode), func(b *testing.B) {
+				resampler, err := NewResampler(conv.from, conv.to, mode)
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.SetBytes(int64(len(chunk)))
+				for i := 0; i < b.N; i++ {
+					if _, err := resampler.Process(chunk); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
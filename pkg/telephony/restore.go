@@ -0,0 +1,149 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ============================================
+// RESTART RECOVERY
+// Reloading in-flight calls from the session store
+// ============================================
+
+// unplacedCallTimeout is how old a session without a call SID must be
+// before restore treats its InitiateCall as interrupted
+const unplacedCallTimeout = 5 * time.Minute
+
+// RestoreReport summarizes a RestoreActiveCalls run
+type RestoreReport struct {
+	Restored int `json:"restored"` // Calls tracked again
+	Updated  int `json:"updated"`  // Calls whose state changed on resync
+	Failed   int `json:"failed"`   // Calls that never reached SignalWire, marked failed
+	Errors   int `json:"errors"`   // Calls that could not be resynchronized
+}
+
+// RestoreActiveCalls reloads sessions that were live when the process
+// stopped and resynchronizes their state with SignalWire, so webhooks for
+// in-flight calls keep working after a restart. Call it once at startup,
+// before serving webhooks.
+//
+// Call config is not persisted, so per-call options such as a voicemail drop
+// are not available for restored calls. With a shared session store
+// sessions are resynchronized but not tracked locally, since lookups read
+// the store anyway and the call may belong to another instance.
+func (ci *CallInitiator) RestoreActiveCalls(ctx context.Context) (RestoreReport, error) {
+	var report RestoreReport
+
+	sessions, err := ci.store.Query(ctx, SessionFilter{
+		States: []CallState{StateQueued, StateInitiated, StateRinging, StateAnswered, StateInProgress},
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to load live sessions: %w", err)
+	}
+
+	shared, _ := ci.store.(SharedSessionStore)
+	track := shared == nil || !shared.Shared()
+
+	for _, session := range sessions {
+		session.mu.RLock()
+		callSID := session.SignalWireCallSID
+		initiatedAt := session.InitiatedAt
+		session.mu.RUnlock()
+
+		// InitiateCall was interrupted before SignalWire returned a call
+		if callSID == "" {
+			if time.Since(initiatedAt) > unplacedCallTimeout {
+				if err := ci.failUnplacedCall(ctx, session); err != nil {
+					log.Printf("[CallInitiator] Failed to close unplaced session %s: %v", session.ID, err)
+					report.Errors++
+				} else {
+					report.Failed++
+				}
+			}
+			continue
+		}
+
+		if track {
+			if _, loaded := ci.activeCalls.LoadOrStore(callSID, session); !loaded {
+				report.Restored++
+			}
+		}
+
+		_, updated, err := ci.resyncCall(ctx, callSID)
+		if err != nil {
+			log.Printf("[CallInitiator] Failed to resync call %s: %v", callSID, err)
+			ci.errorLog.Record("restore", callSID, err)
+			report.Errors++
+			continue
+		}
+		if updated {
+			report.Updated++
+		}
+
+		// Restored calls have no config, so whether they had status
+		// callbacks is unknown; poll them if polling is on
+		ci.startPolling(callSID, nil)
+	}
+
+	log.Printf("[CallInitiator] Restored %d live calls (%d updated, %d unplaced marked failed, %d errors)",
+		report.Restored, report.Updated, report.Failed, report.Errors)
+	return report, nil
+}
+
+// resyncCall applies the call's current SignalWire status, returning that
+// status and whether the session's state changed
+func (ci *CallInitiator) resyncCall(ctx context.Context, callSID string) (CallState, bool, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	status, err := ci.GetCallStatus(reqCtx, callSID)
+	if err != nil {
+		return "", false, err
+	}
+
+	state, ok := StateFromStatus(status.Status)
+	if !ok {
+		return "", false, fmt.Errorf("unknown call status %q", status.Status)
+	}
+
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return state, false, err
+	}
+	session.mu.RLock()
+	current := session.State
+	session.mu.RUnlock()
+
+	if state == current || !CanTransition(current, state) {
+		return state, false, nil
+	}
+	if err := ci.ApplyCallEvent(ctx, CallEvent{CallSID: callSID, State: state}); err != nil {
+		return state, false, err
+	}
+	return state, true, nil
+}
+
+// failUnplacedCall closes a session whose call was never placed. Such
+// sessions have no call SID to reload them by, so another instance closing
+// the same session first surfaces as ErrSessionConflict and is ignored.
+func (ci *CallInitiator) failUnplacedCall(ctx context.Context, session *CallSession) error {
+	_, err := ci.updateSession(ctx, session, func(session *CallSession) error {
+		if session.State.IsTerminal() {
+			return errNoChange
+		}
+		now := time.Now()
+		session.State = StateFailed
+		session.Status = StatusFailed
+		session.Outcome = OutcomeError
+		session.ErrorMessage = "interrupted by restart before the call was placed"
+		session.CompletedAt = &now
+		return nil
+	})
+	if errors.Is(err, errNoChange) || errors.Is(err, ErrSessionConflict) {
+		return nil
+	}
+	return err
+}
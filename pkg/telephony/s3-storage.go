@@ -0,0 +1,273 @@
+package telephony
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================
+// S3 RECORDING STORAGE
+// RecordingStorage for S3 and S3-compatible object stores (MinIO, R2, ...)
+// ============================================
+
+// s3PartSize is the multipart upload part size; S3's minimum for all but
+// the last part
+const s3PartSize = 5 << 20
+
+// S3RecordingStorage uploads recordings to an S3-compatible bucket, signing
+// requests with AWS Signature V4. Media longer than one part is uploaded in
+// parts as it is read, so a recording in progress needn't be held in
+// memory.
+type S3RecordingStorage struct {
+	Endpoint  string // e.g. "https://s3.us-east-1.amazonaws.com" or a MinIO URL
+	Region    string // Default "us-east-1"
+	Bucket    string
+	AccessKey string
+	SecretKey string
+
+	// HTTP client for uploads (default http.DefaultClient)
+	Client *http.Client
+}
+
+// Save uploads the media to Bucket/key (path-style) and returns its URL
+func (s S3RecordingStorage) Save(ctx context.Context, key, contentType string, media io.Reader) (string, error) {
+	objectURL := strings.TrimRight(s.Endpoint, "/") + "/" + s.Bucket + "/" + s3EscapePath(key)
+
+	// Small media goes up in one request
+	part := make([]byte, s3PartSize)
+	n, err := io.ReadFull(media, part)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		header := http.Header{"Content-Type": {contentType}}
+		if _, err := s.do(ctx, http.MethodPut, objectURL, header, part[:n]); err != nil {
+			return "", fmt.Errorf("failed to upload recording: %w", err)
+		}
+		return objectURL, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, objectURL+"?uploads", http.Header{"Content-Type": {contentType}}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to start upload: %w", err)
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(resp, &initiated); err != nil || initiated.UploadID == "" {
+		return "", fmt.Errorf("failed to start upload: bad response: %s", resp)
+	}
+	uploadURL := objectURL + "?uploadId=" + url.QueryEscape(initiated.UploadID)
+
+	type completedPart struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+	var parts []completedPart
+	abort := func(err error) (string, error) {
+		// Best effort, on a fresh context in case ctx is what failed
+		abortCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		s.do(abortCtx, http.MethodDelete, uploadURL, nil, nil)
+		return "", err
+	}
+
+	for number := 1; n > 0; number++ {
+		partURL := fmt.Sprintf("%s?partNumber=%d&uploadId=%s", objectURL, number, url.QueryEscape(initiated.UploadID))
+		etag, err := s.uploadPart(ctx, partURL, part[:n])
+		if err != nil {
+			return abort(fmt.Errorf("failed to upload part %d: %w", number, err))
+		}
+		parts = append(parts, completedPart{PartNumber: number, ETag: etag})
+
+		n, err = io.ReadFull(media, part)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return abort(fmt.Errorf("failed to read recording: %w", err))
+		}
+	}
+
+	body, _ := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	resp, err = s.do(ctx, http.MethodPost, uploadURL, http.Header{"Content-Type": {"application/xml"}}, body)
+	if err != nil {
+		return abort(fmt.Errorf("failed to complete upload: %w", err))
+	}
+	// S3 can report a failed completion with a 200 and an Error body
+	if bytes.Contains(resp, []byte("<Error>")) {
+		return abort(fmt.Errorf("failed to complete upload: %s", resp))
+	}
+	return objectURL, nil
+}
+
+// uploadPart uploads one part and returns its ETag
+func (s S3RecordingStorage) uploadPart(ctx context.Context, partURL string, data []byte) (string, error) {
+	req, err := s.newRequest(ctx, http.MethodPut, partURL, nil, data)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("S3 error (%d): %s", resp.StatusCode, body)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// do sends a signed request and returns the response body
+func (s S3RecordingStorage) do(ctx context.Context, method, rawURL string, header http.Header, body []byte) ([]byte, error) {
+	req, err := s.newRequest(ctx, method, rawURL, header, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("S3 error (%d): %s", resp.StatusCode, respBody)
+	}
+	return respBody, nil
+}
+
+// newRequest builds a request signed with AWS Signature V4
+func (s S3RecordingStorage) newRequest(ctx context.Context, method, rawURL string, header http.Header, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	s3Sign(req, s.AccessKey, s.SecretKey, s.region(), time.Now().UTC())
+	return req, nil
+}
+
+func (s S3RecordingStorage) region() string {
+	if s.Region == "" {
+		return "us-east-1"
+	}
+	return s.Region
+}
+
+func (s S3RecordingStorage) client() *http.Client {
+	if s.Client == nil {
+		return http.DefaultClient
+	}
+	return s.Client
+}
+
+// s3Sign adds an AWS Signature V4 Authorization header to a request whose
+// X-Amz-Content-Sha256 header is set, signing the host and every header set
+func s3Sign(req *http.Request, accessKey, secretKey, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	// Canonical headers: host plus every header set, lowercased and sorted
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// Canonical query: keys sorted, keys and values escaped ("uploads" becomes "uploads=")
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var params []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			params = append(params, s3Escape(key)+"="+s3Escape(value))
+		}
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		strings.Join(params, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Escape percent-encodes everything but unreserved characters, as SigV4
+// requires
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3EscapePath escapes each segment of an object key
+func s3EscapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,131 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ============================================
+// CALL SENTIMENT
+// How the caller feels, turn by turn and over the call
+// ============================================
+
+// Sentiment is how a stretch of speech comes across
+type Sentiment struct {
+	Score   float64 `json:"score"`             // -1 (very negative) to 1 (very positive)
+	Emotion string  `json:"emotion,omitempty"` // Strongest emotion, e.g. angry, frustrated, confused, happy
+}
+
+// SentimentSummary is the caller's sentiment over a call so far
+type SentimentSummary struct {
+	Average  float64        `json:"average"`
+	Min      float64        `json:"min"`
+	Last     float64        `json:"last"`
+	Turns    int            `json:"turns"`              // Caller turns scored
+	Emotions map[string]int `json:"emotions,omitempty"` // Turns per emotion
+}
+
+// add folds a turn's sentiment into the summary
+func (s *SentimentSummary) add(sentiment Sentiment) {
+	if s.Turns == 0 || sentiment.Score < s.Min {
+		s.Min = sentiment.Score
+	}
+	s.Average = (s.Average*float64(s.Turns) + sentiment.Score) / float64(s.Turns+1)
+	s.Last = sentiment.Score
+	s.Turns++
+	if sentiment.Emotion != "" {
+		if s.Emotions == nil {
+			s.Emotions = make(map[string]int)
+		}
+		s.Emotions[sentiment.Emotion]++
+	}
+}
+
+// average returns the average score, or nil for no summary
+func (s *SentimentSummary) average() *float64 {
+	if s == nil {
+		return nil
+	}
+	return &s.Average
+}
+
+// min returns the lowest score, or nil for no summary
+func (s *SentimentSummary) min() *float64 {
+	if s == nil {
+		return nil
+	}
+	return &s.Min
+}
+
+// SentimentEvent is delivered when a caller's sentiment drops below a
+// listener's threshold (see OnSentimentDrop)
+type SentimentEvent struct {
+	Call      CallSummary
+	Sentiment Sentiment        // The turn that crossed the threshold
+	Summary   SentimentSummary // The call so far, including that turn
+	Threshold float64
+}
+
+// sentimentListener is an OnSentimentDrop registration
+type sentimentListener struct {
+	threshold float64
+	listener  func(SentimentEvent)
+}
+
+// OnSentimentDrop registers a listener called when a caller turn scores
+// below threshold (e.g. -0.5) after one that didn't, so each dip is
+// reported once. Use it to escalate, e.g. with HandoffToAgent.
+func (ci *CallInitiator) OnSentimentDrop(threshold float64, listener func(SentimentEvent)) {
+	ci.hooks.mu.Lock()
+	defer ci.hooks.mu.Unlock()
+	ci.hooks.sentiment = append(ci.hooks.sentiment, sentimentListener{threshold: threshold, listener: listener})
+}
+
+// RecordSentiment attaches a score to the transcript segment spoken from
+// start (see RecordTranscript), if it was recorded. Caller scores are also
+// added to the session's Sentiment, and may fire OnSentimentDrop.
+func (ci *CallInitiator) RecordSentiment(ctx context.Context, callSID string, speaker Speaker, start time.Time, sentiment Sentiment) error {
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return err
+	}
+	session.mu.RLock()
+	sessionID := session.ID
+	origin := session.InitiatedAt
+	if session.AnsweredAt != nil {
+		origin = *session.AnsweredAt
+	}
+	session.mu.RUnlock()
+
+	if err := ci.transcripts.SetSentiment(ctx, sessionID, speaker, max(start.Sub(origin), 0), sentiment); err != nil {
+		return err
+	}
+	if speaker != SpeakerCaller {
+		return nil
+	}
+
+	var previous *float64
+	var summary SentimentSummary
+	session, err = ci.updateSession(ctx, session, func(session *CallSession) error {
+		previous, summary = nil, SentimentSummary{}
+		if session.Sentiment != nil {
+			last := session.Sentiment.Last
+			previous = &last
+			summary = *session.Sentiment
+			summary.Emotions = make(map[string]int, len(session.Sentiment.Emotions))
+			for emotion, turns := range session.Sentiment.Emotions {
+				summary.Emotions[emotion] = turns
+			}
+		}
+		summary.add(sentiment)
+		session.Sentiment = &summary
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save sentiment: %w", err)
+	}
+
+	ci.hooks.fireSentiment(session.Summary(), sentiment, summary, previous)
+	return nil
+}
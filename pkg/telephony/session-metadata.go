@@ -0,0 +1,208 @@
+package telephony
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================
+// SESSION METADATA
+// Typed access to free-form session data
+// ============================================
+
+// Metadata is free-form data stored with a session. Values set with Set are
+// kept in their JSON form (numbers as float64, times as RFC 3339 strings,
+// structs as maps), so they read back the same before and after the
+// session is saved to and loaded from a store.
+//
+// Metadata methods don't lock; use CallSession.SetMetadata and
+// CallSession.MetadataCopy from outside the session lock.
+type Metadata map[string]interface{}
+
+// NewMetadata copies values into a new, non-nil Metadata, converting them
+// as Set does
+func NewMetadata(values map[string]interface{}) Metadata {
+	m := make(Metadata, len(values))
+	for key, value := range values {
+		m.Set(key, value)
+	}
+	return m
+}
+
+// MetadataKey namespaces a key, e.g. MetadataKey("crm", "lead_id") is
+// "crm.lead_id". Applications should namespace their keys to stay clear of
+// the keys this package sets.
+func MetadataKey(namespace, key string) string {
+	return namespace + "." + key
+}
+
+// Set stores a value, creating the map if needed
+func (m *Metadata) Set(key string, value interface{}) {
+	if *m == nil {
+		*m = make(Metadata)
+	}
+	(*m)[key] = jsonValue(value)
+}
+
+// Delete removes a key
+func (m Metadata) Delete(key string) {
+	delete(m, key)
+}
+
+// Has reports whether a key is set
+func (m Metadata) Has(key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// GetString returns a string value
+func (m Metadata) GetString(key string) (string, bool) {
+	s, ok := m[key].(string)
+	return s, ok
+}
+
+// GetInt returns a whole-number value, including numbers that went through
+// JSON (float64) and numeric strings
+func (m Metadata) GetInt(key string) (int, bool) {
+	switch v := m[key].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		if v == math.Trunc(v) {
+			return int(v), true
+		}
+	case json.Number:
+		n, err := v.Int64()
+		return int(n), err == nil
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// GetFloat returns a numeric value
+func (m Metadata) GetFloat(key string) (float64, bool) {
+	switch v := m[key].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// GetBool returns a boolean value
+func (m Metadata) GetBool(key string) (bool, bool) {
+	switch v := m[key].(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// GetTime returns a time value, stored by Set as an RFC 3339 string
+func (m Metadata) GetTime(key string) (time.Time, bool) {
+	switch v := m[key].(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// GetStrings returns a list of strings
+func (m Metadata) GetStrings(key string) ([]string, bool) {
+	switch v := m[key].(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			items = append(items, s)
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+// Namespace returns the entries under a namespace, keyed without the
+// namespace prefix
+func (m Metadata) Namespace(namespace string) Metadata {
+	prefix := namespace + "."
+	entries := make(Metadata)
+	for key, value := range m {
+		if name, ok := strings.CutPrefix(key, prefix); ok {
+			entries[name] = value
+		}
+	}
+	return entries
+}
+
+// Copy returns a shallow copy
+func (m Metadata) Copy() Metadata {
+	c := make(Metadata, len(m))
+	for key, value := range m {
+		c[key] = value
+	}
+	return c
+}
+
+// jsonValue converts a value to the form it takes after a JSON round trip
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, float64:
+		return v
+	case int:
+		return float64(v)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return fmt.Sprint(value)
+	}
+	return decoded
+}
+
+// SetMetadata stores a metadata value under the session lock. Persist it
+// with the session store's Update.
+func (s *CallSession) SetMetadata(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Metadata.Set(key, value)
+}
+
+// MetadataCopy returns a snapshot of the session's metadata, safe to read
+// while the call is live
+func (s *CallSession) MetadataCopy() Metadata {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Metadata.Copy()
+}
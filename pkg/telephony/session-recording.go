@@ -0,0 +1,377 @@
+package telephony
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ============================================
+// SESSION RECORDING
+// Dual-channel recording of bridge sessions to RecordingStorage, caller on
+// the left and AI on the right, independent of SignalWire's recording
+// ============================================
+
+const (
+	recordingMaxQueued    = 10 * time.Second       // AI audio held waiting for caller audio to line up with
+	recordingBuffer       = 3000                   // Encoded chunks held for slow storage (~60s)
+	recordingBlockTimeout = 100 * time.Millisecond // How long audio routing waits on full storage before dropping
+)
+
+// RecordingFormat is the file format of session recordings
+type RecordingFormat string
+
+const (
+	RecordingFormatWAV  RecordingFormat = "wav"
+	RecordingFormatFLAC RecordingFormat = "flac"
+)
+
+// SessionRecordingConfig enables recording of bridge sessions
+type SessionRecordingConfig struct {
+	Storage RecordingStorage `json:"-"`      // Where recordings are written
+	Format  RecordingFormat  `json:"format"` // Default RecordingFormatWAV
+
+	// Tone played to both parties while recording, for two-party consent
+	// jurisdictions (nil for none; see DefaultRecordingNoticeConfig)
+	Notice *RecordingNoticeConfig `json:"notice,omitempty"`
+}
+
+// SetSessionRecording records sessions created afterwards into storage,
+// written as the call goes: caller audio on the left channel, audio played
+// to the caller (the AI, comfort noise, a barging supervisor) on the right.
+// A nil config disables recording.
+func (bridge *AudioStreamBridge) SetSessionRecording(config *SessionRecordingConfig) error {
+	if config != nil {
+		if config.Storage == nil {
+			return fmt.Errorf("session recording requires a storage")
+		}
+		if err := config.Notice.validate(); err != nil {
+			return err
+		}
+		switch config.Format {
+		case "":
+			config.Format = RecordingFormatWAV
+		case RecordingFormatWAV, RecordingFormatFLAC:
+		default:
+			return fmt.Errorf("unsupported recording format: %q", config.Format)
+		}
+	}
+
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+	bridge.recording = config
+	return nil
+}
+
+// sessionRecorder writes one session's recording. Caller audio is the
+// clock: audio played to the caller is queued and written alongside caller
+// audio as it arrives, as the two are heard together on the line.
+type sessionRecorder struct {
+	config    SessionRecordingConfig
+	sessionID string
+	key       string
+	rate      int // Recording sample rate, taken from the first audio
+	started   bool
+	closed    bool
+
+	queue      []int16 // Played audio not yet written
+	resamplers [2]*Resampler
+	frames     int64 // Stereo frames written
+
+	flac   *flacEncoder
+	chunks *AudioRing // Encoded audio on its way to storage
+	done   chan struct{}
+
+	storedAt string
+	err      error
+	mu       sync.Mutex
+}
+
+func newSessionRecorder(config SessionRecordingConfig, sessionID string, created time.Time) *sessionRecorder {
+	return &sessionRecorder{
+		config:    config,
+		sessionID: sessionID,
+		key:       fmt.Sprintf("recordings/%s/bridge-%s.%s", sessionID, created.UTC().Format("20060102T150405Z"), config.Format),
+		done:      make(chan struct{}),
+	}
+}
+
+// caller records caller audio in format, writing queued played audio
+// beside it
+func (r *sessionRecorder) caller(format AudioFormat, audio []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	samples := r.samples(0, format, audio)
+	if samples == nil {
+		return
+	}
+	played := r.queue[:min(len(samples), len(r.queue))]
+	r.write(samples, played)
+	r.queue = r.queue[len(played):]
+}
+
+// played queues audio sent to the caller in format
+func (r *sessionRecorder) played(format AudioFormat, audio []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	samples := r.samples(1, format, audio)
+	if samples == nil {
+		return
+	}
+	r.queue = append(r.queue, samples...)
+
+	// The caller's audio has stalled; write the excess beside silence
+	if limit := int(recordingMaxQueued.Seconds()) * r.rate; len(r.queue) > limit {
+		excess := len(r.queue) - limit
+		r.write(make([]int16, excess), r.queue[:excess])
+		r.queue = append(r.queue[:0], r.queue[excess:]...)
+	}
+}
+
+// samples decodes audio for a channel at the recording's rate, starting
+// the recording with the first audio. It returns nil once closed or on
+// failure.
+func (r *sessionRecorder) samples(channel int, format AudioFormat, audio []byte) []int16 {
+	if r.closed || len(audio) == 0 {
+		return nil
+	}
+	if !r.started {
+		r.started = true
+		r.rate = format.SampleRate
+		if err := r.start(); err != nil {
+			log.Printf("[AudioStreamBridge] Failed to start recording %s: %v", r.sessionID, err)
+			r.closed, r.err = true, err
+			close(r.done)
+			return nil
+		}
+	}
+
+	if format.SampleRate != r.rate {
+		resampler := r.resamplers[channel]
+		if resampler == nil || resampler.fromRate != format.SampleRate {
+			var err error
+			if resampler, err = NewResampler(format.SampleRate, r.rate, ResampleSinc); err != nil {
+				return nil
+			}
+			r.resamplers[channel] = resampler
+		}
+		pcm := audio
+		if format.Encoding == AudioFormatMulaw.Encoding {
+			pcm = pcmToBytes(pcmSamples(format, audio))
+		}
+		resampled, err := resampler.Process(pcm)
+		if err != nil {
+			return nil
+		}
+		samples := pcmSamples(AudioFormatPCM, resampled)
+		ReleaseAudioBuffer(resampled)
+		return samples
+	}
+	return pcmSamples(format, audio)
+}
+
+// start opens the recording in storage, which reads it as it is written
+func (r *sessionRecorder) start() error {
+	if r.rate <= 0 {
+		return fmt.Errorf("invalid sample rate: %d", r.rate)
+	}
+
+	reader, writer := io.Pipe()
+	r.chunks = NewAudioRing(AudioRingConfig{
+		Capacity:     recordingBuffer,
+		Overflow:     OverflowBlock,
+		BlockTimeout: recordingBlockTimeout,
+	})
+
+	contentType := "audio/wav"
+	if r.config.Format == RecordingFormatFLAC {
+		contentType = "audio/flac"
+		var err error
+		if r.flac, err = newFLACEncoder(r, r.rate, 2); err != nil {
+			return err
+		}
+	} else {
+		// Sizes aren't known until the end; 0xFFFFFFFF marks them unknown
+		header, err := EncodeWAV(nil, AudioFormat{SampleRate: r.rate, Channels: 2, Encoding: AudioFormatPCM.Encoding, BitDepth: 16})
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint32(header[4:], 0xFFFFFFFF)
+		binary.LittleEndian.PutUint32(header[len(header)-4:], 0xFFFFFFFF)
+		r.Write(header)
+	}
+
+	// Copy encoded audio into the pipe; keep draining if storage gives up
+	go func() {
+		for {
+			chunk, ok := r.chunks.Pop(context.Background())
+			if !ok {
+				writer.Close()
+				return
+			}
+			writer.Write(chunk)
+			ReleaseAudioBuffer(chunk)
+		}
+	}()
+
+	go func() {
+		storedAt, err := r.config.Storage.Save(context.Background(), r.key, contentType, reader)
+		reader.CloseWithError(io.ErrClosedPipe)
+		r.mu.Lock()
+		r.storedAt, r.err = storedAt, err
+		r.mu.Unlock()
+		close(r.done)
+	}()
+
+	log.Printf("[AudioStreamBridge] Recording session %s to %s", r.sessionID, r.key)
+	return nil
+}
+
+// write encodes caller and played samples as stereo frames, padding played
+// audio with silence
+func (r *sessionRecorder) write(caller, played []int16) {
+	frames := make([]int16, 2*len(caller))
+	for i, s := range caller {
+		frames[2*i] = s
+		if i < len(played) {
+			frames[2*i+1] = played[i]
+		}
+	}
+	r.frames += int64(len(caller))
+
+	if r.flac != nil {
+		if err := r.flac.write(frames); err != nil {
+			log.Printf("[AudioStreamBridge] Recording %s encode error: %v", r.sessionID, err)
+		}
+		return
+	}
+	r.Write(pcmToBytes(frames))
+}
+
+// Write queues encoded audio for storage; it is the FLAC encoder's output
+func (r *sessionRecorder) Write(p []byte) (int, error) {
+	chunk := append(GetAudioBuffer(len(p))[:0], p...)
+	if lost := r.chunks.Push(context.Background(), chunk); lost != nil {
+		ReleaseAudioBuffer(lost)
+		log.Printf("[AudioStreamBridge] Recording %s storage falling behind, dropped audio", r.sessionID)
+	}
+	return len(p), nil
+}
+
+// finish writes what's queued, closes the recording and waits for storage.
+// It returns where the recording was stored and its length.
+func (r *sessionRecorder) finish() (string, time.Duration, error) {
+	r.mu.Lock()
+	if !r.started {
+		r.closed = true
+		r.mu.Unlock()
+		return "", 0, nil
+	}
+	if !r.closed {
+		r.closed = true
+		if len(r.queue) > 0 {
+			r.write(make([]int16, len(r.queue)), r.queue)
+			r.queue = nil
+		}
+		if r.flac != nil {
+			if err := r.flac.close(); err != nil {
+				log.Printf("[AudioStreamBridge] Recording %s encode error: %v", r.sessionID, err)
+			}
+		}
+		r.chunks.Close()
+	}
+	r.mu.Unlock()
+
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	duration := time.Duration(r.frames) * time.Second / time.Duration(max(r.rate, 1))
+	if r.err != nil {
+		return "", duration, r.err
+	}
+
+	// A local WAV file can have its sizes filled in now they're known
+	if r.config.Format == RecordingFormatWAV {
+		switch r.config.Storage.(type) {
+		case FileRecordingStorage, *FileRecordingStorage:
+			if err := patchWAVSizes(r.storedAt, r.frames*4); err != nil {
+				log.Printf("[AudioStreamBridge] Failed to finalize recording %s: %v", r.storedAt, err)
+			}
+		}
+	}
+	return r.storedAt, duration, nil
+}
+
+// patchWAVSizes writes the RIFF and data chunk sizes into a WAV file
+// written with unknown sizes by the session recorder
+func patchWAVSizes(path string, dataBytes int64) error {
+	if dataBytes > 0xFFFFFFFF-36 {
+		return nil // Too long for WAV's 32-bit sizes; leave them unknown
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(36+dataBytes))
+	if _, err := f.WriteAt(size[:], 4); err != nil {
+		f.Close()
+		return err
+	}
+	binary.LittleEndian.PutUint32(size[:], uint32(dataBytes))
+	if _, err := f.WriteAt(size[:], 40); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// pcmToBytes encodes samples as 16-bit little-endian PCM
+func pcmToBytes(samples []int16) []byte {
+	out := make([]byte, 2*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(s))
+	}
+	return out
+}
+
+// finishRecording closes a session's recording, if any, once its audio has
+// stopped, and announces where it was stored
+func (bridge *AudioStreamBridge) finishRecording(session *BridgeSession) {
+	if session.recorder == nil {
+		return
+	}
+	go func() {
+		storedAt, duration, err := session.recorder.finish()
+		if err != nil {
+			log.Printf("[AudioStreamBridge] Recording of session %s failed: %v", session.SessionID, err)
+			return
+		}
+		if storedAt == "" {
+			return // No audio
+		}
+
+		log.Printf("[AudioStreamBridge] Recorded session %s (%s) to %s", session.SessionID, duration.Round(time.Second), storedAt)
+		session.mu.RLock()
+		callSID := session.CallSID
+		session.mu.RUnlock()
+		publishEvent(bridge.events, Event{
+			Type:      EventRecordingSaved,
+			CallSID:   callSID,
+			SessionID: session.SessionID,
+			Data: map[string]interface{}{
+				"stored_at":        storedAt,
+				"format":           string(session.recorder.config.Format),
+				"duration_seconds": duration.Seconds(),
+			},
+		})
+	}()
+}
@@ -0,0 +1,538 @@
+package telephony
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============================================
+// CALL SESSION STORAGE
+// Pluggable persistence for call sessions
+// ============================================
+
+// ErrSessionNotFound is returned when no stored session matches
+var ErrSessionNotFound = errors.New("call session not found")
+
+// ErrSessionConflict is returned by Update when the stored session was
+// changed by someone else since it was read
+var ErrSessionConflict = errors.New("call session was updated concurrently")
+
+// CallSessionStore persists call sessions for a CallInitiator
+type CallSessionStore interface {
+	Insert(ctx context.Context, session *CallSession) error
+	// Update saves a session if the stored copy still has session.Version
+	// (otherwise ErrSessionConflict), then increments session.Version.
+	// Callers hold the session's lock.
+	Update(ctx context.Context, session *CallSession) error
+	GetBySID(ctx context.Context, callSID string) (*CallSession, error)
+	Query(ctx context.Context, filter SessionFilter) ([]*CallSession, error)
+}
+
+// SessionFilter selects sessions in CallSessionStore.Query; zero fields
+// match everything
+type SessionFilter struct {
+	AgencyID         *uuid.UUID
+	CampaignID       *uuid.UUID
+	Direction        CallDirection
+	States           []CallState
+	Disposition      string         // Sessions with this disposition code
+	Goal             string         // Sessions with an outcome for this conversation goal
+	GoalAchieved     *bool          // Sessions whose goal outcome was (or wasn't) achieved
+	SentimentBelow   *float64       // Sessions whose caller sentiment dipped below this
+	Attestations     []Attestation  // Sessions with any of these attestation levels
+	AttestationBelow Attestation    // Sessions attested below this level (unreported excluded)
+	Since            time.Time      // Initiated at or after
+	Until            time.Time      // Initiated before
+	After            *SessionCursor // Resume after this session (for paging)
+	Limit            int
+}
+
+// SessionCursor marks a position in Query's newest-first order
+type SessionCursor struct {
+	InitiatedAt time.Time `json:"initiated_at"`
+	ID          uuid.UUID `json:"id"`
+}
+
+// CursorFor returns the cursor positioned at a session
+func CursorFor(session *CallSession) *SessionCursor {
+	return &SessionCursor{InitiatedAt: session.InitiatedAt, ID: session.ID}
+}
+
+// sortsBefore reports whether a session comes before a position in
+// newest-first order (ties broken by ID, descending)
+func (c SessionCursor) sortsBefore(initiatedAt time.Time, id uuid.UUID) bool {
+	if !initiatedAt.Equal(c.InitiatedAt) {
+		return initiatedAt.After(c.InitiatedAt)
+	}
+	return bytes.Compare(id[:], c.ID[:]) > 0
+}
+
+// matches reports whether a session passes the filter
+func (f SessionFilter) matches(session *CallSession) bool {
+	if f.AgencyID != nil && session.AgencyID != *f.AgencyID {
+		return false
+	}
+	if f.CampaignID != nil && (session.CampaignID == nil || *session.CampaignID != *f.CampaignID) {
+		return false
+	}
+	if f.Direction != "" && session.Direction != f.Direction {
+		return false
+	}
+	if f.Disposition != "" && session.Disposition != f.Disposition {
+		return false
+	}
+	if f.Goal != "" && (session.GoalOutcome == nil || session.GoalOutcome.Goal != f.Goal) {
+		return false
+	}
+	if f.GoalAchieved != nil && (session.GoalOutcome == nil || session.GoalOutcome.Achieved != *f.GoalAchieved) {
+		return false
+	}
+	if f.SentimentBelow != nil && (session.Sentiment == nil || session.Sentiment.Min >= *f.SentimentBelow) {
+		return false
+	}
+	if len(f.Attestations) > 0 {
+		found := false
+		for _, attestation := range f.Attestations {
+			if session.Attestation == attestation {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.AttestationBelow != "" && !session.Attestation.Below(f.AttestationBelow) {
+		return false
+	}
+	if len(f.States) > 0 {
+		found := false
+		for _, state := range f.States {
+			if session.State == state {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && session.InitiatedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !session.InitiatedAt.Before(f.Until) {
+		return false
+	}
+	if f.After != nil && (f.After.sortsBefore(session.InitiatedAt, session.ID) || session.ID == f.After.ID) {
+		return false
+	}
+	return true
+}
+
+// ============================================
+// POSTGRES SESSION STORE
+// ============================================
+
+// PostgresSessionStore keeps call sessions in the call_sessions table
+type PostgresSessionStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresSessionStore creates a session store backed by Postgres
+func NewPostgresSessionStore(db *pgxpool.Pool) *PostgresSessionStore {
+	return &PostgresSessionStore{db: db}
+}
+
+// Insert inserts a new call session
+func (s *PostgresSessionStore) Insert(ctx context.Context, session *CallSession) error {
+	query := `
+		INSERT INTO call_sessions (
+			id, campaign_id, target_id, agency_id,
+			from_number, to_number, status, call_state,
+			initiated_at, metadata, created_at, updated_at,
+			direction, caller_name, signalwire_call_sid, amd_policy
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''), $16)
+	`
+
+	metadataJSON, _ := json.Marshal(session.Metadata)
+	var amdJSON []byte
+	if session.amd != nil {
+		amdJSON, _ = json.Marshal(session.amd)
+	}
+
+	_, err := s.db.Exec(ctx, query,
+		session.ID, session.CampaignID, session.TargetID, session.AgencyID,
+		session.FromNumber, session.ToNumber, session.Status, session.State,
+		session.InitiatedAt, metadataJSON, session.CreatedAt, session.UpdatedAt,
+		session.Direction, session.CallerName, session.SignalWireCallSID, amdJSON,
+	)
+
+	return err
+}
+
+// Update updates an existing call session
+func (s *PostgresSessionStore) Update(ctx context.Context, session *CallSession) error {
+	var sets []string
+	var args []interface{}
+	set := func(clause string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf(clause, len(args)))
+	}
+
+	metadataJSON, _ := json.Marshal(session.Metadata)
+	var goalOutcomeJSON []byte
+	if session.GoalOutcome != nil {
+		goalOutcomeJSON, _ = json.Marshal(session.GoalOutcome)
+	}
+	var sentimentJSON []byte
+	if session.Sentiment != nil {
+		sentimentJSON, _ = json.Marshal(session.Sentiment)
+	}
+
+	set("signalwire_call_sid = NULLIF($%d, '')", session.SignalWireCallSID)
+	set("status = $%d", session.Status)
+	set("call_state = $%d", session.State)
+	set("ringing_at = $%d", session.RingingAt)
+	set("answered_at = $%d", session.AnsweredAt)
+	set("completed_at = $%d", session.CompletedAt)
+	set("duration_seconds = $%d", session.DurationSeconds)
+	set("talk_time_seconds = $%d", session.TalkTimeSeconds)
+	set("ring_time_seconds = $%d", session.RingTimeSeconds)
+	set("outcome = $%d", session.Outcome)
+	set("outcome_reason = $%d", session.OutcomeReason)
+	set("recording_url = $%d", session.RecordingURL)
+	set("recording_duration_seconds = $%d", session.RecordingDuration)
+	set("transcript_url = $%d", session.TranscriptURL)
+	set("transcript_text = $%d", session.TranscriptText)
+	set("voicemail_detected = $%d", session.VoicemailDetected)
+	set("voicemail_message_left = $%d", session.VoicemailMessageLeft)
+	set("audio_quality_score = $%d", session.AudioQuality)
+	set("transcription_confidence = $%d", session.Confidence)
+	set("cost_usd = $%d", session.CostUSD)
+	set("error_code = $%d", session.ErrorCode)
+	set("error_message = $%d", session.ErrorMessage)
+	set("metadata = $%d", metadataJSON)
+	set("updated_at = $%d", session.UpdatedAt)
+	set("bridge_session_id = $%d", session.BridgeSessionID)
+	set("answered_by = $%d", session.AnsweredBy)
+	set("disposition = $%d", session.Disposition)
+	set("disposition_notes = $%d", session.DispositionNotes)
+	set("disposition_at = $%d", session.DispositionAt)
+	set("hangup_cause = $%d", session.HangupCause)
+	set("sip_response_code = $%d", session.SIPResponseCode)
+	set("recording_sid = $%d", session.RecordingSID)
+	set("recording_channels = $%d", session.RecordingChannels)
+	set("recording_stored_at = $%d", session.RecordingStoredAt)
+	set("attestation = $%d", session.Attestation)
+	set("verstat = $%d", session.Verstat)
+	set("goal_outcome = $%d", goalOutcomeJSON)
+	set("sentiment = $%d", sentimentJSON)
+	set("last_sequence = $%d", session.lastSequence)
+	set("last_event_at = $%d", nullTime(session.lastEventAt))
+
+	args = append(args, session.ID, session.Version)
+	query := "UPDATE call_sessions SET\n\t\t\t" + strings.Join(sets, ",\n\t\t\t") +
+		",\n\t\t\tversion = version + 1" +
+		fmt.Sprintf("\n\t\tWHERE id = $%d AND version = $%d", len(args)-1, len(args))
+
+	tag, err := s.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := s.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM call_sessions WHERE id = $1)`, session.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrSessionNotFound
+		}
+		return ErrSessionConflict
+	}
+
+	session.Version++
+	return nil
+}
+
+// sessionColumns is the column list read by scanSession
+const sessionColumns = `
+		id, campaign_id, target_id, agency_id,
+		COALESCE(signalwire_call_sid, ''), from_number, to_number,
+		status, call_state,
+		initiated_at, ringing_at, answered_at, completed_at,
+		duration_seconds, talk_time_seconds, ring_time_seconds,
+		outcome, outcome_reason,
+		recording_url, recording_duration_seconds,
+		transcript_url, transcript_text,
+		voicemail_detected, voicemail_message_left,
+		audio_quality_score, transcription_confidence,
+		cost_usd, error_code, error_message,
+		metadata, created_at, updated_at,
+		COALESCE(direction, 'outbound'), COALESCE(caller_name, ''),
+		COALESCE(bridge_session_id, ''), answered_by,
+		disposition, disposition_notes, disposition_at, version,
+		hangup_cause, sip_response_code,
+		recording_sid, recording_channels, recording_stored_at,
+		attestation, verstat, goal_outcome, sentiment, amd_policy,
+		last_sequence, last_event_at`
+
+// GetBySID retrieves a call session by SignalWire SID
+func (s *PostgresSessionStore) GetBySID(ctx context.Context, callSID string) (*CallSession, error) {
+	query := `SELECT ` + sessionColumns + `
+		FROM call_sessions
+		WHERE signalwire_call_sid = $1
+	`
+
+	session, err := scanSession(s.db.QueryRow(ctx, query, callSID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	return session, err
+}
+
+// Query returns sessions matching a filter, newest first
+func (s *PostgresSessionStore) Query(ctx context.Context, filter SessionFilter) ([]*CallSession, error) {
+	var conditions []string
+	var args []interface{}
+	where := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.AgencyID != nil {
+		where("agency_id = $%d", *filter.AgencyID)
+	}
+	if filter.CampaignID != nil {
+		where("campaign_id = $%d", *filter.CampaignID)
+	}
+	if filter.Direction != "" {
+		where("COALESCE(direction, 'outbound') = $%d", string(filter.Direction))
+	}
+	if filter.Disposition != "" {
+		where("disposition = $%d", filter.Disposition)
+	}
+	if filter.Goal != "" {
+		where("goal_outcome->>'goal' = $%d", filter.Goal)
+	}
+	if filter.GoalAchieved != nil {
+		where("(goal_outcome->>'achieved')::boolean = $%d", *filter.GoalAchieved)
+	}
+	if filter.SentimentBelow != nil {
+		where("(sentiment->>'min')::double precision < $%d", *filter.SentimentBelow)
+	}
+	if len(filter.Attestations) > 0 {
+		where("attestation = ANY($%d)", attestationStrings(filter.Attestations))
+	}
+	if filter.AttestationBelow != "" {
+		where("attestation = ANY($%d)", attestationStrings(attestationsBelow(filter.AttestationBelow)))
+	}
+	if len(filter.States) > 0 {
+		states := make([]string, len(filter.States))
+		for i, state := range filter.States {
+			states[i] = string(state)
+		}
+		where("call_state = ANY($%d)", states)
+	}
+	if !filter.Since.IsZero() {
+		where("initiated_at >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		where("initiated_at < $%d", filter.Until)
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.InitiatedAt, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(initiated_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	query := `SELECT ` + sessionColumns + `
+		FROM call_sessions`
+	if len(conditions) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(conditions, " AND ")
+	}
+	query += "\n\t\tORDER BY initiated_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*CallSession
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// scanSession reads a row selected with sessionColumns
+func scanSession(row pgx.Row) (*CallSession, error) {
+	var session CallSession
+	var metadataJSON, goalOutcomeJSON, sentimentJSON, amdJSON []byte
+	var lastEventAt *time.Time
+
+	err := row.Scan(
+		&session.ID, &session.CampaignID, &session.TargetID, &session.AgencyID,
+		&session.SignalWireCallSID, &session.FromNumber, &session.ToNumber,
+		&session.Status, &session.State,
+		&session.InitiatedAt, &session.RingingAt, &session.AnsweredAt, &session.CompletedAt,
+		&session.DurationSeconds, &session.TalkTimeSeconds, &session.RingTimeSeconds,
+		&session.Outcome, &session.OutcomeReason,
+		&session.RecordingURL, &session.RecordingDuration,
+		&session.TranscriptURL, &session.TranscriptText,
+		&session.VoicemailDetected, &session.VoicemailMessageLeft,
+		&session.AudioQuality, &session.Confidence,
+		&session.CostUSD, &session.ErrorCode, &session.ErrorMessage,
+		&metadataJSON, &session.CreatedAt, &session.UpdatedAt,
+		&session.Direction, &session.CallerName, &session.BridgeSessionID,
+		&session.AnsweredBy,
+		&session.Disposition, &session.DispositionNotes, &session.DispositionAt,
+		&session.Version,
+		&session.HangupCause, &session.SIPResponseCode,
+		&session.RecordingSID, &session.RecordingChannels, &session.RecordingStoredAt,
+		&session.Attestation, &session.Verstat, &goalOutcomeJSON, &sentimentJSON,
+		&amdJSON,
+		&session.lastSequence, &lastEventAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal(metadataJSON, &session.Metadata)
+	if goalOutcomeJSON != nil {
+		session.GoalOutcome = &GoalOutcome{}
+		json.Unmarshal(goalOutcomeJSON, session.GoalOutcome)
+	}
+	if sentimentJSON != nil {
+		session.Sentiment = &SentimentSummary{}
+		json.Unmarshal(sentimentJSON, session.Sentiment)
+	}
+	if lastEventAt != nil {
+		session.lastEventAt = *lastEventAt
+	}
+	if amdJSON != nil {
+		session.amd = &amdPolicy{}
+		json.Unmarshal(amdJSON, session.amd)
+	}
+
+	return &session, nil
+}
+
+// nullTime returns nil for a zero time, so it is stored as NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// ============================================
+// IN-MEMORY SESSION STORE
+// ============================================
+
+// MemorySessionStore keeps call sessions in memory, for deployments without
+// Postgres and for development. Sessions are stored by reference, so reads
+// return the same *CallSession the initiator updates.
+type MemorySessionStore struct {
+	sessions map[uuid.UUID]*CallSession
+	bySID    map[string]*CallSession
+	mu       sync.RWMutex
+}
+
+// NewMemorySessionStore creates an in-memory session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[uuid.UUID]*CallSession),
+		bySID:    make(map[string]*CallSession),
+	}
+}
+
+// Insert stores a new session
+func (s *MemorySessionStore) Insert(ctx context.Context, session *CallSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.sessions[session.ID]; exists {
+		return fmt.Errorf("session %s already exists", session.ID)
+	}
+	s.sessions[session.ID] = session
+	if session.SignalWireCallSID != "" {
+		s.bySID[session.SignalWireCallSID] = session
+	}
+	return nil
+}
+
+// Update re-indexes a stored session (its call SID may have been assigned).
+// Sessions are shared pointers within the process, so only a different
+// copy of the session can conflict.
+func (s *MemorySessionStore) Update(ctx context.Context, session *CallSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, exists := s.sessions[session.ID]
+	if !exists {
+		return ErrSessionNotFound
+	}
+	if stored != session && stored.Version != session.Version {
+		return ErrSessionConflict
+	}
+	session.Version++
+	s.sessions[session.ID] = session
+	if session.SignalWireCallSID != "" {
+		s.bySID[session.SignalWireCallSID] = session
+	}
+	return nil
+}
+
+// GetBySID returns the session for a SignalWire call SID
+func (s *MemorySessionStore) GetBySID(ctx context.Context, callSID string) (*CallSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.bySID[callSID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// Query returns sessions matching a filter, newest first
+func (s *MemorySessionStore) Query(ctx context.Context, filter SessionFilter) ([]*CallSession, error) {
+	s.mu.RLock()
+	var sessions []*CallSession
+	for _, session := range s.sessions {
+		session.mu.RLock()
+		match := filter.matches(session)
+		session.mu.RUnlock()
+		if match {
+			sessions = append(sessions, session)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return CursorFor(sessions[j]).sortsBefore(sessions[i].InitiatedAt, sessions[i].ID)
+	})
+	if filter.Limit > 0 && len(sessions) > filter.Limit {
+		sessions = sessions[:filter.Limit]
+	}
+	return sessions, nil
+}
@@ -19,7 +19,10 @@ import (
 // Real-time WebSocket audio streaming for phone calls
 // ============================================
 
-// SignalWireAudioBridge manages WebSocket connections for SignalWire calls
+// SignalWireAudioBridge manages WebSocket connections for SignalWire calls.
+// SignalWire connects to it, so it never dials out and signalwire.TransportConfig
+// (proxy, dialer, TLS) doesn't apply; tune incoming connections with SetUpgrader
+// and terminate TLS in the HTTP server that mounts it
 type SignalWireAudioBridge struct {
 	// Active call sessions
 	calls map[string]*SignalWireCallSession
@@ -30,11 +33,13 @@ type SignalWireAudioBridge struct {
 	// Configuration
 	projectID      string
 	authToken      string
-	spaceURL       string
-	websocketBase  string
+
+	// WebSocket transport
+	upgrader       websocket.Upgrader
 
 	// Audio routing
 	audioRouter    *AudioStreamBridge
+	jitterConfig   JitterBufferConfig
 
 	// Lifecycle
 	ctx            context.Context
@@ -42,21 +47,35 @@ type SignalWireAudioBridge struct {
 }
 
 // NewSignalWireAudioBridge creates a new audio bridge
-func NewSignalWireAudioBridge(projectID, authToken, space string, audioRouter *AudioStreamBridge) *SignalWireAudioBridge {
+func NewSignalWireAudioBridge(projectID, authToken string, audioRouter *AudioStreamBridge) *SignalWireAudioBridge {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &SignalWireAudioBridge{
 		calls:         make(map[string]*SignalWireCallSession),
 		projectID:     projectID,
 		authToken:     authToken,
-		spaceURL:      fmt.Sprintf("https://%s", space),
-		websocketBase: fmt.Sprintf("wss://%s", space),
+		upgrader:      signalWireUpgrader,
 		audioRouter:   audioRouter,
+		jitterConfig:  DefaultJitterBufferConfig(),
 		ctx:           ctx,
 		cancel:        cancel,
 	}
 }
 
+// SetJitterBuffer configures the jitter buffer for inbound media on new
+// streams; a zero TargetDelay releases frames as soon as they're complete
+func (bridge *SignalWireAudioBridge) SetJitterBuffer(config JitterBufferConfig) {
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+	bridge.jitterConfig = config
+}
+
+// SetUpgrader replaces the upgrader used for incoming media WebSockets
+// (buffer sizes, origin checks, compression)
+func (bridge *SignalWireAudioBridge) SetUpgrader(upgrader websocket.Upgrader) {
+	bridge.upgrader = upgrader
+}
+
 // ============================================
 // WEBSOCKET UPGRADE & CONNECTION HANDLING
 // ============================================
@@ -72,10 +91,19 @@ var signalWireUpgrader = websocket.Upgrader{
 
 // HandleWebSocketConnection handles incoming WebSocket connections from SignalWire
 func (bridge *SignalWireAudioBridge) HandleWebSocketConnection(w http.ResponseWriter, r *http.Request) {
-	// Extract session ID from URL path
-	sessionID := r.URL.Query().Get("session_id")
+	query := r.URL.Query()
+	callSID := query.Get("call_sid")
+
+	// Resolve the bridge session, either directly or through the call SID so
+	// that additional streams for the same call share one logical session
+	sessionID := query.Get("session_id")
+	if sessionID == "" && callSID != "" {
+		if session := bridge.audioRouter.GetSessionByCallSID(callSID); session != nil {
+			sessionID = session.SessionID
+		}
+	}
 	if sessionID == "" {
-		http.Error(w, "session_id required", http.StatusBadRequest)
+		http.Error(w, "session_id or call_sid required", http.StatusBadRequest)
 		return
 	}
 
@@ -86,24 +114,41 @@ func (bridge *SignalWireAudioBridge) HandleWebSocketConnection(w http.ResponseWr
 		return
 	}
 
+	route := StreamRoute(query.Get("route"))
+	if route != "" && route != StreamRouteAI && route != StreamRouteTap && route != StreamRouteSupervisor {
+		http.Error(w, "invalid route", http.StatusBadRequest)
+		return
+	}
+
 	log.Printf("[SignalWireBridge] Incoming WebSocket connection for session: %s", sessionID)
 
 	// Upgrade HTTP to WebSocket
-	conn, err := signalWireUpgrader.Upgrade(w, r, nil)
+	conn, err := bridge.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("[SignalWireBridge] WebSocket upgrade failed: %v", err)
 		return
 	}
 
+	bridge.mu.RLock()
+	jitterConfig := bridge.jitterConfig
+	bridge.mu.RUnlock()
+
 	// Create SignalWire call session
 	callSession := &SignalWireCallSession{
 		ID:              uuid.New().String(),
 		SessionID:       sessionID,
-		SignalWireCallSID: r.URL.Query().Get("call_sid"),
+		SignalWireCallSID: callSID,
+		StreamName:      query.Get("stream"),
+		Route:           route,
+		Track:           query.Get("track"),
 		Conn:            conn,
 		ConnectedAt:     time.Now(),
 		AudioInChan:     make(chan []byte, 100),
 		AudioOutChan:    make(chan []byte, 100),
+		OutboundTrackChan: make(chan []byte, 100),
+		done:            make(chan struct{}),
+		MediaFormat:     AudioFormatMulaw,
+		jitterConfig:    jitterConfig,
 		EventChan:       make(map[string]interface{}),
 		ctx:             bridge.ctx,
 		mu:              sync.RWMutex{},
@@ -122,7 +167,11 @@ func (bridge *SignalWireAudioBridge) HandleWebSocketConnection(w http.ResponseWr
 	go callSession.writePump()
 
 	// Link with audio router
-	bridge.audioRouter.LinkSignalWireSession(sessionID, callSession)
+	if err := bridge.audioRouter.LinkSignalWireSession(sessionID, callSession); err != nil {
+		log.Printf("[SignalWireBridge] Failed to link stream: %v", err)
+		callSession.Close()
+		return
+	}
 
 	// Send connection established event
 	callSession.SendEvent("connected", map[string]interface{}{
@@ -143,6 +192,11 @@ type SignalWireCallSession struct {
 	SessionID         string `json:"session_id"`         // Links to AudioStreamSession
 	SignalWireCallSID string `json:"signalwire_call_sid"`
 
+	// Stream identity when a call has several streams
+	StreamName string      `json:"stream_name,omitempty"`
+	Route      StreamRoute `json:"route,omitempty"`
+	Track      string      `json:"track,omitempty"`
+
 	// WebSocket connection
 	Conn *websocket.Conn
 
@@ -151,8 +205,30 @@ type SignalWireCallSession struct {
 	LastActivityAt  time.Time `json:"last_activity_at"`
 
 	// Audio channels (bidirectional)
-	AudioInChan  chan []byte // Audio FROM SignalWire (phone mic)
-	AudioOutChan chan []byte // Audio TO SignalWire (phone speaker)
+	AudioInChan  chan []byte // Audio FROM SignalWire (phone mic), pooled: the receiver releases it
+	AudioOutChan chan []byte // Audio TO SignalWire (phone speaker), pooled: released once sent
+
+	// Outbound track audio (what the caller hears) for streams with track
+	// "both" or "outbound", pooled: the receiver releases it
+	OutboundTrackChan chan []byte
+
+	// Closed by Close under outMu before AudioOutChan is (see SendAudio)
+	done  chan struct{}
+	outMu sync.RWMutex
+
+	// Media codec from the start event (see PipelineFormat)
+	MediaFormat AudioFormat `json:"media_format"`
+	codec       mediaCodec
+	codecMu     sync.Mutex
+
+	// Inbound and outbound track jitter buffers, created once the media
+	// format is known
+	jitterConfig   JitterBufferConfig
+	jitter         *JitterBuffer
+	outboundJitter *JitterBuffer
+
+	// Outbound audio on its way to SignalWire (see writePump)
+	framer audioFramer
 
 	// Event handling
 	EventChan map[string]interface{} `json:"-"`
@@ -209,14 +285,19 @@ func (cs *SignalWireCallSession) readPump() {
 	}
 }
 
-// writePump writes audio data to SignalWire WebSocket
+// writePump writes audio data to SignalWire WebSocket, re-framed into 20ms
+// frames paced in real time (see audioFramer)
 func (cs *SignalWireCallSession) writePump() {
-	ticker := time.NewTicker(54 * time.Millisecond) // ~20ms audio chunks at 8kHz
+	ticker := time.NewTicker(54 * time.Millisecond) // Keepalive ping
 	defer ticker.Stop()
 	defer func() {
 		cs.Conn.Close()
 	}()
 
+	framer := &cs.framer
+	pace := time.NewTimer(0)
+	defer pace.Stop()
+
 	for {
 		select {
 		case <-cs.ctx.Done():
@@ -228,21 +309,74 @@ func (cs *SignalWireCallSession) writePump() {
 				return
 			}
 
-			// Send audio to SignalWire
-			if err := cs.streamAudioToSignalWire(audioChunk); err != nil {
-				log.Printf("[SignalWireSession] Audio send error: %v", err)
-				return
-			}
+			// Queue audio for framing; the chunk is ours to release
+			framer.write(time.Now(), audioChunk)
+			ReleaseAudioBuffer(audioChunk)
+
+		case <-pace.C:
 
 		case <-ticker.C:
 			// Send keepalive ping
-			if err := cs.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			cs.mu.Lock()
+			err := cs.Conn.WriteMessage(websocket.PingMessage, nil)
+			cs.mu.Unlock()
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		// Send the frames that are due, then wait for the next
+		format := cs.PipelineFormat()
+		for {
+			frame, wait := framer.next(time.Now(), format)
+			if frame == nil {
+				if wait > 0 {
+					pace.Reset(wait)
+				}
+				break
+			}
+			if err := cs.streamAudioToSignalWire(frame); err != nil {
+				log.Printf("[SignalWireSession] Audio send error: %v", err)
 				return
 			}
 		}
 	}
 }
 
+// ClearAudio drops audio on its way to the caller: chunks waiting to be
+// framed, the framer's backlog, and what SignalWire has buffered
+func (cs *SignalWireCallSession) ClearAudio() error {
+	cs.mu.RLock()
+	closed := cs.Closed
+	cs.mu.RUnlock()
+	if closed {
+		return fmt.Errorf("session closed")
+	}
+
+drain:
+	for {
+		select {
+		case audioChunk, ok := <-cs.AudioOutChan:
+			if !ok {
+				break drain
+			}
+			ReleaseAudioBuffer(audioChunk)
+		default:
+			break drain
+		}
+	}
+	cs.framer.clear()
+
+	cs.mu.Lock()
+	err := cs.Conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"clear"}`))
+	cs.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to send clear message: %w", err)
+	}
+	return nil
+}
+
 // handleSignalWireMessage processes incoming SignalWire messages
 func (cs *SignalWireCallSession) handleSignalWireMessage(data []byte) error {
 	var msg map[string]interface{}
@@ -301,8 +435,14 @@ func (cs *SignalWireCallSession) handleConnectedEvent(msg map[string]interface{}
 func (cs *SignalWireCallSession) handleStartEvent(msg map[string]interface{}) {
 	log.Printf("[SignalWireSession] Media stream started: %s", cs.SignalWireCallSID)
 
+	if err := cs.negotiateMedia(msg); err != nil {
+		log.Printf("[SignalWireSession] Failed to set up media codec for %s: %v", cs.SignalWireCallSID, err)
+	}
+	cs.jitterBuffer()
+
 	cs.SendEvent("stream_started", map[string]interface{}{
 		"call_sid":  cs.SignalWireCallSID,
+		"encoding":  cs.PipelineFormat().Encoding,
 		"timestamp": time.Now().Unix(),
 	})
 }
@@ -315,14 +455,13 @@ func (cs *SignalWireCallSession) handleMediaEvent(msg map[string]interface{}) er
 		return fmt.Errorf("media event missing payload")
 	}
 
-	// Track sequence number
+	// Inbound audio is the caller's; outbound is what the caller hears
 	track, ok := media["track"].(string)
 	if !ok {
 		return fmt.Errorf("media event missing track")
 	}
-
-	// Only process inbound audio (from phone)
-	if track != "inbound" {
+	outbound := track == "outbound"
+	if track != "inbound" && !(outbound && cs.carriesOutboundTrack()) {
 		return nil
 	}
 
@@ -332,27 +471,134 @@ func (cs *SignalWireCallSession) handleMediaEvent(msg map[string]interface{}) er
 		return fmt.Errorf("media event missing payload")
 	}
 
-	// Decode base64 audio
-	audioData, err := base64.StdEncoding.DecodeString(payload)
+	// Decode base64 audio into a pooled buffer
+	encoded := GetAudioBuffer(len(payload))
+	copy(encoded, payload)
+	audioData := GetAudioBuffer(base64.StdEncoding.DecodedLen(len(payload)))
+	n, err := base64.StdEncoding.Decode(audioData, encoded)
+	ReleaseAudioBuffer(encoded)
 	if err != nil {
+		ReleaseAudioBuffer(audioData)
 		return fmt.Errorf("failed to decode audio payload: %w", err)
 	}
+	audioData = audioData[:n]
 
-	// Send to audio input channel (non-blocking)
-	select {
-	case cs.AudioInChan <- audioData:
-	case <-time.After(10 * time.Millisecond):
-		// Channel full, drop chunk
-		log.Printf("[SignalWireSession] Audio input channel full, dropping chunk")
+	decoded, err := cs.decodeMedia(audioData)
+	if !sameBuffer(decoded, audioData) {
+		ReleaseAudioBuffer(audioData)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode audio: %w", err)
+	}
+	audioData = decoded
+
+	// Reorder and re-frame through the track's jitter buffer, by timestamp
+	// (ms from stream start) or else chunk number
+	jitter := cs.jitterBuffer()
+	if outbound {
+		jitter = cs.outboundJitterBuffer()
+	}
+	if timestamp, ok := mediaNumber(media["timestamp"]); ok {
+		jitter.Push(time.Duration(timestamp)*time.Millisecond, audioData)
+	} else if chunk, ok := mediaNumber(media["chunk"]); ok {
+		jitter.PushChunk(chunk, audioData)
+	} else {
+		jitter.Push(-1, audioData)
+	}
+	if outbound {
+		cs.deliver(jitter, cs.OutboundTrackChan)
+	} else {
+		cs.deliverInbound(jitter)
 	}
 
 	return nil
 }
 
+// jitterBuffer returns the stream's jitter buffer, creating it for the
+// pipeline format on first use
+func (cs *SignalWireCallSession) jitterBuffer() *JitterBuffer {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.jitter == nil {
+		cs.jitter = NewJitterBuffer(cs.PipelineFormat(), cs.jitterConfig)
+	}
+	return cs.jitter
+}
+
+// carriesOutboundTrack reports whether the stream was requested with the
+// outbound track, which is then delivered on OutboundTrackChan
+func (cs *SignalWireCallSession) carriesOutboundTrack() bool {
+	return cs.Track == "both" || cs.Track == "outbound"
+}
+
+// outboundJitterBuffer returns the outbound track's jitter buffer, creating
+// it on first use
+func (cs *SignalWireCallSession) outboundJitterBuffer() *JitterBuffer {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.outboundJitter == nil {
+		cs.outboundJitter = NewJitterBuffer(cs.PipelineFormat(), cs.jitterConfig)
+	}
+	return cs.outboundJitter
+}
+
+// deliverInbound moves frames the jitter buffer has ready into AudioInChan
+// without blocking; frames that don't fit wait in the buffer
+func (cs *SignalWireCallSession) deliverInbound(jitter *JitterBuffer) {
+	cs.deliver(jitter, cs.AudioInChan)
+}
+
+// deliver moves frames a jitter buffer has ready into out without blocking
+func (cs *SignalWireCallSession) deliver(jitter *JitterBuffer, out chan []byte) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	if cs.Closed || out == nil {
+		return
+	}
+
+	jitter.Drain(func(frame []byte) bool {
+		select {
+		case out <- frame:
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+// JitterStats returns the inbound jitter buffer's counters
+func (cs *SignalWireCallSession) JitterStats() JitterBufferStats {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	if cs.jitter == nil {
+		return JitterBufferStats{}
+	}
+	return cs.jitter.Stats()
+}
+
+// PlayoutStats returns the stream's outbound pacing and drift counters
+func (cs *SignalWireCallSession) PlayoutStats() PlayoutStats {
+	return cs.framer.Stats()
+}
+
 // handleStopEvent handles stream stop event
 func (cs *SignalWireCallSession) handleStopEvent(msg map[string]interface{}) {
 	log.Printf("[SignalWireSession] Media stream stopped: %s", cs.SignalWireCallSID)
 
+	// Deliver the audio held for jitter
+	jitter := cs.jitterBuffer()
+	jitter.Flush()
+	cs.deliverInbound(jitter)
+	log.Printf("[SignalWireSession] Jitter buffer stats for %s: %+v", cs.SignalWireCallSID, jitter.Stats())
+
+	cs.mu.RLock()
+	outboundJitter := cs.outboundJitter
+	cs.mu.RUnlock()
+	if outboundJitter != nil {
+		outboundJitter.Flush()
+		cs.deliver(outboundJitter, cs.OutboundTrackChan)
+	}
+
 	cs.SendEvent("stream_stopped", map[string]interface{}{
 		"call_sid":  cs.SignalWireCallSID,
 		"timestamp": time.Now().Unix(),
@@ -363,6 +609,13 @@ func (cs *SignalWireCallSession) handleStopEvent(msg map[string]interface{}) {
 // AUDIO STREAMING
 // ============================================
 
+// Outbound media message around the base64 payload, as json.Marshal would
+// write it
+const (
+	mediaMessagePrefix = `{"event":"media","media":{"payload":"`
+	mediaMessageSuffix = `","track":"outbound"}}`
+)
+
 // streamAudioToSignalWire sends audio data to SignalWire WebSocket
 func (cs *SignalWireCallSession) streamAudioToSignalWire(audioData []byte) error {
 	cs.mu.RLock()
@@ -372,31 +625,28 @@ func (cs *SignalWireCallSession) streamAudioToSignalWire(audioData []byte) error
 	}
 	cs.mu.RUnlock()
 
-	// Encode audio as base64
-	encoded := base64.StdEncoding.EncodeToString(audioData)
-
-	// Construct SignalWire media message
-	msg := map[string]interface{}{
-		"event": "media",
-		"media": map[string]interface{}{
-			"track":   "outbound",
-			"payload": encoded,
-		},
-	}
-
-	// Serialize message
-	data, err := json.Marshal(msg)
+	// Encode for the negotiated codec (several packets for Opus)
+	payloads, err := cs.encodeMedia(audioData)
 	if err != nil {
-		return fmt.Errorf("failed to marshal media message: %w", err)
+		return fmt.Errorf("failed to encode audio: %w", err)
 	}
 
-	// Send to WebSocket
-	cs.mu.Lock()
-	err = cs.Conn.WriteMessage(websocket.TextMessage, data)
-	cs.mu.Unlock()
+	for _, payload := range payloads {
+		// Construct SignalWire media message in a pooled buffer
+		data := GetAudioBuffer(len(mediaMessagePrefix) + base64.StdEncoding.EncodedLen(len(payload)) + len(mediaMessageSuffix))[:0]
+		data = append(data, mediaMessagePrefix...)
+		data = base64.StdEncoding.AppendEncode(data, payload)
+		data = append(data, mediaMessageSuffix...)
 
-	if err != nil {
-		return fmt.Errorf("failed to send media message: %w", err)
+		// Send to WebSocket
+		cs.mu.Lock()
+		err := cs.Conn.WriteMessage(websocket.TextMessage, data)
+		cs.mu.Unlock()
+		ReleaseAudioBuffer(data)
+
+		if err != nil {
+			return fmt.Errorf("failed to send media message: %w", err)
+		}
 	}
 
 	return nil
@@ -430,6 +680,29 @@ func (cs *SignalWireCallSession) SendEvent(eventType string, data map[string]int
 	return err
 }
 
+// SendAudio queues pooled audio for the phone without blocking, releasing
+// it if the queue is full or the session has closed. Goroutines that may
+// outlive the session, such as mixers, send through it rather than on
+// AudioOutChan, which Close closes.
+func (cs *SignalWireCallSession) SendAudio(frame []byte) bool {
+	cs.outMu.RLock()
+	defer cs.outMu.RUnlock()
+
+	select {
+	case <-cs.done:
+		ReleaseAudioBuffer(frame)
+		return false
+	default:
+	}
+	select {
+	case cs.AudioOutChan <- frame:
+		return true
+	default:
+		ReleaseAudioBuffer(frame)
+		return false
+	}
+}
+
 // Close closes the SignalWire session
 func (cs *SignalWireCallSession) Close() error {
 	cs.mu.Lock()
@@ -442,9 +715,15 @@ func (cs *SignalWireCallSession) Close() error {
 	cs.Closed = true
 	cs.ClosedCount++
 
-	// Close channels
+	// Close channels; SendAudio holds outMu, so none is mid-send
+	cs.outMu.Lock()
+	close(cs.done)
 	close(cs.AudioInChan)
 	close(cs.AudioOutChan)
+	cs.outMu.Unlock()
+	if cs.OutboundTrackChan != nil {
+		close(cs.OutboundTrackChan)
+	}
 
 	// Close WebSocket connection
 	if cs.Conn != nil {
@@ -455,6 +734,8 @@ func (cs *SignalWireCallSession) Close() error {
 		cs.Conn.Close()
 	}
 
+	cs.closeCodecs()
+
 	log.Printf("[SignalWireSession] Closed: %s", cs.ID)
 	return nil
 }
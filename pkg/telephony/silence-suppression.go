@@ -0,0 +1,191 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ============================================
+// SILENCE SUPPRESSION & COMFORT NOISE
+// Skip caller silence on the way to STT; fill AI pauses toward the caller
+// ============================================
+
+// SilenceConfig controls silence handling on AI-routed streams
+type SilenceConfig struct {
+	// Stop forwarding caller audio to the AI pipeline while the VAD reports
+	// silence, saving STT cost. Requires SetVAD.
+	SuppressSilence bool `json:"suppress_silence"`
+	// Audio from before detected speech that is still forwarded, so the
+	// start of an utterance isn't clipped (default 300ms)
+	PreRoll time.Duration `json:"pre_roll"`
+
+	// Play low-level noise to the caller while no AI audio is playing, so
+	// the line doesn't sound dead while the AI is thinking
+	ComfortNoise bool `json:"comfort_noise"`
+	// Comfort noise level in dBFS (default -60)
+	ComfortNoiseLevel float64 `json:"comfort_noise_level"`
+	// How long AI audio must have been finished before noise starts
+	// (default 200ms)
+	ComfortNoiseDelay time.Duration `json:"comfort_noise_delay"`
+}
+
+// withDefaults fills in zero fields
+func (c SilenceConfig) withDefaults() SilenceConfig {
+	if c.PreRoll <= 0 {
+		c.PreRoll = 300 * time.Millisecond
+	}
+	if c.ComfortNoiseLevel == 0 {
+		c.ComfortNoiseLevel = -60
+	}
+	if c.ComfortNoiseDelay <= 0 {
+		c.ComfortNoiseDelay = 200 * time.Millisecond
+	}
+	return c
+}
+
+// comfortNoiseFrame is the length of each injected noise frame
+const comfortNoiseFrame = 20 * time.Millisecond
+
+// audioDuration is the playing time of n bytes of audio in format
+func audioDuration(format AudioFormat, n int) time.Duration {
+	sampleBytes := format.Channels * format.BitDepth / 8
+	if sampleBytes <= 0 {
+		sampleBytes = 1
+	}
+	if format.SampleRate <= 0 {
+		return 0
+	}
+	return time.Duration(n/sampleBytes) * time.Second / time.Duration(format.SampleRate)
+}
+
+// ============================================
+// SILENCE GATE
+// ============================================
+
+// silenceGate holds back caller audio while nobody is speaking, keeping the
+// most recent PreRoll of it to release when speech starts
+type silenceGate struct {
+	format  AudioFormat
+	preRoll time.Duration
+	held    [][]byte
+	heldFor time.Duration
+}
+
+func newSilenceGate(format AudioFormat, preRoll time.Duration) *silenceGate {
+	return &silenceGate{format: format, preRoll: preRoll}
+}
+
+// filter returns the audio to forward for a chunk: nothing during silence,
+// or the held pre-roll followed by the chunk once speech is in progress
+func (g *silenceGate) filter(audio []byte, speaking bool) [][]byte {
+	if speaking {
+		out := append(g.held, audio)
+		g.held, g.heldFor = nil, 0
+		return out
+	}
+
+	g.held = append(g.held, audio)
+	g.heldFor += audioDuration(g.format, len(audio))
+	for len(g.held) > 1 {
+		oldest := audioDuration(g.format, len(g.held[0]))
+		if g.heldFor-oldest < g.preRoll {
+			break
+		}
+		ReleaseAudioBuffer(g.held[0])
+		g.held[0] = nil
+		g.held = g.held[1:]
+		g.heldFor -= oldest
+	}
+	return nil
+}
+
+// ============================================
+// COMFORT NOISE
+// ============================================
+
+// comfortNoise generates soft low-passed noise frames in mulaw or 16-bit PCM
+type comfortNoise struct {
+	format    AudioFormat
+	amplitude float64
+	smoothed  float64
+	rng       *rand.Rand
+}
+
+func newComfortNoise(format AudioFormat, levelDBFS float64) *comfortNoise {
+	return &comfortNoise{
+		format:    format,
+		amplitude: 32768 * math.Pow(10, levelDBFS/20),
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// frame returns d of noise
+func (n *comfortNoise) frame(d time.Duration) []byte {
+	samples := int(int64(n.format.SampleRate) * int64(d) / int64(time.Second))
+	mulaw := n.format.Encoding == AudioFormatMulaw.Encoding
+
+	size := samples * 2
+	if mulaw {
+		size = samples
+	}
+	out := GetAudioBuffer(size)
+
+	for i := 0; i < samples; i++ {
+		// One-pole low-pass takes the hiss off white noise; the gain
+		// restores the level the filter removes
+		n.smoothed += 0.3 * (n.rng.NormFloat64() - n.smoothed)
+		sample := clampInt16(n.smoothed * n.amplitude * 2.4)
+
+		if mulaw {
+			out[i] = linearToMulaw(sample)
+		} else {
+			binary.LittleEndian.PutUint16(out[i*2:], uint16(sample))
+		}
+	}
+	return out
+}
+
+// silenceFrame returns d of digital silence in mulaw or 16-bit PCM, from
+// the audio buffer pool
+func silenceFrame(format AudioFormat, d time.Duration) []byte {
+	frame := GetAudioBuffer(frameBytes(format, d))
+	fill := byte(0)
+	if format.Encoding == AudioFormatMulaw.Encoding {
+		fill = 0xFF
+	}
+	for i := range frame {
+		frame[i] = fill
+	}
+	return frame
+}
+
+// ============================================
+// PLAYBACK TRACKING
+// ============================================
+
+// playoutClock estimates when audio sent to SignalWire will have finished
+// playing, so comfort noise only fills real gaps and never queues more than
+// a frame ahead of AI audio
+type playoutClock struct {
+	queued time.Time // When everything sent so far finishes playing
+	aiDone time.Time // When the last AI audio finishes playing
+}
+
+// sent records n bytes of audio in format queued for playback at now
+func (p *playoutClock) sent(now time.Time, format AudioFormat, n int, ai bool) {
+	if p.queued.Before(now) {
+		p.queued = now
+	}
+	p.queued = p.queued.Add(audioDuration(format, n))
+	if ai {
+		p.aiDone = p.queued
+	}
+}
+
+// wantsNoise reports whether AI audio has been finished for delay and the
+// noise already queued is about to run out
+func (p *playoutClock) wantsNoise(now time.Time, delay time.Duration) bool {
+	return now.Sub(p.aiDone) >= delay && p.queued.Sub(now) < comfortNoiseFrame
+}
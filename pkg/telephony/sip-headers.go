@@ -0,0 +1,106 @@
+package telephony
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ============================================
+// SIP HEADERS
+// Custom X- headers on outbound and inbound calls
+// ============================================
+
+// sipHeaderParamPrefix prefixes each custom SIP header in webhook payloads
+const sipHeaderParamPrefix = "SipHeader_"
+
+// Headers set on every outbound call; CallConfig.SIPHeaders can't override them
+const (
+	SIPHeaderSessionID  = "X-Session-ID"
+	SIPHeaderCampaignID = "X-Campaign-ID"
+)
+
+// validateSIPHeaders checks custom header names are X- headers made of
+// token characters and don't collide with the headers we set
+func validateSIPHeaders(headers map[string]string) error {
+	for name := range headers {
+		if len(name) < 3 || !strings.EqualFold(name[:2], "X-") {
+			return fmt.Errorf("sip header %q must start with X-", name)
+		}
+		for _, c := range name {
+			if !isSIPTokenChar(c) {
+				return fmt.Errorf("sip header %q contains invalid character %q", name, c)
+			}
+		}
+		if strings.EqualFold(name, SIPHeaderSessionID) || strings.EqualFold(name, SIPHeaderCampaignID) {
+			return fmt.Errorf("sip header %q is reserved", name)
+		}
+	}
+	return nil
+}
+
+// isSIPTokenChar reports whether c may appear in a SIP header name
+func isSIPTokenChar(c rune) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.ContainsRune("-.!%*_+`'~", c)
+}
+
+// outboundSIPHeaders merges the call's custom headers with our session and
+// campaign headers
+func outboundSIPHeaders(config CallConfig, sessionID uuid.UUID) map[string]string {
+	headers := make(map[string]string, len(config.SIPHeaders)+2)
+	for name, value := range config.SIPHeaders {
+		headers[name] = value
+	}
+	headers[SIPHeaderSessionID] = sessionID.String()
+	if config.CampaignID != uuid.Nil {
+		headers[SIPHeaderCampaignID] = config.CampaignID.String()
+	}
+	return headers
+}
+
+// EncodeSIPHeaders encodes headers as the SipHeaders request parameter:
+// name=value pairs joined by '&' with values URL-escaped, sorted by name
+func EncodeSIPHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		// SIP header values don't treat '+' as a space
+		value := strings.ReplaceAll(url.QueryEscape(headers[name]), "+", "%20")
+		pairs[i] = name + "=" + value
+	}
+	return strings.Join(pairs, "&")
+}
+
+// SIPHeadersFromRequest extracts the custom SIP headers SignalWire passes on
+// a call webhook as SipHeader_<name> parameters. Returns nil if there are none.
+func SIPHeadersFromRequest(r *http.Request) map[string]string {
+	if err := r.ParseForm(); err != nil {
+		return nil
+	}
+
+	var headers map[string]string
+	for key, values := range r.Form {
+		name := strings.TrimPrefix(key, sipHeaderParamPrefix)
+		if name == key || name == "" || len(values) == 0 {
+			continue
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[name] = values[0]
+	}
+	return headers
+}
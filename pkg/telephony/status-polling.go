@@ -0,0 +1,100 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// ============================================
+// STATUS POLLING
+// Tracking call state without status callbacks
+// ============================================
+
+// StatusPollingConfig controls how calls are polled for their status
+type StatusPollingConfig struct {
+	MinInterval time.Duration // Interval after a change, and while setting up (default 2s)
+	MaxInterval time.Duration // Interval for calls that haven't changed in a while (default 30s)
+	Always      bool          // Poll calls that have a StatusCallbackURL too
+}
+
+func (c StatusPollingConfig) withDefaults() StatusPollingConfig {
+	if c.MinInterval <= 0 {
+		c.MinInterval = 2 * time.Second
+	}
+	if c.MaxInterval < c.MinInterval {
+		c.MaxInterval = 30 * time.Second
+		if c.MaxInterval < c.MinInterval {
+			c.MaxInterval = c.MinInterval
+		}
+	}
+	return c
+}
+
+// SetStatusPolling polls SignalWire for the status of calls placed without
+// a StatusCallbackURL (every call with Always), applying each change as if a
+// status callback had reported it. Useful in development and in firewalled
+// environments SignalWire can't reach. Calls are polled every MinInterval
+// while their state changes, backing off to MaxInterval while it doesn't.
+func (ci *CallInitiator) SetStatusPolling(config StatusPollingConfig) {
+	config = config.withDefaults()
+	ci.statusPolling = &config
+}
+
+// startPolling polls a call if status polling covers it and it isn't
+// polled already
+func (ci *CallInitiator) startPolling(callSID string, config *CallConfig) {
+	polling := ci.statusPolling
+	if polling == nil || callSID == "" {
+		return
+	}
+	if !polling.Always && config != nil && config.StatusCallbackURL != "" {
+		return
+	}
+	if _, polled := ci.pollers.LoadOrStore(callSID, struct{}{}); polled {
+		return
+	}
+	go ci.pollCall(callSID, *polling)
+}
+
+// pollCall resynchronizes a call with SignalWire until it ends
+func (ci *CallInitiator) pollCall(callSID string, config StatusPollingConfig) {
+	defer ci.pollers.Delete(callSID)
+
+	interval := config.MinInterval
+	for {
+		time.Sleep(interval)
+
+		// A webhook or another poll may already have ended the call
+		session, err := ci.lookupSession(context.Background(), callSID)
+		if err != nil {
+			return
+		}
+		session.mu.RLock()
+		done := session.State.IsTerminal()
+		session.mu.RUnlock()
+		if done {
+			return
+		}
+
+		state, changed, err := ci.resyncCall(context.Background(), callSID)
+		switch {
+		case errors.Is(err, ErrUnknownCall):
+			log.Printf("[CallInitiator] Stopped polling %s: %v", callSID, err)
+			return
+		case err != nil:
+			log.Printf("[CallInitiator] Failed to poll call %s: %v", callSID, err)
+			ci.errorLog.Record("polling", callSID, err)
+		case state.IsTerminal():
+			return
+		case changed:
+			interval = config.MinInterval
+			continue
+		}
+
+		if interval *= 2; interval > config.MaxInterval {
+			interval = config.MaxInterval
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package telephony
+
+import (
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ============================================
+// STIR/SHAKEN
+// Caller ID attestation reported for inbound and outbound calls
+// ============================================
+
+// Attestation is the STIR/SHAKEN attestation level of a call's caller ID
+type Attestation string
+
+const (
+	AttestationFull    Attestation = "A"      // Carrier knows the caller and their right to the number
+	AttestationPartial Attestation = "B"      // Carrier knows the caller, not their right to the number
+	AttestationGateway Attestation = "C"      // Carrier only knows where the call entered its network
+	AttestationFailed  Attestation = "failed" // Identity header present but didn't verify
+	AttestationNone    Attestation = "none"   // No identity header, or not validated
+)
+
+// attestationRanks orders attestation levels; unreported attestation ("")
+// has no rank
+var attestationRanks = map[Attestation]int{
+	AttestationFull:    4,
+	AttestationPartial: 3,
+	AttestationGateway: 2,
+	AttestationFailed:  1,
+	AttestationNone:    1,
+}
+
+// EventAttestationDowngraded is published when a call is attested below
+// the initiator's minimum (see SetMinAttestation)
+const EventAttestationDowngraded EventType = "call.attestation_downgraded"
+
+// ParseVerstat converts a verification status (StirVerstat, e.g.
+// "TN-Validation-Passed-A") or a bare attestation (StirStatus, e.g. "B") to
+// an attestation level. Unrecognized values return "".
+func ParseVerstat(verstat string) Attestation {
+	v := strings.ToUpper(strings.TrimSpace(verstat))
+	switch {
+	case v == "":
+		return ""
+	case v == "A" || v == "B" || v == "C":
+		return Attestation(v)
+	case strings.HasPrefix(v, "TN-VALIDATION-PASSED-"):
+		level := Attestation(strings.TrimPrefix(v, "TN-VALIDATION-PASSED-"))
+		if _, ok := attestationRanks[level]; ok {
+			return level
+		}
+	case strings.HasPrefix(v, "TN-VALIDATION-FAILED"):
+		return AttestationFailed
+	case v == "NO-TN-VALIDATION":
+		return AttestationNone
+	}
+	return ""
+}
+
+// AttestationFromRequest reads the attestation a SignalWire webhook reports:
+// StirVerstat on inbound calls, StirStatus on outbound status callbacks.
+// It returns the parsed level and the raw value.
+func AttestationFromRequest(r *http.Request) (Attestation, string) {
+	for _, param := range []string{"StirVerstat", "StirStatus"} {
+		if raw := r.FormValue(param); raw != "" {
+			return ParseVerstat(raw), raw
+		}
+	}
+	return "", ""
+}
+
+// Below reports whether the attestation is lower than min. Unreported
+// attestation is never below.
+func (a Attestation) Below(min Attestation) bool {
+	rank, ok := attestationRanks[a]
+	if !ok {
+		return false
+	}
+	return rank < attestationRanks[min]
+}
+
+// attestationsBelow lists the levels lower than min
+func attestationsBelow(min Attestation) []Attestation {
+	var levels []Attestation
+	for level := range attestationRanks {
+		if level.Below(min) {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// SetMinAttestation sets the lowest attestation outbound calls are expected
+// to get (default A). Calls reported below it publish
+// EventAttestationDowngraded, e.g. to alert when a carrier stops fully
+// attesting our numbers. Inbound attestation is recorded but not alerted on;
+// query it with SessionFilter.AttestationBelow.
+func (ci *CallInitiator) SetMinAttestation(min Attestation) {
+	ci.minAttestation = min
+}
+
+// setAttestation records a reported attestation. Caller must hold the
+// session lock.
+func (s *CallSession) setAttestation(attestation Attestation, verstat string) {
+	if verstat == "" {
+		return
+	}
+	s.Attestation = attestation
+	s.Verstat = verstat
+}
+
+// checkAttestation publishes a downgrade event if an outbound call's newly
+// reported attestation is below the minimum
+func (ci *CallInitiator) checkAttestation(session *CallSession) {
+	min := ci.minAttestation
+	if min == "" {
+		min = AttestationFull
+	}
+
+	session.mu.RLock()
+	attestation, verstat := session.Attestation, session.Verstat
+	outbound := session.Direction == DirectionOutbound
+	session.mu.RUnlock()
+	if !outbound || !attestation.Below(min) {
+		return
+	}
+
+	summary := session.Summary()
+	log.Printf("[CallInitiator] Call %s from %s attested %s (%s), below %s",
+		summary.SignalWireCallSID, summary.FromNumber, attestation, verstat, min)
+
+	event := callEvent(EventAttestationDowngraded, summary)
+	event.Data = map[string]interface{}{
+		"attestation": string(attestation),
+		"verstat":     verstat,
+		"minimum":     string(min),
+	}
+	publishEvent(ci.events, event)
+}
+
+// attestationStrings converts levels for a Postgres text array parameter
+func attestationStrings(levels []Attestation) []string {
+	values := make([]string, len(levels))
+	for i, level := range levels {
+		values[i] = string(level)
+	}
+	return values
+}
@@ -0,0 +1,306 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ============================================
+// SUPERVISOR MONITORING
+// Listen, whisper and barge legs on live calls
+// ============================================
+
+// SupervisorMode controls what a supervisor leg hears and who hears it
+type SupervisorMode string
+
+const (
+	// SupervisorMonitor lets the supervisor listen only
+	SupervisorMonitor SupervisorMode = "monitor"
+	// SupervisorWhisper lets the supervisor speak to the agent side only
+	SupervisorWhisper SupervisorMode = "whisper"
+	// SupervisorBarge joins the supervisor to the call in full duplex
+	SupervisorBarge SupervisorMode = "barge"
+)
+
+// SupervisorStreamName is the bridge stream name used by supervisor legs
+const SupervisorStreamName = "supervisor"
+
+// SupervisorLeg is a supervisor attached to a live call
+type SupervisorLeg struct {
+	CallSID           string         `json:"call_sid"`            // Supervised (agent-side) call
+	SupervisorCallSID string         `json:"supervisor_call_sid"` // Supervisor's own call leg
+	Mode              SupervisorMode `json:"mode"`
+	Conference        string         `json:"conference,omitempty"` // Set when attached through a conference
+	SessionID         string         `json:"session_id,omitempty"` // Set when attached through the audio bridge
+	AttachedAt        time.Time      `json:"attached_at"`
+}
+
+// SupervisorManager attaches supervisor legs to live calls. Calls in a
+// conference (AI, customer and human agent) get a conference participant
+// using mute/coach; AI-only calls get a media stream mixed by the bridge.
+type SupervisorManager struct {
+	initiator   *CallInitiator
+	bridge      *AudioStreamBridge
+	conferences *ConferenceManager
+	streamURL   string // Public wss:// URL of the call stream endpoint
+
+	legs map[string]*SupervisorLeg // supervised call SID -> leg
+	mu   sync.RWMutex
+}
+
+// NewSupervisorManager creates a new supervisor manager. streamURL is the
+// public WebSocket URL of the call stream endpoint
+// (e.g. "wss://example.com/api/telephony/calls/stream").
+func NewSupervisorManager(initiator *CallInitiator, bridge *AudioStreamBridge, conferences *ConferenceManager, streamURL string) *SupervisorManager {
+	return &SupervisorManager{
+		initiator:   initiator,
+		bridge:      bridge,
+		conferences: conferences,
+		streamURL:   streamURL,
+		legs:        make(map[string]*SupervisorLeg),
+	}
+}
+
+// Attach dials a supervisor and connects them to a live call
+func (s *SupervisorManager) Attach(ctx context.Context, callSID, supervisor, callerID string, mode SupervisorMode) (*SupervisorLeg, error) {
+	if err := validateSupervisorMode(mode); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	_, attached := s.legs[callSID]
+	s.mu.RUnlock()
+	if attached {
+		return nil, fmt.Errorf("call already has a supervisor: %s", callSID)
+	}
+
+	leg := &SupervisorLeg{
+		CallSID:    callSID,
+		Mode:       mode,
+		AttachedAt: time.Now(),
+	}
+
+	if room := s.conferenceFor(callSID); room != nil {
+		// Conference call: the supervisor is another participant
+		opts := ParticipantOptions{
+			Label:        "supervisor",
+			CallerID:     callerID,
+			StartOnEnter: boolPtr(false),
+			Muted:        mode == SupervisorMonitor,
+		}
+		if mode == SupervisorWhisper {
+			opts.Coach = callSID
+		}
+
+		supervisorCallSID, err := s.conferences.Dial(ctx, room.Name, supervisor, opts)
+		if err != nil {
+			return nil, err
+		}
+		leg.SupervisorCallSID = supervisorCallSID
+		leg.Conference = room.Name
+	} else {
+		// AI call: the supervisor streams into the call's bridge session
+		session := s.bridge.GetSessionByCallSID(callSID)
+		if session == nil {
+			return nil, fmt.Errorf("call is not in a conference or bridge session: %s", callSID)
+		}
+		if err := s.bridge.SetSupervisorMode(session.SessionID, mode); err != nil {
+			return nil, err
+		}
+
+		query := url.Values{}
+		query.Set("session_id", session.SessionID)
+		query.Set("stream", SupervisorStreamName)
+		query.Set("route", string(StreamRouteSupervisor))
+
+		twiml := NewTwiML().Append(ConnectVerb{
+			Stream: &StreamNoun{
+				Name: SupervisorStreamName,
+				URL:  fmt.Sprintf("%s/%s?%s", s.streamURL, session.SessionID, query.Encode()),
+			},
+		})
+
+		call, err := s.initiator.createCallWithTwiML(ctx, callerID, supervisor, twiml, 30)
+		if err != nil {
+			s.initiator.errorLog.Record("supervisor", callSID, err)
+			return nil, fmt.Errorf("failed to dial supervisor: %w", err)
+		}
+		leg.SupervisorCallSID = call.SID
+		leg.SessionID = session.SessionID
+	}
+
+	s.mu.Lock()
+	s.legs[callSID] = leg
+	s.mu.Unlock()
+
+	log.Printf("[SupervisorManager] Supervisor %s attached to call %s (%s)", leg.SupervisorCallSID, callSID, mode)
+	return leg, nil
+}
+
+// SetMode switches an attached supervisor between monitor, whisper and barge
+func (s *SupervisorManager) SetMode(ctx context.Context, callSID string, mode SupervisorMode) error {
+	if err := validateSupervisorMode(mode); err != nil {
+		return err
+	}
+
+	leg := s.Get(callSID)
+	if leg == nil {
+		return fmt.Errorf("no supervisor on call: %s", callSID)
+	}
+
+	if leg.Conference != "" {
+		formData := url.Values{}
+		formData.Set("Muted", fmt.Sprintf("%t", mode == SupervisorMonitor))
+		formData.Set("Coaching", fmt.Sprintf("%t", mode == SupervisorWhisper))
+		if mode == SupervisorWhisper {
+			formData.Set("CallSidToCoach", callSID)
+		}
+		if err := s.conferences.updateParticipant(ctx, leg.Conference, leg.SupervisorCallSID, formData); err != nil {
+			return err
+		}
+	} else if err := s.bridge.SetSupervisorMode(leg.SessionID, mode); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	leg.Mode = mode
+	s.mu.Unlock()
+
+	log.Printf("[SupervisorManager] Supervisor on call %s switched to %s", callSID, mode)
+	return nil
+}
+
+// Detach hangs up the supervisor leg on a call
+func (s *SupervisorManager) Detach(ctx context.Context, callSID string) error {
+	s.mu.Lock()
+	leg, ok := s.legs[callSID]
+	delete(s.legs, callSID)
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no supervisor on call: %s", callSID)
+	}
+
+	return s.initiator.HangupCall(ctx, leg.SupervisorCallSID)
+}
+
+// Get returns the supervisor leg on a call (nil if none)
+func (s *SupervisorManager) Get(callSID string) *SupervisorLeg {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	leg, ok := s.legs[callSID]
+	if !ok {
+		return nil
+	}
+	snapshot := *leg
+	return &snapshot
+}
+
+// conferenceFor returns the active conference a call participates in
+func (s *SupervisorManager) conferenceFor(callSID string) *ConferenceRoom {
+	if s.conferences == nil {
+		return nil
+	}
+	for _, room := range s.conferences.List() {
+		if _, ok := room.Participant(callSID); ok && room.Status != ConferenceCompleted {
+			return room
+		}
+	}
+	return nil
+}
+
+// validateSupervisorMode checks a supervisor mode
+func validateSupervisorMode(mode SupervisorMode) error {
+	switch mode {
+	case SupervisorMonitor, SupervisorWhisper, SupervisorBarge:
+		return nil
+	}
+	return fmt.Errorf("unknown supervisor mode: %s", mode)
+}
+
+// ============================================
+// BRIDGE MIXING
+// ============================================
+
+// SetSupervisorMode sets how a session's supervisor stream is mixed. In
+// whisper and barge modes the supervisor's audio is available from
+// GetStreamChannel(sessionID, SupervisorStreamName) for the agent side.
+func (bridge *AudioStreamBridge) SetSupervisorMode(sessionID string, mode SupervisorMode) error {
+	if err := validateSupervisorMode(mode); err != nil {
+		return err
+	}
+
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	session.supervisorMode = mode
+	session.applySupervisorMode()
+	return nil
+}
+
+// applySupervisorMode starts or stops mixing supervisor audio to the caller.
+// Caller must hold the session lock.
+func (s *BridgeSession) applySupervisorMode() {
+	// Mixing only applies while a supervisor stream is connected
+	if s.monitorMixer == nil {
+		return
+	}
+
+	if s.supervisorMode != SupervisorBarge {
+		if s.phoneMixer != nil {
+			s.phoneMixer.Stop()
+			s.phoneMixer = nil
+		}
+		return
+	}
+
+	if s.phoneMixer != nil || s.SignalWireSession == nil {
+		return
+	}
+
+	phone, format := s.SignalWireSession, s.SignalWireSession.PipelineFormat()
+	s.phoneMixer = NewAudioMixer(func(frame []byte) {
+		// The caller hears the mix, so it's what echoes back; meter and
+		// reference it before the write pump takes the frame
+		s.Metrics.meterLevel(true, format, frame)
+		if aec := s.echoCanceller(format); aec != nil {
+			aec.Reference(frame)
+		}
+		if s.recorder != nil {
+			s.recorder.played(format, frame)
+		}
+		phone.SendAudio(frame)
+	})
+	s.phoneMixer.Start(s.ctx)
+}
+
+// stopSupervisorMixing tears down supervisor mixing when its stream ends.
+// Caller must hold the session lock.
+func (s *BridgeSession) stopSupervisorMixing() {
+	if s.monitorMixer != nil {
+		s.monitorMixer.Stop()
+		s.monitorMixer = nil
+	}
+	if s.phoneMixer != nil {
+		s.phoneMixer.Stop()
+		s.phoneMixer = nil
+	}
+	s.supervisorMode = ""
+}
+
+// supervisorRouting returns the session's supervisor mixers and mode
+func (s *BridgeSession) supervisorRouting() (*AudioMixer, *AudioMixer, SupervisorMode) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.monitorMixer, s.phoneMixer, s.supervisorMode
+}
@@ -0,0 +1,252 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============================================
+// SURVEY RESPONSES
+// Storage and CSAT reporting for survey answers
+// ============================================
+
+// SurveyFilter selects survey responses for reporting
+type SurveyFilter struct {
+	SurveyID string    // Empty for every survey
+	Since    time.Time // Answered at or after (zero = unbounded)
+	Until    time.Time // Answered before (zero = unbounded)
+}
+
+// SurveyStore persists survey responses
+type SurveyStore interface {
+	Save(ctx context.Context, response SurveyResponse) error
+	// ForSession returns a call's responses in the order they were given
+	ForSession(ctx context.Context, sessionID uuid.UUID) ([]SurveyResponse, error)
+	Query(ctx context.Context, filter SurveyFilter) ([]SurveyResponse, error)
+}
+
+// QuestionStats summarizes the answers to one survey question
+type QuestionStats struct {
+	SurveyID   string       `json:"survey_id"`
+	QuestionID string       `json:"question_id"`
+	Type       QuestionType `json:"type"`
+	Responses  int          `json:"responses"`
+	Average    float64      `json:"average"`  // Mean rating (ratings)
+	CSAT       float64      `json:"csat"`     // Share of 4 and 5 ratings (ratings)
+	YesRate    float64      `json:"yes_rate"` // Share of yes answers (yes/no)
+}
+
+// SetSurveyStore replaces the store for survey responses
+func (ci *CallInitiator) SetSurveyStore(store SurveyStore) {
+	ci.surveys = store
+}
+
+// SurveyResponses returns the survey answers given on a call
+func (ci *CallInitiator) SurveyResponses(ctx context.Context, sessionID uuid.UUID) ([]SurveyResponse, error) {
+	return ci.surveys.ForSession(ctx, sessionID)
+}
+
+// SurveyReport returns per-question statistics for matching responses
+func (ci *CallInitiator) SurveyReport(ctx context.Context, filter SurveyFilter) ([]QuestionStats, error) {
+	responses, err := ci.surveys.Query(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return SummarizeSurvey(responses), nil
+}
+
+// SummarizeSurvey computes per-question statistics, ordered by survey and
+// question
+func SummarizeSurvey(responses []SurveyResponse) []QuestionStats {
+	type key struct{ survey, question string }
+	totals := make(map[key]*QuestionStats)
+	sums := make(map[key]int)
+	positives := make(map[key]int)
+
+	for _, r := range responses {
+		k := key{r.SurveyID, r.QuestionID}
+		stats, ok := totals[k]
+		if !ok {
+			stats = &QuestionStats{SurveyID: r.SurveyID, QuestionID: r.QuestionID, Type: r.Type}
+			totals[k] = stats
+		}
+		stats.Responses++
+		sums[k] += r.Value
+		if (r.Type == QuestionRating && r.Value >= 4) || (r.Type == QuestionYesNo && r.Value == 1) {
+			positives[k]++
+		}
+	}
+
+	report := make([]QuestionStats, 0, len(totals))
+	for k, stats := range totals {
+		n := float64(stats.Responses)
+		if stats.Type == QuestionRating {
+			stats.Average = float64(sums[k]) / n
+			stats.CSAT = float64(positives[k]) / n
+		} else {
+			stats.YesRate = float64(positives[k]) / n
+		}
+		report = append(report, *stats)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].SurveyID != report[j].SurveyID {
+			return report[i].SurveyID < report[j].SurveyID
+		}
+		return report[i].QuestionID < report[j].QuestionID
+	})
+	return report
+}
+
+// matches reports whether a response passes the filter
+func (f SurveyFilter) matches(r SurveyResponse) bool {
+	if f.SurveyID != "" && r.SurveyID != f.SurveyID {
+		return false
+	}
+	if !f.Since.IsZero() && r.AnsweredAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !r.AnsweredAt.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// ============================================
+// IN-MEMORY SURVEY STORE
+// ============================================
+
+// MemorySurveyStore keeps survey responses in memory (lost on restart)
+type MemorySurveyStore struct {
+	responses []SurveyResponse
+	mu        sync.RWMutex
+}
+
+// NewMemorySurveyStore creates an in-memory survey store
+func NewMemorySurveyStore() *MemorySurveyStore {
+	return &MemorySurveyStore{}
+}
+
+// Save stores a response
+func (s *MemorySurveyStore) Save(ctx context.Context, response SurveyResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = append(s.responses, response)
+	return nil
+}
+
+// ForSession returns a call's responses
+func (s *MemorySurveyStore) ForSession(ctx context.Context, sessionID uuid.UUID) ([]SurveyResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var responses []SurveyResponse
+	for _, r := range s.responses {
+		if r.SessionID == sessionID {
+			responses = append(responses, r)
+		}
+	}
+	return responses, nil
+}
+
+// Query returns responses matching the filter
+func (s *MemorySurveyStore) Query(ctx context.Context, filter SurveyFilter) ([]SurveyResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var responses []SurveyResponse
+	for _, r := range s.responses {
+		if filter.matches(r) {
+			responses = append(responses, r)
+		}
+	}
+	return responses, nil
+}
+
+// ============================================
+// POSTGRES SURVEY STORE
+// ============================================
+
+// PostgresSurveyStore keeps responses in the survey_responses table (see
+// migrations), linked to call_sessions
+type PostgresSurveyStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresSurveyStore creates a survey store backed by Postgres
+func NewPostgresSurveyStore(db *pgxpool.Pool) *PostgresSurveyStore {
+	return &PostgresSurveyStore{db: db}
+}
+
+// Save inserts a response
+func (s *PostgresSurveyStore) Save(ctx context.Context, response SurveyResponse) error {
+	query := `
+		INSERT INTO survey_responses (
+			id, session_id, call_sid, survey_id, question_id, question_type, value, answered_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := s.db.Exec(ctx, query,
+		response.ID, response.SessionID, response.CallSID, response.SurveyID,
+		response.QuestionID, response.Type, response.Value, response.AnsweredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save survey response: %w", err)
+	}
+	return nil
+}
+
+// ForSession returns a call's responses
+func (s *PostgresSurveyStore) ForSession(ctx context.Context, sessionID uuid.UUID) ([]SurveyResponse, error) {
+	return s.query(ctx, `WHERE session_id = $1 ORDER BY answered_at`, sessionID)
+}
+
+// Query returns responses matching the filter
+func (s *PostgresSurveyStore) Query(ctx context.Context, filter SurveyFilter) ([]SurveyResponse, error) {
+	where := `WHERE ($1 = '' OR survey_id = $1)
+		AND ($2::timestamptz IS NULL OR answered_at >= $2)
+		AND ($3::timestamptz IS NULL OR answered_at < $3)
+		ORDER BY answered_at`
+
+	var since, until *time.Time
+	if !filter.Since.IsZero() {
+		since = &filter.Since
+	}
+	if !filter.Until.IsZero() {
+		until = &filter.Until
+	}
+	return s.query(ctx, where, filter.SurveyID, since, until)
+}
+
+// query selects responses with the given WHERE/ORDER clause
+func (s *PostgresSurveyStore) query(ctx context.Context, clause string, args ...interface{}) ([]SurveyResponse, error) {
+	query := `
+		SELECT id, session_id, call_sid, survey_id, question_id, question_type, value, answered_at
+		FROM survey_responses
+	` + clause
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query survey responses: %w", err)
+	}
+	defer rows.Close()
+
+	var responses []SurveyResponse
+	for rows.Next() {
+		var r SurveyResponse
+		if err := rows.Scan(&r.ID, &r.SessionID, &r.CallSID, &r.SurveyID,
+			&r.QuestionID, &r.Type, &r.Value, &r.AnsweredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan survey response: %w", err)
+		}
+		responses = append(responses, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read survey responses: %w", err)
+	}
+	return responses, nil
+}
@@ -0,0 +1,257 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============================================
+// POST-CALL SURVEYS
+// Keypad questions asked once the conversation is over
+// ============================================
+
+// SurveyPath is the webhook path that starts a survey on a call
+const SurveyPath = "/api/telephony/calls/survey"
+
+// QuestionType is how a survey question is answered
+type QuestionType string
+
+const (
+	QuestionRating QuestionType = "rating" // 1 to 5 on the keypad
+	QuestionYesNo  QuestionType = "yes_no" // 1 for yes, 2 for no
+)
+
+// SurveyQuestion is one question in a survey
+type SurveyQuestion struct {
+	ID   string       `json:"id"`   // Stable key for reporting, e.g. "satisfaction"
+	Text string       `json:"text"` // Spoken to the caller, including how to answer
+	Type QuestionType `json:"type"`
+}
+
+// Survey is a sequence of keypad questions
+type Survey struct {
+	ID        string           `json:"id"`
+	Intro     string           `json:"intro,omitempty"`
+	Outro     string           `json:"outro,omitempty"` // Spoken before hanging up (default thanks)
+	Voice     string           `json:"voice,omitempty"`
+	Questions []SurveyQuestion `json:"questions"`
+}
+
+// validate checks a survey can be run
+func (s Survey) validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("survey id is required")
+	}
+	if len(s.Questions) == 0 {
+		return fmt.Errorf("survey %s has no questions", s.ID)
+	}
+	for _, q := range s.Questions {
+		if q.ID == "" || q.Text == "" {
+			return fmt.Errorf("survey %s has a question without an id or text", s.ID)
+		}
+		if q.Type != QuestionRating && q.Type != QuestionYesNo {
+			return fmt.Errorf("survey %s question %s has unknown type %q", s.ID, q.ID, q.Type)
+		}
+	}
+	return nil
+}
+
+// SurveyResponse is a caller's answer to one question
+type SurveyResponse struct {
+	ID         uuid.UUID    `json:"id"`
+	SessionID  uuid.UUID    `json:"session_id"`
+	CallSID    string       `json:"call_sid"`
+	SurveyID   string       `json:"survey_id"`
+	QuestionID string       `json:"question_id"`
+	Type       QuestionType `json:"type"`
+	Value      int          `json:"value"` // 1-5 for ratings; 1 yes, 0 no
+	AnsweredAt time.Time    `json:"answered_at"`
+}
+
+// parseAnswer converts keypad digits to a response value
+func (q SurveyQuestion) parseAnswer(digits string) (int, bool) {
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, false
+	}
+	switch q.Type {
+	case QuestionRating:
+		return n, n >= 1 && n <= 5
+	case QuestionYesNo:
+		switch n {
+		case 1:
+			return 1, true
+		case 2:
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// invalidText is spoken when a key doesn't answer the question
+func (q SurveyQuestion) invalidText() string {
+	if q.Type == QuestionYesNo {
+		return "Please press 1 for yes or 2 for no."
+	}
+	return "Please enter a number from 1 to 5."
+}
+
+// surveyRegistry holds the surveys calls can be sent to
+type surveyRegistry struct {
+	surveys map[string]Survey
+	mu      sync.RWMutex
+}
+
+func newSurveyRegistry() *surveyRegistry {
+	return &surveyRegistry{surveys: make(map[string]Survey)}
+}
+
+// RegisterSurvey makes a survey available to SurveyTwiML and StartSurvey
+func (h *CallHandlers) RegisterSurvey(survey Survey) error {
+	if err := survey.validate(); err != nil {
+		return err
+	}
+
+	h.surveys.mu.Lock()
+	defer h.surveys.mu.Unlock()
+	h.surveys.surveys[survey.ID] = survey
+	return nil
+}
+
+// SurveyTwiML returns the instructions starting a registered survey on a
+// call, for use as the response to a webhook once the conversation is over
+func (h *CallHandlers) SurveyTwiML(callSID, surveyID string) (*TwiML, error) {
+	h.surveys.mu.RLock()
+	survey, ok := h.surveys.surveys[surveyID]
+	h.surveys.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown survey %q", surveyID)
+	}
+
+	twiml := NewTwiML()
+	if survey.Intro != "" {
+		twiml.Say(survey.Intro, survey.Voice)
+	}
+	twiml.Verbs = append(twiml.Verbs, h.askQuestion(callSID, survey, 0, "").Verbs...)
+	return twiml, nil
+}
+
+// StartSurvey moves a live call into a survey registered on the handlers
+// serving SurveyPath. Requires SetPublicBaseURL.
+func (ci *CallInitiator) StartSurvey(ctx context.Context, callSID, surveyID string) error {
+	surveyURL, err := ci.webhookURL(SurveyPath, url.Values{"survey": {surveyID}})
+	if err != nil {
+		return err
+	}
+	if err := ci.updateLiveCall(ctx, callSID, NewTwiML().Redirect(surveyURL)); err != nil {
+		return fmt.Errorf("failed to start survey: %w", err)
+	}
+
+	log.Printf("[CallInitiator] Started survey %s on call %s", surveyID, callSID)
+	return nil
+}
+
+// HandleSurvey starts the survey named by the survey query parameter
+func (h *CallHandlers) HandleSurvey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	callSID := r.FormValue("CallSid")
+	if callSID == "" {
+		http.Error(w, "Missing CallSid", http.StatusBadRequest)
+		return
+	}
+
+	twiml, err := h.SurveyTwiML(callSID, r.URL.Query().Get("survey"))
+	if err != nil {
+		log.Printf("[CallHandlers] Failed to start survey on %s: %v", callSID, err)
+		writeTwiML(w, NewTwiML().Hangup())
+		return
+	}
+	writeTwiML(w, twiml)
+}
+
+// askQuestion prompts for question i, registering the handler that records
+// the answer and moves on. preface is spoken first (e.g. a correction).
+func (h *CallHandlers) askQuestion(callSID string, survey Survey, i int, preface string) *TwiML {
+	question := survey.Questions[i]
+
+	h.OnDigits(callSID, func(digits string) *TwiML {
+		value, ok := question.parseAnswer(digits)
+		if !ok {
+			return h.askQuestion(callSID, survey, i, question.invalidText())
+		}
+
+		h.recordSurveyResponse(callSID, survey, question, value)
+
+		if i+1 < len(survey.Questions) {
+			return h.askQuestion(callSID, survey, i+1, "")
+		}
+
+		h.gather.mu.Lock()
+		delete(h.gather.byCall, callSID)
+		h.gather.mu.Unlock()
+
+		outro := survey.Outro
+		if outro == "" {
+			outro = "Thank you for your feedback. Goodbye!"
+		}
+		return NewTwiML().Say(outro, survey.Voice).Hangup()
+	})
+
+	twiml := NewTwiML()
+	if preface != "" {
+		twiml.Say(preface, survey.Voice)
+	}
+	prompt := h.GatherTwiML(callSID, "", GatherPrompt{
+		Text:      question.Text,
+		Voice:     survey.Voice,
+		NumDigits: 1,
+	})
+	twiml.Verbs = append(twiml.Verbs, prompt.Verbs...)
+	return twiml
+}
+
+// recordSurveyResponse stores an answer against the call's session
+func (h *CallHandlers) recordSurveyResponse(callSID string, survey Survey, question SurveyQuestion, value int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.callInitiator.RecordSurveyResponse(ctx, callSID, survey.ID, question, value); err != nil {
+		log.Printf("[CallHandlers] Failed to record survey answer for %s: %v", callSID, err)
+		h.callInitiator.Errors().Record("survey", callSID, err)
+	}
+}
+
+// RecordSurveyResponse stores a caller's answer to a survey question,
+// linked to the call's session
+func (ci *CallInitiator) RecordSurveyResponse(ctx context.Context, callSID, surveyID string, question SurveyQuestion, value int) error {
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return err
+	}
+	session.mu.RLock()
+	sessionID := session.ID
+	session.mu.RUnlock()
+
+	return ci.surveys.Save(ctx, SurveyResponse{
+		ID:         uuid.New(),
+		SessionID:  sessionID,
+		CallSID:    callSID,
+		SurveyID:   surveyID,
+		QuestionID: question.ID,
+		Type:       question.Type,
+		Value:      value,
+		AnsweredAt: time.Now(),
+	})
+}
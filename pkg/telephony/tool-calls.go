@@ -0,0 +1,74 @@
+package telephony
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// ============================================
+// AGENT TOOL CALLS
+// Functions an AI agent called during a call, kept on its session
+// ============================================
+
+// ToolCallsKey is the metadata key listing a call's tool calls
+const ToolCallsKey = "tool_calls"
+
+// maxToolOutput caps the result kept for each tool call, so large lookups
+// don't bloat the session
+const maxToolOutput = 2048
+
+// ToolCallRecord is a function an AI agent called during a call
+type ToolCallRecord struct {
+	Name       string          `json:"name"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	Output     string          `json:"output"`
+	IsError    bool            `json:"is_error,omitempty"`
+	DurationMS int64           `json:"duration_ms"`
+	CalledAt   time.Time       `json:"called_at"`
+}
+
+// RecordToolCall adds a tool call to the call's ToolCallsKey metadata and
+// saves the session. Outputs over 2KB are truncated.
+func (ci *CallInitiator) RecordToolCall(ctx context.Context, callSID string, record ToolCallRecord) error {
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return err
+	}
+	if len(record.Output) > maxToolOutput {
+		cut := maxToolOutput
+		for cut > 0 && !utf8.RuneStart(record.Output[cut]) {
+			cut--
+		}
+		record.Output = record.Output[:cut] + "…"
+	}
+
+	_, err = ci.updateSession(ctx, session, func(session *CallSession) error {
+		calls, _ := session.Metadata[ToolCallsKey].([]interface{})
+		session.Metadata.Set(ToolCallsKey, append(calls[:len(calls):len(calls)], jsonValue(record)))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save tool call: %w", err)
+	}
+	return nil
+}
+
+// ToolCalls returns the tool calls recorded on a call, in the order they
+// were made
+func (s *CallSession) ToolCalls() []ToolCallRecord {
+	s.mu.RLock()
+	raw, err := json.Marshal(s.Metadata[ToolCallsKey])
+	s.mu.RUnlock()
+	if err != nil {
+		return nil
+	}
+
+	var calls []ToolCallRecord
+	if err := json.Unmarshal(raw, &calls); err != nil {
+		return nil
+	}
+	return calls
+}
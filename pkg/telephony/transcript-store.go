@@ -0,0 +1,285 @@
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ============================================
+// CALL TRANSCRIPTS
+// What was said on a call, by whom and when
+// ============================================
+
+// Speaker labels who said a transcript segment
+type Speaker string
+
+const (
+	SpeakerCaller Speaker = "caller"
+	SpeakerAgent  Speaker = "agent"
+)
+
+// TranscriptSegment is one stretch of speech on a call. Start and End are
+// offsets from the call being answered.
+type TranscriptSegment struct {
+	ID         uuid.UUID     `json:"id"`
+	SessionID  uuid.UUID     `json:"session_id"`
+	CallSID    string        `json:"call_sid"`
+	Speaker    Speaker       `json:"speaker"`
+	Text       string        `json:"text"`
+	Start      time.Duration `json:"start"`
+	End        time.Duration `json:"end"`
+	Confidence float64       `json:"confidence,omitempty"` // 0-1 for recognized speech; 0 when unknown
+	Sentiment  *Sentiment    `json:"sentiment,omitempty"`  // Set by RecordSentiment
+}
+
+// TranscriptStore persists transcript segments
+type TranscriptStore interface {
+	Save(ctx context.Context, segment TranscriptSegment) error
+	// ForSession returns a call's segments in the order they were spoken
+	ForSession(ctx context.Context, sessionID uuid.UUID) ([]TranscriptSegment, error)
+	// SetSentiment scores the speaker's segments starting at start; none
+	// matching is not an error
+	SetSentiment(ctx context.Context, sessionID uuid.UUID, speaker Speaker, start time.Duration, sentiment Sentiment) error
+}
+
+// SetTranscriptStore replaces the store for transcript segments
+func (ci *CallInitiator) SetTranscriptStore(store TranscriptStore) {
+	ci.transcripts = store
+}
+
+// Transcript returns what was said on a call
+func (ci *CallInitiator) Transcript(ctx context.Context, sessionID uuid.UUID) ([]TranscriptSegment, error) {
+	return ci.transcripts.ForSession(ctx, sessionID)
+}
+
+// RecordTranscript stores something said on a call, spoken between start
+// and end. The call's TranscriptText is written from its segments when it
+// ends, or at once for segments recorded after that.
+func (ci *CallInitiator) RecordTranscript(ctx context.Context, callSID string, speaker Speaker, text string, start, end time.Time, confidence float64) error {
+	session, err := ci.lookupSession(ctx, callSID)
+	if err != nil {
+		return err
+	}
+	session.mu.RLock()
+	sessionID := session.ID
+	origin := session.InitiatedAt
+	if session.AnsweredAt != nil {
+		origin = *session.AnsweredAt
+	}
+	ended := session.State.IsTerminal()
+	session.mu.RUnlock()
+
+	err = ci.transcripts.Save(ctx, TranscriptSegment{
+		ID:         uuid.New(),
+		SessionID:  sessionID,
+		CallSID:    callSID,
+		Speaker:    speaker,
+		Text:       text,
+		Start:      max(start.Sub(origin), 0),
+		End:        max(end.Sub(origin), 0),
+		Confidence: confidence,
+	})
+	if err != nil || !ended {
+		return err
+	}
+
+	_, err = ci.updateSession(ctx, session, func(session *CallSession) error {
+		ci.fillTranscript(ctx, session)
+		return nil
+	})
+	return err
+}
+
+// fillTranscript writes a session's TranscriptText and Confidence from its
+// recorded segments, if it has any. The session must be locked.
+func (ci *CallInitiator) fillTranscript(ctx context.Context, session *CallSession) {
+	segments, err := ci.transcripts.ForSession(ctx, session.ID)
+	if err != nil {
+		log.Printf("[CallInitiator] Failed to load transcript for call %s: %v", session.SignalWireCallSID, err)
+		return
+	}
+	if len(segments) == 0 {
+		return
+	}
+
+	text, confidence := FormatTranscript(segments)
+	session.TranscriptText = text
+	if confidence > 0 {
+		session.Confidence = confidence
+	}
+}
+
+// FormatTranscript renders segments one per line with speaker labels
+// ("Caller: ...", "Agent: ..."), and returns the mean confidence of the
+// caller's recognized speech
+func FormatTranscript(segments []TranscriptSegment) (string, float64) {
+	var text strings.Builder
+	var total float64
+	var scored int
+	for _, s := range segments {
+		label := string(s.Speaker)
+		if label != "" {
+			label = strings.ToUpper(label[:1]) + label[1:]
+		}
+		fmt.Fprintf(&text, "%s: %s\n", label, s.Text)
+		if s.Speaker == SpeakerCaller && s.Confidence > 0 {
+			total += s.Confidence
+			scored++
+		}
+	}
+
+	var confidence float64
+	if scored > 0 {
+		confidence = total / float64(scored)
+	}
+	return strings.TrimSuffix(text.String(), "\n"), confidence
+}
+
+// ============================================
+// IN-MEMORY TRANSCRIPT STORE
+// ============================================
+
+// MemoryTranscriptStore keeps transcript segments in memory (lost on
+// restart)
+type MemoryTranscriptStore struct {
+	segments map[uuid.UUID][]TranscriptSegment
+	mu       sync.RWMutex
+}
+
+// NewMemoryTranscriptStore creates an in-memory transcript store
+func NewMemoryTranscriptStore() *MemoryTranscriptStore {
+	return &MemoryTranscriptStore{segments: make(map[uuid.UUID][]TranscriptSegment)}
+}
+
+// Save stores a segment
+func (s *MemoryTranscriptStore) Save(ctx context.Context, segment TranscriptSegment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.segments[segment.SessionID] = append(s.segments[segment.SessionID], segment)
+	return nil
+}
+
+// ForSession returns a call's segments
+func (s *MemoryTranscriptStore) ForSession(ctx context.Context, sessionID uuid.UUID) ([]TranscriptSegment, error) {
+	s.mu.RLock()
+	segments := append([]TranscriptSegment(nil), s.segments[sessionID]...)
+	s.mu.RUnlock()
+
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].Start < segments[j].Start
+	})
+	return segments, nil
+}
+
+// SetSentiment scores matching segments
+func (s *MemoryTranscriptStore) SetSentiment(ctx context.Context, sessionID uuid.UUID, speaker Speaker, start time.Duration, sentiment Sentiment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, segment := range s.segments[sessionID] {
+		if segment.Speaker == speaker && segment.Start == start {
+			s.segments[sessionID][i].Sentiment = &sentiment
+		}
+	}
+	return nil
+}
+
+// ============================================
+// POSTGRES TRANSCRIPT STORE
+// ============================================
+
+// PostgresTranscriptStore keeps segments in the call_transcripts table (see
+// migrations), linked to call_sessions
+type PostgresTranscriptStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresTranscriptStore creates a transcript store backed by Postgres
+func NewPostgresTranscriptStore(db *pgxpool.Pool) *PostgresTranscriptStore {
+	return &PostgresTranscriptStore{db: db}
+}
+
+// Save inserts a segment
+func (s *PostgresTranscriptStore) Save(ctx context.Context, segment TranscriptSegment) error {
+	query := `
+		INSERT INTO call_transcripts (
+			id, session_id, call_sid, speaker, text, start_ms, end_ms, confidence,
+			sentiment, emotion
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	var score *float64
+	var emotion *string
+	if segment.Sentiment != nil {
+		score, emotion = &segment.Sentiment.Score, &segment.Sentiment.Emotion
+	}
+	_, err := s.db.Exec(ctx, query,
+		segment.ID, segment.SessionID, segment.CallSID, segment.Speaker, segment.Text,
+		segment.Start.Milliseconds(), segment.End.Milliseconds(), segment.Confidence,
+		score, emotion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save transcript segment: %w", err)
+	}
+	return nil
+}
+
+// ForSession returns a call's segments
+func (s *PostgresTranscriptStore) ForSession(ctx context.Context, sessionID uuid.UUID) ([]TranscriptSegment, error) {
+	query := `
+		SELECT id, session_id, call_sid, speaker, text, start_ms, end_ms, confidence,
+			sentiment, COALESCE(emotion, '')
+		FROM call_transcripts
+		WHERE session_id = $1
+		ORDER BY start_ms, end_ms
+	`
+
+	rows, err := s.db.Query(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transcript: %w", err)
+	}
+	defer rows.Close()
+
+	var segments []TranscriptSegment
+	for rows.Next() {
+		var seg TranscriptSegment
+		var startMS, endMS int64
+		var score *float64
+		var emotion string
+		if err := rows.Scan(&seg.ID, &seg.SessionID, &seg.CallSID, &seg.Speaker, &seg.Text,
+			&startMS, &endMS, &seg.Confidence, &score, &emotion); err != nil {
+			return nil, fmt.Errorf("failed to scan transcript segment: %w", err)
+		}
+		seg.Start = time.Duration(startMS) * time.Millisecond
+		seg.End = time.Duration(endMS) * time.Millisecond
+		if score != nil {
+			seg.Sentiment = &Sentiment{Score: *score, Emotion: emotion}
+		}
+		segments = append(segments, seg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return segments, nil
+}
+
+// SetSentiment scores matching segments
+func (s *PostgresTranscriptStore) SetSentiment(ctx context.Context, sessionID uuid.UUID, speaker Speaker, start time.Duration, sentiment Sentiment) error {
+	query := `
+		UPDATE call_transcripts SET sentiment = $4, emotion = $5
+		WHERE session_id = $1 AND speaker = $2 AND start_ms = $3
+	`
+
+	_, err := s.db.Exec(ctx, query, sessionID, speaker, start.Milliseconds(), sentiment.Score, sentiment.Emotion)
+	if err != nil {
+		return fmt.Errorf("failed to save transcript sentiment: %w", err)
+	}
+	return nil
+}
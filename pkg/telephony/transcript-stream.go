@@ -0,0 +1,227 @@
+package telephony
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// ============================================
+// LIVE TRANSCRIPTS
+// Transcript updates streamed to dashboards as a call happens
+// ============================================
+
+// EventTranscript is published for each transcript update (see
+// PublishTranscript)
+const EventTranscript EventType = "transcript.segment"
+
+// TranscriptStreamPath is where hosts conventionally mount
+// HandleTranscriptStream, which RegisterRoutes leaves out
+const TranscriptStreamPath = "/api/telephony/calls/transcript"
+
+// transcriptKeepAlive is how often an idle transcript stream sends a comment,
+// so proxies don't close it
+const transcriptKeepAlive = 15 * time.Second
+
+// TranscriptUpdate is speech heard or said on a bridge session. Interim
+// updates are replaced by the next update from the same speaker until one
+// is final.
+type TranscriptUpdate struct {
+	SessionID  string    `json:"session_id"`
+	CallSID    string    `json:"call_sid,omitempty"`
+	Speaker    Speaker   `json:"speaker"`
+	Text       string    `json:"text"`
+	Final      bool      `json:"final"`
+	Confidence float64   `json:"confidence,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// PublishTranscript publishes a transcript update as an EventTranscript
+func PublishTranscript(bus EventBus, update TranscriptUpdate) {
+	data := map[string]interface{}{
+		"speaker": string(update.Speaker),
+		"text":    update.Text,
+		"final":   update.Final,
+	}
+	if update.Confidence > 0 {
+		data["confidence"] = update.Confidence
+	}
+	publishEvent(bus, Event{
+		Type:      EventTranscript,
+		CallSID:   update.CallSID,
+		SessionID: update.SessionID,
+		Data:      data,
+		Timestamp: update.Time,
+	})
+}
+
+// transcriptUpdate reads an update back from an EventTranscript
+func transcriptUpdate(event Event) TranscriptUpdate {
+	update := TranscriptUpdate{
+		SessionID: event.SessionID,
+		CallSID:   event.CallSID,
+		Time:      event.Timestamp,
+	}
+	if speaker, ok := event.Data["speaker"].(string); ok {
+		update.Speaker = Speaker(speaker)
+	}
+	update.Text, _ = event.Data["text"].(string)
+	update.Final, _ = event.Data["final"].(bool)
+	update.Confidence, _ = event.Data["confidence"].(float64)
+	return update
+}
+
+// HandleTranscriptStream streams a bridge session's transcript as
+// Server-Sent Events: GET TranscriptStreamPath?session_id=...
+//
+// Segments already recorded for the call (see RecordTranscript) are sent
+// first, then each TranscriptUpdate as a "transcript" event with the update
+// as JSON data. An "end" event follows when the session's AI stream stops.
+// Updates come from the event bus (see SetEventBus), published by whatever
+// transcribes the call, e.g. a conversation.Manager sharing the bus.
+//
+// Transcripts are caller PII and the handler has no authentication of its
+// own, so it is not part of RegisterRoutes. Mount it behind your auth
+// middleware:
+//
+//	mux.Handle(telephony.TranscriptStreamPath, requireSupervisor(http.HandlerFunc(handlers.HandleTranscriptStream)))
+func (h *CallHandlers) HandleTranscriptStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessionID := r.URL.Query().Get("session_id")
+	if sessionID == "" {
+		http.Error(w, "Missing session_id", http.StatusBadRequest)
+		return
+	}
+	session := h.streamBridge.GetSession(sessionID)
+	if session == nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if h.events == nil {
+		http.Error(w, "No event bus configured", http.StatusServiceUnavailable)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Subscribe before replaying, so nothing said in between is missed
+	events := make(chan Event, 64)
+	unsubscribe := h.events.Subscribe(func(event Event) {
+		if event.SessionID != sessionID {
+			return
+		}
+		select {
+		case events <- event:
+		case <-r.Context().Done():
+		}
+	}, EventTranscript, EventStreamStopped)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Don't let nginx buffer the stream
+	w.WriteHeader(http.StatusOK)
+
+	session.mu.RLock()
+	callSID := session.CallSID
+	ended := session.EndedAt != nil && !session.Streaming
+	session.mu.RUnlock()
+	for _, update := range h.recordedTranscript(r, sessionID, callSID) {
+		writeTranscriptEvent(w, update)
+	}
+	if ended {
+		fmt.Fprint(w, "event: end\ndata: {}\n\n")
+	}
+	flusher.Flush()
+	if ended {
+		return
+	}
+
+	keepAlive := time.NewTicker(transcriptKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case event := <-events:
+			if event.Type == EventStreamStopped {
+				if route, _ := event.Data["route"].(string); route != string(StreamRouteAI) {
+					continue
+				}
+				fmt.Fprint(w, "event: end\ndata: {}\n\n")
+				flusher.Flush()
+				return
+			}
+			if err := writeTranscriptEvent(w, transcriptUpdate(event)); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// recordedTranscript returns the segments stored so far for the call a
+// session carries, as final updates
+func (h *CallHandlers) recordedTranscript(r *http.Request, sessionID, callSID string) []TranscriptUpdate {
+	if callSID == "" {
+		return nil
+	}
+	call, err := h.callInitiator.lookupSession(r.Context(), callSID)
+	if err != nil {
+		return nil
+	}
+	call.mu.RLock()
+	id := call.ID
+	origin := call.InitiatedAt
+	if call.AnsweredAt != nil {
+		origin = *call.AnsweredAt
+	}
+	call.mu.RUnlock()
+
+	segments, err := h.callInitiator.Transcript(r.Context(), id)
+	if err != nil {
+		log.Printf("[CallHandlers] Failed to load transcript for %s: %v", callSID, err)
+		return nil
+	}
+
+	updates := make([]TranscriptUpdate, 0, len(segments))
+	for _, s := range segments {
+		updates = append(updates, TranscriptUpdate{
+			SessionID:  sessionID,
+			CallSID:    callSID,
+			Speaker:    s.Speaker,
+			Text:       s.Text,
+			Final:      true,
+			Confidence: s.Confidence,
+			Time:       origin.Add(s.End),
+		})
+	}
+	return updates
+}
+
+// writeTranscriptEvent writes an update as a "transcript" event
+func writeTranscriptEvent(w http.ResponseWriter, update TranscriptUpdate) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: transcript\ndata: %s\n\n", data)
+	return err
+}
@@ -0,0 +1,229 @@
+package telephony
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/birddigital/signalwire-telephony/pkg/signalwire"
+)
+
+// ============================================
+// TWIML BUILDER
+// Typed LaML/TwiML document construction
+// ============================================
+
+// TwiML builds a LaML/TwiML <Response> document verb by verb
+type TwiML struct {
+	XMLName xml.Name      `xml:"Response"`
+	Verbs   []interface{} `xml:",any"`
+}
+
+// NewTwiML creates an empty TwiML response
+func NewTwiML() *TwiML {
+	return &TwiML{}
+}
+
+// Say is the <Say> verb
+type Say struct {
+	XMLName  xml.Name `xml:"Say"`
+	Voice    string   `xml:"voice,attr,omitempty"`
+	Language string   `xml:"language,attr,omitempty"`
+	Loop     int      `xml:"loop,attr,omitempty"`
+	Text     string   `xml:",chardata"`
+}
+
+// Play is the <Play> verb
+type Play struct {
+	XMLName xml.Name `xml:"Play"`
+	Loop    int      `xml:"loop,attr,omitempty"`
+	Digits  string   `xml:"digits,attr,omitempty"`
+	URL     string   `xml:",chardata"`
+}
+
+// Pause is the <Pause> verb
+type Pause struct {
+	XMLName xml.Name `xml:"Pause"`
+	Length  int      `xml:"length,attr,omitempty"`
+}
+
+// Redirect is the <Redirect> verb
+type Redirect struct {
+	XMLName xml.Name `xml:"Redirect"`
+	Method  string   `xml:"method,attr,omitempty"`
+	URL     string   `xml:",chardata"`
+}
+
+// Hangup is the <Hangup> verb
+type Hangup struct {
+	XMLName xml.Name `xml:"Hangup"`
+}
+
+// Dial is the <Dial> verb; exactly one kind of noun should be set
+type Dial struct {
+	XMLName        xml.Name    `xml:"Dial"`
+	CallerID       string      `xml:"callerId,attr,omitempty"`
+	Timeout        int         `xml:"timeout,attr,omitempty"`
+	TimeLimit      int         `xml:"timeLimit,attr,omitempty"`
+	Action         string      `xml:"action,attr,omitempty"`
+	Method         string      `xml:"method,attr,omitempty"`
+	Record         string      `xml:"record,attr,omitempty"`
+	AnswerOnBridge bool        `xml:"answerOnBridge,attr,omitempty"`
+	Numbers        []Number    `xml:"Number,omitempty"`
+	Sips           []Sip       `xml:"Sip,omitempty"`
+	Conference     *Conference `xml:"Conference,omitempty"`
+}
+
+// Number is a <Dial> noun for a phone number
+type Number struct {
+	SendDigits string `xml:"sendDigits,attr,omitempty"`
+	URL        string `xml:"url,attr,omitempty"`
+	Number     string `xml:",chardata"`
+}
+
+// Sip is a <Dial> noun for a SIP endpoint
+type Sip struct {
+	Username string `xml:"username,attr,omitempty"`
+	Password string `xml:"password,attr,omitempty"`
+	URL      string `xml:"url,attr,omitempty"`
+	URI      string `xml:",chardata"`
+}
+
+// Conference is a <Dial> noun joining a named conference room
+type Conference struct {
+	Muted                  bool   `xml:"muted,attr,omitempty"`
+	Beep                   string `xml:"beep,attr,omitempty"`
+	StartConferenceOnEnter *bool  `xml:"startConferenceOnEnter,attr,omitempty"`
+	EndConferenceOnExit    bool   `xml:"endConferenceOnExit,attr,omitempty"`
+	WaitURL                string `xml:"waitUrl,attr,omitempty"`
+	MaxParticipants        int    `xml:"maxParticipants,attr,omitempty"`
+	Record                 string `xml:"record,attr,omitempty"`
+	StatusCallback         string `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackEvent    string `xml:"statusCallbackEvent,attr,omitempty"`
+	Coach                  string `xml:"coach,attr,omitempty"`
+	Name                   string `xml:",chardata"`
+}
+
+// Gather is the <Gather> verb; nested Say/Play/Pause prompts are allowed
+type Gather struct {
+	XMLName     xml.Name      `xml:"Gather"`
+	Input       string        `xml:"input,attr,omitempty"`
+	Action      string        `xml:"action,attr,omitempty"`
+	Method      string        `xml:"method,attr,omitempty"`
+	NumDigits   int           `xml:"numDigits,attr,omitempty"`
+	Timeout     int           `xml:"timeout,attr,omitempty"`
+	FinishOnKey string        `xml:"finishOnKey,attr,omitempty"`
+	Prompts     []interface{} `xml:",any"`
+}
+
+// StreamNoun is a <Stream> noun for media streaming
+type StreamNoun struct {
+	XMLName    xml.Name    `xml:"Stream"`
+	Name       string      `xml:"name,attr,omitempty"`
+	URL        string      `xml:"url,attr"`
+	Track      string      `xml:"track,attr,omitempty"`
+	Codec      string      `xml:"codec,attr,omitempty"` // e.g. StreamCodecOpus
+	Parameters []Parameter `xml:"Parameter,omitempty"`
+}
+
+// Parameter is a custom <Parameter> passed to a stream
+type Parameter struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// StartVerb is the <Start> verb wrapping forked streams
+type StartVerb struct {
+	XMLName xml.Name     `xml:"Start"`
+	Streams []StreamNoun `xml:"Stream"`
+}
+
+// ConnectVerb is the <Connect> verb wrapping a bidirectional stream
+type ConnectVerb struct {
+	XMLName xml.Name    `xml:"Connect"`
+	Stream  *StreamNoun `xml:"Stream,omitempty"`
+}
+
+// Append adds any verb to the response
+func (t *TwiML) Append(verb interface{}) *TwiML {
+	t.Verbs = append(t.Verbs, verb)
+	return t
+}
+
+// Say adds a <Say> verb
+func (t *TwiML) Say(text, voice string) *TwiML {
+	return t.Append(Say{Text: text, Voice: voice})
+}
+
+// Play adds a <Play> verb
+func (t *TwiML) Play(url string) *TwiML {
+	return t.Append(Play{URL: url})
+}
+
+// PlayDigits adds a <Play> verb that sends DTMF digits ("w" waits 0.5s)
+func (t *TwiML) PlayDigits(digits string) *TwiML {
+	return t.Append(Play{Digits: digits})
+}
+
+// Pause adds a <Pause> verb
+func (t *TwiML) Pause(seconds int) *TwiML {
+	return t.Append(Pause{Length: seconds})
+}
+
+// Redirect adds a <Redirect> verb
+func (t *TwiML) Redirect(url string) *TwiML {
+	return t.Append(Redirect{URL: url, Method: "POST"})
+}
+
+// Hangup adds a <Hangup> verb
+func (t *TwiML) Hangup() *TwiML {
+	return t.Append(Hangup{})
+}
+
+// Dial adds a <Dial> verb
+func (t *TwiML) Dial(dial Dial) *TwiML {
+	return t.Append(dial)
+}
+
+// Gather adds a <Gather> verb
+func (t *TwiML) Gather(gather Gather) *TwiML {
+	return t.Append(gather)
+}
+
+// Bytes renders the document with an XML declaration
+func (t *TwiML) Bytes() ([]byte, error) {
+	output, err := xml.Marshal(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal TwiML: %w", err)
+	}
+	return append([]byte(xml.Header), output...), nil
+}
+
+// String renders the document, returning an empty response on error
+func (t *TwiML) String() string {
+	output, err := t.Bytes()
+	if err != nil {
+		return xml.Header + "<Response></Response>"
+	}
+	return string(output)
+}
+
+// DialTarget returns a <Dial> for a phone number or "sip:" URI
+func DialTarget(target, callerID string) Dial {
+	dial := Dial{CallerID: callerID}
+	if isSIPURI(target) {
+		dial.Sips = []Sip{{URI: target}}
+	} else {
+		dial.Numbers = []Number{{Number: target}}
+	}
+	return dial
+}
+
+// isSIPURI reports whether a destination is a SIP URI
+func isSIPURI(target string) bool {
+	return signalwire.IsSIPURI(target)
+}
+
+// boolPtr returns a pointer to b (for optional boolean attributes)
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,58 @@
+//go:build webrtcvad && cgo
+
+package telephony
+
+/*
+#cgo pkg-config: libfvad
+#include <fvad.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// webrtcVADAvailable is true in builds with the webrtcvad tag
+const webrtcVADAvailable = true
+
+// webrtcClassifier classifies frames with libfvad, the standalone WebRTC VAD
+type webrtcClassifier struct {
+	inst *C.Fvad
+}
+
+// newWebRTCClassifier creates a WebRTC VAD for 10ms frames at sampleRate
+// (8000, 16000, 32000 or 48000)
+func newWebRTCClassifier(sampleRate, mode int) (frameClassifier, error) {
+	inst := C.fvad_new()
+	if inst == nil {
+		return nil, fmt.Errorf("webrtc vad: out of memory")
+	}
+	if C.fvad_set_mode(inst, C.int(mode)) < 0 {
+		C.fvad_free(inst)
+		return nil, fmt.Errorf("webrtc vad: invalid mode %d", mode)
+	}
+	if C.fvad_set_sample_rate(inst, C.int(sampleRate)) < 0 {
+		C.fvad_free(inst)
+		return nil, fmt.Errorf("webrtc vad: unsupported sample rate %d", sampleRate)
+	}
+	return &webrtcClassifier{inst: inst}, nil
+}
+
+func (c *webrtcClassifier) IsSpeech(samples []int16) (bool, error) {
+	if c.inst == nil {
+		return false, fmt.Errorf("webrtc vad: closed")
+	}
+	result := C.fvad_process(c.inst, (*C.int16_t)(unsafe.Pointer(&samples[0])), C.size_t(len(samples)))
+	if result < 0 {
+		return false, fmt.Errorf("webrtc vad: invalid frame length %d", len(samples))
+	}
+	return result == 1, nil
+}
+
+func (c *webrtcClassifier) Close() {
+	if c.inst != nil {
+		C.fvad_free(c.inst)
+		c.inst = nil
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !webrtcvad || !cgo
+
+package telephony
+
+// webrtcVADAvailable is false without the webrtcvad build tag
+const webrtcVADAvailable = false
+
+// newWebRTCClassifier returns ErrWebRTCVADUnavailable in builds without
+// WebRTC VAD support
+func newWebRTCClassifier(sampleRate, mode int) (frameClassifier, error) {
+	return nil, ErrWebRTCVADUnavailable
+}
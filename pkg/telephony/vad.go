@@ -0,0 +1,301 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ============================================
+// VOICE ACTIVITY DETECTION
+// Speech/silence tagging and speech start/end detection for caller audio
+// ============================================
+
+// VADAlgorithm selects how frames are classified as speech
+type VADAlgorithm string
+
+const (
+	// VADEnergy compares frame energy against an adaptive noise floor
+	VADEnergy VADAlgorithm = "energy"
+	// VADWebRTC uses the WebRTC VAD (libfvad, built with -tags webrtcvad)
+	VADWebRTC VADAlgorithm = "webrtc"
+)
+
+// vadFrameDuration is the length of each classified frame
+const vadFrameDuration = 10 * time.Millisecond
+
+// ErrWebRTCVADUnavailable is returned when the package was built without
+// WebRTC VAD support. Build with -tags webrtcvad (cgo and libfvad required).
+var ErrWebRTCVADUnavailable = errors.New("webrtc vad support not built (build with -tags webrtcvad)")
+
+// WebRTCVADAvailable reports whether WebRTC VAD support was built in
+func WebRTCVADAvailable() bool {
+	return webrtcVADAvailable
+}
+
+// VADConfig tunes voice activity detection
+type VADConfig struct {
+	Algorithm VADAlgorithm `json:"algorithm"` // Default VADEnergy
+
+	// WebRTC VAD mode, 0 (least aggressive) to 3 (most aggressive about
+	// rejecting non-speech); 0 is used as given, DefaultVADConfig sets 2
+	Aggressiveness int `json:"aggressiveness"`
+
+	// Energy VAD: dB above the noise floor that counts as speech (default
+	// 12), and the level in dBFS a frame must exceed (default -50)
+	Margin   float64 `json:"margin"`
+	MinLevel float64 `json:"min_level"`
+
+	// Continuous speech needed to start a segment (default 100ms), and
+	// silence needed to end one (default 500ms)
+	SpeechStart time.Duration `json:"speech_start"`
+	SpeechEnd   time.Duration `json:"speech_end"`
+}
+
+// DefaultVADConfig returns the default VAD settings
+func DefaultVADConfig() VADConfig {
+	return VADConfig{
+		Algorithm:      VADEnergy,
+		Aggressiveness: 2,
+		Margin:         12,
+		MinLevel:       -50,
+		SpeechStart:    100 * time.Millisecond,
+		SpeechEnd:      500 * time.Millisecond,
+	}
+}
+
+// VADEvent is a speech segment starting or ending
+type VADEvent struct {
+	Speaking bool          // True when speech started, false when it ended
+	Offset   time.Duration // Stream position of the start or end of speech
+	Duration time.Duration // Length of the speech segment (end events)
+}
+
+// VADResult is what Process found in a chunk of audio
+type VADResult struct {
+	Speech bool       // Most of the chunk's frames were speech
+	Events []VADEvent // Speech starts and ends detected in the chunk
+}
+
+// frameClassifier classifies one frame of 16-bit PCM as speech or not
+type frameClassifier interface {
+	IsSpeech(samples []int16) (bool, error)
+	Close()
+}
+
+// VAD detects voice activity in a stream of mulaw or PCM audio, split into
+// 10ms frames. Speech is reported once it has lasted SpeechStart and ended
+// once silence has lasted SpeechEnd. It is safe for concurrent use.
+type VAD struct {
+	config       VADConfig
+	mulaw        bool
+	frameSamples int
+	classifier   frameClassifier
+
+	pending     []int16 // Samples short of a full frame
+	frames      int64   // Frames classified so far
+	speaking    bool
+	run         int   // Consecutive frames contradicting the current state
+	segmentFrom int64 // Frame the current speech segment started at
+	startFrames int
+	endFrames   int
+	mu          sync.Mutex
+}
+
+// NewVAD creates a detector for audio in format (mulaw or 16-bit PCM, mono),
+// filling in zero config fields with defaults
+func NewVAD(format AudioFormat, config VADConfig) (*VAD, error) {
+	defaults := DefaultVADConfig()
+	if config.Algorithm == "" {
+		config.Algorithm = defaults.Algorithm
+	}
+	if config.Margin == 0 {
+		config.Margin = defaults.Margin
+	}
+	if config.MinLevel == 0 {
+		config.MinLevel = defaults.MinLevel
+	}
+	if config.SpeechStart <= 0 {
+		config.SpeechStart = defaults.SpeechStart
+	}
+	if config.SpeechEnd <= 0 {
+		config.SpeechEnd = defaults.SpeechEnd
+	}
+	if format.SampleRate <= 0 || format.Channels > 1 {
+		return nil, fmt.Errorf("unsupported VAD audio format: %+v", format)
+	}
+
+	var classifier frameClassifier
+	switch config.Algorithm {
+	case VADEnergy:
+		classifier = &energyClassifier{margin: config.Margin, minLevel: config.MinLevel}
+	case VADWebRTC:
+		if config.Aggressiveness < 0 || config.Aggressiveness > 3 {
+			return nil, fmt.Errorf("webrtc vad aggressiveness must be 0-3, got %d", config.Aggressiveness)
+		}
+		var err error
+		if classifier, err = newWebRTCClassifier(format.SampleRate, config.Aggressiveness); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown VAD algorithm: %s", config.Algorithm)
+	}
+
+	return &VAD{
+		config:       config,
+		mulaw:        format.Encoding == AudioFormatMulaw.Encoding,
+		frameSamples: format.SampleRate * int(vadFrameDuration/time.Millisecond) / 1000,
+		classifier:   classifier,
+		startFrames:  int((config.SpeechStart + vadFrameDuration - 1) / vadFrameDuration),
+		endFrames:    int((config.SpeechEnd + vadFrameDuration - 1) / vadFrameDuration),
+	}, nil
+}
+
+// Process classifies the next chunk of audio, holding back any samples
+// short of a full frame for the next call
+func (v *VAD) Process(audio []byte) (VADResult, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.mulaw {
+		for _, b := range audio {
+			v.pending = append(v.pending, mulawToLinear(b))
+		}
+	} else {
+		for i := 0; i+1 < len(audio); i += 2 {
+			v.pending = append(v.pending, int16(binary.LittleEndian.Uint16(audio[i:])))
+		}
+	}
+
+	var result VADResult
+	speechFrames, frames := 0, 0
+	for len(v.pending) >= v.frameSamples {
+		speech, err := v.classifier.IsSpeech(v.pending[:v.frameSamples])
+		if err != nil {
+			return result, err
+		}
+		v.pending = v.pending[v.frameSamples:]
+		frames++
+		if speech {
+			speechFrames++
+		}
+		if event, ok := v.advance(speech); ok {
+			result.Events = append(result.Events, event)
+		}
+	}
+	v.pending = append([]int16(nil), v.pending...)
+
+	result.Speech = frames > 0 && speechFrames*2 > frames
+	return result, nil
+}
+
+// advance runs the speech state machine for one frame
+func (v *VAD) advance(speech bool) (VADEvent, bool) {
+	frame := v.frames
+	v.frames++
+
+	if speech != v.speaking {
+		v.run++
+	} else {
+		v.run = 0
+	}
+
+	switch {
+	case !v.speaking && v.run >= v.startFrames:
+		v.speaking = true
+		v.segmentFrom = frame - int64(v.run) + 1
+		v.run = 0
+		return VADEvent{Speaking: true, Offset: frameOffset(v.segmentFrom)}, true
+
+	case v.speaking && v.run >= v.endFrames:
+		v.speaking = false
+		end := frame - int64(v.run) + 1
+		v.run = 0
+		return VADEvent{
+			Speaking: false,
+			Offset:   frameOffset(end),
+			Duration: frameOffset(end - v.segmentFrom),
+		}, true
+	}
+	return VADEvent{}, false
+}
+
+// frameOffset converts a frame count to stream time
+func frameOffset(frames int64) time.Duration {
+	return time.Duration(frames) * vadFrameDuration
+}
+
+// Speaking reports whether a speech segment is in progress
+func (v *VAD) Speaking() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.speaking
+}
+
+// Close frees the classifier
+func (v *VAD) Close() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.classifier.Close()
+}
+
+// ============================================
+// ENERGY CLASSIFIER
+// ============================================
+
+const (
+	vadSilenceLevel = -96.0 // dBFS reported for digital silence
+	vadFloorRise    = 0.02  // Noise floor tracking rate on non-speech frames
+	vadFloorCreep   = 0.002 // Tracking rate during speech, so a louder room is eventually learned
+	vadFloorFall    = 0.3   // Tracking rate when the level drops below the floor
+)
+
+// energyClassifier flags frames well above an adaptive noise floor
+type energyClassifier struct {
+	margin   float64
+	minLevel float64
+	floor    float64
+	primed   bool
+}
+
+func (c *energyClassifier) IsSpeech(samples []int16) (bool, error) {
+	level := frameLevel(samples)
+	if !c.primed {
+		c.floor = level
+		c.primed = true
+	}
+
+	speech := level > c.minLevel && level > c.floor+c.margin
+
+	// Follow the noise floor down quickly and up slowly
+	switch {
+	case level < c.floor:
+		c.floor += (level - c.floor) * vadFloorFall
+	case speech:
+		c.floor += (level - c.floor) * vadFloorCreep
+	default:
+		c.floor += (level - c.floor) * vadFloorRise
+	}
+	return speech, nil
+}
+
+func (c *energyClassifier) Close() {}
+
+// frameLevel is a frame's RMS level in dBFS
+func frameLevel(samples []int16) float64 {
+	if len(samples) == 0 {
+		return vadSilenceLevel
+	}
+	sum := 0.0
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+	rms := math.Sqrt(sum / float64(len(samples)))
+	if rms < 1 {
+		return vadSilenceLevel
+	}
+	return math.Max(20*math.Log10(rms/32768), vadSilenceLevel)
+}
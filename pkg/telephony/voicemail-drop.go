@@ -0,0 +1,66 @@
+package telephony
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// ============================================
+// VOICEMAIL DROP
+// Leaving a recorded or spoken message when AMD finds a machine
+// ============================================
+
+// VoicemailDropPath is the webhook SignalWire requests once a dropped
+// message has played; it records the message and hangs up
+const VoicemailDropPath = "/api/telephony/calls/voicemail-drop"
+
+// VoicemailDrop is the message left when a call reaches voicemail. Set it
+// on a campaign's CallConfig; AMD then waits for the greeting to end
+// (DetectMessageEnd) so the message starts after the beep.
+type VoicemailDrop struct {
+	AudioURL string `json:"audio_url,omitempty"` // Pre-recorded message, played when set
+	Text     string `json:"text,omitempty"`      // Spoken with TTS otherwise
+	Voice    string `json:"voice,omitempty"`
+}
+
+// validate checks that the drop has something to play
+func (d *VoicemailDrop) validate() error {
+	if d.AudioURL == "" && d.Text == "" {
+		return fmt.Errorf("voicemail_drop needs audio_url or text")
+	}
+	return nil
+}
+
+// twiml plays the message, then reports back to dropURL (VoicemailDropPath)
+// so the call can be marked
+func (d *VoicemailDrop) twiml(dropURL string) *TwiML {
+	twiml := NewTwiML()
+	if d.AudioURL != "" {
+		twiml.Play(d.AudioURL)
+	} else {
+		twiml.Say(d.Text, d.Voice)
+	}
+	return twiml.Redirect(dropURL)
+}
+
+// HandleVoicemailDrop marks the dropped message as left and hangs up
+func (h *CallHandlers) HandleVoicemailDrop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	callSID := r.FormValue("CallSid")
+	if callSID == "" {
+		http.Error(w, "Missing CallSid", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.callInitiator.MarkVoicemailDetected(r.Context(), callSID, true); err != nil {
+		log.Printf("[CallHandlers] Failed to mark voicemail left on %s: %v", callSID, err)
+		h.callInitiator.Errors().Record("handlers", callSID, err)
+	}
+
+	writeTwiML(w, NewTwiML().Hangup())
+}
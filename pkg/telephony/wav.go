@@ -0,0 +1,264 @@
+package telephony
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ============================================
+// WAV FILES
+// Wrapping audio in RIFF/WAVE headers and unwrapping WAV (e.g. from TTS)
+// ============================================
+
+// ErrInvalidWAV is returned for data that isn't a WAV file this package can
+// read
+var ErrInvalidWAV = errors.New("invalid WAV data")
+
+// WAV format tags
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatALaw       = 6
+	wavFormatMuLaw      = 7
+	wavFormatExtensible = 0xFFFE
+)
+
+// EncodeWAV wraps raw audio in a WAV header. The format's encoding must be
+// "pcm" (16-bit little-endian), "mulaw" or "alaw".
+func EncodeWAV(audio []byte, format AudioFormat) ([]byte, error) {
+	channels := format.Channels
+	if channels <= 0 {
+		channels = 1
+	}
+	if format.SampleRate <= 0 {
+		return nil, fmt.Errorf("invalid WAV sample rate: %d", format.SampleRate)
+	}
+
+	var tag, bits int
+	switch format.Encoding {
+	case AudioFormatPCM.Encoding:
+		if format.BitDepth != 0 && format.BitDepth != 16 {
+			return nil, fmt.Errorf("unsupported WAV bit depth: %d (16-bit PCM only)", format.BitDepth)
+		}
+		tag, bits = wavFormatPCM, 16
+	case AudioFormatMulaw.Encoding:
+		tag, bits = wavFormatMuLaw, 8
+	case "alaw":
+		tag, bits = wavFormatALaw, 8
+	default:
+		return nil, fmt.Errorf("unsupported WAV encoding: %q", format.Encoding)
+	}
+
+	blockAlign := channels * bits / 8
+	if len(audio)%blockAlign != 0 {
+		return nil, fmt.Errorf("audio length %d is not a whole number of %d-byte frames", len(audio), blockAlign)
+	}
+
+	// Non-PCM formats carry a cbSize field and a fact chunk
+	fmtSize := 16
+	if tag != wavFormatPCM {
+		fmtSize = 18
+	}
+	padded := len(audio) + len(audio)%2
+
+	out := make([]byte, 0, 12+8+fmtSize+12+8+padded)
+	out = append(out, "RIFF"...)
+	out = binary.LittleEndian.AppendUint32(out, 0) // Filled in below
+	out = append(out, "WAVE"...)
+
+	out = append(out, "fmt "...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(fmtSize))
+	out = binary.LittleEndian.AppendUint16(out, uint16(tag))
+	out = binary.LittleEndian.AppendUint16(out, uint16(channels))
+	out = binary.LittleEndian.AppendUint32(out, uint32(format.SampleRate))
+	out = binary.LittleEndian.AppendUint32(out, uint32(format.SampleRate*blockAlign))
+	out = binary.LittleEndian.AppendUint16(out, uint16(blockAlign))
+	out = binary.LittleEndian.AppendUint16(out, uint16(bits))
+	if tag != wavFormatPCM {
+		out = binary.LittleEndian.AppendUint16(out, 0)
+
+		out = append(out, "fact"...)
+		out = binary.LittleEndian.AppendUint32(out, 4)
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(audio)/blockAlign))
+	}
+
+	out = append(out, "data"...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(audio)))
+	out = append(out, audio...)
+	if len(audio)%2 != 0 {
+		out = append(out, 0)
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
+
+// DecodeWAV extracts the audio from a WAV file. Integer and float PCM of
+// any common bit depth comes back as 16-bit PCM; mulaw and alaw are
+// returned as stored. A data chunk with an unknown length (as streamed by
+// some TTS providers) runs to the end of the input.
+func DecodeWAV(data []byte) ([]byte, AudioFormat, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, AudioFormat{}, fmt.Errorf("%w: missing RIFF/WAVE header", ErrInvalidWAV)
+	}
+
+	var fmtChunk, audio []byte
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		body := pos + 8
+		if id == "data" && (size == 0 || size > len(data)-body) {
+			size = len(data) - body // Streamed: length unknown when the header was written
+		}
+		if size > len(data)-body {
+			return nil, AudioFormat{}, fmt.Errorf("%w: truncated %q chunk", ErrInvalidWAV, id)
+		}
+
+		switch id {
+		case "fmt ":
+			fmtChunk = data[body : body+size]
+		case "data":
+			audio = data[body : body+size]
+		}
+		pos = body + size + size%2
+	}
+	if fmtChunk == nil {
+		return nil, AudioFormat{}, fmt.Errorf("%w: no fmt chunk", ErrInvalidWAV)
+	}
+	if audio == nil {
+		return nil, AudioFormat{}, fmt.Errorf("%w: no data chunk", ErrInvalidWAV)
+	}
+	stored, err := parseWAVFormat(fmtChunk)
+	if err != nil {
+		return nil, AudioFormat{}, err
+	}
+
+	// Ignore a trailing partial frame
+	audio = audio[:len(audio)-len(audio)%stored.frameSize()]
+	return stored.decode(audio)
+}
+
+// readWAVHeader reads a WAV stream up to the start of its audio, returning
+// the format the audio is stored in
+func readWAVHeader(r io.Reader) (wavFormat, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil || string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return wavFormat{}, fmt.Errorf("%w: missing RIFF/WAVE header", ErrInvalidWAV)
+	}
+
+	var fmtChunk []byte
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return wavFormat{}, fmt.Errorf("%w: no data chunk", ErrInvalidWAV)
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:]))
+		if id == "data" {
+			break
+		}
+
+		body := io.LimitReader(r, size+size%2)
+		if id == "fmt " && size <= 1024 {
+			fmtChunk = make([]byte, size+size%2)
+			if _, err := io.ReadFull(body, fmtChunk); err != nil {
+				return wavFormat{}, fmt.Errorf("%w: truncated %q chunk", ErrInvalidWAV, id)
+			}
+			fmtChunk = fmtChunk[:size]
+		} else if n, _ := io.Copy(io.Discard, body); n < size {
+			return wavFormat{}, fmt.Errorf("%w: truncated %q chunk", ErrInvalidWAV, id)
+		}
+	}
+	if fmtChunk == nil {
+		return wavFormat{}, fmt.Errorf("%w: no fmt chunk", ErrInvalidWAV)
+	}
+	return parseWAVFormat(fmtChunk)
+}
+
+// wavFormat is how a WAV file stores its audio
+type wavFormat struct {
+	tag      int
+	channels int
+	rate     int
+	bits     int
+}
+
+// parseWAVFormat reads a fmt chunk
+func parseWAVFormat(fmtChunk []byte) (wavFormat, error) {
+	if len(fmtChunk) < 16 {
+		return wavFormat{}, fmt.Errorf("%w: short fmt chunk", ErrInvalidWAV)
+	}
+
+	stored := wavFormat{
+		tag:      int(binary.LittleEndian.Uint16(fmtChunk[0:])),
+		channels: int(binary.LittleEndian.Uint16(fmtChunk[2:])),
+		rate:     int(binary.LittleEndian.Uint32(fmtChunk[4:])),
+		bits:     int(binary.LittleEndian.Uint16(fmtChunk[14:])),
+	}
+	if stored.tag == wavFormatExtensible && len(fmtChunk) >= 26 {
+		// The real format is the first two bytes of the subformat GUID
+		stored.tag = int(binary.LittleEndian.Uint16(fmtChunk[24:]))
+	}
+	if stored.channels <= 0 || stored.rate <= 0 || stored.bits <= 0 || stored.bits%8 != 0 {
+		return wavFormat{}, fmt.Errorf("%w: bad format (%d channels, %dHz, %d bits)", ErrInvalidWAV, stored.channels, stored.rate, stored.bits)
+	}
+	return stored, nil
+}
+
+// frameSize returns the bytes in one sample of every channel
+func (w wavFormat) frameSize() int {
+	return w.channels * w.bits / 8
+}
+
+// decode converts whole frames of stored audio: integer and float PCM to
+// 16-bit PCM, mulaw and alaw as they are
+func (w wavFormat) decode(audio []byte) ([]byte, AudioFormat, error) {
+	format := AudioFormat{SampleRate: w.rate, Channels: w.channels, Encoding: AudioFormatPCM.Encoding, BitDepth: 16}
+	switch {
+	case w.tag == wavFormatMuLaw && w.bits == 8:
+		format.Encoding, format.BitDepth = AudioFormatMulaw.Encoding, 8
+		return audio, format, nil
+	case w.tag == wavFormatALaw && w.bits == 8:
+		format.Encoding, format.BitDepth = "alaw", 8
+		return audio, format, nil
+	case w.tag == wavFormatPCM && w.bits == 16:
+		return audio, format, nil
+	case w.tag == wavFormatPCM || w.tag == wavFormatFloat:
+		pcm, err := wavToPCM16(audio, w.bits, w.tag == wavFormatFloat)
+		return pcm, format, err
+	}
+	return nil, AudioFormat{}, fmt.Errorf("unsupported WAV format: tag %d, %d bits", w.tag, w.bits)
+}
+
+// wavToPCM16 converts 8/24/32-bit integer or 32/64-bit float samples to
+// 16-bit PCM
+func wavToPCM16(audio []byte, bits int, float bool) ([]byte, error) {
+	size := bits / 8
+	if float && size != 4 && size != 8 {
+		return nil, fmt.Errorf("unsupported WAV float bit depth: %d", bits)
+	}
+	if !float && size != 1 && size != 3 && size != 4 {
+		return nil, fmt.Errorf("unsupported WAV bit depth: %d", bits)
+	}
+
+	out := make([]byte, len(audio)/size*2)
+	for i := 0; i < len(audio)/size; i++ {
+		b := audio[i*size:]
+		var sample int16
+		switch {
+		case float && size == 4:
+			sample = clampInt16(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))) * 32767)
+		case float:
+			sample = clampInt16(math.Float64frombits(binary.LittleEndian.Uint64(b)) * 32767)
+		case size == 1:
+			sample = int16(int(b[0])-128) << 8 // 8-bit WAV is unsigned
+		default:
+			sample = int16(binary.LittleEndian.Uint16(b[size-2:])) // Keep the top 16 bits
+		}
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(sample))
+	}
+	return out, nil
+}
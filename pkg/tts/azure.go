@@ -0,0 +1,151 @@
+package tts
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// AZURE NEURAL VOICES
+// Speech from Azure AI Speech, streamed and converted to the call's format
+// ============================================
+
+const (
+	azureURL     = "https://%s.tts.speech.microsoft.com/cognitiveservices/v1"
+	azureVoice   = "en-US-JennyNeural"
+	azureTimeout = 60 * time.Second
+
+	azurePCMFormat = "raw-24khz-16bit-mono-pcm"
+	azureMP3Format = "audio-24khz-48kbitrate-mono-mp3"
+)
+
+// azurePCM is the format of azurePCMFormat
+var azurePCM = telephony.AudioFormat{SampleRate: 24000, Channels: 1, Encoding: "pcm", BitDepth: 16}
+
+// Azure is a Provider using Azure AI Speech neural voices. It produces 24kHz
+// PCM, converted to the requested format, or MP3 when that is requested.
+// Voice IDs are voice names such as "en-US-JennyNeural"; Stability and
+// Similarity don't apply.
+type Azure struct {
+	key     string
+	url     string
+	voiceID string
+	client  *http.Client
+}
+
+// NewAzure creates a provider for a Speech resource's key and region
+// (e.g. "eastus")
+func NewAzure(key, region string) *Azure {
+	return &Azure{
+		key:     key,
+		url:     fmt.Sprintf(azureURL, region),
+		voiceID: azureVoice,
+		client:  &http.Client{Timeout: azureTimeout},
+	}
+}
+
+// SetDefaultVoice sets the voice used when a request names none (default
+// en-US-JennyNeural)
+func (a *Azure) SetDefaultVoice(voiceID string) {
+	a.voiceID = voiceID
+}
+
+// SetURL replaces the endpoint, e.g. for a custom voice deployment or
+// sovereign cloud
+func (a *Azure) SetURL(url string) {
+	a.url = url
+}
+
+// SetHTTPClient replaces the HTTP client, e.g. for a proxy
+func (a *Azure) SetHTTPClient(client *http.Client) {
+	a.client = client
+}
+
+// Synthesize returns the whole of text as audio
+func (a *Azure) Synthesize(ctx context.Context, text string, voice Voice, format telephony.AudioFormat) ([]byte, error) {
+	resp, source, err := a.request(ctx, text, voice, format)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	audio, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read azure audio: %w", err)
+	}
+	return convertAudio(audio, source, format)
+}
+
+// SynthesizeStream delivers audio as Azure generates it, in 20ms frames
+// unless MP3 was requested
+func (a *Azure) SynthesizeStream(ctx context.Context, text string, voice Voice, format telephony.AudioFormat) (<-chan Frame, error) {
+	resp, source, err := a.request(ctx, text, voice, format)
+	if err != nil {
+		return nil, err
+	}
+	return streamAudio(ctx, "azure", resp.Body, source, format), nil
+}
+
+// request starts a synthesis request, returning the response once audio
+// starts arriving and the format it is in
+func (a *Azure) request(ctx context.Context, text string, voice Voice, format telephony.AudioFormat) (*http.Response, telephony.AudioFormat, error) {
+	outputFormat, source := azurePCMFormat, azurePCM
+	if format.Encoding == telephony.AudioFormatMP3.Encoding {
+		outputFormat, source = azureMP3Format, format
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, strings.NewReader(azureSSML(text, cmp.Or(voice.ID, a.voiceID), voice.SpeakingRate)))
+	if err != nil {
+		return nil, source, err
+	}
+	req.Header.Set("Content-Type", "application/ssml+xml")
+	req.Header.Set("Ocp-Apim-Subscription-Key", a.key)
+	req.Header.Set("X-Microsoft-OutputFormat", outputFormat)
+	req.Header.Set("User-Agent", "signalwire-telephony")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, source, fmt.Errorf("azure request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, source, fmt.Errorf("azure request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return resp, source, nil
+}
+
+// azureSSML wraps text in SSML for a voice. The document's language is the
+// voice name's locale prefix, e.g. "en-US" for "en-US-JennyNeural".
+func azureSSML(text, voiceName string, rate float64) string {
+	lang := "en-US"
+	if parts := strings.SplitN(voiceName, "-", 3); len(parts) == 3 {
+		lang = parts[0] + "-" + parts[1]
+	}
+
+	var ssml bytes.Buffer
+	ssml.WriteString(`<speak version="1.0" xmlns="http://www.w3.org/2001/10/synthesis" xml:lang="`)
+	xml.EscapeText(&ssml, []byte(lang))
+	ssml.WriteString(`"><voice name="`)
+	xml.EscapeText(&ssml, []byte(voiceName))
+	ssml.WriteString(`">`)
+	if rate > 0 {
+		ssml.WriteString(`<prosody rate="` + strconv.FormatFloat(rate, 'f', -1, 64) + `">`)
+	}
+	xml.EscapeText(&ssml, []byte(text))
+	if rate > 0 {
+		ssml.WriteString(`</prosody>`)
+	}
+	ssml.WriteString(`</voice></speak>`)
+	return ssml.String()
+}
@@ -0,0 +1,176 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// ELEVENLABS
+// Speech from ElevenLabs' text-to-speech API, streamed as it is generated
+// ============================================
+
+const (
+	ElevenLabsURL = "https://api.elevenlabs.io/v1"
+
+	elevenLabsModel      = "eleven_flash_v2_5" // Lowest latency
+	elevenLabsStability  = 0.5
+	elevenLabsSimilarity = 0.75
+	elevenLabsTimeout    = 60 * time.Second
+)
+
+// elevenLabsPCMRates are the PCM sample rates ElevenLabs produces
+var elevenLabsPCMRates = []int{8000, 16000, 22050, 24000, 44100}
+
+// ElevenLabs is a Provider using the ElevenLabs API. It produces mulaw,
+// mono 16-bit PCM at 8, 16, 22.05, 24 or 44.1kHz, and MP3.
+type ElevenLabs struct {
+	apiKey  string
+	url     string
+	model   string
+	voiceID string
+	client  *http.Client
+}
+
+// NewElevenLabs creates a provider authenticating with apiKey
+func NewElevenLabs(apiKey string) *ElevenLabs {
+	return &ElevenLabs{
+		apiKey: apiKey,
+		url:    ElevenLabsURL,
+		model:  elevenLabsModel,
+		client: &http.Client{Timeout: elevenLabsTimeout},
+	}
+}
+
+// SetModel selects the model (default eleven_flash_v2_5)
+func (e *ElevenLabs) SetModel(model string) {
+	e.model = model
+}
+
+// SetDefaultVoice sets the voice used when a request names none
+func (e *ElevenLabs) SetDefaultVoice(voiceID string) {
+	e.voiceID = voiceID
+}
+
+// SetURL replaces the API base URL
+func (e *ElevenLabs) SetURL(url string) {
+	e.url = strings.TrimRight(url, "/")
+}
+
+// SetHTTPClient replaces the HTTP client, e.g. for a proxy
+func (e *ElevenLabs) SetHTTPClient(client *http.Client) {
+	e.client = client
+}
+
+// Synthesize returns the whole of text as audio
+func (e *ElevenLabs) Synthesize(ctx context.Context, text string, voice Voice, format telephony.AudioFormat) ([]byte, error) {
+	resp, err := e.request(ctx, "", text, voice, format)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	audio, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read elevenlabs audio: %w", err)
+	}
+	return audio, nil
+}
+
+// SynthesizeStream delivers audio as ElevenLabs generates it: 20ms frames
+// of mulaw or PCM, or MP3 as it arrives
+func (e *ElevenLabs) SynthesizeStream(ctx context.Context, text string, voice Voice, format telephony.AudioFormat) (<-chan Frame, error) {
+	resp, err := e.request(ctx, "/stream", text, voice, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return streamAudio(ctx, "elevenlabs", resp.Body, format, format), nil
+}
+
+// elevenLabsRequest is a text-to-speech request
+type elevenLabsRequest struct {
+	Text          string `json:"text"`
+	ModelID       string `json:"model_id"`
+	VoiceSettings struct {
+		Stability       float64 `json:"stability"`
+		SimilarityBoost float64 `json:"similarity_boost"`
+		Speed           float64 `json:"speed,omitempty"`
+	} `json:"voice_settings"`
+}
+
+// request starts a text-to-speech request, returning the response once
+// audio starts arriving
+func (e *ElevenLabs) request(ctx context.Context, path, text string, voice Voice, format telephony.AudioFormat) (*http.Response, error) {
+	outputFormat, err := elevenLabsFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	voiceID := voice.ID
+	if voiceID == "" {
+		voiceID = e.voiceID
+	}
+	if voiceID == "" {
+		return nil, fmt.Errorf("no ElevenLabs voice: set Voice.ID or SetDefaultVoice")
+	}
+
+	request := elevenLabsRequest{Text: text, ModelID: e.model}
+	request.VoiceSettings.Stability = elevenLabsStability
+	if voice.Stability > 0 {
+		request.VoiceSettings.Stability = voice.Stability
+	}
+	request.VoiceSettings.SimilarityBoost = elevenLabsSimilarity
+	if voice.Similarity > 0 {
+		request.VoiceSettings.SimilarityBoost = voice.Similarity
+	}
+	request.VoiceSettings.Speed = voice.SpeakingRate
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("%s/text-to-speech/%s%s?output_format=%s", e.url, url.PathEscape(voiceID), path, outputFormat)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("xi-api-key", e.apiKey)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("elevenlabs request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("elevenlabs request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// elevenLabsFormat maps a format to an ElevenLabs output_format
+func elevenLabsFormat(format telephony.AudioFormat) (string, error) {
+	switch {
+	case format == telephony.AudioFormatMulaw:
+		return "ulaw_8000", nil
+	case format.Encoding == telephony.AudioFormatMP3.Encoding:
+		return "mp3_44100_128", nil
+	case format.Encoding == telephony.AudioFormatPCM.Encoding && format.BitDepth == 16 && format.Channels == 1:
+		for _, rate := range elevenLabsPCMRates {
+			if format.SampleRate == rate {
+				return fmt.Sprintf("pcm_%d", rate), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unsupported ElevenLabs audio format: %+v", format)
+}
@@ -0,0 +1,147 @@
+package tts
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// OPENAI TTS
+// Speech from OpenAI's audio API, streamed and converted to the call's format
+// ============================================
+
+const (
+	OpenAIURL = "https://api.openai.com/v1"
+
+	openAIModel   = "tts-1" // Lowest latency
+	openAIVoice   = "alloy"
+	openAITimeout = 60 * time.Second
+)
+
+// openAIPCM is the format of OpenAI's "pcm" output
+var openAIPCM = telephony.AudioFormat{SampleRate: 24000, Channels: 1, Encoding: "pcm", BitDepth: 16}
+
+// OpenAI is a Provider using OpenAI text-to-speech. It produces 24kHz PCM,
+// converted to the requested format, or MP3 when that is requested. Voice
+// Stability and Similarity don't apply.
+type OpenAI struct {
+	apiKey  string
+	url     string
+	model   string
+	voiceID string
+	client  *http.Client
+}
+
+// NewOpenAI creates a provider authenticating with apiKey
+func NewOpenAI(apiKey string) *OpenAI {
+	return &OpenAI{
+		apiKey:  apiKey,
+		url:     OpenAIURL,
+		model:   openAIModel,
+		voiceID: openAIVoice,
+		client:  &http.Client{Timeout: openAITimeout},
+	}
+}
+
+// SetModel selects the model (default tts-1)
+func (o *OpenAI) SetModel(model string) {
+	o.model = model
+}
+
+// SetDefaultVoice sets the voice used when a request names none (default
+// alloy)
+func (o *OpenAI) SetDefaultVoice(voiceID string) {
+	o.voiceID = voiceID
+}
+
+// SetURL replaces the API base URL, e.g. for a compatible server
+func (o *OpenAI) SetURL(url string) {
+	o.url = strings.TrimRight(url, "/")
+}
+
+// SetHTTPClient replaces the HTTP client, e.g. for a proxy
+func (o *OpenAI) SetHTTPClient(client *http.Client) {
+	o.client = client
+}
+
+// Synthesize returns the whole of text as audio
+func (o *OpenAI) Synthesize(ctx context.Context, text string, voice Voice, format telephony.AudioFormat) ([]byte, error) {
+	resp, source, err := o.request(ctx, text, voice, format)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	audio, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read openai audio: %w", err)
+	}
+	return convertAudio(audio, source, format)
+}
+
+// SynthesizeStream delivers audio as OpenAI generates it, in 20ms frames
+// unless MP3 was requested
+func (o *OpenAI) SynthesizeStream(ctx context.Context, text string, voice Voice, format telephony.AudioFormat) (<-chan Frame, error) {
+	resp, source, err := o.request(ctx, text, voice, format)
+	if err != nil {
+		return nil, err
+	}
+	return streamAudio(ctx, "openai", resp.Body, source, format), nil
+}
+
+// openAIRequest is a speech request
+type openAIRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format"`
+	Speed          float64 `json:"speed,omitempty"`
+}
+
+// request starts a speech request, returning the response once audio
+// starts arriving and the format it is in
+func (o *OpenAI) request(ctx context.Context, text string, voice Voice, format telephony.AudioFormat) (*http.Response, telephony.AudioFormat, error) {
+	request := openAIRequest{
+		Model:          o.model,
+		Input:          text,
+		Voice:          cmp.Or(voice.ID, o.voiceID),
+		ResponseFormat: "pcm",
+		Speed:          voice.SpeakingRate,
+	}
+	source := openAIPCM
+	if format.Encoding == telephony.AudioFormatMP3.Encoding {
+		request.ResponseFormat = "mp3"
+		source = format
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, source, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url+"/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return nil, source, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, source, fmt.Errorf("openai request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, source, fmt.Errorf("openai request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return resp, source, nil
+}
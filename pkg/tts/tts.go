@@ -0,0 +1,200 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/birddigital/signalwire-telephony/pkg/telephony"
+)
+
+// ============================================
+// TEXT TO SPEECH
+// Speech synthesis for AI replies, behind a provider interface
+// ============================================
+
+const (
+	frameDuration   = 20 * time.Millisecond
+	compressedChunk = 4096 // Read size for compressed audio
+	frameBufferSize = 64
+)
+
+// Provider synthesizes speech
+type Provider interface {
+	// Synthesize returns text spoken in voice, as audio in format
+	Synthesize(ctx context.Context, text string, voice Voice, format telephony.AudioFormat) ([]byte, error)
+
+	// SynthesizeStream delivers the audio in frames as it is synthesized,
+	// so playback can start straight away. Frames are the receiver's. The
+	// channel closes when synthesis ends; a failure part way is reported
+	// by a last frame carrying Err.
+	SynthesizeStream(ctx context.Context, text string, voice Voice, format telephony.AudioFormat) (<-chan Frame, error)
+}
+
+// Voice selects and tunes the voice. Zero fields use the provider's
+// defaults.
+type Voice struct {
+	ID           string  // Provider voice ID
+	Stability    float64 // 0-1: higher is more consistent, lower more expressive
+	Similarity   float64 // 0-1: how closely to match the original voice
+	SpeakingRate float64 // 1 is normal speed
+}
+
+// CallVoice returns the voice a call was configured with
+func CallVoice(config *telephony.CallConfig) Voice {
+	if config == nil {
+		return Voice{}
+	}
+	return Voice{
+		ID:           config.VoiceID,
+		Stability:    config.VoiceStability,
+		Similarity:   config.VoiceSimilarity,
+		SpeakingRate: config.SpeakingRate,
+	}
+}
+
+// Frame is a piece of synthesized audio
+type Frame struct {
+	Audio []byte
+	Err   error // Set on the last frame when synthesis failed
+}
+
+// Speak synthesizes text into a bridge session's AI → phone channel,
+// returning once it has all been handed to the bridge. Audio is requested
+// in the output format declared with SetAIFormat; with none declared,
+// AudioFormatPCM is declared.
+func Speak(ctx context.Context, provider Provider, bridge *telephony.AudioStreamBridge, sessionID, text string, voice Voice) error {
+	session := bridge.GetSession(sessionID)
+	if session == nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+	input, output, err := bridge.AIFormat(sessionID)
+	if err != nil {
+		return err
+	}
+	if output == (telephony.AudioFormat{}) {
+		output = telephony.AudioFormatPCM
+		if err := bridge.SetAIFormat(sessionID, input, output); err != nil {
+			return err
+		}
+	}
+	playback, err := bridge.GetAIToPhoneChannel(sessionID)
+	if err != nil {
+		return err
+	}
+
+	// Stop with the session, which ends before its channel closes
+	sessionCtx := session.GetContext()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(sessionCtx, cancel)
+	defer stop()
+
+	frames, err := provider.SynthesizeStream(ctx, text, voice, output)
+	if err != nil {
+		return fmt.Errorf("failed to synthesize speech for session %s: %w", sessionID, err)
+	}
+	for frame := range frames {
+		if frame.Err != nil {
+			return frame.Err
+		}
+		if ctx.Err() != nil || sessionCtx.Err() != nil {
+			break
+		}
+		select {
+		case playback <- frame.Audio:
+		case <-ctx.Done():
+		case <-sessionCtx.Done():
+		}
+	}
+	if sessionCtx.Err() != nil {
+		return fmt.Errorf("session closed: %s", sessionID)
+	}
+	return ctx.Err()
+}
+
+// convertAudio converts a whole clip from the format a service produced to
+// the one requested
+func convertAudio(audio []byte, source, target telephony.AudioFormat) ([]byte, error) {
+	if source == target {
+		return audio, nil
+	}
+	if source.Encoding == telephony.AudioFormatPCM.Encoding {
+		sampleSize := max(source.Channels*source.BitDepth/8, 1)
+		audio = audio[:len(audio)-len(audio)%sampleSize] // Whole samples
+	}
+	converter := telephony.NewAudioConverter(source.SampleRate, target.SampleRate, 1, 1)
+	converted, err := converter.ConvertAudio(audio, source, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert synthesized audio: %w", err)
+	}
+	return converted, nil
+}
+
+// streamAudio delivers audio from a service's streaming response as it
+// arrives: 20ms frames of raw audio, or compressed audio as read. Audio is
+// converted from source, the format the service produces, to target.
+func streamAudio(ctx context.Context, name string, body io.ReadCloser, source, target telephony.AudioFormat) <-chan Frame {
+	size := compressedChunk
+	raw := source.Encoding == telephony.AudioFormatPCM.Encoding || source == telephony.AudioFormatMulaw
+	if raw {
+		size = int(int64(source.SampleRate) * int64(frameDuration) / int64(time.Second))
+		size *= source.Channels * source.BitDepth / 8
+	}
+	sampleSize := max(source.Channels*source.BitDepth/8, 1)
+
+	var converter *telephony.AudioConverter
+	if source != target {
+		converter = telephony.NewAudioConverter(source.SampleRate, target.SampleRate, 1, 1)
+	}
+
+	frames := make(chan Frame, frameBufferSize)
+	go func() {
+		defer close(frames)
+		defer body.Close()
+
+		deliver := func(frame Frame) bool {
+			select {
+			case frames <- frame:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		fail := func(err error) {
+			if ctx.Err() == nil {
+				deliver(Frame{Err: err})
+			}
+		}
+
+		for {
+			frame := make([]byte, size)
+			n, err := io.ReadFull(body, frame)
+			if raw {
+				n -= n % sampleSize // Whole samples
+			}
+			audio := frame[:n]
+			if converter != nil && n > 0 {
+				converted, convErr := converter.ConvertAudio(audio, source, target)
+				if convErr != nil {
+					fail(fmt.Errorf("failed to convert %s audio: %w", name, convErr))
+					return
+				}
+				audio = converted // Empty while a compressed frame is incomplete
+			}
+			if len(audio) > 0 && !deliver(Frame{Audio: audio}) {
+				return
+			}
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return
+			}
+			if err != nil {
+				fail(fmt.Errorf("failed to read %s audio: %w", name, err))
+				return
+			}
+		}
+	}()
+	return frames
+}